@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/bundle"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	exportCommand = "export"
+	importCommand = "import"
+
+	// bundleFileMode keeps bundles private, they hold Secrets in plain text.
+	bundleFileMode = 0o600
+
+	// usageExitCode is the exit code for invalid arguments, like the flag package uses.
+	usageExitCode = 2
+)
+
+// bundleClients are the clients of the instance a bundle is exported from or imported into.
+type bundleClients struct {
+	discovery discovery.DiscoveryInterface
+	dynamic   dynamic.Interface
+}
+
+// runBundleCommand runs the export or import subcommand against the instance of the
+// kubeconfig and returns the exit code of the process.
+func runBundleCommand(ctx context.Context, command string, args []string) int {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	kubeconfig := flags.String("kubeconfig", "",
+		"Path to the kubeconfig of the Kommodity instance (default $KUBECONFIG or ~/.kube/config)")
+	file := flags.String("file", "-", "Path of the YAML bundle, - for standard output or input")
+	resources := flags.String("resources", "",
+		"Comma-separated resources to include, as resource or resource.group (default all)")
+	namespaces := flags.String("namespaces", "", "Comma-separated namespaces to include (default all)")
+
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	if err != nil {
+		return usageExitCode
+	}
+
+	filter := bundle.Filter{Resources: splitList(*resources), Namespaces: splitList(*namespaces)}
+
+	clients, err := newBundleClients(*kubeconfig)
+	if err == nil {
+		if command == exportCommand {
+			err = exportBundle(ctx, clients, *file, filter)
+		} else {
+			err = importBundle(ctx, clients, *file, filter)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kommodity %s: %v\n", command, err)
+
+		return 1
+	}
+
+	return 0
+}
+
+func newBundleClients(kubeconfig string) (*bundleClients, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	return &bundleClients{discovery: discoveryClient, dynamic: dynamicClient}, nil
+}
+
+func exportBundle(ctx context.Context, clients *bundleClients, file string, filter bundle.Filter) error {
+	writer := os.Stdout
+
+	if file != "-" {
+		var err error
+
+		writer, err = os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, bundleFileMode)
+		if err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+
+		defer func() { _ = writer.Close() }()
+	}
+
+	objects, err := bundle.Export(ctx, clients.discovery, clients.dynamic, writer, filter)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors of the bundle package name the failed step.
+	}
+
+	if file != "-" {
+		err = writer.Sync()
+		if err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d objects\n", objects)
+
+	return nil
+}
+
+func importBundle(ctx context.Context, clients *bundleClients, file string, filter bundle.Filter) error {
+	reader := os.Stdin
+
+	if file != "-" {
+		var err error
+
+		reader, err = os.Open(file)
+		if err != nil {
+			return fmt.Errorf("failed to open bundle: %w", err)
+		}
+
+		defer func() { _ = reader.Close() }()
+	}
+
+	result, err := bundle.Import(ctx, clients.discovery, clients.dynamic, reader, filter)
+
+	fmt.Fprintf(os.Stderr, "Created %d objects, %d already existed\n", result.Created, result.Existing)
+
+	return err //nolint:wrapcheck // Errors of the bundle package name the failed step.
+}
+
+func splitList(value string) []string {
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
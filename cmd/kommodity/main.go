@@ -3,18 +3,26 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/kommodity-io/kommodity/pkg/apidocs"
 	attestationserver "github.com/kommodity-io/kommodity/pkg/attestation"
+	"github.com/kommodity-io/kommodity/pkg/backup"
 	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/compaction"
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/console"
+	"github.com/kommodity-io/kommodity/pkg/controller"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
 	"github.com/kommodity-io/kommodity/pkg/kine"
 	"github.com/kommodity-io/kommodity/pkg/kms"
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	metadataserver "github.com/kommodity-io/kommodity/pkg/metadata"
 	k8sserver "github.com/kommodity-io/kommodity/pkg/server"
+	"github.com/kommodity-io/kommodity/pkg/spiffe"
 	uiserver "github.com/kommodity-io/kommodity/pkg/ui"
 	"go.uber.org/zap"
 	genericapiserver "k8s.io/apiserver/pkg/server"
@@ -24,7 +32,15 @@ import (
 
 //nolint:funlen // Not complex enough to warrant breaking down, only initialization logic and goroutines.
 func main() {
-	logger := logging.NewLogger()
+	if len(os.Args) > 1 && (os.Args[1] == exportCommand || os.Args[1] == importCommand) {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		code := runBundleCommand(ctx, os.Args[1], os.Args[2:])
+
+		cancel()
+		os.Exit(code)
+	}
+
+	logger, logLevels := logging.NewLeveledLogger()
 	ctx := logging.WithLogger(genericapiserver.SetupSignalContext(), logger)
 
 	triggers := []os.Signal{
@@ -48,12 +64,24 @@ func main() {
 		return
 	}
 
+	cfg.Dynamic.Subscribe(func(tunables config.Tunables) {
+		logLevels.SetLevel(tunables.LogLevel)
+	})
+
+	rootCtx := context.WithoutCancel(ctx)
 	kineServer := kine.NewServer(cfg)
 
+	// Kine outlives the API server, which needs it to drain, so it is stopped by its
+	// finalizer rather than by the signal.
+	kineCtx, stopKine := context.WithCancel(rootCtx)
+	kineStopped := make(chan struct{})
+
 	go func() {
-		err = kineServer.StartKine()
+		defer close(kineStopped)
+
+		err := kineServer.StartKine(kineCtx)
 		if err != nil {
-			logger.Error("Failed to start Kine server", zap.Error(err))
+			logger.Error("Failed to run Kine server", zap.Error(err))
 
 			// Ensure that the server is shut down gracefully when an error occurs.
 			signals <- syscall.SIGTERM
@@ -62,25 +90,142 @@ func main() {
 		}
 	}()
 
-	rootCtx := context.WithoutCancel(ctx)
+	finalizers = append(finalizers, func(ctx context.Context) error {
+		stopKine()
+
+		select {
+		case <-kineStopped:
+		case <-ctx.Done():
+		}
+
+		return nil
+	})
+
 	kineReadyChan := make(chan struct{})
 	kineServer.WaitForKine(ctx, kineReadyChan)
 
 	go func() {
 		// Wait for Kine to be ready before starting the API server.
-		<-kineReadyChan
+		select {
+		case <-ctx.Done():
+			return
+		case <-kineReadyChan:
+		}
+
 		logger.Info("Kine server started successfully")
 
+		if cfg.BackupConfig.RestoreSnapshot != "" {
+			// Restore before the API server starts and creates its default objects.
+			keys, err := backup.NewManager(cfg).Restore(ctx, cfg.BackupConfig.RestoreSnapshot)
+			switch {
+			case errors.Is(err, backup.ErrRegistryNotEmpty):
+				logger.Warn("Not restoring snapshot, the database already holds objects",
+					zap.String("snapshot", cfg.BackupConfig.RestoreSnapshot))
+			case err != nil:
+				logger.Error("Failed to restore snapshot", zap.Error(err))
+
+				// Ensure that the server is shut down gracefully when an error occurs.
+				signals <- syscall.SIGTERM
+
+				return
+			default:
+				logger.Info("Restored snapshot",
+					zap.String("snapshot", cfg.BackupConfig.RestoreSnapshot), zap.Int("keys", keys))
+			}
+		}
+
+		// The API server is stopped once the combined server stops accepting connections,
+		// which ends the watches proxied to it.
+		operatorCAs, err := httpauth.NewOperatorCAs(ctx, cfg)
+		if err != nil {
+			logger.Error("Failed to load operator client CAs", zap.Error(err))
+
+			// Ensure that the server is shut down gracefully when an error occurs.
+			signals <- syscall.SIGTERM
+
+			return
+		}
+
+		apiServerCtx, stopAPIServer := context.WithCancel(rootCtx)
+		apiServerFactory, waitForAPIServer := k8sserver.NewHTTPMuxFactory(apiServerCtx, cfg, operatorCAs)
+
+		finalizers = append(finalizers, func(ctx context.Context) error {
+			stopAPIServer()
+
+			return waitForAPIServer(ctx)
+		})
+
+		var spiffeSource *spiffe.Source
+
+		if cfg.SPIFFEConfig.Enabled() {
+			spiffeSource, err = spiffe.NewSource(ctx, cfg.SPIFFEConfig.EndpointSocket)
+			if err != nil {
+				logger.Error("Failed to get SPIFFE SVID", zap.Error(err))
+
+				// Ensure that the server is shut down gracefully when an error occurs.
+				signals <- syscall.SIGTERM
+
+				return
+			}
+
+			logger.Info("Serving with SPIFFE SVID", zap.String("spiffeID", spiffeSource.ID()))
+		}
+
+		clientCAs, err := httpauth.NewClientCAs(cfg, spiffeSource)
+		if err != nil {
+			logger.Error("Failed to load client CAs", zap.Error(err))
+
+			// Ensure that the server is shut down gracefully when an error occurs.
+			signals <- syscall.SIGTERM
+
+			return
+		}
+
 		server, err := combinedserver.New(combinedserver.ServerConfig{
-			Port:          cfg.ServerPort,
-			APIServerPort: cfg.APIServerPort,
+			Port:            cfg.ServerPort,
+			BindAddresses:   cfg.BindAddresses,
+			ListenAddresses: cfg.ListenAddresses,
+			APIServerPort:   cfg.APIServerPort,
 			HTTPFactories: []combinedserver.HTTPMuxFactory{
 				uiserver.NewHTTPMuxFactory(rootCtx, cfg),
-				attestationserver.NewHTTPMuxFactory(cfg),
-				metadataserver.NewHTTPMuxFactory(cfg),
-				k8sserver.NewHTTPMuxFactory(rootCtx, cfg),
+				attestationserver.NewHTTPMuxFactory(ctx, cfg),
+				metadataserver.NewHTTPMuxFactory(ctx, cfg),
+				apidocs.NewHTTPMuxFactory(),
+				apiServerFactory,
+				clientCAs.NewHTTPMuxFactory(ctx, cfg),
+				backup.NewHTTPMuxFactory(ctx, cfg),
+				compaction.NewHTTPMuxFactory(ctx, cfg),
+				controller.NewHTTPMuxFactory(),
+				kineServer.NewHTTPMuxFactory(),
+			},
+			DrainPeriod: cfg.ShutdownConfig.DrainPeriod,
+			OnShutdown:  []func(){stopAPIServer},
+			GRPCFactories: []combinedserver.GRPCServerFactory{
+				kms.NewGRPCServerFactory(cfg),
+				metadataserver.NewGRPCServerFactory(cfg),
+			},
+			GRPCOptions: console.NewGRPCServerOptions(ctx, cfg),
+			ACME: &combinedserver.ACMEConfig{
+				Email:        cfg.ACMEConfig.Email,
+				Domains:      cfg.ACMEConfig.Domains,
+				CacheDir:     cfg.ACMEConfig.CacheDir,
+				DirectoryURL: cfg.ACMEConfig.DirectoryURL,
+			},
+			GetCertificate: spiffeSource.GetCertificateFunc(),
+			TLS: &combinedserver.TLSConfig{
+				MinVersion:   cfg.TLSConfig.MinVersion,
+				MaxVersion:   cfg.TLSConfig.MaxVersion,
+				CipherSuites: cfg.TLSConfig.CipherSuites,
+			},
+			ClientCAs:               clientCAs.PoolFunc(),
+			VerifyClientCertificate: clientCAs.VerifyFunc(),
+			OperatorClientCAs:       operatorCAs.CertificatesFunc(),
+			CORS: &combinedserver.CORSConfig{
+				AllowedOrigins:   cfg.CORSConfig.AllowedOrigins,
+				AllowedMethods:   cfg.CORSConfig.AllowedMethods,
+				AllowedHeaders:   cfg.CORSConfig.AllowedHeaders,
+				AllowCredentials: cfg.CORSConfig.AllowCredentials,
 			},
-			GRPCFactory: kms.NewGRPCServerFactory(cfg),
 		})
 		if err != nil {
 			logger.Error("Failed to create combined server", zap.Error(err))
@@ -108,11 +253,17 @@ func main() {
 
 	sig := <-signals
 
-	logger.Info("Received signal", zap.String("signal", sig.String()))
+	logger.Info("Received signal", zap.String("signal", sig.String()),
+		zap.Duration("timeout", cfg.ShutdownConfig.Timeout))
+
+	// The signal cancelled ctx, so the finalizers share a context bounded by the
+	// shutdown timeout instead. A second signal exits right away.
+	shutdownCtx, cancelShutdown := context.WithTimeout(rootCtx, cfg.ShutdownConfig.Timeout)
+	defer cancelShutdown()
 
 	// Call the finalizers in reverse order.
 	for i := len(finalizers) - 1; i >= 0; i-- {
-		err := finalizers[i](ctx)
+		err := finalizers[i](shutdownCtx)
 		if err != nil {
 			logger.Error("Failed to shutdown", zap.Error(err))
 		}
@@ -0,0 +1,10 @@
+// Package openapi embeds the Swagger documents generated for the plain HTTP APIs of
+// Kommodity, see the go:generate directives in the doc.go files of their packages.
+package openapi
+
+import "embed"
+
+// Documents holds the swagger.json and swagger.yaml of every API, one directory each.
+//
+//go:embed */swagger.json */swagger.yaml
+var Documents embed.FS
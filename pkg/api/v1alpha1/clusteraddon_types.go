@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterAddonPhasePending means the workload cluster cannot be reached yet.
+	ClusterAddonPhasePending = "Pending"
+	// ClusterAddonPhaseInstalling means the install job runs in the workload cluster.
+	ClusterAddonPhaseInstalling = "Installing"
+	// ClusterAddonPhaseInstalled means the install job of the current spec succeeded.
+	ClusterAddonPhaseInstalled = "Installed"
+	// ClusterAddonPhaseUninstalling means the uninstall job of a deleted addon runs in the
+	// workload cluster.
+	ClusterAddonPhaseUninstalling = "Uninstalling"
+	// ClusterAddonPhaseFailed means the install or uninstall job gave up. Changing the spec or
+	// the values retries an install.
+	ClusterAddonPhaseFailed = "Failed"
+)
+
+// AddonChart references a Helm chart.
+type AddonChart struct {
+	// Repository is the URL of the chart repository, or an oci:// registry.
+	Repository string `json:"repository"`
+	// Name is the name of the chart.
+	Name string `json:"name"`
+	// Version is the version of the chart. Defaults to the latest version.
+	Version string `json:"version,omitempty"`
+}
+
+// ClusterAddonSpec defines a Helm chart installed into a workload cluster.
+type ClusterAddonSpec struct {
+	// ClusterName is the name of the Cluster in the namespace of the addon.
+	ClusterName string `json:"clusterName"`
+	// Chart is the Helm chart of the addon.
+	Chart AddonChart `json:"chart"`
+	// ReleaseName is the name of the Helm release. Defaults to the name of the chart.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Namespace is the namespace of the release in the workload cluster. Defaults to the
+	// release name.
+	Namespace string `json:"namespace,omitempty"`
+	// ValuesSecretName names a Secret in the namespace of the addon whose values.yaml key
+	// holds the chart values.
+	ValuesSecretName string `json:"valuesSecretName,omitempty"`
+	// DisableUpgrade leaves an existing release alone instead of upgrading it, for addons
+	// that are managed by GitOps tools after the first installation.
+	DisableUpgrade bool `json:"disableUpgrade,omitempty"`
+}
+
+// ClusterAddonStatus reports the installation of the addon.
+type ClusterAddonStatus struct {
+	// ObservedGeneration is the generation of the spec the status refers to.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase is Pending, Installing, Installed, Uninstalling or Failed.
+	Phase string `json:"phase,omitempty"`
+	// Message explains what the addon is waiting for or why it failed.
+	Message string `json:"message,omitempty"`
+	// ValuesHash is the hash of the values the addon was last installed with.
+	ValuesHash string `json:"valuesHash,omitempty"`
+	// InstalledVersion is the chart version of the last successful installation.
+	InstalledVersion string `json:"installedVersion,omitempty"`
+}
+
+// ClusterAddon installs a Helm chart into a workload cluster. It replaces the ConfigMaps
+// with well-known keys that drove the installation of single components.
+type ClusterAddon struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterAddonSpec   `json:"spec,omitempty"`
+	Status ClusterAddonStatus `json:"status,omitempty"`
+}
+
+// ClusterAddonList contains a list of ClusterAddons.
+type ClusterAddonList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterAddon `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&ClusterAddon{}, &ClusterAddonList{})
+}
@@ -0,0 +1,126 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterHealthPhaseHealthy means every check of the cluster passes.
+	ClusterHealthPhaseHealthy = "Healthy"
+	// ClusterHealthPhaseDegraded means at least one check of the cluster fails.
+	ClusterHealthPhaseDegraded = "Degraded"
+	// ClusterHealthPhaseUnknown means no check fails, but some could not be evaluated.
+	ClusterHealthPhaseUnknown = "Unknown"
+
+	// ClusterHealthClusterReady mirrors the Ready condition of the Cluster.
+	ClusterHealthClusterReady = "ClusterReady"
+	// ClusterHealthControlPlaneReady mirrors the ControlPlaneReady condition of the Cluster.
+	ClusterHealthControlPlaneReady = "ControlPlaneReady"
+	// ClusterHealthInfrastructureReady mirrors the InfrastructureReady condition of the Cluster.
+	ClusterHealthInfrastructureReady = "InfrastructureReady"
+	// ClusterHealthNodesHealthy reports whether the nodes of all Machines are healthy.
+	ClusterHealthNodesHealthy = "NodesHealthy"
+	// ClusterHealthControlPlaneLeasesHealthy reports whether the controller manager and the
+	// scheduler of the workload cluster renew their leader election leases.
+	ClusterHealthControlPlaneLeasesHealthy = "ControlPlaneLeasesHealthy"
+	// ClusterHealthEtcdQuorum reports whether a majority of the etcd members is healthy. It
+	// is only set for Clusters that opt in to the check.
+	ClusterHealthEtcdQuorum = "EtcdQuorum"
+	// ClusterHealthAddonsInSync reports whether the charts Kommodity installed for the CNI,
+	// the cloud controller manager and the cluster autoscaler are deployed with the version
+	// and values they are configured with. It is only set for Clusters with such add-ons.
+	ClusterHealthAddonsInSync = "AddonsInSync"
+)
+
+// MachineCount counts the Machines of a cluster and those with a healthy node.
+type MachineCount struct {
+	// Total is the number of Machines.
+	Total int32 `json:"total"`
+	// Ready is the number of Machines whose node is healthy.
+	Ready int32 `json:"ready"`
+}
+
+// MachineDeploymentRollout is the rollout progress of a MachineDeployment of the cluster.
+type MachineDeploymentRollout struct {
+	// Name is the name of the MachineDeployment.
+	Name string `json:"name"`
+	// Phase is the phase reported by the MachineDeployment.
+	Phase string `json:"phase,omitempty"`
+	// Desired is the number of replicas the MachineDeployment asks for.
+	Desired int32 `json:"desired"`
+	// Updated is the number of Machines with the current template.
+	Updated int32 `json:"updated"`
+	// Ready is the number of ready Machines.
+	Ready int32 `json:"ready"`
+	// Available is the number of Machines that have been ready for the minimum time.
+	Available int32 `json:"available"`
+	// RolledOut is true when all desired Machines are updated and available.
+	RolledOut bool `json:"rolledOut"`
+	// StuckMachines are the Machines that failed or did not get a healthy node in time.
+	StuckMachines []StuckMachine `json:"stuckMachines,omitempty"`
+}
+
+// StuckMachine is a Machine that holds up a rollout.
+type StuckMachine struct {
+	// Name is the name of the Machine.
+	Name string `json:"name"`
+	// Phase is the phase of the Machine.
+	Phase string `json:"phase,omitempty"`
+	// Message explains why the Machine is stuck.
+	Message string `json:"message,omitempty"`
+}
+
+// AddonSync compares the deployed Helm release of an add-on with its configuration.
+type AddonSync struct {
+	// Name is the name of the Helm release.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Helm release.
+	Namespace string `json:"namespace"`
+	// DesiredVersion is the configured chart version, "latest" when it is not pinned.
+	DesiredVersion string `json:"desiredVersion,omitempty"`
+	// DeployedVersion is the chart version of the deployed release.
+	DeployedVersion string `json:"deployedVersion,omitempty"`
+	// InSync is true when the deployed release matches the configuration.
+	InSync bool `json:"inSync"`
+	// Message explains the drift.
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterHealthStatus aggregates the health of a workload cluster.
+type ClusterHealthStatus struct {
+	// Phase is Healthy, Degraded or Unknown.
+	Phase string `json:"phase,omitempty"`
+	// LastUpdateTime is when the checks last ran.
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// ControlPlaneMachines counts the control plane Machines.
+	ControlPlaneMachines MachineCount `json:"controlPlaneMachines"`
+	// WorkerMachines counts the other Machines.
+	WorkerMachines MachineCount `json:"workerMachines"`
+	// MachineDeployments report the rollout progress of each MachineDeployment.
+	MachineDeployments []MachineDeploymentRollout `json:"machineDeployments,omitempty"`
+	// Addons compare the add-ons installed by Kommodity with their configuration.
+	Addons []AddonSync `json:"addons,omitempty"`
+	// Conditions hold the result of each check.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ClusterHealth aggregates the health of the Cluster of the same name, so that UIs have
+// a single object to watch. It is created and owned by Kommodity.
+type ClusterHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ClusterHealthStatus `json:"status,omitempty"`
+}
+
+// ClusterHealthList contains a list of ClusterHealths.
+type ClusterHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterHealth `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&ClusterHealth{}, &ClusterHealthList{})
+}
@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterInstancePhasePending means the ClusterTemplate of the instance does not exist yet.
+	ClusterInstancePhasePending = "Pending"
+	// ClusterInstancePhaseCreated means every object of the template exists.
+	ClusterInstancePhaseCreated = "Created"
+	// ClusterInstancePhaseFailed means the template could not be rendered or an object not created.
+	ClusterInstancePhaseFailed = "Failed"
+)
+
+// ClusterTemplateParameter declares a value of a ClusterTemplate.
+type ClusterTemplateParameter struct {
+	// Name is the key of the value in .Values.
+	Name string `json:"name"`
+	// Description explains the value to the users of the template.
+	Description string `json:"description,omitempty"`
+	// Default is used when the ClusterInstance does not set the value.
+	Default string `json:"default,omitempty"`
+	// Required values have no default and must be set by the ClusterInstance.
+	Required bool `json:"required,omitempty"`
+}
+
+// ClusterTemplateSpec defines the objects of a cluster and the values they are rendered with.
+type ClusterTemplateSpec struct {
+	// Parameters are the values ClusterInstances may set. Other values are rejected.
+	Parameters []ClusterTemplateParameter `json:"parameters,omitempty"`
+	// Manifests are the YAML manifests of the cluster, e.g. the Cluster, TalosControlPlane,
+	// MachineDeployments and their infrastructure objects, rendered as a Go template with
+	// .Name, .Namespace and .Values.
+	Manifests string `json:"manifests"`
+}
+
+// ClusterTemplate is a parameterized blueprint of the CAPI objects of a cluster. Templates
+// in the namespace of a ClusterInstance take precedence over the shared templates in the
+// Kommodity namespace.
+type ClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterTemplateSpec `json:"spec,omitempty"`
+}
+
+// ClusterTemplateList contains a list of ClusterTemplates.
+type ClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterTemplate `json:"items"`
+}
+
+// ClusterInstanceSpec selects the ClusterTemplate of a cluster and its values.
+type ClusterInstanceSpec struct {
+	// TemplateName is the name of the ClusterTemplate.
+	TemplateName string `json:"templateName"`
+	// Values are the values of the parameters of the template.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// ClusterInstanceStatus reports the objects created from the template.
+type ClusterInstanceStatus struct {
+	// ObservedGeneration is the generation of the spec the status refers to.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase is Pending, Created or Failed.
+	Phase string `json:"phase,omitempty"`
+	// Message explains what the instance is waiting for or why it failed.
+	Message string `json:"message,omitempty"`
+	// Objects is the number of objects rendered from the template.
+	Objects int32 `json:"objects,omitempty"`
+}
+
+// ClusterInstance creates a cluster from a ClusterTemplate. The objects of the template
+// are created in the namespace of the instance and owned by it.
+type ClusterInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterInstanceSpec   `json:"spec,omitempty"`
+	Status ClusterInstanceStatus `json:"status,omitempty"`
+}
+
+// ClusterInstanceList contains a list of ClusterInstances.
+type ClusterInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterInstance `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&ClusterTemplate{}, &ClusterTemplateList{}, &ClusterInstance{}, &ClusterInstanceList{})
+}
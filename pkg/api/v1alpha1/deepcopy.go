@@ -0,0 +1,639 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the receiver into out.
+func (in *TalosUpgradePlan) DeepCopyInto(out *TalosUpgradePlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of the TalosUpgradePlan.
+func (in *TalosUpgradePlan) DeepCopy() *TalosUpgradePlan {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TalosUpgradePlan)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *TalosUpgradePlan) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TalosUpgradePlanList) DeepCopyInto(out *TalosUpgradePlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]TalosUpgradePlan, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the TalosUpgradePlanList.
+func (in *TalosUpgradePlanList) DeepCopy() *TalosUpgradePlanList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TalosUpgradePlanList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *TalosUpgradePlanList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TalosUpgradePlanSpec) DeepCopyInto(out *TalosUpgradePlanSpec) {
+	*out = *in
+
+	if in.HealthCheckTimeout != nil {
+		out.HealthCheckTimeout = new(metav1.Duration)
+		*out.HealthCheckTimeout = *in.HealthCheckTimeout
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TalosUpgradePlanStatus) DeepCopyInto(out *TalosUpgradePlanStatus) {
+	*out = *in
+
+	if in.CurrentMachineStartTime != nil {
+		out.CurrentMachineStartTime = in.CurrentMachineStartTime.DeepCopy()
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of the ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterHealth) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterHealthList) DeepCopyInto(out *ClusterHealthList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]ClusterHealth, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the ClusterHealthList.
+func (in *ClusterHealthList) DeepCopy() *ClusterHealthList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterHealthList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterHealthList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterHealthStatus) DeepCopyInto(out *ClusterHealthStatus) {
+	*out = *in
+
+	if in.LastUpdateTime != nil {
+		out.LastUpdateTime = in.LastUpdateTime.DeepCopy()
+	}
+
+	if in.MachineDeployments != nil {
+		out.MachineDeployments = make([]MachineDeploymentRollout, len(in.MachineDeployments))
+		for i := range in.MachineDeployments {
+			in.MachineDeployments[i].DeepCopyInto(&out.MachineDeployments[i])
+		}
+	}
+
+	if in.Addons != nil {
+		out.Addons = make([]AddonSync, len(in.Addons))
+		copy(out.Addons, in.Addons)
+	}
+
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MachineDeploymentRollout) DeepCopyInto(out *MachineDeploymentRollout) {
+	*out = *in
+
+	if in.StuckMachines != nil {
+		out.StuckMachines = make([]StuckMachine, len(in.StuckMachines))
+		copy(out.StuckMachines, in.StuckMachines)
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterAddon) DeepCopyInto(out *ClusterAddon) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy returns a deep copy of the ClusterAddon.
+func (in *ClusterAddon) DeepCopy() *ClusterAddon {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterAddon)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterAddon) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterAddonList) DeepCopyInto(out *ClusterAddonList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]ClusterAddon, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the ClusterAddonList.
+func (in *ClusterAddonList) DeepCopy() *ClusterAddonList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterAddonList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterAddonList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ProviderCredential) DeepCopyInto(out *ProviderCredential) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+// DeepCopy returns a deep copy of the ProviderCredential.
+func (in *ProviderCredential) DeepCopy() *ProviderCredential {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ProviderCredential)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ProviderCredential) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ProviderCredentialList) DeepCopyInto(out *ProviderCredentialList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]ProviderCredential, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the ProviderCredentialList.
+func (in *ProviderCredentialList) DeepCopy() *ProviderCredentialList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ProviderCredentialList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ProviderCredentialList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of the ClusterTemplate.
+func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterTemplate)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]ClusterTemplate, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the ClusterTemplateList.
+func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterTemplateList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+	*out = *in
+
+	if in.Parameters != nil {
+		out.Parameters = make([]ClusterTemplateParameter, len(in.Parameters))
+		copy(out.Parameters, in.Parameters)
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterInstance) DeepCopyInto(out *ClusterInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy returns a deep copy of the ClusterInstance.
+func (in *ClusterInstance) DeepCopy() *ClusterInstance {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterInstance)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterInstance) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterInstanceList) DeepCopyInto(out *ClusterInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]ClusterInstance, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the ClusterInstanceList.
+func (in *ClusterInstanceList) DeepCopy() *ClusterInstanceList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ClusterInstanceList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterInstanceList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterInstanceSpec) DeepCopyInto(out *ClusterInstanceSpec) {
+	*out = *in
+
+	if in.Values != nil {
+		out.Values = make(map[string]string, len(in.Values))
+		for key, value := range in.Values {
+			out.Values[key] = value
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of the RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *RegistryMirror) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *RegistryMirrorList) DeepCopyInto(out *RegistryMirrorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]RegistryMirror, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the RegistryMirrorList.
+func (in *RegistryMirrorList) DeepCopy() *RegistryMirrorList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(RegistryMirrorList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *RegistryMirrorList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *RegistryMirrorSpec) DeepCopyInto(out *RegistryMirrorSpec) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+
+	if in.Mirrors != nil {
+		out.Mirrors = make([]RegistryMirrorEndpoints, len(in.Mirrors))
+		for i := range in.Mirrors {
+			out.Mirrors[i] = in.Mirrors[i]
+			out.Mirrors[i].Endpoints = append([]string(nil), in.Mirrors[i].Endpoints...)
+		}
+	}
+
+	if in.TLS != nil {
+		out.TLS = make([]RegistryTLS, len(in.TLS))
+		copy(out.TLS, in.TLS)
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MachineConfigPatch) DeepCopyInto(out *MachineConfigPatch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of the MachineConfigPatch.
+func (in *MachineConfigPatch) DeepCopy() *MachineConfigPatch {
+	if in == nil {
+		return nil
+	}
+
+	out := new(MachineConfigPatch)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *MachineConfigPatch) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MachineConfigPatchList) DeepCopyInto(out *MachineConfigPatchList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]MachineConfigPatch, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the MachineConfigPatchList.
+func (in *MachineConfigPatchList) DeepCopy() *MachineConfigPatchList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(MachineConfigPatchList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *MachineConfigPatchList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MachineConfigPatchSpec) DeepCopyInto(out *MachineConfigPatchSpec) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	in.MachineSelector.DeepCopyInto(&out.MachineSelector)
+
+	if in.StrategicPatches != nil {
+		out.StrategicPatches = append([]string(nil), in.StrategicPatches...)
+	}
+
+	if in.JSONPatches != nil {
+		out.JSONPatches = make([]MachineConfigJSONPatch, len(in.JSONPatches))
+		for i := range in.JSONPatches {
+			out.JSONPatches[i] = in.JSONPatches[i]
+			out.JSONPatches[i].Value = in.JSONPatches[i].Value.DeepCopy()
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *InfraCluster) DeepCopyInto(out *InfraCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of the InfraCluster.
+func (in *InfraCluster) DeepCopy() *InfraCluster {
+	if in == nil {
+		return nil
+	}
+
+	out := new(InfraCluster)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *InfraCluster) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *InfraClusterList) DeepCopyInto(out *InfraClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]InfraCluster, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the InfraClusterList.
+func (in *InfraClusterList) DeepCopy() *InfraClusterList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(InfraClusterList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *InfraClusterList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *InfraClusterSpec) DeepCopyInto(out *InfraClusterSpec) {
+	*out = *in
+
+	if in.MaxMachines != nil {
+		maxMachines := *in.MaxMachines
+		out.MaxMachines = &maxMachines
+	}
+}
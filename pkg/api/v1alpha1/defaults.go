@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DefaultTalosUpgradeHealthCheckTimeout bounds the upgrade of a single Machine when a
+// TalosUpgradePlan sets none.
+const DefaultTalosUpgradeHealthCheckTimeout = 15 * time.Minute
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.SchemeBuilder.Register(addDefaultingFuncs)
+}
+
+// addDefaultingFuncs registers the defaults of the kommodity.io types. The API server
+// applies them in process when the objects are created or updated.
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&ClusterAddon{}, func(obj any) {
+		SetClusterAddonDefaults(obj.(*ClusterAddon)) //nolint:forcetypeassert // Registered for ClusterAddons.
+	})
+	scheme.AddTypeDefaultingFunc(&TalosUpgradePlan{}, func(obj any) {
+		SetTalosUpgradePlanDefaults(obj.(*TalosUpgradePlan)) //nolint:forcetypeassert // Registered for plans.
+	})
+
+	return nil
+}
+
+// SetClusterAddonDefaults names the release after the chart and installs it into a
+// namespace named after the release.
+func SetClusterAddonDefaults(addon *ClusterAddon) {
+	if addon.Spec.ReleaseName == "" {
+		addon.Spec.ReleaseName = addon.Spec.Chart.Name
+	}
+
+	if addon.Spec.Namespace == "" {
+		addon.Spec.Namespace = addon.Spec.ReleaseName
+	}
+}
+
+// SetTalosUpgradePlanDefaults sets the health check timeout of the plan.
+func SetTalosUpgradePlanDefaults(plan *TalosUpgradePlan) {
+	if plan.Spec.HealthCheckTimeout == nil {
+		plan.Spec.HealthCheckTimeout = &metav1.Duration{Duration: DefaultTalosUpgradeHealthCheckTimeout}
+	}
+}
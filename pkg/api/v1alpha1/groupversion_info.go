@@ -0,0 +1,20 @@
+// Package v1alpha1 contains the kommodity.io/v1alpha1 API. Its kinds are served as CRDs,
+// embedded with the provider CRDs in pkg/provider/crds/kommodity.
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+//nolint:gochecknoglobals // Scheme registration follows the Kubernetes API conventions.
+var (
+	// GroupVersion is the group version of the kommodity.io API.
+	GroupVersion = schema.GroupVersion{Group: "kommodity.io", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the kommodity.io types with a scheme.
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the kommodity.io types to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
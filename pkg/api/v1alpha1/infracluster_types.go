@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationInfraClusterSelector places a new KubevirtMachine on one of the
+	// InfraClusters of its namespace whose labels match, in the syntax of label selectors
+	// like "region=eu-west,tier!=spot". Empty matches every InfraCluster. Set it on the
+	// machine template of a MachineDeployment, whose annotations are copied to the
+	// KubevirtMachines of its machines.
+	AnnotationInfraClusterSelector = "kommodity.io/infra-cluster-selector"
+	// AnnotationInfraPlacement is the strategy a new KubevirtMachine is placed with,
+	// InfraPlacementSpread by default.
+	AnnotationInfraPlacement = "kommodity.io/infra-placement"
+	// LabelInfraCluster names the InfraCluster a KubevirtMachine was placed on.
+	LabelInfraCluster = "kommodity.io/infra-cluster"
+
+	// InfraPlacementSpread places a machine on the InfraCluster with the fewest machines
+	// of its MachineDeployment, then with the fewest machines overall.
+	InfraPlacementSpread = "Spread"
+	// InfraPlacementPack places a machine on the InfraCluster with the most machines that
+	// still has capacity left, keeping the others free.
+	InfraPlacementPack = "Pack"
+)
+
+// InfraClusterSpec defines a KubeVirt infrastructure cluster machines can be placed on.
+type InfraClusterSpec struct {
+	// SecretName is the name of the Secret holding the kubeconfig of the infrastructure
+	// cluster under the kubeconfig key, in the namespace of the InfraCluster. As for
+	// spec.infraClusterSecretRef of KubevirtMachines, the namespace key of the Secret sets
+	// the namespace of the virtual machines.
+	SecretName string `json:"secretName"`
+	// MaxMachines is the number of machines of the namespace that can be placed on the
+	// infrastructure cluster. Unset places machines without limit.
+	MaxMachines *int32 `json:"maxMachines,omitempty"`
+	// Unschedulable stops placing new machines on the infrastructure cluster, for example
+	// before its maintenance. Machines placed on it already are left alone.
+	Unschedulable bool `json:"unschedulable,omitempty"`
+}
+
+// InfraCluster registers a KubeVirt infrastructure cluster with the clusters of its
+// namespace, so that the machines of their MachineDeployments can be placed across
+// several infrastructure clusters by their labels and capacity.
+type InfraCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec InfraClusterSpec `json:"spec,omitempty"`
+}
+
+// InfraClusterList contains a list of InfraClusters.
+type InfraClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []InfraCluster `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&InfraCluster{}, &InfraClusterList{})
+}
@@ -0,0 +1,57 @@
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MachineConfigJSONPatch is a JSON patch (RFC 6902) operation on the Talos machine
+// configuration.
+type MachineConfigJSONPatch struct {
+	// Op is the operation: add, remove, replace or test.
+	Op string `json:"op"`
+	// Path is the JSON pointer of the value operated on, like /machine/install/extraKernelArgs.
+	Path string `json:"path"`
+	// Value is the value added, replaced or tested.
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+}
+
+// MachineConfigPatchSpec defines the patches of the Talos machine configuration of a set
+// of machines.
+type MachineConfigPatchSpec struct {
+	// ClusterSelector selects the Clusters whose machines are patched. Empty selects every
+	// Cluster of the namespace, or of every namespace for the MachineConfigPatches of the
+	// Kommodity namespace.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// MachineSelector selects the machines of the Clusters that are patched by their labels,
+	// like cluster.x-k8s.io/deployment-name for the machines of a MachineDeployment or
+	// cluster.x-k8s.io/control-plane for those of the control plane. Empty selects every
+	// machine.
+	MachineSelector metav1.LabelSelector `json:"machineSelector,omitempty"`
+	// StrategicPatches are Talos strategic merge patches, YAML documents merged into the
+	// machine configuration.
+	StrategicPatches []string `json:"strategicPatches,omitempty"`
+	// JSONPatches are JSON patch operations applied to the machine configuration.
+	JSONPatches []MachineConfigJSONPatch `json:"jsonPatches,omitempty"`
+}
+
+// MachineConfigPatch patches the Talos machine configuration of new machines, like their
+// kernel arguments or sysctls, without changing the templates of their clusters.
+type MachineConfigPatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MachineConfigPatchSpec `json:"spec,omitempty"`
+}
+
+// MachineConfigPatchList contains a list of MachineConfigPatches.
+type MachineConfigPatchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MachineConfigPatch `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&MachineConfigPatch{}, &MachineConfigPatchList{})
+}
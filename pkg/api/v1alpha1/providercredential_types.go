@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ProviderCredentialKubevirt binds a Secret holding the kubeconfig of the KubeVirt
+	// infrastructure cluster, under the kubeconfig key.
+	ProviderCredentialKubevirt = "kubevirt"
+	// ProviderCredentialScaleway binds a Secret holding SCW_ACCESS_KEY and SCW_SECRET_KEY.
+	ProviderCredentialScaleway = "scaleway"
+	// ProviderCredentialAzure binds a Secret holding AZURE_SUBSCRIPTION_ID, AZURE_TENANT_ID,
+	// AZURE_CLIENT_ID and AZURE_CLIENT_SECRET.
+	ProviderCredentialAzure = "azure"
+)
+
+// ProviderCredentialSpec binds the credentials of an infrastructure provider to a namespace.
+type ProviderCredentialSpec struct {
+	// Provider is the infrastructure provider the credentials are for: kubevirt, scaleway
+	// or azure. A namespace holds at most one ProviderCredential per provider.
+	Provider string `json:"provider"`
+	// SecretName is the name of the Secret holding the credentials, in the namespace of the
+	// ProviderCredential.
+	SecretName string `json:"secretName"`
+}
+
+// ProviderCredential makes the clusters of its namespace use the credentials of a Secret
+// in the same namespace, instead of the credentials configured for the whole management
+// plane.
+type ProviderCredential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProviderCredentialSpec `json:"spec,omitempty"`
+}
+
+// ProviderCredentialList contains a list of ProviderCredentials.
+type ProviderCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ProviderCredential `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&ProviderCredential{}, &ProviderCredentialList{})
+}
@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistryMirrorEndpoints redirects the pulls from a registry to mirrors.
+type RegistryMirrorEndpoints struct {
+	// Registry is the registry host mirrored, like docker.io, or "*" for every registry.
+	Registry string `json:"registry"`
+	// Endpoints are the URLs of the mirrors, tried in order.
+	Endpoints []string `json:"endpoints"`
+	// OverridePath uses the endpoints as is, without appending /v2, for mirrors serving
+	// the registry API on another path.
+	OverridePath bool `json:"overridePath,omitempty"`
+	// SkipFallback fails pulls the mirrors cannot serve instead of falling back to the
+	// registry, as airgapped sites cannot reach it anyway.
+	SkipFallback bool `json:"skipFallback,omitempty"`
+}
+
+// RegistryTLS configures how the mirrors of a host are trusted.
+type RegistryTLS struct {
+	// Host is the host and port of a mirror endpoint, like registry.example.com:5000.
+	Host string `json:"host"`
+	// CA is the PEM encoded certificate authority the mirror certificates are verified with.
+	CA string `json:"ca,omitempty"`
+	// InsecureSkipVerify disables the verification of the mirror certificates.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// RegistryMirrorSpec defines the registry mirrors of the machines of a set of clusters.
+type RegistryMirrorSpec struct {
+	// ClusterSelector selects the Clusters whose machines use the mirrors. Empty selects
+	// every Cluster of the namespace, or of every namespace for the RegistryMirrors of
+	// the Kommodity namespace.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// Mirrors are the registries mirrored.
+	Mirrors []RegistryMirrorEndpoints `json:"mirrors,omitempty"`
+	// TLS configures the trust of the mirror hosts.
+	TLS []RegistryTLS `json:"tls,omitempty"`
+}
+
+// RegistryMirror adds registry mirrors and their certificate authorities to the Talos
+// machine configuration, and so to the containerd configuration, of new machines.
+type RegistryMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RegistryMirrorSpec `json:"spec,omitempty"`
+}
+
+// RegistryMirrorList contains a list of RegistryMirrors.
+type RegistryMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RegistryMirror `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&RegistryMirror{}, &RegistryMirrorList{})
+}
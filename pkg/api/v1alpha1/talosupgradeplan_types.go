@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TalosUpgradePlanPhasePending means the plan was not picked up yet.
+	TalosUpgradePlanPhasePending = "Pending"
+	// TalosUpgradePlanPhaseUpgrading means a Machine of the cluster is being upgraded.
+	TalosUpgradePlanPhaseUpgrading = "Upgrading"
+	// TalosUpgradePlanPhaseCompleted means every Machine runs the image and is healthy.
+	TalosUpgradePlanPhaseCompleted = "Completed"
+	// TalosUpgradePlanPhaseFailed means the plan halted on a Machine. Changing the spec resumes it.
+	TalosUpgradePlanPhaseFailed = "Failed"
+)
+
+// TalosUpgradePlanSpec defines the rollout of a Talos OS upgrade across a cluster.
+type TalosUpgradePlanSpec struct {
+	// ClusterName is the name of the Cluster in the namespace of the plan.
+	ClusterName string `json:"clusterName"`
+	// Image is the Talos installer image the Machines are upgraded to.
+	Image string `json:"image"`
+	// HealthCheckTimeout bounds the upgrade of a single Machine, until its node is healthy
+	// again. The plan halts when it is exceeded. Defaults to 15m.
+	HealthCheckTimeout *metav1.Duration `json:"healthCheckTimeout,omitempty"`
+}
+
+// TalosUpgradePlanStatus reports the progress of the rollout.
+type TalosUpgradePlanStatus struct {
+	// ObservedGeneration is the generation of the spec the status refers to.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase is Pending, Upgrading, Completed or Failed.
+	Phase string `json:"phase,omitempty"`
+	// Message explains what the plan is waiting for or why it halted.
+	Message string `json:"message,omitempty"`
+	// CurrentMachine is the Machine being upgraded.
+	CurrentMachine string `json:"currentMachine,omitempty"`
+	// CurrentMachineStartTime is when the upgrade of the current Machine started.
+	CurrentMachineStartTime *metav1.Time `json:"currentMachineStartTime,omitempty"`
+	// UpgradedMachines is the number of Machines running the image with a healthy node.
+	UpgradedMachines int32 `json:"upgradedMachines,omitempty"`
+	// TotalMachines is the number of Machines of the cluster.
+	TotalMachines int32 `json:"totalMachines,omitempty"`
+}
+
+// TalosUpgradePlan rolls a Talos OS upgrade through the Machines of a cluster, control
+// plane first, one Machine at a time.
+type TalosUpgradePlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TalosUpgradePlanSpec   `json:"spec,omitempty"`
+	Status TalosUpgradePlanStatus `json:"status,omitempty"`
+}
+
+// TalosUpgradePlanList contains a list of TalosUpgradePlans.
+type TalosUpgradePlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TalosUpgradePlan `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	SchemeBuilder.Register(&TalosUpgradePlan{}, &TalosUpgradePlanList{})
+}
@@ -0,0 +1,155 @@
+// Package apidocs serves the Swagger documents of the metadata and attestation APIs,
+// and a Swagger UI to browse and try them, on the combined server.
+package apidocs
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+
+	"github.com/kommodity-io/kommodity/openapi"
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// DocumentsPathPrefix is the path the documents are served under, followed by the
+	// name of the API, and .yaml for the YAML document.
+	DocumentsPathPrefix = "/openapi/"
+	// SwaggerUIPath is the path of the Swagger UI. Its assets are served below it.
+	SwaggerUIPath = "/swagger-ui"
+)
+
+// swaggerUIAssets holds the vendored Swagger UI release, see scripts/fetch-swagger-ui.sh.
+//
+//go:embed swagger-ui
+var swaggerUIAssets embed.FS
+
+// APIs lists the APIs whose documents are served.
+//
+//nolint:gochecknoglobals // Read-only list of the documented APIs.
+var APIs = []string{"attestation", "metadata"}
+
+// NewHTTPMuxFactory creates a new HTTP mux factory serving the API documents. The
+// documents are public, like the API server's OpenAPI documents.
+func NewHTTPMuxFactory() combinedserver.HTTPMuxFactory {
+	return func(mux *http.ServeMux) error {
+		for _, api := range APIs {
+			jsonDocument, err := fs.ReadFile(openapi.Documents, path.Join(api, "swagger.json"))
+			if err != nil {
+				return fmt.Errorf("failed to read the %s API document: %w", api, err)
+			}
+
+			yamlDocument, err := fs.ReadFile(openapi.Documents, path.Join(api, "swagger.yaml"))
+			if err != nil {
+				return fmt.Errorf("failed to read the %s API document: %w", api, err)
+			}
+
+			mux.HandleFunc("GET "+DocumentsPathPrefix+api, serveJSONDocument(jsonDocument))
+			mux.HandleFunc("GET "+DocumentsPathPrefix+api+".yaml", serveDocument("application/yaml", yamlDocument))
+		}
+
+		assets, err := fs.Sub(swaggerUIAssets, "swagger-ui")
+		if err != nil {
+			return fmt.Errorf("failed to read the Swagger UI assets: %w", err)
+		}
+
+		mux.HandleFunc("GET "+SwaggerUIPath, serveSwaggerUI)
+		mux.Handle("GET "+SwaggerUIPath+"/",
+			http.StripPrefix(SwaggerUIPath+"/", http.FileServer(http.FS(assets))))
+
+		return nil
+	}
+}
+
+func serveDocument(contentType string, document []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+
+		_, err := w.Write(document)
+		if err != nil {
+			logging.FromContext(r.Context()).Debug("Failed to write API document", zap.Error(err))
+		}
+	}
+}
+
+// serveJSONDocument serves the JSON document with its schemes set to the scheme it is
+// requested over. The documents are generated for plain HTTP, which browsers refuse to
+// call from a Swagger UI served over HTTPS.
+func serveJSONDocument(document []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var swagger map[string]any
+
+		err := json.Unmarshal(document, &swagger)
+		if err != nil {
+			http.Error(w, "invalid API document", http.StatusInternalServerError)
+
+			return
+		}
+
+		swagger["schemes"] = []string{requestScheme(r)}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		err = json.NewEncoder(w).Encode(swagger)
+		if err != nil {
+			logging.FromContext(r.Context()).Debug("Failed to write API document", zap.Error(err))
+		}
+	}
+}
+
+// requestScheme returns the scheme the client used, also behind a reverse proxy
+// terminating TLS.
+func requestScheme(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded == "http" || forwarded == "https" {
+		return forwarded
+	}
+
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// serveSwaggerUI serves a Swagger UI page listing the documents of all APIs.
+func serveSwaggerUI(w http.ResponseWriter, _ *http.Request) {
+	urls := make([]map[string]string, 0, len(APIs))
+	for _, api := range APIs {
+		urls = append(urls, map[string]string{"name": api, "url": DocumentsPathPrefix + api})
+	}
+
+	// Marshalling a list of string maps cannot fail.
+	urlsJSON, _ := json.Marshal(urls) //nolint:errchkjson // See above.
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	_, _ = fmt.Fprintf(w, swaggerUIPage, SwaggerUIPath, SwaggerUIPath, SwaggerUIPath, urlsJSON)
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Kommodity HTTP APIs</title>
+  <link rel="stylesheet" href="%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%s/swagger-ui-bundle.js"></script>
+  <script src="%s/swagger-ui-standalone-preset.js"></script>
+  <script>
+    // The standalone layout adds the top bar to switch between the APIs.
+    SwaggerUIBundle({
+      urls: %s,
+      dom_id: "#swagger-ui",
+      presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+      layout: "StandaloneLayout",
+    });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,124 @@
+package apidocs_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/apidocs"
+)
+
+func newMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	err := apidocs.NewHTTPMuxFactory()(mux)
+	if err != nil {
+		t.Fatalf("failed to register the API documents: %v", err)
+	}
+
+	return mux
+}
+
+func TestServesJSONDocumentsForRequestScheme(t *testing.T) {
+	t.Parallel()
+
+	mux := newMux(t)
+
+	for _, api := range apidocs.APIs {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, apidocs.DocumentsPathPrefix+api, nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for the %s API, got %d", api, recorder.Code)
+		}
+
+		var swagger struct {
+			Swagger string   `json:"swagger"`
+			Schemes []string `json:"schemes"`
+		}
+
+		err := json.Unmarshal(recorder.Body.Bytes(), &swagger)
+		if err != nil {
+			t.Fatalf("failed to decode the %s API document: %v", api, err)
+		}
+
+		if swagger.Swagger == "" {
+			t.Fatalf("expected a Swagger document for the %s API", api)
+		}
+
+		if len(swagger.Schemes) != 1 || swagger.Schemes[0] != "https" {
+			t.Fatalf("expected the schemes of the %s API to be [https], got %v", api, swagger.Schemes)
+		}
+	}
+}
+
+func TestServesYAMLDocuments(t *testing.T) {
+	t.Parallel()
+
+	mux := newMux(t)
+
+	for _, api := range apidocs.APIs {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, apidocs.DocumentsPathPrefix+api+".yaml", nil)
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for the %s API, got %d", api, recorder.Code)
+		}
+
+		if got := recorder.Header().Get("Content-Type"); got != "application/yaml" {
+			t.Fatalf("expected a YAML content type for the %s API, got %q", api, got)
+		}
+	}
+}
+
+func TestSwaggerUIListsAllAPIs(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, apidocs.SwaggerUIPath, nil)
+
+	recorder := httptest.NewRecorder()
+	newMux(t).ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	for _, api := range apidocs.APIs {
+		if !strings.Contains(recorder.Body.String(), `"url":"`+apidocs.DocumentsPathPrefix+api+`"`) {
+			t.Fatalf("expected the Swagger UI to list the %s API", api)
+		}
+	}
+}
+
+func TestSwaggerUIServesVendoredAssets(t *testing.T) {
+	t.Parallel()
+
+	mux := newMux(t)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, apidocs.SwaggerUIPath, nil)
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	if strings.Contains(recorder.Body.String(), "https://") {
+		t.Fatalf("expected the Swagger UI not to load assets from elsewhere:\n%s", recorder.Body.String())
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, apidocs.SwaggerUIPath+"/VERSION", nil)
+
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the Swagger UI assets to be served, got %d", recorder.Code)
+	}
+}
@@ -8,6 +8,8 @@ var (
 	ErrInvalidNonce = errors.New("invalid nonce")
 	// ErrExpiredNonce is returned when the nonce is expired.
 	ErrExpiredNonce = errors.New("expired nonce")
+	// ErrNonceExists is returned when a generated nonce is already stored.
+	ErrNonceExists = errors.New("nonce already exists")
 	// ErrIPMismatch is returned when the IP address does not match the nonce's bound IP.
 	ErrIPMismatch = errors.New("ip address does not match nonce's bound IP")
 	// ErrNonceNotFound is returned when the nonce is not found.
@@ -34,4 +36,8 @@ var (
 	ErrComponentMismatch = errors.New("component mismatch in attestation report")
 	// ErrPCRMismatch is returned when a PCR does not match the attestation report.
 	ErrPCRMismatch = errors.New("PCR mismatch in attestation report")
+	// ErrInvalidJoinTokenTTL is returned when a join token is requested with a TTL out of range.
+	ErrInvalidJoinTokenTTL = errors.New("join token TTL must be positive and at most 24h")
+	// ErrJoinTokenNotFound is returned when there is no valid join token.
+	ErrJoinTokenNotFound = errors.New("join token not found")
 )
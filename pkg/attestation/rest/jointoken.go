@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraptokenutil "k8s.io/cluster-bootstrap/token/util"
+	bootstrapsecretutil "k8s.io/cluster-bootstrap/util/secrets"
+)
+
+const (
+	// JoinTokenGroup is the group of machines authenticated with a join token, next to
+	// system:bootstrappers.
+	JoinTokenGroup = "system:bootstrappers:kommodity:machines"
+	// JoinTokenMachineAnnotation names the machine a join token is handed out to, as
+	// namespace/name.
+	JoinTokenMachineAnnotation = "kommodity.io/join-token-machine"
+	// DefaultJoinTokenTTL is how long a join token is valid unless requested otherwise.
+	DefaultJoinTokenTTL = time.Hour
+	// MaxJoinTokenTTL is the longest a join token can be valid.
+	MaxJoinTokenTTL = 24 * time.Hour
+
+	joinTokenLabel = "kommodity.io/join-token"
+)
+
+// JoinToken is a bootstrap token for a machine, handed out once the machine passed
+// attestation.
+type JoinToken struct {
+	ID         string    `example:"07401b"           json:"id"`
+	Machine    string    `example:"default/worker-0" json:"machine"`
+	Expiration time.Time `format:"date-time"        json:"expiration"`
+	// Token is only set when the token is handed out to the machine.
+	Token string `example:"07401b.f395accd246ae52d" json:"token,omitempty"`
+}
+
+// CreateJoinToken stores a new bootstrap token for the machine as a Secret. The
+// secret part of the token is not returned, the machine gets it after attestation.
+func CreateJoinToken(ctx context.Context, secrets v1.SecretInterface, machine types.NamespacedName,
+	ttl time.Duration, description string) (*JoinToken, error) {
+	if ttl <= 0 || ttl > MaxJoinTokenTTL {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJoinTokenTTL, ttl)
+	}
+
+	token, err := bootstraptokenutil.GenerateBootstrapToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate join token: %w", err)
+	}
+
+	tokenID, tokenSecret, _ := strings.Cut(token, ".")
+	expiration := time.Now().Add(ttl).UTC().Truncate(time.Second)
+
+	_, err = secrets.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstraptokenutil.BootstrapTokenSecretName(tokenID),
+			Namespace: config.KommodityNamespace,
+			Labels: map[string]string{
+				config.ManagedByLabel: "kommodity",
+				joinTokenLabel:        "true",
+			},
+			Annotations: map[string]string{
+				JoinTokenMachineAnnotation: machine.String(),
+			},
+		},
+		Type: bootstrapapi.SecretTypeBootstrapToken,
+		Data: map[string][]byte{
+			bootstrapapi.BootstrapTokenIDKey:               []byte(tokenID),
+			bootstrapapi.BootstrapTokenSecretKey:           []byte(tokenSecret),
+			bootstrapapi.BootstrapTokenExpirationKey:       []byte(expiration.Format(time.RFC3339)),
+			bootstrapapi.BootstrapTokenUsageAuthentication: []byte("true"),
+			bootstrapapi.BootstrapTokenExtraGroupsKey:      []byte(JoinTokenGroup),
+			bootstrapapi.BootstrapTokenDescriptionKey:      []byte(description),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save join token for machine %s: %w", machine, err)
+	}
+
+	return &JoinToken{ID: tokenID, Machine: machine.String(), Expiration: expiration}, nil
+}
+
+// RevokeJoinToken deletes the Secret of the join token. Bootstrap tokens that were not
+// created as join tokens are left alone.
+func RevokeJoinToken(ctx context.Context, secrets v1.SecretInterface, tokenID string) error {
+	if !bootstraptokenutil.IsValidBootstrapTokenID(tokenID) {
+		return fmt.Errorf("%w: %s", ErrJoinTokenNotFound, tokenID)
+	}
+
+	name := bootstraptokenutil.BootstrapTokenSecretName(tokenID)
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || (err == nil && secret.Labels[joinTokenLabel] != "true") {
+		return fmt.Errorf("%w: %s", ErrJoinTokenNotFound, tokenID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get join token %s: %w", tokenID, err)
+	}
+
+	err = secrets.Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &secret.UID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke join token %s: %w", tokenID, err)
+	}
+
+	return nil
+}
+
+// GetMachineJoinToken returns the join token of the machine that stays valid the
+// longest, including its secret part.
+func GetMachineJoinToken(ctx context.Context, secrets v1.SecretInterface,
+	machine types.NamespacedName) (*JoinToken, error) {
+	list, err := secrets.List(ctx, metav1.ListOptions{LabelSelector: joinTokenLabel + "=true"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list join tokens: %w", err)
+	}
+
+	var joinToken *JoinToken
+
+	now := time.Now()
+
+	for _, secret := range list.Items {
+		if secret.Annotations[JoinTokenMachineAnnotation] != machine.String() ||
+			secret.DeletionTimestamp != nil || bootstrapsecretutil.HasExpired(&secret, now) {
+			continue
+		}
+
+		expiration, err := time.Parse(time.RFC3339,
+			bootstrapsecretutil.GetData(&secret, bootstrapapi.BootstrapTokenExpirationKey))
+		if err != nil || (joinToken != nil && !expiration.After(joinToken.Expiration)) {
+			continue
+		}
+
+		tokenID := bootstrapsecretutil.GetData(&secret, bootstrapapi.BootstrapTokenIDKey)
+
+		joinToken = &JoinToken{
+			ID:         tokenID,
+			Machine:    machine.String(),
+			Expiration: expiration,
+			Token: bootstraptokenutil.TokenFromIDAndSecret(tokenID,
+				bootstrapsecretutil.GetData(&secret, bootstrapapi.BootstrapTokenSecretKey)),
+		}
+	}
+
+	if joinToken == nil {
+		return nil, fmt.Errorf("%w: %s", ErrJoinTokenNotFound, machine)
+	}
+
+	return joinToken, nil
+}
@@ -0,0 +1,79 @@
+package rest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	restutils "github.com/kommodity-io/kommodity/pkg/attestation/rest"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+)
+
+func TestJoinTokens(t *testing.T) {
+	t.Parallel()
+
+	// A bootstrap token that was not created as a join token.
+	secrets := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-token-abcdef", Namespace: config.KommodityNamespace},
+		Type:       bootstrapapi.SecretTypeBootstrapToken,
+	}).CoreV1().Secrets(config.KommodityNamespace)
+
+	machine := types.NamespacedName{Namespace: "default", Name: "worker-0"}
+
+	_, err := restutils.CreateJoinToken(t.Context(), secrets, machine, 48*time.Hour, "")
+	if !errors.Is(err, restutils.ErrInvalidJoinTokenTTL) {
+		t.Fatalf("expected a TTL above the maximum to be rejected, got %v", err)
+	}
+
+	shortLived, err := restutils.CreateJoinToken(t.Context(), secrets, machine, time.Minute, "")
+	if err != nil {
+		t.Fatalf("failed to create join token: %v", err)
+	}
+
+	created, err := restutils.CreateJoinToken(t.Context(), secrets, machine, time.Hour, "worker-0")
+	if err != nil {
+		t.Fatalf("failed to create join token: %v", err)
+	}
+
+	if created.Token != "" {
+		t.Fatal("expected the secret part of a created join token not to be returned")
+	}
+
+	handedOut, err := restutils.GetMachineJoinToken(t.Context(), secrets, machine)
+	if err != nil {
+		t.Fatalf("failed to get join token: %v", err)
+	}
+
+	if handedOut.ID != created.ID || handedOut.Token == "" {
+		t.Fatalf("expected the longest valid join token %s to be handed out, got %+v", created.ID, handedOut)
+	}
+
+	otherMachine := types.NamespacedName{Namespace: "default", Name: "worker-1"}
+
+	_, err = restutils.GetMachineJoinToken(t.Context(), secrets, otherMachine)
+	if !errors.Is(err, restutils.ErrJoinTokenNotFound) {
+		t.Fatalf("expected no join token for another machine, got %v", err)
+	}
+
+	err = restutils.RevokeJoinToken(t.Context(), secrets, created.ID)
+	if err != nil {
+		t.Fatalf("failed to revoke join token: %v", err)
+	}
+
+	handedOut, err = restutils.GetMachineJoinToken(t.Context(), secrets, machine)
+	if err != nil || handedOut.ID != shortLived.ID {
+		t.Fatalf("expected the remaining join token %s to be handed out, got %+v (%v)", shortLived.ID, handedOut, err)
+	}
+
+	for _, tokenID := range []string{created.ID, "abcdef", "../default-token"} {
+		err = restutils.RevokeJoinToken(t.Context(), secrets, tokenID)
+		if !errors.Is(err, restutils.ErrJoinTokenNotFound) {
+			t.Fatalf("expected %q not to be revocable, got %v", tokenID, err)
+		}
+	}
+}
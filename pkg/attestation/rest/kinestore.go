@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kine"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// nonceKeyPrefix is the key prefix below which nonces are stored in kine, outside of
+// the key space of the API server.
+const nonceKeyPrefix = "/kommodity/attestation/nonces/"
+
+// KineNonceStore stores nonces in kine, so that a nonce issued by one instance can be
+// used with any other instance sharing the database. Nonces are attached to a lease of
+// their TTL, so kine deletes them once they expire.
+type KineNonceStore struct {
+	cli *clientv3.Client
+	ttl time.Duration
+}
+
+var _ NonceStore = &KineNonceStore{}
+
+type kineNonceRecord struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	IP        string    `json:"ip"`
+}
+
+// NewKineNonceStore creates a new KineNonceStore with the specified TTL for nonces. The
+// connection to kine is closed when the context is cancelled.
+func NewKineNonceStore(ctx context.Context, cfg *config.KommodityConfig) (*KineNonceStore, error) {
+	cli, err := kine.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nonce store: %w", err)
+	}
+
+	context.AfterFunc(ctx, func() { _ = cli.Close() })
+
+	return &KineNonceStore{cli: cli, ttl: cfg.AttestationConfig.NonceTTL}, nil
+}
+
+// Generate creates a new nonce, stores it with an expiration time, and returns it.
+func (s *KineNonceStore) Generate(ctx context.Context, ip string) (string, time.Time, error) {
+	canonical, err := canonicalIP(ip)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to canonicalize IP: %w", err)
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	exp := time.Now().Add(s.ttl)
+
+	value, err := json.Marshal(kineNonceRecord{ExpiresAt: exp, IP: canonical})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode nonce: %w", err)
+	}
+
+	// Leases are granted in whole seconds, the expiration time is checked on use.
+	lease, err := s.cli.Grant(ctx, int64(math.Ceil(s.ttl.Seconds())))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to grant nonce lease: %w", err)
+	}
+
+	// Kine only supports puts as part of a create transaction.
+	key := nonceKeyPrefix + nonce
+
+	response, err := s.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(value), clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store nonce: %w", err)
+	}
+
+	if !response.Succeeded {
+		return "", time.Time{}, fmt.Errorf("failed to store nonce: %w", ErrNonceExists)
+	}
+
+	return nonce, exp, nil
+}
+
+// Use validates and consumes a nonce. It returns true if the nonce is valid and not expired.
+// A nonce can only be used once, even if used concurrently with several instances.
+func (s *KineNonceStore) Use(ctx context.Context, ip, nonce string) (bool, error) {
+	canonical, err := canonicalIP(ip)
+	if err != nil {
+		return false, fmt.Errorf("failed to canonicalize IP: %w", err)
+	}
+
+	key := nonceKeyPrefix + nonce
+
+	existing, err := s.cli.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to read nonce: %w", err)
+	}
+
+	if len(existing.Kvs) == 0 {
+		return false, ErrInvalidNonce
+	}
+
+	var record kineNonceRecord
+
+	err = json.Unmarshal(existing.Kvs[0].Value, &record)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode nonce: %w", err)
+	}
+
+	if canonical != record.IP {
+		return false, ErrIPMismatch
+	}
+
+	// Kine only supports deletes as part of a transaction on the revision of the key,
+	// which fails if another instance used the nonce in the meantime.
+	response, err := s.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", existing.Kvs[0].ModRevision)).
+		Then(clientv3.OpDelete(key)).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume nonce: %w", err)
+	}
+
+	if !response.Succeeded {
+		return false, ErrInvalidNonce
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return false, ErrExpiredNonce
+	}
+
+	return true, nil
+}
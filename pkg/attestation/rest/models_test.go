@@ -0,0 +1,125 @@
+package rest_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/google/go-tpm/tpm2"
+	restutils "github.com/kommodity-io/kommodity/pkg/attestation/rest"
+)
+
+const (
+	testNonce = "884f2638c74645b859f87e76560748cc"
+	testPCR   = "3d458cfe55cc03ea1f443f1562beec8df51c75e14a9fcf9a7234a13f198e7969"
+)
+
+// newSignedReport returns a report over PCR 7 quoted with the given nonce and signed
+// by a fresh ECDSA key, like the report a booting machine submits.
+func newSignedReport(tb testing.TB, nonce string) restutils.Report {
+	tb.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatalf("failed to generate key: %v", err)
+	}
+
+	pcrValue, err := hex.DecodeString(testPCR)
+	if err != nil {
+		tb.Fatalf("failed to decode PCR: %v", err)
+	}
+
+	pcrDigest := sha256.Sum256(pcrValue)
+
+	quote := tpm2.Marshal(&tpm2.TPMSAttest{
+		Magic:     tpm2.TPMGeneratedValue,
+		Type:      tpm2.TPMSTAttestQuote,
+		ExtraData: tpm2.TPM2BData{Buffer: []byte(nonce)},
+		Attested: tpm2.NewTPMUAttest(tpm2.TPMSTAttestQuote, &tpm2.TPMSQuoteInfo{
+			PCRSelect: tpm2.TPMLPCRSelection{PCRSelections: []tpm2.TPMSPCRSelection{
+				{Hash: tpm2.TPMAlgSHA256, PCRSelect: []byte{0x80, 0x00, 0x00}},
+			}},
+			PCRDigest: tpm2.TPM2BDigest{Buffer: pcrDigest[:]},
+		}),
+	})
+
+	quoteDigest := sha256.Sum256(quote)
+
+	sigR, sigS, err := ecdsa.Sign(rand.Reader, key, quoteDigest[:])
+	if err != nil {
+		tb.Fatalf("failed to sign quote: %v", err)
+	}
+
+	signature := tpm2.Marshal(&tpm2.TPMTSignature{
+		SigAlg: tpm2.TPMAlgECDSA,
+		Signature: tpm2.NewTPMUSignature(tpm2.TPMAlgECDSA, &tpm2.TPMSSignatureECC{
+			Hash:       tpm2.TPMAlgSHA256,
+			SignatureR: tpm2.TPM2BECCParameter{Buffer: sigR.Bytes()},
+			SignatureS: tpm2.TPM2BECCParameter{Buffer: sigS.Bytes()},
+		}),
+	})
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		tb.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+	return restutils.Report{
+		Components:   []restutils.ComponentReport{{Name: "kernel", Measurement: "abc"}},
+		PCRs:         map[int]string{7: testPCR},
+		Quote:        hex.EncodeToString(quote),
+		Signature:    hex.EncodeToString(signature),
+		TPMPublicKey: hex.EncodeToString(publicKeyPEM),
+	}
+}
+
+func TestReportCompliantWith(t *testing.T) {
+	t.Parallel()
+
+	report := newSignedReport(t, testNonce)
+
+	compliant, err := report.CompliantWith(testNonce, &restutils.Report{
+		Components: report.Components,
+		PCRs:       report.PCRs,
+	})
+	if err != nil || !compliant {
+		t.Fatalf("expected report to be compliant, got %t: %v", compliant, err)
+	}
+
+	_, err = report.CompliantWith("another nonce", &restutils.Report{})
+	if err == nil {
+		t.Fatalf("expected a report quoted with another nonce to be rejected")
+	}
+}
+
+// FuzzReportCompliantWith feeds reports submitted by untrusted machines through the
+// TPM quote, signature and PCR parsers, which must reject malformed input with an
+// error instead of panicking.
+func FuzzReportCompliantWith(f *testing.F) {
+	seed := newSignedReport(f, testNonce)
+
+	f.Add(seed.Quote, seed.Signature, seed.TPMPublicKey, testPCR, testNonce)
+	f.Add("", "", "", "", "")
+	f.Add("00", "0x", "zz", "0x"+testPCR, testNonce)
+
+	f.Fuzz(func(t *testing.T, quote, signature, publicKey, pcr, nonce string) {
+		report := restutils.Report{
+			PCRs:         map[int]string{7: pcr},
+			Quote:        quote,
+			Signature:    signature,
+			TPMPublicKey: publicKey,
+		}
+
+		compliant, err := report.CompliantWith(nonce, &restutils.Report{PCRs: map[int]string{7: testPCR}})
+		if compliant && err != nil {
+			t.Fatalf("report reported as compliant with error: %v", err)
+		}
+	})
+}
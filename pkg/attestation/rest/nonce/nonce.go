@@ -29,7 +29,7 @@ type NonceResponse struct {
 // @Router   /nonce [get]
 //
 // GetNonce handles the GET /nonce endpoint.
-func GetNonce(nonceStore *restutils.NonceStore,
+func GetNonce(nonceStore restutils.NonceStore,
 	rateLimiter *net.RateLimiter) func(http.ResponseWriter, *http.Request) {
 	return func(response http.ResponseWriter, request *http.Request) {
 		//nolint:varnamelen // Variable name ip is appropriate for the context.
@@ -52,7 +52,7 @@ func GetNonce(nonceStore *restutils.NonceStore,
 			return
 		}
 
-		nonce, ttl, err := nonceStore.Generate(ip)
+		nonce, ttl, err := nonceStore.Generate(request.Context(), ip)
 		if err != nil {
 			http.Error(response, "Failed to generate nonce", http.StatusInternalServerError)
 
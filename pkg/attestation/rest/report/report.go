@@ -43,7 +43,7 @@ type NodeInfo struct {
 // @Router   /report [post]
 //
 // PostReport handles the POST /report endpoint.
-func PostReport(nonceStore *restutils.NonceStore,
+func PostReport(nonceStore restutils.NonceStore,
 	cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
 	return func(response http.ResponseWriter, request *http.Request) {
 		if request.Method != http.MethodPost {
@@ -69,7 +69,7 @@ func PostReport(nonceStore *restutils.NonceStore,
 			return
 		}
 
-		valid, err := nonceStore.Use(ip, req.Nonce)
+		valid, err := nonceStore.Use(request.Context(), ip, req.Nonce)
 		if err != nil {
 			http.Error(response, err.Error(), http.StatusBadRequest)
 
@@ -0,0 +1,37 @@
+package report_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	restutils "github.com/kommodity-io/kommodity/pkg/attestation/rest"
+	"github.com/kommodity-io/kommodity/pkg/attestation/rest/report"
+)
+
+// FuzzPostReport submits arbitrary bodies from arbitrary addresses. Without a nonce
+// issued to the sender, every request must be rejected as a client error before the
+// report is parsed any further or stored.
+func FuzzPostReport(f *testing.F) {
+	f.Add(`{"nonce":"884f2638c74645b859f87e76560748cc","node":{"uuid":"a","ip":"10.0.0.1"},`+
+		`"report":{"pcrs":{"7":"00"},"quote":"00","signature":"00","tpmPublicKey":"00"}}`, "10.0.0.1")
+	f.Add(`{"report":{"pcrs":{"not a number":"00"}}}`, "")
+	f.Add(`[]`, "10.0.0.1/../x")
+	f.Add(``, "fe80::1%eth0")
+
+	handler := report.PostReport(restutils.NewMemoryNonceStore(f.Context(), time.Minute), nil)
+
+	f.Fuzz(func(t *testing.T, body, forwardedFor string) {
+		request := httptest.NewRequest(http.MethodPost, "/report", strings.NewReader(body))
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+
+		if recorder.Code != http.StatusBadRequest && recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("expected the report to be rejected, got status %d", recorder.Code)
+		}
+	})
+}
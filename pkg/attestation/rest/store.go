@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -14,21 +15,34 @@ const (
 	nonceSize = 32 // 256-bit
 )
 
-// NonceStore is a thread-safe store for nonces with expiration.
-type NonceStore struct {
+// NonceStore issues single use nonces bound to the IP address of a machine.
+type NonceStore interface {
+	// Generate creates a new nonce for the IP and returns it with its expiration time.
+	Generate(ctx context.Context, ip string) (string, time.Time, error)
+	// Use validates and consumes a nonce. It returns true if the nonce is valid and not
+	// expired.
+	Use(ctx context.Context, ip, nonce string) (bool, error)
+}
+
+// MemoryNonceStore is a thread-safe store for nonces with expiration. Nonces are only
+// known to the instance that issued them.
+type MemoryNonceStore struct {
 	mu   sync.Mutex
 	ttl  time.Duration
 	data map[string]nonceRecord
 }
 
+var _ NonceStore = &MemoryNonceStore{}
+
 type nonceRecord struct {
 	expiresAt time.Time
 	ip        string
 }
 
-// NewNonceStore creates a new NonceStore with the specified TTL for nonces.
-func NewNonceStore(ttl time.Duration) *NonceStore {
-	store := &NonceStore{
+// NewMemoryNonceStore creates a new MemoryNonceStore with the specified TTL for nonces.
+// Expired nonces are reaped in the background until the context is cancelled.
+func NewMemoryNonceStore(ctx context.Context, ttl time.Duration) *MemoryNonceStore {
+	store := &MemoryNonceStore{
 		ttl:  ttl,
 		data: make(map[string]nonceRecord),
 	}
@@ -37,8 +51,14 @@ func NewNonceStore(ttl time.Duration) *NonceStore {
 		t := time.NewTicker(time.Minute)
 		defer t.Stop()
 
-		for range t.C {
-			now := time.Now()
+		for {
+			var now time.Time
+
+			select {
+			case <-ctx.Done():
+				return
+			case now = <-t.C:
+			}
 
 			store.mu.Lock()
 
@@ -56,20 +76,17 @@ func NewNonceStore(ttl time.Duration) *NonceStore {
 }
 
 // Generate creates a new nonce, stores it with an expiration time, and returns it.
-func (s *NonceStore) Generate(ip string) (string, time.Time, error) {
+func (s *MemoryNonceStore) Generate(_ context.Context, ip string) (string, time.Time, error) {
 	canonical, err := canonicalIP(ip)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to canonicalize IP: %w", err)
 	}
 
-	reservation := make([]byte, nonceSize)
-
-	_, err = rand.Read(reservation)
+	nonce, err := newNonce()
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to generate nonce: %w", err)
+		return "", time.Time{}, err
 	}
 
-	nonce := hex.EncodeToString(reservation)
 	exp := time.Now().Add(s.ttl)
 
 	s.mu.Lock()
@@ -85,7 +102,7 @@ func (s *NonceStore) Generate(ip string) (string, time.Time, error) {
 // Use validates and consumes a nonce. It returns true if the nonce is valid and not expired.
 // The provided IP is canonicalized (port stripped, address normalized) before
 // comparison so callers can pass either a raw address or a host:port pair.
-func (s *NonceStore) Use(ip, nonce string) (bool, error) {
+func (s *MemoryNonceStore) Use(_ context.Context, ip, nonce string) (bool, error) {
 	canonical, err := canonicalIP(ip)
 	if err != nil {
 		return false, fmt.Errorf("failed to canonicalize IP: %w", err)
@@ -117,6 +134,18 @@ func (s *NonceStore) Use(ip, nonce string) (bool, error) {
 	return true, nil
 }
 
+// newNonce returns a random hex encoded nonce.
+func newNonce() (string, error) {
+	reservation := make([]byte, nonceSize)
+
+	_, err := rand.Read(reservation)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return hex.EncodeToString(reservation), nil
+}
+
 func canonicalIP(hostport string) (string, error) {
 	// Strip optional port if present.
 	host, _, err := net.SplitHostPort(hostport)
@@ -0,0 +1,67 @@
+package rest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	restutils "github.com/kommodity-io/kommodity/pkg/attestation/rest"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+)
+
+// testNonceStore issues a nonce with one store and uses it with the other.
+func testNonceStore(t *testing.T, issuer, user restutils.NonceStore) {
+	t.Helper()
+
+	nonce, _, err := issuer.Generate(t.Context(), "10.0.0.1:4242")
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+
+	_, err = user.Use(t.Context(), "10.0.0.2", nonce)
+	if !errors.Is(err, restutils.ErrIPMismatch) {
+		t.Fatalf("expected nonce used from another IP to be rejected, got %v", err)
+	}
+
+	used, err := user.Use(t.Context(), "10.0.0.1", nonce)
+	if err != nil || !used {
+		t.Fatalf("expected nonce to be usable once, got %t: %v", used, err)
+	}
+
+	_, err = issuer.Use(t.Context(), "10.0.0.1", nonce)
+	if !errors.Is(err, restutils.ErrInvalidNonce) {
+		t.Fatalf("expected nonce to be single use, got %v", err)
+	}
+}
+
+func TestNonceStore(t *testing.T) {
+	t.Parallel()
+
+	store := restutils.NewMemoryNonceStore(t.Context(), time.Minute)
+
+	testNonceStore(t, store, store)
+}
+
+// TestKineNonceStore asserts that a nonce issued by one instance can be used once with
+// another instance sharing kine.
+func TestKineNonceStore(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.KommodityConfig{
+		KineURI:           storagetest.StartKine(t),
+		AttestationConfig: &config.AttestationConfig{NonceTTL: time.Minute},
+	}
+
+	issuer, err := restutils.NewKineNonceStore(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create nonce store: %v", err)
+	}
+
+	user, err := restutils.NewKineNonceStore(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create nonce store: %v", err)
+	}
+
+	testNonceStore(t, issuer, user)
+}
@@ -0,0 +1,239 @@
+// Package token provides the handlers for the join token endpoints.
+package token
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	restutils "github.com/kommodity-io/kommodity/pkg/attestation/rest"
+	"github.com/kommodity-io/kommodity/pkg/attestation/rest/trust"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/net"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoclientset "k8s.io/client-go/kubernetes"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrlclint "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxJoinTokenRequestSize bounds the body of join token creation requests.
+const maxJoinTokenRequestSize = 1 << 12
+
+// JoinTokenRequest represents the request structure for the join token creation endpoint.
+type JoinTokenRequest struct {
+	Namespace   string `example:"default"  json:"namespace"`
+	Machine     string `example:"worker-0" json:"machine"`
+	TTL         string `example:"1h"       json:"ttl"`
+	Description string `json:"description"`
+}
+
+// GetToken godoc
+// @Summary  Obtain the join token of the machine
+// @Tags     Attestation
+// @Produce  json
+// @Success  200  {object}  rest.JoinToken
+// @Failure  401  {object}  string  "If the machine presents no verified client certificate or is not trusted"
+// @Failure  404  {object}  string  "If the machine or its join token is not found"
+// @Failure  500  {object}  string  "If there is a server error"
+// @Router   /token [get]
+//
+// GetToken hands the join token out to the machine sending the request, once its
+// attestation report complies with the policy of its cluster. The machine is identified
+// by its verified client certificate only, as X-Forwarded-For can be forged by anyone.
+//
+//nolint:funlen // Complexity is only apparent due to multiple error checks.
+func GetToken(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		//nolint:varnamelen // Variable name ip is appropriate for the context.
+		ip, found := net.ClientCertificateIP(request)
+		if !found {
+			http.Error(response, "A verified client certificate is required", http.StatusUnauthorized)
+
+			return
+		}
+
+		kubeClient, err := clientgoclientset.NewForConfig(cfg.ClientConfig.LoopbackClientConfig)
+		if err != nil {
+			http.Error(response, "Failed to create kube client", http.StatusInternalServerError)
+
+			return
+		}
+
+		ctrlClient, err := ctrlclint.New(cfg.ClientConfig.LoopbackClientConfig, ctrlclint.Options{})
+		if err != nil {
+			http.Error(response, "Failed to create controller client", http.StatusInternalServerError)
+
+			return
+		}
+
+		machine, err := net.FindManagedMachineByIP(request.Context(), &ctrlClient, ip)
+		if err != nil {
+			if errors.Is(err, net.ErrNoMachineFound) {
+				http.Error(response, "Machine not found", http.StatusNotFound)
+			} else {
+				http.Error(response, "Failed to find machine by IP", http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		trusted, err := trust.IsTrusted(request.Context(), kubeClient, machine)
+		if err != nil {
+			http.Error(response, "Failed to evaluate trust: "+err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		if !trusted {
+			http.Error(response, "Machine is not trusted", http.StatusUnauthorized)
+
+			return
+		}
+
+		joinToken, err := restutils.GetMachineJoinToken(request.Context(),
+			restutils.GetSecretAPI(kubeClient), types.NamespacedName{Namespace: machine.Namespace, Name: machine.Name})
+		if err != nil {
+			if errors.Is(err, restutils.ErrJoinTokenNotFound) {
+				http.Error(response, "No join token for the machine", http.StatusNotFound)
+			} else {
+				http.Error(response, "Failed to get join token", http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		writeJoinToken(response, http.StatusOK, joinToken)
+	}
+}
+
+// PostTokens godoc
+// @Summary  Create a join token for a machine
+// @Tags     Attestation
+// @Accept   json
+// @Produce  json
+// @Param    payload  body  JoinTokenRequest  true  "Join token"
+// @Success  201  {object}  rest.JoinToken
+// @Failure  400  {object}  string  "If the request is invalid"
+// @Failure  403  {object}  string  "If the request is not authenticated as an admin"
+// @Failure  404  {object}  string  "If the machine is not found"
+// @Failure  500  {object}  string  "If there is a server error"
+// @Router   /tokens [post]
+//
+// PostTokens creates a join token for a machine. The token is only handed out to the
+// machine itself, after attestation. The handler expects to be served to admins only.
+//
+//nolint:funlen,cyclop // Complexity is only apparent due to multiple error checks.
+func PostTokens(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		var req JoinTokenRequest
+
+		err := json.NewDecoder(http.MaxBytesReader(response, request.Body, maxJoinTokenRequestSize)).Decode(&req)
+		if err != nil || req.Machine == "" {
+			http.Error(response, "Failed to decode request", http.StatusBadRequest)
+
+			return
+		}
+
+		if req.Namespace == "" {
+			req.Namespace = metav1.NamespaceDefault
+		}
+
+		ttl := restutils.DefaultJoinTokenTTL
+		if req.TTL != "" {
+			ttl, err = time.ParseDuration(req.TTL)
+			if err != nil {
+				http.Error(response, "Invalid TTL: "+err.Error(), http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		kubeClient, err := clientgoclientset.NewForConfig(cfg.ClientConfig.LoopbackClientConfig)
+		if err != nil {
+			http.Error(response, "Failed to create kube client", http.StatusInternalServerError)
+
+			return
+		}
+
+		ctrlClient, err := ctrlclint.New(cfg.ClientConfig.LoopbackClientConfig, ctrlclint.Options{})
+		if err != nil {
+			http.Error(response, "Failed to create controller client", http.StatusInternalServerError)
+
+			return
+		}
+
+		machineName := types.NamespacedName{Namespace: req.Namespace, Name: req.Machine}
+
+		err = ctrlClient.Get(request.Context(), machineName, &clusterv1.Machine{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				http.Error(response, "Machine not found", http.StatusNotFound)
+			} else {
+				http.Error(response, "Failed to get machine", http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		joinToken, err := restutils.CreateJoinToken(request.Context(),
+			restutils.GetSecretAPI(kubeClient), machineName, ttl, req.Description)
+		if err != nil {
+			if errors.Is(err, restutils.ErrInvalidJoinTokenTTL) {
+				http.Error(response, err.Error(), http.StatusBadRequest)
+			} else {
+				http.Error(response, "Failed to create join token", http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		writeJoinToken(response, http.StatusCreated, joinToken)
+	}
+}
+
+// DeleteToken godoc
+// @Summary  Revoke a join token
+// @Tags     Attestation
+// @Param    id   path  string  true  "Token ID"
+// @Success  204  {string}  string  "No content"
+// @Failure  403  {object}  string  "If the request is not authenticated as an admin"
+// @Failure  404  {object}  string  "If the join token is not found"
+// @Failure  500  {object}  string  "If there is a server error"
+// @Router   /tokens/{id} [delete]
+//
+// DeleteToken revokes a join token, machines can no longer authenticate with it. The
+// handler expects to be served to admins only.
+func DeleteToken(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		kubeClient, err := clientgoclientset.NewForConfig(cfg.ClientConfig.LoopbackClientConfig)
+		if err != nil {
+			http.Error(response, "Failed to create kube client", http.StatusInternalServerError)
+
+			return
+		}
+
+		err = restutils.RevokeJoinToken(request.Context(), restutils.GetSecretAPI(kubeClient), request.PathValue("id"))
+		if err != nil {
+			if errors.Is(err, restutils.ErrJoinTokenNotFound) {
+				http.Error(response, "Join token not found", http.StatusNotFound)
+			} else {
+				http.Error(response, "Failed to revoke join token", http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		response.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func writeJoinToken(response http.ResponseWriter, status int, joinToken *restutils.JoinToken) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(status)
+
+	//nolint:errchkjson // The response is already committed, nothing to do on failure.
+	_ = json.NewEncoder(response).Encode(joinToken)
+}
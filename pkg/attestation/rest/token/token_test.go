@@ -0,0 +1,43 @@
+package token_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/attestation/rest/token"
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+func TestGetTokenRequiresClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.KommodityConfig{ClientConfig: &config.ClientConfig{}}
+
+	request := httptest.NewRequest(http.MethodGet, "/token", nil)
+	request.Header.Set("X-Forwarded-For", "10.0.0.5")
+
+	recorder := httptest.NewRecorder()
+	token.GetToken(cfg)(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected a forged X-Forwarded-For to be unauthorized, got %d", recorder.Code)
+	}
+}
+
+func TestPostTokensLimitsRequestBody(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.KommodityConfig{ClientConfig: &config.ClientConfig{}}
+
+	body := `{"machine":"worker-0","description":"` + strings.Repeat("x", 1<<13) + `"}`
+	request := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(body))
+
+	recorder := httptest.NewRecorder()
+	token.PostTokens(cfg)(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected an oversized request to be rejected, got %d", recorder.Code)
+	}
+}
@@ -78,23 +78,9 @@ func GetTrust(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Reque
 			return
 		}
 
-		policy, err := getAttestationPolicy(request.Context(), kubeClient, machine.Spec.ClusterName)
+		compliant, err := IsTrusted(request.Context(), kubeClient, machine)
 		if err != nil {
-			http.Error(response, "Failed to get attestation policy: "+err.Error(), http.StatusInternalServerError)
-
-			return
-		}
-
-		report, nonce, err := getMachineAttestationReport(request.Context(), kubeClient, machine)
-		if err != nil {
-			http.Error(response, "Failed to get attestation report: "+err.Error(), http.StatusInternalServerError)
-
-			return
-		}
-
-		compliant, err := report.CompliantWith(nonce, policy)
-		if err != nil {
-			http.Error(response, "Failed to evaluate attestation report: "+err.Error(), http.StatusInternalServerError)
+			http.Error(response, "Failed to evaluate trust: "+err.Error(), http.StatusInternalServerError)
 
 			return
 		}
@@ -109,6 +95,27 @@ func GetTrust(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Reque
 	}
 }
 
+// IsTrusted reports whether the last attestation report of the machine complies with
+// the attestation policy of its cluster.
+func IsTrusted(ctx context.Context, kubeClient *clientgoclientset.Clientset, machine *clusterv1.Machine) (bool, error) {
+	policy, err := getAttestationPolicy(ctx, kubeClient, machine.Spec.ClusterName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get attestation policy: %w", err)
+	}
+
+	report, nonce, err := getMachineAttestationReport(ctx, kubeClient, machine)
+	if err != nil {
+		return false, fmt.Errorf("failed to get attestation report: %w", err)
+	}
+
+	compliant, err := report.CompliantWith(nonce, policy)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate attestation report: %w", err)
+	}
+
+	return compliant, nil
+}
+
 func getMachineAttestationReport(ctx context.Context,
 	kubeClient *clientgoclientset.Clientset,
 	machine *clusterv1.Machine) (*restutils.Report, string, error) {
@@ -1,14 +1,18 @@
 package attestation
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
 	restutils "github.com/kommodity-io/kommodity/pkg/attestation/rest"
 	restnonce "github.com/kommodity-io/kommodity/pkg/attestation/rest/nonce"
 	restreport "github.com/kommodity-io/kommodity/pkg/attestation/rest/report"
+	resttoken "github.com/kommodity-io/kommodity/pkg/attestation/rest/token"
 	resttrust "github.com/kommodity-io/kommodity/pkg/attestation/rest/trust"
 	"github.com/kommodity-io/kommodity/pkg/combinedserver"
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
 	"github.com/kommodity-io/kommodity/pkg/net"
 )
 
@@ -21,18 +25,59 @@ const (
 
 	// AttestationTrustEndpoint is the endpoint for checking trust status based on an attestation report.
 	AttestationTrustEndpoint = "/report/{ip}/trust"
+
+	// AttestationTokenEndpoint is the endpoint for obtaining the join token of a trusted machine.
+	AttestationTokenEndpoint = "/token"
+
+	// AttestationTokensEndpoint is the endpoint for creating join tokens.
+	AttestationTokensEndpoint = "/tokens"
+
+	// AttestationTokenRevokeEndpoint is the endpoint for revoking a join token.
+	AttestationTokenRevokeEndpoint = "/tokens/{id}"
 )
 
-// NewHTTPMuxFactory creates a new HTTP mux factory for the attestation server.
-func NewHTTPMuxFactory(cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
+// NewHTTPMuxFactory creates a new HTTP mux factory for the attestation server. The
+// nonce store is closed when the context is cancelled. When HTTP
+// authentication is enabled, endpoints that are not exempt require a bearer token.
+// Join tokens can only be managed by admins, with HTTP authentication enabled.
+func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
 	return func(mux *http.ServeMux) error {
+		authenticate, err := httpauth.NewMiddleware(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to set up attestation authentication: %w", err)
+		}
+
 		rateLimiter := net.NewRateLimiter()
-		nonceStore := restutils.NewNonceStore(cfg.AttestationConfig.NonceTTL)
+		nonceStore, err := newNonceStore(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		mux.HandleFunc("GET "+AttestationNonceEndpoint, authenticate(restnonce.GetNonce(nonceStore, rateLimiter)))
+		mux.HandleFunc("POST "+AttestationReportEndpoint, authenticate(restreport.PostReport(nonceStore, cfg)))
+		mux.HandleFunc("GET "+AttestationTrustEndpoint, authenticate(resttrust.GetTrust(cfg)))
+		mux.HandleFunc("GET "+AttestationTokenEndpoint, authenticate(resttoken.GetToken(cfg)))
+
+		requireAdmin := httpauth.RequireGroup(cfg.AuthConfig.AdminGroup)
 
-		mux.HandleFunc("GET "+AttestationNonceEndpoint, restnonce.GetNonce(nonceStore, rateLimiter))
-		mux.HandleFunc("POST "+AttestationReportEndpoint, restreport.PostReport(nonceStore, cfg))
-		mux.HandleFunc("GET "+AttestationTrustEndpoint, resttrust.GetTrust(cfg))
+		mux.HandleFunc("POST "+AttestationTokensEndpoint, authenticate(requireAdmin(resttoken.PostTokens(cfg))))
+		mux.HandleFunc("DELETE "+AttestationTokenRevokeEndpoint,
+			authenticate(requireAdmin(resttoken.DeleteToken(cfg))))
 
 		return nil
 	}
 }
+
+// newNonceStore creates the configured nonce store.
+func newNonceStore(ctx context.Context, cfg *config.KommodityConfig) (restutils.NonceStore, error) {
+	if cfg.AttestationConfig.NonceStore != config.NonceStoreDatabase {
+		return restutils.NewMemoryNonceStore(ctx, cfg.AttestationConfig.NonceTTL), nil
+	}
+
+	store, err := restutils.NewKineNonceStore(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up attestation nonce store: %w", err)
+	}
+
+	return store, nil
+}
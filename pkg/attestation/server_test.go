@@ -0,0 +1,42 @@
+package attestation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/attestation"
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+func TestJoinTokensRequireAdmin(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.KommodityConfig{
+		AuthConfig:        &config.AuthConfig{AdminGroup: "admins"},
+		AttestationConfig: &config.AttestationConfig{NonceTTL: time.Minute},
+		ClientConfig:      &config.ClientConfig{},
+	}
+
+	mux := http.NewServeMux()
+
+	err := attestation.NewHTTPMuxFactory(t.Context(), cfg)(mux)
+	if err != nil {
+		t.Fatalf("failed to register the attestation endpoints: %v", err)
+	}
+
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, attestation.AttestationTokensEndpoint,
+			strings.NewReader(`{"machine":"worker-0"}`)),
+		httptest.NewRequest(http.MethodDelete, "/tokens/abcdef", nil),
+	} {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("expected %s %s to be forbidden, got %d", request.Method, request.URL.Path, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package sigv4 signs requests to AWS APIs, and to S3-compatible stores, with AWS
+// Signature Version 4.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvAccessKeyID is the standard environment variable holding the access key ID.
+	EnvAccessKeyID = "AWS_ACCESS_KEY_ID"
+	// EnvSecretAccessKey is the standard environment variable holding the secret access key.
+	EnvSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	// EnvSessionToken is the standard environment variable holding the session token of
+	// temporary credentials.
+	EnvSessionToken = "AWS_SESSION_TOKEN"
+
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	dateFormat       = "20060102"
+)
+
+// Credentials are the credentials requests are signed with.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is only set for temporary credentials.
+	SessionToken string
+}
+
+// CredentialsFromEnv reads the credentials from the standard AWS_* environment variables.
+// Callers read them on every request so that rotated session credentials are picked up.
+func CredentialsFromEnv() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv(EnvAccessKeyID),
+		SecretAccessKey: os.Getenv(EnvSecretAccessKey),
+		SessionToken:    os.Getenv(EnvSessionToken),
+	}
+}
+
+// PayloadHash returns the hex encoded SHA-256 hash of the body, as S3 expects it in the
+// X-Amz-Content-Sha256 header.
+func PayloadHash(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Sign adds the X-Amz-Date, X-Amz-Security-Token and Authorization headers to the
+// request for the service in the region. The host and all headers set on the request
+// are signed, so headers must not be changed afterwards. The path is signed as escaped
+// in the URL, as S3 expects it.
+func Sign(req *http.Request, body []byte, credentials Credentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	date := now.Format(dateFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := canonicalHeaders(req)
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		PayloadHash(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		PayloadHash([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalHeaders returns the canonical headers of the request and the list of their
+// names, which are the host and the headers set on the request sorted by name.
+func canonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": req.URL.Host}
+
+	for name, value := range req.Header {
+		values[strings.ToLower(name)] = strings.Join(strings.Fields(strings.Join(value, ",")), " ")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonical strings.Builder
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// canonicalQuery returns the query parameters of the request sorted by name and value,
+// encoded as SigV4 requires.
+func canonicalQuery(req *http.Request) string {
+	encoded := map[string][]string{}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			encoded[uriEncode(name)] = append(encoded[uriEncode(name)], uriEncode(value))
+		}
+	}
+
+	names := make([]string, 0, len(encoded))
+	for name := range encoded {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parameters := make([]string, 0, len(names))
+
+	for _, name := range names {
+		sort.Strings(encoded[name])
+
+		for _, value := range encoded[name] {
+			parameters = append(parameters, name+"="+value)
+		}
+	}
+
+	return strings.Join(parameters, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters of RFC 3986.
+func uriEncode(value string) string {
+	var encoded strings.Builder
+
+	for _, char := range []byte(value) {
+		switch {
+		case char >= 'A' && char <= 'Z', char >= 'a' && char <= 'z', char >= '0' && char <= '9',
+			char == '-', char == '_', char == '.', char == '~':
+			encoded.WriteByte(char)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", char)
+		}
+	}
+
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,93 @@
+package sigv4_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/aws/sigv4"
+)
+
+// TestSign checks the signer against requests of the AWS Signature Version 4 test suite.
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	credentials := sigv4.Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, time.August, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		method        string
+		url           string
+		expectedAuthz string
+	}{
+		"get-vanilla": {
+			method: http.MethodGet,
+			url:    "https://example.amazonaws.com/",
+			expectedAuthz: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		"get-vanilla-query-order-key-case": {
+			method: http.MethodGet,
+			url:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			expectedAuthz: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		"post-vanilla": {
+			method: http.MethodPost,
+			url:    "https://example.amazonaws.com/",
+			expectedAuthz: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), test.method, test.url, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			sigv4.Sign(req, nil, credentials, "us-east-1", "service", now)
+
+			authz := req.Header.Get("Authorization")
+			if authz != test.expectedAuthz {
+				t.Fatalf("expected Authorization %q, got %q", test.expectedAuthz, authz)
+			}
+		})
+	}
+}
+
+func TestSignSessionToken(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://kms.eu-west-1.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+
+	sigv4.Sign(req, []byte("{}"), sigv4.Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+	}, "eu-west-1", "kms", time.Now())
+
+	if req.Header.Get("X-Amz-Security-Token") != "session" {
+		t.Fatalf("expected the session token to be sent")
+	}
+
+	const expectedHeaders = "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,"
+	if !strings.Contains(req.Header.Get("Authorization"), expectedHeaders) {
+		t.Fatalf("expected %s in Authorization %q", expectedHeaders, req.Header.Get("Authorization"))
+	}
+}
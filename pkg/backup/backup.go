@@ -0,0 +1,169 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kine"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// s3Timeout bounds a single upload or download of a snapshot.
+	s3Timeout = 5 * time.Minute
+
+	snapshotNameFormat = "20060102T150405Z"
+)
+
+//nolint:gochecknoglobals // Metrics are registered once in the process wide legacy registry.
+var (
+	backupLastSuccess = metrics.NewGauge(&metrics.GaugeOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "backup",
+		Name:           "last_success_timestamp_seconds",
+		Help:           "Unix time of the last snapshot that was uploaded successfully.",
+		StabilityLevel: metrics.ALPHA,
+	})
+
+	registerBackupMetricsOnce sync.Once
+)
+
+func registerBackupMetrics() {
+	registerBackupMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(backupLastSuccess)
+	})
+}
+
+// Snapshot describes a snapshot uploaded to the object store.
+type Snapshot struct {
+	Name     string `json:"name"`
+	Revision int64  `json:"revision"`
+	Keys     int    `json:"keys"`
+}
+
+// Manager takes snapshots of kine and restores them.
+type Manager struct {
+	cfg   *config.KommodityConfig
+	store *s3Store
+	now   func() time.Time
+}
+
+// NewManager creates a manager for the configured bucket.
+func NewManager(cfg *config.KommodityConfig) *Manager {
+	registerBackupMetrics()
+
+	return &Manager{
+		cfg:   cfg,
+		store: newS3Store(&http.Client{Timeout: s3Timeout}, cfg.BackupConfig),
+		now:   time.Now,
+	}
+}
+
+// Backup exports the key space of kine and uploads it as a new snapshot.
+func (m *Manager) Backup(ctx context.Context) (*Snapshot, error) {
+	if !m.Enabled() {
+		return nil, ErrBackupsDisabled
+	}
+
+	cli, err := kine.NewClient(m.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	var buffer bytes.Buffer
+
+	revision, keys, err := Export(ctx, cli, &buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "snapshot-" + m.now().UTC().Format(snapshotNameFormat) + ".jsonl.gz"
+
+	err = m.store.Put(ctx, m.cfg.BackupConfig.Prefix+name, buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload snapshot %s: %w", name, err)
+	}
+
+	backupLastSuccess.SetToCurrentTime()
+
+	return &Snapshot{Name: name, Revision: revision, Keys: keys}, nil
+}
+
+// PutObject uploads an object below the configured prefix. It is used for backups that
+// are not snapshots of kine, like the etcd snapshots of workload clusters.
+func (m *Manager) PutObject(ctx context.Context, name string, body []byte) error {
+	if !m.Enabled() {
+		return ErrBackupsDisabled
+	}
+
+	err := m.store.Put(ctx, m.cfg.BackupConfig.Prefix+name, body)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Restore downloads the named snapshot and creates its keys in kine. It returns
+// ErrRegistryNotEmpty if kine already holds objects.
+func (m *Manager) Restore(ctx context.Context, name string) (int, error) {
+	if !m.Enabled() {
+		return 0, ErrBackupsDisabled
+	}
+
+	data, err := m.store.Get(ctx, m.cfg.BackupConfig.Prefix+name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download snapshot %s: %w", name, err)
+	}
+
+	cli, err := kine.NewClient(m.cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	return Import(ctx, cli, bytes.NewReader(data))
+}
+
+// Run takes a snapshot every configured interval until the context is cancelled.
+// Failed snapshots are logged and retried at the next interval.
+func (m *Manager) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	ticker := time.NewTicker(m.cfg.BackupConfig.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, err := m.Backup(ctx)
+		if err != nil {
+			logger.Error("Failed to take snapshot", zap.Error(err))
+
+			continue
+		}
+
+		logger.Info("Took snapshot", zap.String("name", snapshot.Name),
+			zap.Int64("revision", snapshot.Revision), zap.Int("keys", snapshot.Keys))
+	}
+}
+
+// Enabled reports whether a bucket is configured.
+func (m *Manager) Enabled() bool {
+	return m.cfg.BackupConfig != nil && m.cfg.BackupConfig.Bucket != ""
+}
@@ -0,0 +1,302 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/backup"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kine"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const testBucket = "backups"
+
+// newObjectStore serves a single bucket from memory, rejecting requests that are not
+// signed or whose payload hash does not match the body.
+func newObjectStore(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var (
+		lock    sync.Mutex
+		objects = map[string][]byte{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		if request.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) ||
+			!strings.HasPrefix(request.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access-key/") {
+			http.Error(response, "SignatureDoesNotMatch", http.StatusForbidden)
+
+			return
+		}
+
+		name, found := strings.CutPrefix(request.URL.Path, "/"+testBucket+"/")
+		if !found {
+			http.Error(response, "NoSuchBucket", http.StatusNotFound)
+
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch request.Method {
+		case http.MethodPut:
+			objects[name] = body
+		case http.MethodGet:
+			object, found := objects[name]
+			if !found {
+				http.Error(response, "NoSuchKey", http.StatusNotFound)
+
+				return
+			}
+
+			_, _ = response.Write(object)
+		default:
+			http.Error(response, "MethodNotAllowed", http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newKineClient(t *testing.T) *clientv3.Client {
+	t.Helper()
+
+	cli, err := kine.NewClient(&config.KommodityConfig{KineURI: storagetest.StartKine(t)})
+	if err != nil {
+		t.Fatalf("failed to connect to kine: %v", err)
+	}
+
+	t.Cleanup(func() { _ = cli.Close() })
+
+	return cli
+}
+
+func newManager(t *testing.T, endpoint string) (*backup.Manager, *clientv3.Client) {
+	t.Helper()
+
+	cfg := &config.KommodityConfig{
+		KineURI: storagetest.StartKine(t),
+		BackupConfig: &config.BackupConfig{
+			Endpoint:        endpoint,
+			Region:          "us-east-1",
+			Bucket:          testBucket,
+			Prefix:          "kommodity/",
+			AccessKeyID:     "access-key",
+			SecretAccessKey: "secret-key",
+		},
+	}
+
+	cli, err := kine.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to kine: %v", err)
+	}
+
+	t.Cleanup(func() { _ = cli.Close() })
+
+	return backup.NewManager(cfg), cli
+}
+
+func create(t *testing.T, cli *clientv3.Client, key, value string) {
+	t.Helper()
+
+	response, err := cli.Txn(t.Context()).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, value)).
+		Commit()
+	if err != nil || !response.Succeeded {
+		t.Fatalf("failed to create %s: %v", key, err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	t.Parallel()
+
+	objectStore := newObjectStore(t)
+	source, sourceClient := newManager(t, objectStore.URL)
+
+	// More keys than fit on a single page of the export.
+	expected := map[string]string{}
+
+	for i := range 600 {
+		key := fmt.Sprintf("/registry/configmaps/default/cm-%04d", i)
+		expected[key] = "value-" + key
+		create(t, sourceClient, key, expected[key])
+	}
+
+	create(t, sourceClient, "/other/key", "outside of the registry")
+
+	snapshot, err := source.Backup(t.Context())
+	if err != nil {
+		t.Fatalf("failed to take snapshot: %v", err)
+	}
+
+	if snapshot.Keys != len(expected) {
+		t.Fatalf("expected %d keys in the snapshot, got %d", len(expected), snapshot.Keys)
+	}
+
+	target, targetClient := newManager(t, objectStore.URL)
+
+	keys, err := target.Restore(t.Context(), snapshot.Name)
+	if err != nil {
+		t.Fatalf("failed to restore snapshot: %v", err)
+	}
+
+	if keys != len(expected) {
+		t.Fatalf("expected %d keys to be restored, got %d", len(expected), keys)
+	}
+
+	for key, value := range expected {
+		response, err := targetClient.Get(t.Context(), key)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", key, err)
+		}
+
+		if len(response.Kvs) != 1 || string(response.Kvs[0].Value) != value {
+			t.Fatalf("expected %s to be restored", key)
+		}
+	}
+
+	response, err := targetClient.Get(t.Context(), "/other/key")
+	if err != nil || len(response.Kvs) != 0 {
+		t.Fatalf("expected keys outside of the registry not to be restored")
+	}
+
+	_, err = target.Restore(t.Context(), snapshot.Name)
+	if !errors.Is(err, backup.ErrRegistryNotEmpty) {
+		t.Fatalf("expected %v when restoring twice, got %v", backup.ErrRegistryNotEmpty, err)
+	}
+}
+
+func TestRestoreUnknownSnapshot(t *testing.T) {
+	t.Parallel()
+
+	manager, _ := newManager(t, newObjectStore(t).URL)
+
+	_, err := manager.Restore(t.Context(), "snapshot-unknown.jsonl.gz")
+	if !errors.Is(err, backup.ErrSnapshotNotFound) {
+		t.Fatalf("expected %v, got %v", backup.ErrSnapshotNotFound, err)
+	}
+}
+
+func TestBackupDisabled(t *testing.T) {
+	t.Parallel()
+
+	manager := backup.NewManager(&config.KommodityConfig{BackupConfig: &config.BackupConfig{}})
+
+	_, err := manager.Backup(t.Context())
+	if !errors.Is(err, backup.ErrBackupsDisabled) {
+		t.Fatalf("expected %v, got %v", backup.ErrBackupsDisabled, err)
+	}
+}
+
+// failingKV fails every transaction after the given number of them.
+type failingKV struct {
+	clientv3.KV
+
+	lock  sync.Mutex
+	txns  int
+	limit int
+}
+
+func (kv *failingKV) Txn(ctx context.Context) clientv3.Txn {
+	kv.lock.Lock()
+	defer kv.lock.Unlock()
+
+	kv.txns++
+	if kv.txns > kv.limit {
+		return failingTxn{}
+	}
+
+	return kv.KV.Txn(ctx)
+}
+
+type failingTxn struct{}
+
+func (txn failingTxn) If(...clientv3.Cmp) clientv3.Txn  { return txn }
+func (txn failingTxn) Then(...clientv3.Op) clientv3.Txn { return txn }
+func (txn failingTxn) Else(...clientv3.Op) clientv3.Txn { return txn }
+
+func (failingTxn) Commit() (*clientv3.TxnResponse, error) {
+	return nil, errKineUnavailable
+}
+
+var errKineUnavailable = errors.New("kine unavailable")
+
+func TestImportResumesPartialRestore(t *testing.T) {
+	t.Parallel()
+
+	sourceClient := newKineClient(t)
+
+	for i := range 10 {
+		key := fmt.Sprintf("/registry/configmaps/default/cm-%02d", i)
+		create(t, sourceClient, key, "value-"+key)
+	}
+
+	snapshot := &bytes.Buffer{}
+
+	_, keys, err := backup.Export(t.Context(), sourceClient, snapshot)
+	if err != nil {
+		t.Fatalf("failed to export snapshot: %v", err)
+	}
+
+	targetClient := newKineClient(t)
+
+	// The restore marker and four keys are created before kine becomes unavailable.
+	_, err = backup.Import(t.Context(), &failingKV{KV: targetClient, limit: 5}, bytes.NewReader(snapshot.Bytes()))
+	if !errors.Is(err, errKineUnavailable) {
+		t.Fatalf("expected the restore to fail partway, got %v", err)
+	}
+
+	other := &bytes.Buffer{}
+
+	_, _, err = backup.Export(t.Context(), newKineClient(t), other)
+	if err != nil {
+		t.Fatalf("failed to export other snapshot: %v", err)
+	}
+
+	_, err = backup.Import(t.Context(), targetClient, bytes.NewReader(other.Bytes()))
+	if !errors.Is(err, backup.ErrRestoreIncomplete) {
+		t.Fatalf("expected %v for another snapshot, got %v", backup.ErrRestoreIncomplete, err)
+	}
+
+	restored, err := backup.Import(t.Context(), targetClient, bytes.NewReader(snapshot.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to resume restore: %v", err)
+	}
+
+	if restored != keys {
+		t.Fatalf("expected %d keys to be restored, got %d", keys, restored)
+	}
+
+	response, err := targetClient.Get(t.Context(), "/registry/", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil || response.Count < int64(keys) {
+		t.Fatalf("expected %d keys in the registry, got %d (%v)", keys, response.Count, err)
+	}
+
+	_, err = backup.Import(t.Context(), targetClient, bytes.NewReader(snapshot.Bytes()))
+	if !errors.Is(err, backup.ErrRegistryNotEmpty) {
+		t.Fatalf("expected %v once the restore completed, got %v", backup.ErrRegistryNotEmpty, err)
+	}
+}
@@ -0,0 +1,9 @@
+// Package backup takes snapshots of the objects Kommodity stores in kine and restores
+// them into a fresh instance.
+//
+// A snapshot is a consistent export of the API server key space at a single revision,
+// written as gzip compressed JSON lines and uploaded to an S3-compatible object store.
+// Because the export goes through kine rather than the database, snapshots taken from
+// Postgres can be restored into SQLite and vice versa. Secrets are exported as stored,
+// so restoring them requires the same encryption keys.
+package backup
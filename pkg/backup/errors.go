@@ -0,0 +1,21 @@
+package backup
+
+import "errors"
+
+var (
+	// ErrBackupsDisabled is returned when no bucket is configured for snapshots.
+	ErrBackupsDisabled = errors.New("backups are disabled, no bucket configured")
+	// ErrRegistryNotEmpty is returned when restoring into a key space that already holds objects.
+	ErrRegistryNotEmpty = errors.New("refusing to restore into a non-empty registry")
+	// ErrRestoreIncomplete is returned when restoring a snapshot while the restore of
+	// another one stopped partway.
+	ErrRestoreIncomplete = errors.New("the restore of another snapshot is incomplete")
+	// ErrUnsupportedSnapshot is returned when a snapshot was written in an unknown format.
+	ErrUnsupportedSnapshot = errors.New("unsupported snapshot format")
+	// ErrKeyExists is returned when a key of the snapshot was created concurrently with the restore.
+	ErrKeyExists = errors.New("key already exists")
+	// ErrSnapshotNotFound is returned when the object store has no snapshot of the given name.
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrObjectStore is returned when the object store rejects a request.
+	ErrObjectStore = errors.New("object store error")
+)
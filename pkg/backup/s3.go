@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/aws/sigv4"
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+const (
+	s3Service = "s3"
+
+	// s3ErrorBodyLimit bounds how much of an error response is included in errors.
+	s3ErrorBodyLimit = 1024
+)
+
+// s3Store reads and writes objects of a bucket with SigV4 signed requests. Objects
+// are addressed path-style, which AWS S3 and the common S3-compatible stores such
+// as MinIO and Ceph support alike.
+type s3Store struct {
+	client          *http.Client
+	endpoint        string
+	region          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	now             func() time.Time
+}
+
+func newS3Store(client *http.Client, cfg *config.BackupConfig) *s3Store {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+
+	return &s3Store{
+		client:          client,
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		region:          cfg.Region,
+		bucket:          cfg.Bucket,
+		accessKeyID:     cfg.AccessKeyID,
+		secretAccessKey: cfg.SecretAccessKey,
+		now:             time.Now,
+	}
+}
+
+// Put uploads the object, replacing any object of the same name.
+func (s *s3Store) Put(ctx context.Context, name string, body []byte) error {
+	_, err := s.do(ctx, http.MethodPut, name, body)
+
+	return err
+}
+
+// Get downloads the object.
+func (s *s3Store) Get(ctx context.Context, name string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, name, nil)
+}
+
+func (s *s3Store) do(ctx context.Context, method, name string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+"/"+s.bucket+"/"+name, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", sigv4.PayloadHash(body))
+
+	sigv4.Sign(req, body, sigv4.Credentials{
+		AccessKeyID:     s.accessKeyID,
+		SecretAccessKey: s.secretAccessKey,
+	}, s.region, s3Service, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call S3: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+
+		return nil, fmt.Errorf("%w: %s %s returned %s: %s", ErrObjectStore, method, name,
+			resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 response: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,75 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	bearertoken "k8s.io/apiserver/pkg/authentication/request/bearertoken"
+)
+
+// SnapshotsEndpoint is the endpoint for taking a snapshot on demand.
+const SnapshotsEndpoint = "/backup/snapshots"
+
+// NewHTTPMuxFactory creates a new HTTP mux factory for the backup admin API and starts
+// the periodic snapshots, which stop when the context is cancelled. Nothing is served
+// unless a bucket is configured. Taking snapshots on demand requires OIDC, only members
+// of the admin group may call it.
+func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
+	return func(mux *http.ServeMux) error {
+		if cfg.BackupConfig == nil || cfg.BackupConfig.Bucket == "" {
+			return nil
+		}
+
+		logger := logging.FromContext(ctx)
+		manager := NewManager(cfg)
+
+		if cfg.BackupConfig.Interval > 0 {
+			go manager.Run(ctx)
+		}
+
+		if cfg.AuthConfig.OIDCConfig == nil {
+			logger.Warn("Not serving the backup API, it requires OIDC authentication")
+
+			return nil
+		}
+
+		oidcAuth, err := httpauth.NewOIDCAuthenticator(ctx, cfg.AuthConfig.OIDCConfig)
+		if err != nil {
+			return fmt.Errorf("failed to set up backup authentication: %w", err)
+		}
+
+		authenticate := httpauth.RequireBearerToken(bearertoken.New(oidcAuth), nil)
+
+		mux.HandleFunc("POST "+SnapshotsEndpoint,
+			authenticate(httpauth.RequireGroup(cfg.AuthConfig.AdminGroup)(postSnapshot(manager, logger))))
+
+		logger.Info("Serving the backup API", zap.String("endpoint", SnapshotsEndpoint))
+
+		return nil
+	}
+}
+
+// postSnapshot handles the POST /backup/snapshots endpoint.
+func postSnapshot(manager *Manager, logger *zap.Logger) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		snapshot, err := manager.Backup(request.Context())
+		if err != nil {
+			logger.Error("Failed to take snapshot", zap.Error(err))
+			http.Error(response, "Failed to take snapshot", http.StatusInternalServerError)
+
+			return
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+		response.WriteHeader(http.StatusCreated)
+
+		_ = json.NewEncoder(response).Encode(snapshot)
+	}
+}
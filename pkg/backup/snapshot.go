@@ -0,0 +1,286 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// registryPrefix is the key prefix below which the API server stores all objects.
+	registryPrefix = "/registry/"
+
+	// kineHealthKey is written by kine itself below the registry prefix and belongs to
+	// the instance rather than to the snapshot.
+	kineHealthKey = "/registry/health"
+
+	// restoreMarkerKey holds the header of the snapshot being restored. It is created
+	// before the first key of the snapshot and deleted after the last one, so a restore
+	// that stopped partway can be told apart from a registry in use. It lies outside of
+	// the registry prefix, so it is never exported.
+	restoreMarkerKey = "/kommodity/restore"
+
+	// emptyCheckLimit is the number of keys read to tell whether the registry is empty,
+	// one of them may be the health key.
+	emptyCheckLimit = 2
+
+	// snapshotPageSize is the number of keys read from kine per request.
+	snapshotPageSize = 500
+
+	snapshotFormatVersion = 1
+)
+
+// snapshotHeader is the first line of a snapshot.
+type snapshotHeader struct {
+	Version   int       `json:"version"`
+	Revision  int64     `json:"revision"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// snapshotEntry is a line of a snapshot holding a single key.
+type snapshotEntry struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Export writes all objects of the API server as of a single revision to the writer
+// and returns the revision and the number of keys written. Keys attached to a lease,
+// such as events, expire on their own and are left out, as is the health key of kine.
+func Export(ctx context.Context, kv clientv3.KV, writer io.Writer) (int64, int, error) {
+	compressor := gzip.NewWriter(writer)
+	encoder := json.NewEncoder(compressor)
+
+	rangeEnd := clientv3.GetPrefixRangeEnd(registryPrefix)
+	key := registryPrefix
+
+	var revision int64
+
+	keys := 0
+
+	for {
+		options := []clientv3.OpOption{clientv3.WithRange(rangeEnd), clientv3.WithLimit(snapshotPageSize)}
+		if revision != 0 {
+			// Read all pages at the revision of the first one to get a consistent view.
+			options = append(options, clientv3.WithRev(revision))
+		}
+
+		response, err := kv.Get(ctx, key, options...)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read keys from kine: %w", err)
+		}
+
+		if revision == 0 {
+			revision = response.Header.Revision
+
+			err = encoder.Encode(snapshotHeader{
+				Version:   snapshotFormatVersion,
+				Revision:  revision,
+				CreatedAt: time.Now().UTC(),
+			})
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to write snapshot header: %w", err)
+			}
+		}
+
+		for _, keyValue := range response.Kvs {
+			// The next page starts after the last key read.
+			key = string(keyValue.Key) + "\x00"
+
+			if keyValue.Lease != 0 || string(keyValue.Key) == kineHealthKey {
+				continue
+			}
+
+			err = encoder.Encode(snapshotEntry{Key: string(keyValue.Key), Value: keyValue.Value})
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to write snapshot entry: %w", err)
+			}
+
+			keys++
+		}
+
+		if !response.More {
+			break
+		}
+	}
+
+	err := compressor.Close()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return revision, keys, nil
+}
+
+// Import creates the objects of a snapshot and returns the number of keys restored. It
+// refuses to write into a key space that already holds objects, so a snapshot can only
+// be restored into a fresh instance before the API server starts. Kine cannot create
+// many keys in a single transaction, so a restore that stopped partway is resumed when
+// the same snapshot is imported again, keeping the keys created so far.
+func Import(ctx context.Context, kv clientv3.KV, reader io.Reader) (int, error) {
+	decompressor, err := gzip.NewReader(reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	defer func() { _ = decompressor.Close() }()
+
+	decoder := json.NewDecoder(decompressor)
+
+	var header snapshotHeader
+
+	err = decoder.Decode(&header)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+
+	if header.Version != snapshotFormatVersion {
+		return 0, fmt.Errorf("%w: version %d", ErrUnsupportedSnapshot, header.Version)
+	}
+
+	resuming, err := startRestore(ctx, kv, header)
+	if err != nil {
+		return 0, err
+	}
+
+	keys := 0
+
+	for {
+		var entry snapshotEntry
+
+		err = decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return keys, fmt.Errorf("failed to read snapshot entry: %w", err)
+		}
+
+		err = createKey(ctx, kv, entry, resuming)
+		if err != nil {
+			return keys, err
+		}
+
+		keys++
+	}
+
+	return keys, finishRestore(ctx, kv)
+}
+
+// startRestore records that the snapshot is being restored. It reports whether an
+// earlier restore of the same snapshot stopped partway, whose keys are then kept.
+// Otherwise the registry must be empty.
+func startRestore(ctx context.Context, kv clientv3.KV, header snapshotHeader) (bool, error) {
+	marker, err := kv.Get(ctx, restoreMarkerKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to read restore marker from kine: %w", err)
+	}
+
+	if len(marker.Kvs) > 0 {
+		var restoring snapshotHeader
+
+		err = json.Unmarshal(marker.Kvs[0].Value, &restoring)
+		if err != nil {
+			return false, fmt.Errorf("failed to read restore marker: %w", err)
+		}
+
+		if restoring.Revision != header.Revision || !restoring.CreatedAt.Equal(header.CreatedAt) {
+			return false, fmt.Errorf("%w: snapshot of revision %d", ErrRestoreIncomplete, restoring.Revision)
+		}
+
+		return true, nil
+	}
+
+	existing, err := kv.Get(ctx, registryPrefix, clientv3.WithPrefix(), clientv3.WithLimit(emptyCheckLimit))
+	if err != nil {
+		return false, fmt.Errorf("failed to read keys from kine: %w", err)
+	}
+
+	for _, keyValue := range existing.Kvs {
+		if string(keyValue.Key) != kineHealthKey {
+			return false, ErrRegistryNotEmpty
+		}
+	}
+
+	value, err := json.Marshal(header)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode restore marker: %w", err)
+	}
+
+	response, err := kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(restoreMarkerKey), "=", 0)).
+		Then(clientv3.OpPut(restoreMarkerKey, string(value))).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to create restore marker: %w", err)
+	}
+
+	if !response.Succeeded {
+		return false, fmt.Errorf("%w: %s", ErrKeyExists, restoreMarkerKey)
+	}
+
+	return false, nil
+}
+
+// createKey creates a key of the snapshot. When resuming a restore, keys the earlier
+// attempt created are skipped, as long as they hold the value of the snapshot.
+func createKey(ctx context.Context, kv clientv3.KV, entry snapshotEntry, resuming bool) error {
+	// Kine only supports puts as part of a create transaction.
+	response, err := kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(entry.Key), "=", 0)).
+		Then(clientv3.OpPut(entry.Key, string(entry.Value))).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to create key %s: %w", entry.Key, err)
+	}
+
+	if response.Succeeded {
+		return nil
+	}
+
+	if resuming {
+		existing, err := kv.Get(ctx, entry.Key)
+		if err != nil {
+			return fmt.Errorf("failed to read key %s: %w", entry.Key, err)
+		}
+
+		if len(existing.Kvs) == 1 && bytes.Equal(existing.Kvs[0].Value, entry.Value) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrKeyExists, entry.Key)
+}
+
+// finishRestore deletes the restore marker once all keys of the snapshot are created.
+func finishRestore(ctx context.Context, kv clientv3.KV) error {
+	marker, err := kv.Get(ctx, restoreMarkerKey)
+	if err != nil {
+		return fmt.Errorf("failed to read restore marker from kine: %w", err)
+	}
+
+	if len(marker.Kvs) == 0 {
+		return nil
+	}
+
+	// Kine only supports deletes guarded by the revision of the key.
+	modRevision := marker.Kvs[0].ModRevision
+
+	_, err = kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(restoreMarkerKey), "=", modRevision)).
+		Then(clientv3.OpDelete(restoreMarkerKey)).
+		Else(clientv3.OpGet(restoreMarkerKey)).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to delete restore marker: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+// Package bundle exports the objects served by Kommodity as a multi-document YAML
+// bundle and imports such bundles into another instance.
+//
+// Bundles go through the API of a running instance, so they are independent of the
+// database and of the encryption keys: Secrets are written in plain text and encrypted
+// again by the instance they are imported into. Owner references are rewritten on
+// import to point at the new UIDs of their owners.
+package bundle
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// skippedResources are never exported. They expire on their own or are maintained by
+// controllers of the instance.
+//
+//nolint:gochecknoglobals // Constant set of resources.
+var skippedResources = sets.New(
+	"events",
+	"events.events.k8s.io",
+	"leases.coordination.k8s.io",
+)
+
+// Filter selects the objects of a bundle.
+type Filter struct {
+	// Resources limits the bundle to the given resources, as "resource" for the core
+	// group or "resource.group". Empty means all resources.
+	Resources []string
+	// Namespaces limits namespaced objects and Namespace objects to the given
+	// namespaces. Other cluster-scoped objects are not affected. Empty means all.
+	Namespaces []string
+}
+
+// matchesResource reports whether objects of the resource are part of the bundle.
+func (f Filter) matchesResource(resource schema.GroupResource) bool {
+	if skippedResources.Has(resource.String()) {
+		return false
+	}
+
+	return len(f.Resources) == 0 || slices.Contains(f.Resources, resource.String())
+}
+
+// matchesObject reports whether the object is part of the bundle. The namespace of
+// cluster-scoped objects is empty.
+func (f Filter) matchesObject(resource schema.GroupResource, namespace, name string) bool {
+	if len(f.Namespaces) == 0 {
+		return true
+	}
+
+	if resource == (schema.GroupResource{Resource: "namespaces"}) {
+		return slices.Contains(f.Namespaces, name)
+	}
+
+	return namespace == "" || slices.Contains(f.Namespaces, namespace)
+}
@@ -0,0 +1,184 @@
+package bundle_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/bundle"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+//nolint:gochecknoglobals // Shared by all tests.
+var (
+	namespacesResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	configMapsResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	eventsResource     = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+	clustersResource   = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "clusters"}
+	machinesResource   = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "machines"}
+)
+
+func newDiscovery() *discoveryfake.FakeDiscovery {
+	verbs := metav1.Verbs{"create", "get", "list"}
+
+	return &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "namespaces", Kind: "Namespace", Verbs: verbs},
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: verbs},
+				{Name: "events", Kind: "Event", Namespaced: true, Verbs: verbs},
+			},
+		},
+		{
+			GroupVersion: "cluster.x-k8s.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "clusters", Kind: "Cluster", Namespaced: true, Verbs: verbs},
+				{Name: "clusters/status", Kind: "Cluster", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+				{Name: "machines", Kind: "Machine", Namespaced: true, Verbs: verbs},
+			},
+		},
+	}}}
+}
+
+func newObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	object.SetUID(types.UID("uid-" + name))
+	object.SetResourceVersion("42")
+
+	return object
+}
+
+// newDynamicClient returns a client serving the given objects. Created objects get the
+// UID "new-<name>", like a real server assigns a fresh UID.
+func newDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			namespacesResource: "NamespaceList",
+			configMapsResource: "ConfigMapList",
+			eventsResource:     "EventList",
+			clustersResource:   "ClusterList",
+			machinesResource:   "MachineList",
+		}, objects...)
+
+	client.PrependReactor("create", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		object := action.(clienttesting.CreateAction).GetObject().(*unstructured.Unstructured) //nolint:forcetypeassert // Dynamic clients create unstructured objects.
+		object.SetUID(types.UID("new-" + object.GetName()))
+
+		return false, nil, nil
+	})
+
+	return client
+}
+
+func newSourceClient() *dynamicfake.FakeDynamicClient {
+	machine := newObject("cluster.x-k8s.io/v1beta1", "Machine", "team-a", "worker")
+	machine.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "cluster.x-k8s.io/v1beta1", Kind: "Cluster", Name: "prod", UID: "uid-prod"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "gone", UID: "uid-gone"},
+	})
+
+	return newDynamicClient(
+		newObject("v1", "Namespace", "", "team-a"),
+		newObject("v1", "Namespace", "", "team-b"),
+		newObject("v1", "ConfigMap", "team-a", "settings"),
+		newObject("v1", "ConfigMap", "team-b", "other"),
+		newObject("v1", "Event", "team-a", "noise"),
+		newObject("cluster.x-k8s.io/v1beta1", "Cluster", "team-a", "prod"),
+		machine,
+	)
+}
+
+func export(t *testing.T, filter bundle.Filter) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	_, err := bundle.Export(t.Context(), newDiscovery(), newSourceClient(), &buffer, filter)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	return buffer.String()
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	objects, err := bundle.Export(t.Context(), newDiscovery(), newSourceClient(), &buffer, bundle.Filter{})
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	if objects != 6 {
+		t.Fatalf("expected 6 objects without the event, got %d:\n%s", objects, buffer.String())
+	}
+
+	output := buffer.String()
+
+	if strings.Contains(output, "resourceVersion") || strings.Contains(output, "noise") {
+		t.Fatalf("expected server fields and events to be left out:\n%s", output)
+	}
+
+	if strings.Index(output, "kind: Namespace") > strings.Index(output, "kind: ConfigMap") {
+		t.Fatalf("expected namespaces to be exported first:\n%s", output)
+	}
+}
+
+func TestExportWithFilter(t *testing.T) {
+	t.Parallel()
+
+	output := export(t, bundle.Filter{Namespaces: []string{"team-a"}})
+	if strings.Contains(output, "team-b") || !strings.Contains(output, "name: settings") {
+		t.Fatalf("expected only objects of team-a:\n%s", output)
+	}
+
+	output = export(t, bundle.Filter{Resources: []string{"clusters.cluster.x-k8s.io"}})
+	if strings.Count(output, "---") != 1 || !strings.Contains(output, "kind: Cluster") {
+		t.Fatalf("expected only the cluster:\n%s", output)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	target := newDynamicClient(newObject("v1", "Namespace", "", "team-a"))
+
+	result, err := bundle.Import(t.Context(), newDiscovery(), target,
+		strings.NewReader(export(t, bundle.Filter{})), bundle.Filter{Namespaces: []string{"team-a"}})
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+
+	if result.Created != 3 || result.Existing != 1 {
+		t.Fatalf("expected 3 objects to be created and 1 to exist, got %+v", result)
+	}
+
+	machine, err := target.Resource(machinesResource).Namespace("team-a").Get(t.Context(), "worker", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get machine: %v", err)
+	}
+
+	owners := machine.GetOwnerReferences()
+	if len(owners) != 1 || owners[0].UID != "new-prod" {
+		t.Fatalf("expected the owner reference to point at the imported cluster, got %+v", owners)
+	}
+
+	_, err = target.Resource(configMapsResource).Namespace("team-b").Get(t.Context(), "other", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("expected objects outside of the namespace filter not to be imported")
+	}
+}
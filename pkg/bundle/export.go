@@ -0,0 +1,173 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// exportPageSize is the number of objects listed per request.
+	exportPageSize = 500
+
+	documentSeparator = "---\n"
+)
+
+// resourceOrder lists the resources exported before all others, in order, so that
+// an import creates the types and namespaces before the objects that need them.
+//
+//nolint:gochecknoglobals // Constant order of resources.
+var resourceOrder = []schema.GroupResource{
+	{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"},
+	{Resource: "namespaces"},
+}
+
+// exportedResource is a resource whose objects are written to the bundle.
+type exportedResource struct {
+	resource   schema.GroupVersionResource
+	namespaced bool
+}
+
+// Export writes the objects selected by the filter to the writer as multi-document
+// YAML and returns the number of objects written. Fields set by the server, except
+// for the UID that owner references are resolved with, are removed.
+func Export(ctx context.Context, discoveryClient discovery.DiscoveryInterface,
+	client dynamic.Interface, writer io.Writer, filter Filter) (int, error) {
+	resources, err := exportedResources(discoveryClient, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	objects := 0
+
+	for _, resource := range resources {
+		namespaces := []string{metav1.NamespaceAll}
+		if resource.namespaced && len(filter.Namespaces) > 0 {
+			namespaces = filter.Namespaces
+		}
+
+		for _, namespace := range namespaces {
+			written, err := exportResource(ctx, client.Resource(resource.resource).Namespace(namespace),
+				resource.resource.GroupResource(), writer, filter)
+			objects += written
+
+			if err != nil {
+				return objects, fmt.Errorf("failed to export %s: %w", resource.resource.GroupResource(), err)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// exportedResources returns the preferred version of every resource that can be
+// listed and created and is selected by the filter.
+func exportedResources(discoveryClient discovery.DiscoveryInterface, filter Filter) ([]exportedResource, error) {
+	resourceLists, err := discovery.ServerPreferredResources(discoveryClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+
+	var resources []exportedResource
+
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse group version %s: %w", resourceList.GroupVersion, err)
+		}
+
+		for _, apiResource := range resourceList.APIResources {
+			// Subresources are part of their parent object.
+			if strings.Contains(apiResource.Name, "/") ||
+				!slices.Contains(apiResource.Verbs, "list") || !slices.Contains(apiResource.Verbs, "create") {
+				continue
+			}
+
+			resource := groupVersion.WithResource(apiResource.Name)
+			if !filter.matchesResource(resource.GroupResource()) {
+				continue
+			}
+
+			resources = append(resources, exportedResource{resource: resource, namespaced: apiResource.Namespaced})
+		}
+	}
+
+	slices.SortStableFunc(resources, func(a, b exportedResource) int {
+		return exportRank(a.resource.GroupResource()) - exportRank(b.resource.GroupResource())
+	})
+
+	return resources, nil
+}
+
+func exportRank(resource schema.GroupResource) int {
+	rank := slices.Index(resourceOrder, resource)
+	if rank < 0 {
+		return len(resourceOrder)
+	}
+
+	return rank
+}
+
+// exportResource writes all objects of a resource, page by page.
+func exportResource(ctx context.Context, client dynamic.ResourceInterface, resource schema.GroupResource,
+	writer io.Writer, filter Filter) (int, error) {
+	objects := 0
+	options := metav1.ListOptions{Limit: exportPageSize}
+
+	for {
+		list, err := client.List(ctx, options)
+		if err != nil {
+			return objects, fmt.Errorf("failed to list: %w", err)
+		}
+
+		for _, object := range list.Items {
+			if object.GetDeletionTimestamp() != nil ||
+				!filter.matchesObject(resource, object.GetNamespace(), object.GetName()) {
+				continue
+			}
+
+			err = writeObject(writer, &object)
+			if err != nil {
+				return objects, err
+			}
+
+			objects++
+		}
+
+		options.Continue = list.GetContinue()
+		if options.Continue == "" {
+			return objects, nil
+		}
+	}
+}
+
+func writeObject(writer io.Writer, object *unstructured.Unstructured) error {
+	for _, field := range []string{"resourceVersion", "creationTimestamp", "generation", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(object.Object, "metadata", field)
+	}
+
+	data, err := yaml.Marshal(object.Object)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", object.GetNamespace(), object.GetName(), err)
+	}
+
+	_, err = io.WriteString(writer, documentSeparator)
+	if err == nil {
+		_, err = writer.Write(data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return nil
+}
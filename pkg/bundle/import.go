@@ -0,0 +1,234 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// mappingPollInterval and mappingTimeout bound the wait for the type of an object
+	// to be served, typically a custom resource whose definition was just imported.
+	mappingPollInterval = time.Second
+	mappingTimeout      = time.Minute
+)
+
+// Result summarises an import.
+type Result struct {
+	// Created is the number of objects created.
+	Created int `json:"created"`
+	// Existing is the number of objects skipped because they already existed.
+	Existing int `json:"existing"`
+}
+
+// ownedObject is an imported object whose owner references are restored once all
+// objects are created.
+type ownedObject struct {
+	client dynamic.ResourceInterface
+	name   string
+	owners []metav1.OwnerReference
+}
+
+// Import creates the objects of a bundle that are selected by the filter. Objects that
+// already exist are left as they are. Owner references are restored after all objects
+// are created, pointing at the UIDs of the owners in this instance; references to
+// owners that are not part of the bundle are dropped.
+func Import(ctx context.Context, discoveryClient discovery.DiscoveryInterface,
+	client dynamic.Interface, reader io.Reader, filter Filter) (Result, error) {
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	documents := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+	uids := map[types.UID]types.UID{}
+
+	var (
+		result Result
+		owned  []ownedObject
+	)
+
+	for {
+		document, err := documents.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return result, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		object, err := decodeObject(document)
+		if err != nil {
+			return result, err
+		}
+
+		if object == nil {
+			continue
+		}
+
+		mapping, err := restMapping(ctx, mapper, object)
+		if err != nil {
+			return result, err
+		}
+
+		if !filter.matchesResource(mapping.Resource.GroupResource()) ||
+			!filter.matchesObject(mapping.Resource.GroupResource(), object.GetNamespace(), object.GetName()) {
+			continue
+		}
+
+		var resourceClient dynamic.ResourceInterface = client.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			resourceClient = client.Resource(mapping.Resource).Namespace(object.GetNamespace())
+		}
+
+		oldUID := object.GetUID()
+		owners := object.GetOwnerReferences()
+
+		object.SetUID("")
+		object.SetResourceVersion("")
+		object.SetOwnerReferences(nil)
+
+		created, err := resourceClient.Create(ctx, object, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			result.Existing++
+
+			// Objects of the bundle may still be owned by an existing object.
+			created, err = resourceClient.Get(ctx, object.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return result, fmt.Errorf("failed to get %s %s: %w", mapping.Resource.GroupResource(), describe(object), err)
+			}
+
+			uids[oldUID] = created.GetUID()
+
+			continue
+		}
+
+		if err != nil {
+			return result, fmt.Errorf("failed to create %s %s: %w", mapping.Resource.GroupResource(), describe(object), err)
+		}
+
+		result.Created++
+		uids[oldUID] = created.GetUID()
+
+		if len(owners) > 0 {
+			owned = append(owned, ownedObject{client: resourceClient, name: object.GetName(), owners: owners})
+		}
+	}
+
+	for _, object := range owned {
+		err := restoreOwners(ctx, object, uids)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// decodeObject decodes a document of the bundle. It returns nil for empty documents.
+func decodeObject(document []byte) (*unstructured.Unstructured, error) {
+	if len(bytes.TrimSpace(document)) == 0 {
+		return nil, nil //nolint:nilnil // An empty document is not an error.
+	}
+
+	data, err := yaml.YAMLToJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bundle document: %w", err)
+	}
+
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil //nolint:nilnil // A document holding only comments is not an error.
+	}
+
+	object := &unstructured.Unstructured{}
+
+	err = object.UnmarshalJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bundle document: %w", err)
+	}
+
+	return object, nil
+}
+
+// restMapping resolves the resource of the object. Custom resources are only served
+// once their definition is established, so the mapping is retried for a while.
+func restMapping(ctx context.Context, mapper *restmapper.DeferredDiscoveryRESTMapper,
+	object *unstructured.Unstructured) (*meta.RESTMapping, error) {
+	gvk := object.GroupVersionKind()
+
+	var mapping *meta.RESTMapping
+
+	err := wait.PollUntilContextTimeout(ctx, mappingPollInterval, mappingTimeout, true,
+		func(context.Context) (bool, error) {
+			var err error
+
+			mapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if meta.IsNoMatchError(err) {
+				mapper.Reset()
+
+				return false, nil
+			}
+
+			return err == nil, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the resource of %s: %w", gvk, err)
+	}
+
+	return mapping, nil
+}
+
+// restoreOwners sets the owner references of an imported object, pointing them at the
+// new UIDs of the owners.
+func restoreOwners(ctx context.Context, object ownedObject, uids map[types.UID]types.UID) error {
+	owners := make([]metav1.OwnerReference, 0, len(object.owners))
+
+	for _, owner := range object.owners {
+		uid, found := uids[owner.UID]
+		if !found {
+			continue
+		}
+
+		owner.UID = uid
+		owners = append(owners, owner)
+	}
+
+	if len(owners) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"ownerReferences": owners}})
+	if err != nil {
+		return fmt.Errorf("failed to encode owner references of %s: %w", object.name, err)
+	}
+
+	_, err = object.client.Patch(ctx, object.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restore owner references of %s: %w", object.name, err)
+	}
+
+	return nil
+}
+
+func describe(object *unstructured.Unstructured) string {
+	if object.GetNamespace() == "" {
+		return object.GetName()
+	}
+
+	return object.GetNamespace() + "/" + object.GetName()
+}
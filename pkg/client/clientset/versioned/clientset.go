@@ -0,0 +1,109 @@
+// Package versioned contains the typed clientset of the kommodity.io API.
+package versioned
+
+import (
+	"fmt"
+	"net/http"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Interface is the clientset of the kommodity.io API.
+type Interface interface {
+	Discovery() discovery.DiscoveryInterface
+	KommodityV1alpha1() kommodityv1alpha1.KommodityV1alpha1Interface
+}
+
+// Clientset contains the clients of the kommodity.io API groups.
+type Clientset struct {
+	*discovery.DiscoveryClient
+
+	kommodityV1alpha1 *kommodityv1alpha1.KommodityV1alpha1Client
+}
+
+var _ Interface = &Clientset{}
+
+// KommodityV1alpha1 retrieves the KommodityV1alpha1Client.
+func (c *Clientset) KommodityV1alpha1() kommodityv1alpha1.KommodityV1alpha1Interface {
+	return c.kommodityV1alpha1
+}
+
+// Discovery retrieves the DiscoveryClient.
+func (c *Clientset) Discovery() discovery.DiscoveryInterface {
+	if c == nil {
+		return nil
+	}
+
+	return c.DiscoveryClient
+}
+
+// NewForConfig creates a new Clientset for the given config. The clients of the
+// clientset share one HTTP client.
+func NewForConfig(c *rest.Config) (*Clientset, error) {
+	configShallowCopy := *c
+
+	if configShallowCopy.UserAgent == "" {
+		configShallowCopy.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+
+	httpClient, err := rest.HTTPClientFor(&configShallowCopy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	return NewForConfigAndClient(&configShallowCopy, httpClient)
+}
+
+// NewForConfigAndClient creates a new Clientset for the given config and HTTP client.
+// The HTTP client takes precedence over the transport of the config.
+func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset, error) {
+	configShallowCopy := *c
+
+	if configShallowCopy.RateLimiter == nil && configShallowCopy.QPS > 0 {
+		if configShallowCopy.Burst <= 0 {
+			return nil, ErrBurstRequired
+		}
+
+		configShallowCopy.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(configShallowCopy.QPS,
+			configShallowCopy.Burst)
+	}
+
+	var (
+		clientset Clientset
+		err       error
+	)
+
+	clientset.kommodityV1alpha1, err = kommodityv1alpha1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kommodity.io/v1alpha1 client: %w", err)
+	}
+
+	clientset.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return &clientset, nil
+}
+
+// NewForConfigOrDie creates a new Clientset for the given config and panics if the
+// config is invalid.
+func NewForConfigOrDie(c *rest.Config) *Clientset {
+	clientset, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+
+	return clientset
+}
+
+// New creates a new Clientset for the given REST client.
+func New(c rest.Interface) *Clientset {
+	return &Clientset{
+		DiscoveryClient:   discovery.NewDiscoveryClient(c),
+		kommodityV1alpha1: kommodityv1alpha1.New(c),
+	}
+}
@@ -0,0 +1,6 @@
+package versioned
+
+import "errors"
+
+// ErrBurstRequired is returned when the config sets a QPS limit but no burst.
+var ErrBurstRequired = errors.New("burst is required to be greater than 0 when RateLimiter is not set and QPS is set")
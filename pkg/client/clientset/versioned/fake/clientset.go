@@ -0,0 +1,71 @@
+// Package fake contains a fake of the kommodity.io clientset for unit tests.
+package fake
+
+import (
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	fakekommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/testing"
+)
+
+// NewSimpleClientset returns a clientset that responds with the given objects. It is
+// backed by an object tracker that applies creates, updates and deletes as they are,
+// without defaulting, validation or field management.
+func NewSimpleClientset(objects ...runtime.Object) *Clientset {
+	tracker := testing.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+
+	for _, obj := range objects {
+		err := tracker.Add(obj)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	clientset := &Clientset{tracker: tracker}
+	clientset.discovery = &fakediscovery.FakeDiscovery{Fake: &clientset.Fake}
+	clientset.AddReactor("*", "*", testing.ObjectReaction(tracker))
+	clientset.AddWatchReactor("*", func(action testing.Action) (bool, watch.Interface, error) {
+		watcher, err := tracker.Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err //nolint:wrapcheck // Returned to the fake as is.
+		}
+
+		return true, watcher, nil
+	})
+
+	return clientset
+}
+
+// Clientset implements versioned.Interface on top of an object tracker. Embed it to
+// fake only the methods a test cares about.
+type Clientset struct {
+	testing.Fake
+
+	discovery *fakediscovery.FakeDiscovery
+	tracker   testing.ObjectTracker
+}
+
+var (
+	_ versioned.Interface = &Clientset{}
+	_ testing.FakeClient  = &Clientset{}
+)
+
+// Discovery retrieves the fake DiscoveryClient.
+func (c *Clientset) Discovery() discovery.DiscoveryInterface {
+	return c.discovery
+}
+
+// Tracker returns the object tracker backing the clientset.
+func (c *Clientset) Tracker() testing.ObjectTracker {
+	return c.tracker
+}
+
+// KommodityV1alpha1 retrieves the fake KommodityV1alpha1Client.
+func (c *Clientset) KommodityV1alpha1() kommodityv1alpha1.KommodityV1alpha1Interface {
+	return &fakekommodityv1alpha1.FakeKommodityV1alpha1{Fake: &c.Fake}
+}
@@ -0,0 +1,34 @@
+// Package scheme contains the scheme of the kommodity.io clientset.
+package scheme
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+//nolint:gochecknoglobals // Scheme registration follows the Kubernetes API conventions.
+var (
+	// Scheme contains the kommodity.io types of the clientset.
+	Scheme = runtime.NewScheme()
+	// Codecs encodes and decodes the types of Scheme.
+	Codecs = serializer.NewCodecFactory(Scheme)
+	// ParameterCodec encodes the query parameters of requests.
+	ParameterCodec = runtime.NewParameterCodec(Scheme)
+
+	localSchemeBuilder = runtime.SchemeBuilder{
+		kommodityv1alpha1.AddToScheme,
+	}
+
+	// AddToScheme adds the types of the clientset to a scheme, so that other clientsets
+	// can decode kommodity.io objects, e.g. when embedded in RawExtensions.
+	AddToScheme = localSchemeBuilder.AddToScheme
+)
+
+func init() { //nolint:gochecknoinits // Scheme registration follows the Kubernetes API conventions.
+	metav1.AddToGroupVersion(Scheme, schema.GroupVersion{Version: "v1"})
+	utilruntime.Must(AddToScheme(Scheme))
+}
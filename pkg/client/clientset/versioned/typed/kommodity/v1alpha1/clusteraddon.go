@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// ClusterAddonsGetter has a method to return a ClusterAddonInterface.
+type ClusterAddonsGetter interface {
+	ClusterAddons(namespace string) ClusterAddonInterface
+}
+
+// ClusterAddonInterface has methods to work with ClusterAddon resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type ClusterAddonInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.ClusterAddon, opts metav1.CreateOptions) (*kommodityv1alpha1.ClusterAddon, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.ClusterAddon, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterAddon, error)
+	UpdateStatus(ctx context.Context, obj *kommodityv1alpha1.ClusterAddon, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterAddon, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.ClusterAddon, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.ClusterAddonList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.ClusterAddon, error)
+}
+
+// clusterAddons implements ClusterAddonInterface.
+type clusterAddons struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.ClusterAddon, *kommodityv1alpha1.ClusterAddonList]
+}
+
+func newClusterAddons(c *KommodityV1alpha1Client, namespace string) *clusterAddons {
+	return &clusterAddons{
+		gentype.NewClientWithList[*kommodityv1alpha1.ClusterAddon, *kommodityv1alpha1.ClusterAddonList](
+			"clusteraddons",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.ClusterAddon { return &kommodityv1alpha1.ClusterAddon{} },
+			func() *kommodityv1alpha1.ClusterAddonList { return &kommodityv1alpha1.ClusterAddonList{} },
+		),
+	}
+}
@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// ClusterHealthsGetter has a method to return a ClusterHealthInterface.
+type ClusterHealthsGetter interface {
+	ClusterHealths(namespace string) ClusterHealthInterface
+}
+
+// ClusterHealthInterface has methods to work with ClusterHealth resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type ClusterHealthInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.ClusterHealth, opts metav1.CreateOptions) (*kommodityv1alpha1.ClusterHealth, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.ClusterHealth, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterHealth, error)
+	UpdateStatus(ctx context.Context, obj *kommodityv1alpha1.ClusterHealth, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterHealth, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.ClusterHealth, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.ClusterHealthList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.ClusterHealth, error)
+}
+
+// clusterHealths implements ClusterHealthInterface.
+type clusterHealths struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.ClusterHealth, *kommodityv1alpha1.ClusterHealthList]
+}
+
+func newClusterHealths(c *KommodityV1alpha1Client, namespace string) *clusterHealths {
+	return &clusterHealths{
+		gentype.NewClientWithList[*kommodityv1alpha1.ClusterHealth, *kommodityv1alpha1.ClusterHealthList](
+			"clusterhealths",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.ClusterHealth { return &kommodityv1alpha1.ClusterHealth{} },
+			func() *kommodityv1alpha1.ClusterHealthList { return &kommodityv1alpha1.ClusterHealthList{} },
+		),
+	}
+}
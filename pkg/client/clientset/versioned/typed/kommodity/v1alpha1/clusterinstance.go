@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// ClusterInstancesGetter has a method to return a ClusterInstanceInterface.
+type ClusterInstancesGetter interface {
+	ClusterInstances(namespace string) ClusterInstanceInterface
+}
+
+// ClusterInstanceInterface has methods to work with ClusterInstance resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type ClusterInstanceInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.ClusterInstance, opts metav1.CreateOptions) (*kommodityv1alpha1.ClusterInstance, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.ClusterInstance, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterInstance, error)
+	UpdateStatus(ctx context.Context, obj *kommodityv1alpha1.ClusterInstance, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterInstance, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.ClusterInstance, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.ClusterInstanceList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.ClusterInstance, error)
+}
+
+// clusterInstances implements ClusterInstanceInterface.
+type clusterInstances struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.ClusterInstance, *kommodityv1alpha1.ClusterInstanceList]
+}
+
+func newClusterInstances(c *KommodityV1alpha1Client, namespace string) *clusterInstances {
+	return &clusterInstances{
+		gentype.NewClientWithList[*kommodityv1alpha1.ClusterInstance, *kommodityv1alpha1.ClusterInstanceList](
+			"clusterinstances",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.ClusterInstance { return &kommodityv1alpha1.ClusterInstance{} },
+			func() *kommodityv1alpha1.ClusterInstanceList { return &kommodityv1alpha1.ClusterInstanceList{} },
+		),
+	}
+}
@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// ClusterTemplatesGetter has a method to return a ClusterTemplateInterface.
+type ClusterTemplatesGetter interface {
+	ClusterTemplates(namespace string) ClusterTemplateInterface
+}
+
+// ClusterTemplateInterface has methods to work with ClusterTemplate resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type ClusterTemplateInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.ClusterTemplate, opts metav1.CreateOptions) (*kommodityv1alpha1.ClusterTemplate, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.ClusterTemplate, opts metav1.UpdateOptions) (*kommodityv1alpha1.ClusterTemplate, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.ClusterTemplate, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.ClusterTemplateList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.ClusterTemplate, error)
+}
+
+// clusterTemplates implements ClusterTemplateInterface.
+type clusterTemplates struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.ClusterTemplate, *kommodityv1alpha1.ClusterTemplateList]
+}
+
+func newClusterTemplates(c *KommodityV1alpha1Client, namespace string) *clusterTemplates {
+	return &clusterTemplates{
+		gentype.NewClientWithList[*kommodityv1alpha1.ClusterTemplate, *kommodityv1alpha1.ClusterTemplateList](
+			"clustertemplates",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.ClusterTemplate { return &kommodityv1alpha1.ClusterTemplate{} },
+			func() *kommodityv1alpha1.ClusterTemplateList { return &kommodityv1alpha1.ClusterTemplateList{} },
+		),
+	}
+}
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeClusterAddons implements v1alpha1.ClusterAddonInterface.
+type fakeClusterAddons struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.ClusterAddon, *kommodityv1alpha1.ClusterAddonList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeClusterAddons(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.ClusterAddonInterface {
+	return &fakeClusterAddons{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.ClusterAddon, *kommodityv1alpha1.ClusterAddonList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("clusteraddons"),
+			kommodityv1alpha1.GroupVersion.WithKind("ClusterAddon"),
+			func() *kommodityv1alpha1.ClusterAddon { return &kommodityv1alpha1.ClusterAddon{} },
+			func() *kommodityv1alpha1.ClusterAddonList { return &kommodityv1alpha1.ClusterAddonList{} },
+			func(dst, src *kommodityv1alpha1.ClusterAddonList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.ClusterAddonList) []*kommodityv1alpha1.ClusterAddon {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.ClusterAddonList, items []*kommodityv1alpha1.ClusterAddon) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeClusterHealths implements v1alpha1.ClusterHealthInterface.
+type fakeClusterHealths struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.ClusterHealth, *kommodityv1alpha1.ClusterHealthList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeClusterHealths(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.ClusterHealthInterface {
+	return &fakeClusterHealths{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.ClusterHealth, *kommodityv1alpha1.ClusterHealthList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("clusterhealths"),
+			kommodityv1alpha1.GroupVersion.WithKind("ClusterHealth"),
+			func() *kommodityv1alpha1.ClusterHealth { return &kommodityv1alpha1.ClusterHealth{} },
+			func() *kommodityv1alpha1.ClusterHealthList { return &kommodityv1alpha1.ClusterHealthList{} },
+			func(dst, src *kommodityv1alpha1.ClusterHealthList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.ClusterHealthList) []*kommodityv1alpha1.ClusterHealth {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.ClusterHealthList, items []*kommodityv1alpha1.ClusterHealth) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
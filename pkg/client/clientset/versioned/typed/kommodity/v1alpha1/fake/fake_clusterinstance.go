@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeClusterInstances implements v1alpha1.ClusterInstanceInterface.
+type fakeClusterInstances struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.ClusterInstance, *kommodityv1alpha1.ClusterInstanceList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeClusterInstances(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.ClusterInstanceInterface {
+	return &fakeClusterInstances{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.ClusterInstance, *kommodityv1alpha1.ClusterInstanceList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("clusterinstances"),
+			kommodityv1alpha1.GroupVersion.WithKind("ClusterInstance"),
+			func() *kommodityv1alpha1.ClusterInstance { return &kommodityv1alpha1.ClusterInstance{} },
+			func() *kommodityv1alpha1.ClusterInstanceList { return &kommodityv1alpha1.ClusterInstanceList{} },
+			func(dst, src *kommodityv1alpha1.ClusterInstanceList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.ClusterInstanceList) []*kommodityv1alpha1.ClusterInstance {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.ClusterInstanceList, items []*kommodityv1alpha1.ClusterInstance) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeClusterTemplates implements v1alpha1.ClusterTemplateInterface.
+type fakeClusterTemplates struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.ClusterTemplate, *kommodityv1alpha1.ClusterTemplateList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeClusterTemplates(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.ClusterTemplateInterface {
+	return &fakeClusterTemplates{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.ClusterTemplate, *kommodityv1alpha1.ClusterTemplateList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("clustertemplates"),
+			kommodityv1alpha1.GroupVersion.WithKind("ClusterTemplate"),
+			func() *kommodityv1alpha1.ClusterTemplate { return &kommodityv1alpha1.ClusterTemplate{} },
+			func() *kommodityv1alpha1.ClusterTemplateList { return &kommodityv1alpha1.ClusterTemplateList{} },
+			func(dst, src *kommodityv1alpha1.ClusterTemplateList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.ClusterTemplateList) []*kommodityv1alpha1.ClusterTemplate {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.ClusterTemplateList, items []*kommodityv1alpha1.ClusterTemplate) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeInfraClusters implements v1alpha1.InfraClusterInterface.
+type fakeInfraClusters struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.InfraCluster, *kommodityv1alpha1.InfraClusterList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeInfraClusters(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.InfraClusterInterface {
+	return &fakeInfraClusters{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.InfraCluster, *kommodityv1alpha1.InfraClusterList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("infraclusters"),
+			kommodityv1alpha1.GroupVersion.WithKind("InfraCluster"),
+			func() *kommodityv1alpha1.InfraCluster { return &kommodityv1alpha1.InfraCluster{} },
+			func() *kommodityv1alpha1.InfraClusterList { return &kommodityv1alpha1.InfraClusterList{} },
+			func(dst, src *kommodityv1alpha1.InfraClusterList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.InfraClusterList) []*kommodityv1alpha1.InfraCluster {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.InfraClusterList, items []*kommodityv1alpha1.InfraCluster) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
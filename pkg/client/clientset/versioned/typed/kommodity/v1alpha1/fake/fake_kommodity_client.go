@@ -0,0 +1,68 @@
+// Package fake contains a fake of the kommodity.io/v1alpha1 typed client, backed by the
+// object tracker of a fake clientset.
+package fake
+
+import (
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/testing"
+)
+
+// FakeKommodityV1alpha1 implements v1alpha1.KommodityV1alpha1Interface.
+type FakeKommodityV1alpha1 struct {
+	*testing.Fake
+}
+
+var _ v1alpha1.KommodityV1alpha1Interface = &FakeKommodityV1alpha1{}
+
+// ClusterAddons returns the fake client of the ClusterAddons in the namespace.
+func (c *FakeKommodityV1alpha1) ClusterAddons(namespace string) v1alpha1.ClusterAddonInterface {
+	return newFakeClusterAddons(c, namespace)
+}
+
+// ClusterHealths returns the fake client of the ClusterHealths in the namespace.
+func (c *FakeKommodityV1alpha1) ClusterHealths(namespace string) v1alpha1.ClusterHealthInterface {
+	return newFakeClusterHealths(c, namespace)
+}
+
+// ClusterInstances returns the fake client of the ClusterInstances in the namespace.
+func (c *FakeKommodityV1alpha1) ClusterInstances(namespace string) v1alpha1.ClusterInstanceInterface {
+	return newFakeClusterInstances(c, namespace)
+}
+
+// ClusterTemplates returns the fake client of the ClusterTemplates in the namespace.
+func (c *FakeKommodityV1alpha1) ClusterTemplates(namespace string) v1alpha1.ClusterTemplateInterface {
+	return newFakeClusterTemplates(c, namespace)
+}
+
+// InfraClusters returns the fake client of the InfraClusters in the namespace.
+func (c *FakeKommodityV1alpha1) InfraClusters(namespace string) v1alpha1.InfraClusterInterface {
+	return newFakeInfraClusters(c, namespace)
+}
+
+// MachineConfigPatches returns the fake client of the MachineConfigPatches in the namespace.
+func (c *FakeKommodityV1alpha1) MachineConfigPatches(namespace string) v1alpha1.MachineConfigPatchInterface {
+	return newFakeMachineConfigPatches(c, namespace)
+}
+
+// ProviderCredentials returns the fake client of the ProviderCredentials in the namespace.
+func (c *FakeKommodityV1alpha1) ProviderCredentials(namespace string) v1alpha1.ProviderCredentialInterface {
+	return newFakeProviderCredentials(c, namespace)
+}
+
+// RegistryMirrors returns the fake client of the RegistryMirrors in the namespace.
+func (c *FakeKommodityV1alpha1) RegistryMirrors(namespace string) v1alpha1.RegistryMirrorInterface {
+	return newFakeRegistryMirrors(c, namespace)
+}
+
+// TalosUpgradePlans returns the fake client of the TalosUpgradePlans in the namespace.
+func (c *FakeKommodityV1alpha1) TalosUpgradePlans(namespace string) v1alpha1.TalosUpgradePlanInterface {
+	return newFakeTalosUpgradePlans(c, namespace)
+}
+
+// RESTClient returns a nil REST client, the fake does not talk to an API server.
+func (c *FakeKommodityV1alpha1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+
+	return ret
+}
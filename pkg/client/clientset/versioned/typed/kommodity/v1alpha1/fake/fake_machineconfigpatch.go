@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeMachineConfigPatches implements v1alpha1.MachineConfigPatchInterface.
+type fakeMachineConfigPatches struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.MachineConfigPatch, *kommodityv1alpha1.MachineConfigPatchList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeMachineConfigPatches(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.MachineConfigPatchInterface {
+	return &fakeMachineConfigPatches{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.MachineConfigPatch, *kommodityv1alpha1.MachineConfigPatchList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("machineconfigpatches"),
+			kommodityv1alpha1.GroupVersion.WithKind("MachineConfigPatch"),
+			func() *kommodityv1alpha1.MachineConfigPatch { return &kommodityv1alpha1.MachineConfigPatch{} },
+			func() *kommodityv1alpha1.MachineConfigPatchList { return &kommodityv1alpha1.MachineConfigPatchList{} },
+			func(dst, src *kommodityv1alpha1.MachineConfigPatchList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.MachineConfigPatchList) []*kommodityv1alpha1.MachineConfigPatch {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.MachineConfigPatchList, items []*kommodityv1alpha1.MachineConfigPatch) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeProviderCredentials implements v1alpha1.ProviderCredentialInterface.
+type fakeProviderCredentials struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.ProviderCredential, *kommodityv1alpha1.ProviderCredentialList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeProviderCredentials(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.ProviderCredentialInterface {
+	return &fakeProviderCredentials{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.ProviderCredential, *kommodityv1alpha1.ProviderCredentialList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("providercredentials"),
+			kommodityv1alpha1.GroupVersion.WithKind("ProviderCredential"),
+			func() *kommodityv1alpha1.ProviderCredential { return &kommodityv1alpha1.ProviderCredential{} },
+			func() *kommodityv1alpha1.ProviderCredentialList { return &kommodityv1alpha1.ProviderCredentialList{} },
+			func(dst, src *kommodityv1alpha1.ProviderCredentialList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.ProviderCredentialList) []*kommodityv1alpha1.ProviderCredential {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.ProviderCredentialList, items []*kommodityv1alpha1.ProviderCredential) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
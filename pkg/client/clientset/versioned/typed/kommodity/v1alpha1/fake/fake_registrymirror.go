@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeRegistryMirrors implements v1alpha1.RegistryMirrorInterface.
+type fakeRegistryMirrors struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.RegistryMirror, *kommodityv1alpha1.RegistryMirrorList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeRegistryMirrors(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.RegistryMirrorInterface {
+	return &fakeRegistryMirrors{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.RegistryMirror, *kommodityv1alpha1.RegistryMirrorList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("registrymirrors"),
+			kommodityv1alpha1.GroupVersion.WithKind("RegistryMirror"),
+			func() *kommodityv1alpha1.RegistryMirror { return &kommodityv1alpha1.RegistryMirror{} },
+			func() *kommodityv1alpha1.RegistryMirrorList { return &kommodityv1alpha1.RegistryMirrorList{} },
+			func(dst, src *kommodityv1alpha1.RegistryMirrorList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.RegistryMirrorList) []*kommodityv1alpha1.RegistryMirror {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.RegistryMirrorList, items []*kommodityv1alpha1.RegistryMirror) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	v1alpha1 "github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/typed/kommodity/v1alpha1"
+	"k8s.io/client-go/gentype"
+)
+
+// fakeTalosUpgradePlans implements v1alpha1.TalosUpgradePlanInterface.
+type fakeTalosUpgradePlans struct {
+	*gentype.FakeClientWithList[*kommodityv1alpha1.TalosUpgradePlan, *kommodityv1alpha1.TalosUpgradePlanList]
+
+	Fake *FakeKommodityV1alpha1
+}
+
+//nolint:dupl // Mirrors the fake clients generated for the Kubernetes APIs.
+func newFakeTalosUpgradePlans(fake *FakeKommodityV1alpha1, namespace string) v1alpha1.TalosUpgradePlanInterface {
+	return &fakeTalosUpgradePlans{
+		gentype.NewFakeClientWithList[*kommodityv1alpha1.TalosUpgradePlan, *kommodityv1alpha1.TalosUpgradePlanList](
+			fake.Fake,
+			namespace,
+			kommodityv1alpha1.GroupVersion.WithResource("talosupgradeplans"),
+			kommodityv1alpha1.GroupVersion.WithKind("TalosUpgradePlan"),
+			func() *kommodityv1alpha1.TalosUpgradePlan { return &kommodityv1alpha1.TalosUpgradePlan{} },
+			func() *kommodityv1alpha1.TalosUpgradePlanList { return &kommodityv1alpha1.TalosUpgradePlanList{} },
+			func(dst, src *kommodityv1alpha1.TalosUpgradePlanList) { dst.ListMeta = src.ListMeta },
+			func(list *kommodityv1alpha1.TalosUpgradePlanList) []*kommodityv1alpha1.TalosUpgradePlan {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *kommodityv1alpha1.TalosUpgradePlanList, items []*kommodityv1alpha1.TalosUpgradePlan) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
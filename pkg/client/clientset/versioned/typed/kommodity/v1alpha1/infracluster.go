@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// InfraClustersGetter has a method to return an InfraClusterInterface.
+type InfraClustersGetter interface {
+	InfraClusters(namespace string) InfraClusterInterface
+}
+
+// InfraClusterInterface has methods to work with InfraCluster resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type InfraClusterInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.InfraCluster, opts metav1.CreateOptions) (*kommodityv1alpha1.InfraCluster, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.InfraCluster, opts metav1.UpdateOptions) (*kommodityv1alpha1.InfraCluster, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.InfraCluster, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.InfraClusterList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.InfraCluster, error)
+}
+
+// infraClusters implements InfraClusterInterface.
+type infraClusters struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.InfraCluster, *kommodityv1alpha1.InfraClusterList]
+}
+
+func newInfraClusters(c *KommodityV1alpha1Client, namespace string) *infraClusters {
+	return &infraClusters{
+		gentype.NewClientWithList[*kommodityv1alpha1.InfraCluster, *kommodityv1alpha1.InfraClusterList](
+			"infraclusters",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.InfraCluster { return &kommodityv1alpha1.InfraCluster{} },
+			func() *kommodityv1alpha1.InfraClusterList { return &kommodityv1alpha1.InfraClusterList{} },
+		),
+	}
+}
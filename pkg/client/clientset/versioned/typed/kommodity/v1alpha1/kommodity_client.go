@@ -0,0 +1,138 @@
+// Package v1alpha1 contains the typed client of the kommodity.io/v1alpha1 API.
+package v1alpha1
+
+import (
+	"fmt"
+	"net/http"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// KommodityV1alpha1Interface has methods to work with the kommodity.io/v1alpha1 resources.
+type KommodityV1alpha1Interface interface {
+	RESTClient() rest.Interface
+	ClusterAddonsGetter
+	ClusterHealthsGetter
+	ClusterInstancesGetter
+	ClusterTemplatesGetter
+	InfraClustersGetter
+	MachineConfigPatchesGetter
+	ProviderCredentialsGetter
+	RegistryMirrorsGetter
+	TalosUpgradePlansGetter
+}
+
+// KommodityV1alpha1Client is used to interact with the kommodity.io/v1alpha1 resources.
+type KommodityV1alpha1Client struct {
+	restClient rest.Interface
+}
+
+// ClusterAddons returns the client of the ClusterAddons in the namespace.
+func (c *KommodityV1alpha1Client) ClusterAddons(namespace string) ClusterAddonInterface {
+	return newClusterAddons(c, namespace)
+}
+
+// ClusterHealths returns the client of the ClusterHealths in the namespace.
+func (c *KommodityV1alpha1Client) ClusterHealths(namespace string) ClusterHealthInterface {
+	return newClusterHealths(c, namespace)
+}
+
+// ClusterInstances returns the client of the ClusterInstances in the namespace.
+func (c *KommodityV1alpha1Client) ClusterInstances(namespace string) ClusterInstanceInterface {
+	return newClusterInstances(c, namespace)
+}
+
+// ClusterTemplates returns the client of the ClusterTemplates in the namespace.
+func (c *KommodityV1alpha1Client) ClusterTemplates(namespace string) ClusterTemplateInterface {
+	return newClusterTemplates(c, namespace)
+}
+
+// InfraClusters returns the client of the InfraClusters in the namespace.
+func (c *KommodityV1alpha1Client) InfraClusters(namespace string) InfraClusterInterface {
+	return newInfraClusters(c, namespace)
+}
+
+// MachineConfigPatches returns the client of the MachineConfigPatches in the namespace.
+func (c *KommodityV1alpha1Client) MachineConfigPatches(namespace string) MachineConfigPatchInterface {
+	return newMachineConfigPatches(c, namespace)
+}
+
+// ProviderCredentials returns the client of the ProviderCredentials in the namespace.
+func (c *KommodityV1alpha1Client) ProviderCredentials(namespace string) ProviderCredentialInterface {
+	return newProviderCredentials(c, namespace)
+}
+
+// RegistryMirrors returns the client of the RegistryMirrors in the namespace.
+func (c *KommodityV1alpha1Client) RegistryMirrors(namespace string) RegistryMirrorInterface {
+	return newRegistryMirrors(c, namespace)
+}
+
+// TalosUpgradePlans returns the client of the TalosUpgradePlans in the namespace.
+func (c *KommodityV1alpha1Client) TalosUpgradePlans(namespace string) TalosUpgradePlanInterface {
+	return newTalosUpgradePlans(c, namespace)
+}
+
+// NewForConfig creates a new KommodityV1alpha1Client for the given config.
+func NewForConfig(c *rest.Config) (*KommodityV1alpha1Client, error) {
+	config := *c
+	setConfigDefaults(&config)
+
+	httpClient, err := rest.HTTPClientFor(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	return NewForConfigAndClient(&config, httpClient)
+}
+
+// NewForConfigAndClient creates a new KommodityV1alpha1Client for the given config and
+// HTTP client. The HTTP client takes precedence over the transport of the config.
+func NewForConfigAndClient(c *rest.Config, h *http.Client) (*KommodityV1alpha1Client, error) {
+	config := *c
+	setConfigDefaults(&config)
+
+	client, err := rest.RESTClientForConfigAndClient(&config, h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST client: %w", err)
+	}
+
+	return &KommodityV1alpha1Client{client}, nil
+}
+
+// NewForConfigOrDie creates a new KommodityV1alpha1Client for the given config and
+// panics if the config is invalid.
+func NewForConfigOrDie(c *rest.Config) *KommodityV1alpha1Client {
+	client, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+
+	return client
+}
+
+// New creates a new KommodityV1alpha1Client for the given REST client.
+func New(c rest.Interface) *KommodityV1alpha1Client {
+	return &KommodityV1alpha1Client{c}
+}
+
+func setConfigDefaults(config *rest.Config) {
+	gv := kommodityv1alpha1.GroupVersion
+	config.GroupVersion = &gv
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = rest.CodecFactoryForGeneratedClient(scheme.Scheme, scheme.Codecs).WithoutConversion()
+
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+}
+
+// RESTClient returns the REST client used to communicate with the API server.
+func (c *KommodityV1alpha1Client) RESTClient() rest.Interface {
+	if c == nil {
+		return nil
+	}
+
+	return c.restClient
+}
@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// MachineConfigPatchesGetter has a method to return a MachineConfigPatchInterface.
+type MachineConfigPatchesGetter interface {
+	MachineConfigPatches(namespace string) MachineConfigPatchInterface
+}
+
+// MachineConfigPatchInterface has methods to work with MachineConfigPatch resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type MachineConfigPatchInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.MachineConfigPatch, opts metav1.CreateOptions) (*kommodityv1alpha1.MachineConfigPatch, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.MachineConfigPatch, opts metav1.UpdateOptions) (*kommodityv1alpha1.MachineConfigPatch, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.MachineConfigPatch, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.MachineConfigPatchList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.MachineConfigPatch, error)
+}
+
+// machineConfigPatches implements MachineConfigPatchInterface.
+type machineConfigPatches struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.MachineConfigPatch, *kommodityv1alpha1.MachineConfigPatchList]
+}
+
+func newMachineConfigPatches(c *KommodityV1alpha1Client, namespace string) *machineConfigPatches {
+	return &machineConfigPatches{
+		gentype.NewClientWithList[*kommodityv1alpha1.MachineConfigPatch, *kommodityv1alpha1.MachineConfigPatchList](
+			"machineconfigpatches",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.MachineConfigPatch { return &kommodityv1alpha1.MachineConfigPatch{} },
+			func() *kommodityv1alpha1.MachineConfigPatchList { return &kommodityv1alpha1.MachineConfigPatchList{} },
+		),
+	}
+}
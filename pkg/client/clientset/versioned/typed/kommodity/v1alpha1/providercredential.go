@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// ProviderCredentialsGetter has a method to return a ProviderCredentialInterface.
+type ProviderCredentialsGetter interface {
+	ProviderCredentials(namespace string) ProviderCredentialInterface
+}
+
+// ProviderCredentialInterface has methods to work with ProviderCredential resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type ProviderCredentialInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.ProviderCredential, opts metav1.CreateOptions) (*kommodityv1alpha1.ProviderCredential, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.ProviderCredential, opts metav1.UpdateOptions) (*kommodityv1alpha1.ProviderCredential, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.ProviderCredential, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.ProviderCredentialList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.ProviderCredential, error)
+}
+
+// providerCredentials implements ProviderCredentialInterface.
+type providerCredentials struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.ProviderCredential, *kommodityv1alpha1.ProviderCredentialList]
+}
+
+func newProviderCredentials(c *KommodityV1alpha1Client, namespace string) *providerCredentials {
+	return &providerCredentials{
+		gentype.NewClientWithList[*kommodityv1alpha1.ProviderCredential, *kommodityv1alpha1.ProviderCredentialList](
+			"providercredentials",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.ProviderCredential { return &kommodityv1alpha1.ProviderCredential{} },
+			func() *kommodityv1alpha1.ProviderCredentialList { return &kommodityv1alpha1.ProviderCredentialList{} },
+		),
+	}
+}
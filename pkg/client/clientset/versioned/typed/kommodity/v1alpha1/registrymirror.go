@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// RegistryMirrorsGetter has a method to return a RegistryMirrorInterface.
+type RegistryMirrorsGetter interface {
+	RegistryMirrors(namespace string) RegistryMirrorInterface
+}
+
+// RegistryMirrorInterface has methods to work with RegistryMirror resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type RegistryMirrorInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.RegistryMirror, opts metav1.CreateOptions) (*kommodityv1alpha1.RegistryMirror, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.RegistryMirror, opts metav1.UpdateOptions) (*kommodityv1alpha1.RegistryMirror, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.RegistryMirror, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.RegistryMirrorList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.RegistryMirror, error)
+}
+
+// registryMirrors implements RegistryMirrorInterface.
+type registryMirrors struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.RegistryMirror, *kommodityv1alpha1.RegistryMirrorList]
+}
+
+func newRegistryMirrors(c *KommodityV1alpha1Client, namespace string) *registryMirrors {
+	return &registryMirrors{
+		gentype.NewClientWithList[*kommodityv1alpha1.RegistryMirror, *kommodityv1alpha1.RegistryMirrorList](
+			"registrymirrors",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.RegistryMirror { return &kommodityv1alpha1.RegistryMirror{} },
+			func() *kommodityv1alpha1.RegistryMirrorList { return &kommodityv1alpha1.RegistryMirrorList{} },
+		),
+	}
+}
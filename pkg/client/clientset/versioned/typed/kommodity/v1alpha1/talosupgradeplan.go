@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	"context"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/gentype"
+)
+
+// TalosUpgradePlansGetter has a method to return a TalosUpgradePlanInterface.
+type TalosUpgradePlansGetter interface {
+	TalosUpgradePlans(namespace string) TalosUpgradePlanInterface
+}
+
+// TalosUpgradePlanInterface has methods to work with TalosUpgradePlan resources.
+//
+//nolint:lll,dupl // Mirrors the clients generated for the Kubernetes APIs.
+type TalosUpgradePlanInterface interface {
+	Create(ctx context.Context, obj *kommodityv1alpha1.TalosUpgradePlan, opts metav1.CreateOptions) (*kommodityv1alpha1.TalosUpgradePlan, error)
+	Update(ctx context.Context, obj *kommodityv1alpha1.TalosUpgradePlan, opts metav1.UpdateOptions) (*kommodityv1alpha1.TalosUpgradePlan, error)
+	UpdateStatus(ctx context.Context, obj *kommodityv1alpha1.TalosUpgradePlan, opts metav1.UpdateOptions) (*kommodityv1alpha1.TalosUpgradePlan, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*kommodityv1alpha1.TalosUpgradePlan, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*kommodityv1alpha1.TalosUpgradePlanList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kommodityv1alpha1.TalosUpgradePlan, error)
+}
+
+// talosUpgradePlans implements TalosUpgradePlanInterface.
+type talosUpgradePlans struct {
+	*gentype.ClientWithList[*kommodityv1alpha1.TalosUpgradePlan, *kommodityv1alpha1.TalosUpgradePlanList]
+}
+
+func newTalosUpgradePlans(c *KommodityV1alpha1Client, namespace string) *talosUpgradePlans {
+	return &talosUpgradePlans{
+		gentype.NewClientWithList[*kommodityv1alpha1.TalosUpgradePlan, *kommodityv1alpha1.TalosUpgradePlanList](
+			"talosupgradeplans",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *kommodityv1alpha1.TalosUpgradePlan { return &kommodityv1alpha1.TalosUpgradePlan{} },
+			func() *kommodityv1alpha1.TalosUpgradePlanList { return &kommodityv1alpha1.TalosUpgradePlanList{} },
+		),
+	}
+}
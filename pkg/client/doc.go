@@ -0,0 +1,10 @@
+// Package client contains the typed clientset, informers and listers for the kommodity.io
+// API. They follow the layout of the clients generated for the Kubernetes APIs, so they
+// compose with client-go the same way:
+//
+//	clientset, err := versioned.NewForConfig(config)
+//	...
+//	factory := externalversions.NewSharedInformerFactory(clientset, resync)
+//	addons := factory.Kommodity().V1alpha1().ClusterAddons().Lister()
+//	factory.Start(ctx.Done())
+package client
@@ -0,0 +1,6 @@
+package externalversions
+
+import "errors"
+
+// ErrNoInformer is returned when the factory has no informer for a resource.
+var ErrNoInformer = errors.New("no informer found for resource")
@@ -0,0 +1,206 @@
+// Package externalversions contains the shared informer factory of the kommodity.io API.
+package externalversions
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned"
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions/internalinterfaces"
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions/kommodity"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SharedInformerOption configures a SharedInformerFactory.
+type SharedInformerOption func(*sharedInformerFactory) *sharedInformerFactory
+
+type sharedInformerFactory struct {
+	client           versioned.Interface
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	lock             sync.Mutex
+	defaultResync    time.Duration
+	customResync     map[reflect.Type]time.Duration
+	transform        cache.TransformFunc
+
+	informers map[reflect.Type]cache.SharedIndexInformer
+	// startedInformers lets Start be called repeatedly, e.g. after adding informers.
+	startedInformers map[reflect.Type]bool
+	// wg tracks the goroutines running the informers.
+	wg sync.WaitGroup
+	// shuttingDown is set by Shutdown, no informers are started afterwards.
+	shuttingDown bool
+}
+
+// WithCustomResyncConfig sets the resync period of the given informer types.
+func WithCustomResyncConfig(resyncConfig map[metav1.Object]time.Duration) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		for obj, resync := range resyncConfig {
+			factory.customResync[reflect.TypeOf(obj)] = resync
+		}
+
+		return factory
+	}
+}
+
+// WithTweakListOptions transforms the list options of all informers of the factory.
+func WithTweakListOptions(tweakListOptions internalinterfaces.TweakListOptionsFunc) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		factory.tweakListOptions = tweakListOptions
+
+		return factory
+	}
+}
+
+// WithNamespace limits the informers of the factory to a namespace.
+func WithNamespace(namespace string) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		factory.namespace = namespace
+
+		return factory
+	}
+}
+
+// WithTransform sets a transform on all informers of the factory.
+func WithTransform(transform cache.TransformFunc) SharedInformerOption {
+	return func(factory *sharedInformerFactory) *sharedInformerFactory {
+		factory.transform = transform
+
+		return factory
+	}
+}
+
+// NewSharedInformerFactory constructs a SharedInformerFactory for all namespaces.
+func NewSharedInformerFactory(client versioned.Interface, defaultResync time.Duration) SharedInformerFactory {
+	return NewSharedInformerFactoryWithOptions(client, defaultResync)
+}
+
+// NewSharedInformerFactoryWithOptions constructs a SharedInformerFactory with options.
+func NewSharedInformerFactoryWithOptions(client versioned.Interface, defaultResync time.Duration,
+	options ...SharedInformerOption) SharedInformerFactory {
+	factory := &sharedInformerFactory{
+		client:           client,
+		namespace:        metav1.NamespaceAll,
+		defaultResync:    defaultResync,
+		informers:        make(map[reflect.Type]cache.SharedIndexInformer),
+		startedInformers: make(map[reflect.Type]bool),
+		customResync:     make(map[reflect.Type]time.Duration),
+	}
+
+	for _, opt := range options {
+		factory = opt(factory)
+	}
+
+	return factory
+}
+
+// Start runs the informers requested so far that are not running yet.
+func (f *sharedInformerFactory) Start(stopCh <-chan struct{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.shuttingDown {
+		return
+	}
+
+	for informerType, informer := range f.informers {
+		if f.startedInformers[informerType] {
+			continue
+		}
+
+		f.wg.Go(func() {
+			informer.Run(stopCh)
+		})
+
+		f.startedInformers[informerType] = true
+	}
+}
+
+// Shutdown stops starting informers and waits for the running ones to return.
+func (f *sharedInformerFactory) Shutdown() {
+	f.lock.Lock()
+	f.shuttingDown = true
+	f.lock.Unlock()
+
+	f.wg.Wait()
+}
+
+// WaitForCacheSync waits for the caches of the started informers to sync.
+func (f *sharedInformerFactory) WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool {
+	informers := func() map[reflect.Type]cache.SharedIndexInformer {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		informers := map[reflect.Type]cache.SharedIndexInformer{}
+
+		for informerType, informer := range f.informers {
+			if f.startedInformers[informerType] {
+				informers[informerType] = informer
+			}
+		}
+
+		return informers
+	}()
+
+	res := map[reflect.Type]bool{}
+	for informerType, informer := range informers {
+		res[informerType] = cache.WaitForCacheSync(stopCh, informer.HasSynced)
+	}
+
+	return res
+}
+
+// InformerFor returns the shared informer for the type of obj, creating it with newFunc
+// on first use.
+func (f *sharedInformerFactory) InformerFor(obj runtime.Object,
+	newFunc internalinterfaces.NewInformerFunc) cache.SharedIndexInformer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	informerType := reflect.TypeOf(obj)
+
+	informer, exists := f.informers[informerType]
+	if exists {
+		return informer
+	}
+
+	resyncPeriod, exists := f.customResync[informerType]
+	if !exists {
+		resyncPeriod = f.defaultResync
+	}
+
+	informer = newFunc(f.client, resyncPeriod)
+
+	// Setting the transform only fails for started informers, this one was just created.
+	_ = informer.SetTransform(f.transform)
+
+	f.informers[informerType] = informer
+
+	return informer
+}
+
+// SharedInformerFactory provides shared informers for the resources of the kommodity.io
+// API. Informers requested after Start only run once Start is called again.
+type SharedInformerFactory interface {
+	internalinterfaces.SharedInformerFactory
+
+	// Shutdown stops starting informers and blocks until the running ones returned,
+	// which they do once the stop channel they were started with is closed.
+	Shutdown()
+	// WaitForCacheSync blocks until the caches of all started informers synced or the
+	// stop channel is closed.
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+	// ForResource gives generic access to the shared informer of a resource.
+	ForResource(resource schema.GroupVersionResource) (GenericInformer, error)
+
+	Kommodity() kommodity.Interface
+}
+
+// Kommodity returns the informers of the kommodity.io API group.
+func (f *sharedInformerFactory) Kommodity() kommodity.Interface {
+	return kommodity.New(f, f.namespace, f.tweakListOptions)
+}
@@ -0,0 +1,74 @@
+package externalversions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned/fake"
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestListerServesObjectsOfClientset(t *testing.T) {
+	t.Parallel()
+
+	addon := &kommodityv1alpha1.ClusterAddon{
+		ObjectMeta: metav1.ObjectMeta{Name: "cilium", Namespace: "default"},
+		Spec:       kommodityv1alpha1.ClusterAddonSpec{ClusterName: "demo"},
+	}
+
+	client := fake.NewSimpleClientset(addon)
+
+	factory := externalversions.NewSharedInformerFactory(client, time.Minute)
+	lister := factory.Kommodity().V1alpha1().ClusterAddons().Lister()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	factory.Start(ctx.Done())
+	// Shutdown waits for the informers, which only return once ctx is canceled.
+	defer factory.Shutdown()
+	defer cancel()
+
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			t.Fatalf("cache of %v did not sync", informerType)
+		}
+	}
+
+	got, err := lister.ClusterAddons("default").Get("cilium")
+	if err != nil {
+		t.Fatalf("failed to get addon from lister: %v", err)
+	}
+
+	if got.Spec.ClusterName != "demo" {
+		t.Fatalf("expected cluster name demo, got %q", got.Spec.ClusterName)
+	}
+
+	all, err := lister.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("failed to list addons: %v", err)
+	}
+
+	if len(all) != 1 {
+		t.Fatalf("expected 1 addon, got %d", len(all))
+	}
+}
+
+func TestForResourceRejectsUnknownResources(t *testing.T) {
+	t.Parallel()
+
+	factory := externalversions.NewSharedInformerFactory(fake.NewSimpleClientset(), time.Minute)
+
+	_, err := factory.ForResource(kommodityv1alpha1.GroupVersion.WithResource("clusterhealths"))
+	if err != nil {
+		t.Fatalf("expected an informer for clusterhealths, got %v", err)
+	}
+
+	_, err = factory.ForResource(kommodityv1alpha1.GroupVersion.WithResource("unknown"))
+	if err == nil {
+		t.Fatal("expected an error for an unknown resource")
+	}
+}
@@ -0,0 +1,60 @@
+package externalversions
+
+import (
+	"fmt"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// GenericInformer is a shared informer with a lister that is not typed.
+type GenericInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() cache.GenericLister
+}
+
+type genericInformer struct {
+	informer cache.SharedIndexInformer
+	resource schema.GroupResource
+}
+
+// Informer returns the SharedIndexInformer.
+func (f *genericInformer) Informer() cache.SharedIndexInformer {
+	return f.informer
+}
+
+// Lister returns the GenericLister.
+func (f *genericInformer) Lister() cache.GenericLister {
+	return cache.NewGenericLister(f.Informer().GetIndexer(), f.resource)
+}
+
+// ForResource gives generic access to the shared informer of a resource.
+func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
+	var informer cache.SharedIndexInformer
+
+	switch resource {
+	case kommodityv1alpha1.GroupVersion.WithResource("clusteraddons"):
+		informer = f.Kommodity().V1alpha1().ClusterAddons().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("clusterhealths"):
+		informer = f.Kommodity().V1alpha1().ClusterHealths().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("clusterinstances"):
+		informer = f.Kommodity().V1alpha1().ClusterInstances().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("clustertemplates"):
+		informer = f.Kommodity().V1alpha1().ClusterTemplates().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("infraclusters"):
+		informer = f.Kommodity().V1alpha1().InfraClusters().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("machineconfigpatches"):
+		informer = f.Kommodity().V1alpha1().MachineConfigPatches().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("providercredentials"):
+		informer = f.Kommodity().V1alpha1().ProviderCredentials().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("registrymirrors"):
+		informer = f.Kommodity().V1alpha1().RegistryMirrors().Informer()
+	case kommodityv1alpha1.GroupVersion.WithResource("talosupgradeplans"):
+		informer = f.Kommodity().V1alpha1().TalosUpgradePlans().Informer()
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNoInformer, resource)
+	}
+
+	return &genericInformer{resource: resource.GroupResource(), informer: informer}, nil
+}
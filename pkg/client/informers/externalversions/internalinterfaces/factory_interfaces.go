@@ -0,0 +1,24 @@
+// Package internalinterfaces contains the interfaces shared by the informer factory and
+// the informers of the API groups, without an import cycle between them.
+package internalinterfaces
+
+import (
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewInformerFunc creates the informer of a type for a clientset and resync period.
+type NewInformerFunc func(versioned.Interface, time.Duration) cache.SharedIndexInformer
+
+// SharedInformerFactory lets informers register with the factory they are shared through.
+type SharedInformerFactory interface {
+	Start(stopCh <-chan struct{})
+	InformerFor(obj runtime.Object, newFunc NewInformerFunc) cache.SharedIndexInformer
+}
+
+// TweakListOptionsFunc transforms the list options of an informer.
+type TweakListOptionsFunc func(*metav1.ListOptions)
@@ -0,0 +1,30 @@
+// Package kommodity contains the informers of the kommodity.io API group.
+package kommodity
+
+import (
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions/internalinterfaces"
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions/kommodity/v1alpha1"
+)
+
+// Interface provides access to each version of the group.
+type Interface interface {
+	// V1alpha1 provides access to the informers of the v1alpha1 resources.
+	V1alpha1() v1alpha1.Interface
+}
+
+type group struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string,
+	tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &group{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// V1alpha1 returns a new v1alpha1.Interface.
+func (g *group) V1alpha1() v1alpha1.Interface {
+	return v1alpha1.New(g.factory, g.namespace, g.tweakListOptions)
+}
@@ -0,0 +1,82 @@
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/client/clientset/versioned"
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions/internalinterfaces"
+	listersv1alpha1 "github.com/kommodity-io/kommodity/pkg/client/listers/kommodity/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InfraClusterInformer provides access to a shared informer and lister for InfraClusters.
+type InfraClusterInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() listersv1alpha1.InfraClusterLister
+}
+
+type infraClusterInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewInfraClusterInformer constructs a new informer for InfraClusters. Prefer the informer of a
+// shared informer factory, which shares the cache and the watch between its users.
+func NewInfraClusterInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration,
+	indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredInfraClusterInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredInfraClusterInformer constructs a new informer for InfraClusters whose list options
+// are transformed by tweakListOptions.
+//
+//nolint:dupl // Mirrors the informers generated for the Kubernetes APIs.
+func NewFilteredInfraClusterInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration,
+	indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+
+				//nolint:wrapcheck // API status errors must be returned as is.
+				return client.KommodityV1alpha1().InfraClusters(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+
+				//nolint:wrapcheck // API status errors must be returned as is.
+				return client.KommodityV1alpha1().InfraClusters(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&kommodityv1alpha1.InfraCluster{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *infraClusterInformer) defaultInformer(client versioned.Interface,
+	resyncPeriod time.Duration) cache.SharedIndexInformer {
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+
+	return NewFilteredInfraClusterInformer(client, f.namespace, resyncPeriod, indexers, f.tweakListOptions)
+}
+
+// Informer returns the shared informer of the factory for InfraClusters.
+func (f *infraClusterInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&kommodityv1alpha1.InfraCluster{}, f.defaultInformer)
+}
+
+// Lister returns a lister reading from the cache of the shared informer.
+func (f *infraClusterInformer) Lister() listersv1alpha1.InfraClusterLister {
+	return listersv1alpha1.NewInfraClusterLister(f.Informer().GetIndexer())
+}
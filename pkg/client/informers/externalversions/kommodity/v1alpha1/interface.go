@@ -0,0 +1,92 @@
+// Package v1alpha1 contains the informers of the kommodity.io/v1alpha1 resources.
+package v1alpha1
+
+import (
+	"github.com/kommodity-io/kommodity/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to the informers of the group version.
+type Interface interface {
+	// ClusterAddons returns a ClusterAddonInformer.
+	ClusterAddons() ClusterAddonInformer
+	// ClusterHealths returns a ClusterHealthInformer.
+	ClusterHealths() ClusterHealthInformer
+	// ClusterInstances returns a ClusterInstanceInformer.
+	ClusterInstances() ClusterInstanceInformer
+	// ClusterTemplates returns a ClusterTemplateInformer.
+	ClusterTemplates() ClusterTemplateInformer
+	// InfraClusters returns an InfraClusterInformer.
+	InfraClusters() InfraClusterInformer
+	// MachineConfigPatches returns a MachineConfigPatchInformer.
+	MachineConfigPatches() MachineConfigPatchInformer
+	// ProviderCredentials returns a ProviderCredentialInformer.
+	ProviderCredentials() ProviderCredentialInformer
+	// RegistryMirrors returns a RegistryMirrorInformer.
+	RegistryMirrors() RegistryMirrorInformer
+	// TalosUpgradePlans returns a TalosUpgradePlanInformer.
+	TalosUpgradePlans() TalosUpgradePlanInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string,
+	tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// ClusterAddons returns a ClusterAddonInformer.
+func (v *version) ClusterAddons() ClusterAddonInformer {
+	return &clusterAddonInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterHealths returns a ClusterHealthInformer.
+func (v *version) ClusterHealths() ClusterHealthInformer {
+	return &clusterHealthInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterInstances returns a ClusterInstanceInformer.
+func (v *version) ClusterInstances() ClusterInstanceInformer {
+	return &clusterInstanceInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterTemplates returns a ClusterTemplateInformer.
+func (v *version) ClusterTemplates() ClusterTemplateInformer {
+	return &clusterTemplateInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
+
+// InfraClusters returns an InfraClusterInformer.
+func (v *version) InfraClusters() InfraClusterInformer {
+	return &infraClusterInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
+
+// MachineConfigPatches returns a MachineConfigPatchInformer.
+func (v *version) MachineConfigPatches() MachineConfigPatchInformer {
+	return &machineConfigPatchInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
+
+// ProviderCredentials returns a ProviderCredentialInformer.
+func (v *version) ProviderCredentials() ProviderCredentialInformer {
+	return &providerCredentialInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
+
+// RegistryMirrors returns a RegistryMirrorInformer.
+func (v *version) RegistryMirrors() RegistryMirrorInformer {
+	return &registryMirrorInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
+
+// TalosUpgradePlans returns a TalosUpgradePlanInformer.
+func (v *version) TalosUpgradePlans() TalosUpgradePlanInformer {
+	return &talosUpgradePlanInformer{factory: v.factory, namespace: v.namespace,
+		tweakListOptions: v.tweakListOptions}
+}
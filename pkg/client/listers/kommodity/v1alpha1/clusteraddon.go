@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterAddonLister lists ClusterAddons in all namespaces.
+type ClusterAddonLister interface {
+	// List lists all ClusterAddons in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterAddon, error)
+	// ClusterAddons returns a lister for the ClusterAddons in a namespace.
+	ClusterAddons(namespace string) ClusterAddonNamespaceLister
+}
+
+// ClusterAddonNamespaceLister lists and gets the ClusterAddons of a namespace.
+type ClusterAddonNamespaceLister interface {
+	// List lists the ClusterAddons of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterAddon, error)
+	// Get retrieves a ClusterAddon of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.ClusterAddon, error)
+}
+
+type clusterAddonLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterAddon]
+}
+
+// NewClusterAddonLister returns a ClusterAddonLister reading from the indexer.
+func NewClusterAddonLister(indexer cache.Indexer) ClusterAddonLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("clusteraddons").GroupResource()
+
+	return &clusterAddonLister{listers.New[*kommodityv1alpha1.ClusterAddon](indexer, resource)}
+}
+
+func (l *clusterAddonLister) ClusterAddons(namespace string) ClusterAddonNamespaceLister {
+	return clusterAddonNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type clusterAddonNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterAddon]
+}
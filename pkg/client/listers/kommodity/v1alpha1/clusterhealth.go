@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterHealthLister lists ClusterHealths in all namespaces.
+type ClusterHealthLister interface {
+	// List lists all ClusterHealths in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterHealth, error)
+	// ClusterHealths returns a lister for the ClusterHealths in a namespace.
+	ClusterHealths(namespace string) ClusterHealthNamespaceLister
+}
+
+// ClusterHealthNamespaceLister lists and gets the ClusterHealths of a namespace.
+type ClusterHealthNamespaceLister interface {
+	// List lists the ClusterHealths of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterHealth, error)
+	// Get retrieves a ClusterHealth of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.ClusterHealth, error)
+}
+
+type clusterHealthLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterHealth]
+}
+
+// NewClusterHealthLister returns a ClusterHealthLister reading from the indexer.
+func NewClusterHealthLister(indexer cache.Indexer) ClusterHealthLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("clusterhealths").GroupResource()
+
+	return &clusterHealthLister{listers.New[*kommodityv1alpha1.ClusterHealth](indexer, resource)}
+}
+
+func (l *clusterHealthLister) ClusterHealths(namespace string) ClusterHealthNamespaceLister {
+	return clusterHealthNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type clusterHealthNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterHealth]
+}
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterInstanceLister lists ClusterInstances in all namespaces.
+type ClusterInstanceLister interface {
+	// List lists all ClusterInstances in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterInstance, error)
+	// ClusterInstances returns a lister for the ClusterInstances in a namespace.
+	ClusterInstances(namespace string) ClusterInstanceNamespaceLister
+}
+
+// ClusterInstanceNamespaceLister lists and gets the ClusterInstances of a namespace.
+type ClusterInstanceNamespaceLister interface {
+	// List lists the ClusterInstances of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterInstance, error)
+	// Get retrieves a ClusterInstance of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.ClusterInstance, error)
+}
+
+type clusterInstanceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterInstance]
+}
+
+// NewClusterInstanceLister returns a ClusterInstanceLister reading from the indexer.
+func NewClusterInstanceLister(indexer cache.Indexer) ClusterInstanceLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("clusterinstances").GroupResource()
+
+	return &clusterInstanceLister{listers.New[*kommodityv1alpha1.ClusterInstance](indexer, resource)}
+}
+
+func (l *clusterInstanceLister) ClusterInstances(namespace string) ClusterInstanceNamespaceLister {
+	return clusterInstanceNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type clusterInstanceNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterInstance]
+}
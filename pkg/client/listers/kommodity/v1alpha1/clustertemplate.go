@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterTemplateLister lists ClusterTemplates in all namespaces.
+type ClusterTemplateLister interface {
+	// List lists all ClusterTemplates in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterTemplate, error)
+	// ClusterTemplates returns a lister for the ClusterTemplates in a namespace.
+	ClusterTemplates(namespace string) ClusterTemplateNamespaceLister
+}
+
+// ClusterTemplateNamespaceLister lists and gets the ClusterTemplates of a namespace.
+type ClusterTemplateNamespaceLister interface {
+	// List lists the ClusterTemplates of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ClusterTemplate, error)
+	// Get retrieves a ClusterTemplate of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.ClusterTemplate, error)
+}
+
+type clusterTemplateLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterTemplate]
+}
+
+// NewClusterTemplateLister returns a ClusterTemplateLister reading from the indexer.
+func NewClusterTemplateLister(indexer cache.Indexer) ClusterTemplateLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("clustertemplates").GroupResource()
+
+	return &clusterTemplateLister{listers.New[*kommodityv1alpha1.ClusterTemplate](indexer, resource)}
+}
+
+func (l *clusterTemplateLister) ClusterTemplates(namespace string) ClusterTemplateNamespaceLister {
+	return clusterTemplateNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type clusterTemplateNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ClusterTemplate]
+}
@@ -0,0 +1,3 @@
+// Package v1alpha1 contains the listers of the kommodity.io/v1alpha1 resources. Listers
+// read from the cache of an informer, the objects they return must be treated as read-only.
+package v1alpha1
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InfraClusterLister lists InfraClusters in all namespaces.
+type InfraClusterLister interface {
+	// List lists all InfraClusters in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.InfraCluster, error)
+	// InfraClusters returns a lister for the InfraClusters in a namespace.
+	InfraClusters(namespace string) InfraClusterNamespaceLister
+}
+
+// InfraClusterNamespaceLister lists and gets the InfraClusters of a namespace.
+type InfraClusterNamespaceLister interface {
+	// List lists the InfraClusters of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.InfraCluster, error)
+	// Get retrieves an InfraCluster of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.InfraCluster, error)
+}
+
+type infraClusterLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.InfraCluster]
+}
+
+// NewInfraClusterLister returns an InfraClusterLister reading from the indexer.
+func NewInfraClusterLister(indexer cache.Indexer) InfraClusterLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("infraclusters").GroupResource()
+
+	return &infraClusterLister{listers.New[*kommodityv1alpha1.InfraCluster](indexer, resource)}
+}
+
+func (l *infraClusterLister) InfraClusters(namespace string) InfraClusterNamespaceLister {
+	return infraClusterNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type infraClusterNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.InfraCluster]
+}
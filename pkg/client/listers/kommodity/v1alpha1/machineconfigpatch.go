@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MachineConfigPatchLister lists MachineConfigPatches in all namespaces.
+type MachineConfigPatchLister interface {
+	// List lists all MachineConfigPatches in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.MachineConfigPatch, error)
+	// MachineConfigPatches returns a lister for the MachineConfigPatches in a namespace.
+	MachineConfigPatches(namespace string) MachineConfigPatchNamespaceLister
+}
+
+// MachineConfigPatchNamespaceLister lists and gets the MachineConfigPatches of a namespace.
+type MachineConfigPatchNamespaceLister interface {
+	// List lists the MachineConfigPatches of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.MachineConfigPatch, error)
+	// Get retrieves a MachineConfigPatch of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.MachineConfigPatch, error)
+}
+
+type machineConfigPatchLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.MachineConfigPatch]
+}
+
+// NewMachineConfigPatchLister returns a MachineConfigPatchLister reading from the indexer.
+func NewMachineConfigPatchLister(indexer cache.Indexer) MachineConfigPatchLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("machineconfigpatches").GroupResource()
+
+	return &machineConfigPatchLister{listers.New[*kommodityv1alpha1.MachineConfigPatch](indexer, resource)}
+}
+
+func (l *machineConfigPatchLister) MachineConfigPatches(namespace string) MachineConfigPatchNamespaceLister {
+	return machineConfigPatchNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type machineConfigPatchNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.MachineConfigPatch]
+}
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ProviderCredentialLister lists ProviderCredentials in all namespaces.
+type ProviderCredentialLister interface {
+	// List lists all ProviderCredentials in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ProviderCredential, error)
+	// ProviderCredentials returns a lister for the ProviderCredentials in a namespace.
+	ProviderCredentials(namespace string) ProviderCredentialNamespaceLister
+}
+
+// ProviderCredentialNamespaceLister lists and gets the ProviderCredentials of a namespace.
+type ProviderCredentialNamespaceLister interface {
+	// List lists the ProviderCredentials of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.ProviderCredential, error)
+	// Get retrieves a ProviderCredential of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.ProviderCredential, error)
+}
+
+type providerCredentialLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ProviderCredential]
+}
+
+// NewProviderCredentialLister returns a ProviderCredentialLister reading from the indexer.
+func NewProviderCredentialLister(indexer cache.Indexer) ProviderCredentialLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("providercredentials").GroupResource()
+
+	return &providerCredentialLister{listers.New[*kommodityv1alpha1.ProviderCredential](indexer, resource)}
+}
+
+func (l *providerCredentialLister) ProviderCredentials(namespace string) ProviderCredentialNamespaceLister {
+	return providerCredentialNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type providerCredentialNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.ProviderCredential]
+}
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RegistryMirrorLister lists RegistryMirrors in all namespaces.
+type RegistryMirrorLister interface {
+	// List lists all RegistryMirrors in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.RegistryMirror, error)
+	// RegistryMirrors returns a lister for the RegistryMirrors in a namespace.
+	RegistryMirrors(namespace string) RegistryMirrorNamespaceLister
+}
+
+// RegistryMirrorNamespaceLister lists and gets the RegistryMirrors of a namespace.
+type RegistryMirrorNamespaceLister interface {
+	// List lists the RegistryMirrors of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.RegistryMirror, error)
+	// Get retrieves a RegistryMirror of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.RegistryMirror, error)
+}
+
+type registryMirrorLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.RegistryMirror]
+}
+
+// NewRegistryMirrorLister returns a RegistryMirrorLister reading from the indexer.
+func NewRegistryMirrorLister(indexer cache.Indexer) RegistryMirrorLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("registrymirrors").GroupResource()
+
+	return &registryMirrorLister{listers.New[*kommodityv1alpha1.RegistryMirror](indexer, resource)}
+}
+
+func (l *registryMirrorLister) RegistryMirrors(namespace string) RegistryMirrorNamespaceLister {
+	return registryMirrorNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type registryMirrorNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.RegistryMirror]
+}
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// TalosUpgradePlanLister lists TalosUpgradePlans in all namespaces.
+type TalosUpgradePlanLister interface {
+	// List lists all TalosUpgradePlans in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.TalosUpgradePlan, error)
+	// TalosUpgradePlans returns a lister for the TalosUpgradePlans in a namespace.
+	TalosUpgradePlans(namespace string) TalosUpgradePlanNamespaceLister
+}
+
+// TalosUpgradePlanNamespaceLister lists and gets the TalosUpgradePlans of a namespace.
+type TalosUpgradePlanNamespaceLister interface {
+	// List lists the TalosUpgradePlans of the namespace in the indexer.
+	List(selector labels.Selector) ([]*kommodityv1alpha1.TalosUpgradePlan, error)
+	// Get retrieves a TalosUpgradePlan of the namespace by name.
+	Get(name string) (*kommodityv1alpha1.TalosUpgradePlan, error)
+}
+
+type talosUpgradePlanLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.TalosUpgradePlan]
+}
+
+// NewTalosUpgradePlanLister returns a TalosUpgradePlanLister reading from the indexer.
+func NewTalosUpgradePlanLister(indexer cache.Indexer) TalosUpgradePlanLister {
+	resource := kommodityv1alpha1.GroupVersion.WithResource("talosupgradeplans").GroupResource()
+
+	return &talosUpgradePlanLister{listers.New[*kommodityv1alpha1.TalosUpgradePlan](indexer, resource)}
+}
+
+func (l *talosUpgradePlanLister) TalosUpgradePlans(namespace string) TalosUpgradePlanNamespaceLister {
+	return talosUpgradePlanNamespaceLister{listers.NewNamespaced(l.ResourceIndexer, namespace)}
+}
+
+type talosUpgradePlanNamespaceLister struct {
+	listers.ResourceIndexer[*kommodityv1alpha1.TalosUpgradePlan]
+}
@@ -0,0 +1,69 @@
+package combinedserver
+
+import (
+	"crypto/tls"
+	"net"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// acmeChallengePort is the port the CA connects to for TLS-ALPN-01 challenges.
+const acmeChallengePort = 443
+
+// ACMEConfig holds the configuration for obtaining the listener certificate via ACME.
+type ACMEConfig struct {
+	// Email is the contact address registered with the ACME account.
+	Email string
+	// Domains lists the host names the certificate is requested for. Requests
+	// for any other SNI name are refused.
+	Domains []string
+	// CacheDir is the directory where the account key and certificates are kept,
+	// so restarts do not re-issue certificates and hit the CA rate limits.
+	CacheDir string
+	// DirectoryURL overrides the ACME directory. Defaults to Let's Encrypt production.
+	DirectoryURL string
+}
+
+// Enabled reports whether ACME has been configured.
+func (c *ACMEConfig) Enabled() bool {
+	return c != nil && len(c.Domains) > 0
+}
+
+// newACMETLSConfig creates a TLS config that obtains and renews certificates through
+// ACME. Challenges are answered with TLS-ALPN-01 on the listener itself, so the
+// listener has to be reachable on port 443 for the configured domains.
+func newACMETLSConfig(config *ACMEConfig) (*tls.Config, error) {
+	if config.CacheDir == "" {
+		return nil, ErrACMECacheDirNotSet
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Email:      config.Email,
+		HostPolicy: autocert.HostWhitelist(config.Domains...),
+		Cache:      autocert.DirCache(config.CacheDir),
+	}
+
+	if config.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: config.DirectoryURL}
+	}
+
+	tlsConfig := manager.TLSConfig()
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	return tlsConfig, nil
+}
+
+// servesACMEChallengePort reports whether one of the listeners is a TCP listener on the
+// port of TLS-ALPN-01 challenges, such as a socket passed by systemd.
+func servesACMEChallengePort(listeners []net.Listener) bool {
+	for _, listener := range listeners {
+		address, ok := listener.Addr().(*net.TCPAddr)
+		if ok && address.Port == acmeChallengePort {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+package combinedserver
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+)
+
+// corsPreflightMaxAge is how long browsers may cache the answer to a preflight request.
+const corsPreflightMaxAge = 10 * time.Minute
+
+// CORSConfig holds the cross-origin resource sharing policy of the HTTP endpoints.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the endpoints, "*" allows any.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in cross-origin requests.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in cross-origin requests, "*"
+	// allows any.
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and the Authorization header along.
+	AllowCredentials bool
+}
+
+// Enabled reports whether CORS has been configured.
+func (c *CORSConfig) Enabled() bool {
+	return c != nil && len(c.AllowedOrigins) > 0
+}
+
+func (c *CORSConfig) allowsOrigin(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+func (c *CORSConfig) allowsMethod(method string) bool {
+	return slices.Contains(c.AllowedMethods, method)
+}
+
+// allowsHeaders reports whether all headers of a comma-separated list are allowed.
+// Header names are case-insensitive.
+func (c *CORSConfig) allowsHeaders(headers string) bool {
+	if slices.Contains(c.AllowedHeaders, "*") {
+		return true
+	}
+
+	for header := range strings.SplitSeq(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		if !slices.ContainsFunc(c.AllowedHeaders, func(allowed string) bool {
+			return strings.EqualFold(allowed, header)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withCORS lets browsers call the HTTP endpoints from the allowed origins. Preflight
+// requests are answered here and never reach the handlers, as browsers send them
+// without credentials. Requests without an Origin header, or from an origin that is
+// not allowed, are served without CORS headers, so browsers withhold the response.
+func withCORS(config *CORSConfig, handler http.Handler) http.Handler {
+	if !config.Enabled() {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && requestMethod != "" {
+			servePreflight(config, w, r, origin, requestMethod)
+
+			return
+		}
+
+		if config.allowsOrigin(origin) {
+			setAllowOrigin(config, w, origin)
+			w.Header().Set("Access-Control-Expose-Headers", logging.RequestIDHeader)
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// servePreflight answers a preflight request, rejecting it with 403 unless the
+// origin, the method and all headers of the actual request are allowed.
+func servePreflight(config *CORSConfig, w http.ResponseWriter, r *http.Request, origin, requestMethod string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	requestHeaders := r.Header.Get("Access-Control-Request-Headers")
+
+	if !config.allowsOrigin(origin) || !config.allowsMethod(requestMethod) || !config.allowsHeaders(requestHeaders) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	setAllowOrigin(config, w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+
+	if requestHeaders != "" {
+		// The requested headers were checked above, echoing them also covers "*",
+		// which browsers do not accept as a wildcard when credentials are allowed.
+		w.Header().Set("Access-Control-Allow-Headers", requestHeaders)
+	}
+
+	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(corsPreflightMaxAge.Seconds())))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setAllowOrigin allows the origin of the request. The origin is echoed rather than
+// answered with "*" whenever credentials are allowed, as browsers require.
+func setAllowOrigin(config *CORSConfig, w http.ResponseWriter, origin string) {
+	if config.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+
+		return
+	}
+
+	if slices.Contains(config.AllowedOrigins, "*") {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+}
@@ -0,0 +1,142 @@
+package combinedserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+)
+
+func serveCORS(t *testing.T, config *combinedserver.CORSConfig, req *http.Request) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+
+	served := false
+	handler := combinedserver.WithCORS(config, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		served = true
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder, served
+}
+
+func newCORSRequest(t *testing.T, method, origin string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(t.Context(), method, "/nonce", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+
+	return req
+}
+
+func TestCORSAllowsConfiguredOrigins(t *testing.T) {
+	t.Parallel()
+
+	config := &combinedserver.CORSConfig{
+		AllowedOrigins: []string{"https://ui.example.com"},
+		AllowedMethods: []string{http.MethodGet},
+	}
+
+	resp, served := serveCORS(t, config, newCORSRequest(t, http.MethodGet, "https://ui.example.com"))
+	if !served {
+		t.Fatal("expected the request to be served")
+	}
+
+	if got := resp.Header().Get("Access-Control-Allow-Origin"); got != "https://ui.example.com" {
+		t.Fatalf("expected the origin to be allowed, got %q", got)
+	}
+
+	resp, served = serveCORS(t, config, newCORSRequest(t, http.MethodGet, "https://evil.example.com"))
+	if !served {
+		t.Fatal("expected the request from another origin to be served")
+	}
+
+	if got := resp.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no CORS headers for another origin, got %q", got)
+	}
+}
+
+func TestCORSAnswersPreflights(t *testing.T) {
+	t.Parallel()
+
+	config := &combinedserver.CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		headers string
+		status  int
+	}{
+		{name: "allowed", method: http.MethodPost, headers: "content-type, authorization", status: http.StatusNoContent},
+		{name: "method not allowed", method: http.MethodDelete, status: http.StatusForbidden},
+		{name: "header not allowed", method: http.MethodGet, headers: "X-Custom", status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := newCORSRequest(t, http.MethodOptions, "https://ui.example.com")
+			req.Header.Set("Access-Control-Request-Method", test.method)
+
+			if test.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", test.headers)
+			}
+
+			resp, served := serveCORS(t, config, req)
+			if served {
+				t.Fatal("expected the preflight not to reach the handler")
+			}
+
+			if resp.Code != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, resp.Code)
+			}
+		})
+	}
+}
+
+func TestCORSEchoesOriginWithCredentials(t *testing.T) {
+	t.Parallel()
+
+	config := &combinedserver.CORSConfig{
+		AllowedOrigins:   []string{"https://ui.example.com"},
+		AllowedMethods:   []string{http.MethodGet},
+		AllowCredentials: true,
+	}
+
+	resp, _ := serveCORS(t, config, newCORSRequest(t, http.MethodGet, "https://ui.example.com"))
+
+	if got := resp.Header().Get("Access-Control-Allow-Origin"); got != "https://ui.example.com" {
+		t.Fatalf("expected the origin to be echoed, got %q", got)
+	}
+
+	if got := resp.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("expected credentials to be allowed, got %q", got)
+	}
+}
+
+func TestCORSDisabledWithoutOrigins(t *testing.T) {
+	t.Parallel()
+
+	req := newCORSRequest(t, http.MethodOptions, "https://ui.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	resp, served := serveCORS(t, &combinedserver.CORSConfig{}, req)
+	if !served {
+		t.Fatal("expected the request to reach the handler when CORS is disabled")
+	}
+
+	if got := resp.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no CORS headers, got %q", got)
+	}
+}
@@ -7,4 +7,18 @@ var (
 	ErrServerNotRunning = errors.New("server is not running")
 	// ErrAPIServerNotReady is returned when the internal Kubernetes API server is not ready.
 	ErrAPIServerNotReady = errors.New("apiserver is not ready")
+	// ErrInvalidHealthCheck is returned when a health check without a name or function is registered.
+	ErrInvalidHealthCheck = errors.New("invalid health check")
+	// ErrDuplicateHealthCheck is returned when a health check with an already registered name is registered.
+	ErrDuplicateHealthCheck = errors.New("health check already registered")
+	// ErrACMECacheDirNotSet is returned when ACME is enabled without a certificate cache directory.
+	ErrACMECacheDirNotSet = errors.New("ACME cache directory is not set")
+	// ErrCertificateWithACME is returned when both ACME and GetCertificate provide the certificate of the listener.
+	ErrCertificateWithACME = errors.New("ACME cannot be combined with another certificate source")
+	// ErrClientCAsWithoutTLS is returned when client certificates are requested on a listener without TLS.
+	ErrClientCAsWithoutTLS = errors.New("client certificates require TLS")
+	// ErrUnsupportedListenAddress is returned for a listen address that is neither a unix socket nor fd://.
+	ErrUnsupportedListenAddress = errors.New("unsupported listen address")
+	// ErrNoSystemdSockets is returned when systemd passed no socket matching a fd:// listen address.
+	ErrNoSystemdSockets = errors.New("no matching socket passed by systemd")
 )
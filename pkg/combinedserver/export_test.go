@@ -0,0 +1,14 @@
+package combinedserver
+
+// Exported aliases for black-box testing.
+//
+//nolint:gochecknoglobals // test exports
+var (
+	RegisterHealthChecks      = registerHealthChecks
+	WithRequestLogging        = withRequestLogging
+	WithCORS                  = withCORS
+	ApplyTLS                  = (*TLSConfig).apply
+	RequestClientCertificates = requestClientCertificates
+	OnlyClientCAChains        = onlyClientCAChains
+	ServesACMEChallengePort   = servesACMEChallengePort
+)
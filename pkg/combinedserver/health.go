@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,12 +29,15 @@ const (
 	checkOKPrefix     = "[+]"
 	checkFailedPrefix = "[-]"
 
-	// individualCheckPathSegments is the expected number of path segments for individual check requests.
-	// For example, /livez/ping has 2 segments: ["livez", "ping"].
+	// individualCheckPathSegments is the minimum number of path segments for individual check requests.
+	// For example, /livez/ping has 2 segments: ["livez", "ping"], /readyz/apiserver/etcd has 3.
 	individualCheckPathSegments = 2
 
 	// apiServerHealthCheckTimeout is the timeout for API server health check requests.
 	apiServerHealthCheckTimeout = 5 * time.Second
+
+	// maxAPIServerHealthBodySize bounds how much of the API server health output is read.
+	maxAPIServerHealthBodySize = 64 * 1024
 )
 
 // HealthCheckConfig holds configuration for health checks.
@@ -70,6 +75,32 @@ func (r *healthCheckRegistry) register(check HealthChecker) {
 	r.checks = append(r.checks, check)
 }
 
+// registerUnique adds a health check to the registry, rejecting duplicate names.
+func (r *healthCheckRegistry) registerUnique(check HealthChecker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.checks {
+		if existing.Name() == check.Name() {
+			return fmt.Errorf("%w: %s", ErrDuplicateHealthCheck, check.Name())
+		}
+	}
+
+	r.checks = append(r.checks, check)
+
+	return nil
+}
+
+// unregister removes the health check with the given name from the registry.
+func (r *healthCheckRegistry) unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = slices.DeleteFunc(r.checks, func(check HealthChecker) bool {
+		return check.Name() == name
+	})
+}
+
 // getChecks returns a copy of all registered health checks.
 func (r *healthCheckRegistry) getChecks() []HealthChecker {
 	r.mu.RLock()
@@ -148,7 +179,46 @@ func (a *apiServerHealthCheck) Name() string {
 
 // Check verifies the internal Kubernetes API server is ready by calling its /readyz endpoint.
 func (a *apiServerHealthCheck) Check() error {
-	url := "https://localhost:" + strconv.Itoa(a.apiServerPort) + "/readyz"
+	statusCode, _, err := a.getReadyz(false)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("%w: status code %d", ErrAPIServerNotReady, statusCode)
+	}
+
+	return nil
+}
+
+// Expand queries the verbose /readyz endpoint of the API server and returns one
+// check per reported item (etcd, informer-sync, poststarthook/..., etc.), each
+// prefixed with "apiserver/". If the API server cannot be reached, or its output
+// cannot be parsed, a single "apiserver" check carrying the failure is returned.
+func (a *apiServerHealthCheck) Expand() []HealthChecker {
+	statusCode, body, err := a.getReadyz(true)
+	if err != nil {
+		return []HealthChecker{&staticHealthCheck{name: a.Name(), err: err}}
+	}
+
+	checks := parseVerboseHealthOutput(a.Name(), body)
+	if len(checks) > 0 {
+		return checks
+	}
+
+	if statusCode != http.StatusOK {
+		err = fmt.Errorf("%w: status code %d", ErrAPIServerNotReady, statusCode)
+	}
+
+	return []HealthChecker{&staticHealthCheck{name: a.Name(), err: err}}
+}
+
+// getReadyz calls the API server /readyz endpoint and returns the status code and body.
+func (a *apiServerHealthCheck) getReadyz(verbose bool) (int, string, error) {
+	url := "https://localhost:" + strconv.Itoa(a.apiServerPort) + ReadyzPath
+	if verbose {
+		url += "?" + verboseParam
+	}
 
 	req, err := http.NewRequestWithContext(
 		context.Background(),
@@ -157,42 +227,102 @@ func (a *apiServerHealthCheck) Check() error {
 		nil,
 	)
 	if err != nil {
-		return fmt.Errorf("%w: failed to create request: %w", ErrAPIServerNotReady, err)
+		return 0, "", fmt.Errorf("%w: failed to create request: %w", ErrAPIServerNotReady, err)
 	}
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrAPIServerNotReady, err)
+		return 0, "", fmt.Errorf("%w: %w", ErrAPIServerNotReady, err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: status code %d", ErrAPIServerNotReady, resp.StatusCode)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIServerHealthBodySize))
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: failed to read response: %w", ErrAPIServerNotReady, err)
 	}
 
-	return nil
+	return resp.StatusCode, string(body), nil
+}
+
+// parseVerboseHealthOutput converts the verbose output of a Kubernetes health
+// endpoint ("[+]etcd ok", "[-]poststarthook/foo failed: reason") into static checks
+// whose names are prefixed with the given prefix.
+func parseVerboseHealthOutput(prefix string, body string) []HealthChecker {
+	var checks []HealthChecker
+
+	for line := range strings.Lines(body) {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, checkOKPrefix):
+			name, _, _ := strings.Cut(strings.TrimPrefix(line, checkOKPrefix), " ")
+			checks = append(checks, &staticHealthCheck{name: prefix + "/" + name})
+		case strings.HasPrefix(line, checkFailedPrefix):
+			name, reason, _ := strings.Cut(strings.TrimPrefix(line, checkFailedPrefix), " failed: ")
+			name, _, _ = strings.Cut(name, " ")
+			checks = append(checks, &staticHealthCheck{
+				name: prefix + "/" + name,
+				err:  fmt.Errorf("%w: %s: %s", ErrAPIServerNotReady, name, reason),
+			})
+		}
+	}
+
+	return checks
+}
+
+// expandableHealthChecker is implemented by health checks that can report their
+// result as several finer grained checks in aggregated output.
+type expandableHealthChecker interface {
+	HealthChecker
+	// Expand returns the checks that replace this check in aggregated output.
+	Expand() []HealthChecker
+}
+
+// staticHealthCheck is a health check with a precomputed result.
+type staticHealthCheck struct {
+	name string
+	err  error
+}
+
+func (s *staticHealthCheck) Name() string {
+	return s.name
+}
+
+func (s *staticHealthCheck) Check() error {
+	return s.err
 }
 
 // healthHandler handles health check requests with support for verbose and exclude parameters.
 type healthHandler struct {
-	registry *healthCheckRegistry
-	name     string
+	registries []*healthCheckRegistry
+	name       string
 }
 
-// newHealthHandler creates a new health handler.
-func newHealthHandler(registry *healthCheckRegistry, name string) *healthHandler {
+// newHealthHandler creates a new health handler serving the checks of all given registries.
+func newHealthHandler(name string, registries ...*healthCheckRegistry) *healthHandler {
 	return &healthHandler{
-		registry: registry,
-		name:     name,
+		registries: registries,
+		name:       name,
 	}
 }
 
+// getChecks returns the checks of all registries in registration order.
+func (h *healthHandler) getChecks() []HealthChecker {
+	var checks []HealthChecker
+
+	for _, registry := range h.registries {
+		checks = append(checks, registry.getChecks()...)
+	}
+
+	return checks
+}
+
 // ServeHTTP handles health check HTTP requests.
 func (h *healthHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	// Check for individual health check path (e.g., /livez/ping)
-	pathParts := strings.Split(strings.TrimPrefix(request.URL.Path, "/"), "/")
-	if len(pathParts) == individualCheckPathSegments {
+	pathParts := strings.SplitN(strings.TrimPrefix(request.URL.Path, "/"), "/", individualCheckPathSegments)
+	if len(pathParts) == individualCheckPathSegments && pathParts[1] != "" {
 		h.handleIndividualCheck(writer, pathParts[1])
 
 		return
@@ -210,7 +340,7 @@ func (h *healthHandler) ServeHTTP(writer http.ResponseWriter, request *http.Requ
 
 // handleIndividualCheck handles requests for a specific health check.
 func (h *healthHandler) handleIndividualCheck(writer http.ResponseWriter, checkName string) {
-	checks := h.registry.getChecks()
+	checks := h.getChecks()
 
 	for _, check := range checks {
 		if check.Name() == checkName {
@@ -220,6 +350,22 @@ func (h *healthHandler) handleIndividualCheck(writer http.ResponseWriter, checkN
 		}
 	}
 
+	// Sub-checks of expandable checks, e.g. /readyz/apiserver/etcd.
+	for _, check := range checks {
+		expandable, ok := check.(expandableHealthChecker)
+		if !ok || !strings.HasPrefix(checkName, check.Name()+"/") {
+			continue
+		}
+
+		for _, subCheck := range expandable.Expand() {
+			if subCheck.Name() == checkName {
+				h.writeCheckResult(writer, subCheck)
+
+				return
+			}
+		}
+	}
+
 	http.NotFound(writer, nil)
 }
 
@@ -243,7 +389,7 @@ func (h *healthHandler) handleAggregatedCheck(
 	verbose bool,
 	excludes map[string]bool,
 ) {
-	checks := h.registry.getChecks()
+	checks := h.getChecks()
 	result := h.runAllChecks(checks, excludes, verbose)
 
 	h.writeAggregatedResponse(writer, result, verbose)
@@ -264,7 +410,16 @@ func (h *healthHandler) runAllChecks(
 	result := checkResult{allHealthy: true}
 
 	for _, check := range checks {
-		h.runSingleCheck(check, excludes, verbose, &result)
+		expandable, ok := check.(expandableHealthChecker)
+		if !ok || excludes[check.Name()] {
+			h.runSingleCheck(check, excludes, verbose, &result)
+
+			continue
+		}
+
+		for _, subCheck := range expandable.Expand() {
+			h.runSingleCheck(subCheck, excludes, verbose, &result)
+		}
 	}
 
 	if verbose {
@@ -358,14 +513,15 @@ func registerHealthChecks(
 	// Register always-healthy check for liveness
 	livezRegistry.register(&alwaysHealthyCheck{})
 
-	// Register readiness checks: always-healthy, ping (server state), and apiserver
+	// Register readiness checks: always-healthy, ping (server state), and apiserver.
+	// Checks added through RegisterReadinessCheck are appended after these.
 	readyzRegistry.register(&alwaysHealthyCheck{})
 	readyzRegistry.register(newPingHealthCheck(stateTracker))
 	readyzRegistry.register(newAPIServerHealthCheck(config.APIServerPort))
 
 	// Create handlers
-	livezHandler := newHealthHandler(livezRegistry, "livez")
-	readyzHandler := newHealthHandler(readyzRegistry, "readyz")
+	livezHandler := newHealthHandler("livez", livezRegistry)
+	readyzHandler := newHealthHandler("readyz", readyzRegistry, externalReadinessChecks)
 
 	// Register endpoints
 	// /livez and /livez/{checkName}
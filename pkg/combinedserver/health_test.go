@@ -0,0 +1,110 @@
+package combinedserver_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+)
+
+const apiServerVerboseReadyz = `[+]ping ok
+[+]log ok
+[-]etcd failed: reason withheld
+[+]poststarthook/start-apiextensions-informers ok
+readyz check failed
+`
+
+func newFakeAPIServer(t *testing.T) int {
+	t.Helper()
+
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprint(writer, apiServerVerboseReadyz)
+	}))
+	t.Cleanup(apiServer.Close)
+
+	_, port, err := net.SplitHostPort(apiServer.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse fake API server address: %v", err)
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("failed to parse fake API server port: %v", err)
+	}
+
+	return portNumber
+}
+
+func newMuxWithAPIServer(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	tracker := combinedserver.NewServerStateTracker()
+	tracker.SetState(combinedserver.ServerStateRunning)
+
+	combinedserver.RegisterHealthChecks(mux, tracker, combinedserver.HealthCheckConfig{
+		APIServerPort: newFakeAPIServer(t),
+	})
+
+	return mux
+}
+
+func TestReadyzIncludesAPIServerChecks(t *testing.T) {
+	t.Parallel()
+
+	recorder := serve(newMuxWithAPIServer(t), "/readyz?verbose")
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		"[+]ping ok",
+		"[+]apiserver/ping ok",
+		"[-]apiserver/etcd failed",
+		"[+]apiserver/poststarthook/start-apiextensions-informers ok",
+		"readyz check failed",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("expected %q in body: %q", expected, body)
+		}
+	}
+}
+
+func TestReadyzExcludesSingleAPIServerCheck(t *testing.T) {
+	t.Parallel()
+
+	recorder := serve(newMuxWithAPIServer(t), "/readyz?exclude=apiserver/etcd")
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %q", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestReadyzServesIndividualAPIServerCheck(t *testing.T) {
+	t.Parallel()
+
+	mux := newMuxWithAPIServer(t)
+
+	recorder := serve(mux, "/readyz/apiserver/log")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	recorder = serve(mux, "/readyz/apiserver/etcd")
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+
+	recorder = serve(mux, "/readyz/apiserver/unknown")
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package combinedserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// unixListenPrefix prefixes the path of a unix domain socket to listen on.
+	unixListenPrefix = "unix://"
+	// systemdListenPrefix selects the sockets passed by systemd socket activation,
+	// optionally followed by the FileDescriptorName= of the socket unit.
+	systemdListenPrefix = "fd://"
+
+	// systemdListenFDsStart is the first file descriptor passed by systemd, following
+	// stdin, stdout and stderr.
+	systemdListenFDsStart = 3
+)
+
+// listen opens a listener on the TCP port of each bind address, or of all interfaces
+// when none are given, and on each of the extra listen addresses. The listeners opened
+// so far are closed on error.
+func listen(ctx context.Context, port int, bindAddresses []net.IP, addresses []string) ([]net.Listener, error) {
+	var listenConfig net.ListenConfig
+
+	listeners, err := listenTCP(ctx, &listenConfig, port, bindAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	var activated *systemdSockets
+
+	for _, address := range addresses {
+		var opened []net.Listener
+
+		switch {
+		case strings.HasPrefix(address, unixListenPrefix):
+			var listener net.Listener
+
+			listener, err = listenUnix(ctx, &listenConfig, strings.TrimPrefix(address, unixListenPrefix))
+			opened = []net.Listener{listener}
+		case strings.HasPrefix(address, systemdListenPrefix):
+			if activated == nil {
+				activated = newSystemdSockets()
+			}
+
+			opened, err = activated.listeners(strings.TrimPrefix(address, systemdListenPrefix))
+		default:
+			err = fmt.Errorf("%w: %q", ErrUnsupportedListenAddress, address)
+		}
+
+		if err != nil {
+			closeListeners(listeners)
+
+			return nil, err
+		}
+
+		listeners = append(listeners, opened...)
+	}
+
+	return listeners, nil
+}
+
+// listenTCP listens on the port of each bind address. Listening on all interfaces
+// accepts both IPv4 and IPv6 connections.
+func listenTCP(ctx context.Context, listenConfig *net.ListenConfig, port int,
+	bindAddresses []net.IP) ([]net.Listener, error) {
+	hosts := []string{""}
+
+	if len(bindAddresses) > 0 {
+		hosts = make([]string, 0, len(bindAddresses))
+		for _, address := range bindAddresses {
+			hosts = append(hosts, address.String())
+		}
+	}
+
+	listeners := make([]net.Listener, 0, len(hosts))
+
+	for _, host := range hosts {
+		address := net.JoinHostPort(host, strconv.Itoa(port))
+
+		listener, err := listenConfig.Listen(ctx, "tcp", address)
+		if err != nil {
+			closeListeners(listeners)
+
+			return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// listenUnix listens on a unix domain socket. A socket left behind by a previous
+// process that did not shut down cleanly is removed first.
+func listenUnix(ctx context.Context, listenConfig *net.ListenConfig, path string) (net.Listener, error) {
+	info, err := os.Lstat(path)
+	if err == nil && info.Mode()&os.ModeSocket != 0 {
+		err = os.Remove(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	listener, err := listenConfig.Listen(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on socket %s: %w", path, err)
+	}
+
+	return listener, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+}
+
+// systemdSockets holds the sockets passed by systemd socket activation, see
+// sd_listen_fds(3). Each socket can be taken once.
+type systemdSockets struct {
+	names []string
+	files []*os.File
+}
+
+// newSystemdSockets takes the sockets passed to this process. The environment
+// variables are unset, so that child processes do not take them as well.
+func newSystemdSockets() *systemdSockets {
+	defer func() {
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return &systemdSockets{}
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return &systemdSockets{}
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	sockets := &systemdSockets{
+		names: make([]string, count),
+		files: make([]*os.File, count),
+	}
+
+	for i := range count {
+		// systemd names unnamed sockets "unknown".
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		sockets.names[i] = name
+		sockets.files[i] = os.NewFile(uintptr(systemdListenFDsStart+i), name)
+	}
+
+	return sockets
+}
+
+// listeners returns listeners for the sockets with the given name, or for all
+// remaining sockets when the name is empty.
+func (s *systemdSockets) listeners(name string) ([]net.Listener, error) {
+	var listeners []net.Listener
+
+	for i, file := range s.files {
+		if file == nil || (name != "" && s.names[i] != name) {
+			continue
+		}
+
+		s.files[i] = nil
+
+		// The listener is backed by a duplicate of the file descriptor.
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+
+		if err != nil {
+			closeListeners(listeners)
+
+			return nil, fmt.Errorf("failed to listen on systemd socket %s: %w", s.names[i], err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrNoSystemdSockets, systemdListenPrefix+name)
+	}
+
+	return listeners, nil
+}
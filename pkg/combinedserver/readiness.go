@@ -0,0 +1,78 @@
+package combinedserver
+
+import (
+	"fmt"
+)
+
+// ReadinessCheckFunc is a function that reports whether a subsystem is ready.
+// It returns nil when the subsystem is ready and a descriptive error otherwise.
+type ReadinessCheckFunc func() error
+
+// readinessCheck adapts a ReadinessCheckFunc to the HealthChecker interface.
+type readinessCheck struct {
+	name  string
+	check ReadinessCheckFunc
+}
+
+func (r *readinessCheck) Name() string {
+	return r.name
+}
+
+func (r *readinessCheck) Check() error {
+	return r.check()
+}
+
+// externalReadinessChecks holds readiness checks registered by subsystems outside
+// of the combined server. They are evaluated on every /readyz request, so checks
+// registered after the server started are picked up without a restart.
+//
+//nolint:gochecknoglobals // process-wide registry shared by all subsystems.
+var externalReadinessChecks = newHealthCheckRegistry()
+
+// RegisterReadinessCheck registers a named readiness check that is evaluated as
+// part of /readyz and exposed individually as /readyz/<name>. Names must be unique
+// and must not collide with the built-in checks.
+func RegisterReadinessCheck(name string, check ReadinessCheckFunc) error {
+	return RegisterReadinessChecker(&readinessCheck{
+		name:  name,
+		check: check,
+	})
+}
+
+// RegisterReadinessChecker registers a HealthChecker implementation as a readiness check.
+func RegisterReadinessChecker(checker HealthChecker) error {
+	if checker == nil || checker.Name() == "" {
+		return ErrInvalidHealthCheck
+	}
+
+	if adapter, ok := checker.(*readinessCheck); ok && adapter.check == nil {
+		return fmt.Errorf("%w: %s has no check function", ErrInvalidHealthCheck, checker.Name())
+	}
+
+	if isBuiltinReadinessCheck(checker.Name()) {
+		return fmt.Errorf("%w: %s", ErrDuplicateHealthCheck, checker.Name())
+	}
+
+	err := externalReadinessChecks.registerUnique(checker)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnregisterReadinessCheck removes a previously registered readiness check.
+// It is a no-op when no check with the given name exists.
+func UnregisterReadinessCheck(name string) {
+	externalReadinessChecks.unregister(name)
+}
+
+// isBuiltinReadinessCheck reports whether the name is reserved by the combined server.
+func isBuiltinReadinessCheck(name string) bool {
+	switch name {
+	case "healthy", "ping", "apiserver":
+		return true
+	default:
+		return false
+	}
+}
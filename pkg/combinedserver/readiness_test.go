@@ -0,0 +1,100 @@
+//nolint:paralleltest // readiness checks are registered in a process-wide registry.
+package combinedserver_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+)
+
+var errNotReady = errors.New("not ready")
+
+func newReadyzMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	tracker := combinedserver.NewServerStateTracker()
+	tracker.SetState(combinedserver.ServerStateRunning)
+
+	combinedserver.RegisterHealthChecks(mux, tracker, combinedserver.HealthCheckConfig{})
+
+	return mux
+}
+
+func serve(mux *http.ServeMux, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return recorder
+}
+
+func TestRegisterReadinessCheckIsServedIndividually(t *testing.T) {
+	err := combinedserver.RegisterReadinessCheck("test-ok", func() error { return nil })
+	if err != nil {
+		t.Fatalf("failed to register readiness check: %v", err)
+	}
+
+	t.Cleanup(func() { combinedserver.UnregisterReadinessCheck("test-ok") })
+
+	recorder := serve(newReadyzMux(), "/readyz/test-ok")
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	if !strings.Contains(recorder.Body.String(), "[+]test-ok ok") {
+		t.Fatalf("unexpected body: %q", recorder.Body.String())
+	}
+}
+
+func TestRegisterReadinessCheckFailureFailsReadyz(t *testing.T) {
+	err := combinedserver.RegisterReadinessCheck("test-failing", func() error { return errNotReady })
+	if err != nil {
+		t.Fatalf("failed to register readiness check: %v", err)
+	}
+
+	t.Cleanup(func() { combinedserver.UnregisterReadinessCheck("test-failing") })
+
+	recorder := serve(newReadyzMux(), "/readyz?verbose&exclude=apiserver")
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+
+	if !strings.Contains(recorder.Body.String(), "[-]test-failing failed: not ready") {
+		t.Fatalf("unexpected body: %q", recorder.Body.String())
+	}
+}
+
+func TestRegisterReadinessCheckRejectsDuplicates(t *testing.T) {
+	err := combinedserver.RegisterReadinessCheck("test-duplicate", func() error { return nil })
+	if err != nil {
+		t.Fatalf("failed to register readiness check: %v", err)
+	}
+
+	t.Cleanup(func() { combinedserver.UnregisterReadinessCheck("test-duplicate") })
+
+	err = combinedserver.RegisterReadinessCheck("test-duplicate", func() error { return nil })
+	if !errors.Is(err, combinedserver.ErrDuplicateHealthCheck) {
+		t.Fatalf("expected ErrDuplicateHealthCheck, got %v", err)
+	}
+
+	err = combinedserver.RegisterReadinessCheck("apiserver", func() error { return nil })
+	if !errors.Is(err, combinedserver.ErrDuplicateHealthCheck) {
+		t.Fatalf("expected ErrDuplicateHealthCheck for built-in name, got %v", err)
+	}
+}
+
+func TestRegisterReadinessCheckRejectsInvalidChecks(t *testing.T) {
+	err := combinedserver.RegisterReadinessCheck("", func() error { return nil })
+	if !errors.Is(err, combinedserver.ErrInvalidHealthCheck) {
+		t.Fatalf("expected ErrInvalidHealthCheck for empty name, got %v", err)
+	}
+
+	err = combinedserver.RegisterReadinessCheck("test-nil", nil)
+	if !errors.Is(err, combinedserver.ErrInvalidHealthCheck) {
+		t.Fatalf("expected ErrInvalidHealthCheck for nil function, got %v", err)
+	}
+}
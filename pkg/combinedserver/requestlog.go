@@ -0,0 +1,129 @@
+package combinedserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"k8s.io/apiserver/pkg/endpoints/responsewriter"
+)
+
+// withRequestLogging assigns every HTTP request an ID, passes a logger carrying it to
+// the handlers through the request context and logs the request once it was served.
+// The ID is forwarded to the API server in the request header, so that its logs can
+// be correlated as well.
+func withRequestLogging(logger *zap.Logger, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := logging.RequestID(r.Header.Get(logging.RequestIDHeader))
+		r.Header.Set(logging.RequestIDHeader, requestID)
+		w.Header().Set(logging.RequestIDHeader, requestID)
+
+		ctx := logging.WithRequestID(logging.WithLogger(r.Context(), logger), requestID)
+		recorder := logging.NewResponseRecorder(w)
+
+		handler.ServeHTTP(responsewriter.WrapForHTTP1Or2(recorder), r.WithContext(ctx))
+
+		logging.FromContext(ctx).Log(requestLogLevel(r.URL.Path, recorder.Status()), "Served HTTP request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", recorder.Status()),
+			zap.Int("size", recorder.Size()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("remoteAddr", r.RemoteAddr))
+	})
+}
+
+// requestLogLevel keeps successful health probes, which are polled continuously, out
+// of the request log unless debug logging is enabled.
+func requestLogLevel(path string, status int) zapcore.Level {
+	for _, healthPath := range []string{HealthzPath, LivezPath, ReadyzPath} {
+		if status < http.StatusBadRequest && (path == healthPath || strings.HasPrefix(path, healthPath+"/")) {
+			return zapcore.DebugLevel
+		}
+	}
+
+	return zapcore.InfoLevel
+}
+
+// requestLoggingServerOptions returns the gRPC counterpart of withRequestLogging. The
+// request ID is taken from, or added to, the incoming metadata of the call.
+func requestLoggingServerOptions(logger *zap.Logger) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler) (any, error) {
+			start := time.Now()
+			ctx = withCallRequestID(ctx, logger)
+
+			resp, err := handler(ctx, req)
+
+			logCall(ctx, info.FullMethod, start, err)
+
+			return resp, err
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+			handler grpc.StreamHandler) error {
+			start := time.Now()
+			ctx := withCallRequestID(stream.Context(), logger)
+
+			err := handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+
+			logCall(ctx, info.FullMethod, start, err)
+
+			return err
+		}),
+	}
+}
+
+// withCallRequestID adds the logger and the request ID of the call to the context.
+func withCallRequestID(ctx context.Context, logger *zap.Logger) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+
+	var given string
+	if values := md.Get(logging.RequestIDMetadataKey); len(values) > 0 {
+		given = values[0]
+	}
+
+	requestID := logging.RequestID(given)
+	md.Set(logging.RequestIDMetadataKey, requestID)
+
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	return logging.WithRequestID(logging.WithLogger(ctx, logger), requestID)
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("latency", time.Since(start)),
+	}
+
+	if callPeer, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, zap.String("remoteAddr", callPeer.Addr.String()))
+	}
+
+	logging.FromContext(ctx).Info("Served gRPC call", fields...)
+}
+
+// contextServerStream replaces the context of a server stream.
+type contextServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx // The stream context is replaced, not stored.
+}
+
+// Context returns the replaced context.
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,96 @@
+package combinedserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func serveLogged(
+	t *testing.T, handler http.HandlerFunc, path, requestID string,
+) (*http.Response, *observer.ObservedLogs) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	server := httptest.NewServer(combinedserver.WithRequestLogging(zap.New(core), handler))
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	if requestID != "" {
+		req.Header.Set(logging.RequestIDHeader, requestID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	return resp, logs
+}
+
+func TestRequestLoggingAssignsRequestID(t *testing.T) {
+	t.Parallel()
+
+	var handlerRequestID string
+
+	resp, logs := serveLogged(t, func(writer http.ResponseWriter, req *http.Request) {
+		handlerRequestID, _ = logging.RequestIDFromContext(req.Context())
+
+		// Streaming handlers rely on the response being flushable.
+		if _, ok := writer.(http.Flusher); !ok {
+			t.Errorf("expected the response writer to be flushable")
+		}
+
+		writer.WriteHeader(http.StatusTeapot)
+	}, "/machines", "")
+
+	requestID := resp.Header.Get(logging.RequestIDHeader)
+	if requestID == "" || requestID != handlerRequestID {
+		t.Fatalf("expected the request ID %q to be passed to the handler, got %q", requestID, handlerRequestID)
+	}
+
+	entries := logs.FilterMessage("Served HTTP request").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one request log, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if entries[0].Level != zapcore.InfoLevel || fields["requestID"] != requestID ||
+		fields["status"] != int64(http.StatusTeapot) || fields["path"] != "/machines" {
+		t.Fatalf("unexpected request log %v: %v", entries[0].Level, fields)
+	}
+}
+
+func TestRequestLoggingKeepsGivenRequestID(t *testing.T) {
+	t.Parallel()
+
+	resp, _ := serveLogged(t, func(http.ResponseWriter, *http.Request) {}, "/", "upstream-id")
+
+	if requestID := resp.Header.Get(logging.RequestIDHeader); requestID != "upstream-id" {
+		t.Fatalf("expected the given request ID to be kept, got %q", requestID)
+	}
+}
+
+func TestRequestLoggingLogsHealthProbesAtDebug(t *testing.T) {
+	t.Parallel()
+
+	_, logs := serveLogged(t, func(http.ResponseWriter, *http.Request) {}, combinedserver.ReadyzPath, "")
+
+	entries := logs.FilterMessage("Served HTTP request").All()
+	if len(entries) != 1 || entries[0].Level != zapcore.DebugLevel {
+		t.Fatalf("expected the health probe to be logged at debug level, got %v", entries)
+	}
+}
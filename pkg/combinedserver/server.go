@@ -3,8 +3,11 @@ package combinedserver
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -81,12 +84,49 @@ type GRPCServerFactory func(*grpc.Server) error
 
 // ServerConfig holds the configuration for the combined server.
 type ServerConfig struct {
-	GRPCFactory   GRPCServerFactory
+	GRPCFactories []GRPCServerFactory
+	// GRPCOptions are passed to the gRPC server, for example to handle unknown services.
+	GRPCOptions   []grpc.ServerOption
 	HTTPFactories []HTTPMuxFactory
 	Port          int
+	// BindAddresses restricts Port to the given addresses. When empty, all interfaces
+	// are listened on, over both IPv4 and IPv6.
+	BindAddresses []net.IP
+	// ListenAddresses are served in addition to Port: unix:///path listens on a unix
+	// domain socket, fd:// on all sockets passed by systemd socket activation and
+	// fd://name on those with the given FileDescriptorName=.
+	ListenAddresses []string
 	// APIServerPort is the port where the internal Kubernetes API server listens.
 	// Used for health checks to verify API server readiness.
 	APIServerPort int
+	// ACME enables TLS on the listener with a certificate obtained via ACME.
+	// When nil or without domains, the listener serves plain HTTP (h2c).
+	ACME *ACMEConfig
+	// GetCertificate enables TLS on the listener with the certificates it returns, such
+	// as a SPIFFE SVID. It cannot be combined with ACME.
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	// TLS restricts the TLS versions and cipher suites when the listener serves TLS.
+	TLS *TLSConfig
+	// ClientCAs returns the CA bundle client certificates are verified against. When
+	// set, the listener requests client certificates, which requires TLS.
+	ClientCAs func() *x509.CertPool
+	// VerifyClientCertificate can reject a client certificate once it is verified
+	// against the client CAs, for example for its SPIFFE ID.
+	VerifyClientCertificate func(chain []*x509.Certificate) error
+	// OperatorClientCAs returns CAs whose client certificates the listener accepts as
+	// well, but which handlers verify on their own, like the API server proxy does for
+	// operators. Only chains of the client CAs are passed on as verified to handlers,
+	// so that operator certificates never pass for the identity of a machine.
+	OperatorClientCAs func() []*x509.Certificate
+	// CORS lets browser applications from other origins call the HTTP endpoints.
+	// When nil or without origins, no CORS headers are sent.
+	CORS *CORSConfig
+	// DrainPeriod is how long the server keeps serving with a failing readiness check
+	// on shutdown, so that load balancers stop sending it new requests first.
+	DrainPeriod time.Duration
+	// OnShutdown is called when the server stops accepting connections. It should
+	// start ending long-running requests, such as watches, but not wait for them.
+	OnShutdown []func()
 }
 
 type server struct {
@@ -113,12 +153,16 @@ func (s *server) ListenAndServe(ctx context.Context) error {
 	logger := logging.FromContext(ctx)
 
 	// Initialize gRPC server
-	s.grpcServer = grpc.NewServer()
+	s.grpcServer = grpc.NewServer(append(requestLoggingServerOptions(logger), s.GRPCOptions...)...)
 	reflection.Register(s.grpcServer)
 
-	err := s.GRPCFactory(s.grpcServer)
-	if err != nil {
-		return fmt.Errorf("failed to create gRPC factory: %w", err)
+	var err error
+
+	for _, factory := range s.GRPCFactories {
+		err = factory(s.grpcServer)
+		if err != nil {
+			return fmt.Errorf("failed to create gRPC factory: %w", err)
+		}
 	}
 
 	// Initialize HTTP mux
@@ -136,20 +180,26 @@ func (s *server) ListenAndServe(ctx context.Context) error {
 		}
 	}
 
+	httpHandler := withRequestLogging(logger, withCORS(s.CORS, s.httpMux))
+
 	// Create a handler that routes based on Content-Type header.
 	// gRPC requests have Content-Type starting with "application/grpc".
 	// This allows both gRPC and HTTP to be served on the same port,
 	// which is necessary when running behind a reverse proxy that
 	// terminates TLS and forwards HTTP/2.
-	mixedHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var mixedHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		contentType := r.Header.Get("Content-Type")
 		if strings.HasPrefix(contentType, "application/grpc") {
 			s.grpcServer.ServeHTTP(w, r)
 		} else {
-			s.httpMux.ServeHTTP(w, r)
+			httpHandler.ServeHTTP(w, r)
 		}
 	})
 
+	if s.OperatorClientCAs != nil {
+		mixedHandler = onlyClientCAChains(s.ClientCAs, s.VerifyClientCertificate, mixedHandler)
+	}
+
 	// Create HTTP server with h2c support for HTTP/2 without TLS
 	s.httpServer = &http.Server{
 		Addr:              ":" + strconv.Itoa(s.Port),
@@ -157,49 +207,147 @@ func (s *server) ListenAndServe(ctx context.Context) error {
 		ReadHeaderTimeout: 1 * time.Second,
 	}
 
-	logger.Info("Starting combined HTTP/gRPC server", zap.Int("port", s.Port))
+	switch {
+	case s.ACME.Enabled() && s.GetCertificate != nil:
+		return ErrCertificateWithACME
+	case s.ACME.Enabled():
+		s.httpServer.TLSConfig, err = newACMETLSConfig(s.ACME)
+		if err != nil {
+			return fmt.Errorf("failed to set up ACME: %w", err)
+		}
+	case s.GetCertificate != nil:
+		s.httpServer.TLSConfig = &tls.Config{
+			GetCertificate: s.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+			// gRPC requires HTTP/2 to be negotiated.
+			NextProtos: []string{http2.NextProtoTLS, "http/1.1"},
+		}
+	}
+
+	if s.httpServer.TLSConfig != nil {
+		s.TLS.apply(s.httpServer.TLSConfig)
+	}
+
+	if s.ClientCAs != nil || s.OperatorClientCAs != nil {
+		if s.httpServer.TLSConfig == nil {
+			return ErrClientCAsWithoutTLS
+		}
+
+		requestClientCertificates(s.httpServer.TLSConfig, s.ClientCAs, s.OperatorClientCAs, s.VerifyClientCertificate)
+	}
+
+	listeners, err := listen(ctx, s.Port, s.BindAddresses, s.ListenAddresses)
+	if err != nil {
+		return err
+	}
+
+	// The port may still be forwarded to by a load balancer, so this is not an error.
+	if s.ACME.Enabled() && !servesACMEChallengePort(listeners) {
+		logger.Warn("ACME is enabled but no listener is on port 443: certificates are only issued when "+
+			"port 443 of the ACME domains is forwarded to this server, as TLS-ALPN-01 challenges are "+
+			"answered on it",
+			zap.Int("port", s.Port),
+			zap.Strings("domains", s.ACME.Domains))
+	}
+
+	logger.Info("Starting combined HTTP/gRPC server",
+		zap.Int("port", s.Port),
+		zap.Any("bindAddresses", s.BindAddresses),
+		zap.Strings("listen", s.ListenAddresses),
+		zap.Bool("tls", s.httpServer.TLSConfig != nil))
 
 	// Mark server as running before starting to listen
 	s.stateTracker.SetState(ServerStateRunning)
 
-	err = s.httpServer.ListenAndServe()
+	err = s.serve(listeners)
 	if err != nil {
-		if errors.Is(err, http.ErrServerClosed) {
-			logger.Info("Server closed", zap.Int("port", s.Port))
+		return err
+	}
 
-			return nil
-		}
+	logger.Info("Server closed", zap.Int("port", s.Port))
+
+	return nil
+}
 
-		return fmt.Errorf("failed to serve: %w", err)
+// serve serves the listeners until the server is shut down. All listeners are served
+// alike, with TLS when it is configured. When serving one of them fails, the server is
+// closed and the error returned.
+func (s *server) serve(listeners []net.Listener) error {
+	served := make(chan error, len(listeners))
+
+	// Serving sets up HTTP/2, which fills in the TLS config of the server.
+	useTLS := s.httpServer.TLSConfig != nil
+
+	for _, listener := range listeners {
+		go func() {
+			if useTLS {
+				// Certificates are provided by the TLS config, so no files are passed.
+				served <- s.httpServer.ServeTLS(listener, "", "")
+			} else {
+				served <- s.httpServer.Serve(listener)
+			}
+		}()
+	}
+
+	for range listeners {
+		err := <-served
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = s.httpServer.Close()
+
+			return fmt.Errorf("failed to serve: %w", err)
+		}
 	}
 
 	return nil
 }
 
+// Shutdown shuts the server down gracefully. The server keeps serving for the drain
+// period with a failing readiness check, then stops accepting connections and waits
+// for in-flight requests until the context is done, after which they are aborted.
 func (s *server) Shutdown(ctx context.Context) error {
 	logger := logging.FromContext(ctx)
 
 	// Mark server as shutting down
 	s.stateTracker.SetState(ServerStateShuttingDown)
 
-	if s.grpcServer != nil {
-		logger.Info("Shutting down gRPC server", zap.Int("port", s.Port))
-		s.grpcServer.GracefulStop()
-		logger.Info("Shut down gRPC server", zap.Int("port", s.Port))
+	if s.DrainPeriod > 0 {
+		logger.Info("Draining server", zap.Int("port", s.Port), zap.Duration("drainPeriod", s.DrainPeriod))
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.DrainPeriod):
+		}
 	}
 
+	// Long-running requests are ended by their handlers, so that waiting for the
+	// in-flight requests below does not take until the context is done.
+	for _, onShutdown := range s.OnShutdown {
+		go onShutdown()
+	}
+
+	var err error
+
 	if s.httpServer != nil {
 		s.httpServer.SetKeepAlivesEnabled(false)
 
 		logger.Info("Shutting down HTTP server", zap.Int("port", s.Port))
 
-		err := s.httpServer.Shutdown(ctx)
+		err = s.httpServer.Shutdown(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to shutdown HTTP server: %w", err)
+			_ = s.httpServer.Close()
+			err = fmt.Errorf("failed to shutdown HTTP server: %w", err)
+		} else {
+			logger.Info("Shut down HTTP server", zap.Int("port", s.Port))
 		}
+	}
 
-		logger.Info("Shut down HTTP server", zap.Int("port", s.Port))
+	if s.grpcServer != nil {
+		// gRPC calls are served by the HTTP server, which waited for them above, so the
+		// gRPC server is stopped rather than drained: draining is not supported for
+		// calls served through ServeHTTP.
+		s.grpcServer.Stop()
+		logger.Info("Shut down gRPC server", zap.Int("port", s.Port))
 	}
 
-	return nil
+	return err
 }
@@ -0,0 +1,229 @@
+package combinedserver_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"google.golang.org/grpc"
+)
+
+const testDrainPeriod = 300 * time.Millisecond
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port //nolint:forcetypeassert // Listening on TCP.
+	_ = listener.Close()
+
+	return port
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err //nolint:wrapcheck // The error is only checked by the test.
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	shutdownCalled := make(chan struct{})
+	port := freePort(t)
+	baseURL := "http://127.0.0.1:" + strconv.Itoa(port)
+
+	server, err := combinedserver.New(combinedserver.ServerConfig{
+		Port:          port,
+		GRPCFactories: []combinedserver.GRPCServerFactory{func(*grpc.Server) error { return nil }},
+		HTTPFactories: []combinedserver.HTTPMuxFactory{func(mux *http.ServeMux) error {
+			mux.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+				<-release
+				w.WriteHeader(http.StatusOK)
+			})
+
+			return nil
+		}},
+		DrainPeriod: testDrainPeriod,
+		OnShutdown:  []func(){func() { close(shutdownCalled) }},
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- server.ListenAndServe(context.WithoutCancel(t.Context()))
+	}()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	for {
+		status, err := get(t, client, baseURL+combinedserver.LivezPath)
+		if err == nil && status == http.StatusOK {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	slow := make(chan int, 1)
+
+	go func() {
+		status, _ := get(t, client, baseURL+"/slow")
+		slow <- status
+	}()
+
+	shutdown := make(chan error, 1)
+	start := time.Now()
+
+	go func() {
+		shutdown <- server.Shutdown(context.WithoutCancel(t.Context()))
+	}()
+
+	// The server keeps serving during the drain period, but is no longer ready.
+	time.Sleep(testDrainPeriod / 3)
+
+	status, err := get(t, client, baseURL+combinedserver.ReadyzPath+"/ping")
+	if err != nil || status != http.StatusInternalServerError {
+		t.Fatalf("expected readiness to fail while draining, got %d (%v)", status, err)
+	}
+
+	<-shutdownCalled
+	close(release)
+
+	if status := <-slow; status != http.StatusOK {
+		t.Fatalf("expected the in-flight request to finish, got %d", status)
+	}
+
+	err = <-shutdown
+	if err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < testDrainPeriod {
+		t.Fatalf("expected shutdown to wait for the drain period, took %s", elapsed)
+	}
+
+	err = <-served
+	if err != nil {
+		t.Fatalf("server failed: %v", err)
+	}
+
+	_, err = get(t, client, baseURL+combinedserver.LivezPath)
+	if err == nil {
+		t.Fatalf("expected the server to stop accepting connections")
+	}
+}
+
+func TestListenAndServeOnUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	// Unix socket paths are limited to about 100 bytes, which t.TempDir can exceed.
+	dir, err := os.MkdirTemp("", "kommodity")
+	if err != nil {
+		t.Fatalf("failed to create socket directory: %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socketPath := filepath.Join(dir, "kommodity.sock")
+
+	server, err := combinedserver.New(combinedserver.ServerConfig{
+		Port:            freePort(t),
+		ListenAddresses: []string{"unix://" + socketPath},
+		GRPCFactories:   []combinedserver.GRPCServerFactory{func(*grpc.Server) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- server.ListenAndServe(context.WithoutCancel(t.Context()))
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+
+			return dialer.DialContext(ctx, "unix", socketPath)
+		},
+	}}
+
+	for {
+		status, err := get(t, client, "http://kommodity"+combinedserver.LivezPath)
+		select {
+		case err := <-served:
+			t.Fatalf("server failed: %v", err)
+		default:
+		}
+		if err == nil && status == http.StatusOK {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = server.Shutdown(context.WithoutCancel(t.Context()))
+	if err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	err = <-served
+	if err != nil {
+		t.Fatalf("server failed: %v", err)
+	}
+
+	_, err = os.Stat(socketPath)
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected the socket to be removed on shutdown, got %v", err)
+	}
+}
+
+func TestListenAndServeRejectsUnsupportedAddress(t *testing.T) {
+	t.Parallel()
+
+	server, err := combinedserver.New(combinedserver.ServerConfig{
+		Port:            freePort(t),
+		ListenAddresses: []string{"tcp://127.0.0.1:8080"},
+		GRPCFactories:   []combinedserver.GRPCServerFactory{func(*grpc.Server) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	err = server.ListenAndServe(context.WithoutCancel(t.Context()))
+	if !errors.Is(err, combinedserver.ErrUnsupportedListenAddress) {
+		t.Fatalf("expected ErrUnsupportedListenAddress, got %v", err)
+	}
+}
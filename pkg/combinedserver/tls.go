@@ -0,0 +1,119 @@
+package combinedserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+)
+
+// TLSConfig restricts the TLS versions and cipher suites of the listener. Zero values
+// keep the defaults.
+type TLSConfig struct {
+	MinVersion   uint16
+	MaxVersion   uint16
+	CipherSuites []uint16
+}
+
+// apply restricts the TLS config of the listener.
+func (c *TLSConfig) apply(tlsConfig *tls.Config) {
+	if c == nil {
+		return
+	}
+
+	if c.MinVersion != 0 {
+		tlsConfig.MinVersion = c.MinVersion
+	}
+
+	tlsConfig.MaxVersion = c.MaxVersion
+	tlsConfig.CipherSuites = c.CipherSuites
+}
+
+// requestClientCertificates makes the listener request client certificates and verify
+// them against the current client CAs, so that they can be rotated while serving.
+// Connections without a client certificate are still accepted. When verify is set, it
+// can reject the verified chain of a client certificate. Certificates of the operator
+// CAs are accepted as well, see onlyClientCAChains.
+func requestClientCertificates(tlsConfig *tls.Config, clientCAs func() *x509.CertPool,
+	operatorCAs func() []*x509.Certificate, verify func([]*x509.Certificate) error) {
+	base := tlsConfig.Clone()
+
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := base.Clone()
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		config.ClientCAs = listenerClientCAs(clientCAs, operatorCAs)
+
+		if verify != nil {
+			config.VerifyConnection = func(state tls.ConnectionState) error {
+				if len(state.VerifiedChains) == 0 {
+					return nil
+				}
+
+				return verify(state.VerifiedChains[0])
+			}
+		}
+
+		return config, nil
+	}
+}
+
+// listenerClientCAs returns the client CAs together with the operator CAs.
+func listenerClientCAs(clientCAs func() *x509.CertPool, operatorCAs func() []*x509.Certificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+	if clientCAs != nil {
+		pool = clientCAs().Clone()
+	}
+
+	if operatorCAs != nil {
+		for _, cert := range operatorCAs() {
+			pool.AddCert(cert)
+		}
+	}
+
+	return pool
+}
+
+// onlyClientCAChains removes the chains of client certificates that do not lead to the
+// client CAs from the verified chains of requests. The listener accepts certificates
+// of the operator CAs too, which their handlers verify on their own, and they must
+// never pass for the identity of a machine.
+func onlyClientCAChains(clientCAs func() *x509.CertPool, verify func([]*x509.Certificate) error,
+	next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			// The connection state is shared by the requests of a connection.
+			state := *r.TLS
+			state.VerifiedChains = verifyClientCAChains(state.PeerCertificates, clientCAs, verify)
+			r.TLS = &state
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func verifyClientCAChains(certs []*x509.Certificate, clientCAs func() *x509.CertPool,
+	verify func([]*x509.Certificate) error) [][]*x509.Certificate {
+	if clientCAs == nil || len(certs) == 0 {
+		return nil
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// The options match those of the listener.
+	chains, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         clientCAs(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil
+	}
+
+	if verify != nil && verify(chains[0]) != nil {
+		return nil
+	}
+
+	return chains
+}
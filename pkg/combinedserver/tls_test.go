@@ -0,0 +1,335 @@
+package combinedserver_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+)
+
+func TestTLSConfigRestrictsVersionsAndCipherSuites(t *testing.T) {
+	t.Parallel()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	cipherSuites := []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+
+	combinedserver.ApplyTLS(&combinedserver.TLSConfig{
+		MinVersion:   tls.VersionTLS13,
+		MaxVersion:   tls.VersionTLS13,
+		CipherSuites: cipherSuites,
+	}, tlsConfig)
+
+	if tlsConfig.MinVersion != tls.VersionTLS13 || tlsConfig.MaxVersion != tls.VersionTLS13 {
+		t.Fatalf("expected TLS 1.3 only, got versions %x to %x", tlsConfig.MinVersion, tlsConfig.MaxVersion)
+	}
+
+	if !slices.Equal(tlsConfig.CipherSuites, cipherSuites) {
+		t.Fatalf("expected the cipher suites to be restricted, got %v", tlsConfig.CipherSuites)
+	}
+}
+
+func TestTLSConfigKeepsDefaults(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []*combinedserver.TLSConfig{nil, {}} {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+		combinedserver.ApplyTLS(config, tlsConfig)
+
+		if tlsConfig.MinVersion != tls.VersionTLS12 || tlsConfig.MaxVersion != 0 || tlsConfig.CipherSuites != nil {
+			t.Fatalf("expected the defaults to be kept for %v, got %+v", config, tlsConfig)
+		}
+	}
+}
+
+func newCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert, key
+}
+
+var errRejected = errors.New("rejected")
+
+func TestRequestClientCertificates(t *testing.T) {
+	t.Parallel()
+
+	notAfter := time.Now().Add(time.Hour)
+
+	ca, caKey := newCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "machine-ca"},
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	clientCert, clientKey := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "10.0.0.5"},
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	rejectedCert, rejectedKey := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "10.0.0.6"},
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	serverCert, serverKey := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+
+	// The same CA issues the serving and the client certificates.
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.VerifiedChains) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+	}
+	combinedserver.RequestClientCertificates(server.TLS, func() *x509.CertPool { return clientCAs }, nil,
+		func(chain []*x509.Certificate) error {
+			if chain[0].Subject.CommonName == rejectedCert.Subject.CommonName {
+				return errRejected
+			}
+
+			return nil
+		})
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	tests := map[string]struct {
+		certificates []tls.Certificate
+		status       int
+	}{
+		"with client certificate": {
+			certificates: []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}},
+			status:       http.StatusOK,
+		},
+		"without client certificate": {status: http.StatusUnauthorized},
+		"with rejected client certificate": {
+			certificates: []tls.Certificate{{Certificate: [][]byte{rejectedCert.Raw}, PrivateKey: rejectedKey}},
+		},
+	}
+
+	for name, test := range tests {
+		transport := &http.Transport{TLSClientConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      clientCAs,
+			Certificates: test.certificates,
+		}}
+
+		status, err := get(t, &http.Client{Transport: transport}, server.URL)
+		if test.status == 0 {
+			if err == nil {
+				t.Fatalf("%s: expected the handshake to fail, got status %d", name, status)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+
+		if status != test.status {
+			t.Fatalf("%s: expected status %d, got %d", name, test.status, status)
+		}
+	}
+}
+
+func TestOperatorCertificatesAreNotMachineIdentities(t *testing.T) {
+	t.Parallel()
+
+	notAfter := time.Now().Add(time.Hour)
+	caTemplate := func(name string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: name},
+			NotAfter:              notAfter,
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "10.0.0.5"},
+		IPAddresses:  []net.IP{net.IPv4(10, 0, 0, 5)},
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	machineCA, machineCAKey := newCertificate(t, caTemplate("machine-ca"), nil, nil)
+	operatorCA, operatorCAKey := newCertificate(t, caTemplate("operator-ca"), nil, nil)
+	machineCert, machineKey := newCertificate(t, clientTemplate, machineCA, machineCAKey)
+	operatorCert, operatorKey := newCertificate(t, clientTemplate, operatorCA, operatorCAKey)
+
+	serverCert, serverKey := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, machineCA, machineCAKey)
+
+	machineCAs := x509.NewCertPool()
+	machineCAs.AddCert(machineCA)
+
+	clientCAs := func() *x509.CertPool { return machineCAs }
+
+	// Both certificates name the same machine, only the one of the machine CA may be
+	// taken as its identity.
+	handler := combinedserver.OnlyClientCAChains(clientCAs, nil, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			if len(r.TLS.VerifiedChains) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+	}
+	combinedserver.RequestClientCertificates(server.TLS, clientCAs,
+		func() []*x509.Certificate { return []*x509.Certificate{operatorCA} }, nil)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	tests := map[string]struct {
+		certificate tls.Certificate
+		status      int
+	}{
+		"machine certificate": {
+			certificate: tls.Certificate{Certificate: [][]byte{machineCert.Raw}, PrivateKey: machineKey},
+			status:      http.StatusOK,
+		},
+		"operator certificate": {
+			certificate: tls.Certificate{Certificate: [][]byte{operatorCert.Raw}, PrivateKey: operatorKey},
+			status:      http.StatusUnauthorized,
+		},
+	}
+
+	for name, test := range tests {
+		transport := &http.Transport{TLSClientConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      machineCAs,
+			Certificates: []tls.Certificate{test.certificate},
+		}}
+
+		status, err := get(t, &http.Client{Transport: transport}, server.URL)
+		if err != nil {
+			t.Fatalf("%s: expected the listener to accept the certificate: %v", name, err)
+		}
+
+		if status != test.status {
+			t.Fatalf("%s: expected status %d, got %d", name, test.status, status)
+		}
+	}
+}
+
+func TestServesACMEChallengePort(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		addresses []net.Addr
+		expected  bool
+	}{
+		"default port": {
+			addresses: []net.Addr{&net.TCPAddr{IP: net.IPv6zero, Port: 5000}},
+			expected:  false,
+		},
+		"port 443": {
+			addresses: []net.Addr{&net.TCPAddr{IP: net.IPv6zero, Port: 5000}, &net.TCPAddr{IP: net.IPv4zero, Port: 443}},
+			expected:  true,
+		},
+		"unix socket": {
+			addresses: []net.Addr{&net.UnixAddr{Name: "/run/kommodity.sock", Net: "unix"}},
+			expected:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			listeners := make([]net.Listener, 0, len(test.addresses))
+			for _, address := range test.addresses {
+				listeners = append(listeners, addrListener{address: address})
+			}
+
+			served := combinedserver.ServesACMEChallengePort(listeners)
+			if served != test.expected {
+				t.Fatalf("expected %t, got %t", test.expected, served)
+			}
+		})
+	}
+}
+
+// addrListener is a net.Listener that only reports its address.
+type addrListener struct {
+	net.Listener
+
+	address net.Addr
+}
+
+func (l addrListener) Addr() net.Addr {
+	return l.address
+}
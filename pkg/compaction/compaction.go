@@ -0,0 +1,223 @@
+package compaction
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kineserver "github.com/k3s-io/kine/pkg/server"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kine"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// compactionTimeout bounds a single compaction, which deletes in batches of 1000
+// revisions.
+const compactionTimeout = 5 * time.Minute
+
+//nolint:gochecknoglobals // Metrics are registered once in the process wide legacy registry.
+var (
+	compactedRevisions = metrics.NewCounter(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "kine_compaction",
+		Name:           "revisions_total",
+		Help:           "Number of revisions compacted, whose replaced and deleted rows were deleted.",
+		StabilityLevel: metrics.ALPHA,
+	})
+
+	compactionsTotal = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "kine_compaction",
+		Name:           "runs_total",
+		Help:           "Number of compactions by result.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"result"})
+
+	databaseSize = metrics.NewGauge(&metrics.GaugeOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "kine_compaction",
+		Name:           "database_size_bytes",
+		Help:           "Size of the kine database after the last compaction.",
+		StabilityLevel: metrics.ALPHA,
+	})
+
+	registerCompactionMetricsOnce sync.Once
+)
+
+func registerCompactionMetrics() {
+	registerCompactionMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(compactedRevisions, compactionsTotal, databaseSize)
+	})
+}
+
+// Result describes a compaction.
+type Result struct {
+	// Revision is the revision kine is compacted to.
+	Revision int64 `json:"revision"`
+	// CompactedRevisions is the number of revisions compacted by this compaction.
+	CompactedRevisions int64 `json:"compactedRevisions"`
+	// DatabaseSize is the size of the database in bytes after the compaction, unset if
+	// the database does not report it.
+	DatabaseSize int64 `json:"databaseSize,omitempty"`
+}
+
+// Compactor compacts kine, one compaction at a time.
+type Compactor struct {
+	cfg     *config.KommodityConfig
+	running sync.Mutex
+}
+
+// NewCompactor creates a compactor for the kine of the config.
+func NewCompactor(cfg *config.KommodityConfig) *Compactor {
+	registerCompactionMetrics()
+
+	return &Compactor{cfg: cfg}
+}
+
+// Compact compacts kine up to the retained revisions. It returns ErrCompactionRunning
+// if another compaction is running.
+func (c *Compactor) Compact(ctx context.Context) (*Result, error) {
+	if !c.running.TryLock() {
+		return nil, ErrCompactionRunning
+	}
+	defer c.running.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, compactionTimeout)
+	defer cancel()
+
+	result, err := c.compact(ctx)
+	if err != nil {
+		compactionsTotal.WithLabelValues("error").Inc()
+
+		return nil, err
+	}
+
+	compactionsTotal.WithLabelValues("success").Inc()
+	compactedRevisions.Add(float64(result.CompactedRevisions))
+	if result.DatabaseSize > 0 {
+		databaseSize.Set(float64(result.DatabaseSize))
+	}
+
+	return result, nil
+}
+
+func (c *Compactor) compact(ctx context.Context) (*Result, error) {
+	cli, err := kine.NewClient(c.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	current, err := cli.Get(ctx, "compact_rev_key", clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the current revision: %w", err)
+	}
+
+	previous, err := compactRevision(ctx, cli, current.Header.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	target := current.Header.Revision - int64(c.retention())
+	if target > previous {
+		_, err = cli.Compact(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compact to revision %d: %w", target, err)
+		}
+
+		compacted, err := compactRevision(ctx, cli, target)
+		if err != nil {
+			return nil, err
+		}
+
+		if compacted < target {
+			return nil, fmt.Errorf("%w: compacted to %d instead of %d", ErrCompactionFailed, compacted, target)
+		}
+	}
+
+	result := &Result{Revision: max(target, previous), CompactedRevisions: max(target-previous, 0)}
+
+	// SQLite only reports its size if it is built with the dbstat table.
+	dbStatus, err := cli.Status(ctx, c.cfg.KineURI)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to read the kine database size", zap.Error(err))
+
+		return result, nil
+	}
+
+	result.DatabaseSize = dbStatus.DbSize
+
+	return result, nil
+}
+
+func (c *Compactor) retention() int {
+	if c.cfg.DatabaseConfig == nil {
+		return 0
+	}
+
+	return c.cfg.DatabaseConfig.CompactionRetention
+}
+
+// Run compacts kine every configured interval until the context is cancelled. Failed
+// compactions are logged and retried at the next interval.
+func (c *Compactor) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	ticker := time.NewTicker(c.cfg.DatabaseConfig.CompactionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := c.Compact(ctx)
+		if err != nil {
+			logger.Error("Failed to compact kine", zap.Error(err))
+
+			continue
+		}
+
+		logger.Info("Compacted kine", zap.Int64("revision", result.Revision),
+			zap.Int64("compactedRevisions", result.CompactedRevisions),
+			zap.Int64("databaseSize", result.DatabaseSize))
+	}
+}
+
+// compactRevision returns the revision kine is compacted to, found by bisecting the
+// revisions up to the given one: reads of the revisions before it fail.
+func compactRevision(ctx context.Context, cli *clientv3.Client, upTo int64) (int64, error) {
+	low, high := int64(1), upTo
+
+	for low < high {
+		middle := low + (high-low)/2
+
+		_, err := cli.Get(ctx, "compact_rev_key", clientv3.WithRev(middle), clientv3.WithKeysOnly())
+
+		switch {
+		case isCompacted(err):
+			low = middle + 1
+		case err != nil:
+			return 0, fmt.Errorf("failed to read revision %d: %w", middle, err)
+		default:
+			high = middle
+		}
+	}
+
+	return low, nil
+}
+
+// isCompacted reports whether kine rejected a read as its revision was compacted. The
+// client turns the gRPC error of kine into an error carrying its message.
+func isCompacted(err error) bool {
+	return err != nil && err.Error() == status.Convert(kineserver.ErrCompacted).Message()
+}
@@ -0,0 +1,152 @@
+package compaction_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/compaction"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kine"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	testRetention = 10
+	testRevisions = 50
+	testKey       = "/registry/configmaps/default/compacted"
+)
+
+// startKine starts kine on SQLite with the compaction settings of Kommodity, until the
+// test ends.
+func startKine(t *testing.T) *config.KommodityConfig {
+	t.Helper()
+
+	// Unix socket paths are limited to about 100 bytes, which the directories created
+	// by t.TempDir easily exceed.
+	dir, err := os.MkdirTemp("", "kine")
+	if err != nil {
+		t.Fatalf("failed to create kine directory: %v", err)
+	}
+
+	cfg := &config.KommodityConfig{
+		DBURI:   &url.URL{Scheme: "sqlite", Path: filepath.Join(dir, "state.db")},
+		KineURI: "unix://" + filepath.Join(dir, "kine.sock"),
+		DatabaseConfig: &config.DatabaseConfig{
+			SlowQueryThreshold:  time.Second,
+			CompactionRetention: testRetention,
+		},
+	}
+
+	server := kine.NewServer(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.StartKine(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+
+		_ = os.RemoveAll(dir)
+	})
+
+	deadline := time.Now().Add(30 * time.Second)
+	for server.CheckDatabase() != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("kine did not become ready: %v", server.CheckDatabase())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return cfg
+}
+
+func TestCompactRetainsRecentRevisions(t *testing.T) {
+	t.Parallel()
+
+	cfg := startKine(t)
+
+	cli, err := kine.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to kine: %v", err)
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	var first, revision int64
+
+	// Kine only supports puts as part of a transaction on the revision of the key,
+	// which reads the key otherwise.
+	for i := range testRevisions {
+		response, err := cli.Txn(t.Context()).
+			If(clientv3.Compare(clientv3.ModRevision(testKey), "=", revision)).
+			Then(clientv3.OpPut(testKey, strconv.Itoa(i))).
+			Else(clientv3.OpGet(testKey)).
+			Commit()
+		if err != nil || !response.Succeeded {
+			t.Fatalf("failed to write revision %d: %v", i, err)
+		}
+
+		revision = response.Header.Revision
+		if i == 0 {
+			first = revision
+		}
+	}
+
+	compactor := compaction.NewCompactor(cfg)
+
+	result, err := compactor.Compact(t.Context())
+	if err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+
+	if result.CompactedRevisions <= 0 || result.Revision <= first {
+		t.Fatalf("expected revisions to be compacted, got %+v", result)
+	}
+
+	_, err = cli.Get(t.Context(), testKey, clientv3.WithRev(first))
+	if err == nil {
+		t.Fatalf("expected revision %d to be compacted", first)
+	}
+
+	_, err = cli.Get(t.Context(), testKey, clientv3.WithRev(result.Revision))
+	if err != nil {
+		t.Fatalf("expected revision %d to be retained: %v", result.Revision, err)
+	}
+
+	again, err := compactor.Compact(t.Context())
+	if err != nil {
+		t.Fatalf("failed to compact again: %v", err)
+	}
+
+	if again.CompactedRevisions != 0 || again.Revision != result.Revision {
+		t.Fatalf("expected nothing left to compact, got %+v", again)
+	}
+}
+
+func TestCompactFailsWithoutKine(t *testing.T) {
+	t.Parallel()
+
+	compactor := compaction.NewCompactor(&config.KommodityConfig{
+		KineURI:        "unix://" + filepath.Join(os.TempDir(), "missing-kine.sock"),
+		DatabaseConfig: &config.DatabaseConfig{},
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+
+	_, err := compactor.Compact(ctx)
+	if err == nil || errors.Is(err, compaction.ErrCompactionRunning) {
+		t.Fatalf("expected the compaction to fail, got %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+// Package compaction prunes the revisions kine keeps of replaced and deleted keys.
+//
+// Kine stores every revision of every key as a row. Compacting up to a revision deletes
+// the rows of the revisions before it that were replaced or deleted since, like etcd
+// does for the API server. Kommodity compacts on a schedule and on demand, and always
+// retains the most recent revisions so that watches can resume.
+package compaction
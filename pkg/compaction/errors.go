@@ -0,0 +1,10 @@
+package compaction
+
+import "errors"
+
+var (
+	// ErrCompactionFailed is returned when kine did not compact up to the requested revision.
+	ErrCompactionFailed = errors.New("kine did not compact to the requested revision")
+	// ErrCompactionRunning is returned when a compaction is requested while another one runs.
+	ErrCompactionRunning = errors.New("a compaction is already running")
+)
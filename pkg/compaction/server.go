@@ -0,0 +1,76 @@
+package compaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	bearertoken "k8s.io/apiserver/pkg/authentication/request/bearertoken"
+)
+
+// CompactionsEndpoint is the endpoint for compacting kine on demand.
+const CompactionsEndpoint = "/kine/compactions"
+
+// NewHTTPMuxFactory creates a new HTTP mux factory for the compaction admin API and
+// starts the scheduled compactions, which stop when the context is cancelled.
+// Compacting on demand requires OIDC, only members of the admin group may call it.
+func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
+	return func(mux *http.ServeMux) error {
+		logger := logging.FromContext(ctx)
+		compactor := NewCompactor(cfg)
+
+		if cfg.DatabaseConfig != nil && cfg.DatabaseConfig.CompactionInterval > 0 {
+			go compactor.Run(ctx)
+		}
+
+		if cfg.AuthConfig.OIDCConfig == nil {
+			logger.Warn("Not serving the compaction API, it requires OIDC authentication")
+
+			return nil
+		}
+
+		oidcAuth, err := httpauth.NewOIDCAuthenticator(ctx, cfg.AuthConfig.OIDCConfig)
+		if err != nil {
+			return fmt.Errorf("failed to set up compaction authentication: %w", err)
+		}
+
+		authenticate := httpauth.RequireBearerToken(bearertoken.New(oidcAuth), nil)
+
+		mux.HandleFunc("POST "+CompactionsEndpoint,
+			authenticate(httpauth.RequireGroup(cfg.AuthConfig.AdminGroup)(postCompaction(compactor, logger))))
+
+		logger.Info("Serving the compaction API", zap.String("endpoint", CompactionsEndpoint))
+
+		return nil
+	}
+}
+
+// postCompaction handles the POST /kine/compactions endpoint.
+func postCompaction(compactor *Compactor, logger *zap.Logger) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		result, err := compactor.Compact(request.Context())
+		if errors.Is(err, ErrCompactionRunning) {
+			http.Error(response, err.Error(), http.StatusConflict)
+
+			return
+		}
+
+		if err != nil {
+			logger.Error("Failed to compact kine", zap.Error(err))
+			http.Error(response, "Failed to compact kine", http.StatusInternalServerError)
+
+			return
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(response).Encode(result)
+	}
+}
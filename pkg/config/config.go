@@ -3,7 +3,11 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
 	"os"
 	"slices"
@@ -13,13 +17,20 @@ import (
 
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	restclient "k8s.io/client-go/rest"
+	cliflag "k8s.io/component-base/cli/flag"
 	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 const (
 	envBaseURL                            = "KOMMODITY_BASE_URL"
 	envServerPort                         = "KOMMODITY_PORT"
+	envListen                             = "KOMMODITY_LISTEN"
+	envBindAddresses                      = "KOMMODITY_BIND_ADDRESSES"
+	envAPIServerBindAddress               = "KOMMODITY_API_SERVER_BIND_ADDRESS"
+	envAPIServerCertSANs                  = "KOMMODITY_API_SERVER_CERT_SANS"
 	envAPIServerPort                      = "KOMMODITY_API_SERVER_PORT"
 	envAdminGroup                         = "KOMMODITY_ADMIN_GROUP"
 	envDisableAuth                        = "KOMMODITY_INSECURE_DISABLE_AUTHENTICATION"
@@ -27,16 +38,37 @@ const (
 	envOIDCClientID                       = "KOMMODITY_OIDC_CLIENT_ID"
 	envOIDCUsernameClaim                  = "KOMMODITY_OIDC_USERNAME_CLAIM"
 	envOIDCGroupsClaim                    = "KOMMODITY_OIDC_GROUPS_CLAIM"
+	envClientCAFile                       = "KOMMODITY_CLIENT_CA_FILE"
+	envHTTPAuthEnabled                    = "KOMMODITY_HTTP_AUTH_ENABLED"
+	envHTTPAuthExemptPaths                = "KOMMODITY_HTTP_AUTH_EXEMPT_PATHS"
+	envHTTPClientCAFile                   = "KOMMODITY_HTTP_CLIENT_CA_FILE"
+	envHTTPClientCASecret                 = "KOMMODITY_HTTP_CLIENT_CA_SECRET"
+	envAuthzWebhookKubeconfig             = "KOMMODITY_AUTHZ_WEBHOOK_KUBECONFIG"
+	envAuthzWebhookAuthorizedTTL          = "KOMMODITY_AUTHZ_WEBHOOK_AUTHORIZED_TTL"
+	envAuthzWebhookUnauthorizedTTL        = "KOMMODITY_AUTHZ_WEBHOOK_UNAUTHORIZED_TTL"
 	envDatabaseURI                        = "KOMMODITY_DB_URI"
 	envAttestationNonceTTL                = "KOMMODITY_ATTESTATION_NONCE_TTL"
+	envAttestationNonceStore              = "KOMMODITY_ATTESTATION_NONCE_STORE"
 	envDevelopmentMode                    = "KOMMODITY_DEVELOPMENT_MODE"
 	envKineURI                            = "KOMMODITY_KINE_URI"
+	envKineMaxRestarts                    = "KOMMODITY_KINE_MAX_RESTARTS"
 	envInfrastructureProviders            = "KOMMODITY_INFRASTRUCTURE_PROVIDERS"
+	envMetadataCompatibility              = "KOMMODITY_METADATA_COMPATIBILITY"
 	envAuditPolicyFilePath                = "KOMMODITY_AUDIT_POLICY_FILE_PATH"
 	envGarbageCollectorEnabled            = "KOMMODITY_GARBAGE_COLLECTOR_ENABLED"
 	envGarbageCollectorWorkers            = "KOMMODITY_GARBAGE_COLLECTOR_WORKERS"
 	envGarbageCollectorSyncPeriod         = "KOMMODITY_GARBAGE_COLLECTOR_SYNC_PERIOD"
 	envGarbageCollectorInitialSyncTimeout = "KOMMODITY_GARBAGE_COLLECTOR_INITIAL_SYNC_TIMEOUT"
+	envControllerMaxConcurrency           = "KOMMODITY_CONTROLLER_MAX_CONCURRENT_RECONCILES"
+	envControllerConcurrency              = "KOMMODITY_CONTROLLER_CONCURRENCY"
+	envControllerRateLimitBaseDelay       = "KOMMODITY_CONTROLLER_RATE_LIMIT_BASE_DELAY"
+	envControllerRateLimitMaxDelay        = "KOMMODITY_CONTROLLER_RATE_LIMIT_MAX_DELAY"
+	envControllerRateLimitQPS             = "KOMMODITY_CONTROLLER_RATE_LIMIT_QPS"
+	envControllerRateLimitBurst           = "KOMMODITY_CONTROLLER_RATE_LIMIT_BURST"
+	envControllerSyncPeriod               = "KOMMODITY_CONTROLLER_SYNC_PERIOD"
+	envControllerWatchNamespaces          = "KOMMODITY_CONTROLLER_WATCH_NAMESPACES"
+	envControllerSecretLabelSelector      = "KOMMODITY_CONTROLLER_SECRET_LABEL_SELECTOR"
+	envControllerConfigMapLabelSelector   = "KOMMODITY_CONTROLLER_CONFIGMAP_LABEL_SELECTOR"
 	envTalosProxyEnabled                  = "KOMMODITY_TALOS_PROXY_ENABLED"
 	envTalosProxyPort                     = "KOMMODITY_TALOS_PROXY_PORT"
 	envTalosProxyNamespace                = "KOMMODITY_TALOS_PROXY_NAMESPACE"
@@ -45,14 +77,91 @@ const (
 	//nolint:gosec // G101: env var name, not a credential
 	envAzureDefaultCredentialSecret = "KOMMODITY_AZURE_DEFAULT_CREDENTIAL_SECRET"
 	envAzureARMDeletionGracePeriod  = "KOMMODITY_AZURE_ARM_DELETION_GRACE_PERIOD"
+	envListLoadSheddingResources    = "KOMMODITY_LIST_LOAD_SHEDDING_RESOURCES"
+	envACMEEmail                    = "KOMMODITY_ACME_EMAIL"
+	envACMEDomains                  = "KOMMODITY_ACME_DOMAINS"
+	envACMECacheDir                 = "KOMMODITY_ACME_CACHE_DIR"
+	envACMEDirectoryURL             = "KOMMODITY_ACME_DIRECTORY_URL"
+	envCORSAllowedOrigins           = "KOMMODITY_CORS_ALLOWED_ORIGINS"
+	envCORSAllowedMethods           = "KOMMODITY_CORS_ALLOWED_METHODS"
+	envCORSAllowedHeaders           = "KOMMODITY_CORS_ALLOWED_HEADERS"
+	envCORSAllowCredentials         = "KOMMODITY_CORS_ALLOW_CREDENTIALS"
+	envTLSMinVersion                = "KOMMODITY_TLS_MIN_VERSION"
+	envTLSMaxVersion                = "KOMMODITY_TLS_MAX_VERSION"
+	envTLSCipherSuites              = "KOMMODITY_TLS_CIPHER_SUITES"
+	envSPIFFEEndpointSocket         = "KOMMODITY_SPIFFE_ENDPOINT_SOCKET"
+	envSPIFFEAllowedIDs             = "KOMMODITY_SPIFFE_ALLOWED_IDS"
+	envListLoadSheddingRetryAfter   = "KOMMODITY_LIST_LOAD_SHEDDING_RETRY_AFTER"
+	envListLoadSheddingMinObjects   = "KOMMODITY_LIST_LOAD_SHEDDING_MIN_OBJECTS"
+	envRateLimitUserQPS             = "KOMMODITY_RATE_LIMIT_USER_QPS"
+	envRateLimitUserBurst           = "KOMMODITY_RATE_LIMIT_USER_BURST"
+	envRateLimitIPQPS               = "KOMMODITY_RATE_LIMIT_IP_QPS"
+	envRateLimitIPBurst             = "KOMMODITY_RATE_LIMIT_IP_BURST"
+	envPriorityInteractiveSeats     = "KOMMODITY_PRIORITY_INTERACTIVE_CONCURRENCY"
+	envPriorityWorkloadSeats        = "KOMMODITY_PRIORITY_WORKLOAD_CONCURRENCY"
+	envPriorityQueueLength          = "KOMMODITY_PRIORITY_QUEUE_LENGTH"
+	envPriorityQueueTimeout         = "KOMMODITY_PRIORITY_QUEUE_TIMEOUT"
+	envEncryptionProvider           = "KOMMODITY_ENCRYPTION_PROVIDER"
+	envEncryptionStaticKeys         = "KOMMODITY_ENCRYPTION_STATIC_KEYS"
+	envEncryptionVaultAddress       = "KOMMODITY_ENCRYPTION_VAULT_ADDRESS"
+	envEncryptionVaultToken         = "KOMMODITY_ENCRYPTION_VAULT_TOKEN"
+	envEncryptionVaultMount         = "KOMMODITY_ENCRYPTION_VAULT_MOUNT"
+	envEncryptionVaultKey           = "KOMMODITY_ENCRYPTION_VAULT_KEY"
+	envEncryptionAWSKeyID           = "KOMMODITY_ENCRYPTION_AWS_KEY_ID"
+	envEncryptionAWSRegion          = "KOMMODITY_ENCRYPTION_AWS_REGION"
+	envEncryptionGCPKeyName         = "KOMMODITY_ENCRYPTION_GCP_KEY_NAME"
+	envSecretsBackend               = "KOMMODITY_SECRETS_BACKEND"
+	envSecretReadAuditEnabled       = "KOMMODITY_SECRET_READ_AUDIT_ENABLED"
+	envSecretReadAuditEvents        = "KOMMODITY_SECRET_READ_AUDIT_EVENTS"
+	envSecretReadAuditExcludeUsers  = "KOMMODITY_SECRET_READ_AUDIT_EXCLUDE_USERS"
+	envSecretReadAuditExcludeGroups = "KOMMODITY_SECRET_READ_AUDIT_EXCLUDE_GROUPS"
+	envSecretsBackendTypes          = "KOMMODITY_SECRETS_BACKEND_TYPES"
+	envSecretsBackendNamespaces     = "KOMMODITY_SECRETS_BACKEND_NAMESPACES"
+	envSecretsBackendPrefix         = "KOMMODITY_SECRETS_BACKEND_PREFIX"
+	envSecretsBackendCacheTTL       = "KOMMODITY_SECRETS_BACKEND_CACHE_TTL"
+	envSecretsVaultAddress          = "KOMMODITY_SECRETS_VAULT_ADDRESS"
+	envSecretsVaultToken            = "KOMMODITY_SECRETS_VAULT_TOKEN"
+	envSecretsVaultMount            = "KOMMODITY_SECRETS_VAULT_MOUNT"
+	envSecretsAWSRegion             = "KOMMODITY_SECRETS_AWS_REGION"
+	envBackupS3Endpoint             = "KOMMODITY_BACKUP_S3_ENDPOINT"
+	envBackupS3Region               = "KOMMODITY_BACKUP_S3_REGION"
+	envBackupS3Bucket               = "KOMMODITY_BACKUP_S3_BUCKET"
+	envBackupS3Prefix               = "KOMMODITY_BACKUP_S3_PREFIX"
+	envBackupS3AccessKeyID          = "KOMMODITY_BACKUP_S3_ACCESS_KEY_ID"
+	envBackupInterval               = "KOMMODITY_BACKUP_INTERVAL"
+	envBackupRestoreSnapshot        = "KOMMODITY_BACKUP_RESTORE_SNAPSHOT"
+	envClusterHealthInterval        = "KOMMODITY_CLUSTER_HEALTH_INTERVAL"
+	envEventTTL                     = "KOMMODITY_EVENT_TTL"
+	envEnablePprof                  = "KOMMODITY_ENABLE_PPROF"
+	envShutdownDrainPeriod          = "KOMMODITY_SHUTDOWN_DRAIN_PERIOD"
+	envShutdownTimeout              = "KOMMODITY_SHUTDOWN_TIMEOUT"
+	envCompressionEnabled           = "KOMMODITY_RESPONSE_COMPRESSION_ENABLED"
+	envWatchCache                   = "KOMMODITY_WATCH_CACHE"
+	envDBMaxOpenConnections         = "KOMMODITY_DB_MAX_OPEN_CONNECTIONS"
+	envDBMaxIdleConnections         = "KOMMODITY_DB_MAX_IDLE_CONNECTIONS"
+	envDBConnectionMaxLifetime      = "KOMMODITY_DB_CONNECTION_MAX_LIFETIME"
+	envDBStatementTimeout           = "KOMMODITY_DB_STATEMENT_TIMEOUT"
+	envDBSlowQueryThreshold         = "KOMMODITY_DB_SLOW_QUERY_THRESHOLD"
+	envDBCompactionInterval         = "KOMMODITY_DB_COMPACTION_INTERVAL"
+	envDBCompactionRetention        = "KOMMODITY_DB_COMPACTION_RETENTION"
+	envCompressionMinSize           = "KOMMODITY_RESPONSE_COMPRESSION_MIN_SIZE"
+	envCompressionWatches           = "KOMMODITY_RESPONSE_COMPRESSION_WATCHES"
+	//nolint:gosec // G101: env var name, not a credential
+	envBackupS3SecretAccessKey = "KOMMODITY_BACKUP_S3_SECRET_ACCESS_KEY"
 
 	defaultServerPort                         = 5000
 	defaultAPIServerPort                      = 8443
+	defaultAPIServerBindAddress               = "127.0.0.1"
 	defaultDisableAuth                        = false
 	defaultOIDCUsernameClaim                  = "email"
 	defaultOIDCGroupsClaim                    = "groups"
+	defaultHTTPAuthEnabled                    = false
+	defaultAuthzWebhookAuthorizedTTL          = 5 * time.Minute
+	defaultAuthzWebhookUnauthorizedTTL        = 30 * time.Second
 	defaultDevelopmentMode                    = false
+	defaultEnablePprof                        = false
 	defaultKineURI                            = "unix://bin/kine.sock"
+	defaultKineMaxRestarts                    = 5
 	defaultAttestationNonceTTL                = 5 * time.Minute
 	defaultTalosProxyEnabled                  = true
 	defaultTalosProxyPort                     = 15050
@@ -64,35 +173,391 @@ const (
 	defaultGarbageCollectorSyncPeriod         = 30 * time.Second
 	defaultGarbageCollectorInitialSyncTimeout = 60 * time.Second
 	defaultAzureDefaultCredentialSecret       = ""
+	// The controller defaults are those of controller-runtime, but for the concurrency.
+	defaultControllerMaxConcurrency     = 10
+	defaultControllerRateLimitBaseDelay = 5 * time.Millisecond
+	defaultControllerRateLimitMaxDelay  = 1000 * time.Second
+	defaultControllerRateLimitQPS       = 10
+	defaultControllerRateLimitBurst     = 100
+	defaultControllerSyncPeriod         = 10 * time.Hour
 	// defaultAzureARMDeletionGracePeriod bounds how long the embedded ARM
 	// reconciler waits for Azure to actually delete a managed resource before it
 	// releases its finalizer. This prevents a single un-deletable resource from
 	// wedging cluster teardown indefinitely (the resource may be orphaned, which
 	// is recoverable; a stuck finalizer blocks the whole namespace, which is not).
 	defaultAzureARMDeletionGracePeriod = 15 * time.Minute
+	defaultListLoadSheddingRetryAfter  = 5 * time.Second
+	defaultListLoadSheddingMinObjects  = 500
+	defaultRateLimitBurst              = 100
+	defaultPriorityQueueLength         = 50
+	defaultPriorityQueueTimeout        = 15 * time.Second
+	defaultACMECacheDir                = "bin/acme"
+	defaultCORSAllowedMethods          = "GET,HEAD,POST,PUT,PATCH,DELETE"
+	defaultCORSAllowedHeaders          = "Authorization,Content-Type"
+	defaultCORSAllowCredentials        = false
+	defaultTLSMinVersion               = "VersionTLS12"
+	defaultEncryptionVaultMount        = "transit"
+	defaultSecretsVaultMount           = "secret"
+	defaultSecretsBackendPrefix        = "kommodity"
+	defaultSecretsBackendCacheTTL      = 5 * time.Minute
+	// defaultSecretReadAuditExcludeUsers is the loopback user Kommodity's own API
+	// server and controllers read Secrets as.
+	defaultSecretReadAuditExcludeUsers = "system:apiserver"
+	defaultBackupS3Region              = "us-east-1"
+	defaultBackupS3Prefix              = "kommodity/"
+	defaultClusterHealthInterval       = time.Minute
+	// defaultEventTTL matches the default --event-ttl of the upstream API server.
+	defaultEventTTL = time.Hour
+	// defaultShutdownDrainPeriod gives load balancers time to notice the failing
+	// readiness check before Kommodity stops accepting connections.
+	defaultShutdownDrainPeriod = 5 * time.Second
+	// defaultShutdownTimeout stays below the default termination grace period of 30s
+	// of Kubernetes pods.
+	defaultShutdownTimeout = 25 * time.Second
+	// defaultHTTPAuthExemptPaths are the endpoints booting machines call. Machines
+	// hold no OIDC token and are identified by their IP and attestation instead.
+	defaultHTTPAuthExemptPaths = "/nonce,/report,/token,/configs/user-data,/latest/,/openstack/"
+	// defaultCompressionMinSize matches the size above which the upstream API server
+	// gzips responses.
+	defaultCompressionMinSize = 128 * 1024
+	defaultCompressionEnabled = true
+	defaultCompressionWatches = true
+	// defaultWatchCache caches Secrets, which every provider controller watches, on top of
+	// the custom resources like machines and clusters, which are cached unless disabled.
+	defaultWatchCache = "secrets=true"
+	// defaultDBMaxIdleConnections matches the default of database/sql, which kine keeps.
+	defaultDBMaxIdleConnections = 2
+	// defaultDBSlowQueryThreshold matches the default of kine.
+	defaultDBSlowQueryThreshold = time.Second
+	// defaultDBCompactionInterval and defaultDBCompactionRetention match the defaults of
+	// kine, which compacts by itself unless Kommodity does.
+	defaultDBCompactionInterval  = 5 * time.Minute
+	defaultDBCompactionRetention = 1000
 )
 
 const (
 	configurationNotSpecified = "Configuration not specified, using default value"
+	staticEncryptionKeySize   = 32
 )
 
 // KommodityConfig holds the configuration settings for the Kommodity API server.
 type KommodityConfig struct {
 	BaseURL                 string
 	ServerPort              int
+	ListenAddresses         []string
+	BindAddresses           []net.IP
 	APIServerPort           int
+	APIServerBindAddress    net.IP
+	APIServerCertSANs       []string
 	WebhookPort             int
 	DBURI                   *url.URL
 	KineURI                 string
+	KineMaxRestarts         int
+	DatabaseConfig          *DatabaseConfig
 	AttestationConfig       *AttestationConfig
 	AuthConfig              *AuthConfig
 	ClientConfig            *ClientConfig
 	TalosProxyConfig        *TalosProxyConfig
 	GarbageCollectorConfig  *GarbageCollectorConfig
+	ControllerConfig        *ControllerConfig
 	AuditPolicyFilePath     string
 	DevelopmentMode         bool
 	InfrastructureProviders []Provider
 	AzureConfig             *AzureConfig
+	LoadSheddingConfig      *LoadSheddingConfig
+	RateLimitConfig         *RateLimitConfig
+	PriorityQueueingConfig  *PriorityQueueingConfig
+	ACMEConfig              *ACMEConfig
+	CORSConfig              *CORSConfig
+	TLSConfig               *TLSConfig
+	SPIFFEConfig            *SPIFFEConfig
+	EncryptionConfig        *EncryptionConfig
+	SecretsBackendConfig    *SecretsBackendConfig
+	SecretReadAuditConfig   *SecretReadAuditConfig
+	BackupConfig            *BackupConfig
+	// ClusterHealthInterval is how often the health of a workload cluster is checked
+	// when nothing else changes. Clusters can override it with an annotation.
+	ClusterHealthInterval time.Duration
+	// EventTTL is how long events are kept before kine expires them.
+	EventTTL time.Duration
+	// EnablePprof serves the Go runtime profiles and variables of Kommodity under
+	// /debug on the API server, to callers allowed those non-resource URLs.
+	EnablePprof bool
+	// ShutdownConfig holds the timeouts of the graceful shutdown.
+	ShutdownConfig *ShutdownConfig
+	// CompressionConfig holds the compression of the LIST and WATCH responses.
+	CompressionConfig *CompressionConfig
+	// WatchCacheConfig selects the resources served from a watch cache.
+	WatchCacheConfig *WatchCacheConfig
+	// MetadataCompatibility serves the metadata of the machines of a provider in the
+	// format of a cloud metadata service as well, for images that expect one.
+	MetadataCompatibility map[Provider]MetadataFormat
+	// Dynamic holds the settings that can be changed at runtime through the dynamic
+	// configuration ConfigMap. It starts out with the settings above.
+	Dynamic *DynamicConfig
+}
+
+// ShutdownConfig holds the timeouts of the graceful shutdown of Kommodity.
+type ShutdownConfig struct {
+	// DrainPeriod is how long Kommodity keeps serving with a failing readiness check
+	// before it stops accepting connections.
+	DrainPeriod time.Duration
+	// Timeout bounds the whole shutdown, including the drain period. In-flight
+	// requests and reconciles still running when it expires are aborted.
+	Timeout time.Duration
+}
+
+// CompressionConfig holds the compression of the LIST and WATCH responses of the API
+// server for clients accepting gzip.
+type CompressionConfig struct {
+	// Enabled compresses the responses in Kommodity. Disabled, the API server still
+	// gzips responses above 128KiB itself, but never watches.
+	Enabled bool
+	// MinSize is the size in bytes above which LIST responses are compressed.
+	MinSize int
+	// Watches compresses the event streams of WATCH requests as well.
+	Watches bool
+}
+
+// DatabaseConfig holds the connection pool and query settings of the database kine
+// stores the objects in.
+type DatabaseConfig struct {
+	// MaxOpenConnections limits the connections to the database. Zero is unlimited.
+	MaxOpenConnections int
+	// MaxIdleConnections is the number of idle connections kept for reuse.
+	MaxIdleConnections int
+	// ConnectionMaxLifetime closes connections once they reached this age. Zero keeps
+	// them open.
+	ConnectionMaxLifetime time.Duration
+	// StatementTimeout aborts queries running longer on PostgreSQL. Zero disables it.
+	StatementTimeout time.Duration
+	// SlowQueryThreshold logs the queries running longer. Zero disables the log.
+	SlowQueryThreshold time.Duration
+	// CompactionInterval is how often the revisions replaced or deleted before the
+	// retained ones are pruned. Zero only compacts on demand.
+	CompactionInterval time.Duration
+	// CompactionRetention is the number of most recent revisions never compacted.
+	CompactionRetention int
+}
+
+// WatchCacheConfig selects the resources served from memory instead of kine, like the
+// watch cache of the upstream API server. The caches size themselves between 100 and
+// 102400 events, as upstream, there is no size to configure.
+type WatchCacheConfig struct {
+	// Resources maps a resource ("secrets") or group-qualified resource
+	// ("machines.cluster.x-k8s.io") to whether it is served from a watch cache.
+	Resources map[string]bool
+}
+
+// Enabled reports whether the resource is served from a watch cache. Resources not
+// listed fall back to the given default.
+func (c *WatchCacheConfig) Enabled(resource schema.GroupResource, fallback bool) bool {
+	if c == nil {
+		return fallback
+	}
+
+	enabled, found := c.Resources[resource.String()]
+	if !found {
+		return fallback
+	}
+
+	return enabled
+}
+
+// EncryptionProvider names the backend holding the key encryption key (KEK) used
+// to encrypt Secrets at rest.
+type EncryptionProvider string
+
+const (
+	// EncryptionProviderNone disables encryption at rest.
+	EncryptionProviderNone EncryptionProvider = ""
+	// EncryptionProviderStatic uses locally configured AES-256 keys.
+	EncryptionProviderStatic EncryptionProvider = "static"
+	// EncryptionProviderVault uses the HashiCorp Vault transit secrets engine.
+	EncryptionProviderVault EncryptionProvider = "vault"
+	// EncryptionProviderAWS uses AWS KMS.
+	EncryptionProviderAWS EncryptionProvider = "aws"
+	// EncryptionProviderGCP uses Google Cloud KMS.
+	EncryptionProviderGCP EncryptionProvider = "gcp"
+)
+
+// EncryptionConfig holds the configuration for envelope encryption of Secrets at rest.
+type EncryptionConfig struct {
+	Provider EncryptionProvider
+	// StaticKeys are the keys of the static provider. The first key encrypts new
+	// data, the others are only used to decrypt data written before a rotation.
+	StaticKeys   []StaticEncryptionKey
+	VaultAddress string
+	VaultToken   string
+	VaultMount   string
+	VaultKey     string
+	AWSKeyID     string
+	AWSRegion    string
+	// GCPKeyName is the full resource name of the Cloud KMS key,
+	// projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>.
+	GCPKeyName string
+}
+
+// StaticEncryptionKey is a named AES-256 key of the static encryption provider.
+type StaticEncryptionKey struct {
+	Name string
+	Key  []byte
+}
+
+// Enabled reports whether Secrets are encrypted at rest.
+func (c *EncryptionConfig) Enabled() bool {
+	return c != nil && c.Provider != EncryptionProviderNone
+}
+
+// SecretsBackendProvider names the external secret manager holding the data of
+// Secrets instead of kine.
+type SecretsBackendProvider string
+
+const (
+	// SecretsBackendNone keeps the data of all Secrets in kine.
+	SecretsBackendNone SecretsBackendProvider = ""
+	// SecretsBackendVault uses the HashiCorp Vault KV version 2 secrets engine.
+	SecretsBackendVault SecretsBackendProvider = "vault"
+	// SecretsBackendAWS uses AWS Secrets Manager.
+	SecretsBackendAWS SecretsBackendProvider = "aws"
+)
+
+// SecretsBackendConfig holds the configuration for keeping the data of Secrets in an
+// external secret manager.
+type SecretsBackendConfig struct {
+	Provider SecretsBackendProvider
+	// Types and Namespaces select the Secrets kept in the secret manager. Empty lists
+	// select Secrets of all types or in all namespaces.
+	Types      []string
+	Namespaces []string
+	// Prefix is prepended to the paths or names of the secrets in the secret manager.
+	Prefix string
+	// CacheTTL is how long data read from the secret manager is cached.
+	CacheTTL     time.Duration
+	VaultAddress string
+	VaultToken   string
+	VaultMount   string
+	AWSRegion    string
+}
+
+// Enabled reports whether Secrets are kept in an external secret manager.
+func (c *SecretsBackendConfig) Enabled() bool {
+	return c != nil && c.Provider != SecretsBackendNone
+}
+
+// SecretReadAuditConfig holds the configuration for logging reads of Secrets.
+type SecretReadAuditConfig struct {
+	// Enabled logs every get, list and watch of Secrets with the user, namespace and name.
+	Enabled bool
+	// Events also records the reads as Events on the Secrets.
+	Events bool
+	// ExcludeUsers and ExcludeGroups are the users, and members of the groups, whose
+	// reads are not logged, such as system components.
+	ExcludeUsers  []string
+	ExcludeGroups []string
+}
+
+// ACMEConfig holds the configuration for serving the public listener over TLS with
+// a certificate obtained from an ACME CA such as Let's Encrypt.
+type ACMEConfig struct {
+	Email        string
+	Domains      []string
+	CacheDir     string
+	DirectoryURL string
+}
+
+// SPIFFEConfig holds the configuration for serving the public listener with a SPIFFE
+// SVID from the Workload API, and for accepting the SVIDs of machine agents.
+type SPIFFEConfig struct {
+	// EndpointSocket is the address of the Workload API, such as
+	// unix:///run/spire/sockets/agent.sock. SPIFFE is disabled when it is empty.
+	EndpointSocket string
+	// AllowedIDs lists the SPIFFE IDs accepted from agents, including the IDs below
+	// them. When empty, the trust domain of Kommodity is accepted.
+	AllowedIDs []string
+}
+
+// Enabled reports whether SPIFFE has been configured.
+func (c *SPIFFEConfig) Enabled() bool {
+	return c != nil && c.EndpointSocket != ""
+}
+
+// CORSConfig holds the cross-origin resource sharing policy of the HTTP endpoints,
+// which lets browser applications served from other origins call them.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the endpoints, "*" allows any.
+	// CORS is disabled when it is empty.
+	AllowedOrigins []string
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers browsers may send, "*" allows any.
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and the Authorization header.
+	AllowCredentials bool
+}
+
+// TLSConfig restricts the TLS versions and cipher suites the TLS listeners accept.
+// Zero values leave the choice to Go, whose defaults are secure.
+type TLSConfig struct {
+	MinVersion uint16
+	// MaxVersion is not supported by the API server listener.
+	MaxVersion uint16
+	// CipherSuites only apply to TLS 1.2, TLS 1.3 cipher suites are not configurable.
+	CipherSuites []uint16
+}
+
+// BackupConfig holds the configuration for snapshots of the kine key space stored in
+// an S3-compatible object store. Backups are disabled unless a bucket is set.
+type BackupConfig struct {
+	// Endpoint is the base URL of the object store. Objects are addressed path-style.
+	// If empty, the AWS S3 endpoint of the region is used.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Interval between periodic snapshots. Zero disables periodic snapshots.
+	Interval time.Duration
+	// RestoreSnapshot names a snapshot to restore into an empty database at startup.
+	RestoreSnapshot string
+}
+
+// LoadSheddingConfig holds the configuration for rejecting expensive LIST requests under load.
+type LoadSheddingConfig struct {
+	// Resources maps a resource ("events") or group-qualified resource
+	// ("machines.cluster.x-k8s.io") to the number of in-flight requests at which
+	// unbounded LIST requests for it are rejected. An empty map disables shedding.
+	Resources map[string]int
+	// RetryAfter is the delay advertised to clients in the Retry-After header.
+	RetryAfter time.Duration
+	// MinObjects is the number of stored objects from which a collection is large
+	// enough for its unbounded LIST requests to be rejected. Collections whose size
+	// is not known are treated as large. Zero sheds regardless of the size.
+	MinObjects int
+}
+
+// RateLimitConfig holds the token bucket settings for API requests, per authenticated
+// user and per source IP. A QPS of zero disables the respective limit.
+type RateLimitConfig struct {
+	UserQPS   float64
+	UserBurst int
+	IPQPS     float64
+	IPBurst   int
+}
+
+// PriorityQueueingConfig holds the concurrency limits of the priority levels API
+// requests are classified into. A limit of zero leaves the level unlimited; with both
+// limits at zero priority queueing is disabled.
+type PriorityQueueingConfig struct {
+	// InteractiveConcurrency is the number of concurrent requests served for human users.
+	InteractiveConcurrency int
+	// WorkloadConcurrency is the number of concurrent requests served for controllers,
+	// service accounts and other system users.
+	WorkloadConcurrency int
+	// QueueLength is the number of requests a level queues once its limit is reached.
+	QueueLength int
+	// QueueTimeout is how long a request waits in the queue before it is rejected.
+	QueueTimeout time.Duration
 }
 
 // AzureConfig holds configuration for the embedded Azure integration.
@@ -116,6 +581,33 @@ type GarbageCollectorConfig struct {
 	InitialSyncTimeout time.Duration
 }
 
+// ControllerConfig holds the throughput settings of the controllers of the controller
+// manager.
+type ControllerConfig struct {
+	// MaxConcurrentReconciles is the number of concurrent reconciles of a controller.
+	MaxConcurrentReconciles int
+	// GroupKindConcurrency overrides it for the controllers of a kind, keyed like
+	// "Machine.cluster.x-k8s.io".
+	GroupKindConcurrency map[string]int
+	// RateLimitBaseDelay and RateLimitMaxDelay bound the exponential backoff of the
+	// requeues of a failing object.
+	RateLimitBaseDelay time.Duration
+	RateLimitMaxDelay  time.Duration
+	// RateLimitQPS and RateLimitBurst limit the requeues of each controller overall.
+	RateLimitQPS   float64
+	RateLimitBurst int
+	// SyncPeriod is how often the informers resync, reconciling every object again.
+	SyncPeriod time.Duration
+	// WatchNamespaces restricts the informers to these namespaces. Empty watches every
+	// namespace.
+	WatchNamespaces []string
+	// SecretLabelSelector and ConfigMapLabelSelector restrict the Secrets and ConfigMaps
+	// the informers hold, and so the ones the controllers are notified about. Nil holds
+	// every one.
+	SecretLabelSelector    labels.Selector
+	ConfigMapLabelSelector labels.Selector
+}
+
 // TalosProxyConfig holds the configuration for the transparent Talos gRPC proxy.
 type TalosProxyConfig struct {
 	Enabled          bool
@@ -130,16 +622,82 @@ type AuthConfig struct {
 	Apply      bool
 	OIDCConfig *OIDCConfig
 	AdminGroup string
+	// ClientCAFile is the path to a PEM bundle of CAs used to verify client
+	// certificates of operators on the combined server. The certificate CN becomes
+	// the user name and O the groups.
+	ClientCAFile string
+	// HTTPAuthConfig protects the metadata and attestation endpoints with OIDC
+	// bearer tokens.
+	HTTPAuthConfig *HTTPAuthConfig
+	// AuthzWebhookConfig delegates requests the admin group check does not allow to
+	// an external authorizer. Nil when no authorization webhook is configured.
+	AuthzWebhookConfig *AuthzWebhookConfig
+}
+
+// AuthzWebhookConfig holds the settings of the authorization webhook.
+type AuthzWebhookConfig struct {
+	// KubeconfigFile points at the authorizer, like --authorization-webhook-config-file
+	// of kube-apiserver.
+	KubeconfigFile string
+	// AuthorizedTTL and UnauthorizedTTL are how long decisions are cached.
+	AuthorizedTTL   time.Duration
+	UnauthorizedTTL time.Duration
+}
+
+// HTTPAuthConfig holds the authentication settings of the plain HTTP endpoints.
+type HTTPAuthConfig struct {
+	Enabled bool
+	// ExemptPaths are request paths served without a bearer token.
+	ExemptPaths []string
+	// ClientCAFile and ClientCASecret hold the CA bundle client certificates of machine
+	// agents are verified against. The Secret lives in the Kommodity namespace.
+	ClientCAFile   string
+	ClientCASecret string
+}
+
+// ClientCertificatesEnabled reports whether client certificates are accepted.
+func (c *HTTPAuthConfig) ClientCertificatesEnabled() bool {
+	return c != nil && (c.ClientCAFile != "" || c.ClientCASecret != "")
 }
 
+// MetadataFormat names the cloud metadata service whose format the metadata of
+// machines is served in.
+type MetadataFormat string
+
+const (
+	// MetadataFormatEC2 serves the metadata under /latest/meta-data/, like the EC2
+	// instance metadata service.
+	MetadataFormatEC2 MetadataFormat = "ec2"
+	// MetadataFormatOpenStack serves the metadata as /openstack/latest/meta_data.json,
+	// like the OpenStack metadata service.
+	MetadataFormatOpenStack MetadataFormat = "openstack"
+)
+
+// NonceStoreBackend names where attestation nonces are stored.
+type NonceStoreBackend string
+
+const (
+	// NonceStoreMemory keeps nonces in memory, so they can only be used with the
+	// instance that issued them.
+	NonceStoreMemory NonceStoreBackend = "memory"
+	// NonceStoreDatabase keeps nonces in kine, so they can be used with any instance
+	// sharing the database.
+	NonceStoreDatabase NonceStoreBackend = "database"
+)
+
 // AttestationConfig holds the attestation configuration settings for the Kommodity API server.
 type AttestationConfig struct {
-	NonceTTL time.Duration
+	NonceTTL   time.Duration
+	NonceStore NonceStoreBackend
 }
 
 // ClientConfig holds the client configuration settings for the Kommodity API server.
 type ClientConfig struct {
 	LoopbackClientConfig *restclient.Config
+	// FrontProxyCertificate authenticates the proxy of the combined server to the API
+	// server, which then takes the identity of operators authenticated with a client
+	// certificate from its X-Remote-* headers. Nil unless ClientCAFile is set.
+	FrontProxyCertificate *tls.Certificate
 }
 
 // OIDCConfig holds the OIDC configuration settings from the environment variables.
@@ -157,6 +715,7 @@ func LoadConfig(ctx context.Context) (*KommodityConfig, error) {
 	serverPort := getServerPort(ctx)
 	apply := getApplyAuth(ctx)
 	oidcConfig := getOIDCConfig(ctx)
+	clientCAFile := getClientCAFile(ctx)
 	developmentMode := getDevelopmentMode(ctx)
 	kineURI := getKineURI(ctx)
 	infrastructureProviders := getInfrastructureProviders(ctx)
@@ -166,6 +725,17 @@ func LoadConfig(ctx context.Context) (*KommodityConfig, error) {
 		return nil, fmt.Errorf("failed to get admin group: %w", err)
 	}
 
+	spiffeConfig, err := getSPIFFEConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SPIFFE config: %w", err)
+	}
+
+	httpAuthConfig := getHTTPAuthConfig(ctx)
+	if httpAuthConfig.Enabled && oidcConfig == nil && !httpAuthConfig.ClientCertificatesEnabled() &&
+		!spiffeConfig.Enabled() {
+		return nil, ErrHTTPAuthWithoutOIDC
+	}
+
 	dbURI, err := getDatabaseURI()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database URI: %w", err)
@@ -174,27 +744,107 @@ func LoadConfig(ctx context.Context) (*KommodityConfig, error) {
 	talosProxyConfig := getTalosProxyConfig(ctx)
 	garbageCollectorConfig := getGarbageCollectorConfig(ctx)
 	azureConfig := getAzureConfig(ctx)
+	loadSheddingConfig := getLoadSheddingConfig(ctx)
+	acmeConfig := getACMEConfig(ctx)
+	if spiffeConfig.Enabled() && len(acmeConfig.Domains) > 0 {
+		return nil, ErrSPIFFEWithACME
+	}
+
+	if httpAuthConfig.ClientCertificatesEnabled() && len(acmeConfig.Domains) == 0 && !spiffeConfig.Enabled() {
+		return nil, ErrClientCAWithoutTLS
+	}
+
+	corsConfig := getCORSConfig(ctx)
+	if corsConfig.AllowCredentials && slices.Contains(corsConfig.AllowedOrigins, "*") {
+		return nil, ErrCORSCredentialsWithAnyOrigin
+	}
+
+	tlsConfig, err := getTLSConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TLS config: %w", err)
+	}
+
+	encryptionConfig, err := getEncryptionConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption config: %w", err)
+	}
+
+	secretsBackendConfig, err := getSecretsBackendConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secrets backend config: %w", err)
+	}
+
+	attestationConfig, err := getAttestationConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attestation config: %w", err)
+	}
+
+	bindAddresses, err := getBindAddresses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bind addresses: %w", err)
+	}
+
+	apiServerBindAddress, err := getAPIServerBindAddress(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API server bind address: %w", err)
+	}
+
+	rateLimitConfig := getRateLimitConfig(ctx)
+	clusterHealthInterval := getClusterHealthInterval(ctx)
 
 	return &KommodityConfig{
-		BaseURL:             baseURL,
-		ServerPort:          serverPort,
-		APIServerPort:       getAPIServerPort(ctx),
-		WebhookPort:         ctrlwebhook.DefaultPort,
-		DBURI:               dbURI,
-		KineURI:             kineURI,
-		AttestationConfig:   getAttestationConfig(ctx),
-		AuditPolicyFilePath: getAuditPolicyFilePath(ctx),
+		BaseURL:              baseURL,
+		ServerPort:           serverPort,
+		ListenAddresses:      getListenAddresses(ctx),
+		BindAddresses:        bindAddresses,
+		APIServerPort:        getAPIServerPort(ctx),
+		APIServerBindAddress: apiServerBindAddress,
+		APIServerCertSANs:    splitCommaSeparated(os.Getenv(envAPIServerCertSANs)),
+		WebhookPort:          ctrlwebhook.DefaultPort,
+		DBURI:                dbURI,
+		KineURI:              kineURI,
+		KineMaxRestarts:      getKineMaxRestarts(ctx),
+		DatabaseConfig:       getDatabaseConfig(ctx),
+		AttestationConfig:    attestationConfig,
+		AuditPolicyFilePath:  getAuditPolicyFilePath(ctx),
 		AuthConfig: &AuthConfig{
-			Apply:      apply,
-			OIDCConfig: oidcConfig,
-			AdminGroup: adminGroup,
+			Apply:              apply,
+			OIDCConfig:         oidcConfig,
+			AdminGroup:         adminGroup,
+			ClientCAFile:       clientCAFile,
+			HTTPAuthConfig:     httpAuthConfig,
+			AuthzWebhookConfig: getAuthzWebhookConfig(ctx),
 		},
 		ClientConfig:            &ClientConfig{},
 		TalosProxyConfig:        talosProxyConfig,
 		GarbageCollectorConfig:  garbageCollectorConfig,
+		ControllerConfig:        getControllerConfig(ctx),
 		DevelopmentMode:         developmentMode,
 		InfrastructureProviders: infrastructureProviders,
 		AzureConfig:             azureConfig,
+		LoadSheddingConfig:      loadSheddingConfig,
+		RateLimitConfig:         rateLimitConfig,
+		PriorityQueueingConfig:  getPriorityQueueingConfig(ctx),
+		ACMEConfig:              acmeConfig,
+		CORSConfig:              corsConfig,
+		TLSConfig:               tlsConfig,
+		SPIFFEConfig:            spiffeConfig,
+		EncryptionConfig:        encryptionConfig,
+		SecretsBackendConfig:    secretsBackendConfig,
+		SecretReadAuditConfig:   getSecretReadAuditConfig(ctx),
+		BackupConfig:            getBackupConfig(ctx),
+		ClusterHealthInterval:   clusterHealthInterval,
+		EventTTL:                getEventTTL(ctx),
+		EnablePprof:             getEnablePprof(ctx),
+		ShutdownConfig:          getShutdownConfig(ctx),
+		CompressionConfig:       getCompressionConfig(ctx),
+		WatchCacheConfig:        getWatchCacheConfig(ctx),
+		MetadataCompatibility:   getMetadataCompatibility(ctx),
+		Dynamic: NewDynamicConfig(Tunables{
+			LogLevel:              logging.FromContext(ctx).Level(),
+			RateLimit:             *rateLimitConfig,
+			ClusterHealthInterval: clusterHealthInterval,
+		}),
 	}, nil
 }
 
@@ -285,6 +935,79 @@ func getServerPort(ctx context.Context) int {
 	return serverPortInt
 }
 
+func getListenAddresses(ctx context.Context) []string {
+	logger := logging.FromContext(ctx)
+
+	addresses := splitCommaSeparated(os.Getenv(envListen))
+	if len(addresses) == 0 {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envListen),
+			zap.String("default", "port only"))
+	}
+
+	return addresses
+}
+
+func getBindAddresses(ctx context.Context) ([]net.IP, error) {
+	logger := logging.FromContext(ctx)
+
+	values := splitCommaSeparated(os.Getenv(envBindAddresses))
+	if len(values) == 0 {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envBindAddresses),
+			zap.String("default", "all interfaces"))
+
+		return nil, nil
+	}
+
+	addresses := make([]net.IP, 0, len(values))
+
+	for _, value := range values {
+		address := net.ParseIP(value)
+		if address == nil {
+			return nil, fmt.Errorf("%w: %s: %q", ErrInvalidIPAddress, envBindAddresses, value)
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	// Kommodity calls its own endpoints over localhost.
+	if !slices.ContainsFunc(addresses, func(address net.IP) bool {
+		return address.IsLoopback() || address.IsUnspecified()
+	}) {
+		return nil, fmt.Errorf("%w: %s", ErrBindAddressesWithoutLoopback, envBindAddresses)
+	}
+
+	return addresses, nil
+}
+
+// getAPIServerBindAddress returns the address the internal API server listens on.
+// Kommodity reaches it over loopback, so only loopback addresses or all interfaces
+// ("0.0.0.0", "::") are accepted.
+func getAPIServerBindAddress(ctx context.Context) (net.IP, error) {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envAPIServerBindAddress)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envAPIServerBindAddress),
+			zap.String("default", defaultAPIServerBindAddress))
+
+		return net.ParseIP(defaultAPIServerBindAddress), nil
+	}
+
+	address := net.ParseIP(value)
+	if address == nil {
+		return nil, fmt.Errorf("%w: %s: %q", ErrInvalidIPAddress, envAPIServerBindAddress, value)
+	}
+
+	if !address.IsLoopback() && !address.IsUnspecified() {
+		return nil, fmt.Errorf("%w: %s: %q", ErrAPIServerBindAddressNotLocal, envAPIServerBindAddress, value)
+	}
+
+	return address, nil
+}
+
 func getAPIServerPort(ctx context.Context) int {
 	logger := logging.FromContext(ctx)
 
@@ -369,6 +1092,21 @@ func getOIDCConfig(ctx context.Context) *OIDCConfig {
 	}
 }
 
+func getClientCAFile(ctx context.Context) string {
+	logger := logging.FromContext(ctx)
+
+	clientCAFile := os.Getenv(envClientCAFile)
+	if clientCAFile == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envClientCAFile),
+			zap.String("default", ""))
+
+		return ""
+	}
+
+	return clientCAFile
+}
+
 func getAdminGroup() (string, error) {
 	adminGroup := os.Getenv(envAdminGroup)
 	if adminGroup == "" {
@@ -392,7 +1130,163 @@ func getDatabaseURI() (*url.URL, error) {
 	return uri, nil
 }
 
-func getAttestationConfig(ctx context.Context) *AttestationConfig {
+func getHTTPAuthConfig(ctx context.Context) *HTTPAuthConfig {
+	return &HTTPAuthConfig{
+		Enabled:        getHTTPAuthEnabled(ctx),
+		ExemptPaths:    getHTTPAuthExemptPaths(ctx),
+		ClientCAFile:   os.Getenv(envHTTPClientCAFile),
+		ClientCASecret: os.Getenv(envHTTPClientCASecret),
+	}
+}
+
+func getHTTPAuthEnabled(ctx context.Context) bool {
+	logger := logging.FromContext(ctx)
+
+	enabled := os.Getenv(envHTTPAuthEnabled)
+	if enabled == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envHTTPAuthEnabled),
+			zap.Bool("default", defaultHTTPAuthEnabled))
+
+		return defaultHTTPAuthEnabled
+	}
+
+	enabledBool, err := strconv.ParseBool(enabled)
+	if err != nil {
+		logger.Info("failed to convert HTTP auth enabled to boolean",
+			zap.String("envVar", envHTTPAuthEnabled),
+			zap.String("value", enabled),
+			zap.Bool("default", defaultHTTPAuthEnabled))
+
+		return defaultHTTPAuthEnabled
+	}
+
+	return enabledBool
+}
+
+// getHTTPAuthExemptPaths parses a comma-separated list of request paths. Setting the
+// variable to a single comma exempts no path at all.
+func getHTTPAuthExemptPaths(ctx context.Context) []string {
+	logger := logging.FromContext(ctx)
+
+	value, found := os.LookupEnv(envHTTPAuthExemptPaths)
+	if !found {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envHTTPAuthExemptPaths),
+			zap.String("default", defaultHTTPAuthExemptPaths))
+
+		value = defaultHTTPAuthExemptPaths
+	}
+
+	paths := []string{}
+
+	for path := range strings.SplitSeq(value, ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+func getAuthzWebhookConfig(ctx context.Context) *AuthzWebhookConfig {
+	logger := logging.FromContext(ctx)
+
+	kubeconfigFile := os.Getenv(envAuthzWebhookKubeconfig)
+	if kubeconfigFile == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envAuthzWebhookKubeconfig),
+			zap.String("default", "disabled"))
+
+		return nil
+	}
+
+	return &AuthzWebhookConfig{
+		KubeconfigFile:  kubeconfigFile,
+		AuthorizedTTL:   getAuthzWebhookAuthorizedTTL(ctx),
+		UnauthorizedTTL: getAuthzWebhookUnauthorizedTTL(ctx),
+	}
+}
+
+func getAuthzWebhookAuthorizedTTL(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	ttl := os.Getenv(envAuthzWebhookAuthorizedTTL)
+	if ttl == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envAuthzWebhookAuthorizedTTL),
+			zap.String("default", defaultAuthzWebhookAuthorizedTTL.String()))
+
+		return defaultAuthzWebhookAuthorizedTTL
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		logger.Info("failed to parse authorization webhook authorized TTL",
+			zap.String("envVar", envAuthzWebhookAuthorizedTTL),
+			zap.String("value", ttl),
+			zap.String("default", defaultAuthzWebhookAuthorizedTTL.String()))
+
+		return defaultAuthzWebhookAuthorizedTTL
+	}
+
+	return duration
+}
+
+func getAuthzWebhookUnauthorizedTTL(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	ttl := os.Getenv(envAuthzWebhookUnauthorizedTTL)
+	if ttl == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envAuthzWebhookUnauthorizedTTL),
+			zap.String("default", defaultAuthzWebhookUnauthorizedTTL.String()))
+
+		return defaultAuthzWebhookUnauthorizedTTL
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		logger.Info("failed to parse authorization webhook unauthorized TTL",
+			zap.String("envVar", envAuthzWebhookUnauthorizedTTL),
+			zap.String("value", ttl),
+			zap.String("default", defaultAuthzWebhookUnauthorizedTTL.String()))
+
+		return defaultAuthzWebhookUnauthorizedTTL
+	}
+
+	return duration
+}
+
+// getAttestationConfig reads the attestation settings. An unknown nonce store is an
+// error rather than a fallback to memory, which would break attestation across
+// instances.
+func getAttestationConfig(ctx context.Context) (*AttestationConfig, error) {
+	logger := logging.FromContext(ctx)
+
+	attestationConfig := &AttestationConfig{
+		NonceTTL:   getAttestationNonceTTL(ctx),
+		NonceStore: NonceStoreBackend(os.Getenv(envAttestationNonceStore)),
+	}
+
+	switch attestationConfig.NonceStore {
+	case "":
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envAttestationNonceStore),
+			zap.String("default", string(NonceStoreMemory)))
+
+		attestationConfig.NonceStore = NonceStoreMemory
+	case NonceStoreMemory, NonceStoreDatabase:
+	default:
+		return nil, fmt.Errorf("%w: unknown nonce store %q", ErrInvalidAttestationConfig,
+			attestationConfig.NonceStore)
+	}
+
+	return attestationConfig, nil
+}
+
+func getAttestationNonceTTL(ctx context.Context) time.Duration {
 	logger := logging.FromContext(ctx)
 
 	nonceTTLStr := os.Getenv(envAttestationNonceTTL)
@@ -401,9 +1295,7 @@ func getAttestationConfig(ctx context.Context) *AttestationConfig {
 			zap.String("envVar", envAttestationNonceTTL),
 			zap.String("default", defaultAttestationNonceTTL.String()))
 
-		return &AttestationConfig{
-			NonceTTL: defaultAttestationNonceTTL,
-		}
+		return defaultAttestationNonceTTL
 	}
 
 	nonceTTL, err := time.ParseDuration(nonceTTLStr)
@@ -413,14 +1305,10 @@ func getAttestationConfig(ctx context.Context) *AttestationConfig {
 			zap.String("value", nonceTTLStr),
 			zap.String("default", defaultAttestationNonceTTL.String()))
 
-		return &AttestationConfig{
-			NonceTTL: defaultAttestationNonceTTL,
-		}
+		return defaultAttestationNonceTTL
 	}
 
-	return &AttestationConfig{
-		NonceTTL: nonceTTL,
-	}
+	return nonceTTL
 }
 
 func getDevelopmentMode(ctx context.Context) bool {
@@ -448,6 +1336,31 @@ func getDevelopmentMode(ctx context.Context) bool {
 	return developmentModeBool
 }
 
+func getEnablePprof(ctx context.Context) bool {
+	logger := logging.FromContext(ctx)
+
+	enablePprof := os.Getenv(envEnablePprof)
+	if enablePprof == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envEnablePprof),
+			zap.Bool("default", defaultEnablePprof))
+
+		return defaultEnablePprof
+	}
+
+	enablePprofBool, err := strconv.ParseBool(enablePprof)
+	if err != nil {
+		logger.Info("failed to convert enable pprof to boolean",
+			zap.String("envVar", envEnablePprof),
+			zap.String("value", enablePprof),
+			zap.Bool("default", defaultEnablePprof))
+
+		return defaultEnablePprof
+	}
+
+	return enablePprofBool
+}
+
 func getKineURI(ctx context.Context) string {
 	logger := logging.FromContext(ctx)
 
@@ -463,6 +1376,31 @@ func getKineURI(ctx context.Context) string {
 	return kineURI
 }
 
+func getKineMaxRestarts(ctx context.Context) int {
+	logger := logging.FromContext(ctx)
+
+	maxRestarts := os.Getenv(envKineMaxRestarts)
+	if maxRestarts == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envKineMaxRestarts),
+			zap.Int("default", defaultKineMaxRestarts))
+
+		return defaultKineMaxRestarts
+	}
+
+	maxRestartsInt, err := strconv.Atoi(maxRestarts)
+	if err != nil || maxRestartsInt < 0 {
+		logger.Info("failed to convert kine max restarts to non-negative integer",
+			zap.String("envVar", envKineMaxRestarts),
+			zap.String("value", maxRestarts),
+			zap.Int("default", defaultKineMaxRestarts))
+
+		return defaultKineMaxRestarts
+	}
+
+	return maxRestartsInt
+}
+
 func getInfrastructureProviders(ctx context.Context) []Provider {
 	logger := logging.FromContext(ctx)
 
@@ -497,22 +1435,57 @@ func getInfrastructureProviders(ctx context.Context) []Provider {
 	return providers
 }
 
-func getAuditPolicyFilePath(ctx context.Context) string {
+// getMetadataCompatibility parses a comma-separated list of provider=format pairs,
+// e.g. "kubevirt=openstack,scaleway=ec2". Invalid entries are skipped.
+func getMetadataCompatibility(ctx context.Context) map[Provider]MetadataFormat {
 	logger := logging.FromContext(ctx)
 
-	policyFilePath := os.Getenv(envAuditPolicyFilePath)
-	if policyFilePath == "" {
+	formats := map[Provider]MetadataFormat{}
+
+	value := os.Getenv(envMetadataCompatibility)
+	if value == "" {
 		logger.Info(configurationNotSpecified,
-			zap.String("envVar", envAuditPolicyFilePath),
-			zap.String("default", ""))
+			zap.String("envVar", envMetadataCompatibility),
+			zap.String("default", "disabled"))
 
-		return ""
+		return formats
 	}
 
-	return policyFilePath
-}
+	for entry := range strings.SplitSeq(value, ",") {
+		provider, format, found := strings.Cut(strings.TrimSpace(entry), "=")
 
-func getTalosProxyConfig(ctx context.Context) *TalosProxyConfig {
+		metadataFormat := MetadataFormat(strings.TrimSpace(format))
+		if !found || !slices.Contains(GetAllProviders(), Provider(strings.TrimSpace(provider))) ||
+			(metadataFormat != MetadataFormatEC2 && metadataFormat != MetadataFormatOpenStack) {
+			logger.Info("failed to parse metadata compatibility entry, skipping",
+				zap.String("envVar", envMetadataCompatibility),
+				zap.String("value", entry))
+
+			continue
+		}
+
+		formats[Provider(strings.TrimSpace(provider))] = metadataFormat
+	}
+
+	return formats
+}
+
+func getAuditPolicyFilePath(ctx context.Context) string {
+	logger := logging.FromContext(ctx)
+
+	policyFilePath := os.Getenv(envAuditPolicyFilePath)
+	if policyFilePath == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envAuditPolicyFilePath),
+			zap.String("default", ""))
+
+		return ""
+	}
+
+	return policyFilePath
+}
+
+func getTalosProxyConfig(ctx context.Context) *TalosProxyConfig {
 	return &TalosProxyConfig{
 		Enabled:          getTalosProxyEnabled(ctx),
 		ListenPort:       getTalosProxyPort(ctx),
@@ -735,3 +1708,1135 @@ func getGarbageCollectorInitialSyncTimeout(ctx context.Context) time.Duration {
 
 	return duration
 }
+
+func getControllerConfig(ctx context.Context) *ControllerConfig {
+	return &ControllerConfig{
+		MaxConcurrentReconciles: getControllerInt(ctx, envControllerMaxConcurrency, defaultControllerMaxConcurrency),
+		GroupKindConcurrency:    getControllerConcurrency(ctx),
+		RateLimitBaseDelay: getControllerDuration(ctx, envControllerRateLimitBaseDelay,
+			defaultControllerRateLimitBaseDelay),
+		RateLimitMaxDelay: getControllerDuration(ctx, envControllerRateLimitMaxDelay,
+			defaultControllerRateLimitMaxDelay),
+		RateLimitQPS:           getControllerRateLimitQPS(ctx),
+		RateLimitBurst:         getControllerInt(ctx, envControllerRateLimitBurst, defaultControllerRateLimitBurst),
+		SyncPeriod:             getControllerDuration(ctx, envControllerSyncPeriod, defaultControllerSyncPeriod),
+		WatchNamespaces:        getControllerWatchNamespaces(ctx),
+		SecretLabelSelector:    getControllerLabelSelector(ctx, envControllerSecretLabelSelector),
+		ConfigMapLabelSelector: getControllerLabelSelector(ctx, envControllerConfigMapLabelSelector),
+	}
+}
+
+func getControllerInt(ctx context.Context, envVar string, defaultValue int) int {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.Int("default", defaultValue))
+
+		return defaultValue
+	}
+
+	valueInt, err := strconv.Atoi(value)
+	if err != nil || valueInt < 1 {
+		logger.Info("failed to convert controller setting to positive integer",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.Int("default", defaultValue))
+
+		return defaultValue
+	}
+
+	return valueInt
+}
+
+func getControllerDuration(ctx context.Context, envVar string, defaultValue time.Duration) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", defaultValue.String()))
+
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		logger.Info("failed to parse controller duration",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.String("default", defaultValue.String()))
+
+		return defaultValue
+	}
+
+	return duration
+}
+
+func getControllerRateLimitQPS(ctx context.Context) float64 {
+	logger := logging.FromContext(ctx)
+
+	qps := os.Getenv(envControllerRateLimitQPS)
+	if qps == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envControllerRateLimitQPS),
+			zap.Int("default", defaultControllerRateLimitQPS))
+
+		return defaultControllerRateLimitQPS
+	}
+
+	qpsFloat, err := strconv.ParseFloat(qps, 64)
+	if err != nil || qpsFloat <= 0 {
+		logger.Info("failed to parse controller rate limit QPS",
+			zap.String("envVar", envControllerRateLimitQPS),
+			zap.String("value", qps),
+			zap.Int("default", defaultControllerRateLimitQPS))
+
+		return defaultControllerRateLimitQPS
+	}
+
+	return qpsFloat
+}
+
+// getControllerWatchNamespaces parses a comma-separated list of namespaces. The
+// Kommodity namespace is always watched, as the controllers keep their own state there.
+func getControllerWatchNamespaces(ctx context.Context) []string {
+	value := os.Getenv(envControllerWatchNamespaces)
+	if value == "" {
+		logging.FromContext(ctx).Info(configurationNotSpecified,
+			zap.String("envVar", envControllerWatchNamespaces),
+			zap.String("default", "all namespaces"))
+
+		return nil
+	}
+
+	namespaces := []string{KommodityNamespace}
+
+	for namespace := range strings.SplitSeq(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces
+}
+
+func getControllerLabelSelector(ctx context.Context, envVar string) labels.Selector {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", "none"))
+
+		return nil
+	}
+
+	selector, err := labels.Parse(value)
+	if err != nil {
+		logger.Info("failed to parse controller label selector",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.String("default", "none"),
+			zap.Error(err))
+
+		return nil
+	}
+
+	return selector
+}
+
+// getControllerConcurrency parses a comma-separated list of kind=concurrency pairs,
+// e.g. "Machine.cluster.x-k8s.io=20,TalosConfig.bootstrap.cluster.x-k8s.io=5".
+// Invalid entries are skipped.
+func getControllerConcurrency(ctx context.Context) map[string]int {
+	logger := logging.FromContext(ctx)
+
+	concurrency := map[string]int{}
+
+	value := os.Getenv(envControllerConcurrency)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envControllerConcurrency),
+			zap.String("default", "none"))
+
+		return concurrency
+	}
+
+	for entry := range strings.SplitSeq(value, ",") {
+		groupKind, reconciles, found := strings.Cut(strings.TrimSpace(entry), "=")
+
+		reconcilesInt, err := strconv.Atoi(strings.TrimSpace(reconciles))
+		if !found || groupKind == "" || err != nil || reconcilesInt < 1 {
+			logger.Info("failed to parse controller concurrency entry, skipping",
+				zap.String("envVar", envControllerConcurrency),
+				zap.String("value", entry))
+
+			continue
+		}
+
+		concurrency[strings.TrimSpace(groupKind)] = reconcilesInt
+	}
+
+	return concurrency
+}
+
+func getLoadSheddingConfig(ctx context.Context) *LoadSheddingConfig {
+	return &LoadSheddingConfig{
+		Resources:  getListLoadSheddingResources(ctx),
+		RetryAfter: getListLoadSheddingRetryAfter(ctx),
+		MinObjects: getListLoadSheddingMinObjects(ctx),
+	}
+}
+
+// getListLoadSheddingResources parses a comma-separated list of resource=threshold
+// pairs, e.g. "events=200,machines.cluster.x-k8s.io=100". Invalid entries are skipped.
+func getListLoadSheddingResources(ctx context.Context) map[string]int {
+	logger := logging.FromContext(ctx)
+
+	resources := map[string]int{}
+
+	value := os.Getenv(envListLoadSheddingResources)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envListLoadSheddingResources),
+			zap.String("default", "disabled"))
+
+		return resources
+	}
+
+	for entry := range strings.SplitSeq(value, ",") {
+		resource, threshold, found := strings.Cut(strings.TrimSpace(entry), "=")
+
+		thresholdInt, err := strconv.Atoi(strings.TrimSpace(threshold))
+		if !found || resource == "" || err != nil || thresholdInt < 1 {
+			logger.Info("failed to parse list load shedding entry, skipping",
+				zap.String("envVar", envListLoadSheddingResources),
+				zap.String("value", entry))
+
+			continue
+		}
+
+		resources[strings.TrimSpace(resource)] = thresholdInt
+	}
+
+	return resources
+}
+
+func getListLoadSheddingRetryAfter(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	retryAfter := os.Getenv(envListLoadSheddingRetryAfter)
+	if retryAfter == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envListLoadSheddingRetryAfter),
+			zap.String("default", defaultListLoadSheddingRetryAfter.String()))
+
+		return defaultListLoadSheddingRetryAfter
+	}
+
+	duration, err := time.ParseDuration(retryAfter)
+	if err != nil || duration < time.Second {
+		logger.Info("failed to parse list load shedding retry after",
+			zap.String("envVar", envListLoadSheddingRetryAfter),
+			zap.String("value", retryAfter),
+			zap.String("default", defaultListLoadSheddingRetryAfter.String()))
+
+		return defaultListLoadSheddingRetryAfter
+	}
+
+	return duration
+}
+
+func getListLoadSheddingMinObjects(ctx context.Context) int {
+	logger := logging.FromContext(ctx)
+
+	minObjects := os.Getenv(envListLoadSheddingMinObjects)
+	if minObjects == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envListLoadSheddingMinObjects),
+			zap.Int("default", defaultListLoadSheddingMinObjects))
+
+		return defaultListLoadSheddingMinObjects
+	}
+
+	minObjectsInt, err := strconv.Atoi(minObjects)
+	if err != nil || minObjectsInt < 0 {
+		logger.Info("failed to parse list load shedding minimum objects",
+			zap.String("envVar", envListLoadSheddingMinObjects),
+			zap.String("value", minObjects),
+			zap.Int("default", defaultListLoadSheddingMinObjects))
+
+		return defaultListLoadSheddingMinObjects
+	}
+
+	return minObjectsInt
+}
+
+func getRateLimitConfig(ctx context.Context) *RateLimitConfig {
+	return &RateLimitConfig{
+		UserQPS:   getRateLimitQPS(ctx, envRateLimitUserQPS),
+		UserBurst: getRateLimitBurst(ctx, envRateLimitUserBurst),
+		IPQPS:     getRateLimitQPS(ctx, envRateLimitIPQPS),
+		IPBurst:   getRateLimitBurst(ctx, envRateLimitIPBurst),
+	}
+}
+
+func getRateLimitQPS(ctx context.Context, envVar string) float64 {
+	logger := logging.FromContext(ctx)
+
+	qps := os.Getenv(envVar)
+	if qps == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", "disabled"))
+
+		return 0
+	}
+
+	qpsFloat, err := strconv.ParseFloat(qps, 64)
+	if err != nil || qpsFloat < 0 {
+		logger.Info("failed to parse rate limit QPS",
+			zap.String("envVar", envVar),
+			zap.String("value", qps),
+			zap.String("default", "disabled"))
+
+		return 0
+	}
+
+	return qpsFloat
+}
+
+func getRateLimitBurst(ctx context.Context, envVar string) int {
+	logger := logging.FromContext(ctx)
+
+	burst := os.Getenv(envVar)
+	if burst == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.Int("default", defaultRateLimitBurst))
+
+		return defaultRateLimitBurst
+	}
+
+	burstInt, err := strconv.Atoi(burst)
+	if err != nil || burstInt < 1 {
+		logger.Info("failed to parse rate limit burst",
+			zap.String("envVar", envVar),
+			zap.String("value", burst),
+			zap.Int("default", defaultRateLimitBurst))
+
+		return defaultRateLimitBurst
+	}
+
+	return burstInt
+}
+
+func getPriorityQueueingConfig(ctx context.Context) *PriorityQueueingConfig {
+	return &PriorityQueueingConfig{
+		InteractiveConcurrency: getPriorityConcurrency(ctx, envPriorityInteractiveSeats),
+		WorkloadConcurrency:    getPriorityConcurrency(ctx, envPriorityWorkloadSeats),
+		QueueLength:            getPriorityQueueLength(ctx),
+		QueueTimeout:           getPriorityQueueTimeout(ctx),
+	}
+}
+
+func getPriorityConcurrency(ctx context.Context, envVar string) int {
+	logger := logging.FromContext(ctx)
+
+	concurrency := os.Getenv(envVar)
+	if concurrency == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", "unlimited"))
+
+		return 0
+	}
+
+	concurrencyInt, err := strconv.Atoi(concurrency)
+	if err != nil || concurrencyInt < 0 {
+		logger.Info("failed to parse priority level concurrency",
+			zap.String("envVar", envVar),
+			zap.String("value", concurrency),
+			zap.String("default", "unlimited"))
+
+		return 0
+	}
+
+	return concurrencyInt
+}
+
+func getPriorityQueueLength(ctx context.Context) int {
+	logger := logging.FromContext(ctx)
+
+	queueLength := os.Getenv(envPriorityQueueLength)
+	if queueLength == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envPriorityQueueLength),
+			zap.Int("default", defaultPriorityQueueLength))
+
+		return defaultPriorityQueueLength
+	}
+
+	queueLengthInt, err := strconv.Atoi(queueLength)
+	if err != nil || queueLengthInt < 0 {
+		logger.Info("failed to parse priority queue length",
+			zap.String("envVar", envPriorityQueueLength),
+			zap.String("value", queueLength),
+			zap.Int("default", defaultPriorityQueueLength))
+
+		return defaultPriorityQueueLength
+	}
+
+	return queueLengthInt
+}
+
+func getPriorityQueueTimeout(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	timeout := os.Getenv(envPriorityQueueTimeout)
+	if timeout == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envPriorityQueueTimeout),
+			zap.String("default", defaultPriorityQueueTimeout.String()))
+
+		return defaultPriorityQueueTimeout
+	}
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		logger.Info("failed to parse priority queue timeout",
+			zap.String("envVar", envPriorityQueueTimeout),
+			zap.String("value", timeout),
+			zap.String("default", defaultPriorityQueueTimeout.String()))
+
+		return defaultPriorityQueueTimeout
+	}
+
+	return duration
+}
+
+func getACMEConfig(ctx context.Context) *ACMEConfig {
+	logger := logging.FromContext(ctx)
+
+	var domains []string
+
+	for domain := range strings.SplitSeq(os.Getenv(envACMEDomains), ",") {
+		domain = strings.TrimSpace(domain)
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	if len(domains) == 0 {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envACMEDomains),
+			zap.String("default", "disabled"))
+
+		return &ACMEConfig{}
+	}
+
+	cacheDir := os.Getenv(envACMECacheDir)
+	if cacheDir == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envACMECacheDir),
+			zap.String("default", defaultACMECacheDir))
+
+		cacheDir = defaultACMECacheDir
+	}
+
+	return &ACMEConfig{
+		Email:        os.Getenv(envACMEEmail),
+		Domains:      domains,
+		CacheDir:     cacheDir,
+		DirectoryURL: os.Getenv(envACMEDirectoryURL),
+	}
+}
+
+func getCORSConfig(ctx context.Context) *CORSConfig {
+	logger := logging.FromContext(ctx)
+
+	origins := splitCommaSeparated(os.Getenv(envCORSAllowedOrigins))
+	if len(origins) == 0 {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envCORSAllowedOrigins),
+			zap.String("default", "disabled"))
+
+		return &CORSConfig{}
+	}
+
+	return &CORSConfig{
+		AllowedOrigins:   origins,
+		AllowedMethods:   getCORSList(ctx, envCORSAllowedMethods, defaultCORSAllowedMethods),
+		AllowedHeaders:   getCORSList(ctx, envCORSAllowedHeaders, defaultCORSAllowedHeaders),
+		AllowCredentials: getCORSAllowCredentials(ctx),
+	}
+}
+
+func getCORSList(ctx context.Context, envVar string, defaultValue string) []string {
+	logger := logging.FromContext(ctx)
+
+	values := splitCommaSeparated(os.Getenv(envVar))
+	if len(values) == 0 {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", defaultValue))
+
+		return splitCommaSeparated(defaultValue)
+	}
+
+	return values
+}
+
+func getCORSAllowCredentials(ctx context.Context) bool {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envCORSAllowCredentials)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envCORSAllowCredentials),
+			zap.Bool("default", defaultCORSAllowCredentials))
+
+		return defaultCORSAllowCredentials
+	}
+
+	allowCredentials, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Info("failed to convert CORS allow credentials to boolean",
+			zap.String("envVar", envCORSAllowCredentials),
+			zap.String("value", value),
+			zap.Bool("default", defaultCORSAllowCredentials))
+
+		return defaultCORSAllowCredentials
+	}
+
+	return allowCredentials
+}
+
+// getSPIFFEConfig reads the Workload API address and the SPIFFE IDs accepted from
+// agents, which have to be spiffe:// URIs.
+func getSPIFFEConfig(ctx context.Context) (*SPIFFEConfig, error) {
+	endpointSocket := os.Getenv(envSPIFFEEndpointSocket)
+	if endpointSocket == "" {
+		logging.FromContext(ctx).Info(configurationNotSpecified,
+			zap.String("envVar", envSPIFFEEndpointSocket),
+			zap.String("default", "disabled"))
+
+		return &SPIFFEConfig{}, nil
+	}
+
+	allowedIDs := splitCommaSeparated(os.Getenv(envSPIFFEAllowedIDs))
+	for _, id := range allowedIDs {
+		if !strings.HasPrefix(id, "spiffe://") || strings.HasSuffix(id, "/") {
+			return nil, fmt.Errorf("%w: %s: %q", ErrInvalidSPIFFEID, envSPIFFEAllowedIDs, id)
+		}
+	}
+
+	return &SPIFFEConfig{EndpointSocket: endpointSocket, AllowedIDs: allowedIDs}, nil
+}
+
+// getTLSConfig reads the TLS settings, with versions and cipher suites named like the
+// flags of kube-apiserver, e.g. VersionTLS13 and TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func getTLSConfig(ctx context.Context) (*TLSConfig, error) {
+	logger := logging.FromContext(ctx)
+
+	minVersionName := os.Getenv(envTLSMinVersion)
+	if minVersionName == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envTLSMinVersion),
+			zap.String("default", defaultTLSMinVersion))
+
+		minVersionName = defaultTLSMinVersion
+	}
+
+	minVersion, err := cliflag.TLSVersion(minVersionName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTLSConfig, envTLSMinVersion, err)
+	}
+
+	tlsConfig := &TLSConfig{MinVersion: minVersion}
+
+	maxVersionName := os.Getenv(envTLSMaxVersion)
+	if maxVersionName != "" {
+		tlsConfig.MaxVersion, err = cliflag.TLSVersion(maxVersionName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTLSConfig, envTLSMaxVersion, err)
+		}
+
+		if tlsConfig.MaxVersion < tlsConfig.MinVersion {
+			return nil, fmt.Errorf("%w: %s is lower than %s", ErrInvalidTLSConfig, envTLSMaxVersion, envTLSMinVersion)
+		}
+	}
+
+	cipherSuiteNames := splitCommaSeparated(os.Getenv(envTLSCipherSuites))
+	if len(cipherSuiteNames) == 0 {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envTLSCipherSuites),
+			zap.String("default", "Go defaults"))
+
+		return tlsConfig, nil
+	}
+
+	tlsConfig.CipherSuites, err = cliflag.TLSCipherSuites(cipherSuiteNames)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTLSConfig, envTLSCipherSuites, err)
+	}
+
+	return tlsConfig, nil
+}
+
+// splitCommaSeparated splits a comma-separated list, dropping empty entries.
+func splitCommaSeparated(value string) []string {
+	var values []string
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			values = append(values, entry)
+		}
+	}
+
+	return values
+}
+
+func getBackupConfig(ctx context.Context) *BackupConfig {
+	logger := logging.FromContext(ctx)
+
+	bucket := os.Getenv(envBackupS3Bucket)
+	if bucket == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envBackupS3Bucket),
+			zap.String("default", "disabled"))
+
+		return &BackupConfig{}
+	}
+
+	region := os.Getenv(envBackupS3Region)
+	if region == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envBackupS3Region),
+			zap.String("default", defaultBackupS3Region))
+
+		region = defaultBackupS3Region
+	}
+
+	prefix, found := os.LookupEnv(envBackupS3Prefix)
+	if !found {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envBackupS3Prefix),
+			zap.String("default", defaultBackupS3Prefix))
+
+		prefix = defaultBackupS3Prefix
+	}
+
+	return &BackupConfig{
+		Endpoint:        os.Getenv(envBackupS3Endpoint),
+		Region:          region,
+		Bucket:          bucket,
+		Prefix:          prefix,
+		AccessKeyID:     os.Getenv(envBackupS3AccessKeyID),
+		SecretAccessKey: os.Getenv(envBackupS3SecretAccessKey),
+		Interval:        getBackupInterval(ctx),
+		RestoreSnapshot: os.Getenv(envBackupRestoreSnapshot),
+	}
+}
+
+func getBackupInterval(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	interval := os.Getenv(envBackupInterval)
+	if interval == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envBackupInterval),
+			zap.String("default", "disabled"))
+
+		return 0
+	}
+
+	duration, err := time.ParseDuration(interval)
+	if err != nil || duration < 0 {
+		logger.Info("failed to parse backup interval",
+			zap.String("envVar", envBackupInterval),
+			zap.String("value", interval),
+			zap.String("default", "disabled"))
+
+		return 0
+	}
+
+	return duration
+}
+
+func getClusterHealthInterval(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	interval := os.Getenv(envClusterHealthInterval)
+	if interval == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envClusterHealthInterval),
+			zap.String("default", defaultClusterHealthInterval.String()))
+
+		return defaultClusterHealthInterval
+	}
+
+	duration, err := time.ParseDuration(interval)
+	if err != nil || duration <= 0 {
+		logger.Info("failed to parse cluster health interval",
+			zap.String("envVar", envClusterHealthInterval),
+			zap.String("value", interval),
+			zap.String("default", defaultClusterHealthInterval.String()))
+
+		return defaultClusterHealthInterval
+	}
+
+	return duration
+}
+
+func getShutdownConfig(ctx context.Context) *ShutdownConfig {
+	return &ShutdownConfig{
+		DrainPeriod: getShutdownDuration(ctx, envShutdownDrainPeriod, defaultShutdownDrainPeriod),
+		Timeout:     getShutdownDuration(ctx, envShutdownTimeout, defaultShutdownTimeout),
+	}
+}
+
+func getShutdownDuration(ctx context.Context, envVar string, defaultValue time.Duration) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", defaultValue.String()))
+
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		logger.Info("failed to parse shutdown duration",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.String("default", defaultValue.String()))
+
+		return defaultValue
+	}
+
+	return duration
+}
+
+func getDatabaseConfig(ctx context.Context) *DatabaseConfig {
+	return &DatabaseConfig{
+		MaxOpenConnections:    getDatabaseInt(ctx, envDBMaxOpenConnections, 0),
+		MaxIdleConnections:    getDatabaseInt(ctx, envDBMaxIdleConnections, defaultDBMaxIdleConnections),
+		ConnectionMaxLifetime: getDatabaseDuration(ctx, envDBConnectionMaxLifetime, 0),
+		StatementTimeout:      getDatabaseDuration(ctx, envDBStatementTimeout, 0),
+		SlowQueryThreshold:    getDatabaseDuration(ctx, envDBSlowQueryThreshold, defaultDBSlowQueryThreshold),
+		CompactionInterval:    getDatabaseDuration(ctx, envDBCompactionInterval, defaultDBCompactionInterval),
+		CompactionRetention:   getDatabaseInt(ctx, envDBCompactionRetention, defaultDBCompactionRetention),
+	}
+}
+
+func getDatabaseInt(ctx context.Context, envVar string, defaultValue int) int {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.Int("default", defaultValue))
+
+		return defaultValue
+	}
+
+	valueInt, err := strconv.Atoi(value)
+	if err != nil || valueInt < 0 {
+		logger.Info("failed to parse database setting",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.Int("default", defaultValue))
+
+		return defaultValue
+	}
+
+	return valueInt
+}
+
+func getDatabaseDuration(ctx context.Context, envVar string, defaultValue time.Duration) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.String("default", defaultValue.String()))
+
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		logger.Info("failed to parse database setting",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.String("default", defaultValue.String()))
+
+		return defaultValue
+	}
+
+	return duration
+}
+
+func getWatchCacheConfig(ctx context.Context) *WatchCacheConfig {
+	return &WatchCacheConfig{
+		Resources: getWatchCacheResources(ctx),
+	}
+}
+
+// getWatchCacheResources parses a comma-separated list of resource=true|false pairs,
+// e.g. "configmaps=true,machines.cluster.x-k8s.io=false", on top of the defaults, so
+// enabling one resource does not disable the others. Invalid entries are skipped.
+func getWatchCacheResources(ctx context.Context) map[string]bool {
+	logger := logging.FromContext(ctx)
+
+	resources := parseWatchCacheResources(ctx, defaultWatchCache)
+
+	value := os.Getenv(envWatchCache)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envWatchCache),
+			zap.String("default", defaultWatchCache))
+
+		return resources
+	}
+
+	maps.Copy(resources, parseWatchCacheResources(ctx, value))
+
+	return resources
+}
+
+func parseWatchCacheResources(ctx context.Context, value string) map[string]bool {
+	logger := logging.FromContext(ctx)
+
+	resources := map[string]bool{}
+
+	for entry := range strings.SplitSeq(value, ",") {
+		resource, enabled, found := strings.Cut(strings.TrimSpace(entry), "=")
+
+		enabledBool, err := strconv.ParseBool(strings.TrimSpace(enabled))
+		if !found || strings.TrimSpace(resource) == "" || err != nil {
+			logger.Info("failed to parse watch cache entry, skipping",
+				zap.String("envVar", envWatchCache),
+				zap.String("value", entry))
+
+			continue
+		}
+
+		resources[strings.TrimSpace(resource)] = enabledBool
+	}
+
+	return resources
+}
+
+func getCompressionConfig(ctx context.Context) *CompressionConfig {
+	return &CompressionConfig{
+		Enabled: getCompressionBool(ctx, envCompressionEnabled, defaultCompressionEnabled),
+		MinSize: getCompressionMinSize(ctx),
+		Watches: getCompressionBool(ctx, envCompressionWatches, defaultCompressionWatches),
+	}
+}
+
+func getCompressionBool(ctx context.Context, envVar string, defaultValue bool) bool {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.Bool("default", defaultValue))
+
+		return defaultValue
+	}
+
+	valueBool, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Info("failed to convert response compression setting to boolean",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.Bool("default", defaultValue))
+
+		return defaultValue
+	}
+
+	return valueBool
+}
+
+func getCompressionMinSize(ctx context.Context) int {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envCompressionMinSize)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envCompressionMinSize),
+			zap.Int("default", defaultCompressionMinSize))
+
+		return defaultCompressionMinSize
+	}
+
+	minSize, err := strconv.Atoi(value)
+	if err != nil || minSize < 0 {
+		logger.Info("failed to convert response compression minimum size to non-negative integer",
+			zap.String("envVar", envCompressionMinSize),
+			zap.String("value", value),
+			zap.Int("default", defaultCompressionMinSize))
+
+		return defaultCompressionMinSize
+	}
+
+	return minSize
+}
+
+// getEncryptionConfig reads the encryption at rest settings. Unlike most settings,
+// an invalid value is an error: silently falling back would store Secrets in the
+// clear while the operator believes they are encrypted.
+func getEncryptionConfig(ctx context.Context) (*EncryptionConfig, error) {
+	logger := logging.FromContext(ctx)
+
+	provider := EncryptionProvider(os.Getenv(envEncryptionProvider))
+
+	encryptionConfig := &EncryptionConfig{Provider: provider}
+
+	switch provider {
+	case EncryptionProviderNone:
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envEncryptionProvider),
+			zap.String("default", "disabled"))
+
+		return encryptionConfig, nil
+	case EncryptionProviderStatic:
+		keys, err := parseStaticEncryptionKeys(os.Getenv(envEncryptionStaticKeys))
+		if err != nil {
+			return nil, err
+		}
+
+		encryptionConfig.StaticKeys = keys
+	case EncryptionProviderVault:
+		encryptionConfig.VaultAddress = os.Getenv(envEncryptionVaultAddress)
+		encryptionConfig.VaultToken = os.Getenv(envEncryptionVaultToken)
+		encryptionConfig.VaultKey = os.Getenv(envEncryptionVaultKey)
+		encryptionConfig.VaultMount = os.Getenv(envEncryptionVaultMount)
+
+		if encryptionConfig.VaultMount == "" {
+			logger.Info(configurationNotSpecified,
+				zap.String("envVar", envEncryptionVaultMount),
+				zap.String("default", defaultEncryptionVaultMount))
+
+			encryptionConfig.VaultMount = defaultEncryptionVaultMount
+		}
+
+		if encryptionConfig.VaultAddress == "" || encryptionConfig.VaultToken == "" || encryptionConfig.VaultKey == "" {
+			return nil, fmt.Errorf("%w: %s, %s and %s are required", ErrInvalidEncryptionConfig,
+				envEncryptionVaultAddress, envEncryptionVaultToken, envEncryptionVaultKey)
+		}
+	case EncryptionProviderAWS:
+		encryptionConfig.AWSKeyID = os.Getenv(envEncryptionAWSKeyID)
+		encryptionConfig.AWSRegion = os.Getenv(envEncryptionAWSRegion)
+
+		if encryptionConfig.AWSKeyID == "" || encryptionConfig.AWSRegion == "" {
+			return nil, fmt.Errorf("%w: %s and %s are required", ErrInvalidEncryptionConfig,
+				envEncryptionAWSKeyID, envEncryptionAWSRegion)
+		}
+	case EncryptionProviderGCP:
+		encryptionConfig.GCPKeyName = os.Getenv(envEncryptionGCPKeyName)
+
+		if encryptionConfig.GCPKeyName == "" {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidEncryptionConfig, envEncryptionGCPKeyName)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrInvalidEncryptionConfig, provider)
+	}
+
+	return encryptionConfig, nil
+}
+
+// parseStaticEncryptionKeys parses a comma-separated list of name:base64key pairs,
+// e.g. "key2:<base64>,key1:<base64>". Each key must decode to 32 bytes.
+func parseStaticEncryptionKeys(value string) ([]StaticEncryptionKey, error) {
+	keys := []StaticEncryptionKey{}
+	names := map[string]bool{}
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, encoded, found := strings.Cut(entry, ":")
+		if !found || name == "" || names[name] {
+			return nil, fmt.Errorf("%w: %s entries must be unique name:base64key pairs",
+				ErrInvalidEncryptionConfig, envEncryptionStaticKeys)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != staticEncryptionKeySize {
+			return nil, fmt.Errorf("%w: key %s must be %d base64 encoded bytes",
+				ErrInvalidEncryptionConfig, name, staticEncryptionKeySize)
+		}
+
+		names[name] = true
+		keys = append(keys, StaticEncryptionKey{Name: name, Key: key})
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s is required", ErrInvalidEncryptionConfig, envEncryptionStaticKeys)
+	}
+
+	return keys, nil
+}
+
+// getSecretsBackendConfig reads the external secret manager settings. Like the
+// encryption settings, an invalid value is an error rather than a fallback to kine.
+func getSecretsBackendConfig(ctx context.Context) (*SecretsBackendConfig, error) {
+	logger := logging.FromContext(ctx)
+
+	provider := SecretsBackendProvider(os.Getenv(envSecretsBackend))
+
+	backendConfig := &SecretsBackendConfig{Provider: provider}
+
+	switch provider {
+	case SecretsBackendNone:
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envSecretsBackend),
+			zap.String("default", "disabled"))
+
+		return backendConfig, nil
+	case SecretsBackendVault:
+		backendConfig.VaultAddress = os.Getenv(envSecretsVaultAddress)
+		backendConfig.VaultToken = os.Getenv(envSecretsVaultToken)
+		backendConfig.VaultMount = os.Getenv(envSecretsVaultMount)
+
+		if backendConfig.VaultMount == "" {
+			logger.Info(configurationNotSpecified,
+				zap.String("envVar", envSecretsVaultMount),
+				zap.String("default", defaultSecretsVaultMount))
+
+			backendConfig.VaultMount = defaultSecretsVaultMount
+		}
+
+		if backendConfig.VaultAddress == "" || backendConfig.VaultToken == "" {
+			return nil, fmt.Errorf("%w: %s and %s are required", ErrInvalidSecretsBackendConfig,
+				envSecretsVaultAddress, envSecretsVaultToken)
+		}
+	case SecretsBackendAWS:
+		backendConfig.AWSRegion = os.Getenv(envSecretsAWSRegion)
+
+		if backendConfig.AWSRegion == "" {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidSecretsBackendConfig, envSecretsAWSRegion)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown backend %q", ErrInvalidSecretsBackendConfig, provider)
+	}
+
+	backendConfig.Types = splitCommaSeparated(os.Getenv(envSecretsBackendTypes))
+	backendConfig.Namespaces = splitCommaSeparated(os.Getenv(envSecretsBackendNamespaces))
+
+	backendConfig.Prefix = strings.Trim(os.Getenv(envSecretsBackendPrefix), "/")
+	if backendConfig.Prefix == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envSecretsBackendPrefix),
+			zap.String("default", defaultSecretsBackendPrefix))
+
+		backendConfig.Prefix = defaultSecretsBackendPrefix
+	}
+
+	backendConfig.CacheTTL = defaultSecretsBackendCacheTTL
+
+	cacheTTL := os.Getenv(envSecretsBackendCacheTTL)
+	if cacheTTL == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envSecretsBackendCacheTTL),
+			zap.String("default", defaultSecretsBackendCacheTTL.String()))
+
+		return backendConfig, nil
+	}
+
+	duration, err := time.ParseDuration(cacheTTL)
+	if err != nil || duration < 0 {
+		return nil, fmt.Errorf("%w: %s: %q", ErrInvalidSecretsBackendConfig, envSecretsBackendCacheTTL, cacheTTL)
+	}
+
+	backendConfig.CacheTTL = duration
+
+	return backendConfig, nil
+}
+
+func getSecretReadAuditConfig(ctx context.Context) *SecretReadAuditConfig {
+	excludeUsers, found := os.LookupEnv(envSecretReadAuditExcludeUsers)
+	if !found {
+		logging.FromContext(ctx).Info(configurationNotSpecified,
+			zap.String("envVar", envSecretReadAuditExcludeUsers),
+			zap.String("default", defaultSecretReadAuditExcludeUsers))
+
+		excludeUsers = defaultSecretReadAuditExcludeUsers
+	}
+
+	return &SecretReadAuditConfig{
+		Enabled:       getSecretReadAuditBool(ctx, envSecretReadAuditEnabled),
+		Events:        getSecretReadAuditBool(ctx, envSecretReadAuditEvents),
+		ExcludeUsers:  splitCommaSeparated(excludeUsers),
+		ExcludeGroups: splitCommaSeparated(os.Getenv(envSecretReadAuditExcludeGroups)),
+	}
+}
+
+func getSecretReadAuditBool(ctx context.Context, envVar string) bool {
+	logger := logging.FromContext(ctx)
+
+	value := os.Getenv(envVar)
+	if value == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envVar),
+			zap.Bool("default", false))
+
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Info("failed to convert secret read audit setting to boolean",
+			zap.String("envVar", envVar),
+			zap.String("value", value),
+			zap.Bool("default", false))
+
+		return false
+	}
+
+	return enabled
+}
+
+func getEventTTL(ctx context.Context) time.Duration {
+	logger := logging.FromContext(ctx)
+
+	ttl := os.Getenv(envEventTTL)
+	if ttl == "" {
+		logger.Info(configurationNotSpecified,
+			zap.String("envVar", envEventTTL),
+			zap.String("default", defaultEventTTL.String()))
+
+		return defaultEventTTL
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil || duration < time.Second {
+		logger.Info("failed to parse event TTL",
+			zap.String("envVar", envEventTTL),
+			zap.String("value", ttl),
+			zap.String("default", defaultEventTTL.String()))
+
+		return defaultEventTTL
+	}
+
+	return duration
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// DynamicConfigMapName is the ConfigMap in the Kommodity namespace whose data
+	// overrides the tunables at runtime. Its keys are the names of the environment
+	// variables they override.
+	DynamicConfigMapName = "kommodity-config"
+
+	// envLogLevel is read by the logging package, it is listed here to be reloadable.
+	envLogLevel = "LOG_LEVEL"
+)
+
+// Tunables are the settings that can be changed without restarting Kommodity.
+type Tunables struct {
+	LogLevel              zapcore.Level
+	RateLimit             RateLimitConfig
+	ClusterHealthInterval time.Duration
+}
+
+// DynamicConfig holds the current tunables. They start out as read from the
+// environment and follow the data of the dynamic ConfigMap from then on.
+type DynamicConfig struct {
+	lock        sync.RWMutex
+	defaults    Tunables
+	current     Tunables
+	subscribers []func(Tunables)
+}
+
+// NewDynamicConfig returns a DynamicConfig that starts out with, and falls back to,
+// the given tunables.
+func NewDynamicConfig(defaults Tunables) *DynamicConfig {
+	return &DynamicConfig{
+		defaults: defaults,
+		current:  defaults,
+	}
+}
+
+// Current returns the tunables in effect.
+func (d *DynamicConfig) Current() Tunables {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return d.current
+}
+
+// Subscribe registers a function that is called with the new tunables whenever they
+// change. It is called with the tunables in effect right away.
+func (d *DynamicConfig) Subscribe(subscriber func(Tunables)) {
+	d.lock.Lock()
+	d.subscribers = append(d.subscribers, subscriber)
+	current := d.current
+	d.lock.Unlock()
+
+	subscriber(current)
+}
+
+// Apply replaces the tunables with the environment overridden by the given ConfigMap
+// data; keys that are missing fall back to the environment. Data with an invalid
+// value is rejected as a whole and the tunables in effect are kept. Keys of settings
+// that are only read at startup are reported in the log.
+func (d *DynamicConfig) Apply(ctx context.Context, data map[string]string) error {
+	d.lock.Lock()
+	tunables := d.defaults
+	d.lock.Unlock()
+
+	var errs []error
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		err := tunables.set(ctx, key, strings.TrimSpace(data[key]))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidDynamicConfig, errors.Join(errs...))
+	}
+
+	d.lock.Lock()
+	changed := tunables != d.current
+	d.current = tunables
+	subscribers := slices.Clone(d.subscribers)
+	d.lock.Unlock()
+
+	if !changed {
+		return nil
+	}
+
+	logging.FromContext(ctx).Info("Applying dynamic configuration",
+		zap.String("logLevel", tunables.LogLevel.String()),
+		zap.Any("rateLimit", tunables.RateLimit),
+		zap.Duration("clusterHealthInterval", tunables.ClusterHealthInterval))
+
+	for _, subscriber := range subscribers {
+		subscriber(tunables)
+	}
+
+	return nil
+}
+
+// set overrides the tunable of the environment variable key with value.
+//
+//nolint:cyclop // One case per tunable, no real complexity here.
+func (t *Tunables) set(ctx context.Context, key, value string) error {
+	var err error
+
+	switch key {
+	case envLogLevel:
+		t.LogLevel, err = zapcore.ParseLevel(value)
+	case envRateLimitUserQPS:
+		t.RateLimit.UserQPS, err = parseRateLimitQPS(value)
+	case envRateLimitUserBurst:
+		t.RateLimit.UserBurst, err = parseRateLimitBurst(value)
+	case envRateLimitIPQPS:
+		t.RateLimit.IPQPS, err = parseRateLimitQPS(value)
+	case envRateLimitIPBurst:
+		t.RateLimit.IPBurst, err = parseRateLimitBurst(value)
+	case envClusterHealthInterval:
+		t.ClusterHealthInterval, err = time.ParseDuration(value)
+		if err == nil && t.ClusterHealthInterval <= 0 {
+			err = ErrValueNotPositive
+		}
+	default:
+		logging.FromContext(ctx).Warn(
+			"Setting of the dynamic configuration is only read at startup, restart Kommodity to apply it",
+			zap.String("key", key))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s=%q: %w", key, value, err)
+	}
+
+	return nil
+}
+
+func parseRateLimitQPS(value string) (float64, error) {
+	qps, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse QPS: %w", err)
+	}
+
+	if qps < 0 {
+		return 0, ErrValueNegative
+	}
+
+	return qps, nil
+}
+
+func parseRateLimitBurst(value string) (int, error) {
+	burst, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse burst: %w", err)
+	}
+
+	if burst < 1 {
+		return 0, ErrValueNotPositive
+	}
+
+	return burst, nil
+}
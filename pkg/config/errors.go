@@ -7,4 +7,42 @@ var (
 	ErrAdminGroupNotSet = errors.New("admin group is not set, no admin group configured")
 	// ErrKommodityDBEnvVarNotSet indicates that the KOMMODITY_DB_URI environment variable is not set.
 	ErrKommodityDBEnvVarNotSet = errors.New("KOMMODITY_DB_URI environment variable is not set")
+	// ErrHTTPAuthWithoutOIDC indicates that HTTP authentication is enabled without an OIDC provider,
+	// client CA or SPIFFE.
+	ErrHTTPAuthWithoutOIDC = errors.New(
+		"KOMMODITY_HTTP_AUTH_ENABLED requires the OIDC configuration, a client CA or SPIFFE to be set")
+	// ErrClientCAWithoutTLS indicates that client certificates are configured on a listener
+	// without TLS, which cannot receive them.
+	ErrClientCAWithoutTLS = errors.New(
+		"client certificates require ACME or SPIFFE to serve KOMMODITY_PORT over TLS")
+	// ErrSPIFFEWithACME indicates that both SPIFFE and ACME provide the certificate of the listener.
+	ErrSPIFFEWithACME = errors.New("SPIFFE and ACME cannot both serve KOMMODITY_PORT")
+	// ErrInvalidSPIFFEID indicates that an allowed SPIFFE ID is not a spiffe:// URI.
+	ErrInvalidSPIFFEID = errors.New("invalid SPIFFE ID")
+	// ErrCORSCredentialsWithAnyOrigin indicates that credentials are allowed for any origin,
+	// which would let every website act on behalf of the signed in user.
+	ErrCORSCredentialsWithAnyOrigin = errors.New(
+		"KOMMODITY_CORS_ALLOW_CREDENTIALS requires KOMMODITY_CORS_ALLOWED_ORIGINS to list the origins")
+	// ErrInvalidTLSConfig indicates that the TLS versions or cipher suites are invalid.
+	ErrInvalidTLSConfig = errors.New("invalid TLS configuration")
+	// ErrInvalidEncryptionConfig indicates that the encryption at rest settings are invalid.
+	ErrInvalidEncryptionConfig = errors.New("invalid encryption configuration")
+	// ErrInvalidSecretsBackendConfig indicates that the external secret manager settings are invalid.
+	ErrInvalidSecretsBackendConfig = errors.New("invalid secrets backend configuration")
+	// ErrInvalidAttestationConfig indicates that the attestation settings are invalid.
+	ErrInvalidAttestationConfig = errors.New("invalid attestation configuration")
+	// ErrInvalidDynamicConfig indicates that the dynamic configuration ConfigMap holds an invalid value.
+	ErrInvalidDynamicConfig = errors.New("invalid dynamic configuration")
+	// ErrInvalidIPAddress indicates that a setting is not a valid IP address.
+	ErrInvalidIPAddress = errors.New("invalid IP address")
+	// ErrBindAddressesWithoutLoopback indicates that the server would not listen on loopback,
+	// which Kommodity calls its own endpoints over.
+	ErrBindAddressesWithoutLoopback = errors.New("bind addresses must include a loopback address or all interfaces")
+	// ErrAPIServerBindAddressNotLocal indicates that the API server would not be reachable
+	// over loopback, which the combined server proxies requests to it over.
+	ErrAPIServerBindAddressNotLocal = errors.New("API server bind address must be a loopback address or all interfaces")
+	// ErrValueNegative indicates that a setting is negative where it must not be.
+	ErrValueNegative = errors.New("must not be negative")
+	// ErrValueNotPositive indicates that a setting is zero or negative where it must be positive.
+	ErrValueNotPositive = errors.New("must be positive")
 )
@@ -0,0 +1,94 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// consoleSubresource is the Machine subresource users need the get verb on.
+	consoleSubresource = "console"
+	// machinesResource is the resource of the Machines of Cluster API.
+	machinesResource = "machines"
+)
+
+// Authorizer decides whether the owner of the bearer token may open the console of
+// the Machine.
+type Authorizer func(ctx context.Context, token, namespace, name string) error
+
+// newAccessReviewAuthorizer asks the API server whether the owner of the token may get
+// the console subresource of the Machine. The token is authenticated by the API server,
+// so every identity it accepts works, and RBAC decides the outcome.
+func newAccessReviewAuthorizer(cfg *config.KommodityConfig) Authorizer {
+	return func(ctx context.Context, token, namespace, name string) error {
+		tokenConfig := restclient.AnonymousClientConfig(cfg.ClientConfig.LoopbackClientConfig)
+		tokenConfig.BearerToken = token
+
+		kubeClient, err := kubernetes.NewForConfig(tokenConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create client for access review: %w", err)
+		}
+
+		review, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx,
+			&authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace:   namespace,
+						Verb:        "get",
+						Group:       clusterv1.GroupVersion.Group,
+						Resource:    machinesResource,
+						Subresource: consoleSubresource,
+						Name:        name,
+					},
+				},
+			}, metav1.CreateOptions{})
+		if apierrors.IsUnauthorized(err) {
+			return fmt.Errorf("%w: %w", ErrMissingToken, err)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to review access to machine %s/%s: %w", namespace, name, err)
+		}
+
+		if !review.Status.Allowed {
+			return fmt.Errorf("%w: %s/%s", ErrForbidden, namespace, name)
+		}
+
+		return nil
+	}
+}
+
+// bearerToken returns the token of an "authorization: Bearer <token>" value.
+func bearerToken(values []string) (string, error) {
+	for _, value := range values {
+		scheme, token, found := strings.Cut(value, " ")
+		if found && strings.EqualFold(scheme, "bearer") && strings.TrimSpace(token) != "" {
+			return strings.TrimSpace(token), nil
+		}
+	}
+
+	return "", ErrMissingToken
+}
+
+// machineRef splits a "<namespace>/<name>" reference to a Machine.
+func machineRef(values []string) (string, string, error) {
+	if len(values) == 0 {
+		return "", "", ErrMissingMachine
+	}
+
+	namespace, name, found := strings.Cut(values[0], "/")
+	if !found || namespace == "" || name == "" {
+		return "", "", fmt.Errorf("%w: %q is not <namespace>/<name>", ErrMissingMachine, values[0])
+	}
+
+	return namespace, name, nil
+}
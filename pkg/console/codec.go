@@ -0,0 +1,59 @@
+package console
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/mem"
+)
+
+// frame is a message that is passed through without being decoded.
+type frame struct {
+	payload []byte
+}
+
+// passthroughCodec leaves frames as they are and encodes every other message as
+// protobuf, so that the services registered on the server keep working.
+type passthroughCodec struct {
+	proto encoding.CodecV2
+}
+
+func newPassthroughCodec() *passthroughCodec {
+	return &passthroughCodec{proto: encoding.GetCodecV2(proto.Name)}
+}
+
+// Marshal returns the payload of a frame or the protobuf encoding of a message.
+func (c *passthroughCodec) Marshal(v any) (mem.BufferSlice, error) {
+	if message, ok := v.(*frame); ok {
+		return mem.BufferSlice{mem.SliceBuffer(message.payload)}, nil
+	}
+
+	data, err := c.proto.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return data, nil
+}
+
+// Unmarshal stores the payload in a frame or decodes a protobuf message.
+func (c *passthroughCodec) Unmarshal(data mem.BufferSlice, v any) error {
+	if message, ok := v.(*frame); ok {
+		message.payload = data.Materialize()
+
+		return nil
+	}
+
+	err := c.proto.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	return nil
+}
+
+// Name returns the name of the protobuf codec, which the clients negotiate.
+func (c *passthroughCodec) Name() string {
+	return proto.Name
+}
@@ -0,0 +1,14 @@
+package console
+
+import "errors"
+
+var (
+	// ErrMissingToken is returned when a request carries no bearer token.
+	ErrMissingToken = errors.New("missing bearer token")
+	// ErrMissingMachine is returned when a request does not name the Machine to reach.
+	ErrMissingMachine = errors.New("missing machine")
+	// ErrMethodNotAllowed is returned for Talos API methods the console does not proxy.
+	ErrMethodNotAllowed = errors.New("method is not available on the machine console")
+	// ErrForbidden is returned when the user may not open the console of the Machine.
+	ErrForbidden = errors.New("console access to the machine is forbidden")
+)
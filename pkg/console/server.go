@@ -0,0 +1,271 @@
+// Package console proxies read-only Talos API sessions, like logs, dmesg and the
+// resource usage shown by the dashboard, to the nodes of workload clusters. Nodes on
+// private networks are reached through the Talos proxy, so operators can debug them
+// without direct network access.
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachineMetadataKey is the gRPC metadata key naming the Machine a request is for, as
+// "<namespace>/<name>".
+const MachineMetadataKey = "kommodity-machine"
+
+// allowedMethods are the Talos API methods the console proxies. They only read state;
+// file access, resources and anything that changes the node are left out, because the
+// proxy talks to the node with the admin talosconfig of the cluster.
+//
+//nolint:gochecknoglobals // Constant set of proxied methods.
+var allowedMethods = map[string]bool{
+	machine.MachineService_Logs_FullMethodName:               true,
+	machine.MachineService_LogsContainers_FullMethodName:     true,
+	machine.MachineService_Dmesg_FullMethodName:              true,
+	machine.MachineService_Events_FullMethodName:             true,
+	machine.MachineService_Version_FullMethodName:            true,
+	machine.MachineService_Hostname_FullMethodName:           true,
+	machine.MachineService_ServiceList_FullMethodName:        true,
+	machine.MachineService_Containers_FullMethodName:         true,
+	machine.MachineService_Stats_FullMethodName:              true,
+	machine.MachineService_Processes_FullMethodName:          true,
+	machine.MachineService_Memory_FullMethodName:             true,
+	machine.MachineService_SystemStat_FullMethodName:         true,
+	machine.MachineService_CPUInfo_FullMethodName:            true,
+	machine.MachineService_CPUFreqStats_FullMethodName:       true,
+	machine.MachineService_LoadAvg_FullMethodName:            true,
+	machine.MachineService_DiskStats_FullMethodName:          true,
+	machine.MachineService_NetworkDeviceStats_FullMethodName: true,
+	machine.MachineService_Mounts_FullMethodName:             true,
+}
+
+// Dialer connects to the Talos API of the node behind the Machine. The returned
+// function closes the connection.
+type Dialer func(ctx context.Context, namespace, name string) (grpc.ClientConnInterface, func() error, error)
+
+// Proxy forwards console sessions to the Talos API of Machines.
+type Proxy struct {
+	authorize Authorizer
+	dial      Dialer
+	codec     *passthroughCodec
+}
+
+// NewProxy creates a console proxy.
+func NewProxy(authorize Authorizer, dial Dialer) *Proxy {
+	return &Proxy{
+		authorize: authorize,
+		dial:      dial,
+		codec:     newPassthroughCodec(),
+	}
+}
+
+// NewGRPCServerOptions returns the options that serve the console on the gRPC server.
+// The console is only served when the API server authenticates requests, as it relies
+// on the API server to decide who may open it.
+func NewGRPCServerOptions(ctx context.Context, cfg *config.KommodityConfig) []grpc.ServerOption {
+	if !cfg.AuthConfig.Apply {
+		logging.FromContext(ctx).Warn("Not serving the machine console, it requires API server authentication")
+
+		return nil
+	}
+
+	return NewProxy(newAccessReviewAuthorizer(cfg), newTalosDialer(cfg)).ServerOptions()
+}
+
+// ServerOptions returns the gRPC server options that route the proxied methods to the
+// console. Methods of services registered on the server are not affected.
+func (p *Proxy) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ForceServerCodecV2(p.codec),
+		grpc.UnknownServiceHandler(p.handle),
+	}
+}
+
+// handle authorizes a console session and forwards it to the node.
+func (p *Proxy) handle(_ any, serverStream grpc.ServerStream) error {
+	ctx := serverStream.Context()
+
+	method, _ := grpc.MethodFromServerStream(serverStream)
+	if !allowedMethods[method] {
+		return status.Errorf(codes.Unimplemented, "%v: %s", ErrMethodNotAllowed, method)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	token, err := bearerToken(md.Get("authorization"))
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	namespace, name, err := machineRef(md.Get(MachineMetadataKey))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = p.authorize(ctx, token, namespace, name)
+
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	logger := logging.FromContext(ctx).With(
+		zap.String("machine", namespace+"/"+name),
+		zap.String("method", method))
+	logger.Info("Opening machine console session")
+
+	conn, closeConn, err := p.dial(ctx, namespace, name)
+	if err != nil {
+		logger.Error("Failed to reach machine", zap.Error(err))
+
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	defer func() {
+		_ = closeConn()
+	}()
+
+	return p.forward(ctx, serverStream, conn, method)
+}
+
+// forward copies the messages of the session in both directions until the node ends
+// it, and passes the status of the node on to the caller.
+func (p *Proxy) forward(ctx context.Context, serverStream grpc.ServerStream,
+	conn grpc.ClientConnInterface, method string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clientStream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true, ClientStreams: true},
+		method, grpc.ForceCodecV2(p.codec))
+	if err != nil {
+		return err //nolint:wrapcheck // The status of the node is passed on as is.
+	}
+
+	requests := make(chan error, 1)
+	responses := make(chan error, 1)
+
+	go func() { requests <- forwardRequests(serverStream, clientStream) }()
+	go func() { responses <- forwardResponses(clientStream, serverStream) }()
+
+	for {
+		select {
+		case err := <-requests:
+			if !errors.Is(err, io.EOF) {
+				return status.Errorf(codes.Canceled, "failed to forward request: %v", err)
+			}
+
+			// The caller is done sending, wait for the rest of the responses.
+			requests = nil
+		case err := <-responses:
+			serverStream.SetTrailer(clientStream.Trailer())
+
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return nil
+		}
+	}
+}
+
+func forwardRequests(src grpc.ServerStream, dst grpc.ClientStream) error {
+	for {
+		message := &frame{}
+
+		err := src.RecvMsg(message)
+		if errors.Is(err, io.EOF) {
+			_ = dst.CloseSend()
+
+			return io.EOF
+		}
+
+		if err != nil {
+			return err //nolint:wrapcheck // Reported by forward.
+		}
+
+		err = dst.SendMsg(message)
+		if err != nil {
+			return err //nolint:wrapcheck // Reported by forward.
+		}
+	}
+}
+
+func forwardResponses(src grpc.ClientStream, dst grpc.ServerStream) error {
+	header, err := src.Header()
+	if err != nil {
+		return err //nolint:wrapcheck // Reported by forward.
+	}
+
+	err = dst.SendHeader(header)
+	if err != nil {
+		return err //nolint:wrapcheck // Reported by forward.
+	}
+
+	for {
+		message := &frame{}
+
+		err := src.RecvMsg(message)
+		if err != nil {
+			return err //nolint:wrapcheck // The status of the node is passed on as is.
+		}
+
+		err = dst.SendMsg(message)
+		if err != nil {
+			return err //nolint:wrapcheck // Reported by forward.
+		}
+	}
+}
+
+// newTalosDialer reaches the node of a Machine with the talosconfig of its Cluster,
+// through the Talos proxy for private networks.
+func newTalosDialer(cfg *config.KommodityConfig) Dialer {
+	return func(ctx context.Context, namespace, name string) (grpc.ClientConnInterface, func() error, error) {
+		scheme := runtime.NewScheme()
+
+		for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, clusterv1.AddToScheme} {
+			err := add(scheme)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to build scheme: %w", err)
+			}
+		}
+
+		kubeClient, err := client.New(cfg.ClientConfig.LoopbackClientConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+		target := &clusterv1.Machine{}
+
+		err = kubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get Machine %s/%s: %w", namespace, name, err)
+		}
+
+		talosClient, err := reconciler.TalosClientForMachine(ctx, kubeClient, target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create Talos client for Machine %s/%s: %w", namespace, name, err)
+		}
+
+		return talosClient.Conn(), talosClient.Close, nil
+	}
+}
@@ -0,0 +1,210 @@
+package console_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/console"
+	"github.com/siderolabs/talos/pkg/machinery/api/common"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testToken   = "operator-token"
+	testMachine = "default/worker-0"
+)
+
+// fakeNode serves the parts of the Talos API the tests call.
+type fakeNode struct {
+	machine.UnimplementedMachineServiceServer
+}
+
+func (fakeNode) Stats(_ context.Context, request *machine.StatsRequest) (*machine.StatsResponse, error) {
+	stats := []*machine.Stat{{Namespace: request.GetNamespace()}}
+
+	return &machine.StatsResponse{Messages: []*machine.Stats{{Stats: stats}}}, nil
+}
+
+func (fakeNode) Logs(_ *machine.LogsRequest, stream grpc.ServerStreamingServer[common.Data]) error {
+	for _, line := range []string{"first", "second"} {
+		err := stream.Send(&common.Data{Bytes: []byte(line)})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serve starts a gRPC server on an in-memory listener and returns a connection to it.
+func serve(t *testing.T, server *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	go func() { _ = server.Serve(listener) }()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// newConsole starts a node and a console in front of it, and returns a Talos API
+// client for the console and the number of times the node was dialed.
+func newConsole(t *testing.T) (machine.MachineServiceClient, *int) {
+	t.Helper()
+
+	node := grpc.NewServer()
+	machine.RegisterMachineServiceServer(node, fakeNode{})
+	nodeConn := serve(t, node)
+
+	dials := 0
+
+	proxy := console.NewProxy(
+		func(_ context.Context, token, namespace, name string) error {
+			if token != testToken || namespace+"/"+name != testMachine {
+				return console.ErrForbidden
+			}
+
+			return nil
+		},
+		func(context.Context, string, string) (grpc.ClientConnInterface, func() error, error) {
+			dials++
+
+			return nodeConn, func() error { return nil }, nil
+		})
+
+	return machine.NewMachineServiceClient(serve(t, grpc.NewServer(proxy.ServerOptions()...))), &dials
+}
+
+func withSession(ctx context.Context, token, machineRef string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		"authorization", "Bearer "+token,
+		console.MachineMetadataKey, machineRef)
+}
+
+func TestConsoleProxiesUnaryCalls(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newConsole(t)
+
+	response, err := client.Stats(withSession(t.Context(), testToken, testMachine),
+		&machine.StatsRequest{Namespace: "k8s.io"})
+	if err != nil {
+		t.Fatalf("expected the stats of the node: %v", err)
+	}
+
+	if namespace := response.GetMessages()[0].GetStats()[0].GetNamespace(); namespace != "k8s.io" {
+		t.Fatalf("expected the request to reach the node, got namespace %q", namespace)
+	}
+}
+
+func TestConsoleProxiesStreams(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newConsole(t)
+
+	stream, err := client.Logs(withSession(t.Context(), testToken, testMachine), &machine.LogsRequest{Id: "kubelet"})
+	if err != nil {
+		t.Fatalf("failed to open logs: %v", err)
+	}
+
+	var lines []string
+
+	for {
+		data, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("failed to read logs: %v", err)
+		}
+
+		lines = append(lines, string(data.GetBytes()))
+	}
+
+	if len(lines) != 2 || lines[0] != "first" || lines[1] != "second" {
+		t.Fatalf("expected both log lines, got %v", lines)
+	}
+}
+
+func TestConsoleRejectsSessions(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		ctx  func(ctx context.Context) context.Context
+		call func(ctx context.Context, client machine.MachineServiceClient) error
+		code codes.Code
+	}{
+		"without token": {
+			ctx: func(ctx context.Context) context.Context {
+				return metadata.AppendToOutgoingContext(ctx, console.MachineMetadataKey, testMachine)
+			},
+			code: codes.Unauthenticated,
+		},
+		"without machine": {
+			ctx: func(ctx context.Context) context.Context {
+				return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testToken)
+			},
+			code: codes.InvalidArgument,
+		},
+		"forbidden machine": {
+			ctx:  func(ctx context.Context) context.Context { return withSession(ctx, testToken, "default/other") },
+			code: codes.PermissionDenied,
+		},
+		"method changing the node": {
+			ctx: func(ctx context.Context) context.Context { return withSession(ctx, testToken, testMachine) },
+			call: func(ctx context.Context, client machine.MachineServiceClient) error {
+				_, err := client.Reboot(ctx, &machine.RebootRequest{})
+
+				return err
+			},
+			code: codes.Unimplemented,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, dials := newConsole(t)
+
+			call := test.call
+			if call == nil {
+				call = func(ctx context.Context, client machine.MachineServiceClient) error {
+					_, err := client.Stats(ctx, &machine.StatsRequest{})
+
+					return err
+				}
+			}
+
+			err := call(test.ctx(t.Context()), client)
+			if status.Code(err) != test.code {
+				t.Fatalf("expected %s, got %v", test.code, err)
+			}
+
+			if *dials != 0 {
+				t.Fatalf("expected the node not to be dialed, got %d dials", *dials)
+			}
+		})
+	}
+}
@@ -13,23 +13,22 @@ import (
 	"github.com/kommodity-io/kommodity/pkg/controller/webhook"
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/talosproxy"
+	"golang.org/x/time/rate"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/util/workqueue"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
-	crwebconv "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
-)
-
-const (
-	// MaxConcurrentReconciles is the maximum number of concurrent reconciles for controllers.
-	MaxConcurrentReconciles = 10
 )
 
 // AggregatedControllerManagerDeps bundles the dependencies for NewAggregatedControllerManager.
@@ -55,22 +54,43 @@ func NewAggregatedControllerManager(ctx context.Context,
 	scheme := deps.Scheme
 	signingKeyDeps := deps.SigningKeyDeps
 
+	ctx = logging.WithModule(ctx, logging.ModuleControllers)
+
 	logger := zapr.NewLogger(logging.FromContext(ctx))
 	ctrl.SetLogger(logger)
 
 	logger.Info("Creating controller manager")
 
+	controllerConfig := kommodityConfig.ControllerConfig
+
+	err := validateConfigMapLabelSelector(controllerConfig.ConfigMapLabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
 	webhookServer := getWebhookServerConfig(kommodityConfig, deps.WebhookCertPEM, deps.WebhookKeyPEM)
-	webhookServer.Register("/convert", crwebconv.NewWebhookHandler(scheme))
+	webhookServer.Register(conversionWebhookPath, newConversionWebhook(scheme, logging.FromContext(ctx)))
 
 	manager, err := ctrl.NewManager(
 		genericServerConfig.LoopbackClientConfig,
 		ctrl.Options{
 			Scheme: scheme,
 			Logger: logger,
-			Cache: cache.Options{
-				Scheme: scheme,
+			// Reconcilers log with the logger of their context.
+			BaseContext: func() context.Context {
+				return context.WithoutCancel(ctx)
+			},
+			// Reconciles in flight when Kommodity shuts down are given the shutdown
+			// timeout to finish.
+			GracefulShutdownTimeout: &kommodityConfig.ShutdownConfig.Timeout,
+			// Controllers built for a kind listed in GroupKindConcurrency get its
+			// concurrency, the others MaxConcurrentReconciles.
+			Controller: ctrlconfig.Controller{
+				MaxConcurrentReconciles: controllerConfig.MaxConcurrentReconciles,
+				GroupKindConcurrency:    controllerConfig.GroupKindConcurrency,
 			},
+			Cache: newCacheOptions(scheme, controllerConfig),
+			// Metrics and health checks are served on the combined server, see NewHTTPMuxFactory.
 			Metrics: metricsserver.Options{
 				BindAddress: "0",
 			},
@@ -93,12 +113,14 @@ func NewAggregatedControllerManager(ctx context.Context,
 	}
 
 	controllerOpts := controller.Options{
-		MaxConcurrentReconciles: MaxConcurrentReconciles,
+		NewQueue: newControllerQueue(controllerConfig),
 		LogConstructor: func(_ *reconcile.Request) logr.Logger {
 			return logger
 		},
 	}
 
+	setManagerHealthChecks(manager, webhookServer)
+
 	clusterCache, err := setupClusterCacheWithManager(ctx, manager, controllerOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup ClusterCache: %w", err)
@@ -132,16 +154,118 @@ func NewAggregatedControllerManager(ctx context.Context,
 		return nil, fmt.Errorf("failed to setup garbage collector: %w", err)
 	}
 
+	err = setupNamespaceLifecycleController(ctx, manager, genericServerConfig.LoopbackClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup namespace lifecycle controller: %w", err)
+	}
+
+	err = setupWebhookMonitor(ctx, webhookMonitorDeps{
+		manager:    manager,
+		webhookURL: fmt.Sprintf("https://localhost:%d", kommodityConfig.WebhookPort),
+		caBundle:   deps.WebhookCertPEM,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup webhook monitor: %w", err)
+	}
+
 	logger.Info("Controller manager created")
 
 	return manager, nil
 }
 
+// newControllerQueue returns the work queue constructor of the controllers. Each
+// controller gets its own rate limiter, so that the backoff of an object and the overall
+// requeue rate are tracked per controller as with the default of controller-runtime.
+func newControllerQueue(controllerConfig *config.ControllerConfig) func(string,
+	workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(controllerName string,
+		_ workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		rateLimiter := workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
+				controllerConfig.RateLimitBaseDelay, controllerConfig.RateLimitMaxDelay),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{
+				Limiter: rate.NewLimiter(rate.Limit(controllerConfig.RateLimitQPS), controllerConfig.RateLimitBurst),
+			},
+		)
+
+		return workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter,
+			workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Name: controllerName})
+	}
+}
+
+// newCacheOptions scopes the informers of the controllers to the configured namespaces,
+// and the Secrets and ConfigMaps to the configured label selectors, so that large fleets
+// are not cached whole. Managed fields are never read by the controllers and are dropped.
+func newCacheOptions(scheme *runtime.Scheme, controllerConfig *config.ControllerConfig) cache.Options {
+	options := cache.Options{
+		Scheme:           scheme,
+		SyncPeriod:       &controllerConfig.SyncPeriod,
+		DefaultTransform: cache.TransformStripManagedFields(),
+		ByObject:         map[client.Object]cache.ByObject{},
+	}
+
+	if len(controllerConfig.WatchNamespaces) > 0 {
+		options.DefaultNamespaces = map[string]cache.Config{}
+
+		for _, namespace := range controllerConfig.WatchNamespaces {
+			options.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+
+	if controllerConfig.SecretLabelSelector != nil {
+		options.ByObject[&corev1.Secret{}] = cache.ByObject{Label: controllerConfig.SecretLabelSelector}
+	}
+
+	if controllerConfig.ConfigMapLabelSelector != nil {
+		options.ByObject[&corev1.ConfigMap{}] = cache.ByObject{
+			Namespaces: configMapNamespaces(options.DefaultNamespaces, controllerConfig.ConfigMapLabelSelector),
+		}
+	}
+
+	return options
+}
+
+// configMapNamespaces selects the ConfigMaps of the watched namespaces by the selector,
+// but every ConfigMap of the Kommodity namespace, such as the dynamic configuration,
+// the Secrets re-encryption requests and the TenantTemplates.
+func configMapNamespaces(watched map[string]cache.Config, selector labels.Selector) map[string]cache.Config {
+	namespaces := map[string]cache.Config{}
+
+	if len(watched) == 0 {
+		namespaces[cache.AllNamespaces] = cache.Config{LabelSelector: selector}
+	}
+
+	for namespace := range watched {
+		namespaces[namespace] = cache.Config{LabelSelector: selector}
+	}
+
+	namespaces[config.KommodityNamespace] = cache.Config{LabelSelector: labels.Everything()}
+
+	return namespaces
+}
+
+// validateConfigMapLabelSelector refuses a selector that hides the ConfigMaps installing
+// the add-ons of clusters, as their controllers would never be notified about them.
+func validateConfigMapLabelSelector(selector labels.Selector) error {
+	if selector == nil {
+		return nil
+	}
+
+	for _, filter := range reconciler.AddonConfigMapWatchFilters() {
+		if !selector.Matches(labels.Set{clusterv1.WatchLabel: filter}) {
+			return fmt.Errorf("%w: %s does not select %s=%s", ErrConfigMapSelectorHidesAddons,
+				selector, clusterv1.WatchLabel, filter)
+		}
+	}
+
+	return nil
+}
+
 func getWebhookServerConfig(kommodityConfig *config.KommodityConfig,
 	certPEM []byte, keyPEM []byte) ctrlwebhook.Server {
 	return ctrlwebhook.NewServer(ctrlwebhook.Options{
 		Port:    kommodityConfig.WebhookPort,
-		TLSOpts: setupWebhookTLSOptions(certPEM, keyPEM),
+		TLSOpts: append(setupWebhookTLSOptions(certPEM, keyPEM), restrictTLS(kommodityConfig.TLSConfig)),
 	})
 }
 
@@ -191,6 +315,19 @@ func setupTalosProxy(ctx context.Context,
 	return nil
 }
 
+// restrictTLS applies the configured TLS versions and cipher suites.
+func restrictTLS(tlsConfig *config.TLSConfig) func(*tls.Config) {
+	return func(c *tls.Config) {
+		if tlsConfig == nil {
+			return
+		}
+
+		c.MinVersion = tlsConfig.MinVersion
+		c.MaxVersion = tlsConfig.MaxVersion
+		c.CipherSuites = tlsConfig.CipherSuites
+	}
+}
+
 func setupWebhookTLSOptions(certPEM []byte, keyPEM []byte) []func(*tls.Config) {
 	return []func(*tls.Config){
 		func(c *tls.Config) {
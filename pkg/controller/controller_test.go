@@ -0,0 +1,147 @@
+package controller_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestControllerQueueUsesConfiguredBackoff(t *testing.T) {
+	t.Parallel()
+
+	queue := controller.NewControllerQueue(&config.ControllerConfig{
+		RateLimitBaseDelay: time.Millisecond,
+		RateLimitMaxDelay:  time.Second,
+		RateLimitQPS:       100,
+		RateLimitBurst:     10,
+	}, "test")
+	defer queue.ShutDown()
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cluster"}}
+
+	for range 3 {
+		queue.AddRateLimited(request)
+
+		item, shutdown := queue.Get()
+		if shutdown || item != request {
+			t.Fatalf("expected the request to be requeued, got %v (shutdown %v)", item, shutdown)
+		}
+
+		queue.Done(item)
+	}
+
+	if queue.NumRequeues(request) != 3 {
+		t.Fatalf("expected the backoff of the request to be tracked, got %d requeues", queue.NumRequeues(request))
+	}
+}
+
+func TestCacheOptionsScopeInformers(t *testing.T) {
+	t.Parallel()
+
+	secretSelector := labels.SelectorFromSet(labels.Set{"cluster.x-k8s.io/cluster-name": "cluster"})
+
+	options := controller.NewCacheOptions(runtime.NewScheme(), &config.ControllerConfig{
+		WatchNamespaces:     []string{config.KommodityNamespace, "tenant"},
+		SecretLabelSelector: secretSelector,
+	})
+
+	if len(options.DefaultNamespaces) != 2 {
+		t.Fatalf("expected the informers to be scoped to 2 namespaces, got %v", options.DefaultNamespaces)
+	}
+
+	if _, found := options.DefaultNamespaces["tenant"]; !found {
+		t.Fatalf("expected the informers to watch the tenant namespace, got %v", options.DefaultNamespaces)
+	}
+
+	if len(options.ByObject) != 1 {
+		t.Fatalf("expected only the Secrets to be selected, got %d objects", len(options.ByObject))
+	}
+
+	for obj, byObject := range options.ByObject {
+		if _, isSecret := obj.(*corev1.Secret); !isSecret || byObject.Label.String() != secretSelector.String() {
+			t.Fatalf("expected the Secrets to be selected by %s, got %T selected by %v", secretSelector, obj, byObject.Label)
+		}
+	}
+
+	if options.DefaultTransform == nil {
+		t.Fatalf("expected the managed fields to be stripped")
+	}
+}
+
+func TestCacheOptionsWatchEverythingByDefault(t *testing.T) {
+	t.Parallel()
+
+	options := controller.NewCacheOptions(runtime.NewScheme(), &config.ControllerConfig{})
+
+	if options.DefaultNamespaces != nil || len(options.ByObject) != 0 {
+		t.Fatalf("expected every namespace and object to be watched, got %v and %v",
+			options.DefaultNamespaces, options.ByObject)
+	}
+}
+
+func TestCacheOptionsKeepKommodityConfigMaps(t *testing.T) {
+	t.Parallel()
+
+	configMapSelector := labels.SelectorFromSet(labels.Set{"team": "a"})
+
+	options := controller.NewCacheOptions(runtime.NewScheme(), &config.ControllerConfig{
+		ConfigMapLabelSelector: configMapSelector,
+	})
+
+	for obj, byObject := range options.ByObject {
+		if _, isConfigMap := obj.(*corev1.ConfigMap); !isConfigMap {
+			t.Fatalf("expected only the ConfigMaps to be selected, got %T", obj)
+		}
+
+		if byObject.Namespaces[cache.AllNamespaces].LabelSelector.String() != configMapSelector.String() {
+			t.Fatalf("expected the ConfigMaps to be selected by %s, got %v", configMapSelector, byObject.Namespaces)
+		}
+
+		if !byObject.Namespaces[config.KommodityNamespace].LabelSelector.Empty() {
+			t.Fatalf("expected every ConfigMap of %s to be watched, got %v",
+				config.KommodityNamespace, byObject.Namespaces[config.KommodityNamespace].LabelSelector)
+		}
+	}
+}
+
+func TestValidateConfigMapLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		selector string
+		valid    bool
+	}{
+		"add-on ConfigMaps selected": {selector: clusterv1.WatchLabel, valid: true},
+		"add-on ConfigMaps hidden":   {selector: "team=a", valid: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := labels.Parse(test.selector)
+			if err != nil {
+				t.Fatalf("failed to parse selector %s: %v", test.selector, err)
+			}
+
+			err = controller.ValidateConfigMapLabelSelector(selector)
+			if test.valid != (err == nil) {
+				t.Fatalf("expected %s to be valid: %v, got %v", test.selector, test.valid, err)
+			}
+
+			if err != nil && !errors.Is(err, controller.ErrConfigMapSelectorHidesAddons) {
+				t.Fatalf("expected %v, got %v", controller.ErrConfigMapSelectorHidesAddons, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	crwebconv "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// conversionWebhookPath is the path the CRD conversion webhooks are served on. It must
+// match the path the provider CRDs are rewritten to.
+const conversionWebhookPath = "/convert"
+
+//nolint:gochecknoglobals // Metrics are registered once in the process wide legacy registry.
+var (
+	conversionFailures = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "conversion_webhook",
+		Name:           "failures_total",
+		Help:           "Number of CRD conversion requests that failed.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"group", "kind"})
+
+	conversionPassthroughs = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "conversion_webhook",
+		Name:           "passthrough_total",
+		Help:           "Number of objects converted without a registered converter by only changing their apiVersion.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"group", "kind"})
+
+	registerConversionMetricsOnce sync.Once
+)
+
+func registerConversionMetrics() {
+	registerConversionMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(conversionFailures, conversionPassthroughs)
+	})
+}
+
+// conversionWebhook serves the conversion webhooks of every CRD rewritten to point at
+// Kommodity. Group kinds with a hub version in the scheme are converted through their
+// hub, as by the controller-runtime conversion webhook. Objects of other group kinds
+// are passed through with their apiVersion set to the desired one, as with the None
+// conversion strategy, so that a provider type without conversion functions degrades
+// to a counted pass-through instead of failing every read of the CRD.
+type conversionWebhook struct {
+	scheme  *runtime.Scheme
+	decoder *crwebconv.Decoder
+	logger  *zap.Logger
+
+	// hubs registers the hub version of every convertible group kind of the scheme.
+	hubs map[schema.GroupKind]schema.GroupVersionKind
+}
+
+var _ http.Handler = &conversionWebhook{}
+
+func newConversionWebhook(scheme *runtime.Scheme, logger *zap.Logger) *conversionWebhook {
+	registerConversionMetrics()
+
+	hubs := make(map[schema.GroupKind]schema.GroupVersionKind)
+
+	for gvk := range scheme.AllKnownTypes() {
+		obj, err := scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+
+		if _, isHub := obj.(conversion.Hub); isHub {
+			hubs[gvk.GroupKind()] = gvk
+		}
+	}
+
+	return &conversionWebhook{
+		scheme:  scheme,
+		decoder: crwebconv.NewDecoder(scheme),
+		logger:  logger,
+		hubs:    hubs,
+	}
+}
+
+// ServeHTTP answers a ConversionReview.
+func (w *conversionWebhook) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	review := &apiextensionsv1.ConversionReview{}
+
+	err := json.NewDecoder(req.Body).Decode(review)
+	if err != nil || review.Request == nil {
+		w.logger.Warn("Failed to read conversion request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	review.Response = w.convertRequest(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(writer).Encode(review)
+	if err != nil {
+		w.logger.Warn("Failed to write conversion response", zap.Error(err))
+	}
+}
+
+// convertRequest converts every object of the request, failing the whole request if
+// any object cannot be converted.
+func (w *conversionWebhook) convertRequest(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	objects := make([]runtime.RawExtension, 0, len(req.Objects))
+
+	for _, raw := range req.Objects {
+		obj, gvk, err := w.convert(raw.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			conversionFailures.WithLabelValues(gvk.Group, gvk.Kind).Inc()
+			w.logger.Warn("Failed to convert object",
+				zap.String("uid", string(req.UID)),
+				zap.String("from", gvk.String()),
+				zap.String("to", req.DesiredAPIVersion),
+				zap.Error(err))
+
+			return &apiextensionsv1.ConversionResponse{
+				Result: metav1.Status{Status: metav1.StatusFailure, Message: err.Error()},
+			}
+		}
+
+		objects = append(objects, runtime.RawExtension{Object: obj})
+	}
+
+	return &apiextensionsv1.ConversionResponse{
+		ConvertedObjects: objects,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+// convert converts a single object to the desired API version. It returns the group
+// version kind of the object for reporting.
+func (w *conversionWebhook) convert(raw []byte,
+	desiredAPIVersion string) (runtime.Object, schema.GroupVersionKind, error) {
+	src := &unstructured.Unstructured{}
+
+	err := src.UnmarshalJSON(raw)
+	if err != nil {
+		return nil, schema.GroupVersionKind{}, fmt.Errorf("failed to decode object: %w", err)
+	}
+
+	srcGVK := src.GroupVersionKind()
+	dstGVK := schema.FromAPIVersionAndKind(desiredAPIVersion, srcGVK.Kind)
+
+	if dstGVK.GroupKind() != srcGVK.GroupKind() {
+		return nil, srcGVK, fmt.Errorf("%w: %s to %s", ErrConversionAcrossGroups, srcGVK, desiredAPIVersion)
+	}
+
+	hubGVK, convertible := w.hubs[srcGVK.GroupKind()]
+	if !convertible || srcGVK == dstGVK || !w.scheme.Recognizes(srcGVK) || !w.scheme.Recognizes(dstGVK) {
+		if srcGVK != dstGVK {
+			conversionPassthroughs.WithLabelValues(srcGVK.Group, srcGVK.Kind).Inc()
+		}
+
+		src.SetAPIVersion(desiredAPIVersion)
+
+		return src, srcGVK, nil
+	}
+
+	typedSrc, _, err := w.decoder.Decode(raw)
+	if err != nil {
+		return nil, srcGVK, fmt.Errorf("failed to decode %s: %w", srcGVK, err)
+	}
+
+	dst, err := w.scheme.New(dstGVK)
+	if err != nil {
+		return nil, srcGVK, fmt.Errorf("failed to allocate %s: %w", dstGVK, err)
+	}
+
+	dst.GetObjectKind().SetGroupVersionKind(dstGVK)
+
+	err = w.convertThroughHub(typedSrc, dst, hubGVK)
+	if err != nil {
+		return nil, srcGVK, err
+	}
+
+	return dst, srcGVK, nil
+}
+
+// convertThroughHub converts between a hub and a spoke, or between two spokes through
+// the hub of their group kind.
+func (w *conversionWebhook) convertThroughHub(src, dst runtime.Object, hubGVK schema.GroupVersionKind) error {
+	srcHub, srcIsHub := src.(conversion.Hub)
+	dstHub, dstIsHub := dst.(conversion.Hub)
+	srcSpoke, srcIsSpoke := src.(conversion.Convertible)
+	dstSpoke, dstIsSpoke := dst.(conversion.Convertible)
+
+	switch {
+	case srcIsHub && dstIsSpoke:
+		return wrapConversionError(dstSpoke.ConvertFrom(srcHub))
+	case dstIsHub && srcIsSpoke:
+		return wrapConversionError(srcSpoke.ConvertTo(dstHub))
+	case srcIsSpoke && dstIsSpoke:
+		hubObj, err := w.scheme.New(hubGVK)
+		if err != nil {
+			return fmt.Errorf("failed to allocate hub: %w", err)
+		}
+
+		hub, _ := hubObj.(conversion.Hub)
+
+		err = srcSpoke.ConvertTo(hub)
+		if err != nil {
+			return wrapConversionError(err)
+		}
+
+		return wrapConversionError(dstSpoke.ConvertFrom(hub))
+	default:
+		return fmt.Errorf("%w: %T to %T", ErrNotConvertible, src, dst)
+	}
+}
+
+func wrapConversionError(err error) error {
+	if err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+
+	return nil
+}
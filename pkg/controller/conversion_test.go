@@ -0,0 +1,148 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ipamv1alpha1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	ipamv1beta1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+)
+
+func newConversionScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{ipamv1alpha1.AddToScheme, ipamv1beta1.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	return scheme
+}
+
+// convertObjects posts a ConversionReview of the objects to the conversion webhook and
+// returns its response.
+func convertObjects(t *testing.T, desiredAPIVersion string,
+	objects ...map[string]any) *apiextensionsv1.ConversionResponse {
+	t.Helper()
+
+	request := &apiextensionsv1.ConversionRequest{UID: "request-uid", DesiredAPIVersion: desiredAPIVersion}
+
+	for _, obj := range objects {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatalf("failed to encode object: %v", err)
+		}
+
+		request.Objects = append(request.Objects, runtime.RawExtension{Raw: raw})
+	}
+
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  request,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode review: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	controller.NewConversionWebhookForTest(newConversionScheme(t)).ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body)))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the review to be answered, got status %d", recorder.Code)
+	}
+
+	review := &apiextensionsv1.ConversionReview{}
+
+	err = json.Unmarshal(recorder.Body.Bytes(), review)
+	if err != nil || review.Response == nil || review.Response.UID != "request-uid" {
+		t.Fatalf("expected a response to the request, got %s (%v)", recorder.Body.String(), err)
+	}
+
+	return review.Response
+}
+
+func convertedObject(t *testing.T, response *apiextensionsv1.ConversionResponse) *unstructured.Unstructured {
+	t.Helper()
+
+	if response.Result.Status != metav1.StatusSuccess || len(response.ConvertedObjects) != 1 {
+		t.Fatalf("expected a single converted object, got %+v", response)
+	}
+
+	obj := &unstructured.Unstructured{}
+
+	err := obj.UnmarshalJSON(response.ConvertedObjects[0].Raw)
+	if err != nil {
+		t.Fatalf("failed to decode converted object: %v", err)
+	}
+
+	return obj
+}
+
+func TestConversionWebhookConvertsThroughHub(t *testing.T) {
+	t.Parallel()
+
+	response := convertObjects(t, ipamv1beta1.GroupVersion.String(), map[string]any{
+		"apiVersion": ipamv1alpha1.GroupVersion.String(),
+		"kind":       "IPAddressClaim",
+		"metadata":   map[string]any{"name": "claim", "namespace": "default"},
+		"spec":       map[string]any{"poolRef": map[string]any{"kind": "InClusterIPPool", "name": "pool"}},
+	})
+
+	obj := convertedObject(t, response)
+	if obj.GetAPIVersion() != ipamv1beta1.GroupVersion.String() {
+		t.Fatalf("expected the claim to be converted to v1beta1, got %s", obj.GetAPIVersion())
+	}
+
+	poolName, _, _ := unstructured.NestedString(obj.Object, "spec", "poolRef", "name")
+	if poolName != "pool" {
+		t.Fatalf("expected the pool reference to be converted, got %v", obj.Object["spec"])
+	}
+}
+
+func TestConversionWebhookPassesThroughUnknownKinds(t *testing.T) {
+	t.Parallel()
+
+	response := convertObjects(t, "infrastructure.example.com/v1beta1", map[string]any{
+		"apiVersion": "infrastructure.example.com/v1alpha1",
+		"kind":       "ExampleMachine",
+		"metadata":   map[string]any{"name": "machine", "namespace": "default"},
+		"spec":       map[string]any{"size": "large"},
+	})
+
+	obj := convertedObject(t, response)
+	if obj.GetAPIVersion() != "infrastructure.example.com/v1beta1" {
+		t.Fatalf("expected the apiVersion to be set to the desired one, got %s", obj.GetAPIVersion())
+	}
+
+	size, _, _ := unstructured.NestedString(obj.Object, "spec", "size")
+	if size != "large" {
+		t.Fatalf("expected the spec to be kept, got %v", obj.Object["spec"])
+	}
+}
+
+func TestConversionWebhookRejectsOtherGroups(t *testing.T) {
+	t.Parallel()
+
+	response := convertObjects(t, "infrastructure.example.com/v1beta1", map[string]any{
+		"apiVersion": ipamv1alpha1.GroupVersion.String(),
+		"kind":       "IPAddressClaim",
+		"metadata":   map[string]any{"name": "claim", "namespace": "default"},
+	})
+
+	if response.Result.Status != metav1.StatusFailure || len(response.ConvertedObjects) != 0 {
+		t.Fatalf("expected the conversion to fail, got %+v", response)
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"k8s.io/component-base/metrics"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// MetricsPath is the path of the metrics of the controller manager, like the depth of
+	// the reconcile queues and the reconcile errors.
+	MetricsPath = "/controller/metrics"
+	// HealthzPath is the path of the health checks of the controller manager. Individual
+	// checks are served on HealthzPath/<name>.
+	HealthzPath = "/controller/healthz"
+)
+
+// managerHealthChecks holds the health checks of the controller manager. The manager is
+// created by the API server after the combined server registered its endpoints, so the
+// checks are set late and read on every request.
+type managerHealthChecks struct {
+	lock   sync.RWMutex
+	checks map[string]healthz.Checker
+}
+
+//nolint:gochecknoglobals // process-wide checks of the single controller manager.
+var controllerManagerHealth = &managerHealthChecks{}
+
+// set replaces the health checks.
+func (h *managerHealthChecks) set(checks map[string]healthz.Checker) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.checks = checks
+}
+
+// ServeHTTP runs the health checks, and fails while the controller manager has not been
+// created.
+func (h *managerHealthChecks) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	h.lock.RLock()
+	checks := h.checks
+	h.lock.RUnlock()
+
+	if checks == nil {
+		http.Error(writer, "controller manager not created", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	(&healthz.Handler{Checks: checks}).ServeHTTP(writer, req)
+}
+
+// setManagerHealthChecks registers the health checks of the manager: it answers, it was
+// started, and its webhook server listens.
+func setManagerHealthChecks(manager ctrl.Manager, webhookServer ctrlwebhook.Server) {
+	controllerManagerHealth.set(map[string]healthz.Checker{
+		"ping": healthz.Ping,
+		"started": func(*http.Request) error {
+			select {
+			case <-manager.Elected():
+				return nil
+			default:
+				return ErrManagerNotStarted
+			}
+		},
+		"webhook": webhookServer.StartedChecker(),
+	})
+}
+
+// NewHTTPMuxFactory serves the metrics and health checks of the controller manager on
+// the combined server, as the manager serves neither itself.
+func NewHTTPMuxFactory() combinedserver.HTTPMuxFactory {
+	return func(mux *http.ServeMux) error {
+		mux.Handle(MetricsPath, metrics.HandlerFor(ctrlmetrics.Registry, metrics.HandlerOpts{}))
+
+		healthHandler := http.StripPrefix(HealthzPath, controllerManagerHealth)
+		mux.Handle(HealthzPath, healthHandler)
+		mux.Handle(HealthzPath+"/", healthHandler)
+
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package controller_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+var errNotReady = errors.New("not ready")
+
+func serveControllerEndpoint(t *testing.T, mux *http.ServeMux, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return recorder
+}
+
+//nolint:paralleltest // the health checks of the controller manager are process wide.
+func TestControllerEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+
+	err := controller.NewHTTPMuxFactory()(mux)
+	if err != nil {
+		t.Fatalf("expected the endpoints to be registered, got %v", err)
+	}
+
+	t.Cleanup(func() { controller.SetManagerHealthChecks(nil) })
+
+	recorder := serveControllerEndpoint(t, mux, controller.HealthzPath)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected the health check to fail before the manager is created, got %d", recorder.Code)
+	}
+
+	controller.SetManagerHealthChecks(map[string]healthz.Checker{
+		"ping":    healthz.Ping,
+		"webhook": func(*http.Request) error { return errNotReady },
+	})
+
+	recorder = serveControllerEndpoint(t, mux, controller.HealthzPath)
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), "[-]webhook") {
+		t.Fatalf("expected the failing webhook check to be reported, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	recorder = serveControllerEndpoint(t, mux, controller.HealthzPath+"/ping")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the ping check to pass, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	recorder = serveControllerEndpoint(t, mux, controller.MetricsPath)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the metrics to be served, got %d", recorder.Code)
+	}
+}
@@ -14,4 +14,40 @@ var (
 	// enabled but a required dependency (controller-runtime Manager or
 	// loopback rest.Config) is nil.
 	ErrGarbageCollectorMissingDep = errors.New("garbage collector dependency missing")
+
+	// ErrNamespaceControllerClientBuild is returned when constructing the typed,
+	// metadata, or discovery client for the namespace lifecycle controller fails.
+	ErrNamespaceControllerClientBuild = errors.New("failed to build namespace lifecycle controller client")
+
+	// ErrNamespaceControllerMissingDep is returned when a required dependency of the
+	// namespace lifecycle controller (controller-runtime Manager or loopback
+	// rest.Config) is nil.
+	ErrNamespaceControllerMissingDep = errors.New("namespace lifecycle controller dependency missing")
+
+	// ErrConfigMapSelectorHidesAddons is returned when the ConfigMap label selector of the
+	// informers does not select the ConfigMaps that install the add-ons of clusters.
+	ErrConfigMapSelectorHidesAddons = errors.New("ConfigMap label selector hides the add-on ConfigMaps")
+
+	// ErrWebhookMonitorMissingDep is returned when a required dependency of the webhook
+	// monitor (controller-runtime Manager or webhook serving certificate) is missing.
+	ErrWebhookMonitorMissingDep = errors.New("webhook monitor dependency missing")
+
+	// ErrWebhookInvalidCABundle is returned when a webhook caBundle contains no PEM certificate.
+	ErrWebhookInvalidCABundle = errors.New("webhook caBundle contains no valid certificate")
+
+	// ErrWebhookUnreachable is returned by the readiness check while registered webhooks
+	// fail their probes.
+	ErrWebhookUnreachable = errors.New("webhooks are unreachable")
+
+	// ErrConversionAcrossGroups is returned when a conversion request asks for an API
+	// version of another group.
+	ErrConversionAcrossGroups = errors.New("cannot convert between API groups")
+
+	// ErrNotConvertible is returned when a group kind has a hub but one of its versions
+	// implements no conversion to it.
+	ErrNotConvertible = errors.New("version is not convertible")
+
+	// ErrManagerNotStarted is returned by the health check of the controller manager until
+	// it is started.
+	ErrManagerNotStarted = errors.New("controller manager not started")
 )
@@ -2,12 +2,22 @@ package controller
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // This file exposes internal symbols of the controller package to the
@@ -57,3 +67,63 @@ func NewRunnerForTest(mapper meta.ResettableRESTMapper) *RunnerForTest {
 func (r *RunnerForTest) RunRESTMapperReset(ctx context.Context, period time.Duration) {
 	r.inner.runRESTMapperReset(ctx, period)
 }
+
+// SetupNamespaceLifecycleController is an exported wrapper around the unexported
+// setupNamespaceLifecycleController so external tests can exercise its dependency
+// validation.
+func SetupNamespaceLifecycleController(ctx context.Context, mgr ctrl.Manager, restConfig *rest.Config) error {
+	return setupNamespaceLifecycleController(ctx, mgr, restConfig)
+}
+
+// WebhookMonitorForTest wraps a webhookMonitor so external tests can run single
+// monitoring passes without a Manager.
+type WebhookMonitorForTest struct {
+	inner *webhookMonitor
+}
+
+// NewWebhookMonitorForTest builds a webhook monitor around the given client and recorder.
+func NewWebhookMonitorForTest(c client.Client, recorder record.EventRecorder,
+	webhookURL string, caBundle []byte) *WebhookMonitorForTest {
+	return &WebhookMonitorForTest{inner: newWebhookMonitor(c, recorder, webhookURL, caBundle)}
+}
+
+// Check proxies to the unexported check method.
+func (m *WebhookMonitorForTest) Check(ctx context.Context) {
+	m.inner.check(ctx)
+}
+
+// Ready proxies to the readiness check of the webhook monitor.
+func (m *WebhookMonitorForTest) Ready() error {
+	return m.inner.Check()
+}
+
+// WebhookFailureThreshold re-exports the number of failed probes before a webhook is reported.
+const WebhookFailureThreshold = webhookFailureThreshold
+
+// NewConversionWebhookForTest returns the conversion webhook handler for the scheme.
+func NewConversionWebhookForTest(scheme *runtime.Scheme) http.Handler {
+	return newConversionWebhook(scheme, zap.NewNop())
+}
+
+// SetManagerHealthChecks replaces the health checks served on HealthzPath, nil serving
+// them as if the controller manager was not created.
+func SetManagerHealthChecks(checks map[string]healthz.Checker) {
+	controllerManagerHealth.set(checks)
+}
+
+// NewControllerQueue is an exported wrapper around the unexported newControllerQueue.
+func NewControllerQueue(controllerConfig *config.ControllerConfig,
+	controllerName string) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return newControllerQueue(controllerConfig)(controllerName, nil)
+}
+
+// NewCacheOptions is an exported wrapper around the unexported newCacheOptions.
+func NewCacheOptions(scheme *runtime.Scheme, controllerConfig *config.ControllerConfig) cache.Options {
+	return newCacheOptions(scheme, controllerConfig)
+}
+
+// ValidateConfigMapLabelSelector is an exported wrapper around the unexported
+// validateConfigMapLabelSelector.
+func ValidateConfigMapLabelSelector(selector labels.Selector) error {
+	return validateConfigMapLabelSelector(selector)
+}
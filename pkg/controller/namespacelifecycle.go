@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	namespacecontroller "k8s.io/kubernetes/pkg/controller/namespace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// namespaceSyncPeriod is the resync period of the namespace informer. Matches the
+	// upstream kube-controller-manager default.
+	namespaceSyncPeriod = 5 * time.Minute
+
+	// namespaceWorkers is the number of namespaces deleted concurrently. Matches the
+	// upstream kube-controller-manager default.
+	namespaceWorkers = 10
+
+	// namespaceUserAgent is the User-Agent string used by the namespace lifecycle
+	// controller's rest clients.
+	namespaceUserAgent = "kommodity-namespace-controller"
+)
+
+// setupNamespaceLifecycleController wires the upstream Kubernetes namespace controller
+// into the controller manager. Once a namespace is Terminating, the controller
+// enumerates every namespaced resource the API server advertises via discovery,
+// deletes the objects of the namespace and finally removes the kubernetes finalizer,
+// which lets the API server remove the namespace itself.
+func setupNamespaceLifecycleController(ctx context.Context, mgr ctrl.Manager, restConfig *rest.Config) error {
+	logger := logging.FromContext(ctx)
+
+	if mgr == nil {
+		return fmt.Errorf("%w: controller-runtime Manager is nil", ErrNamespaceControllerMissingDep)
+	}
+
+	if restConfig == nil {
+		return fmt.Errorf("%w: loopback rest.Config is nil", ErrNamespaceControllerMissingDep)
+	}
+
+	logger.Info("Setting up namespace lifecycle controller")
+
+	runner, err := newNamespaceLifecycleRunner(ctx, restConfig)
+	if err != nil {
+		return err
+	}
+
+	err = mgr.Add(runner)
+	if err != nil {
+		return fmt.Errorf("failed to register namespace lifecycle controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// namespaceLifecycleRunner runs the namespace controller and its informers as a
+// controller-runtime manager.Runnable.
+type namespaceLifecycleRunner struct {
+	controller *namespacecontroller.NamespaceController
+	informers  informers.SharedInformerFactory
+}
+
+func newNamespaceLifecycleRunner(ctx context.Context, restConfig *rest.Config) (*namespaceLifecycleRunner, error) {
+	cfg := rest.CopyConfig(restConfig)
+	cfg.UserAgent = namespaceUserAgent
+
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: typed client: %w", ErrNamespaceControllerClientBuild, err)
+	}
+
+	metadataClient, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata client: %w", ErrNamespaceControllerClientBuild, err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: discovery client: %w", ErrNamespaceControllerClientBuild, err)
+	}
+
+	sharedInformers := informers.NewSharedInformerFactory(kubeClient, namespaceSyncPeriod)
+
+	// Discovery is queried on every sync, so resources of CRDs installed after startup
+	// are cleaned up as well.
+	namespaceController := namespacecontroller.NewNamespaceController(
+		ctx,
+		kubeClient,
+		metadataClient,
+		discoveryClient.ServerPreferredNamespacedResources,
+		sharedInformers.Core().V1().Namespaces(),
+		namespaceSyncPeriod,
+		corev1.FinalizerKubernetes,
+	)
+
+	return &namespaceLifecycleRunner{
+		controller: namespaceController,
+		informers:  sharedInformers,
+	}, nil
+}
+
+// Compile-time assertion that the runner implements manager.Runnable.
+var _ manager.Runnable = (*namespaceLifecycleRunner)(nil)
+
+// Start runs the namespace informer and controller workers until ctx is cancelled.
+func (r *namespaceLifecycleRunner) Start(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Starting namespace lifecycle controller")
+
+	r.informers.Start(ctx.Done())
+
+	// Run blocks until ctx is cancelled.
+	r.controller.Run(ctx, namespaceWorkers)
+
+	r.informers.Shutdown()
+	logger.Info("Namespace lifecycle controller stopped")
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestSetupNamespaceLifecycleController_NilManagerReturnsError(t *testing.T) {
+	t.Parallel()
+
+	err := controller.SetupNamespaceLifecycleController(context.Background(), nil, &rest.Config{})
+	if !errors.Is(err, controller.ErrNamespaceControllerMissingDep) {
+		t.Errorf("expected ErrNamespaceControllerMissingDep, got %v", err)
+	}
+}
+
+func TestSetupNamespaceLifecycleController_NilRestConfigReturnsError(t *testing.T) {
+	t.Parallel()
+
+	var mgr ctrl.Manager = &stubManager{}
+
+	err := controller.SetupNamespaceLifecycleController(context.Background(), mgr, nil)
+	if !errors.Is(err, controller.ErrNamespaceControllerMissingDep) {
+		t.Errorf("expected ErrNamespaceControllerMissingDep, got %v", err)
+	}
+}
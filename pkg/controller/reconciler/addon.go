@@ -0,0 +1,360 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// addonControllerName is the name used to register the controller.
+	addonControllerName = "kommodity-addon-controller"
+	// addonRecorderName is the component the events of the controller are reported as.
+	addonRecorderName = "kommodity-addon"
+
+	// addonValuesKey is the key of the chart values in the values Secret of an addon.
+	addonValuesKey = "values.yaml"
+
+	// FinalizerClusterAddon holds a ClusterAddon until its chart is uninstalled from the
+	// workload cluster.
+	FinalizerClusterAddon = "kommodity.io/cluster-addon"
+)
+
+// AddonReconciler installs the Helm chart of a ClusterAddon into its workload cluster
+// and follows the install job. The chart is installed again whenever the spec or the
+// values change.
+type AddonReconciler struct {
+	client.Client
+
+	Recorder record.EventRecorder
+
+	// downstreamClient returns a client for the workload cluster, defaults to one built
+	// from the kubeconfig Secret of the cluster.
+	downstreamClient func(ctx context.Context, clusterName string) (client.Client, error)
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *AddonReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up ClusterAddon reconciler")
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(addonRecorderName)
+	}
+
+	if r.downstreamClient == nil {
+		r.downstreamClient = func(ctx context.Context, clusterName string) (client.Client, error) {
+			return (&DownstreamClientConfig{Client: r.Client, ClusterName: clusterName}).
+				FetchDownstreamKubernetesClient(ctx)
+		}
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(addonControllerName).
+		For(&kommodityv1alpha1.ClusterAddon{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.addonsForValuesSecret)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up ClusterAddon controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile moves a ClusterAddon through its phases.
+//
+//nolint:funlen,cyclop // One step of the install flow per phase, no real complexity here.
+func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("clusterAddon", req.String()))
+
+	addon := &kommodityv1alpha1.ClusterAddon{}
+
+	err := r.Get(ctx, req.NamespacedName, addon)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger,
+		client.ObjectKey{Namespace: addon.Namespace, Name: addon.Spec.ClusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	if !addon.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, addon)
+	}
+
+	if controllerutil.AddFinalizer(addon, FinalizerClusterAddon) {
+		err = r.Update(ctx, addon)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer to ClusterAddon %s: %w", addon.Name, err)
+		}
+	}
+
+	values, err := r.values(ctx, addon)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	jobConfig := addonJobConfig(addon)
+	valuesHash := hashValues(values)
+	changed := addon.Status.ObservedGeneration != addon.Generation || addon.Status.ValuesHash != valuesHash
+
+	if !changed && (addon.Status.Phase == kommodityv1alpha1.ClusterAddonPhaseInstalled ||
+		addon.Status.Phase == kommodityv1alpha1.ClusterAddonPhaseFailed) {
+		return ctrl.Result{}, nil
+	}
+
+	original := addon.DeepCopy()
+	result := ctrl.Result{RequeueAfter: RequeueAfter}
+
+	kubeClient, err := r.reachableCluster(ctx, addon)
+	if err != nil {
+		logger.Info("Workload cluster not reachable yet, requeuing", zap.Error(err))
+
+		addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhasePending
+		addon.Status.Message = err.Error()
+
+		return result, r.patchStatus(ctx, addon, original)
+	}
+
+	if changed {
+		if addon.Status.Phase == kommodityv1alpha1.ClusterAddonPhaseFailed {
+			// Retry even if the chart version and values stayed the same.
+			err = deleteHelmJob(ctx, kubeClient, &jobConfig)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		started, err := upgradeHelmChart(ctx, kubeClient, &jobConfig, values)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if started {
+			r.Recorder.Eventf(addon, corev1.EventTypeNormal, "Installing", "Installing %s %s",
+				jobConfig.Chart.Name, jobConfig.Chart.Version)
+		}
+
+		addon.Status.ObservedGeneration = addon.Generation
+		addon.Status.ValuesHash = valuesHash
+		addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhaseInstalling
+		addon.Status.Message = ""
+
+		return result, r.patchStatus(ctx, addon, original)
+	}
+
+	job, err := helmJob(ctx, kubeClient, &jobConfig)
+	if apierrors.IsNotFound(err) {
+		// The job expired before its outcome was seen; install again.
+		addon.Status.ObservedGeneration = 0
+
+		return ctrl.Result{Requeue: true}, r.patchStatus(ctx, addon, original)
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case job.Status.Succeeded > 0:
+		addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhaseInstalled
+		addon.Status.InstalledVersion = jobConfig.Chart.Version
+		result = ctrl.Result{}
+
+		r.Recorder.Eventf(addon, corev1.EventTypeNormal, "Installed", "Installed %s %s",
+			jobConfig.Chart.Name, jobConfig.Chart.Version)
+	case jobFailed(job):
+		addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhaseFailed
+		addon.Status.Message = fmt.Sprintf("install job %s/%s failed in the workload cluster", job.Namespace, job.Name)
+		result = ctrl.Result{}
+
+		r.Recorder.Event(addon, corev1.EventTypeWarning, "InstallFailed", addon.Status.Message)
+	}
+
+	return result, r.patchStatus(ctx, addon, original)
+}
+
+// reconcileDelete uninstalls the chart of a deleted addon before it releases the
+// finalizer. Nothing is uninstalled when the Cluster itself is gone or being deleted.
+func (r *AddonReconciler) reconcileDelete(ctx context.Context,
+	addon *kommodityv1alpha1.ClusterAddon) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(addon, FinalizerClusterAddon) {
+		return ctrl.Result{}, nil
+	}
+
+	cluster := &clusterv1.Cluster{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: addon.Namespace, Name: addon.Spec.ClusterName}, cluster)
+	if client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get Cluster %s: %w", addon.Spec.ClusterName, err)
+	}
+
+	if err == nil && cluster.DeletionTimestamp.IsZero() {
+		original := addon.DeepCopy()
+
+		kubeClient, err := r.reachableCluster(ctx, addon)
+		if err != nil {
+			addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhaseUninstalling
+			addon.Status.Message = err.Error()
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, r.patchStatus(ctx, addon, original)
+		}
+
+		uninstalled, err := uninstallHelmChart(ctx, kubeClient, addonJobConfig(addon))
+		if errors.Is(err, ErrHelmUninstallFailed) {
+			// Keep the finalizer, the release is still there. Deleting the failed job retries.
+			if addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseFailed {
+				r.Recorder.Event(addon, corev1.EventTypeWarning, "UninstallFailed", err.Error())
+			}
+
+			addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhaseFailed
+			addon.Status.Message = err.Error()
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, r.patchStatus(ctx, addon, original)
+		}
+
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if !uninstalled {
+			addon.Status.Phase = kommodityv1alpha1.ClusterAddonPhaseUninstalling
+			addon.Status.Message = ""
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, r.patchStatus(ctx, addon, original)
+		}
+
+		r.Recorder.Eventf(addon, corev1.EventTypeNormal, "Uninstalled", "Uninstalled %s", addon.Spec.Chart.Name)
+	}
+
+	controllerutil.RemoveFinalizer(addon, FinalizerClusterAddon)
+
+	err = r.Update(ctx, addon)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer from ClusterAddon %s: %w", addon.Name, err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// values reads the chart values from the values Secret of the addon.
+func (r *AddonReconciler) values(ctx context.Context,
+	addon *kommodityv1alpha1.ClusterAddon) (map[string][]byte, error) {
+	if addon.Spec.ValuesSecretName == "" {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: addon.Namespace, Name: addon.Spec.ValuesSecretName}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values Secret %s: %w", addon.Spec.ValuesSecretName, err)
+	}
+
+	value, found := secret.Data[addonValuesKey]
+	if !found {
+		return nil, fmt.Errorf("%s %w: %s", addonValuesKey, ErrValueNotFoundInSecret, secret.Name)
+	}
+
+	return map[string][]byte{addonValuesKey: value}, nil
+}
+
+// reachableCluster returns a client for the workload cluster of the addon once it answers.
+func (r *AddonReconciler) reachableCluster(ctx context.Context,
+	addon *kommodityv1alpha1.ClusterAddon) (client.Client, error) {
+	cluster := &clusterv1.Cluster{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: addon.Namespace, Name: addon.Spec.ClusterName}, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Cluster %s: %w", addon.Spec.ClusterName, err)
+	}
+
+	kubeClient, err := r.downstreamClient(ctx, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = CheckClusterReady(ctx, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubeClient, nil
+}
+
+func (r *AddonReconciler) patchStatus(ctx context.Context, addon, original *kommodityv1alpha1.ClusterAddon) error {
+	err := r.Status().Patch(ctx, addon, client.MergeFrom(original))
+	if err != nil {
+		return fmt.Errorf("failed to update status of ClusterAddon %s: %w", addon.Name, err)
+	}
+
+	return nil
+}
+
+// addonsForValuesSecret maps a Secret to the ClusterAddons that read their values from it.
+func (r *AddonReconciler) addonsForValuesSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	addons := &kommodityv1alpha1.ClusterAddonList{}
+
+	err := r.List(ctx, addons, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list ClusterAddons", zap.Error(err))
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, addon := range addons.Items {
+		if addon.Spec.ValuesSecretName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&addon)})
+		}
+	}
+
+	return requests
+}
+
+// addonJobConfig returns the Helm install job settings of the addon.
+func addonJobConfig(addon *kommodityv1alpha1.ClusterAddon) Config {
+	releaseName := addon.Spec.ReleaseName
+	if releaseName == "" {
+		releaseName = addon.Spec.Chart.Name
+	}
+
+	version := addon.Spec.Chart.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	jobConfig := NewHelmInstallConfig(releaseName, addon.Spec.Namespace, addon.Spec.Chart.Name, version,
+		addon.Spec.Chart.Repository, false)
+	if jobConfig.Namespace == "" {
+		jobConfig.Namespace = releaseName
+	}
+
+	jobConfig.UpgradeDisabled = addon.Spec.DisableUpgrade
+	jobConfig.RollbackOnFailure = true
+
+	return jobConfig
+}
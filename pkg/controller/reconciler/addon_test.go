@@ -0,0 +1,274 @@
+//nolint:testpackage // white-box tests replace the downstream cluster client
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	testAddonName    = "metrics-server"
+	testAddonSecret  = "metrics-server-values"
+	testAddonJobName = "metrics-server-3-12-2-install"
+)
+
+func buildAddonReconciler(t *testing.T) (*AddonReconciler, client.Client, *record.FakeRecorder) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme, clusterv1.AddToScheme, kommodityv1alpha1.AddToScheme,
+	} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	addon := &kommodityv1alpha1.ClusterAddon{
+		ObjectMeta: metav1.ObjectMeta{Name: testAddonName, Namespace: testPlanNamespace, Generation: 1},
+		Spec: kommodityv1alpha1.ClusterAddonSpec{
+			ClusterName: testPlanCluster,
+			Chart: kommodityv1alpha1.AddonChart{
+				Repository: "https://kubernetes-sigs.github.io/metrics-server/",
+				Name:       "metrics-server",
+				Version:    "3.12.2",
+			},
+			Namespace:        metav1.NamespaceSystem,
+			ValuesSecretName: testAddonSecret,
+		},
+	}
+
+	values := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testAddonSecret, Namespace: testPlanNamespace},
+		Data:       map[string][]byte{addonValuesKey: []byte("replicas: 2\n")},
+	}
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace}}
+
+	kubeSystem := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceSystem}}
+	downstream := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithObjects(kubeSystem).
+		WithStatusSubresource(&batchv1.Job{}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+
+	return &AddonReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(addon, values, cluster).
+			WithStatusSubresource(&kommodityv1alpha1.ClusterAddon{}).
+			Build(),
+		Recorder: recorder,
+		downstreamClient: func(context.Context, string) (client.Client, error) {
+			return downstream, nil
+		},
+	}, downstream, recorder
+}
+
+func reconcileAddon(t *testing.T, reconciler *AddonReconciler) *kommodityv1alpha1.ClusterAddon {
+	t.Helper()
+
+	key := client.ObjectKey{Namespace: testPlanNamespace, Name: testAddonName}
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	addon := &kommodityv1alpha1.ClusterAddon{}
+
+	err = reconciler.Get(t.Context(), key, addon)
+	if err != nil {
+		t.Fatalf("failed to get ClusterAddon: %v", err)
+	}
+
+	return addon
+}
+
+func setAddonJobStatus(t *testing.T, downstream client.Client, status batchv1.JobStatus) {
+	t.Helper()
+
+	job := &batchv1.Job{}
+
+	err := downstream.Get(t.Context(), client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: testAddonJobName}, job)
+	if err != nil {
+		t.Fatalf("expected the install job: %v", err)
+	}
+
+	job.Status = status
+
+	err = downstream.Status().Update(t.Context(), job)
+	if err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+}
+
+func TestAddonInstallSucceeds(t *testing.T) {
+	t.Parallel()
+
+	reconciler, downstream, recorder := buildAddonReconciler(t)
+
+	addon := reconcileAddon(t, reconciler)
+	if addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseInstalling || addon.Status.ValuesHash == "" {
+		t.Fatalf("expected the addon to install with its values, got %+v", addon.Status)
+	}
+
+	expectEvent(t, recorder, "Installing")
+
+	values := &corev1.Secret{}
+
+	err := downstream.Get(t.Context(), client.ObjectKey{
+		Namespace: metav1.NamespaceSystem,
+		Name:      "metrics-server-extra-values",
+	}, values)
+	if err != nil || string(values.Data[addonValuesKey]) != "replicas: 2\n" {
+		t.Fatalf("expected the values in the workload cluster, got %+v: %v", values.Data, err)
+	}
+
+	setAddonJobStatus(t, downstream, batchv1.JobStatus{Succeeded: 1})
+
+	addon = reconcileAddon(t, reconciler)
+	if addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseInstalled || addon.Status.InstalledVersion != "3.12.2" {
+		t.Fatalf("expected the addon to be installed, got %+v", addon.Status)
+	}
+
+	expectEvent(t, recorder, "Installed")
+}
+
+func TestAddonReinstallsOnValuesChange(t *testing.T) {
+	t.Parallel()
+
+	reconciler, downstream, recorder := buildAddonReconciler(t)
+
+	reconcileAddon(t, reconciler)
+	setAddonJobStatus(t, downstream, batchv1.JobStatus{Succeeded: 1})
+	installed := reconcileAddon(t, reconciler)
+
+	if addon := reconcileAddon(t, reconciler); addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseInstalled {
+		t.Fatalf("expected an installed addon to stay installed, got %+v", addon.Status)
+	}
+
+	values := &corev1.Secret{}
+
+	err := reconciler.Get(t.Context(), client.ObjectKey{Namespace: testPlanNamespace, Name: testAddonSecret}, values)
+	if err != nil {
+		t.Fatalf("failed to get values: %v", err)
+	}
+
+	values.Data[addonValuesKey] = []byte("replicas: 3\n")
+
+	err = reconciler.Update(t.Context(), values)
+	if err != nil {
+		t.Fatalf("failed to update values: %v", err)
+	}
+
+	addon := reconcileAddon(t, reconciler)
+	if addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseInstalling ||
+		addon.Status.ValuesHash == installed.Status.ValuesHash {
+		t.Fatalf("expected new values to install the addon again, got %+v", addon.Status)
+	}
+
+	job := &batchv1.Job{}
+
+	err = downstream.Get(t.Context(), client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: testAddonJobName}, job)
+	if err != nil || job.Status.Succeeded != 0 {
+		t.Fatalf("expected a new install job, got %+v: %v", job.Status, err)
+	}
+
+	expectEvent(t, recorder, "Installing")
+}
+
+func TestAddonInstallFails(t *testing.T) {
+	t.Parallel()
+
+	reconciler, downstream, recorder := buildAddonReconciler(t)
+
+	reconcileAddon(t, reconciler)
+	expectEvent(t, recorder, "Installing")
+
+	setAddonJobStatus(t, downstream, batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+	}})
+
+	addon := reconcileAddon(t, reconciler)
+	if addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseFailed || addon.Status.Message == "" {
+		t.Fatalf("expected the addon to fail, got %+v", addon.Status)
+	}
+
+	expectEvent(t, recorder, "InstallFailed")
+}
+
+func TestAddonUninstallsOnDelete(t *testing.T) {
+	t.Parallel()
+
+	reconciler, downstream, recorder := buildAddonReconciler(t)
+
+	addon := reconcileAddon(t, reconciler)
+	if !controllerutil.ContainsFinalizer(addon, FinalizerClusterAddon) {
+		t.Fatalf("expected the finalizer on the addon, got %v", addon.Finalizers)
+	}
+
+	setAddonJobStatus(t, downstream, batchv1.JobStatus{Succeeded: 1})
+	reconcileAddon(t, reconciler)
+	expectEvent(t, recorder, "Installing")
+	expectEvent(t, recorder, "Installed")
+
+	err := reconciler.Delete(t.Context(), addon)
+	if err != nil {
+		t.Fatalf("failed to delete addon: %v", err)
+	}
+
+	addon = reconcileAddon(t, reconciler)
+	if addon.Status.Phase != kommodityv1alpha1.ClusterAddonPhaseUninstalling {
+		t.Fatalf("expected the addon to uninstall, got %+v", addon.Status)
+	}
+
+	job := &batchv1.Job{}
+	key := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "metrics-server-3-12-2-uninstall"}
+
+	err = downstream.Get(t.Context(), key, job)
+	if err != nil {
+		t.Fatalf("expected the uninstall job: %v", err)
+	}
+
+	job.Status = batchv1.JobStatus{Succeeded: 1}
+
+	err = downstream.Status().Update(t.Context(), job)
+	if err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+
+	_, err = reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(addon)})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	expectEvent(t, recorder, "Uninstalled")
+
+	err = reconciler.Get(t.Context(), client.ObjectKeyFromObject(addon), addon)
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the addon to be gone after the uninstall, got %v", err)
+	}
+
+	err = downstream.Get(t.Context(), client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: testAddonJobName}, job)
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the install job to be removed, got %v", err)
+	}
+}
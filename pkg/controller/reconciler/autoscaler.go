@@ -53,10 +53,27 @@ type AutoscalerJob struct {
 // Kubeconfig holds the configuration for accessing the Kommodity cluster from downstream clusters.
 type Kubeconfig struct {
 	BaseURL   string
+	User      string
 	Token     string
 	Namespace string
 }
 
+// Render renders the kubeconfig.
+func (k *Kubeconfig) Render() ([]byte, error) {
+	tpl := template.Must(template.New("kubeconfig.tmpl").
+		Funcs(sprig.FuncMap()).
+		ParseFS(kubeconfigTmplFS, "kubeconfig.tmpl"))
+
+	var buf bytes.Buffer
+
+	err := tpl.Execute(&buf, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kubeconfig template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
 const (
 	// AutoscalerConfigMapSuffix is the suffix for the ConfigMap that triggers the Autoscaler installation.
 	AutoscalerConfigMapSuffix = "-cluster-autoscaler-config"
@@ -115,21 +132,14 @@ func (a *AutoscalerJob) PrepareForApply(ctx context.Context, cfg *config.Kommodi
 		return fmt.Errorf("%w namespace: %s", ErrValueNotFoundInSecret, autoscalerSecret.Name)
 	}
 
-	kubeconfig := &Kubeconfig{
+	kubeconfig, err := (&Kubeconfig{
 		BaseURL:   cfg.BaseURL,
+		User:      autoscalerDeploymentName,
 		Token:     string(token),
 		Namespace: string(namespace),
-	}
-
-	tpl := template.Must(template.New("kubeconfig.tmpl").
-		Funcs(sprig.FuncMap()).
-		ParseFS(kubeconfigTmplFS, "kubeconfig.tmpl"))
-
-	var buf bytes.Buffer
-
-	err = tpl.Execute(&buf, kubeconfig)
+	}).Render()
 	if err != nil {
-		return fmt.Errorf("failed to render kubeconfig template: %w", err)
+		return err
 	}
 
 	autoscalerKubeconfig := &corev1.Secret{
@@ -138,7 +148,7 @@ func (a *AutoscalerJob) PrepareForApply(ctx context.Context, cfg *config.Kommodi
 			Namespace: "kube-system",
 		},
 		Data: map[string][]byte{
-			"value": buf.Bytes(),
+			"value": kubeconfig,
 		},
 		Type: corev1.SecretTypeOpaque,
 	}
@@ -323,7 +333,7 @@ func (r *AutoscalerReconciler) SetupWithManager(ctx context.Context,
 
 	builder := ctrl.NewControllerManagedBy(mgr).
 		Named(autoscalerControllerName).
-		For(&corev1.ConfigMap{}, ctrlbuilder.WithPredicates(configMapPredicate)).
+		For(&corev1.ConfigMap{}, ctrlbuilder.OnlyMetadata, ctrlbuilder.WithPredicates(configMapPredicate)).
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.configMapForAutoscalerTokenSecret),
@@ -360,6 +370,19 @@ func (r *AutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("clusterName %w: %s", ErrValueNotFoundInConfigMap, req.String())
 	}
 
+	paused, err := skipPausedCluster(ctx, r, logger,
+		client.ObjectKey{Namespace: ccmConfigMap.Namespace, Name: clusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	logger.Warn("Installing charts from an Autoscaler ConfigMap is deprecated, use a ClusterAddon instead",
+		zap.String("configmap", req.String()))
+
 	result, err := r.installAutoscaler(ctx, clusterName, ccmConfigMap.Data)
 	if err != nil {
 		logger.Error("Failed to install Autoscaler", zap.String("clusterName", clusterName), zap.Error(err))
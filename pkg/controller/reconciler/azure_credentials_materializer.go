@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -197,6 +198,14 @@ func (r *AzureCredentialMaterializer) resolveIdentityCredentials(
 		return nil, false, fmt.Errorf("failed to get AzureClusterIdentity %s: %w", identityKey, err)
 	}
 
+	// An identity in another namespace must allow the cluster's namespace, otherwise a
+	// tenant could materialize the service principal of another tenant.
+	if identity.Namespace != cluster.Namespace &&
+		!scope.IsClusterNamespaceAllowed(ctx, r.Client, identity.Spec.AllowedNamespaces, cluster.Namespace) {
+		return nil, false, fmt.Errorf("%w: %s does not allow namespace %s",
+			ErrIdentityNamespaceNotAllowed, identityKey, cluster.Namespace)
+	}
+
 	if identity.Spec.Type != infrav1.ServicePrincipal {
 		logger.Info("AzureClusterIdentity is not a ServicePrincipal; leaving credentials to the operator",
 			zap.String("identity", identityKey.String()),
@@ -55,11 +55,19 @@ type materializerFixture struct {
 	withClientSecret  bool
 	ccmEnabled        bool
 	preExistingSecret *corev1.Secret
+	// identityNamespace places the identity in another namespace than the cluster.
+	identityNamespace string
+	allowedNamespaces *infrav1.AllowedNamespaces
 }
 
 func buildMaterializer(t *testing.T, fixture materializerFixture) *AzureCredentialMaterializer {
 	t.Helper()
 
+	identityNamespace := fixture.identityNamespace
+	if identityNamespace == "" {
+		identityNamespace = testMatNamespace
+	}
+
 	objs := []client.Object{
 		&infrav1.AzureCluster{
 			ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testMatNamespace},
@@ -68,7 +76,7 @@ func buildMaterializer(t *testing.T, fixture materializerFixture) *AzureCredenti
 					SubscriptionID: testSubscription,
 					Location:       testLocation,
 					IdentityRef: &corev1.ObjectReference{
-						Kind: "AzureClusterIdentity", Name: "aci", Namespace: testMatNamespace,
+						Kind: "AzureClusterIdentity", Name: "aci", Namespace: identityNamespace,
 					},
 				},
 				ResourceGroup: testRG,
@@ -78,12 +86,13 @@ func buildMaterializer(t *testing.T, fixture materializerFixture) *AzureCredenti
 			},
 		},
 		&infrav1.AzureClusterIdentity{
-			ObjectMeta: metav1.ObjectMeta{Name: "aci", Namespace: testMatNamespace},
+			ObjectMeta: metav1.ObjectMeta{Name: "aci", Namespace: identityNamespace},
 			Spec: infrav1.AzureClusterIdentitySpec{
-				Type:         fixture.identityType,
-				TenantID:     testTenant,
-				ClientID:     testClientID,
-				ClientSecret: corev1.SecretReference{Name: testIdentitySecret, Namespace: testMatNamespace},
+				Type:              fixture.identityType,
+				AllowedNamespaces: fixture.allowedNamespaces,
+				TenantID:          testTenant,
+				ClientID:          testClientID,
+				ClientSecret:      corev1.SecretReference{Name: testIdentitySecret, Namespace: testMatNamespace},
 			},
 		},
 	}
@@ -290,6 +299,28 @@ func TestMaterializeRefusesSecretOwnedByAnotherCluster(t *testing.T) {
 	}
 }
 
+func TestMaterializeRefusesIdentityOfAnotherNamespace(t *testing.T) {
+	t.Parallel()
+
+	materializer := buildMaterializer(t, materializerFixture{
+		identityType: infrav1.ServicePrincipal, withClientSecret: true, identityNamespace: "team-b",
+	})
+
+	_, err := materializer.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: testMatNamespace, Name: testClusterName},
+	})
+	if !errors.Is(err, ErrIdentityNamespaceNotAllowed) {
+		t.Fatalf("Reconcile error = %v, want ErrIdentityNamespaceNotAllowed", err)
+	}
+
+	materializer = buildMaterializer(t, materializerFixture{
+		identityType: infrav1.ServicePrincipal, withClientSecret: true, identityNamespace: "team-b",
+		allowedNamespaces: &infrav1.AllowedNamespaces{NamespaceList: []string{testMatNamespace}},
+	})
+	reconcileCluster(t, materializer)
+	getSecret(t, materializer, testClusterName+asoSecretSuffix)
+}
+
 // TestMaterializePopulatesEmptyPlaceholderSecret verifies the escape hatch is gated
 // on the Secret carrying data: an empty, unlabeled placeholder Secret is not treated
 // as operator-supplied and gets populated (rather than left empty forever).
@@ -9,6 +9,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,7 +33,8 @@ type azureCredentials struct {
 
 // credentialProvider resolves and caches Azure credentials from the Kubernetes
 // Secret referenced by a resource's "serviceoperator.azure.com/credential-from"
-// annotation (falling back to a configured default Secret name).
+// annotation, falling back to the azure ProviderCredential of the resource's namespace
+// and then to a configured default Secret name.
 type credentialProvider struct {
 	client            client.Client
 	defaultSecretName string
@@ -60,14 +62,18 @@ func (p *credentialProvider) resolve(
 	ctx context.Context,
 	obj genruntime.ARMMetaObject,
 ) (*azureCredentials, error) {
-	secretRef := p.secretRefForObject(obj)
+	secretRef, err := p.secretRef(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
 	if secretRef.Name == "" {
 		return nil, fmt.Errorf("%w: no credential-from annotation or default secret", ErrCredentialSecretNotFound)
 	}
 
 	var secret corev1.Secret
 
-	err := p.client.Get(ctx, secretRef, &secret)
+	err = p.client.Get(ctx, secretRef, &secret)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil, fmt.Errorf("%w: %s", ErrCredentialSecretNotFound, secretRef)
@@ -94,6 +100,33 @@ func (p *credentialProvider) resolve(
 	return creds, nil
 }
 
+// secretRef resolves the Secret reference for a resource. Resources without a
+// credential-from annotation use the Secret of the azure ProviderCredential of their
+// namespace, so that tenants do not share the default Secret.
+func (p *credentialProvider) secretRef(
+	ctx context.Context,
+	obj genruntime.ARMMetaObject,
+) (types.NamespacedName, error) {
+	if obj.GetAnnotations()[annotations.PerResourceSecret] != "" {
+		return p.secretRefForObject(obj), nil
+	}
+
+	var credentials kommodityv1alpha1.ProviderCredentialList
+
+	err := p.client.List(ctx, &credentials, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return types.NamespacedName{}, fmt.Errorf("listing provider credentials of %s: %w", obj.GetNamespace(), err)
+	}
+
+	for _, credential := range credentials.Items {
+		if credential.Spec.Provider == kommodityv1alpha1.ProviderCredentialAzure {
+			return types.NamespacedName{Namespace: obj.GetNamespace(), Name: credential.Spec.SecretName}, nil
+		}
+	}
+
+	return p.secretRefForObject(obj), nil
+}
+
 // secretRefForObject resolves the Secret reference for a resource. The annotation
 // value may be "name" (resolved in the resource's namespace) or "namespace/name".
 func (p *credentialProvider) secretRefForObject(obj genruntime.ARMMetaObject) types.NamespacedName {
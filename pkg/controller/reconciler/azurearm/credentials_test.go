@@ -6,9 +6,11 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const (
@@ -71,6 +73,58 @@ func TestSecretRefForObject(t *testing.T) {
 	}
 }
 
+func TestSecretRefUsesNamespaceProviderCredential(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestScheme(t)
+
+	err := kommodityv1alpha1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding kommodity scheme: %v", err)
+	}
+
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&kommodityv1alpha1.ProviderCredential{
+				ObjectMeta: metav1.ObjectMeta{Name: "scaleway", Namespace: testNamespace},
+				Spec:       kommodityv1alpha1.ProviderCredentialSpec{Provider: "scaleway", SecretName: "scw"},
+			},
+			&kommodityv1alpha1.ProviderCredential{
+				ObjectMeta: metav1.ObjectMeta{Name: "azure", Namespace: testNamespace},
+				Spec:       kommodityv1alpha1.ProviderCredentialSpec{Provider: "azure", SecretName: testSecretName},
+			},
+		).
+		Build()
+
+	provider := newCredentialProvider(kubeClient, "default-secret")
+
+	resourceGroup := newResourceGroup("rg")
+	resourceGroup.SetNamespace(testNamespace)
+
+	got, err := provider.secretRef(t.Context(), resourceGroup)
+	if err != nil {
+		t.Fatalf("secretRef returned error: %v", err)
+	}
+
+	want := types.NamespacedName{Namespace: testNamespace, Name: testSecretName}
+	if got != want {
+		t.Fatalf("secretRef = %v, want %v", got, want)
+	}
+
+	resourceGroup.SetNamespace("team-b")
+
+	got, err = provider.secretRef(t.Context(), resourceGroup)
+	if err != nil {
+		t.Fatalf("secretRef returned error: %v", err)
+	}
+
+	want = types.NamespacedName{Namespace: "team-b", Name: "default-secret"}
+	if got != want {
+		t.Fatalf("secretRef = %v, want %v", got, want)
+	}
+}
+
 func TestBuildCredentials(t *testing.T) {
 	t.Parallel()
 
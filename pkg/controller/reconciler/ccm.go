@@ -0,0 +1,424 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-logr/zapr"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// CCMConfigMapSuffix is the suffix for the ConfigMap that triggers the cloud controller
+	// manager installation.
+	CCMConfigMapSuffix = "-cloud-controller-manager-config"
+
+	// AnnotationCCMAppliedHash is stamped on the CCM ConfigMap with the hash of the chart
+	// settings, extra values and kubeconfig last installed, so that unchanged settings are
+	// not installed again.
+	AnnotationCCMAppliedHash = "kommodity.io/ccm-applied-hash"
+
+	// ccmSATokenSecretSuffix is appended to the Cluster name to derive the Secret of type
+	// kubernetes.io/service-account-token whose token feeds the CCM kubeconfig.
+	ccmSATokenSecretSuffix = "-cloud-controller-manager" //nolint:gosec // secret-name suffix, not a credential
+
+	// ccmExtraValuesSecretSuffix is appended to the Cluster name to derive the Secret holding
+	// the extra Helm values of the chart.
+	ccmExtraValuesSecretSuffix = "-cloud-controller-manager-extra-values" //nolint:gosec // secret-name suffix
+
+	// ccmKubeconfigSecretName is the workload-cluster Secret in kube-system holding the
+	// rendered kubeconfig under the `value` key.
+	ccmKubeconfigSecretName = "kommodity-cloud-controller-manager-kubeconfig" //nolint:gosec // secret name
+
+	// ccmKubeconfigUser is the user name in the rendered kubeconfig.
+	ccmKubeconfigUser = "cloud-controller-manager"
+
+	// ccmControllerName is the controller name used to scope the filter label and the
+	// controller registration.
+	ccmControllerName = "kommodity-ccm-controller"
+)
+
+// CCMJobConfig holds the Helm chart settings of a cloud controller manager installation.
+type CCMJobConfig struct {
+	Name            string
+	Namespace       string
+	ChartName       string
+	ChartVersion    string
+	ChartRepository string
+	// Kubeconfig asks for a kubeconfig to the Kommodity API to be delivered to the
+	// workload cluster, for CCMs that manage their nodes through CAPI objects.
+	Kubeconfig bool
+}
+
+// CCMJob installs a cloud controller manager Helm chart into a downstream cluster.
+type CCMJob struct {
+	client.Client
+
+	downstreamClient client.Client
+	config           CCMJobConfig
+	hasExtraValues   bool
+}
+
+// Prepare delivers the kubeconfig and the extra values of the chart to the downstream
+// cluster. It returns a hash of everything the installation depends on.
+func (c *CCMJob) Prepare(ctx context.Context, cfg *config.KommodityConfig,
+	clusterName, namespace string) (string, error) {
+	hash := sha256.New()
+
+	for _, value := range []string{
+		c.config.Name, c.config.Namespace, c.config.ChartName, c.config.ChartVersion, c.config.ChartRepository,
+	} {
+		hash.Write([]byte(value + "\x00"))
+	}
+
+	if c.config.Kubeconfig {
+		token, err := c.applyKubeconfig(ctx, cfg, clusterName, namespace)
+		if err != nil {
+			return "", err
+		}
+
+		hash.Write(token)
+	}
+
+	values, err := c.applyExtraValues(ctx, clusterName, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		hash.Write([]byte(key + "\x00"))
+		hash.Write(values[key])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Apply runs the Helm install job in the downstream cluster. A finished job of an earlier
+// installation is removed first, so that changed settings are installed again.
+func (c *CCMJob) Apply(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Applying cloud controller manager Job", zap.String("jobName", c.config.Name))
+
+	jobConfig := NewHelmInstallConfig(
+		c.config.Name,
+		c.config.Namespace,
+		c.config.ChartName,
+		c.config.ChartVersion,
+		c.config.ChartRepository,
+		c.hasExtraValues,
+	)
+	// Settings changed since the last installation, so an existing release is upgraded.
+	jobConfig.UpgradeDisabled = false
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobConfig.getFullName() + "-install",
+			Namespace: c.config.Namespace,
+		},
+	}
+
+	err := c.downstreamClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete previous cloud controller manager Job: %w", err)
+	}
+
+	err = jobConfig.ApplyTemplate(ctx, c.downstreamClient)
+	if err != nil {
+		return fmt.Errorf("failed to apply cloud controller manager Helm install job: %w", err)
+	}
+
+	logger.Info("Successfully applied cloud controller manager Job", zap.String("jobName", c.config.Name))
+
+	return nil
+}
+
+// applyKubeconfig renders a kubeconfig from the service account token of the cluster
+// and applies it to the downstream cluster. It returns the token.
+func (c *CCMJob) applyKubeconfig(ctx context.Context, cfg *config.KommodityConfig,
+	clusterName, namespace string) ([]byte, error) {
+	var tokenSecret corev1.Secret
+
+	err := c.Get(ctx, client.ObjectKey{Name: clusterName + ccmSATokenSecretSuffix, Namespace: namespace},
+		&tokenSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloud controller manager token secret: %w", err)
+	}
+
+	token := tokenSecret.Data[corev1.ServiceAccountTokenKey]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTokenNotPopulated, tokenSecret.Name)
+	}
+
+	kubeconfig, err := (&Kubeconfig{
+		BaseURL:   cfg.BaseURL,
+		User:      ccmKubeconfigUser,
+		Token:     string(token),
+		Namespace: namespace,
+	}).Render()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ApplySecretToClient(ctx, c.downstreamClient, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ccmKubeconfigSecretName,
+			Namespace: downstreamCCMSecretNamespace,
+		},
+		Data: map[string][]byte{"value": kubeconfig},
+		Type: corev1.SecretTypeOpaque,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply cloud controller manager kubeconfig to downstream cluster: %w", err)
+	}
+
+	return token, nil
+}
+
+// applyExtraValues copies the extra values Secret of the cluster, if any, to the
+// namespace of the chart in the downstream cluster and returns its data.
+func (c *CCMJob) applyExtraValues(ctx context.Context, clusterName, namespace string) (map[string][]byte, error) {
+	var valuesSecret corev1.Secret
+
+	err := c.Get(ctx, client.ObjectKey{Name: clusterName + ccmExtraValuesSecretSuffix, Namespace: namespace},
+		&valuesSecret)
+	if apierrors.IsNotFound(err) {
+		c.hasExtraValues = false
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get extra values secret: %w", err)
+	}
+
+	c.hasExtraValues = true
+
+	err = ApplySecretToClient(ctx, c.downstreamClient, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.config.Name + "-extra-values",
+			Namespace: c.config.Namespace,
+		},
+		Data: valuesSecret.Data,
+		Type: corev1.SecretTypeOpaque,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply cloud controller manager values to downstream cluster: %w", err)
+	}
+
+	return valuesSecret.Data, nil
+}
+
+// CCMReconciler installs a cloud controller manager Helm chart into workload clusters.
+// Like the AutoscalerReconciler it is driven by a labelled ConfigMap per cluster, but it
+// upgrades the release whenever the chart settings, extra values or token change.
+type CCMReconciler struct {
+	client.Client
+
+	cfg *config.KommodityConfig
+}
+
+// SetupWithManager sets up the reconciler with the provided manager. Besides the CCM
+// ConfigMap it watches the token and extra values Secrets of the cluster.
+func (r *CCMReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	configMapPredicate := predicates.ResourceNotPausedAndHasFilterLabel(
+		mgr.GetScheme(),
+		zapr.NewLogger(logging.FromContext(ctx)),
+		ccmControllerName,
+	)
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(ccmControllerName).
+		For(&corev1.ConfigMap{}, ctrlbuilder.OnlyMetadata, ctrlbuilder.WithPredicates(configMapPredicate)).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.configMapForCCMSecret),
+			ctrlbuilder.WithPredicates(predicate.NewPredicateFuncs(isCCMSecret)),
+		).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up CCM controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile reconciles cloud controller manager ConfigMaps.
+func (r *CCMReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx)
+	logger.Info("Reconciling cloud controller manager for ConfigMap", zap.String("configmap", req.String()))
+
+	configMap := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, req.NamespacedName, configMap)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	clusterName, success := configMap.Labels[clusterNameLabel]
+	if !success {
+		return ctrl.Result{}, fmt.Errorf("clusterName %w: %s", ErrValueNotFoundInConfigMap, req.String())
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger, client.ObjectKey{Namespace: configMap.Namespace, Name: clusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	result, err := r.installCCM(ctx, clusterName, configMap)
+	if err != nil {
+		logger.Error("Failed to install cloud controller manager",
+			zap.String("clusterName", clusterName), zap.Error(err))
+
+		return result, fmt.Errorf("failed to install cloud controller manager for cluster %s: %w", clusterName, err)
+	}
+
+	return result, nil
+}
+
+//nolint:funlen,cyclop // Reads the ConfigMap, waits for the downstream cluster, and installs on changes only.
+func (r *CCMReconciler) installCCM(ctx context.Context, clusterName string,
+	configMap *corev1.ConfigMap) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("clusterName", clusterName))
+
+	jobConfig := CCMJobConfig{
+		Namespace:       configMap.Data["namespace"],
+		ChartName:       configMap.Data["name"],
+		ChartVersion:    configMap.Data["version"],
+		ChartRepository: configMap.Data["repository"],
+		Kubeconfig:      configMap.Data["kubeconfig"] == "true",
+	}
+
+	for _, key := range []string{"namespace", "name", "repository"} {
+		if configMap.Data[key] == "" {
+			return ctrl.Result{}, fmt.Errorf("%s %w: cluster %s", key, ErrValueNotFoundInConfigMap, clusterName)
+		}
+	}
+
+	jobConfig.Name = jobConfig.ChartName
+	if jobConfig.ChartVersion == "" {
+		jobConfig.ChartVersion = "latest"
+	}
+
+	kubeClient, err := (&DownstreamClientConfig{
+		Client:      r.Client,
+		ClusterName: clusterName,
+	}).FetchDownstreamKubernetesClient(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Cluster kubeconfig not ready yet, requeuing", zap.Duration("requeueAfter", RequeueAfter))
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to fetch kubeconfig from secret: %w", err)
+	}
+
+	err = CheckClusterReady(ctx, kubeClient)
+	if err != nil {
+		logger.Info("Downstream cluster not ready yet, requeuing", zap.Duration("requeueAfter", RequeueAfter))
+
+		//nolint:nilerr // intentionally return nil to avoid exponential backoff
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	ccmJob := &CCMJob{
+		Client:           r.Client,
+		downstreamClient: kubeClient,
+		config:           jobConfig,
+	}
+
+	hash, err := ccmJob.Prepare(ctx, r.cfg, clusterName, configMap.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) || errors.Is(err, ErrTokenNotPopulated) {
+			logger.Info("Cloud controller manager token secret not ready yet, requeuing",
+				zap.Duration("requeueAfter", RequeueAfter))
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to prepare cloud controller manager Job: %w", err)
+	}
+
+	if configMap.Annotations[AnnotationCCMAppliedHash] == hash {
+		logger.Info("Cloud controller manager settings unchanged, skipping installation")
+
+		return ctrl.Result{}, nil
+	}
+
+	err = ccmJob.Apply(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply cloud controller manager Job: %w", err)
+	}
+
+	original := configMap.DeepCopy()
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+
+	configMap.Annotations[AnnotationCCMAppliedHash] = hash
+
+	err = r.Patch(ctx, configMap, client.MergeFrom(original))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to record applied hash on ConfigMap %s: %w", configMap.Name, err)
+	}
+
+	logger.Info("Successfully installed cloud controller manager")
+
+	return ctrl.Result{}, nil
+}
+
+// configMapForCCMSecret maps a token or extra values Secret to the CCM ConfigMap of the
+// same Cluster, if that ConfigMap carries the watch-filter label of this controller.
+func (r *CCMReconciler) configMapForCCMSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	configMapKey := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetLabels()[clusterNameLabel] + CCMConfigMapSuffix,
+	}
+
+	configMap := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, configMapKey, configMap)
+	if err != nil || configMap.Labels[clusterv1.WatchLabel] != ccmControllerName {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: configMapKey}}
+}
+
+// isCCMSecret reports whether the object is the token or extra values Secret of a cluster.
+func isCCMSecret(obj client.Object) bool {
+	clusterName, success := obj.GetLabels()[clusterNameLabel]
+	if !success {
+		return false
+	}
+
+	return obj.GetName() == clusterName+ccmSATokenSecretSuffix ||
+		obj.GetName() == clusterName+ccmExtraValuesSecretSuffix
+}
@@ -0,0 +1,136 @@
+//nolint:testpackage // white-box tests drive the CCM job against fake clients
+package reconciler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildCCMJob(t *testing.T, objects ...client.Object) (*CCMJob, client.Client) {
+	t.Helper()
+
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster + ccmSATokenSecretSuffix, Namespace: testPlanNamespace},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("token")},
+	}
+
+	downstream := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	return &CCMJob{
+		Client: fake.NewClientBuilder().
+			WithScheme(clientgoscheme.Scheme).
+			WithObjects(append(objects, tokenSecret)...).
+			Build(),
+		downstreamClient: downstream,
+		config: CCMJobConfig{
+			Name:            "kubevirt-cloud-controller-manager",
+			Namespace:       "kube-system",
+			ChartName:       "kubevirt-cloud-controller-manager",
+			ChartVersion:    "0.5.0",
+			ChartRepository: "oci://registry.example.com/charts",
+			Kubeconfig:      true,
+		},
+	}, downstream
+}
+
+func prepareCCMJob(t *testing.T, job *CCMJob) string {
+	t.Helper()
+
+	hash, err := job.Prepare(t.Context(), &config.KommodityConfig{BaseURL: "https://kommodity.example.com"},
+		testPlanCluster, testPlanNamespace)
+	if err != nil {
+		t.Fatalf("prepare failed: %v", err)
+	}
+
+	return hash
+}
+
+func TestCCMJobPrepareDeliversKubeconfig(t *testing.T) {
+	t.Parallel()
+
+	job, downstream := buildCCMJob(t)
+
+	prepareCCMJob(t, job)
+
+	secret := &corev1.Secret{}
+
+	err := downstream.Get(t.Context(),
+		client.ObjectKey{Name: ccmKubeconfigSecretName, Namespace: downstreamCCMSecretNamespace}, secret)
+	if err != nil {
+		t.Fatalf("expected the kubeconfig in the downstream cluster: %v", err)
+	}
+
+	kubeconfig := string(secret.Data["value"])
+	if !strings.Contains(kubeconfig, "https://kommodity.example.com") ||
+		!strings.Contains(kubeconfig, "user: "+ccmKubeconfigUser) {
+		t.Fatalf("unexpected kubeconfig:\n%s", kubeconfig)
+	}
+}
+
+func TestCCMJobPrepareHashTracksValues(t *testing.T) {
+	t.Parallel()
+
+	values := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster + ccmExtraValuesSecretSuffix, Namespace: testPlanNamespace},
+		Data:       map[string][]byte{"values.yaml": []byte("replicas: 1")},
+	}
+
+	job, _ := buildCCMJob(t, values)
+
+	first := prepareCCMJob(t, job)
+	if first != prepareCCMJob(t, job) {
+		t.Fatalf("expected the hash to be stable for unchanged settings")
+	}
+
+	if !job.hasExtraValues {
+		t.Fatalf("expected the extra values to be picked up")
+	}
+
+	values.Data["values.yaml"] = []byte("replicas: 2")
+
+	err := job.Update(t.Context(), values)
+	if err != nil {
+		t.Fatalf("failed to update values: %v", err)
+	}
+
+	if first == prepareCCMJob(t, job) {
+		t.Fatalf("expected the hash to change with the extra values")
+	}
+}
+
+func TestCCMJobApplyReplacesJob(t *testing.T) {
+	t.Parallel()
+
+	job, downstream := buildCCMJob(t)
+
+	for range 2 {
+		err := job.Apply(t.Context())
+		if err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	}
+
+	jobs := &batchv1.JobList{}
+
+	err := downstream.List(t.Context(), jobs, client.InNamespace("kube-system"))
+	if err != nil {
+		t.Fatalf("failed to list jobs: %v", err)
+	}
+
+	if len(jobs.Items) != 1 || jobs.Items[0].Name != "kubevirt-cloud-controller-manager-0-5-0-install" {
+		t.Fatalf("expected a single install job, got %+v", jobs.Items)
+	}
+
+	if !strings.Contains(jobs.Items[0].Spec.Template.Spec.Containers[0].Command[2], `UPGRADE_DISABLE="false"`) {
+		t.Fatalf("expected the install job to upgrade an existing release")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -29,11 +30,11 @@ type DownstreamClientConfig struct {
 func (c *DownstreamClientConfig) FetchKubeConfigFromSecret(ctx context.Context) ([]byte, error) {
 	kubeConfigSecret := &corev1.Secret{}
 
-	kubeConfigSecretName := c.ClusterName + "-kubeconfig"
+	kubeConfigSecretName := c.ClusterName + kubeConfigSecretSuffix
 
 	err := c.Get(ctx, client.ObjectKey{
 		Name:      kubeConfigSecretName,
-		Namespace: "default",
+		Namespace: kubeConfigSecretNamespace,
 	}, kubeConfigSecret)
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to get kubeconfig secret",
@@ -54,14 +55,20 @@ func (c *DownstreamClientConfig) FetchKubeConfigFromSecret(ctx context.Context)
 	return kubeConfigBytes, nil
 }
 
-// FetchDownstreamKubernetesClient retrieves the kubeconfig from a
-// Kubernetes Secret and creates a client for the downstream cluster.
+// FetchDownstreamKubernetesClient returns a client for the downstream cluster,
+// built from the kubeconfig in its Secret. Clients are shared between
+// reconciles, see downstreamClientCache.
 func (c *DownstreamClientConfig) FetchDownstreamKubernetesClient(ctx context.Context) (client.Client, error) {
-	kubeConfigBytes, err := c.FetchKubeConfigFromSecret(ctx)
+	kubeClient, err := downstreamClients.get(ctx, c.ClusterName, c.FetchKubeConfigFromSecret)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch kubeconfig from secret: %w", err)
+		return nil, fmt.Errorf("failed to fetch downstream client of cluster %s: %w", c.ClusterName, err)
 	}
 
+	return kubeClient, nil
+}
+
+// newClientFromKubeConfig creates a client for the cluster of the kubeconfig.
+func newClientFromKubeConfig(ctx context.Context, kubeConfigBytes []byte) (client.Client, error) {
 	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfigBytes)
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to create REST configuration from kubeconfig", zap.Error(err))
@@ -128,3 +135,22 @@ func ApplySecretToClient(ctx context.Context, kubeClient client.Client, secret *
 
 	return nil
 }
+
+// ensurePrivilegedNamespace creates a namespace in the downstream cluster that admits
+// privileged pods, as node agents like CNI and CSI drivers need. Talos enforces the
+// baseline pod security standard by default. An existing namespace is left alone.
+func ensurePrivilegedNamespace(ctx context.Context, kubeClient client.Client, name string) error {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"},
+		},
+	}
+
+	err := kubeClient.Create(ctx, namespace)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// downstreamClientTTL bounds how long a downstream client is reused, so that
+	// connections and discovery data of a cluster are refreshed now and then.
+	downstreamClientTTL = 10 * time.Minute
+	// downstreamClientProbeInterval is how often a cached client is checked against its
+	// cluster before it is handed out again.
+	downstreamClientProbeInterval = time.Minute
+
+	// kubeConfigSecretSuffix is appended to the Cluster name to derive its kubeconfig Secret.
+	kubeConfigSecretSuffix = "-kubeconfig"
+	// kubeConfigSecretNamespace is the namespace of the kubeconfig Secrets.
+	kubeConfigSecretNamespace = "default"
+
+	// clientCacheControllerName is the name used to register the controller.
+	clientCacheControllerName = "kommodity-client-cache-controller"
+)
+
+// downstreamClients is shared by all reconcilers, which reach the same clusters.
+//
+//nolint:gochecknoglobals // Process-wide cache of downstream clients.
+var downstreamClients = newDownstreamClientCache(newClientFromKubeConfig)
+
+// downstreamClientEntry is a cached client of a downstream cluster.
+type downstreamClientEntry struct {
+	client  client.Client
+	created time.Time
+	probed  time.Time
+}
+
+// downstreamClientCache reuses the clients of downstream clusters across reconciles,
+// so that the kubeconfig Secret is read and the API of the cluster is discovered once.
+// A client is rebuilt when it is older than downstreamClientTTL, when its cluster stops
+// answering, or when the ClientCacheReconciler sees the kubeconfig Secret change.
+type downstreamClientCache struct {
+	mu      sync.Mutex
+	entries map[string]*downstreamClientEntry
+
+	newClient func(ctx context.Context, kubeConfig []byte) (client.Client, error)
+	now       func() time.Time
+}
+
+func newDownstreamClientCache(
+	newClient func(ctx context.Context, kubeConfig []byte) (client.Client, error)) *downstreamClientCache {
+	return &downstreamClientCache{
+		entries:   map[string]*downstreamClientEntry{},
+		newClient: newClient,
+		now:       time.Now,
+	}
+}
+
+// get returns the cached client of the cluster, or builds one from the kubeconfig
+// returned by fetchKubeConfig.
+func (c *downstreamClientCache) get(ctx context.Context, clusterName string,
+	fetchKubeConfig func(ctx context.Context) ([]byte, error)) (client.Client, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	c.pruneLocked(now)
+
+	var entry downstreamClientEntry
+
+	cached, found := c.entries[clusterName]
+	if found {
+		entry = *cached
+	}
+	c.mu.Unlock()
+
+	if found {
+		if now.Sub(entry.probed) < downstreamClientProbeInterval {
+			return entry.client, nil
+		}
+
+		err := CheckClusterReady(ctx, entry.client)
+		if err == nil {
+			c.mu.Lock()
+			cached.probed = now
+			c.mu.Unlock()
+
+			return entry.client, nil
+		}
+
+		logging.FromContext(ctx).Info("Cached downstream client failed its health probe, rebuilding it",
+			zap.String("cluster", clusterName), zap.Error(err))
+		c.invalidate(clusterName)
+	}
+
+	kubeConfig, err := fetchKubeConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.newClient(ctx, kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[clusterName] = &downstreamClientEntry{client: kubeClient, created: now, probed: now}
+	c.mu.Unlock()
+
+	return kubeClient, nil
+}
+
+// invalidate drops the client of the cluster, if any.
+func (c *downstreamClientCache) invalidate(clusterName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, clusterName)
+}
+
+// pruneLocked drops the clients that outlived downstreamClientTTL, including those of
+// clusters that are gone.
+func (c *downstreamClientCache) pruneLocked(now time.Time) {
+	for clusterName, entry := range c.entries {
+		if now.Sub(entry.created) >= downstreamClientTTL {
+			delete(c.entries, clusterName)
+		}
+	}
+}
+
+// ClientCacheReconciler drops the cached downstream client of a cluster whenever its
+// kubeconfig Secret changes or goes away, for example after a CA rotation.
+type ClientCacheReconciler struct {
+	client.Client
+
+	cache *downstreamClientCache
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *ClientCacheReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up downstream client cache reconciler")
+
+	if r.cache == nil {
+		r.cache = downstreamClients
+	}
+
+	isKubeConfigSecret := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == kubeConfigSecretNamespace &&
+			strings.HasSuffix(obj.GetName(), kubeConfigSecretSuffix)
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(clientCacheControllerName).
+		For(&corev1.Secret{}, builder.WithPredicates(isKubeConfigSecret)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up downstream client cache controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile drops the cached client of the cluster of the kubeconfig Secret.
+func (r *ClientCacheReconciler) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.cache.invalidate(strings.TrimSuffix(req.Name, kubeConfigSecretSuffix))
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,119 @@
+//nolint:testpackage // white-box tests drive the unexported client cache
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingClientCache counts the kubeconfig reads and the clients it builds.
+type countingClientCache struct {
+	*downstreamClientCache
+
+	now    time.Time
+	reads  int
+	builds int
+}
+
+func newCountingClientCache(t *testing.T, reachable bool) *countingClientCache {
+	t.Helper()
+
+	builder := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme)
+	if reachable {
+		builder = builder.WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceSystem}})
+	}
+
+	counting := &countingClientCache{now: time.Now()}
+	counting.downstreamClientCache = newDownstreamClientCache(func(context.Context, []byte) (client.Client, error) {
+		counting.builds++
+
+		return builder.Build(), nil
+	})
+	counting.downstreamClientCache.now = func() time.Time { return counting.now }
+
+	return counting
+}
+
+func (c *countingClientCache) fetch(t *testing.T) client.Client {
+	t.Helper()
+
+	kubeClient, err := c.get(t.Context(), testPlanCluster, func(context.Context) ([]byte, error) {
+		c.reads++
+
+		return []byte("kubeconfig"), nil
+	})
+	if err != nil {
+		t.Fatalf("failed to get client: %v", err)
+	}
+
+	return kubeClient
+}
+
+func TestDownstreamClientCacheReusesClients(t *testing.T) {
+	t.Parallel()
+
+	cache := newCountingClientCache(t, true)
+
+	first := cache.fetch(t)
+
+	cache.now = cache.now.Add(2 * downstreamClientProbeInterval)
+
+	if cache.fetch(t) != first || cache.reads != 1 || cache.builds != 1 {
+		t.Fatalf("expected a healthy client to be reused, got %d reads and %d builds", cache.reads, cache.builds)
+	}
+
+	cache.now = cache.now.Add(downstreamClientTTL)
+
+	if cache.fetch(t) == first || cache.reads != 2 {
+		t.Fatalf("expected an expired client to be rebuilt, got %d reads", cache.reads)
+	}
+}
+
+func TestDownstreamClientCacheRebuildsUnhealthyClients(t *testing.T) {
+	t.Parallel()
+
+	cache := newCountingClientCache(t, false)
+
+	first := cache.fetch(t)
+	if cache.fetch(t) != first {
+		t.Fatalf("expected the client to be reused until it is probed")
+	}
+
+	cache.now = cache.now.Add(downstreamClientProbeInterval)
+
+	if cache.fetch(t) == first || cache.builds != 2 {
+		t.Fatalf("expected a client failing its probe to be rebuilt, got %d builds", cache.builds)
+	}
+}
+
+func TestClientCacheReconcilerInvalidatesOnKubeconfigChange(t *testing.T) {
+	t.Parallel()
+
+	cache := newCountingClientCache(t, true)
+	cache.fetch(t)
+
+	reconciler := &ClientCacheReconciler{cache: cache.downstreamClientCache}
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{
+		Namespace: kubeConfigSecretNamespace,
+		Name:      testPlanCluster + kubeConfigSecretSuffix,
+	}})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	cache.fetch(t)
+
+	if cache.reads != 2 {
+		t.Fatalf("expected the kubeconfig to be read again, got %d reads", cache.reads)
+	}
+}
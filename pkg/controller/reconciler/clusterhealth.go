@@ -0,0 +1,430 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// AnnotationClusterHealthInterval overrides how often the checks of a Cluster run,
+	// as a Go duration like "5m".
+	AnnotationClusterHealthInterval = "kommodity.io/cluster-health-interval"
+
+	// clusterHealthControllerName is the name used to register the controller.
+	clusterHealthControllerName = "kommodity-cluster-health-controller"
+
+	// defaultClusterHealthInterval is how often the checks of a cluster run without other
+	// changes when no interval is configured.
+	defaultClusterHealthInterval = time.Minute
+	// leaseCheckTimeout bounds reading the leases of a workload cluster.
+	leaseCheckTimeout = 10 * time.Second
+
+	// Reasons of the conditions of a ClusterHealth.
+	conditionNotReportedReason = "NotReported"
+	nodesHealthyReason         = "NodesHealthy"
+	nodesUnhealthyReason       = "NodesUnhealthy"
+	leasesRenewedReason        = "LeasesRenewed"
+	leasesNotRenewedReason     = "LeasesNotRenewed"
+	clusterNotReachableReason  = "ClusterNotReachable"
+)
+
+// controlPlaneLeases are the leader election leases in kube-system that the controller
+// manager and the scheduler of a healthy control plane keep renewing.
+//
+//nolint:gochecknoglobals // Constant list of lease names.
+var controlPlaneLeases = []string{"kube-controller-manager", "kube-scheduler"}
+
+// ClusterHealthReconciler keeps a ClusterHealth next to every Cluster. It mirrors the
+// readiness conditions of the Cluster, counts the Machines with healthy nodes and checks
+// that the control plane of the workload cluster renews its leader election leases.
+type ClusterHealthReconciler struct {
+	client.Client
+
+	ClusterCache clustercache.ClusterCache
+	// Interval is how often the checks of a cluster run without other changes.
+	Interval time.Duration
+	// Dynamic, if set, overrides Interval with the tunables in effect.
+	Dynamic *config.DynamicConfig
+
+	// workloadClient returns a client for the workload cluster, defaults to one built
+	// from the REST config of the cluster cache.
+	workloadClient func(ctx context.Context, cluster client.ObjectKey) (kubernetes.Interface, error)
+	// etcdProbe asks a control plane node about its etcd member, defaults to the Talos API.
+	etcdProbe func(ctx context.Context, machine *clusterv1.Machine) (etcdMemberHealth, error)
+	// downstreamClient returns a client to re-converge the add-ons of a workload cluster,
+	// defaults to one built from its kubeconfig Secret.
+	downstreamClient func(ctx context.Context, clusterName string) (client.Client, error)
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *ClusterHealthReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up ClusterHealth reconciler")
+
+	if r.workloadClient == nil {
+		r.workloadClient = r.clientFromClusterCache
+	}
+
+	if r.etcdProbe == nil {
+		r.etcdProbe = r.probeEtcdMember
+	}
+
+	if r.downstreamClient == nil {
+		r.downstreamClient = func(ctx context.Context, clusterName string) (client.Client, error) {
+			return (&DownstreamClientConfig{Client: r.Client, ClusterName: clusterName}).
+				FetchDownstreamKubernetesClient(ctx)
+		}
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(clusterHealthControllerName).
+		For(&clusterv1.Cluster{}).
+		Owns(&kommodityv1alpha1.ClusterHealth{}).
+		Watches(&clusterv1.Machine{}, handler.EnqueueRequestsFromMapFunc(clusterForObject)).
+		Watches(&clusterv1.MachineDeployment{}, handler.EnqueueRequestsFromMapFunc(clusterForObject)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up ClusterHealth controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile runs the checks of a single Cluster and records them in its ClusterHealth.
+func (r *ClusterHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cluster := &clusterv1.Cluster{}
+
+	err := r.Get(ctx, req.NamespacedName, cluster)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cluster.DeletionTimestamp.IsZero() {
+		// The ClusterHealth is garbage collected with the Cluster.
+		return ctrl.Result{}, nil
+	}
+
+	health, err := r.getOrCreateClusterHealth(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := health.DeepCopy()
+
+	err = r.updateStatus(ctx, cluster, &health.Status)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.Status().Patch(ctx, health, client.MergeFrom(original))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status of ClusterHealth %s: %w", health.Name, err)
+	}
+
+	return ctrl.Result{RequeueAfter: r.interval(ctx, cluster)}, nil
+}
+
+// interval returns the check interval of the Cluster, taken from its annotation if
+// it holds a valid duration.
+func (r *ClusterHealthReconciler) interval(ctx context.Context, cluster *clusterv1.Cluster) time.Duration {
+	interval := r.Interval
+	if r.Dynamic != nil {
+		interval = r.Dynamic.Current().ClusterHealthInterval
+	}
+
+	if interval <= 0 {
+		interval = defaultClusterHealthInterval
+	}
+
+	value := cluster.Annotations[AnnotationClusterHealthInterval]
+	if value == "" {
+		return interval
+	}
+
+	override, err := time.ParseDuration(value)
+	if err != nil || override <= 0 {
+		logging.FromContext(ctx).Error("Invalid cluster health interval, using the default",
+			zap.String("cluster", cluster.Name),
+			zap.String("annotation", AnnotationClusterHealthInterval),
+			zap.String("value", value))
+
+		return interval
+	}
+
+	return override
+}
+
+func (r *ClusterHealthReconciler) getOrCreateClusterHealth(ctx context.Context,
+	cluster *clusterv1.Cluster) (*kommodityv1alpha1.ClusterHealth, error) {
+	health := &kommodityv1alpha1.ClusterHealth{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), health)
+	if err == nil {
+		return health, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get ClusterHealth %s: %w", cluster.Name, err)
+	}
+
+	health = &kommodityv1alpha1.ClusterHealth{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+		},
+	}
+
+	err = controllerutil.SetControllerReference(cluster, health, r.Scheme())
+	if err != nil {
+		return nil, fmt.Errorf("failed to set owner of ClusterHealth %s: %w", cluster.Name, err)
+	}
+
+	err = r.Create(ctx, health)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ClusterHealth %s: %w", cluster.Name, err)
+	}
+
+	logging.FromContext(ctx).Info("Created ClusterHealth", zap.String("cluster", cluster.Name))
+
+	return health, nil
+}
+
+func (r *ClusterHealthReconciler) updateStatus(ctx context.Context, cluster *clusterv1.Cluster,
+	status *kommodityv1alpha1.ClusterHealthStatus) error {
+	for conditionType, clusterCondition := range map[string]clusterv1.ConditionType{
+		kommodityv1alpha1.ClusterHealthClusterReady:        clusterv1.ReadyCondition,
+		kommodityv1alpha1.ClusterHealthControlPlaneReady:   clusterv1.ControlPlaneReadyCondition,
+		kommodityv1alpha1.ClusterHealthInfrastructureReady: clusterv1.InfrastructureReadyCondition,
+	} {
+		meta.SetStatusCondition(&status.Conditions, mirrorCondition(conditionType, cluster, clusterCondition))
+	}
+
+	machines := &clusterv1.MachineList{}
+
+	err := r.List(ctx, machines,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name})
+	if err != nil {
+		return fmt.Errorf("failed to list Machines of Cluster %s: %w", cluster.Name, err)
+	}
+
+	nodesCondition, controlPlane := countMachines(machines.Items, status)
+
+	err = r.setMachineDeploymentRollouts(ctx, cluster, machines.Items, status)
+	if err != nil {
+		return err
+	}
+
+	meta.SetStatusCondition(&status.Conditions, nodesCondition)
+	meta.SetStatusCondition(&status.Conditions, r.leasesCondition(ctx, cluster))
+	r.setEtcdQuorumCondition(ctx, cluster, controlPlane, status)
+
+	err = r.setAddonsCondition(ctx, cluster, status)
+	if err != nil {
+		return err
+	}
+
+	status.Phase = clusterHealthPhase(status.Conditions)
+	now := metav1.Now()
+	status.LastUpdateTime = &now
+
+	return nil
+}
+
+// countMachines counts the Machines of the cluster and reports whether all their nodes
+// are healthy. It also returns the control plane Machines.
+func countMachines(machines []clusterv1.Machine,
+	status *kommodityv1alpha1.ClusterHealthStatus) (metav1.Condition, []*clusterv1.Machine) {
+	status.ControlPlaneMachines = kommodityv1alpha1.MachineCount{}
+	status.WorkerMachines = kommodityv1alpha1.MachineCount{}
+
+	var (
+		unhealthy    []string
+		controlPlane []*clusterv1.Machine
+	)
+
+	for i := range machines {
+		machine := &machines[i]
+
+		count := &status.WorkerMachines
+		if _, isControlPlane := machine.Labels[clusterv1.MachineControlPlaneLabel]; isControlPlane {
+			count = &status.ControlPlaneMachines
+
+			if machine.DeletionTimestamp.IsZero() {
+				controlPlane = append(controlPlane, machine)
+			}
+		}
+
+		count.Total++
+
+		if conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition) {
+			count.Ready++
+		} else {
+			unhealthy = append(unhealthy, machine.Name)
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		return metav1.Condition{
+			Type:    kommodityv1alpha1.ClusterHealthNodesHealthy,
+			Status:  metav1.ConditionFalse,
+			Reason:  nodesUnhealthyReason,
+			Message: "Machines without a healthy node: " + strings.Join(unhealthy, ", "),
+		}, controlPlane
+	}
+
+	return metav1.Condition{
+		Type:   kommodityv1alpha1.ClusterHealthNodesHealthy,
+		Status: metav1.ConditionTrue,
+		Reason: nodesHealthyReason,
+	}, controlPlane
+}
+
+// leasesCondition checks that the leader election leases of the control plane are renewed.
+func (r *ClusterHealthReconciler) leasesCondition(ctx context.Context, cluster *clusterv1.Cluster) metav1.Condition {
+	condition := metav1.Condition{Type: kommodityv1alpha1.ClusterHealthControlPlaneLeasesHealthy}
+
+	workloadClient, err := r.workloadClient(ctx, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = clusterNotReachableReason
+		condition.Message = err.Error()
+
+		return condition
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, leaseCheckTimeout)
+	defer cancel()
+
+	var stale []string
+
+	for _, name := range controlPlaneLeases {
+		lease, err := workloadClient.CoordinationV1().Leases(metav1.NamespaceSystem).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			stale = append(stale, fmt.Sprintf("%s: %v", name, err))
+
+			continue
+		}
+
+		if !leaseRenewed(lease, time.Now()) {
+			stale = append(stale, name+": not renewed")
+		}
+	}
+
+	if len(stale) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = leasesNotRenewedReason
+		condition.Message = strings.Join(stale, "; ")
+
+		return condition
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = leasesRenewedReason
+
+	return condition
+}
+
+func (r *ClusterHealthReconciler) clientFromClusterCache(ctx context.Context,
+	cluster client.ObjectKey) (kubernetes.Interface, error) {
+	restConfig, err := r.ClusterCache.GetRESTConfig(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get REST config of Cluster %s: %w", cluster.Name, err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for Cluster %s: %w", cluster.Name, err)
+	}
+
+	return clientset, nil
+}
+
+// leaseRenewed reports whether the holder renewed the lease within its duration.
+func leaseRenewed(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+
+	return now.Before(lease.Spec.RenewTime.Add(duration))
+}
+
+// mirrorCondition copies a condition of the Cluster into a ClusterHealth condition.
+func mirrorCondition(conditionType string, cluster *clusterv1.Cluster,
+	clusterCondition clusterv1.ConditionType) metav1.Condition {
+	source := conditions.Get(cluster, clusterCondition)
+	if source == nil {
+		return metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  conditionNotReportedReason,
+			Message: fmt.Sprintf("Cluster does not report %s yet", clusterCondition),
+		}
+	}
+
+	reason := source.Reason
+	if reason == "" {
+		reason = string(clusterCondition)
+	}
+
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionStatus(source.Status),
+		Reason:  reason,
+		Message: source.Message,
+	}
+}
+
+// clusterHealthPhase is Degraded when a check fails, Unknown when a check could not be
+// evaluated and Healthy otherwise.
+func clusterHealthPhase(checks []metav1.Condition) string {
+	phase := kommodityv1alpha1.ClusterHealthPhaseHealthy
+
+	for _, check := range checks {
+		switch check.Status {
+		case metav1.ConditionFalse:
+			return kommodityv1alpha1.ClusterHealthPhaseDegraded
+		case metav1.ConditionUnknown:
+			phase = kommodityv1alpha1.ClusterHealthPhaseUnknown
+		case metav1.ConditionTrue:
+		}
+	}
+
+	return phase
+}
+
+// clusterForObject maps an object labelled with its cluster name to the Cluster.
+func clusterForObject(_ context.Context, obj client.Object) []reconcile.Request {
+	clusterName := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if clusterName == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName}}}
+}
@@ -0,0 +1,375 @@
+package reconciler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AnnotationReconvergeAddons asks for the drifted add-ons of a Cluster to be installed
+	// again with their configuration. It is removed once the install jobs are applied.
+	AnnotationReconvergeAddons = "kommodity.io/reconverge-addons"
+
+	// helmReleaseSecretType is the type of the Secrets Helm stores its releases in.
+	helmReleaseSecretType = "helm.sh/release.v1"
+	// helmReleaseDeployed is the status of a successfully deployed release.
+	helmReleaseDeployed = "deployed"
+
+	addonsInSyncReason  = "AddonsInSync"
+	addonsDriftedReason = "AddonsDrifted"
+)
+
+// gzipMagic starts every gzip stream, Helm compresses releases before storing them.
+//
+//nolint:gochecknoglobals // Constant byte sequence.
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// AddonConfigMapWatchFilters returns the values of the watch label the ConfigMaps that
+// install the add-ons of a cluster carry, one for each of their controllers.
+func AddonConfigMapWatchFilters() []string {
+	return []string{ccmControllerName, csiControllerName, autoscalerControllerName}
+}
+
+// desiredAddon is an add-on of a cluster as its installer is configured.
+type desiredAddon struct {
+	jobConfig Config
+	// values are the extra values of the chart, the install job reads cniValuesKey.
+	values map[string][]byte
+}
+
+// helmRelease holds the fields of a Helm release compared with the configuration.
+type helmRelease struct {
+	Chart struct {
+		Metadata struct {
+			Version string `json:"version"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config map[string]any `json:"config"`
+	Info   struct {
+		Status string `json:"status"`
+	} `json:"info"`
+}
+
+// setAddonsCondition compares the add-ons installed by the CNI, CCM and autoscaler
+// reconcilers with the releases deployed in the workload cluster. Drifted add-ons are
+// installed again when the Cluster asks for it.
+func (r *ClusterHealthReconciler) setAddonsCondition(ctx context.Context, cluster *clusterv1.Cluster,
+	status *kommodityv1alpha1.ClusterHealthStatus) error {
+	addons, err := r.desiredAddons(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	status.Addons = nil
+
+	if len(addons) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, kommodityv1alpha1.ClusterHealthAddonsInSync)
+
+		return nil
+	}
+
+	condition := metav1.Condition{Type: kommodityv1alpha1.ClusterHealthAddonsInSync}
+
+	workloadClient, err := r.workloadClient(ctx, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = clusterNotReachableReason
+		condition.Message = err.Error()
+		meta.SetStatusCondition(&status.Conditions, condition)
+
+		return nil
+	}
+
+	var drifted []desiredAddon
+
+	for _, addon := range addons {
+		sync := compareAddon(ctx, workloadClient, addon)
+		if !sync.InSync {
+			drifted = append(drifted, addon)
+		}
+
+		status.Addons = append(status.Addons, sync)
+	}
+
+	if len(drifted) == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = addonsInSyncReason
+		meta.SetStatusCondition(&status.Conditions, condition)
+
+		return nil
+	}
+
+	names := make([]string, 0, len(drifted))
+	for _, addon := range drifted {
+		names = append(names, addon.jobConfig.Name)
+	}
+
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = addonsDriftedReason
+	condition.Message = "Add-ons drifted from their configuration: " + strings.Join(names, ", ")
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	return r.reconvergeAddons(ctx, cluster, drifted)
+}
+
+// desiredAddons returns the add-ons the installers of the cluster are configured with.
+// The CNI and the CCM are only included once installed.
+func (r *ClusterHealthReconciler) desiredAddons(ctx context.Context,
+	cluster *clusterv1.Cluster) ([]desiredAddon, error) {
+	var addons []desiredAddon
+
+	if cluster.Annotations[AnnotationCNIInstalled] != "" {
+		chart, err := (&CNIReconciler{Client: r.Client}).resolveCNI(ctx, cluster)
+		if err == nil {
+			addon := desiredAddon{
+				jobConfig: NewHelmInstallConfig(chart.Name, chart.Namespace, chart.Name, chart.Version,
+					chart.Repository, false),
+			}
+			addon.jobConfig.HostNetwork = true
+
+			if chart.Values != "" {
+				addon.values = map[string][]byte{cniValuesKey: []byte(chart.Values)}
+			}
+
+			addons = append(addons, addon)
+		} else if !apierrors.IsNotFound(err) && !errors.Is(err, ErrUnknownCNI) {
+			return nil, err
+		}
+	}
+
+	ccm, err := r.configuredAddon(ctx, cluster.Namespace, cluster.Name+CCMConfigMapSuffix,
+		cluster.Namespace, cluster.Name+ccmExtraValuesSecretSuffix, AnnotationCCMAppliedHash)
+	if err != nil {
+		return nil, err
+	}
+
+	autoscaler, err := r.configuredAddon(ctx, cluster.Namespace, cluster.Name+AutoscalerConfigMapSuffix,
+		metav1.NamespaceDefault, cluster.Name+"-cluster-autoscaler-extra-values", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(addons, ccm...), autoscaler...), nil
+}
+
+// configuredAddon reads an add-on from the ConfigMap of its installer and the extra
+// values from the Secret. If installedAnnotation is set, the add-on is only returned
+// once the installer stamped it on the ConfigMap.
+func (r *ClusterHealthReconciler) configuredAddon(ctx context.Context, namespace, configMapName,
+	valuesNamespace, valuesSecretName, installedAnnotation string) ([]desiredAddon, error) {
+	configMap := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: configMapName}, configMap)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %w", configMapName, err)
+	}
+
+	if installedAnnotation != "" && configMap.Annotations[installedAnnotation] == "" {
+		return nil, nil
+	}
+
+	if configMap.Data["namespace"] == "" || configMap.Data["name"] == "" || configMap.Data["repository"] == "" {
+		return nil, nil
+	}
+
+	version := configMap.Data["version"]
+	if version == "" {
+		version = "latest"
+	}
+
+	valuesSecret := &corev1.Secret{}
+
+	err = r.Get(ctx, client.ObjectKey{Namespace: valuesNamespace, Name: valuesSecretName}, valuesSecret)
+	if client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get extra values secret %s: %w", valuesSecretName, err)
+	}
+
+	return []desiredAddon{{
+		jobConfig: NewHelmInstallConfig(configMap.Data["name"], configMap.Data["namespace"],
+			configMap.Data["name"], version, configMap.Data["repository"], false),
+		values: valuesSecret.Data,
+	}}, nil
+}
+
+// compareAddon compares the latest release of the add-on with its configuration.
+func compareAddon(ctx context.Context, workloadClient kubernetes.Interface,
+	addon desiredAddon) kommodityv1alpha1.AddonSync {
+	sync := kommodityv1alpha1.AddonSync{
+		Name:           addon.jobConfig.Name,
+		Namespace:      addon.jobConfig.Namespace,
+		DesiredVersion: addon.jobConfig.Chart.Version,
+	}
+
+	release, err := latestHelmRelease(ctx, workloadClient, addon.jobConfig.Namespace, addon.jobConfig.Name)
+	if err != nil {
+		sync.Message = err.Error()
+
+		return sync
+	}
+
+	sync.DeployedVersion = release.Chart.Metadata.Version
+
+	desiredValues := map[string]any{}
+
+	err = yaml.Unmarshal(addon.values[cniValuesKey], &desiredValues)
+	if err != nil {
+		sync.Message = fmt.Sprintf("invalid values: %v", err)
+
+		return sync
+	}
+
+	switch {
+	case release.Info.Status != helmReleaseDeployed:
+		sync.Message = "release is " + release.Info.Status
+	case sync.DesiredVersion != "latest" && sync.DesiredVersion != sync.DeployedVersion:
+		sync.Message = fmt.Sprintf("chart version %s is deployed instead of %s", sync.DeployedVersion, sync.DesiredVersion)
+	case !valuesEqual(desiredValues, release.Config):
+		sync.Message = "deployed values differ from the configured values"
+	default:
+		sync.InSync = true
+	}
+
+	return sync
+}
+
+// latestHelmRelease decodes the newest revision of the release from the Secrets Helm
+// keeps in the namespace of the release.
+func latestHelmRelease(ctx context.Context, workloadClient kubernetes.Interface,
+	namespace, name string) (*helmRelease, error) {
+	ctx, cancel := context.WithTimeout(ctx, leaseCheckTimeout)
+	defer cancel()
+
+	secrets, err := workloadClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm,name=" + name,
+		FieldSelector: "type=" + helmReleaseSecretType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var (
+		latest   *corev1.Secret
+		revision int
+	)
+
+	for i := range secrets.Items {
+		current, err := strconv.Atoi(secrets.Items[i].Labels["version"])
+		if err == nil && current > revision {
+			latest, revision = &secrets.Items[i], current
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrHelmReleaseNotFound, namespace, name)
+	}
+
+	return decodeHelmRelease(latest.Data["release"])
+}
+
+// decodeHelmRelease reverses the base64 encoding and the compression Helm applies to
+// releases.
+func decodeHelmRelease(data []byte) (*helmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+	}
+
+	release := &helmRelease{}
+
+	err = json.Unmarshal(decoded, release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal release: %w", err)
+	}
+
+	return release, nil
+}
+
+// valuesEqual compares chart values, treating missing and empty values alike.
+func valuesEqual(desired, deployed map[string]any) bool {
+	if len(desired) == 0 && len(deployed) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(desired, deployed)
+}
+
+// reconvergeAddons applies the install jobs of the drifted add-ons again, with upgrades
+// enabled, if the Cluster asks for it and is not paused. The request is then removed.
+func (r *ClusterHealthReconciler) reconvergeAddons(ctx context.Context, cluster *clusterv1.Cluster,
+	drifted []desiredAddon) error {
+	if cluster.Annotations[AnnotationReconvergeAddons] == "" || annotations.IsPaused(cluster, cluster) {
+		return nil
+	}
+
+	logger := logging.FromContext(ctx).With(zap.String("cluster", cluster.Name))
+
+	kubeClient, err := r.downstreamClient(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("failed to fetch downstream client of Cluster %s: %w", cluster.Name, err)
+	}
+
+	for _, addon := range drifted {
+		jobConfig := addon.jobConfig
+		jobConfig.UpgradeDisabled = false
+
+		err = deleteHelmJob(ctx, kubeClient, &jobConfig)
+		if err != nil {
+			return err
+		}
+
+		err = installHelmChart(ctx, kubeClient, &jobConfig, addon.values)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("Re-converging drifted add-on", zap.String("addon", jobConfig.Name))
+	}
+
+	original := cluster.DeepCopy()
+	delete(cluster.Annotations, AnnotationReconvergeAddons)
+
+	err = r.Patch(ctx, cluster, client.MergeFrom(original))
+	if err != nil {
+		return fmt.Errorf("failed to remove %s from Cluster %s: %w", AnnotationReconvergeAddons, cluster.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	machineapi "github.com/siderolabs/talos/pkg/machinery/api/machine"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// AnnotationEtcdHealthCheck enables the etcd quorum check of a Cluster when set to "true".
+	// The check calls the Talos API of every control plane node, so it is opt-in.
+	AnnotationEtcdHealthCheck = "kommodity.io/etcd-health-check"
+
+	// etcdStatusTimeout bounds the etcd status calls to a single node.
+	etcdStatusTimeout = 10 * time.Second
+
+	etcdQuorumHealthyReason = "EtcdQuorumHealthy"
+	etcdQuorumLostReason    = "EtcdQuorumLost"
+	noControlPlaneReason    = "NoControlPlaneMachines"
+)
+
+// etcdMemberHealth is what a control plane node reports about etcd.
+type etcdMemberHealth struct {
+	// voters is the number of voting members in the member list of the node.
+	voters int
+	// learner is true when the member of the node is not a voting member yet.
+	learner bool
+}
+
+// setEtcdQuorumCondition checks the etcd quorum of the cluster if the check is enabled,
+// and removes the condition otherwise.
+func (r *ClusterHealthReconciler) setEtcdQuorumCondition(ctx context.Context, cluster *clusterv1.Cluster,
+	controlPlane []*clusterv1.Machine, status *kommodityv1alpha1.ClusterHealthStatus) {
+	if cluster.Annotations[AnnotationEtcdHealthCheck] != "true" {
+		meta.RemoveStatusCondition(&status.Conditions, kommodityv1alpha1.ClusterHealthEtcdQuorum)
+
+		return
+	}
+
+	meta.SetStatusCondition(&status.Conditions, r.etcdQuorumCondition(ctx, controlPlane))
+}
+
+// etcdQuorumCondition asks every control plane node for the health of its etcd member.
+// The quorum holds when a majority of the voting members is healthy.
+func (r *ClusterHealthReconciler) etcdQuorumCondition(ctx context.Context,
+	controlPlane []*clusterv1.Machine) metav1.Condition {
+	condition := metav1.Condition{Type: kommodityv1alpha1.ClusterHealthEtcdQuorum}
+
+	if len(controlPlane) == 0 {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = noControlPlaneReason
+
+		return condition
+	}
+
+	voters, healthy := 0, 0
+
+	var unhealthy []string
+
+	for _, machine := range controlPlane {
+		member, err := r.etcdProbe(ctx, machine)
+		if err != nil {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s: %v", machine.Name, err))
+
+			continue
+		}
+
+		voters = max(voters, member.voters)
+
+		if !member.learner {
+			healthy++
+		}
+	}
+
+	if voters == 0 {
+		// Without an answer the member list is unknown; count every control plane Machine.
+		voters = len(controlPlane)
+	}
+
+	quorum := voters/2 + 1
+
+	condition.Message = fmt.Sprintf("%d of %d voting members healthy", healthy, voters)
+	if len(unhealthy) > 0 {
+		condition.Message += "; " + strings.Join(unhealthy, "; ")
+	}
+
+	if healthy < quorum {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = etcdQuorumLostReason
+
+		return condition
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = etcdQuorumHealthyReason
+
+	return condition
+}
+
+// probeEtcdMember reads the etcd status and member list from the node of the Machine.
+func (r *ClusterHealthReconciler) probeEtcdMember(ctx context.Context,
+	machine *clusterv1.Machine) (etcdMemberHealth, error) {
+	talosClient, err := TalosClientForMachine(ctx, r, machine)
+	if err != nil {
+		return etcdMemberHealth{}, err
+	}
+
+	defer func() { _ = talosClient.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, etcdStatusTimeout)
+	defer cancel()
+
+	status, err := talosClient.EtcdStatus(ctx)
+	if err != nil {
+		return etcdMemberHealth{}, fmt.Errorf("failed to get etcd status: %w", err)
+	}
+
+	if len(status.GetMessages()) == 0 {
+		return etcdMemberHealth{}, ErrEtcdMemberUnhealthy
+	}
+
+	memberStatus := status.GetMessages()[0].GetMemberStatus()
+	if len(memberStatus.GetErrors()) > 0 {
+		return etcdMemberHealth{}, fmt.Errorf("%w: %s", ErrEtcdMemberUnhealthy,
+			strings.Join(memberStatus.GetErrors(), "; "))
+	}
+
+	members, err := talosClient.EtcdMemberList(ctx, &machineapi.EtcdMemberListRequest{})
+	if err != nil {
+		return etcdMemberHealth{}, fmt.Errorf("failed to list etcd members: %w", err)
+	}
+
+	health := etcdMemberHealth{learner: memberStatus.GetIsLearner()}
+
+	for _, message := range members.GetMessages() {
+		for _, member := range message.GetMembers() {
+			if !member.GetIsLearner() {
+				health.voters++
+			}
+		}
+	}
+
+	return health, nil
+}
@@ -0,0 +1,111 @@
+package reconciler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// machineStuckTimeout is how long a Machine may go without a healthy node before it is
+// reported as stuck.
+const machineStuckTimeout = 15 * time.Minute
+
+// setMachineDeploymentRollouts records the rollout progress of every MachineDeployment
+// of the cluster, so that CI systems can wait for a rollout on a single object.
+func (r *ClusterHealthReconciler) setMachineDeploymentRollouts(ctx context.Context, cluster *clusterv1.Cluster,
+	machines []clusterv1.Machine, status *kommodityv1alpha1.ClusterHealthStatus) error {
+	deployments := &clusterv1.MachineDeploymentList{}
+
+	err := r.List(ctx, deployments,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name})
+	if err != nil {
+		return fmt.Errorf("failed to list MachineDeployments of Cluster %s: %w", cluster.Name, err)
+	}
+
+	status.MachineDeployments = nil
+
+	for i := range deployments.Items {
+		status.MachineDeployments = append(status.MachineDeployments,
+			machineDeploymentRollout(&deployments.Items[i], machines, time.Now()))
+	}
+
+	slices.SortFunc(status.MachineDeployments, func(a, b kommodityv1alpha1.MachineDeploymentRollout) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return nil
+}
+
+// machineDeploymentRollout summarizes the replicas of the MachineDeployment and the
+// Machines that hold up its rollout.
+func machineDeploymentRollout(deployment *clusterv1.MachineDeployment, machines []clusterv1.Machine,
+	now time.Time) kommodityv1alpha1.MachineDeploymentRollout {
+	rollout := kommodityv1alpha1.MachineDeploymentRollout{
+		Name:      deployment.Name,
+		Phase:     deployment.Status.Phase,
+		Updated:   deployment.Status.UpdatedReplicas,
+		Ready:     deployment.Status.ReadyReplicas,
+		Available: deployment.Status.AvailableReplicas,
+	}
+
+	if deployment.Spec.Replicas != nil {
+		rollout.Desired = *deployment.Spec.Replicas
+	}
+
+	for i := range machines {
+		machine := &machines[i]
+		if machine.Labels[clusterv1.MachineDeploymentNameLabel] != deployment.Name {
+			continue
+		}
+
+		if stuck, ok := stuckMachine(machine, now); ok {
+			rollout.StuckMachines = append(rollout.StuckMachines, stuck)
+		}
+	}
+
+	// Old Machines are still counted in the replicas until they are deleted.
+	rollout.RolledOut = rollout.Updated == rollout.Desired &&
+		rollout.Available == rollout.Desired &&
+		deployment.Status.Replicas == rollout.Desired &&
+		len(rollout.StuckMachines) == 0
+
+	return rollout
+}
+
+// stuckMachine reports a Machine that failed, or that has had no healthy node for
+// longer than machineStuckTimeout.
+func stuckMachine(machine *clusterv1.Machine, now time.Time) (kommodityv1alpha1.StuckMachine, bool) {
+	stuck := kommodityv1alpha1.StuckMachine{Name: machine.Name, Phase: machine.Status.Phase}
+
+	if machine.Status.FailureMessage != nil {
+		stuck.Message = *machine.Status.FailureMessage
+
+		return stuck, true
+	}
+
+	if machine.Status.GetTypedPhase() == clusterv1.MachinePhaseFailed {
+		return stuck, true
+	}
+
+	if conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition) ||
+		now.Sub(machine.CreationTimestamp.Time) < machineStuckTimeout {
+		return stuck, false
+	}
+
+	stuck.Message = fmt.Sprintf("no healthy node after %s", machineStuckTimeout)
+
+	if condition := conditions.Get(machine, clusterv1.MachineNodeHealthyCondition); condition != nil &&
+		condition.Message != "" {
+		stuck.Message += ": " + condition.Message
+	}
+
+	return stuck, true
+}
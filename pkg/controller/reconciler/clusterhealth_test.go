@@ -0,0 +1,526 @@
+//nolint:testpackage // white-box tests replace the workload cluster client
+package reconciler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var errClusterNotConnected = errors.New("not connected")
+
+func newLease(name string, renewed time.Time) *coordinationv1.Lease {
+	duration := int32(15)
+	renewTime := metav1.NewMicroTime(renewed)
+
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem},
+		Spec:       coordinationv1.LeaseSpec{LeaseDurationSeconds: &duration, RenewTime: &renewTime},
+	}
+}
+
+func buildClusterHealthReconciler(t *testing.T, workload kubernetes.Interface,
+	machines ...client.Object) *ClusterHealthReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme, clusterv1.AddToScheme, kommodityv1alpha1.AddToScheme,
+	} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace},
+		Status: clusterv1.ClusterStatus{Conditions: clusterv1.Conditions{
+			{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue},
+			{Type: clusterv1.ControlPlaneReadyCondition, Status: corev1.ConditionTrue},
+			{Type: clusterv1.InfrastructureReadyCondition, Status: corev1.ConditionTrue},
+		}},
+	}
+
+	return &ClusterHealthReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(append(machines, cluster)...).
+			WithStatusSubresource(&kommodityv1alpha1.ClusterHealth{}).
+			Build(),
+		workloadClient: func(context.Context, client.ObjectKey) (kubernetes.Interface, error) {
+			if workload == nil {
+				return nil, errClusterNotConnected
+			}
+
+			return workload, nil
+		},
+	}
+}
+
+func reconcileClusterHealth(t *testing.T, reconciler *ClusterHealthReconciler) *kommodityv1alpha1.ClusterHealth {
+	t.Helper()
+
+	key := types.NamespacedName{Namespace: testPlanNamespace, Name: testPlanCluster}
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	health := &kommodityv1alpha1.ClusterHealth{}
+
+	err = reconciler.Get(t.Context(), key, health)
+	if err != nil {
+		t.Fatalf("failed to get ClusterHealth: %v", err)
+	}
+
+	return health
+}
+
+func TestClusterHealthHealthy(t *testing.T) {
+	t.Parallel()
+
+	workload := kubernetesfake.NewClientset(
+		newLease("kube-controller-manager", time.Now()),
+		newLease("kube-scheduler", time.Now()))
+
+	reconciler := buildClusterHealthReconciler(t, workload,
+		newPlanMachine("control-plane", true, nil, true),
+		newPlanMachine("worker", false, nil, true))
+
+	health := reconcileClusterHealth(t, reconciler)
+
+	if health.Status.Phase != kommodityv1alpha1.ClusterHealthPhaseHealthy {
+		t.Fatalf("expected a healthy cluster, got %+v", health.Status)
+	}
+
+	if health.Status.ControlPlaneMachines.Ready != 1 || health.Status.WorkerMachines.Total != 1 {
+		t.Fatalf("unexpected Machine counts %+v", health.Status)
+	}
+
+	if len(health.OwnerReferences) != 1 || health.OwnerReferences[0].Name != testPlanCluster {
+		t.Fatalf("expected the ClusterHealth to be owned by the Cluster, got %+v", health.OwnerReferences)
+	}
+}
+
+func TestClusterHealthDegraded(t *testing.T) {
+	t.Parallel()
+
+	workload := kubernetesfake.NewClientset(
+		newLease("kube-controller-manager", time.Now()),
+		newLease("kube-scheduler", time.Now().Add(-time.Hour)))
+
+	reconciler := buildClusterHealthReconciler(t, workload,
+		newPlanMachine("control-plane", true, nil, true),
+		newPlanMachine("worker", false, nil, false))
+
+	health := reconcileClusterHealth(t, reconciler)
+
+	if health.Status.Phase != kommodityv1alpha1.ClusterHealthPhaseDegraded {
+		t.Fatalf("expected a degraded cluster, got %+v", health.Status)
+	}
+
+	for _, conditionType := range []string{
+		kommodityv1alpha1.ClusterHealthNodesHealthy,
+		kommodityv1alpha1.ClusterHealthControlPlaneLeasesHealthy,
+	} {
+		if !meta.IsStatusConditionFalse(health.Status.Conditions, conditionType) {
+			t.Fatalf("expected %s to be false, got %+v", conditionType, health.Status.Conditions)
+		}
+	}
+}
+
+func TestClusterHealthUnreachable(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildClusterHealthReconciler(t, nil, newPlanMachine("control-plane", true, nil, true))
+
+	health := reconcileClusterHealth(t, reconciler)
+
+	condition := meta.FindStatusCondition(health.Status.Conditions,
+		kommodityv1alpha1.ClusterHealthControlPlaneLeasesHealthy)
+	if health.Status.Phase != kommodityv1alpha1.ClusterHealthPhaseUnknown ||
+		condition == nil || condition.Reason != clusterNotReachableReason {
+		t.Fatalf("expected an unknown lease check, got %+v", health.Status)
+	}
+}
+
+func TestClusterHealthEtcdQuorum(t *testing.T) {
+	t.Parallel()
+
+	controlPlane := []*clusterv1.Machine{
+		newPlanMachine("control-plane-0", true, nil, true),
+		newPlanMachine("control-plane-1", true, nil, true),
+		newPlanMachine("control-plane-2", true, nil, true),
+	}
+
+	tests := map[string]struct {
+		failing        int
+		expectedStatus metav1.ConditionStatus
+	}{
+		"all members healthy":  {failing: 0, expectedStatus: metav1.ConditionTrue},
+		"one member down":      {failing: 1, expectedStatus: metav1.ConditionTrue},
+		"quorum lost":          {failing: 2, expectedStatus: metav1.ConditionFalse},
+		"no member responding": {failing: 3, expectedStatus: metav1.ConditionFalse},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := &ClusterHealthReconciler{
+				etcdProbe: func(_ context.Context, machine *clusterv1.Machine) (etcdMemberHealth, error) {
+					for _, failing := range controlPlane[:test.failing] {
+						if failing.Name == machine.Name {
+							return etcdMemberHealth{}, ErrEtcdMemberUnhealthy
+						}
+					}
+
+					return etcdMemberHealth{voters: len(controlPlane)}, nil
+				},
+			}
+
+			condition := reconciler.etcdQuorumCondition(t.Context(), controlPlane)
+			if condition.Status != test.expectedStatus {
+				t.Fatalf("expected %s, got %+v", test.expectedStatus, condition)
+			}
+		})
+	}
+}
+
+func TestClusterHealthInterval(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		configured time.Duration
+		dynamic    time.Duration
+		annotation string
+		expected   time.Duration
+	}{
+		"default":            {expected: defaultClusterHealthInterval},
+		"dynamic":            {configured: 5 * time.Minute, dynamic: 2 * time.Minute, expected: 2 * time.Minute},
+		"configured":         {configured: 5 * time.Minute, expected: 5 * time.Minute},
+		"annotation":         {configured: 5 * time.Minute, annotation: "30s", expected: 30 * time.Second},
+		"invalid annotation": {configured: 5 * time.Minute, annotation: "often", expected: 5 * time.Minute},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster}}
+			if test.annotation != "" {
+				cluster.Annotations = map[string]string{AnnotationClusterHealthInterval: test.annotation}
+			}
+
+			reconciler := &ClusterHealthReconciler{Interval: test.configured}
+			if test.dynamic != 0 {
+				reconciler.Dynamic = config.NewDynamicConfig(config.Tunables{ClusterHealthInterval: test.dynamic})
+			}
+
+			interval := reconciler.interval(t.Context(), cluster)
+			if interval != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, interval)
+			}
+		})
+	}
+}
+
+func TestClusterHealthMachineDeploymentRollout(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workers",
+			Namespace: testPlanNamespace,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: testPlanCluster},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{ClusterName: testPlanCluster, Replicas: &replicas},
+		Status: clusterv1.MachineDeploymentStatus{
+			Phase:             string(clusterv1.MachineDeploymentPhaseScalingUp),
+			Replicas:          2,
+			UpdatedReplicas:   2,
+			ReadyReplicas:     1,
+			AvailableReplicas: 1,
+		},
+	}
+
+	ready := newPlanMachine("workers-ready", false, nil, true)
+	stuck := newPlanMachine("workers-stuck", false, nil, false)
+	stuck.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	stuck.Status.Phase = string(clusterv1.MachinePhaseProvisioned)
+
+	for _, machine := range []*clusterv1.Machine{ready, stuck} {
+		machine.Labels[clusterv1.MachineDeploymentNameLabel] = deployment.Name
+	}
+
+	reconciler := buildClusterHealthReconciler(t, kubernetesfake.NewClientset(), deployment, ready, stuck)
+
+	health := reconcileClusterHealth(t, reconciler)
+
+	if len(health.Status.MachineDeployments) != 1 {
+		t.Fatalf("expected one MachineDeployment, got %+v", health.Status.MachineDeployments)
+	}
+
+	rollout := health.Status.MachineDeployments[0]
+	if rollout.RolledOut || rollout.Desired != 2 || rollout.Ready != 1 {
+		t.Fatalf("expected an unfinished rollout, got %+v", rollout)
+	}
+
+	if len(rollout.StuckMachines) != 1 || rollout.StuckMachines[0].Name != stuck.Name ||
+		rollout.StuckMachines[0].Phase != stuck.Status.Phase {
+		t.Fatalf("expected %s to be stuck, got %+v", stuck.Name, rollout.StuckMachines)
+	}
+}
+
+// newHelmRelease encodes a release of the chart the way Helm stores it.
+func newHelmRelease(t *testing.T, namespace, name, revision, version, status string,
+	values map[string]any) *corev1.Secret {
+	t.Helper()
+
+	content, err := json.Marshal(map[string]any{
+		"chart":  map[string]any{"metadata": map[string]any{"version": version}},
+		"config": values,
+		"info":   map[string]any{"status": status},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal release: %v", err)
+	}
+
+	var compressed bytes.Buffer
+
+	writer := gzip.NewWriter(&compressed)
+
+	_, err = writer.Write(content)
+	if err != nil {
+		t.Fatalf("failed to compress release: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("failed to compress release: %v", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + name + ".v" + revision,
+			Namespace: namespace,
+			Labels:    map[string]string{"owner": "helm", "name": name, "version": revision},
+		},
+		Type: helmReleaseSecretType,
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(compressed.Bytes()))},
+	}
+}
+
+// newCCMAddon returns the installed CCM ConfigMap of the test cluster with its values.
+func newCCMAddon(version, values string) []client.Object {
+	return []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testPlanCluster + CCMConfigMapSuffix,
+				Namespace:   testPlanNamespace,
+				Labels:      map[string]string{clusterv1.ClusterNameLabel: testPlanCluster},
+				Annotations: map[string]string{AnnotationCCMAppliedHash: "applied"},
+			},
+			Data: map[string]string{
+				"namespace":  metav1.NamespaceSystem,
+				"name":       "hcloud-cloud-controller-manager",
+				"version":    version,
+				"repository": "https://charts.hetzner.cloud",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster + ccmExtraValuesSecretSuffix, Namespace: testPlanNamespace},
+			Data:       map[string][]byte{cniValuesKey: []byte(values)},
+		},
+	}
+}
+
+func TestClusterHealthAddonDrift(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		desiredVersion  string
+		releases        []*corev1.Secret
+		expectedInSync  bool
+		expectedVersion string
+	}{
+		"in sync": {
+			desiredVersion: "1.2.0",
+			releases: []*corev1.Secret{
+				newHelmRelease(t, metav1.NamespaceSystem, "hcloud-cloud-controller-manager", "1", "1.1.0",
+					"superseded", nil),
+				newHelmRelease(t, metav1.NamespaceSystem, "hcloud-cloud-controller-manager", "2", "1.2.0",
+					helmReleaseDeployed, map[string]any{"replicas": 2}),
+			},
+			expectedInSync:  true,
+			expectedVersion: "1.2.0",
+		},
+		"latest is not pinned": {
+			desiredVersion: "",
+			releases: []*corev1.Secret{
+				newHelmRelease(t, metav1.NamespaceSystem, "hcloud-cloud-controller-manager", "1", "1.1.0",
+					helmReleaseDeployed, map[string]any{"replicas": 2}),
+			},
+			expectedInSync:  true,
+			expectedVersion: "1.1.0",
+		},
+		"version drifted": {
+			desiredVersion: "1.2.0",
+			releases: []*corev1.Secret{
+				newHelmRelease(t, metav1.NamespaceSystem, "hcloud-cloud-controller-manager", "1", "1.1.0",
+					helmReleaseDeployed, map[string]any{"replicas": 2}),
+			},
+			expectedVersion: "1.1.0",
+		},
+		"values drifted": {
+			desiredVersion: "1.2.0",
+			releases: []*corev1.Secret{
+				newHelmRelease(t, metav1.NamespaceSystem, "hcloud-cloud-controller-manager", "1", "1.2.0",
+					helmReleaseDeployed, map[string]any{"replicas": 1}),
+			},
+			expectedVersion: "1.2.0",
+		},
+		"release failed": {
+			desiredVersion: "1.2.0",
+			releases: []*corev1.Secret{
+				newHelmRelease(t, metav1.NamespaceSystem, "hcloud-cloud-controller-manager", "1", "1.2.0",
+					"failed", map[string]any{"replicas": 2}),
+			},
+			expectedVersion: "1.2.0",
+		},
+		"release missing": {
+			desiredVersion: "1.2.0",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := kubernetesfake.NewClientset(
+				newLease("kube-controller-manager", time.Now()),
+				newLease("kube-scheduler", time.Now()))
+
+			for _, release := range test.releases {
+				_, err := workload.CoreV1().Secrets(release.Namespace).Create(t.Context(), release, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatalf("failed to create release: %v", err)
+				}
+			}
+
+			reconciler := buildClusterHealthReconciler(t, workload, newCCMAddon(test.desiredVersion, "replicas: 2\n")...)
+
+			health := reconcileClusterHealth(t, reconciler)
+
+			if len(health.Status.Addons) != 1 {
+				t.Fatalf("expected one add-on, got %+v", health.Status.Addons)
+			}
+
+			addon := health.Status.Addons[0]
+			if addon.InSync != test.expectedInSync || addon.DeployedVersion != test.expectedVersion {
+				t.Fatalf("unexpected add-on sync %+v", addon)
+			}
+
+			expectedStatus := metav1.ConditionFalse
+			if test.expectedInSync {
+				expectedStatus = metav1.ConditionTrue
+			}
+
+			if !meta.IsStatusConditionPresentAndEqual(health.Status.Conditions,
+				kommodityv1alpha1.ClusterHealthAddonsInSync, expectedStatus) {
+				t.Fatalf("expected %s to be %s, got %+v", kommodityv1alpha1.ClusterHealthAddonsInSync,
+					expectedStatus, health.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestClusterHealthWithoutAddons(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildClusterHealthReconciler(t, kubernetesfake.NewClientset())
+
+	health := reconcileClusterHealth(t, reconciler)
+
+	if len(health.Status.Addons) != 0 ||
+		meta.FindStatusCondition(health.Status.Conditions, kommodityv1alpha1.ClusterHealthAddonsInSync) != nil {
+		t.Fatalf("expected no add-ons to be checked, got %+v", health.Status)
+	}
+}
+
+func TestClusterHealthReconvergesDriftedAddons(t *testing.T) {
+	t.Parallel()
+
+	workload := kubernetesfake.NewClientset(newHelmRelease(t, metav1.NamespaceSystem,
+		"hcloud-cloud-controller-manager", "1", "1.1.0", helmReleaseDeployed, map[string]any{"replicas": 2}))
+
+	reconciler := buildClusterHealthReconciler(t, workload, newCCMAddon("1.2.0", "replicas: 2\n")...)
+
+	downstream := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	reconciler.downstreamClient = func(context.Context, string) (client.Client, error) {
+		return downstream, nil
+	}
+
+	key := types.NamespacedName{Namespace: testPlanNamespace, Name: testPlanCluster}
+	cluster := &clusterv1.Cluster{}
+
+	err := reconciler.Get(t.Context(), key, cluster)
+	if err != nil {
+		t.Fatalf("failed to get Cluster: %v", err)
+	}
+
+	cluster.Annotations = map[string]string{AnnotationReconvergeAddons: "true"}
+
+	err = reconciler.Update(t.Context(), cluster)
+	if err != nil {
+		t.Fatalf("failed to request re-converging: %v", err)
+	}
+
+	reconcileClusterHealth(t, reconciler)
+
+	jobs := &batchv1.JobList{}
+
+	err = downstream.List(t.Context(), jobs, client.InNamespace(metav1.NamespaceSystem))
+	if err != nil {
+		t.Fatalf("failed to list jobs: %v", err)
+	}
+
+	if len(jobs.Items) != 1 {
+		t.Fatalf("expected the install job to be applied, got %d jobs", len(jobs.Items))
+	}
+
+	err = reconciler.Get(t.Context(), key, cluster)
+	if err != nil {
+		t.Fatalf("failed to get Cluster: %v", err)
+	}
+
+	if _, requested := cluster.Annotations[AnnotationReconvergeAddons]; requested {
+		t.Fatalf("expected the re-converge request to be removed, got %+v", cluster.Annotations)
+	}
+}
@@ -0,0 +1,336 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+const (
+	// AnnotationDeletionProtection is "true" on a Cluster that can only be deleted after
+	// confirming it, and whose nodes are drained before their machines are removed.
+	AnnotationDeletionProtection = "kommodity.io/deletion-protection"
+	// AnnotationDeletionConfirmedAt is the RFC 3339 time a deletion of a protected Cluster
+	// was confirmed at. The Cluster can be deleted within DeletionConfirmationWindow of it.
+	AnnotationDeletionConfirmedAt = "kommodity.io/deletion-confirmed-at"
+	// DeletionConfirmationWindow is how long a confirmed deletion of a protected Cluster
+	// may be carried out.
+	DeletionConfirmationWindow = 10 * time.Minute
+
+	// teardownHookAnnotation holds the infrastructure of a Machine of a protected Cluster
+	// until its node is drained. Cluster API does not drain the nodes of a Cluster that is
+	// being deleted, but waits for pre-terminate hooks before deleting the infrastructure.
+	teardownHookAnnotation = clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/kommodity-teardown"
+	// teardownHookOwner is the value of the hook annotation, naming its owner.
+	teardownHookOwner = "kommodity"
+	// teardownDrainTimeout is how long after the deletion of the Cluster its nodes are
+	// drained, after which the remaining Machines are released undrained.
+	teardownDrainTimeout = 15 * time.Minute
+
+	// clusterTeardownControllerName is the name used to register the controller.
+	clusterTeardownControllerName = "kommodity-cluster-teardown-controller"
+	// clusterTeardownRecorderName is the component the events of the controller are reported as.
+	clusterTeardownRecorderName = "kommodity-cluster-teardown"
+	// nodeNameField selects the Pods of a node.
+	nodeNameField = "spec.nodeName"
+)
+
+// ClusterTeardownReconciler tears protected Clusters down in order. While a protected
+// Cluster exists, its Machines carry a pre-terminate hook. Once the Cluster is deleted,
+// the nodes of the workers are cordoned and drained first, then those of the control
+// plane, which keeps serving the API meanwhile; the hook of a Machine is removed once its
+// node is drained, letting Cluster API delete its infrastructure.
+type ClusterTeardownReconciler struct {
+	client.Client
+
+	Recorder record.EventRecorder
+
+	// downstreamClient returns a client for the workload cluster, defaults to one built
+	// from its kubeconfig Secret.
+	downstreamClient func(ctx context.Context, clusterName string) (client.Client, error)
+	// now returns the current time, defaults to time.Now.
+	now func() time.Time
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *ClusterTeardownReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up Cluster teardown reconciler")
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(clusterTeardownRecorderName)
+	}
+
+	if r.downstreamClient == nil {
+		r.downstreamClient = func(ctx context.Context, clusterName string) (client.Client, error) {
+			return (&DownstreamClientConfig{Client: r.Client, ClusterName: clusterName}).
+				FetchDownstreamKubernetesClient(ctx)
+		}
+	}
+
+	if r.now == nil {
+		r.now = time.Now
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(clusterTeardownControllerName).
+		For(&clusterv1.Cluster{}).
+		Watches(&clusterv1.Machine{}, handler.EnqueueRequestsFromMapFunc(clusterForObject)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up Cluster teardown controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile keeps the teardown hooks on the Machines of a protected Cluster, and drains
+// their nodes once the Cluster is deleted.
+func (r *ClusterTeardownReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("cluster", req.String()))
+
+	cluster := &clusterv1.Cluster{}
+
+	err := r.Get(ctx, req.NamespacedName, cluster)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	machines := &clusterv1.MachineList{}
+
+	err = r.List(ctx, machines, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list Machines of Cluster %s: %w", req, err)
+	}
+
+	if cluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileHooks(ctx, cluster, machines.Items)
+	}
+
+	if annotations.IsPaused(cluster, cluster) {
+		logger.Debug("Cluster is paused, skipping teardown")
+
+		return pausedResult(), nil
+	}
+
+	return r.reconcileTeardown(ctx, logger, cluster, machines.Items)
+}
+
+// reconcileHooks adds the teardown hook to the Machines of a protected Cluster, and
+// removes it from deleted Machines and once the protection is lifted.
+func (r *ClusterTeardownReconciler) reconcileHooks(ctx context.Context,
+	cluster *clusterv1.Cluster, machines []clusterv1.Machine) error {
+	protected := cluster.Annotations[AnnotationDeletionProtection] == "true"
+
+	for i := range machines {
+		machine := &machines[i]
+
+		// Cluster API drains the nodes of Machines deleted while the Cluster is not.
+		want := protected && machine.DeletionTimestamp.IsZero()
+
+		_, held := machine.Annotations[teardownHookAnnotation]
+		if held == want {
+			continue
+		}
+
+		err := r.setHook(ctx, machine, want)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileTeardown drains the nodes of the held worker Machines of a deleted Cluster,
+// and those of its control plane once no worker is held anymore.
+func (r *ClusterTeardownReconciler) reconcileTeardown(ctx context.Context, logger *zap.Logger,
+	cluster *clusterv1.Cluster, machines []clusterv1.Machine) (ctrl.Result, error) {
+	var workers, controlPlane []*clusterv1.Machine
+
+	for i := range machines {
+		machine := &machines[i]
+
+		if _, held := machine.Annotations[teardownHookAnnotation]; !held {
+			continue
+		}
+
+		if _, isControlPlane := machine.Labels[clusterv1.MachineControlPlaneLabel]; isControlPlane {
+			controlPlane = append(controlPlane, machine)
+		} else {
+			workers = append(workers, machine)
+		}
+	}
+
+	held := workers
+	if len(held) == 0 {
+		held = controlPlane
+	}
+
+	if len(held) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if r.now().Sub(cluster.DeletionTimestamp.Time) > teardownDrainTimeout {
+		logger.Info("Draining the nodes of the Cluster timed out, releasing its Machines",
+			zap.Duration("timeout", teardownDrainTimeout))
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "TeardownDrainTimedOut",
+			"Nodes were not drained within %s, deleting their machines anyway", teardownDrainTimeout)
+
+		for _, machine := range append(workers, controlPlane...) {
+			err := r.setHook(ctx, machine, false)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	workload, err := r.downstreamClient(ctx, cluster.Name)
+	if err != nil {
+		logger.Info("Workload cluster not reachable for teardown yet, requeuing",
+			zap.Error(err),
+			zap.Duration("requeueAfter", RequeueAfter))
+
+		//nolint:nilerr // the nodes are drained once reachable or released on timeout, retry without backoff.
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	for _, machine := range held {
+		drained, err := drainNode(ctx, workload, machine)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if !drained {
+			continue
+		}
+
+		logger.Info("Node drained, releasing Machine", zap.String("machine", machine.Name))
+
+		err = r.setHook(ctx, machine, false)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+}
+
+// setHook adds or removes the teardown hook of a Machine.
+func (r *ClusterTeardownReconciler) setHook(ctx context.Context, machine *clusterv1.Machine, held bool) error {
+	patched := machine.DeepCopy()
+
+	if held {
+		if patched.Annotations == nil {
+			patched.Annotations = map[string]string{}
+		}
+
+		patched.Annotations[teardownHookAnnotation] = teardownHookOwner
+	} else {
+		delete(patched.Annotations, teardownHookAnnotation)
+	}
+
+	err := r.Patch(ctx, patched, client.MergeFrom(machine))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to set teardown hook of Machine %s: %w", machine.Name, err)
+	}
+
+	return nil
+}
+
+// drainNode cordons the node of a Machine and evicts its Pods, other than those of
+// DaemonSets and static Pods. It reports whether no such Pod is left; a Machine without
+// node is drained. Evictions blocked by a PodDisruptionBudget are retried on the next call.
+func drainNode(ctx context.Context, workload client.Client, machine *clusterv1.Machine) (bool, error) {
+	if machine.Status.NodeRef == nil {
+		return true, nil
+	}
+
+	node := &corev1.Node{}
+
+	err := workload.Get(ctx, client.ObjectKey{Name: machine.Status.NodeRef.Name}, node)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", machine.Status.NodeRef.Name, err)
+	}
+
+	if !node.Spec.Unschedulable {
+		cordoned := node.DeepCopy()
+		cordoned.Spec.Unschedulable = true
+
+		err = workload.Patch(ctx, cordoned, client.MergeFrom(node))
+		if err != nil {
+			return false, fmt.Errorf("failed to cordon node %s: %w", node.Name, err)
+		}
+	}
+
+	pods := &corev1.PodList{}
+
+	err = workload.List(ctx, pods, client.MatchingFields{nodeNameField: node.Name})
+	if err != nil {
+		return false, fmt.Errorf("failed to list Pods of node %s: %w", node.Name, err)
+	}
+
+	remaining := 0
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		if !drainsPod(pod) {
+			continue
+		}
+
+		remaining++
+
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err = workload.SubResource("eviction").Create(ctx, pod, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		if err != nil && !apierrors.IsTooManyRequests(err) && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to evict Pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	return remaining == 0, nil
+}
+
+// drainsPod reports whether draining a node evicts the Pod. Finished Pods, static Pods
+// and the Pods of DaemonSets are left alone.
+func drainsPod(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+
+	if _, isMirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; isMirror {
+		return false
+	}
+
+	owner := metav1.GetControllerOf(pod)
+
+	return owner == nil || owner.Kind != "DaemonSet"
+}
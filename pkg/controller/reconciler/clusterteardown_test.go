@@ -0,0 +1,220 @@
+//nolint:testpackage // white-box tests replace the workload cluster client
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTeardownCluster(protected bool, deletedAt *time.Time) *clusterv1.Cluster {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace},
+	}
+
+	if protected {
+		cluster.Annotations = map[string]string{AnnotationDeletionProtection: "true"}
+	}
+
+	if deletedAt != nil {
+		deletionTimestamp := metav1.NewTime(*deletedAt)
+		cluster.DeletionTimestamp = &deletionTimestamp
+		cluster.Finalizers = []string{clusterv1.ClusterFinalizer}
+	}
+
+	return cluster
+}
+
+// newTeardownMachine returns a Machine of the test cluster held by the teardown hook,
+// with a node of the same name.
+func newTeardownMachine(name string, controlPlane bool) *clusterv1.Machine {
+	machine := newPlanMachine(name, controlPlane, map[string]string{teardownHookAnnotation: teardownHookOwner}, true)
+	machine.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: name}
+
+	return machine
+}
+
+func newTeardownPod(name, node, ownerKind string) *corev1.Pod {
+	isController := true
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: ownerKind, Name: name, UID: types.UID(name), Controller: &isController},
+			},
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func buildClusterTeardownReconciler(t *testing.T, workload client.Client,
+	objects ...client.Object) *ClusterTeardownReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	err := clusterv1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding to scheme: %v", err)
+	}
+
+	return &ClusterTeardownReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Recorder: record.NewFakeRecorder(10),
+		downstreamClient: func(context.Context, string) (client.Client, error) {
+			if workload == nil {
+				return nil, errClusterNotConnected
+			}
+
+			return workload, nil
+		},
+		now: time.Now,
+	}
+}
+
+func buildTeardownWorkload(objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithObjects(objects...).
+		WithIndex(&corev1.Pod{}, nodeNameField, func(obj client.Object) []string {
+			pod, _ := obj.(*corev1.Pod)
+
+			return []string{pod.Spec.NodeName}
+		}).
+		Build()
+}
+
+func reconcileClusterTeardown(t *testing.T, reconciler *ClusterTeardownReconciler) {
+	t.Helper()
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: testPlanNamespace, Name: testPlanCluster},
+	})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+}
+
+func teardownHeld(t *testing.T, c client.Client, name string) bool {
+	t.Helper()
+
+	machine := &clusterv1.Machine{}
+
+	err := c.Get(t.Context(), types.NamespacedName{Namespace: testPlanNamespace, Name: name}, machine)
+	if err != nil {
+		t.Fatalf("failed to get Machine %s: %v", name, err)
+	}
+
+	_, held := machine.Annotations[teardownHookAnnotation]
+
+	return held
+}
+
+func TestClusterTeardownHooksFollowProtection(t *testing.T) {
+	t.Parallel()
+
+	running := newPlanMachine("running", false, nil, true)
+	deleted := newTeardownMachine("deleted", false)
+	deletionTimestamp := metav1.Now()
+	deleted.DeletionTimestamp = &deletionTimestamp
+	deleted.Finalizers = []string{clusterv1.MachineFinalizer}
+
+	reconciler := buildClusterTeardownReconciler(t, nil, newTeardownCluster(true, nil), running, deleted)
+
+	reconcileClusterTeardown(t, reconciler)
+
+	if !teardownHeld(t, reconciler, "running") {
+		t.Fatal("expected the Machine of a protected Cluster to be held")
+	}
+
+	// Cluster API drains the node of a Machine deleted on its own.
+	if teardownHeld(t, reconciler, "deleted") {
+		t.Fatal("expected a deleted Machine of a Cluster that is not deleted to be released")
+	}
+
+	cluster := newTeardownCluster(false, nil)
+	reconciler.Client = fake.NewClientBuilder().WithScheme(reconciler.Scheme()).
+		WithObjects(cluster, newTeardownMachine("running", false)).Build()
+
+	reconcileClusterTeardown(t, reconciler)
+
+	if teardownHeld(t, reconciler, "running") {
+		t.Fatal("expected the Machine to be released once the protection is lifted")
+	}
+}
+
+func TestClusterTeardownDrainsWorkersBeforeControlPlane(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Now()
+	workload := buildTeardownWorkload(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "control-plane"}},
+		newTeardownPod("app", "worker", "ReplicaSet"),
+		newTeardownPod("agent", "worker", "DaemonSet"))
+
+	reconciler := buildClusterTeardownReconciler(t, workload, newTeardownCluster(true, &deletedAt),
+		newTeardownMachine("worker", false), newTeardownMachine("control-plane", true))
+
+	reconcileClusterTeardown(t, reconciler)
+
+	node := &corev1.Node{}
+
+	err := workload.Get(t.Context(), types.NamespacedName{Name: "worker"}, node)
+	if err != nil || !node.Spec.Unschedulable {
+		t.Fatalf("expected the worker node to be cordoned, got %v (err %v)", node.Spec, err)
+	}
+
+	pods := &corev1.PodList{}
+
+	err = workload.List(t.Context(), pods)
+	if err != nil || len(pods.Items) != 1 || pods.Items[0].Name != "agent" {
+		t.Fatalf("expected only the DaemonSet Pod to be left, got %v (err %v)", pods.Items, err)
+	}
+
+	if !teardownHeld(t, reconciler, "worker") || !teardownHeld(t, reconciler, "control-plane") {
+		t.Fatal("expected both Machines to be held until the evicted Pods are gone")
+	}
+
+	reconcileClusterTeardown(t, reconciler)
+
+	if teardownHeld(t, reconciler, "worker") {
+		t.Fatal("expected the drained worker to be released")
+	}
+
+	if !teardownHeld(t, reconciler, "control-plane") {
+		t.Fatal("expected the control plane to be held while workers were drained")
+	}
+
+	reconcileClusterTeardown(t, reconciler)
+
+	if teardownHeld(t, reconciler, "control-plane") {
+		t.Fatal("expected the drained control plane to be released")
+	}
+}
+
+func TestClusterTeardownReleasesAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Now().Add(-teardownDrainTimeout - time.Minute)
+	reconciler := buildClusterTeardownReconciler(t, nil, newTeardownCluster(true, &deletedAt),
+		newTeardownMachine("worker", false), newTeardownMachine("control-plane", true))
+
+	reconcileClusterTeardown(t, reconciler)
+
+	if teardownHeld(t, reconciler, "worker") || teardownHeld(t, reconciler, "control-plane") {
+		t.Fatal("expected the Machines to be released once draining timed out")
+	}
+}
@@ -0,0 +1,347 @@
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// LabelClusterInstance records the ClusterInstance an object was created for.
+	LabelClusterInstance = "kommodity.io/cluster-instance"
+
+	clusterInstanceControllerName = "kommodity-cluster-instance-controller"
+)
+
+// ClusterInstanceReconciler creates clusters from ClusterTemplates. The manifests of the
+// template of a ClusterInstance are rendered with the values of the instance and the
+// objects, typically the Cluster, TalosControlPlane, MachineDeployments and their
+// infrastructure templates, are created in its namespace, owned by the instance so that
+// deleting it deletes the cluster.
+//
+// Objects are only created when missing. Most CAPI templates are immutable, so changes
+// to the template or the values do not update existing objects; they apply to objects
+// added to the template and to new instances.
+type ClusterInstanceReconciler struct {
+	client.Client
+}
+
+// clusterTemplateValues are the values available to ClusterTemplate manifests.
+type clusterTemplateValues struct {
+	Name      string
+	Namespace string
+	Template  string
+	Values    map[string]string
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *ClusterInstanceReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up ClusterInstance reconciler")
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(clusterInstanceControllerName).
+		For(&kommodityv1alpha1.ClusterInstance{}).
+		Watches(&kommodityv1alpha1.ClusterTemplate{}, handler.EnqueueRequestsFromMapFunc(r.instancesForTemplate)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up ClusterInstance controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile creates the missing objects of a ClusterInstance.
+func (r *ClusterInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("clusterInstance", req.String()))
+
+	instance := &kommodityv1alpha1.ClusterInstance{}
+
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	original := instance.DeepCopy()
+	instance.Status.ObservedGeneration = instance.Generation
+
+	applyErr := r.apply(ctx, logger, instance)
+
+	err = r.Status().Patch(ctx, instance, client.MergeFrom(original))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status of ClusterInstance %s: %w", instance.Name, err)
+	}
+
+	return ctrl.Result{}, applyErr
+}
+
+// apply renders the template of the instance and creates the missing objects. The
+// outcome is recorded in the instance status. Only failures that may resolve on their
+// own, like a rejected create, are returned to be retried.
+func (r *ClusterInstanceReconciler) apply(ctx context.Context, logger *zap.Logger,
+	instance *kommodityv1alpha1.ClusterInstance) error {
+	clusterTemplate, err := r.clusterTemplate(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	if clusterTemplate == nil {
+		instance.Status.Phase = kommodityv1alpha1.ClusterInstancePhasePending
+		instance.Status.Message = fmt.Sprintf("waiting for ClusterTemplate %s to be created", instance.Spec.TemplateName)
+
+		return nil
+	}
+
+	values, err := clusterInstanceValues(clusterTemplate, instance)
+	if err != nil {
+		return failClusterInstance(logger, instance, err)
+	}
+
+	objects, err := renderClusterTemplate(clusterTemplate, values)
+	if err != nil {
+		return failClusterInstance(logger, instance, err)
+	}
+
+	instance.Status.Objects = int32(len(objects)) //nolint:gosec // Bounded by the size of the template.
+
+	err = r.createObjects(ctx, logger, instance, objects)
+	if err != nil {
+		return failClusterInstance(logger, instance, err)
+	}
+
+	instance.Status.Phase = kommodityv1alpha1.ClusterInstancePhaseCreated
+	instance.Status.Message = ""
+
+	return nil
+}
+
+// failClusterInstance records the failure in the status of the instance. Invalid
+// templates and values are only retried once the instance or the template change.
+func failClusterInstance(logger *zap.Logger, instance *kommodityv1alpha1.ClusterInstance, err error) error {
+	instance.Status.Phase = kommodityv1alpha1.ClusterInstancePhaseFailed
+	instance.Status.Message = err.Error()
+
+	if errors.Is(err, ErrInvalidClusterTemplate) || errors.Is(err, ErrInvalidClusterInstanceValues) {
+		logger.Warn("ClusterInstance cannot be rendered", zap.Error(err))
+
+		return nil
+	}
+
+	return err
+}
+
+// clusterTemplate returns the ClusterTemplate of the instance from its namespace, or the
+// shared one from the Kommodity namespace, or nil if neither exists.
+func (r *ClusterInstanceReconciler) clusterTemplate(ctx context.Context,
+	instance *kommodityv1alpha1.ClusterInstance) (*kommodityv1alpha1.ClusterTemplate, error) {
+	for _, namespace := range []string{instance.Namespace, config.KommodityNamespace} {
+		clusterTemplate := &kommodityv1alpha1.ClusterTemplate{}
+
+		err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: instance.Spec.TemplateName}, clusterTemplate)
+		if err == nil {
+			return clusterTemplate, nil
+		}
+
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get ClusterTemplate %s/%s: %w", namespace, instance.Spec.TemplateName, err)
+		}
+	}
+
+	return nil, nil //nolint:nilnil // The ClusterTemplate does not exist yet.
+}
+
+// createObjects creates the objects that do not exist yet.
+func (r *ClusterInstanceReconciler) createObjects(ctx context.Context, logger *zap.Logger,
+	instance *kommodityv1alpha1.ClusterInstance, objects []*unstructured.Unstructured) error {
+	owner := metav1.OwnerReference{
+		APIVersion: kommodityv1alpha1.GroupVersion.String(),
+		Kind:       "ClusterInstance",
+		Name:       instance.Name,
+		UID:        instance.UID,
+	}
+
+	for _, desired := range objects {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(desired.GroupVersionKind())
+
+		err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+
+		switch {
+		case err == nil:
+			continue
+		case meta.IsNoMatchError(err):
+			return fmt.Errorf("%w: %s is not served by the API server", ErrInvalidClusterTemplate, desired.GetKind())
+		case !apierrors.IsNotFound(err):
+			return fmt.Errorf("failed to get %s %s/%s: %w", desired.GetKind(),
+				desired.GetNamespace(), desired.GetName(), err)
+		}
+
+		desired.SetOwnerReferences([]metav1.OwnerReference{owner})
+
+		err = r.Create(ctx, desired)
+		if err != nil {
+			return fmt.Errorf("failed to create %s %s/%s: %w", desired.GetKind(),
+				desired.GetNamespace(), desired.GetName(), err)
+		}
+
+		logger.Info("Created ClusterInstance object",
+			zap.String("kind", desired.GetKind()),
+			zap.String("name", desired.GetName()))
+	}
+
+	return nil
+}
+
+// instancesForTemplate enqueues the ClusterInstances that may use the changed template:
+// those of its namespace, or of every namespace for shared templates.
+func (r *ClusterInstanceReconciler) instancesForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	opts := []client.ListOption{}
+	if obj.GetNamespace() != config.KommodityNamespace {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+
+	instances := &kommodityv1alpha1.ClusterInstanceList{}
+
+	err := r.List(ctx, instances, opts...)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list ClusterInstances for ClusterTemplate watch",
+			zap.String("template", obj.GetName()),
+			zap.Error(err))
+
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+
+	for i := range instances.Items {
+		if instances.Items[i].Spec.TemplateName == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&instances.Items[i]),
+			})
+		}
+	}
+
+	return requests
+}
+
+// clusterInstanceValues returns the values of the instance completed with the defaults of
+// the template. Values the template does not declare and missing required values are
+// rejected.
+func clusterInstanceValues(clusterTemplate *kommodityv1alpha1.ClusterTemplate,
+	instance *kommodityv1alpha1.ClusterInstance) (clusterTemplateValues, error) {
+	values := map[string]string{}
+	declared := make([]string, 0, len(clusterTemplate.Spec.Parameters))
+	missing := []string{}
+
+	for _, parameter := range clusterTemplate.Spec.Parameters {
+		declared = append(declared, parameter.Name)
+
+		value, found := instance.Spec.Values[parameter.Name]
+
+		switch {
+		case found:
+			values[parameter.Name] = value
+		case parameter.Required:
+			missing = append(missing, parameter.Name)
+		default:
+			values[parameter.Name] = parameter.Default
+		}
+	}
+
+	if len(missing) > 0 {
+		return clusterTemplateValues{}, fmt.Errorf("%w: missing required values %s",
+			ErrInvalidClusterInstanceValues, strings.Join(missing, ", "))
+	}
+
+	unknown := []string{}
+
+	for name := range instance.Spec.Values {
+		if !slices.Contains(declared, name) {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		return clusterTemplateValues{}, fmt.Errorf("%w: template %s has no parameters %s",
+			ErrInvalidClusterInstanceValues, clusterTemplate.Name, strings.Join(unknown, ", "))
+	}
+
+	return clusterTemplateValues{
+		Name:      instance.Name,
+		Namespace: instance.Namespace,
+		Template:  clusterTemplate.Name,
+		Values:    values,
+	}, nil
+}
+
+// renderClusterTemplate renders the manifests of a ClusterTemplate into objects in the
+// namespace of the instance.
+func renderClusterTemplate(clusterTemplate *kommodityv1alpha1.ClusterTemplate,
+	values clusterTemplateValues) ([]*unstructured.Unstructured, error) {
+	tpl, err := template.New(clusterTemplate.Name).Option("missingkey=error").Parse(clusterTemplate.Spec.Manifests)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidClusterTemplate, err)
+	}
+
+	rendered := &bytes.Buffer{}
+
+	err = tpl.Execute(rendered, values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidClusterTemplate, err)
+	}
+
+	objects, err := decodeManifests(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidClusterTemplate, err)
+	}
+
+	for _, obj := range objects {
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("%w: objects must set kind and metadata.name", ErrInvalidClusterTemplate)
+		}
+
+		if obj.GetNamespace() != "" && obj.GetNamespace() != values.Namespace {
+			return nil, fmt.Errorf("%w: %s %s targets namespace %s", ErrInvalidClusterTemplate,
+				obj.GetKind(), obj.GetName(), obj.GetNamespace())
+		}
+
+		obj.SetNamespace(values.Namespace)
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		labels[config.ManagedByLabel] = "kommodity"
+		labels[LabelClusterInstance] = values.Name
+		obj.SetLabels(labels)
+	}
+
+	return objects, nil
+}
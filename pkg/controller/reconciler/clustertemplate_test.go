@@ -0,0 +1,226 @@
+//nolint:testpackage // white-box tests exercise unexported template rendering
+package reconciler
+
+import (
+	"errors"
+	"testing"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testInstanceNamespace = "team-a"
+	testInstanceName      = "prod"
+	testClusterManifests  = `
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: {{ .Name }}
+spec:
+  clusterNetwork:
+    pods:
+      cidrBlocks:
+        - {{ .Values.podCIDR }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Name }}-values
+data:
+  region: {{ .Values.region }}
+`
+)
+
+func newClusterTemplate(namespace string) *kommodityv1alpha1.ClusterTemplate {
+	return &kommodityv1alpha1.ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "talos", Namespace: namespace},
+		Spec: kommodityv1alpha1.ClusterTemplateSpec{
+			Parameters: []kommodityv1alpha1.ClusterTemplateParameter{
+				{Name: "podCIDR", Default: "10.244.0.0/16"},
+				{Name: "region", Required: true},
+			},
+			Manifests: testClusterManifests,
+		},
+	}
+}
+
+func newClusterInstance(values map[string]string) *kommodityv1alpha1.ClusterInstance {
+	return &kommodityv1alpha1.ClusterInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testInstanceName, Namespace: testInstanceNamespace, Generation: 1, UID: "instance-uid",
+		},
+		Spec: kommodityv1alpha1.ClusterInstanceSpec{TemplateName: "talos", Values: values},
+	}
+}
+
+func buildClusterInstanceReconciler(t *testing.T, objects ...client.Object) *ClusterInstanceReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme, clusterv1.AddToScheme, kommodityv1alpha1.AddToScheme,
+	} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	return &ClusterInstanceReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&kommodityv1alpha1.ClusterInstance{}).
+			Build(),
+	}
+}
+
+func reconcileInstance(t *testing.T, reconciler *ClusterInstanceReconciler) *kommodityv1alpha1.ClusterInstance {
+	t.Helper()
+
+	key := types.NamespacedName{Namespace: testInstanceNamespace, Name: testInstanceName}
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	instance := &kommodityv1alpha1.ClusterInstance{}
+
+	err = reconciler.Get(t.Context(), key, instance)
+	if err != nil {
+		t.Fatalf("failed to get instance: %v", err)
+	}
+
+	return instance
+}
+
+func TestClusterInstanceCreatesTemplateObjects(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildClusterInstanceReconciler(t,
+		newClusterTemplate(config.KommodityNamespace),
+		newClusterInstance(map[string]string{"region": "fr-par"}),
+	)
+
+	instance := reconcileInstance(t, reconciler)
+	if instance.Status.Phase != kommodityv1alpha1.ClusterInstancePhaseCreated || instance.Status.Objects != 2 {
+		t.Fatalf("expected two created objects, got %+v", instance.Status)
+	}
+
+	cluster := &clusterv1.Cluster{}
+
+	err := reconciler.Get(t.Context(),
+		types.NamespacedName{Namespace: testInstanceNamespace, Name: testInstanceName}, cluster)
+	if err != nil {
+		t.Fatalf("expected the Cluster to be created: %v", err)
+	}
+
+	if cluster.Spec.ClusterNetwork.Pods.CIDRBlocks[0] != "10.244.0.0/16" {
+		t.Fatalf("expected the default pod CIDR, got %v", cluster.Spec.ClusterNetwork.Pods.CIDRBlocks)
+	}
+
+	if cluster.Labels[LabelClusterInstance] != testInstanceName {
+		t.Fatalf("expected the Cluster to be labelled with its instance, got %v", cluster.Labels)
+	}
+
+	if len(cluster.OwnerReferences) != 1 || cluster.OwnerReferences[0].UID != "instance-uid" {
+		t.Fatalf("expected the Cluster to be owned by its instance, got %v", cluster.OwnerReferences)
+	}
+
+	values := &corev1.ConfigMap{}
+
+	err = reconciler.Get(t.Context(),
+		types.NamespacedName{Namespace: testInstanceNamespace, Name: testInstanceName + "-values"}, values)
+	if err != nil || values.Data["region"] != "fr-par" {
+		t.Fatalf("expected the ConfigMap to hold the region, got %v (%v)", values.Data, err)
+	}
+}
+
+func TestClusterInstanceDoesNotUpdateExistingObjects(t *testing.T) {
+	t.Parallel()
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: testInstanceName + "-values", Namespace: testInstanceNamespace},
+		Data:       map[string]string{"region": "nl-ams"},
+	}
+
+	reconciler := buildClusterInstanceReconciler(t,
+		newClusterTemplate(testInstanceNamespace),
+		newClusterInstance(map[string]string{"region": "fr-par"}),
+		existing,
+	)
+	reconcileInstance(t, reconciler)
+
+	values := &corev1.ConfigMap{}
+
+	err := reconciler.Get(t.Context(), client.ObjectKeyFromObject(existing), values)
+	if err != nil || values.Data["region"] != "nl-ams" {
+		t.Fatalf("expected the existing ConfigMap to be kept, got %v (%v)", values.Data, err)
+	}
+}
+
+func TestClusterInstanceWaitsForTemplate(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildClusterInstanceReconciler(t, newClusterInstance(nil))
+
+	instance := reconcileInstance(t, reconciler)
+	if instance.Status.Phase != kommodityv1alpha1.ClusterInstancePhasePending {
+		t.Fatalf("expected the instance to wait for its template, got %+v", instance.Status)
+	}
+}
+
+func TestClusterInstanceValues(t *testing.T) {
+	t.Parallel()
+
+	clusterTemplate := newClusterTemplate(testInstanceNamespace)
+
+	_, err := clusterInstanceValues(clusterTemplate, newClusterInstance(nil))
+	if !errors.Is(err, ErrInvalidClusterInstanceValues) {
+		t.Fatalf("expected missing required values to be rejected, got %v", err)
+	}
+
+	_, err = clusterInstanceValues(clusterTemplate,
+		newClusterInstance(map[string]string{"region": "fr-par", "zone": "fr-par-1"}))
+	if !errors.Is(err, ErrInvalidClusterInstanceValues) {
+		t.Fatalf("expected undeclared values to be rejected, got %v", err)
+	}
+
+	reconciler := buildClusterInstanceReconciler(t, clusterTemplate, newClusterInstance(nil))
+
+	instance := reconcileInstance(t, reconciler)
+	if instance.Status.Phase != kommodityv1alpha1.ClusterInstancePhaseFailed || instance.Status.Message == "" {
+		t.Fatalf("expected the invalid values to be reported, got %+v", instance.Status)
+	}
+}
+
+func TestRenderClusterTemplateRejectsOtherNamespaces(t *testing.T) {
+	t.Parallel()
+
+	clusterTemplate := newClusterTemplate(testInstanceNamespace)
+	clusterTemplate.Spec.Manifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: values
+  namespace: team-b
+`
+
+	_, err := renderClusterTemplate(clusterTemplate, clusterTemplateValues{
+		Name: testInstanceName, Namespace: testInstanceNamespace, Template: "talos",
+	})
+	if !errors.Is(err, ErrInvalidClusterTemplate) {
+		t.Fatalf("expected objects of other namespaces to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// AnnotationCNI selects a built-in CNI preset for a Cluster, "cilium" or "flannel".
+	AnnotationCNI = "kommodity.io/cni"
+	// AnnotationCNIConfig names a ConfigMap in the namespace of the Cluster holding the chart
+	// of the CNI, with the keys namespace, repository, name, version and optionally
+	// values.yaml. It takes precedence over AnnotationCNI.
+	AnnotationCNIConfig = "kommodity.io/cni-config"
+	// AnnotationCNIInstalled records the chart and version of the CNI installed into the
+	// workload cluster, so that the install job runs once.
+	AnnotationCNIInstalled = "kommodity.io/cni-installed"
+
+	// cniControllerName is the name used to register the controller.
+	cniControllerName = "kommodity-cni-controller"
+
+	// cniValuesKey is the key of the chart values in the CNI ConfigMap.
+	cniValuesKey = "values.yaml"
+)
+
+// cniChart is the Helm chart of a CNI and the values it is installed with.
+type cniChart struct {
+	Namespace  string
+	Repository string
+	Name       string
+	Version    string
+	Values     string
+}
+
+// cniPresets are the CNIs that can be selected by name. The values fit Talos, which
+// keeps kube-proxy and mounts cgroups itself.
+//
+//nolint:gochecknoglobals // Constant table of CNI presets.
+var cniPresets = map[string]func(cluster *clusterv1.Cluster) cniChart{
+	"cilium": func(*clusterv1.Cluster) cniChart {
+		return cniChart{
+			Namespace:  metav1.NamespaceSystem,
+			Repository: "https://helm.cilium.io/",
+			Name:       "cilium",
+			Version:    "1.18.4",
+			Values: `ipam:
+  mode: kubernetes
+cgroup:
+  autoMount:
+    enabled: false
+  hostRoot: /sys/fs/cgroup
+securityContext:
+  capabilities:
+    ciliumAgent: [CHOWN, KILL, NET_ADMIN, NET_RAW, IPC_LOCK, SYS_ADMIN, SYS_RESOURCE, DAC_OVERRIDE, FOWNER, SETGID, SETUID]
+    cleanCiliumState: [NET_ADMIN, SYS_ADMIN, SYS_RESOURCE]
+`,
+		}
+	},
+	"flannel": func(cluster *clusterv1.Cluster) cniChart {
+		chart := cniChart{
+			Namespace:  "kube-flannel",
+			Repository: "https://flannel-io.github.io/flannel/",
+			Name:       "flannel",
+			Version:    "v0.27.4",
+		}
+
+		if network := cluster.Spec.ClusterNetwork; network != nil && network.Pods != nil &&
+			len(network.Pods.CIDRBlocks) > 0 {
+			chart.Values = fmt.Sprintf("podCidr: %q\n", network.Pods.CIDRBlocks[0])
+		}
+
+		return chart
+	},
+}
+
+// CNIReconciler installs a CNI into workload clusters as soon as their control plane
+// is reachable, so that nodes become ready without manual steps. The CNI is selected
+// per Cluster with an annotation.
+type CNIReconciler struct {
+	client.Client
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *CNIReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up CNI reconciler")
+
+	wantsCNI := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		annotations := obj.GetAnnotations()
+
+		return annotations[AnnotationCNI] != "" || annotations[AnnotationCNIConfig] != ""
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(cniControllerName).
+		For(&clusterv1.Cluster{}, builder.WithPredicates(wantsCNI)).
+		WithOptions(opt).
+		WithEventFilter(predicates.ResourceNotPaused(
+			mgr.GetScheme(),
+			zapr.NewLogger(logging.FromContext(ctx)),
+		)).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up CNI controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile installs the CNI of a Cluster once.
+func (r *CNIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("cluster", req.String()))
+
+	cluster := &clusterv1.Cluster{}
+
+	err := r.Get(ctx, req.NamespacedName, cluster)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	chart, err := r.resolveCNI(ctx, cluster)
+	if errors.Is(err, ErrUnknownCNI) {
+		logger.Error("Unknown CNI preset, skipping installation", zap.Error(err))
+
+		return ctrl.Result{}, nil
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	installed := chart.Name + "@" + chart.Version
+	if cluster.Annotations[AnnotationCNIInstalled] == installed {
+		return ctrl.Result{}, nil
+	}
+
+	kubeClient, err := (&DownstreamClientConfig{
+		Client:      r.Client,
+		ClusterName: cluster.Name,
+	}).FetchDownstreamKubernetesClient(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Cluster kubeconfig not ready yet, requeuing", zap.Duration("requeueAfter", RequeueAfter))
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to fetch kubeconfig from secret: %w", err)
+	}
+
+	err = CheckClusterReady(ctx, kubeClient)
+	if err != nil {
+		logger.Info("Downstream cluster not ready yet, requeuing", zap.Duration("requeueAfter", RequeueAfter))
+
+		//nolint:nilerr // intentionally return nil to avoid exponential backoff
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	err = installCNI(ctx, kubeClient, chart)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := cluster.DeepCopy()
+	cluster.Annotations[AnnotationCNIInstalled] = installed
+
+	err = r.Patch(ctx, cluster, client.MergeFrom(original))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to record installed CNI on Cluster %s: %w", cluster.Name, err)
+	}
+
+	logger.Info("Applied CNI install job", zap.String("cni", installed))
+
+	return ctrl.Result{}, nil
+}
+
+// resolveCNI returns the chart selected by the annotations of the Cluster.
+func (r *CNIReconciler) resolveCNI(ctx context.Context, cluster *clusterv1.Cluster) (cniChart, error) {
+	configMapName := cluster.Annotations[AnnotationCNIConfig]
+	if configMapName == "" {
+		preset, found := cniPresets[cluster.Annotations[AnnotationCNI]]
+		if !found {
+			return cniChart{}, fmt.Errorf("%w: %q", ErrUnknownCNI, cluster.Annotations[AnnotationCNI])
+		}
+
+		return preset(cluster), nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: configMapName}, configMap)
+	if err != nil {
+		return cniChart{}, fmt.Errorf("failed to get CNI ConfigMap %s: %w", configMapName, err)
+	}
+
+	for _, key := range []string{"namespace", "repository", "name"} {
+		if configMap.Data[key] == "" {
+			return cniChart{}, fmt.Errorf("%s %w: %s", key, ErrValueNotFoundInConfigMap, configMapName)
+		}
+	}
+
+	chart := cniChart{
+		Namespace:  configMap.Data["namespace"],
+		Repository: configMap.Data["repository"],
+		Name:       configMap.Data["name"],
+		Version:    configMap.Data["version"],
+		Values:     configMap.Data[cniValuesKey],
+	}
+
+	if chart.Version == "" {
+		chart.Version = "latest"
+	}
+
+	return chart, nil
+}
+
+// installCNI creates the namespace of the chart and applies the Helm install job. The
+// job leaves an existing release alone, so applying it again is harmless.
+func installCNI(ctx context.Context, kubeClient client.Client, chart cniChart) error {
+	jobConfig := NewHelmInstallConfig(chart.Name, chart.Namespace, chart.Name, chart.Version, chart.Repository, false)
+	// Pods outside the host network only get an address once the CNI runs.
+	jobConfig.HostNetwork = true
+
+	var values map[string][]byte
+	if chart.Values != "" {
+		values = map[string][]byte{cniValuesKey: []byte(chart.Values)}
+	}
+
+	return installHelmChart(ctx, kubeClient, &jobConfig, values)
+}
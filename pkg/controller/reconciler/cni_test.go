@@ -0,0 +1,107 @@
+//nolint:testpackage // white-box tests cover the CNI presets of the package
+package reconciler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newCNICluster(annotations map[string]string) *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace, Annotations: annotations},
+		Spec: clusterv1.ClusterSpec{ClusterNetwork: &clusterv1.ClusterNetwork{
+			Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.244.0.0/16"}},
+		}},
+	}
+}
+
+func TestCNIResolve(t *testing.T) {
+	t.Parallel()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "calico", Namespace: testPlanNamespace},
+		Data: map[string]string{
+			"namespace":  "tigera-operator",
+			"repository": "https://docs.tigera.io/calico/charts",
+			"name":       "tigera-operator",
+		},
+	}
+
+	reconciler := &CNIReconciler{
+		Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(configMap).Build(),
+	}
+
+	tests := map[string]struct {
+		annotations map[string]string
+		expected    string
+		values      string
+		err         error
+	}{
+		"flannel preset": {
+			annotations: map[string]string{AnnotationCNI: "flannel"},
+			expected:    "flannel",
+			values:      `podCidr: "10.244.0.0/16"`,
+		},
+		"configmap wins over preset": {
+			annotations: map[string]string{AnnotationCNI: "cilium", AnnotationCNIConfig: "calico"},
+			expected:    "tigera-operator",
+		},
+		"unknown preset": {
+			annotations: map[string]string{AnnotationCNI: "weave"},
+			err:         ErrUnknownCNI,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			chart, err := reconciler.resolveCNI(t.Context(), newCNICluster(test.annotations))
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if chart.Name != test.expected || !strings.Contains(chart.Values, test.values) {
+				t.Fatalf("unexpected chart %+v", chart)
+			}
+		})
+	}
+}
+
+func TestCNIInstallRunsOnHostNetwork(t *testing.T) {
+	t.Parallel()
+
+	downstream := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	err := installCNI(t.Context(), downstream, cniPresets["flannel"](newCNICluster(nil)))
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	namespace := &corev1.Namespace{}
+
+	err = downstream.Get(t.Context(), client.ObjectKey{Name: "kube-flannel"}, namespace)
+	if err != nil || namespace.Labels["pod-security.kubernetes.io/enforce"] != "privileged" {
+		t.Fatalf("expected a privileged kube-flannel namespace, got %+v: %v", namespace.Labels, err)
+	}
+
+	job := &batchv1.Job{}
+
+	err = downstream.Get(t.Context(), client.ObjectKey{Name: "flannel-v0-27-4-install", Namespace: "kube-flannel"}, job)
+	if err != nil {
+		t.Fatalf("expected the install job: %v", err)
+	}
+
+	if !job.Spec.Template.Spec.HostNetwork || len(job.Spec.Template.Spec.Volumes) != 1 {
+		t.Fatalf("expected a host network job with the values mounted, got %+v", job.Spec.Template.Spec)
+	}
+}
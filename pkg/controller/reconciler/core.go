@@ -9,6 +9,7 @@ import (
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	capi_controllers "sigs.k8s.io/cluster-api/controllers"
 	"sigs.k8s.io/cluster-api/controllers/clustercache"
+	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
@@ -40,13 +41,28 @@ func setupCAPI(ctx context.Context, manager ctrl.Manager,
 	remoteConnectionGracePeriod time.Duration) error {
 	logger := logging.FromContext(ctx)
 
+	// The ClusterClass and Cluster webhooks reject ClusterClasses and managed topologies
+	// unless the gate is enabled. It is read when requests are admitted, so enabling it
+	// before the manager starts covers the webhooks as well.
+	err := feature.MutableGates.Set(string(feature.ClusterTopology) + "=true")
+	if err != nil {
+		return fmt.Errorf("failed to enable the %s feature gate: %w", feature.ClusterTopology, err)
+	}
+
 	logger.Info("Setting up ClusterClass controller")
 
-	err := setupClusterClassWithManager(ctx, manager, opt)
+	err = setupClusterClassWithManager(ctx, manager, opt)
 	if err != nil {
 		return fmt.Errorf("failed to setup ClusterClass controller: %w", err)
 	}
 
+	logger.Info("Setting up ClusterTopology controllers")
+
+	err = setupClusterTopologyWithManager(ctx, manager, clusterCache, opt)
+	if err != nil {
+		return fmt.Errorf("failed to setup ClusterTopology controllers: %w", err)
+	}
+
 	logger.Info("Setting up Cluster controller")
 
 	err = setupClusterWithManager(ctx, manager, clusterCache, opt, remoteConnectionGracePeriod)
@@ -126,6 +142,40 @@ func setupClusterClassWithManager(ctx context.Context, manager ctrl.Manager, opt
 	return nil
 }
 
+// setupClusterTopologyWithManager sets up the controllers reconciling the managed
+// topologies of Clusters, and cleaning up the templates of the MachineDeployments and
+// MachineSets they own. The RuntimeSDK is not enabled, so ClusterClasses cannot use
+// external patches and no lifecycle hooks are called.
+func setupClusterTopologyWithManager(ctx context.Context, manager ctrl.Manager,
+	clusterCache clustercache.ClusterCache, opt controller.Options) error {
+	err := (&capi_controllers.ClusterTopologyReconciler{
+		Client:       manager.GetClient(),
+		APIReader:    manager.GetAPIReader(),
+		ClusterCache: clusterCache,
+	}).SetupWithManager(ctx, manager, opt)
+	if err != nil {
+		return fmt.Errorf("failed to setup ClusterTopology: %w", err)
+	}
+
+	err = (&capi_controllers.MachineDeploymentTopologyReconciler{
+		Client:    manager.GetClient(),
+		APIReader: manager.GetAPIReader(),
+	}).SetupWithManager(ctx, manager, opt)
+	if err != nil {
+		return fmt.Errorf("failed to setup MachineDeploymentTopology: %w", err)
+	}
+
+	err = (&capi_controllers.MachineSetTopologyReconciler{
+		Client:    manager.GetClient(),
+		APIReader: manager.GetAPIReader(),
+	}).SetupWithManager(ctx, manager, opt)
+	if err != nil {
+		return fmt.Errorf("failed to setup MachineSetTopology: %w", err)
+	}
+
+	return nil
+}
+
 func setupMachineWithManager(ctx context.Context, manager ctrl.Manager,
 	clusterCache clustercache.ClusterCache, opt controller.Options,
 	remoteConnectionGracePeriod time.Duration) error {
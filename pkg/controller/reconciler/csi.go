@@ -0,0 +1,226 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// CSIConfigMapSuffix is the suffix for the ConfigMap that triggers the CSI driver installation.
+	CSIConfigMapSuffix = "-csi-driver-config"
+
+	// AnnotationCSIInstalled is stamped on the CSI ConfigMap with the chart and version
+	// whose install job succeeded.
+	AnnotationCSIInstalled = "kommodity.io/csi-installed"
+
+	// csiExtraValuesSecretSuffix is appended to the Cluster name to derive the Secret holding
+	// the extra Helm values of the chart.
+	csiExtraValuesSecretSuffix = "-csi-driver-extra-values" //nolint:gosec // secret-name suffix, not a credential
+
+	// csiControllerName is the controller name used to scope the filter label and the
+	// controller registration.
+	csiControllerName = "kommodity-csi-controller"
+	// csiRecorderName is the component the events of the controller are reported as.
+	csiRecorderName = "kommodity-csi"
+)
+
+// CSIReconciler installs a storage driver chart, like KubeVirt CSI or Longhorn, into
+// workload clusters. Like the AutoscalerReconciler it is driven by a labelled ConfigMap
+// per cluster. It follows the install job until it finishes and reports the outcome as
+// Events on the Cluster.
+type CSIReconciler struct {
+	client.Client
+
+	Recorder record.EventRecorder
+
+	// downstreamClient returns a client for the workload cluster, defaults to one built
+	// from the kubeconfig Secret of the cluster.
+	downstreamClient func(ctx context.Context, clusterName string) (client.Client, error)
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *CSIReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(csiRecorderName)
+	}
+
+	if r.downstreamClient == nil {
+		r.downstreamClient = r.fetchDownstreamClient
+	}
+
+	configMapPredicate := predicates.ResourceNotPausedAndHasFilterLabel(
+		mgr.GetScheme(),
+		zapr.NewLogger(logging.FromContext(ctx)),
+		csiControllerName,
+	)
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(csiControllerName).
+		For(&corev1.ConfigMap{}, ctrlbuilder.OnlyMetadata, ctrlbuilder.WithPredicates(configMapPredicate)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up CSI controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile installs the CSI driver of a cluster and follows its install job.
+//
+//nolint:funlen,cyclop // Reads the ConfigMap, waits for the downstream cluster, and follows the job.
+func (r *CSIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("configmap", req.String()))
+
+	configMap := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, req.NamespacedName, configMap)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	clusterName, success := configMap.Labels[clusterNameLabel]
+	if !success {
+		return ctrl.Result{}, fmt.Errorf("clusterName %w: %s", ErrValueNotFoundInConfigMap, req.String())
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger, client.ObjectKey{Namespace: configMap.Namespace, Name: clusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	for _, key := range []string{"namespace", "repository", "name"} {
+		if configMap.Data[key] == "" {
+			return ctrl.Result{}, fmt.Errorf("%s %w: cluster %s", key, ErrValueNotFoundInConfigMap, clusterName)
+		}
+	}
+
+	version := configMap.Data["version"]
+	if version == "" {
+		version = "latest"
+	}
+
+	jobConfig := NewHelmInstallConfig(configMap.Data["name"], configMap.Data["namespace"], configMap.Data["name"],
+		version, configMap.Data["repository"], false)
+	// The ConfigMap holds the wanted version, so a new version upgrades the release.
+	jobConfig.UpgradeDisabled = false
+
+	installed := jobConfig.Chart.Name + "@" + jobConfig.Chart.Version
+	if configMap.Annotations[AnnotationCSIInstalled] == installed {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Warn("Installing charts from a CSI driver ConfigMap is deprecated, use a ClusterAddon instead")
+
+	cluster := &clusterv1.Cluster{}
+
+	err = r.Get(ctx, client.ObjectKey{Namespace: configMap.Namespace, Name: clusterName}, cluster)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get Cluster %s: %w", clusterName, err)
+	}
+
+	kubeClient, err := r.downstreamClient(ctx, clusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Cluster kubeconfig not ready yet, requeuing", zap.Duration("requeueAfter", RequeueAfter))
+
+			return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to fetch kubeconfig from secret: %w", err)
+	}
+
+	err = CheckClusterReady(ctx, kubeClient)
+	if err != nil {
+		logger.Info("Downstream cluster not ready yet, requeuing", zap.Duration("requeueAfter", RequeueAfter))
+
+		//nolint:nilerr // intentionally return nil to avoid exponential backoff
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	job, err := helmJob(ctx, kubeClient, &jobConfig)
+	if apierrors.IsNotFound(err) {
+		err = r.applyJob(ctx, kubeClient, &jobConfig, configMap.Namespace, clusterName)
+		if err != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CSIDriverInstallFailed",
+				"Failed to start the installation of %s: %v", installed, err)
+
+			return ctrl.Result{}, err
+		}
+
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "CSIDriverInstalling", "Installing %s", installed)
+
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case job.Status.Succeeded > 0:
+		original := configMap.DeepCopy()
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+
+		configMap.Annotations[AnnotationCSIInstalled] = installed
+
+		err = r.Patch(ctx, configMap, client.MergeFrom(original))
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record installed CSI driver on ConfigMap %s: %w",
+				configMap.Name, err)
+		}
+
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "CSIDriverInstalled", "Installed %s", installed)
+
+		return ctrl.Result{}, nil
+	case jobFailed(job):
+		// The job retried on its own already; a new version in the ConfigMap starts a new job.
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CSIDriverInstallFailed",
+			"Install job of %s failed, see job %s/%s in the workload cluster", installed, job.Namespace, job.Name)
+
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+}
+
+// applyJob installs the chart with the extra values Secret of the cluster, if any.
+func (r *CSIReconciler) applyJob(ctx context.Context, kubeClient client.Client, jobConfig *Config,
+	namespace, clusterName string) error {
+	valuesSecret := &corev1.Secret{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName + csiExtraValuesSecretSuffix},
+		valuesSecret)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get extra values secret: %w", err)
+	}
+
+	return installHelmChart(ctx, kubeClient, jobConfig, valuesSecret.Data)
+}
+
+func (r *CSIReconciler) fetchDownstreamClient(ctx context.Context, clusterName string) (client.Client, error) {
+	return (&DownstreamClientConfig{
+		Client:      r.Client,
+		ClusterName: clusterName,
+	}).FetchDownstreamKubernetesClient(ctx)
+}
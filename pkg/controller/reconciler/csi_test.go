@@ -0,0 +1,158 @@
+//nolint:testpackage // white-box tests replace the downstream cluster client
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testCSIJobName = "longhorn-1-9-1-install"
+
+func buildCSIReconciler(t *testing.T) (*CSIReconciler, client.Client, *record.FakeRecorder) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, clusterv1.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPlanCluster + CSIConfigMapSuffix,
+			Namespace: testPlanNamespace,
+			Labels:    map[string]string{clusterNameLabel: testPlanCluster},
+		},
+		Data: map[string]string{
+			"namespace":  "longhorn-system",
+			"repository": "https://charts.longhorn.io",
+			"name":       "longhorn",
+			"version":    "1.9.1",
+		},
+	}
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace}}
+
+	kubeSystem := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceSystem}}
+	downstream := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithObjects(kubeSystem).
+		WithStatusSubresource(&batchv1.Job{}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+
+	return &CSIReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap, cluster).Build(),
+		Recorder: recorder,
+		downstreamClient: func(context.Context, string) (client.Client, error) {
+			return downstream, nil
+		},
+	}, downstream, recorder
+}
+
+func reconcileCSI(t *testing.T, reconciler *CSIReconciler) ctrl.Result {
+	t.Helper()
+
+	result, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{
+		Namespace: testPlanNamespace,
+		Name:      testPlanCluster + CSIConfigMapSuffix,
+	}})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	return result
+}
+
+func expectEvent(t *testing.T, recorder *record.FakeRecorder, reason string) {
+	t.Helper()
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, reason) {
+			t.Fatalf("expected a %s event, got %q", reason, event)
+		}
+	default:
+		t.Fatalf("expected a %s event", reason)
+	}
+}
+
+func setCSIJobStatus(t *testing.T, downstream client.Client, status batchv1.JobStatus) {
+	t.Helper()
+
+	job := &batchv1.Job{}
+
+	err := downstream.Get(t.Context(), client.ObjectKey{Namespace: "longhorn-system", Name: testCSIJobName}, job)
+	if err != nil {
+		t.Fatalf("expected the install job: %v", err)
+	}
+
+	job.Status = status
+
+	err = downstream.Status().Update(t.Context(), job)
+	if err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+}
+
+func TestCSIInstallSucceeds(t *testing.T) {
+	t.Parallel()
+
+	reconciler, downstream, recorder := buildCSIReconciler(t)
+
+	if result := reconcileCSI(t, reconciler); result.RequeueAfter != RequeueAfter {
+		t.Fatalf("expected to follow the job, got %+v", result)
+	}
+
+	expectEvent(t, recorder, "CSIDriverInstalling")
+
+	setCSIJobStatus(t, downstream, batchv1.JobStatus{Succeeded: 1})
+
+	if result := reconcileCSI(t, reconciler); result.RequeueAfter != 0 {
+		t.Fatalf("expected no requeue after the installation, got %+v", result)
+	}
+
+	expectEvent(t, recorder, "CSIDriverInstalled")
+
+	configMap := &corev1.ConfigMap{}
+
+	err := reconciler.Get(t.Context(), client.ObjectKey{
+		Namespace: testPlanNamespace,
+		Name:      testPlanCluster + CSIConfigMapSuffix,
+	}, configMap)
+	if err != nil || configMap.Annotations[AnnotationCSIInstalled] != "longhorn@1.9.1" {
+		t.Fatalf("expected the installed chart on the ConfigMap, got %+v: %v", configMap.Annotations, err)
+	}
+}
+
+func TestCSIInstallFails(t *testing.T) {
+	t.Parallel()
+
+	reconciler, downstream, recorder := buildCSIReconciler(t)
+
+	reconcileCSI(t, reconciler)
+	expectEvent(t, recorder, "CSIDriverInstalling")
+
+	setCSIJobStatus(t, downstream, batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+	}})
+
+	reconcileCSI(t, reconciler)
+	expectEvent(t, recorder, "CSIDriverInstallFailed")
+}
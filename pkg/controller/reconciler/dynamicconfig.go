@@ -0,0 +1,73 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const dynamicConfigControllerName = "kommodity-dynamic-config-controller"
+
+// DynamicConfigReconciler applies the kommodity-config ConfigMap in the Kommodity
+// namespace to the dynamic configuration, so that tunables such as the log level and
+// the rate limits change without restarting Kommodity. When the ConfigMap is deleted,
+// the tunables fall back to the environment.
+type DynamicConfigReconciler struct {
+	client.Client
+
+	Config *config.DynamicConfig
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *DynamicConfigReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up dynamic configuration reconciler")
+
+	isDynamicConfig := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == config.KommodityNamespace && obj.GetName() == config.DynamicConfigMapName
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(dynamicConfigControllerName).
+		For(&corev1.ConfigMap{}, builder.OnlyMetadata, builder.WithPredicates(isDynamicConfig)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up dynamic configuration controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile applies the data of the ConfigMap. Invalid data is reported and not
+// retried, the ConfigMap has to be fixed.
+func (r *DynamicConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, req.NamespacedName, configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get dynamic configuration: %w", err)
+	}
+
+	// A deleted ConfigMap leaves an empty object, which restores the environment.
+	err = r.Config.Apply(ctx, configMap.Data)
+	if err != nil {
+		logger.Error("Rejected dynamic configuration, keeping the previous one",
+			zap.String("configMap", req.String()), zap.Error(err))
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,143 @@
+//nolint:testpackage // white-box tests exercise the dynamic configuration reconciler with a fake client
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildDynamicConfigReconciler(t *testing.T, data map[string]string) *DynamicConfigReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	err := corev1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding to scheme: %v", err)
+	}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	if data != nil {
+		builder = builder.WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: config.DynamicConfigMapName, Namespace: config.KommodityNamespace},
+			Data:       data,
+		})
+	}
+
+	return &DynamicConfigReconciler{
+		Client: builder.Build(),
+		Config: config.NewDynamicConfig(config.Tunables{
+			LogLevel:              zapcore.InfoLevel,
+			RateLimit:             config.RateLimitConfig{UserQPS: 10, UserBurst: 20},
+			ClusterHealthInterval: time.Minute,
+		}),
+	}
+}
+
+func reconcileDynamicConfig(t *testing.T, reconciler *DynamicConfigReconciler) {
+	t.Helper()
+
+	key := types.NamespacedName{Name: config.DynamicConfigMapName, Namespace: config.KommodityNamespace}
+
+	result, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	if !result.IsZero() {
+		t.Fatalf("expected no requeue, got %+v", result)
+	}
+}
+
+func TestDynamicConfigAppliesTunables(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildDynamicConfigReconciler(t, map[string]string{
+		"LOG_LEVEL":                          "debug",
+		"KOMMODITY_RATE_LIMIT_IP_QPS":        "2.5",
+		"KOMMODITY_RATE_LIMIT_IP_BURST":      "5",
+		"KOMMODITY_CLUSTER_HEALTH_INTERVAL":  "30s",
+		"KOMMODITY_INFRASTRUCTURE_PROVIDERS": "docker",
+	})
+
+	var notified []config.Tunables
+
+	reconciler.Config.Subscribe(func(tunables config.Tunables) {
+		notified = append(notified, tunables)
+	})
+
+	reconcileDynamicConfig(t, reconciler)
+
+	expected := config.Tunables{
+		LogLevel:              zapcore.DebugLevel,
+		RateLimit:             config.RateLimitConfig{UserQPS: 10, UserBurst: 20, IPQPS: 2.5, IPBurst: 5},
+		ClusterHealthInterval: 30 * time.Second,
+	}
+
+	if current := reconciler.Config.Current(); current != expected {
+		t.Fatalf("expected tunables %+v, got %+v", expected, current)
+	}
+
+	if len(notified) != 2 || notified[1] != expected {
+		t.Fatalf("expected the subscriber to be notified of the change, got %+v", notified)
+	}
+
+	// Reconciling unchanged data does not notify again.
+	reconcileDynamicConfig(t, reconciler)
+
+	if len(notified) != 2 {
+		t.Fatalf("expected no notification without a change, got %d", len(notified))
+	}
+}
+
+func TestDynamicConfigRejectsInvalidData(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildDynamicConfigReconciler(t, map[string]string{
+		"LOG_LEVEL":                         "debug",
+		"KOMMODITY_CLUSTER_HEALTH_INTERVAL": "-1m",
+	})
+
+	before := reconciler.Config.Current()
+
+	reconcileDynamicConfig(t, reconciler)
+
+	if current := reconciler.Config.Current(); current != before {
+		t.Fatalf("expected invalid data to be rejected as a whole, got %+v", current)
+	}
+}
+
+func TestDynamicConfigFallsBackWhenDeleted(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildDynamicConfigReconciler(t, map[string]string{"LOG_LEVEL": "error"})
+	defaults := reconciler.Config.Current()
+
+	reconcileDynamicConfig(t, reconciler)
+
+	if reconciler.Config.Current().LogLevel != zapcore.ErrorLevel {
+		t.Fatalf("expected the log level to be applied, got %s", reconciler.Config.Current().LogLevel)
+	}
+
+	err := reconciler.Delete(t.Context(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.DynamicConfigMapName, Namespace: config.KommodityNamespace},
+	})
+	if err != nil {
+		t.Fatalf("failed to delete ConfigMap: %v", err)
+	}
+
+	reconcileDynamicConfig(t, reconciler)
+
+	if current := reconciler.Config.Current(); current != defaults {
+		t.Fatalf("expected the tunables to fall back to %+v, got %+v", defaults, current)
+	}
+}
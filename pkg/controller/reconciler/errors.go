@@ -25,4 +25,31 @@ var (
 	// cluster's teardown, garbage collect a Secret the other cluster still depends on. This is the
 	// signature of a values file copied from another cluster without updating provider.secret.name.
 	ErrSecretOwnedByAnotherCluster = errors.New("secret is materialized for another cluster")
+	// ErrIdentityNamespaceNotAllowed is returned when an AzureCluster refers to an AzureClusterIdentity
+	// in another namespace whose allowedNamespaces do not include the namespace of the cluster.
+	ErrIdentityNamespaceNotAllowed = errors.New("azure cluster identity does not allow the namespace")
+	// ErrMachineHasNoAddress is returned when a Machine does not report an address to reach its node.
+	ErrMachineHasNoAddress = errors.New("machine has no address")
+	// ErrNodeVersionUnknown is returned when a node does not report its Talos version.
+	ErrNodeVersionUnknown = errors.New("node did not report its version")
+	// ErrNoControlPlaneMachine is returned when a cluster has no reachable control plane Machine.
+	ErrNoControlPlaneMachine = errors.New("no reachable control plane machine")
+	// ErrEtcdMemberUnhealthy is returned when a node reports errors for its etcd member.
+	ErrEtcdMemberUnhealthy = errors.New("etcd member is unhealthy")
+	// ErrUnknownCNI is returned when a Cluster selects a CNI preset that does not exist.
+	ErrUnknownCNI = errors.New("unknown CNI preset")
+	// ErrHelmReleaseNotFound is returned when a workload cluster has no release of an add-on.
+	ErrHelmReleaseNotFound = errors.New("helm release not found")
+	// ErrHelmUninstallFailed is returned when the Helm uninstall job of a chart gave up.
+	ErrHelmUninstallFailed = errors.New("helm uninstall job failed")
+	// ErrInvalidTenantTemplate is returned when a TenantTemplate cannot be rendered or decoded.
+	ErrInvalidTenantTemplate = errors.New("invalid tenant template")
+	// ErrInvalidClusterTemplate is returned when a ClusterTemplate cannot be rendered, decoded or created.
+	ErrInvalidClusterTemplate = errors.New("invalid cluster template")
+	// ErrInvalidClusterInstanceValues is returned when the values of a ClusterInstance do not match its template.
+	ErrInvalidClusterInstanceValues = errors.New("invalid cluster instance values")
+	// ErrTenantObjectMissingKindOrName is returned when a TenantTemplate manifest lacks a kind or name.
+	ErrTenantObjectMissingKindOrName = errors.New("tenant template object must set kind and metadata.name")
+	// ErrTenantObjectOutsideNamespace is returned when a TenantTemplate manifest targets another namespace.
+	ErrTenantObjectOutsideNamespace = errors.New("tenant template object targets another namespace")
 )
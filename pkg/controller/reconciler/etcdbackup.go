@@ -0,0 +1,267 @@
+package reconciler
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	controlplanev1 "github.com/siderolabs/cluster-api-control-plane-provider-talos/api/v1alpha3"
+	machineapi "github.com/siderolabs/talos/pkg/machinery/api/machine"
+	"go.uber.org/zap"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// AnnotationEtcdBackupInterval enables etcd backups of the cluster of a TalosControlPlane
+	// and sets how often they are taken, as a Go duration like "6h".
+	AnnotationEtcdBackupInterval = "kommodity.io/etcd-backup-interval"
+	// AnnotationEtcdBackupLastSuccess is the RFC 3339 time of the last uploaded etcd snapshot.
+	AnnotationEtcdBackupLastSuccess = "kommodity.io/etcd-backup-last-success"
+
+	// EtcdBackupSucceededCondition reports whether the last etcd backup of the cluster succeeded.
+	EtcdBackupSucceededCondition clusterv1.ConditionType = "EtcdBackupSucceeded"
+	// EtcdBackupFailedReason is the reason of a failed etcd backup.
+	EtcdBackupFailedReason = "EtcdBackupFailed"
+
+	// etcdBackupControllerName is the name used to register the controller.
+	etcdBackupControllerName = "kommodity-etcd-backup-controller"
+
+	// etcdSnapshotTimeout bounds streaming a snapshot from a node.
+	etcdSnapshotTimeout = 5 * time.Minute
+	// etcdBackupRetryInterval is how long to wait before retrying a failed backup.
+	etcdBackupRetryInterval = 5 * time.Minute
+)
+
+// EtcdSnapshotStore stores etcd snapshots in object storage.
+type EtcdSnapshotStore interface {
+	PutObject(ctx context.Context, name string, body []byte) error
+}
+
+// EtcdBackupReconciler periodically takes etcd snapshots of workload clusters through the
+// Talos API of a control plane node and uploads them to object storage. Backups are
+// enabled per cluster with an annotation on its TalosControlPlane, which also carries
+// the time of the last success; the outcome is reported as a condition.
+type EtcdBackupReconciler struct {
+	client.Client
+
+	Store EtcdSnapshotStore
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *EtcdBackupReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up etcd backup reconciler")
+
+	hasInterval := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetAnnotations()[AnnotationEtcdBackupInterval] != ""
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(etcdBackupControllerName).
+		For(&controlplanev1.TalosControlPlane{}, builder.WithPredicates(hasInterval)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up etcd backup controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile takes a backup of the cluster of a TalosControlPlane when one is due.
+func (r *EtcdBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("talosControlPlane", req.String()))
+
+	controlPlane := &controlplanev1.TalosControlPlane{}
+
+	err := r.Get(ctx, req.NamespacedName, controlPlane)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	value := controlPlane.Annotations[AnnotationEtcdBackupInterval]
+	if value == "" || !controlPlane.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		logger.Error("Invalid etcd backup interval, skipping backups",
+			zap.String("annotation", AnnotationEtcdBackupInterval),
+			zap.String("value", value))
+
+		return ctrl.Result{}, nil
+	}
+
+	lastSuccess, _ := time.Parse(time.RFC3339, controlPlane.Annotations[AnnotationEtcdBackupLastSuccess])
+	if wait := time.Until(lastSuccess.Add(interval)); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, controlPlane.ObjectMeta)
+	if err != nil || cluster == nil {
+		logger.Info("Cluster of TalosControlPlane not found yet, requeuing", zap.Error(err))
+
+		//nolint:nilerr // the owner reference is set by CAPI shortly after creation.
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	helper, err := patch.NewHelper(controlPlane, r.Client)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create patch helper: %w", err)
+	}
+
+	result := ctrl.Result{RequeueAfter: interval}
+
+	name, err := r.backup(ctx, cluster)
+	if err != nil {
+		logger.Error("Etcd backup failed", zap.Error(err))
+		conditions.MarkFalse(controlPlane, EtcdBackupSucceededCondition, EtcdBackupFailedReason,
+			clusterv1.ConditionSeverityWarning, "%s", err.Error())
+
+		result = ctrl.Result{RequeueAfter: etcdBackupRetryInterval}
+	} else {
+		logger.Info("Uploaded etcd snapshot", zap.String("name", name))
+		conditions.MarkTrue(controlPlane, EtcdBackupSucceededCondition)
+		controlPlane.Annotations[AnnotationEtcdBackupLastSuccess] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	// Only the backup condition is ours, the rest of the status belongs to CACPPT.
+	err = helper.Patch(ctx, controlPlane,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{EtcdBackupSucceededCondition}})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch TalosControlPlane %s: %w", controlPlane.Name, err)
+	}
+
+	return result, nil
+}
+
+// backup uploads a snapshot taken from the first control plane node that provides one
+// and returns its object name.
+func (r *EtcdBackupReconciler) backup(ctx context.Context, cluster *clusterv1.Cluster) (string, error) {
+	machines, err := r.controlPlaneMachines(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	if len(machines) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoControlPlaneMachine, cluster.Name)
+	}
+
+	var errs []error
+
+	for _, machine := range machines {
+		snapshot, err := r.snapshot(ctx, machine)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("machine %s: %w", machine.Name, err))
+
+			continue
+		}
+
+		name := path.Join("etcd", cluster.Namespace, cluster.Name,
+			time.Now().UTC().Format("20060102T150405Z")+".db.gz")
+
+		err = r.Store.PutObject(ctx, name, snapshot)
+		if err != nil {
+			return "", fmt.Errorf("failed to upload etcd snapshot: %w", err)
+		}
+
+		return name, nil
+	}
+
+	return "", errors.Join(errs...)
+}
+
+// snapshot streams an etcd snapshot from the node of the Machine and compresses it.
+func (r *EtcdBackupReconciler) snapshot(ctx context.Context, machine *clusterv1.Machine) ([]byte, error) {
+	talosClient, err := TalosClientForMachine(ctx, r, machine)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = talosClient.Close() }()
+
+	callCtx, cancel := context.WithTimeout(ctx, etcdSnapshotTimeout)
+	defer cancel()
+
+	reader, err := talosClient.EtcdSnapshot(callCtx, &machineapi.EtcdSnapshotRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request etcd snapshot: %w", err)
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err = io.Copy(writer, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read etcd snapshot: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress etcd snapshot: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// controlPlaneMachines returns the control plane Machines of the cluster that can be
+// reached, healthy nodes first.
+func (r *EtcdBackupReconciler) controlPlaneMachines(ctx context.Context,
+	cluster *clusterv1.Cluster) ([]*clusterv1.Machine, error) {
+	machineList := &clusterv1.MachineList{}
+
+	err := r.List(ctx, machineList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name},
+		client.HasLabels{clusterv1.MachineControlPlaneLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list control plane Machines of Cluster %s: %w", cluster.Name, err)
+	}
+
+	machines := make([]*clusterv1.Machine, 0, len(machineList.Items))
+
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if machine.DeletionTimestamp.IsZero() && machineAddress(machine) != "" {
+			machines = append(machines, machine)
+		}
+	}
+
+	slices.SortFunc(machines, func(a, b *clusterv1.Machine) int {
+		aHealthy := conditions.IsTrue(a, clusterv1.MachineNodeHealthyCondition)
+		bHealthy := conditions.IsTrue(b, clusterv1.MachineNodeHealthyCondition)
+
+		if aHealthy != bHealthy {
+			if aHealthy {
+				return -1
+			}
+
+			return 1
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return machines, nil
+}
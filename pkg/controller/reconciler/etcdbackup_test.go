@@ -0,0 +1,139 @@
+//nolint:testpackage // white-box tests share the etcd backup annotations of the package
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	controlplanev1 "github.com/siderolabs/cluster-api-control-plane-provider-talos/api/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeEtcdSnapshotStore struct {
+	names []string
+}
+
+func (s *fakeEtcdSnapshotStore) PutObject(_ context.Context, name string, _ []byte) error {
+	s.names = append(s.names, name)
+
+	return nil
+}
+
+func buildEtcdBackupReconciler(t *testing.T, annotations map[string]string) (*EtcdBackupReconciler,
+	*fakeEtcdSnapshotStore) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{clusterv1.AddToScheme, controlplanev1.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	controlPlane := &controlplanev1.TalosControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "prod-control-plane",
+			Namespace:   testPlanNamespace,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       testPlanCluster,
+			}},
+		},
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace},
+	}
+
+	store := &fakeEtcdSnapshotStore{}
+
+	return &EtcdBackupReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(controlPlane, cluster).
+			WithStatusSubresource(controlPlane).
+			Build(),
+		Store: store,
+	}, store
+}
+
+func reconcileEtcdBackup(t *testing.T, reconciler *EtcdBackupReconciler) (ctrl.Result,
+	*controlplanev1.TalosControlPlane) {
+	t.Helper()
+
+	key := types.NamespacedName{Namespace: testPlanNamespace, Name: "prod-control-plane"}
+
+	result, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	controlPlane := &controlplanev1.TalosControlPlane{}
+
+	err = reconciler.Get(t.Context(), key, controlPlane)
+	if err != nil {
+		t.Fatalf("failed to get TalosControlPlane: %v", err)
+	}
+
+	return result, controlPlane
+}
+
+func TestEtcdBackupWaitsForInterval(t *testing.T) {
+	t.Parallel()
+
+	reconciler, store := buildEtcdBackupReconciler(t, map[string]string{
+		AnnotationEtcdBackupInterval:    "6h",
+		AnnotationEtcdBackupLastSuccess: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+
+	result, _ := reconcileEtcdBackup(t, reconciler)
+
+	if result.RequeueAfter < 4*time.Hour || result.RequeueAfter > 5*time.Hour || len(store.names) != 0 {
+		t.Fatalf("expected the next backup in about 5h, got %s", result.RequeueAfter)
+	}
+}
+
+func TestEtcdBackupIgnoresInvalidInterval(t *testing.T) {
+	t.Parallel()
+
+	reconciler, store := buildEtcdBackupReconciler(t, map[string]string{AnnotationEtcdBackupInterval: "daily"})
+
+	result, _ := reconcileEtcdBackup(t, reconciler)
+	if result.RequeueAfter != 0 || len(store.names) != 0 {
+		t.Fatalf("expected no backup for an invalid interval, got %+v", result)
+	}
+}
+
+func TestEtcdBackupReportsFailure(t *testing.T) {
+	t.Parallel()
+
+	reconciler, store := buildEtcdBackupReconciler(t, map[string]string{AnnotationEtcdBackupInterval: "6h"})
+
+	result, controlPlane := reconcileEtcdBackup(t, reconciler)
+
+	if result.RequeueAfter != etcdBackupRetryInterval || len(store.names) != 0 {
+		t.Fatalf("expected a retry without upload, got %+v", result)
+	}
+
+	condition := conditions.Get(controlPlane, EtcdBackupSucceededCondition)
+	if condition == nil || condition.Reason != EtcdBackupFailedReason ||
+		!strings.Contains(condition.Message, ErrNoControlPlaneMachine.Error()) {
+		t.Fatalf("expected a failed backup condition, got %+v", condition)
+	}
+
+	if controlPlane.Annotations[AnnotationEtcdBackupLastSuccess] != "" {
+		t.Fatalf("expected no last success time after a failure")
+	}
+}
@@ -3,13 +3,20 @@ package reconciler
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"html/template"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/sprig/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +27,15 @@ const (
 	InstallModeHelmInstall = "HelmInstall"
 	// InstallModeKubectlApply indicates that the Helm chart should be installed using kubectl apply.
 	InstallModeKubectlApply = "KubectlApply"
+
+	// JobActionInstall installs or upgrades the chart.
+	JobActionInstall = "install"
+	// JobActionUninstall removes the chart.
+	JobActionUninstall = "uninstall"
+
+	// AnnotationValuesHash is stamped on the Helm job with the hash of the values it was
+	// rendered with, so that a job is only replaced when the chart or its values change.
+	AnnotationValuesHash = "kommodity.io/values-hash"
 )
 
 //go:embed job.tmpl
@@ -34,6 +50,16 @@ type Config struct {
 	Condition       Condition
 	UpgradeDisabled bool
 	HasExtraValues  bool
+	// HostNetwork runs the job on the host network of a control plane node, which is
+	// always the case in kube-system. Needed while the cluster has no CNI yet.
+	HostNetwork bool
+	// Action is JobActionInstall or JobActionUninstall. Defaults to JobActionInstall.
+	Action string
+	// ValuesHash is the hash of the extra values, see hashValues.
+	ValuesHash string
+	// RollbackOnFailure rolls a release back to its last deployed revision when an
+	// upgrade or one of its hooks fails.
+	RollbackOnFailure bool
 }
 
 // Chart holds the Helm chart information.
@@ -114,6 +140,9 @@ func (c *Config) getTemplatedJob() (string, error) {
 	funcs["getFullName"] = func() string {
 		return c.getFullName()
 	}
+	funcs["jobName"] = func() string {
+		return c.jobName()
+	}
 
 	tpl := template.Must(template.New("job.tmpl").
 		Funcs(funcs).
@@ -132,3 +161,184 @@ func (c *Config) getTemplatedJob() (string, error) {
 func (c *Config) getFullName() string {
 	return fmt.Sprintf("%s-%s", c.Name, strings.ReplaceAll(c.Chart.Version, ".", "-"))
 }
+
+func (c *Config) jobName() string {
+	action := c.Action
+	if action == "" {
+		action = JobActionInstall
+	}
+
+	return c.getFullName() + "-" + action
+}
+
+// installHelmChart creates the namespace of the chart in the downstream cluster, delivers
+// its values and applies the Helm install job. Without values the chart defaults apply.
+func installHelmChart(ctx context.Context, kubeClient client.Client, jobConfig *Config,
+	values map[string][]byte) error {
+	err := ensurePrivilegedNamespace(ctx, kubeClient, jobConfig.Namespace)
+	if err != nil {
+		return err
+	}
+
+	jobConfig.HasExtraValues = len(values) > 0
+	jobConfig.ValuesHash = hashValues(values)
+
+	if jobConfig.HasExtraValues {
+		err = ApplySecretToClient(ctx, kubeClient, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: jobConfig.Name + "-extra-values", Namespace: jobConfig.Namespace},
+			Data:       values,
+			Type:       corev1.SecretTypeOpaque,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply values of %s to downstream cluster: %w", jobConfig.Name, err)
+		}
+	}
+
+	err = jobConfig.ApplyTemplate(ctx, kubeClient)
+	if err != nil {
+		return fmt.Errorf("failed to apply Helm install job of %s: %w", jobConfig.Name, err)
+	}
+
+	return nil
+}
+
+// upgradeHelmChart installs or upgrades the chart unless the install job of the same
+// chart version and values exists already. It reports whether a new job was started.
+func upgradeHelmChart(ctx context.Context, kubeClient client.Client, jobConfig *Config,
+	values map[string][]byte) (bool, error) {
+	job, err := helmJob(ctx, kubeClient, jobConfig)
+	if client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+
+	if err == nil && job.Annotations[AnnotationValuesHash] == hashValues(values) {
+		return false, nil
+	}
+
+	// The job name only changes with the chart version, so an earlier job is replaced.
+	err = deleteHelmJob(ctx, kubeClient, jobConfig)
+	if err != nil {
+		return false, err
+	}
+
+	err = installHelmChart(ctx, kubeClient, jobConfig, values)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// uninstallHelmChart starts the uninstall job of the chart and reports whether it
+// succeeded. The install job and the values of the chart are removed afterwards.
+func uninstallHelmChart(ctx context.Context, kubeClient client.Client, jobConfig Config) (bool, error) {
+	err := kubeClient.Get(ctx, client.ObjectKey{Name: jobConfig.Namespace}, &corev1.Namespace{})
+	if apierrors.IsNotFound(err) {
+		// The chart was never installed.
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get namespace %s: %w", jobConfig.Namespace, err)
+	}
+
+	jobConfig.Action = JobActionUninstall
+
+	job, err := helmJob(ctx, kubeClient, &jobConfig)
+	if apierrors.IsNotFound(err) {
+		err = jobConfig.ApplyTemplate(ctx, kubeClient)
+		if err != nil {
+			return false, fmt.Errorf("failed to apply Helm uninstall job of %s: %w", jobConfig.Name, err)
+		}
+
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if jobFailed(job) {
+		return false, fmt.Errorf("%w: job %s/%s", ErrHelmUninstallFailed, job.Namespace, job.Name)
+	}
+
+	if job.Status.Succeeded == 0 {
+		return false, nil
+	}
+
+	jobConfig.Action = JobActionInstall
+
+	err = deleteHelmJob(ctx, kubeClient, &jobConfig)
+	if err != nil {
+		return false, err
+	}
+
+	err = kubeClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      jobConfig.Name + "-extra-values",
+		Namespace: jobConfig.Namespace,
+	}})
+	if client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("failed to delete values of %s: %w", jobConfig.Name, err)
+	}
+
+	return true, nil
+}
+
+// helmJob returns the Helm job of the chart and action in the downstream cluster.
+func helmJob(ctx context.Context, kubeClient client.Client, jobConfig *Config) (*batchv1.Job, error) {
+	job := &batchv1.Job{}
+
+	err := kubeClient.Get(ctx, client.ObjectKey{
+		Namespace: jobConfig.Namespace,
+		Name:      jobConfig.jobName(),
+	}, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Helm job of %s: %w", jobConfig.Name, err)
+	}
+
+	return job, nil
+}
+
+// deleteHelmJob removes the Helm job of the chart and action, if any.
+func deleteHelmJob(ctx context.Context, kubeClient client.Client, jobConfig *Config) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobConfig.jobName(),
+			Namespace: jobConfig.Namespace,
+		},
+	}
+
+	err := kubeClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete Helm job of %s: %w", jobConfig.Name, err)
+	}
+
+	return nil
+}
+
+// hashValues returns a hash of the chart values, empty without values.
+func hashValues(values map[string][]byte) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	hash := sha256.New()
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		hash.Write([]byte(key))
+		hash.Write(values[key])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// jobFailed reports whether the job gave up.
+func jobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+//nolint:testpackage // white-box tests render the unexported job template
+package reconciler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelmJobTemplateActions(t *testing.T) {
+	t.Parallel()
+
+	jobConfig := NewHelmInstallConfig("metrics-server", "kube-system", "metrics-server", "3.12.2",
+		"https://kubernetes-sigs.github.io/metrics-server/", false)
+	jobConfig.RollbackOnFailure = true
+	jobConfig.ValuesHash = hashValues(map[string][]byte{"values.yaml": []byte("replicas: 2\n")})
+
+	install, err := jobConfig.getTemplatedJob()
+	if err != nil {
+		t.Fatalf("failed to render install job: %v", err)
+	}
+
+	for _, want := range []string{
+		"name: metrics-server-3-12-2-install",
+		`ACTION="install"`,
+		`ROLLBACK_ON_FAILURE="true"`,
+		AnnotationValuesHash + `: "` + jobConfig.ValuesHash + `"`,
+	} {
+		if !strings.Contains(install, want) {
+			t.Fatalf("expected %q in the install job:\n%s", want, install)
+		}
+	}
+
+	jobConfig.Action = JobActionUninstall
+
+	uninstall, err := jobConfig.getTemplatedJob()
+	if err != nil {
+		t.Fatalf("failed to render uninstall job: %v", err)
+	}
+
+	for _, want := range []string{"name: metrics-server-3-12-2-uninstall", "name: metrics-server-uninstall"} {
+		if !strings.Contains(uninstall, want) {
+			t.Fatalf("expected %q in the uninstall job:\n%s", want, uninstall)
+		}
+	}
+}
+
+func TestHashValuesIgnoresKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	if hashValues(nil) != "" {
+		t.Fatalf("expected no hash without values")
+	}
+
+	first := hashValues(map[string][]byte{"a.yaml": []byte("a"), "b.yaml": []byte("b")})
+	second := hashValues(map[string][]byte{"b.yaml": []byte("b"), "a.yaml": []byte("a")})
+
+	if first != second {
+		t.Fatalf("expected the same hash, got %s and %s", first, second)
+	}
+
+	if first == hashValues(map[string][]byte{"a.yaml": []byte("b"), "b.yaml": []byte("a")}) {
+		t.Fatalf("expected different values to change the hash")
+	}
+}
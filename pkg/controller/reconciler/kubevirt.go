@@ -55,6 +55,14 @@ func setupKubevirt(ctx context.Context, manager ctrl.Manager, options controller
 		return fmt.Errorf("failed to setup KubevirtMachine controller: %w", err)
 	}
 
+	err = (&KubevirtDiagnosticsReconciler{
+		Client:       manager.GetClient(),
+		InfraCluster: infracluster.New(manager.GetClient(), noCachedClient),
+	}).SetupWithManager(ctx, manager, options)
+	if err != nil {
+		return fmt.Errorf("failed to setup KubeVirt diagnostics reconciler: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,280 @@
+package reconciler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// AnnotationPowerState is the phase of the KubeVirt VirtualMachineInstance of a
+	// Machine, or "Stopped" when the VirtualMachine has no instance.
+	AnnotationPowerState = "kommodity.io/power-state"
+	// AnnotationGuestOS is the operating system and kernel the guest agent of the
+	// VirtualMachineInstance of a Machine reports.
+	AnnotationGuestOS = "kommodity.io/guest-os"
+	// AnnotationBootDiagnostics is the latest warning event of the KubeVirt VirtualMachine
+	// of a Machine, its instance or its launcher Pod.
+	AnnotationBootDiagnostics = "kommodity.io/boot-diagnostics"
+
+	// VirtualMachineRunningCondition reports whether the VirtualMachineInstance of a
+	// Machine is running, and otherwise why not.
+	VirtualMachineRunningCondition clusterv1.ConditionType = "VirtualMachineRunning"
+
+	// PowerStateStopped is the power state of a Machine whose VirtualMachine has no instance.
+	PowerStateStopped = "Stopped"
+
+	// kubevirtDiagnosticsControllerName is the name used to register the controller.
+	kubevirtDiagnosticsControllerName = "kommodity-kubevirt-diagnostics-controller"
+	// kubevirtDiagnosticsInterval is how often the infra cluster is looked at, whose
+	// objects are not watched.
+	kubevirtDiagnosticsInterval = time.Minute
+	// involvedObjectNameField selects the Events of an object.
+	involvedObjectNameField = "involvedObject.name"
+)
+
+// KubevirtDiagnosticsReconciler mirrors the state of the KubeVirt VirtualMachineInstance
+// of a KubevirtMachine into the annotations and a condition of its Machine: the power
+// state, what the guest agent reports and the latest warning event of the virtual
+// machine, its instance or its launcher Pod. Users can thus see why a machine is stuck without access to the
+// infra cluster.
+type KubevirtDiagnosticsReconciler struct {
+	client.Client
+
+	InfraCluster infracluster.InfraCluster
+}
+
+// kubevirtDiagnostics is what is known about the VirtualMachineInstance of a Machine.
+type kubevirtDiagnostics struct {
+	powerState     string
+	agentConnected bool
+	guestOS        string
+	lastWarning    string
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *KubevirtDiagnosticsReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up KubeVirt diagnostics reconciler")
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(kubevirtDiagnosticsControllerName).
+		For(&infrav1.KubevirtMachine{}).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up KubeVirt diagnostics controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile mirrors the VirtualMachineInstance of a single KubevirtMachine into its Machine.
+func (r *KubevirtDiagnosticsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("kubevirtMachine", req.String()))
+
+	kubevirtMachine := &infrav1.KubevirtMachine{}
+
+	err := r.Get(ctx, req.NamespacedName, kubevirtMachine)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !kubevirtMachine.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, r.Client, kubevirtMachine.ObjectMeta)
+	if err != nil || machine == nil {
+		logger.Info("Machine of KubevirtMachine not found yet, requeuing", zap.Error(err))
+
+		//nolint:nilerr // the owner reference is set by CAPI shortly after creation.
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	infraClient, infraNamespace, err := r.InfraCluster.GenerateInfraClusterClient(
+		kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create client of the infra cluster of KubevirtMachine %s: %w",
+			req, err)
+	}
+
+	// As CAPK, place the VirtualMachine in the namespace of its template, if any.
+	vmNamespace := cmp.Or(kubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Namespace, infraNamespace)
+
+	diagnostics, err := diagnoseVirtualMachine(ctx, infraClient,
+		client.ObjectKey{Namespace: vmNamespace, Name: kubevirtMachine.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.patchMachine(ctx, machine, diagnostics)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: kubevirtDiagnosticsInterval}, nil
+}
+
+func (r *KubevirtDiagnosticsReconciler) patchMachine(ctx context.Context,
+	machine *clusterv1.Machine, diagnostics *kubevirtDiagnostics) error {
+	helper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return fmt.Errorf("failed to create patch helper: %w", err)
+	}
+
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+
+	for annotation, value := range map[string]string{
+		AnnotationPowerState:      diagnostics.powerState,
+		AnnotationGuestOS:         diagnostics.guestOS,
+		AnnotationBootDiagnostics: diagnostics.lastWarning,
+	} {
+		if value != "" {
+			machine.Annotations[annotation] = value
+		} else {
+			delete(machine.Annotations, annotation)
+		}
+	}
+
+	if diagnostics.powerState == string(kubevirtv1.Running) {
+		conditions.MarkTrue(machine, VirtualMachineRunningCondition)
+	} else {
+		severity := clusterv1.ConditionSeverityInfo
+		if diagnostics.powerState == string(kubevirtv1.Failed) || diagnostics.lastWarning != "" {
+			severity = clusterv1.ConditionSeverityWarning
+		}
+
+		conditions.MarkFalse(machine, VirtualMachineRunningCondition, diagnostics.powerState, severity,
+			"%s", diagnostics.lastWarning)
+	}
+
+	// Only the diagnostics condition is ours, the rest of the status belongs to CAPI.
+	err = helper.Patch(ctx, machine,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{VirtualMachineRunningCondition}})
+	if err != nil {
+		return fmt.Errorf("failed to patch Machine %s: %w", machine.Name, err)
+	}
+
+	return nil
+}
+
+// diagnoseVirtualMachine reads the VirtualMachineInstance, its launcher Pods and the
+// Events of the VirtualMachine, its instance and their Pods from the infra cluster. The
+// VirtualMachine and its instance share their name.
+func diagnoseVirtualMachine(ctx context.Context, infraClient client.Client,
+	key client.ObjectKey) (*kubevirtDiagnostics, error) {
+	diagnostics := &kubevirtDiagnostics{powerState: PowerStateStopped}
+	objectNames := []string{key.Name}
+
+	vmi := &kubevirtv1.VirtualMachineInstance{}
+
+	err := infraClient.Get(ctx, key, vmi)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get VirtualMachineInstance %s: %w", key, err)
+	}
+
+	if err == nil {
+		launcherPods, err := diagnoseVirtualMachineInstance(ctx, infraClient, vmi, diagnostics)
+		if err != nil {
+			return nil, err
+		}
+
+		objectNames = append(objectNames, launcherPods...)
+	}
+
+	var latest *corev1.Event
+
+	for _, name := range objectNames {
+		events := &corev1.EventList{}
+
+		err = infraClient.List(ctx, events, client.InNamespace(key.Namespace),
+			client.MatchingFields{involvedObjectNameField: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Events of %s/%s: %w", key.Namespace, name, err)
+		}
+
+		for i := range events.Items {
+			event := &events.Items[i]
+
+			if event.Type == corev1.EventTypeWarning && (latest == nil || eventTime(event).After(eventTime(latest))) {
+				latest = event
+			}
+		}
+	}
+
+	if latest != nil {
+		diagnostics.lastWarning = fmt.Sprintf("%s: %s", latest.Reason, latest.Message)
+	}
+
+	return diagnostics, nil
+}
+
+// diagnoseVirtualMachineInstance records the phase and guest agent information of the
+// instance, and returns the names of its launcher Pods.
+func diagnoseVirtualMachineInstance(ctx context.Context, infraClient client.Client,
+	vmi *kubevirtv1.VirtualMachineInstance, diagnostics *kubevirtDiagnostics) ([]string, error) {
+	diagnostics.powerState = string(cmp.Or(vmi.Status.Phase, kubevirtv1.Pending))
+
+	for _, condition := range vmi.Status.Conditions {
+		if condition.Type == kubevirtv1.VirtualMachineInstanceAgentConnected {
+			diagnostics.agentConnected = condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	if guestOS := vmi.Status.GuestOSInfo; diagnostics.agentConnected && guestOS.PrettyName != "" {
+		diagnostics.guestOS = guestOS.PrettyName
+		if guestOS.KernelRelease != "" {
+			diagnostics.guestOS += " (kernel " + guestOS.KernelRelease + ")"
+		}
+	}
+
+	pods := &corev1.PodList{}
+
+	err := infraClient.List(ctx, pods, client.InNamespace(vmi.Namespace),
+		client.MatchingLabels{kubevirtv1.CreatedByLabel: string(vmi.UID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list launcher Pods of VirtualMachineInstance %s/%s: %w",
+			vmi.Namespace, vmi.Name, err)
+	}
+
+	names := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+
+	return names, nil
+}
+
+// eventTime is when an Event was last seen, by the time of the events API if set.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,191 @@
+//nolint:testpackage // white-box tests replace the infra cluster client
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testInfraNamespace = "vms"
+
+// staticInfraCluster hands out the same infra cluster client for every KubevirtMachine.
+type staticInfraCluster struct {
+	client client.Client
+}
+
+func (s *staticInfraCluster) GenerateInfraClusterClient(_ *corev1.ObjectReference, _ string,
+	_ context.Context) (client.Client, string, error) {
+	return s.client, testInfraNamespace, nil
+}
+
+func buildKubevirtDiagnosticsReconciler(t *testing.T, infraObjects ...client.Object) *KubevirtDiagnosticsReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme, clusterv1.AddToScheme, infrav1.AddToScheme, kubevirtv1.AddToScheme,
+	} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	machine := newPlanMachine("worker", false, nil, true)
+	isController := true
+	kubevirtMachine := &infrav1.KubevirtMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker",
+			Namespace: testPlanNamespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Name: machine.Name, Controller: &isController,
+			}},
+		},
+	}
+
+	infraClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(infraObjects...).
+		WithIndex(&corev1.Event{}, involvedObjectNameField, func(obj client.Object) []string {
+			event, _ := obj.(*corev1.Event)
+
+			return []string{event.InvolvedObject.Name}
+		}).
+		Build()
+
+	return &KubevirtDiagnosticsReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(machine, kubevirtMachine).
+			WithStatusSubresource(&clusterv1.Machine{}).
+			Build(),
+		InfraCluster: &staticInfraCluster{client: infraClient},
+	}
+}
+
+func reconcileKubevirtDiagnostics(t *testing.T, reconciler *KubevirtDiagnosticsReconciler) *clusterv1.Machine {
+	t.Helper()
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: testPlanNamespace, Name: "worker"},
+	})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	machine := &clusterv1.Machine{}
+
+	err = reconciler.Get(t.Context(), types.NamespacedName{Namespace: testPlanNamespace, Name: "worker"}, machine)
+	if err != nil {
+		t.Fatalf("failed to get Machine: %v", err)
+	}
+
+	return machine
+}
+
+func newTestEvent(name, involvedObject, reason string, seen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: testInfraNamespace},
+		InvolvedObject: corev1.ObjectReference{Name: involvedObject, Namespace: testInfraNamespace},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        reason + " happened",
+		LastTimestamp:  metav1.NewTime(seen),
+	}
+}
+
+func TestKubevirtDiagnosticsRunning(t *testing.T) {
+	t.Parallel()
+
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: testInfraNamespace, UID: "vmi-uid"},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			Phase: kubevirtv1.Running,
+			Conditions: []kubevirtv1.VirtualMachineInstanceCondition{
+				{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: corev1.ConditionTrue},
+			},
+			GuestOSInfo: kubevirtv1.VirtualMachineInstanceGuestOSInfo{
+				PrettyName: "Talos (v1.10.0)", KernelRelease: "6.12.25",
+			},
+		},
+	}
+
+	machine := reconcileKubevirtDiagnostics(t, buildKubevirtDiagnosticsReconciler(t, vmi))
+
+	if machine.Annotations[AnnotationPowerState] != "Running" ||
+		machine.Annotations[AnnotationGuestOS] != "Talos (v1.10.0) (kernel 6.12.25)" {
+		t.Fatalf("expected a running machine with its guest OS, got %v", machine.Annotations)
+	}
+
+	if _, found := machine.Annotations[AnnotationBootDiagnostics]; found {
+		t.Fatalf("expected no boot diagnostics, got %v", machine.Annotations)
+	}
+
+	if !conditions.IsTrue(machine, VirtualMachineRunningCondition) {
+		t.Fatalf("expected the %s condition to be true, got %v", VirtualMachineRunningCondition, machine.Status.Conditions)
+	}
+}
+
+func TestKubevirtDiagnosticsReportsLatestWarning(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: testInfraNamespace, UID: "vmi-uid"},
+		Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Scheduling},
+	}
+	launcher := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "virt-launcher-worker-abcde",
+		Namespace: testInfraNamespace,
+		Labels:    map[string]string{kubevirtv1.CreatedByLabel: "vmi-uid"},
+	}}
+
+	machine := reconcileKubevirtDiagnostics(t, buildKubevirtDiagnosticsReconciler(t, vmi, launcher,
+		newTestEvent("old", "worker", "SyncFailed", now.Add(-time.Hour)),
+		newTestEvent("new", launcher.Name, "FailedScheduling", now),
+		newTestEvent("other", "other-vm", "FailedMount", now.Add(time.Minute))))
+
+	if machine.Annotations[AnnotationPowerState] != "Scheduling" {
+		t.Fatalf("expected a scheduling machine, got %v", machine.Annotations)
+	}
+
+	diagnostics := machine.Annotations[AnnotationBootDiagnostics]
+	if diagnostics != "FailedScheduling: FailedScheduling happened" {
+		t.Fatalf("expected the latest warning of the launcher Pod, got %q", diagnostics)
+	}
+
+	condition := conditions.Get(machine, VirtualMachineRunningCondition)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != "Scheduling" ||
+		condition.Severity != clusterv1.ConditionSeverityWarning || condition.Message != diagnostics {
+		t.Fatalf("expected the %s condition to report the warning, got %+v", VirtualMachineRunningCondition, condition)
+	}
+}
+
+func TestKubevirtDiagnosticsStopped(t *testing.T) {
+	t.Parallel()
+
+	machine := reconcileKubevirtDiagnostics(t, buildKubevirtDiagnosticsReconciler(t))
+
+	if machine.Annotations[AnnotationPowerState] != PowerStateStopped {
+		t.Fatalf("expected a stopped machine, got %v", machine.Annotations)
+	}
+
+	if !conditions.IsFalse(machine, VirtualMachineRunningCondition) {
+		t.Fatalf("expected the %s condition to be false, got %v", VirtualMachineRunningCondition, machine.Status.Conditions)
+	}
+}
@@ -0,0 +1,267 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// AnnotationAction carries the Talos lifecycle action requested for a Machine through
+	// the machines/{name}/actions endpoint. Upgrades are carried out by the
+	// MachineUpgradeReconciler and only recorded here.
+	AnnotationAction = "kommodity.io/action"
+	// AnnotationActionGraceful is "false" for a reset that skips cordoning and draining the node.
+	AnnotationActionGraceful = "kommodity.io/action-graceful"
+	// AnnotationActionReboot is "true" for a reset that reboots the node instead of powering it off.
+	AnnotationActionReboot = "kommodity.io/action-reboot"
+	// AnnotationActionPhase reports the progress of the requested action.
+	AnnotationActionPhase = "kommodity.io/action-phase"
+	// AnnotationActionError carries the error of the last failed action, if any.
+	AnnotationActionError = "kommodity.io/action-error"
+	// AnnotationActionTransitionTime is the RFC 3339 time the phase of the action last changed.
+	AnnotationActionTransitionTime = "kommodity.io/action-transition-time"
+
+	// MachineActionReboot reboots the node.
+	MachineActionReboot = "reboot"
+	// MachineActionReset wipes the node.
+	MachineActionReset = "reset"
+	// MachineActionUpgrade upgrades Talos OS on the node.
+	MachineActionUpgrade = "upgrade"
+
+	// ActionPhasePending means the action was requested but not yet sent to the node.
+	ActionPhasePending = "Pending"
+	// ActionPhaseInProgress means the action was claimed by the controller and is being
+	// sent to the node. It is never sent again from this phase.
+	ActionPhaseInProgress = "InProgress"
+	// ActionPhaseCompleted means the Talos API of the node accepted the action.
+	ActionPhaseCompleted = "Completed"
+	// ActionPhaseFailed means the node rejected the action.
+	ActionPhaseFailed = "Failed"
+
+	// machineActionControllerName is the name used to register the controller.
+	machineActionControllerName = "kommodity-machine-action-controller"
+	// machineActionRecorderName is the component the events of the controller are reported as.
+	machineActionRecorderName = "kommodity-machine-action"
+)
+
+// MachineActionReconciler reboots and resets the nodes of Machines through their Talos
+// API. Like upgrades, the requested action and its phase are kept in annotations on the
+// Machine, so requests are authorized and audited by the API server; the outcome is
+// also reported as an event on the Machine.
+type MachineActionReconciler struct {
+	client.Client
+
+	Recorder record.EventRecorder
+	// talosClient returns a Talos API client for the node of the Machine, defaults to
+	// TalosClientForMachine.
+	talosClient func(ctx context.Context, reader client.Reader, machine *clusterv1.Machine) (talosActionClient, error)
+}
+
+// talosActionClient is the part of the Talos API client the MachineActionReconciler uses.
+type talosActionClient interface {
+	Reboot(ctx context.Context, opts ...talosclient.RebootMode) error
+	Reset(ctx context.Context, graceful, reboot bool) error
+	Close() error
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *MachineActionReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up Machine action reconciler")
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(machineActionRecorderName)
+	}
+
+	if r.talosClient == nil {
+		r.talosClient = func(ctx context.Context, reader client.Reader,
+			machine *clusterv1.Machine) (talosActionClient, error) {
+			return TalosClientForMachine(ctx, reader, machine)
+		}
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(machineActionControllerName).
+		For(&clusterv1.Machine{}, builder.WithPredicates(machineActionRequestedPredicate())).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up Machine action controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile sends a pending action to the node of a single Machine.
+func (r *MachineActionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("machine", req.String()))
+
+	machine := &clusterv1.Machine{}
+
+	err := r.Get(ctx, req.NamespacedName, machine)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	action := machine.Annotations[AnnotationAction]
+	if !isTalosAction(action) || machine.Annotations[AnnotationActionPhase] != ActionPhasePending ||
+		!machine.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger,
+		client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.ClusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	talosClient, err := r.talosClient(ctx, r, machine)
+	if err != nil {
+		logger.Info("Talos API not reachable for Machine yet, requeuing",
+			zap.Error(err),
+			zap.Duration("requeueAfter", RequeueAfter))
+
+		//nolint:nilerr // the node may not have an address yet, retry without backoff.
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	defer func() { _ = talosClient.Close() }()
+
+	// Claim the action before sending it, so that it is never sent twice: a failed write
+	// leaves the action pending, a failed call after the write leaves it in progress.
+	err = r.setActionPhase(ctx, machine, ActionPhaseInProgress, "")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, machineUpgradeCallTimeout)
+	defer cancel()
+
+	logger.Info("Requesting Talos action", zap.String("action", action))
+
+	if action == MachineActionReboot {
+		err = talosClient.Reboot(callCtx)
+	} else {
+		err = talosClient.Reset(callCtx,
+			machine.Annotations[AnnotationActionGraceful] != "false",
+			machine.Annotations[AnnotationActionReboot] == "true")
+	}
+
+	if err != nil {
+		logger.Error("Talos rejected the action", zap.String("action", action), zap.Error(err))
+		r.Recorder.Eventf(machine, corev1.EventTypeWarning, "TalosActionFailed",
+			"Talos rejected the %s: %v", action, err)
+
+		return ctrl.Result{}, r.finishAction(ctx, machine, ActionPhaseFailed, err.Error())
+	}
+
+	r.Recorder.Eventf(machine, corev1.EventTypeNormal, "TalosActionCompleted", "Talos accepted the %s", action)
+
+	return ctrl.Result{}, r.finishAction(ctx, machine, ActionPhaseCompleted, "")
+}
+
+// finishAction records the outcome of the action the Machine was claimed for. Conflicts
+// with unrelated changes are retried, unless the action was requested again meanwhile.
+func (r *MachineActionReconciler) finishAction(ctx context.Context,
+	claimed *clusterv1.Machine, phase string, actionError string) error {
+	claimedAt := claimed.Annotations[AnnotationActionTransitionTime]
+
+	//nolint:wrapcheck // setActionPhase wraps the error already.
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		machine := &clusterv1.Machine{}
+
+		err := r.Get(ctx, client.ObjectKeyFromObject(claimed), machine)
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		if machine.Annotations[AnnotationActionPhase] != ActionPhaseInProgress ||
+			machine.Annotations[AnnotationActionTransitionTime] != claimedAt {
+			return nil
+		}
+
+		return r.setActionPhase(ctx, machine, phase, actionError)
+	})
+}
+
+// setActionPhase sets the phase of the action on the Machine, which is updated with the
+// patched object.
+func (r *MachineActionReconciler) setActionPhase(ctx context.Context,
+	machine *clusterv1.Machine, phase string, actionError string) error {
+	original := machine.DeepCopy()
+	machine.Annotations[AnnotationActionPhase] = phase
+	machine.Annotations[AnnotationActionTransitionTime] = time.Now().UTC().Format(time.RFC3339Nano)
+
+	if actionError != "" {
+		machine.Annotations[AnnotationActionError] = actionError
+	} else {
+		delete(machine.Annotations, AnnotationActionError)
+	}
+
+	// Fail on concurrent changes so that a newer request is not marked as done, and so
+	// that an action is only claimed once.
+	err := r.Patch(ctx, machine, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return fmt.Errorf("failed to set action phase %s on Machine %s: %w", phase, machine.Name, err)
+	}
+
+	return nil
+}
+
+// isTalosAction reports whether the action is carried out by the MachineActionReconciler.
+func isTalosAction(action string) bool {
+	return action == MachineActionReboot || action == MachineActionReset
+}
+
+// machineActionRequestedPredicate only lets through Machines with a pending reboot or
+// reset, and on update only when the request or its phase changed.
+func machineActionRequestedPredicate() predicate.Predicate {
+	isPending := func(obj client.Object) bool {
+		annotations := obj.GetAnnotations()
+
+		return isTalosAction(annotations[AnnotationAction]) && annotations[AnnotationActionPhase] == ActionPhasePending
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(createEvent event.CreateEvent) bool {
+			return isPending(createEvent.Object)
+		},
+		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+			if !isPending(updateEvent.ObjectNew) {
+				return false
+			}
+
+			oldAnnotations := updateEvent.ObjectOld.GetAnnotations()
+			newAnnotations := updateEvent.ObjectNew.GetAnnotations()
+
+			return oldAnnotations[AnnotationActionPhase] != newAnnotations[AnnotationActionPhase] ||
+				oldAnnotations[AnnotationActionTransitionTime] != newAnnotations[AnnotationActionTransitionTime]
+		},
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(_ event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,152 @@
+//nolint:testpackage // white-box tests replace the Talos client of the reconciler
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+type fakeTalosActionClient struct {
+	reboots int
+}
+
+func (c *fakeTalosActionClient) Reboot(context.Context, ...talosclient.RebootMode) error {
+	c.reboots++
+
+	return nil
+}
+
+func (c *fakeTalosActionClient) Reset(context.Context, bool, bool) error {
+	return nil
+}
+
+func (c *fakeTalosActionClient) Close() error {
+	return nil
+}
+
+// buildMachineActionReconciler returns a reconciler for a Machine with a pending reboot,
+// whose patches fail with a conflict when failPatch returns true for them.
+func buildMachineActionReconciler(t *testing.T, talos *fakeTalosActionClient,
+	failPatch func(patches int) bool) *MachineActionReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	err := clusterv1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding to scheme: %v", err)
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testPlanNamespace,
+			Name:      "worker-0",
+			Annotations: map[string]string{
+				AnnotationAction:      MachineActionReboot,
+				AnnotationActionPhase: ActionPhasePending,
+			},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: testPlanCluster},
+	}
+
+	patches := 0
+
+	return &MachineActionReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object,
+					patch client.Patch, opts ...client.PatchOption) error {
+					patches++
+					if failPatch(patches) {
+						return apierrors.NewConflict(schema.GroupResource{Resource: "machines"}, obj.GetName(), nil)
+					}
+
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build(),
+		Recorder: record.NewFakeRecorder(10),
+		talosClient: func(context.Context, client.Reader, *clusterv1.Machine) (talosActionClient, error) {
+			return talos, nil
+		},
+	}
+}
+
+func reconcileMachineAction(t *testing.T, reconciler *MachineActionReconciler) error {
+	t.Helper()
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: testPlanNamespace, Name: "worker-0"},
+	})
+
+	return err
+}
+
+func machineActionPhase(t *testing.T, reconciler *MachineActionReconciler) string {
+	t.Helper()
+
+	machine := &clusterv1.Machine{}
+
+	err := reconciler.Get(t.Context(), client.ObjectKey{Namespace: testPlanNamespace, Name: "worker-0"}, machine)
+	if err != nil {
+		t.Fatalf("failed to get Machine: %v", err)
+	}
+
+	return machine.Annotations[AnnotationActionPhase]
+}
+
+func TestMachineActionIsSentOnce(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		failPatch     func(patches int) bool
+		expectedPhase string
+	}{
+		"conflict claiming the action": {
+			failPatch:     func(patches int) bool { return patches == 1 },
+			expectedPhase: ActionPhaseCompleted,
+		},
+		"conflict recording the outcome": {
+			failPatch:     func(patches int) bool { return patches == 2 },
+			expectedPhase: ActionPhaseCompleted,
+		},
+		"outcome never recorded": {
+			failPatch:     func(patches int) bool { return patches > 1 },
+			expectedPhase: ActionPhaseInProgress,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			talos := &fakeTalosActionClient{}
+			reconciler := buildMachineActionReconciler(t, talos, test.failPatch)
+
+			// Retry as the controller would, until the reconcile succeeds or gives up.
+			for range 3 {
+				_ = reconcileMachineAction(t, reconciler)
+			}
+
+			if talos.reboots != 1 {
+				t.Fatalf("expected the reboot to be sent once, got %d", talos.reboots)
+			}
+
+			phase := machineActionPhase(t, reconciler)
+			if phase != test.expectedPhase {
+				t.Fatalf("expected phase %s, got %s", test.expectedPhase, phase)
+			}
+		})
+	}
+}
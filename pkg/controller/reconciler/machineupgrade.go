@@ -0,0 +1,404 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	machineapi "github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	talosclientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// AnnotationUpgradeImage carries the Talos installer image a Machine should be upgraded to.
+	// Setting or changing it (through the machines/{name}/upgrade endpoint) starts an upgrade.
+	AnnotationUpgradeImage = "kommodity.io/upgrade-image"
+	// AnnotationUpgradePhase reports the progress of the requested upgrade.
+	AnnotationUpgradePhase = "kommodity.io/upgrade-phase"
+	// AnnotationUpgradeError carries the last upgrade error, if any.
+	AnnotationUpgradeError = "kommodity.io/upgrade-error"
+	// AnnotationUpgradeFromVersion records the Talos version the node ran before the
+	// upgrade, when the image does not name the version it installs (e.g. it is pinned by
+	// digest only). Such an upgrade completes once the node reports another version.
+	AnnotationUpgradeFromVersion = "kommodity.io/upgrade-from-version"
+
+	// UpgradePhasePending means the upgrade was requested but not yet sent to the node.
+	UpgradePhasePending = "Pending"
+	// UpgradePhaseUpgrading means the upgrade was claimed by the controller and sent to the
+	// node, which is installing or rebooting. It is never sent again from this phase.
+	UpgradePhaseUpgrading = "Upgrading"
+	// UpgradePhaseCompleted means the node reports the requested version.
+	UpgradePhaseCompleted = "Completed"
+	// UpgradePhaseFailed means the node rejected the upgrade request.
+	UpgradePhaseFailed = "Failed"
+
+	// talosConfigSecretSuffix is appended to the Cluster name by CABPT for the talosconfig Secret.
+	talosConfigSecretSuffix = "-talosconfig"
+	// talosConfigSecretKey is the data key holding the talosconfig.
+	talosConfigSecretKey = "talosconfig"
+
+	// machineUpgradeControllerName is the name used to register the controller.
+	machineUpgradeControllerName = "kommodity-machine-upgrade-controller"
+
+	// machineUpgradePollInterval is how often the node version is checked while upgrading.
+	machineUpgradePollInterval = 30 * time.Second
+	// machineUpgradeCallTimeout bounds a single call to the Talos API.
+	machineUpgradeCallTimeout = 30 * time.Second
+)
+
+// MachineUpgradeReconciler drives Talos OS upgrades of individual Machines. The desired
+// image and the observed phase are kept in annotations on the Machine, so every request
+// and state change goes through the API server and shows up in the audit log.
+type MachineUpgradeReconciler struct {
+	client.Client
+
+	// talosClient returns a Talos API client for the node of the Machine, defaults to
+	// TalosClientForMachine.
+	talosClient func(ctx context.Context, reader client.Reader, machine *clusterv1.Machine) (talosUpgradeClient, error)
+}
+
+// talosUpgradeClient is the part of the Talos API client the MachineUpgradeReconciler uses.
+type talosUpgradeClient interface {
+	UpgradeWithOptions(ctx context.Context, opts ...talosclient.UpgradeOption) (*machineapi.UpgradeResponse, error)
+	Version(ctx context.Context, callOptions ...grpc.CallOption) (*machineapi.VersionResponse, error)
+	Close() error
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *MachineUpgradeReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up Machine upgrade reconciler")
+
+	if r.talosClient == nil {
+		r.talosClient = func(ctx context.Context, reader client.Reader,
+			machine *clusterv1.Machine) (talosUpgradeClient, error) {
+			return TalosClientForMachine(ctx, reader, machine)
+		}
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(machineUpgradeControllerName).
+		For(&clusterv1.Machine{}, builder.WithPredicates(machineUpgradeRequestedPredicate())).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up Machine upgrade controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile advances the upgrade state machine of a single Machine.
+func (r *MachineUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("machine", req.String()))
+
+	machine := &clusterv1.Machine{}
+
+	err := r.Get(ctx, req.NamespacedName, machine)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	image := machine.Annotations[AnnotationUpgradeImage]
+	if image == "" || !machine.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger,
+		client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.ClusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	switch machine.Annotations[AnnotationUpgradePhase] {
+	case "", UpgradePhasePending:
+		return r.startUpgrade(ctx, logger, machine, image)
+	case UpgradePhaseUpgrading:
+		return r.checkUpgrade(ctx, logger, machine, image)
+	default:
+		return ctrl.Result{}, nil
+	}
+}
+
+func (r *MachineUpgradeReconciler) startUpgrade(ctx context.Context, logger *zap.Logger,
+	machine *clusterv1.Machine, image string) (ctrl.Result, error) {
+	talosClient, err := r.talosClient(ctx, r, machine)
+	if err != nil {
+		logger.Info("Talos API not reachable for Machine yet, requeuing",
+			zap.Error(err),
+			zap.Duration("requeueAfter", RequeueAfter))
+
+		//nolint:nilerr // the node may not have an address yet, retry without backoff.
+		return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+	}
+
+	defer func() { _ = talosClient.Close() }()
+
+	callCtx, cancel := context.WithTimeout(ctx, machineUpgradeCallTimeout)
+	defer cancel()
+
+	fromVersion := ""
+
+	if imageVersion(image) == "" {
+		fromVersion, err = runningVersion(callCtx, talosClient)
+		if err != nil {
+			logger.Info("Node version not available yet, requeuing",
+				zap.Error(err),
+				zap.Duration("requeueAfter", RequeueAfter))
+
+			//nolint:nilerr // the node may still be booting, retry without backoff.
+			return ctrl.Result{RequeueAfter: RequeueAfter}, nil
+		}
+	}
+
+	// Claim the upgrade before sending it, so that it is never sent twice: a failed write
+	// leaves the upgrade pending, a failed call after the write leaves it upgrading.
+	err = r.setUpgradePhase(ctx, machine, UpgradePhaseUpgrading, "", fromVersion)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Requesting Talos upgrade", zap.String("image", image))
+
+	//nolint:staticcheck // LifecycleService is only served by recent Talos versions, older nodes need the machine API.
+	_, err = talosClient.UpgradeWithOptions(callCtx,
+		talosclient.WithUpgradeImage(image),
+		talosclient.WithUpgradePreserve(true),
+	)
+	if err != nil {
+		logger.Error("Talos rejected the upgrade request", zap.Error(err))
+
+		return ctrl.Result{}, r.finishUpgrade(ctx, machine, UpgradePhaseFailed, err.Error())
+	}
+
+	return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+}
+
+func (r *MachineUpgradeReconciler) checkUpgrade(ctx context.Context, logger *zap.Logger,
+	machine *clusterv1.Machine, image string) (ctrl.Result, error) {
+	talosClient, err := r.talosClient(ctx, r, machine)
+	if err != nil {
+		//nolint:nilerr // the node is expected to be unreachable while rebooting.
+		return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+	}
+
+	defer func() { _ = talosClient.Close() }()
+
+	callCtx, cancel := context.WithTimeout(ctx, machineUpgradeCallTimeout)
+	defer cancel()
+
+	version, err := runningVersion(callCtx, talosClient)
+	if err != nil {
+		logger.Debug("Node version not available yet", zap.Error(err))
+
+		//nolint:nilerr // the node is expected to be unreachable while rebooting.
+		return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+	}
+
+	if !upgradeCompleted(image, machine.Annotations[AnnotationUpgradeFromVersion], version) {
+		return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+	}
+
+	logger.Info("Talos upgrade completed", zap.String("version", version))
+
+	return ctrl.Result{}, r.finishUpgrade(ctx, machine, UpgradePhaseCompleted, "")
+}
+
+// runningVersion returns the Talos version the node reports.
+func runningVersion(ctx context.Context, talosClient talosUpgradeClient) (string, error) {
+	version, err := talosClient.Version(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get node version: %w", err)
+	}
+
+	if len(version.GetMessages()) == 0 || version.GetMessages()[0].GetVersion().GetTag() == "" {
+		return "", ErrNodeVersionUnknown
+	}
+
+	return version.GetMessages()[0].GetVersion().GetTag(), nil
+}
+
+// imageVersion returns the version the installer image installs, which is the tag of the
+// image reference, or an empty string when the image is not tagged (e.g. pinned by digest
+// only).
+func imageVersion(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+
+	tagged, ok := named.(reference.Tagged)
+	if !ok {
+		return ""
+	}
+
+	return tagged.Tag()
+}
+
+// upgradeCompleted reports whether the node running version completed the upgrade to the
+// image. Without a version in the image, any version other than the one the node ran
+// before the upgrade completes it.
+func upgradeCompleted(image string, fromVersion string, version string) bool {
+	expected := imageVersion(image)
+	if expected == "" {
+		return version != fromVersion
+	}
+
+	return version == expected
+}
+
+// TalosClientForMachine creates a Talos API client for the node backing the Machine,
+// using the talosconfig of its Cluster. Connections to private addresses are routed
+// through the Talos proxy transparently when it is enabled.
+func TalosClientForMachine(ctx context.Context, reader client.Reader,
+	machine *clusterv1.Machine) (*talosclient.Client, error) {
+	address := machineAddress(machine)
+	if address == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMachineHasNoAddress, machine.Name)
+	}
+
+	secret := &corev1.Secret{}
+
+	err := reader.Get(ctx, client.ObjectKey{
+		Namespace: machine.Namespace,
+		Name:      machine.Spec.ClusterName + talosConfigSecretSuffix,
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get talosconfig secret: %w", err)
+	}
+
+	talosConfig, err := talosclientconfig.FromBytes(secret.Data[talosConfigSecretKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse talosconfig: %w", err)
+	}
+
+	talosClient, err := talosclient.New(ctx,
+		talosclient.WithConfig(talosConfig),
+		talosclient.WithEndpoints(address),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Talos client: %w", err)
+	}
+
+	return talosClient, nil
+}
+
+// finishUpgrade records the outcome of the upgrade the Machine was claimed for. Conflicts
+// with unrelated changes are retried, unless the upgrade was requested again meanwhile.
+func (r *MachineUpgradeReconciler) finishUpgrade(ctx context.Context,
+	claimed *clusterv1.Machine, phase string, upgradeError string) error {
+	claimedImage := claimed.Annotations[AnnotationUpgradeImage]
+
+	//nolint:wrapcheck // setUpgradePhase wraps the error already.
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		machine := &clusterv1.Machine{}
+
+		err := r.Get(ctx, client.ObjectKeyFromObject(claimed), machine)
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		if machine.Annotations[AnnotationUpgradePhase] != UpgradePhaseUpgrading ||
+			machine.Annotations[AnnotationUpgradeImage] != claimedImage {
+			return nil
+		}
+
+		return r.setUpgradePhase(ctx, machine, phase, upgradeError,
+			machine.Annotations[AnnotationUpgradeFromVersion])
+	})
+}
+
+// setUpgradePhase sets the phase of the upgrade on the Machine, which is updated with the
+// patched object.
+func (r *MachineUpgradeReconciler) setUpgradePhase(ctx context.Context,
+	machine *clusterv1.Machine, phase string, upgradeError string, fromVersion string) error {
+	original := machine.DeepCopy()
+	machine.Annotations[AnnotationUpgradePhase] = phase
+
+	if upgradeError != "" {
+		machine.Annotations[AnnotationUpgradeError] = upgradeError
+	} else {
+		delete(machine.Annotations, AnnotationUpgradeError)
+	}
+
+	if fromVersion != "" {
+		machine.Annotations[AnnotationUpgradeFromVersion] = fromVersion
+	} else {
+		delete(machine.Annotations, AnnotationUpgradeFromVersion)
+	}
+
+	// Fail on concurrent changes so that a newer request is not marked as done, and so
+	// that an upgrade is only claimed once.
+	err := r.Patch(ctx, machine, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return fmt.Errorf("failed to set upgrade phase %s on Machine %s: %w", phase, machine.Name, err)
+	}
+
+	return nil
+}
+
+// machineAddress returns the preferred address to reach the Talos API of a Machine.
+func machineAddress(machine *clusterv1.Machine) string {
+	for _, addressType := range []clusterv1.MachineAddressType{
+		clusterv1.MachineInternalIP,
+		clusterv1.MachineExternalIP,
+	} {
+		for _, address := range machine.Status.Addresses {
+			if address.Type == addressType && address.Address != "" {
+				return address.Address
+			}
+		}
+	}
+
+	return ""
+}
+
+// machineUpgradeRequestedPredicate only lets through Machines carrying an upgrade
+// request, and on update only when the request or its phase changed.
+func machineUpgradeRequestedPredicate() predicate.Predicate {
+	hasRequest := func(obj client.Object) bool {
+		return obj.GetAnnotations()[AnnotationUpgradeImage] != ""
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(createEvent event.CreateEvent) bool {
+			return hasRequest(createEvent.Object)
+		},
+		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+			if !hasRequest(updateEvent.ObjectNew) {
+				return false
+			}
+
+			oldAnnotations := updateEvent.ObjectOld.GetAnnotations()
+			newAnnotations := updateEvent.ObjectNew.GetAnnotations()
+
+			return oldAnnotations[AnnotationUpgradeImage] != newAnnotations[AnnotationUpgradeImage] ||
+				oldAnnotations[AnnotationUpgradePhase] != newAnnotations[AnnotationUpgradePhase]
+		},
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(_ event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,224 @@
+//nolint:testpackage // white-box tests replace the Talos client of the reconciler
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	machineapi "github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"google.golang.org/grpc"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testUpgradeImage  = "ghcr.io/siderolabs/installer:v1.13.0"
+	testUpgradeDigest = "sha256:4bf0ef3a0c39b4de4bb3b0e4d06ae3c0a2a0e8d1d0cba3b0e5b1d2b0ad9e8f10"
+)
+
+type fakeTalosUpgradeClient struct {
+	upgrades int
+	version  string
+}
+
+func (c *fakeTalosUpgradeClient) UpgradeWithOptions(context.Context,
+	...talosclient.UpgradeOption) (*machineapi.UpgradeResponse, error) {
+	c.upgrades++
+
+	return &machineapi.UpgradeResponse{}, nil
+}
+
+func (c *fakeTalosUpgradeClient) Version(context.Context, ...grpc.CallOption) (*machineapi.VersionResponse, error) {
+	return &machineapi.VersionResponse{
+		Messages: []*machineapi.Version{{Version: &machineapi.VersionInfo{Tag: c.version}}},
+	}, nil
+}
+
+func (c *fakeTalosUpgradeClient) Close() error {
+	return nil
+}
+
+// buildMachineUpgradeReconciler returns a reconciler for a Machine with a pending upgrade
+// to the image, whose patches fail with a conflict when failPatch returns true for them.
+func buildMachineUpgradeReconciler(t *testing.T, talos *fakeTalosUpgradeClient, image string,
+	failPatch func(patches int) bool) *MachineUpgradeReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	err := clusterv1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding to scheme: %v", err)
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testPlanNamespace,
+			Name:      "worker-0",
+			Annotations: map[string]string{
+				AnnotationUpgradeImage: image,
+				AnnotationUpgradePhase: UpgradePhasePending,
+			},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: testPlanCluster},
+	}
+
+	patches := 0
+
+	return &MachineUpgradeReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object,
+					patch client.Patch, opts ...client.PatchOption) error {
+					patches++
+					if failPatch(patches) {
+						return apierrors.NewConflict(schema.GroupResource{Resource: "machines"}, obj.GetName(), nil)
+					}
+
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build(),
+		talosClient: func(context.Context, client.Reader, *clusterv1.Machine) (talosUpgradeClient, error) {
+			return talos, nil
+		},
+	}
+}
+
+func reconcileMachineUpgrade(t *testing.T, reconciler *MachineUpgradeReconciler) error {
+	t.Helper()
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: testPlanNamespace, Name: "worker-0"},
+	})
+
+	return err
+}
+
+func machineUpgradePhase(t *testing.T, reconciler *MachineUpgradeReconciler) string {
+	t.Helper()
+
+	machine := &clusterv1.Machine{}
+
+	err := reconciler.Get(t.Context(), client.ObjectKey{Namespace: testPlanNamespace, Name: "worker-0"}, machine)
+	if err != nil {
+		t.Fatalf("failed to get Machine: %v", err)
+	}
+
+	return machine.Annotations[AnnotationUpgradePhase]
+}
+
+func TestMachineUpgradeIsSentOnce(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		failPatch     func(patches int) bool
+		expectedPhase string
+	}{
+		"conflict claiming the upgrade": {
+			failPatch:     func(patches int) bool { return patches == 1 },
+			expectedPhase: UpgradePhaseCompleted,
+		},
+		"conflict recording the outcome": {
+			failPatch:     func(patches int) bool { return patches == 2 },
+			expectedPhase: UpgradePhaseCompleted,
+		},
+		"outcome never recorded": {
+			failPatch:     func(patches int) bool { return patches > 1 },
+			expectedPhase: UpgradePhaseUpgrading,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			talos := &fakeTalosUpgradeClient{version: "v1.13.0"}
+			reconciler := buildMachineUpgradeReconciler(t, talos, testUpgradeImage, test.failPatch)
+
+			// Retry as the controller would, until the reconcile succeeds or gives up.
+			for range 3 {
+				_ = reconcileMachineUpgrade(t, reconciler)
+			}
+
+			if talos.upgrades != 1 {
+				t.Fatalf("expected the upgrade to be sent once, got %d", talos.upgrades)
+			}
+
+			phase := machineUpgradePhase(t, reconciler)
+			if phase != test.expectedPhase {
+				t.Fatalf("expected phase %s, got %s", test.expectedPhase, phase)
+			}
+		})
+	}
+}
+
+func TestMachineUpgradeCompletes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		image string
+		// runningVersion is the version the node reports after the upgrade was sent.
+		runningVersion string
+		expectedPhase  string
+	}{
+		"tagged image": {
+			image:          testUpgradeImage,
+			runningVersion: "v1.13.0",
+			expectedPhase:  UpgradePhaseCompleted,
+		},
+		"tagged image still on the old version": {
+			image:          testUpgradeImage,
+			runningVersion: "v1.12.4",
+			expectedPhase:  UpgradePhaseUpgrading,
+		},
+		"tagged and digest-pinned image": {
+			image:          testUpgradeImage + "@" + testUpgradeDigest,
+			runningVersion: "v1.13.0",
+			expectedPhase:  UpgradePhaseCompleted,
+		},
+		"digest-pinned image": {
+			image:          "ghcr.io/siderolabs/installer@" + testUpgradeDigest,
+			runningVersion: "v1.13.0",
+			expectedPhase:  UpgradePhaseCompleted,
+		},
+		"digest-pinned image still on the old version": {
+			image:          "ghcr.io/siderolabs/installer@" + testUpgradeDigest,
+			runningVersion: "v1.12.4",
+			expectedPhase:  UpgradePhaseUpgrading,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			talos := &fakeTalosUpgradeClient{version: "v1.12.4"}
+			reconciler := buildMachineUpgradeReconciler(t, talos, test.image, func(int) bool { return false })
+
+			err := reconcileMachineUpgrade(t, reconciler)
+			if err != nil {
+				t.Fatalf("failed to start the upgrade: %v", err)
+			}
+
+			talos.version = test.runningVersion
+
+			err = reconcileMachineUpgrade(t, reconciler)
+			if err != nil {
+				t.Fatalf("failed to check the upgrade: %v", err)
+			}
+
+			phase := machineUpgradePhase(t, reconciler)
+			if phase != test.expectedPhase {
+				t.Fatalf("expected phase %s, got %s", test.expectedPhase, phase)
+			}
+		})
+	}
+}
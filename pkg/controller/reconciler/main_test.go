@@ -0,0 +1,12 @@
+//nolint:testpackage // TestMain must share the package of the white-box tests.
+package reconciler
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,64 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationPauseReason records on a paused Cluster why it was paused.
+	AnnotationPauseReason = "kommodity.io/pause-reason"
+	// pausedRequeueAfter is how often reconcilers look again at objects of a paused
+	// Cluster, which do not change when the Cluster is resumed.
+	pausedRequeueAfter = time.Minute
+)
+
+// ClusterPaused reports whether reconciliation of the Cluster is paused, with spec.paused
+// or the paused annotation of Cluster API. Reconcilers that change the workload cluster or
+// its nodes skip paused Clusters and retry later, so that operators can do manual
+// maintenance without controllers undoing it. A missing Cluster is not paused.
+func ClusterPaused(ctx context.Context, c client.Reader, key client.ObjectKey) (bool, error) {
+	cluster := &clusterv1.Cluster{}
+
+	err := c.Get(ctx, key, cluster)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get Cluster %s: %w", key, err)
+	}
+
+	return annotations.IsPaused(cluster, cluster), nil
+}
+
+// skipPausedCluster reports whether the Cluster is paused, in which case the caller
+// returns pausedResult to look again once it may have been resumed.
+func skipPausedCluster(ctx context.Context, c client.Reader, logger *zap.Logger,
+	key client.ObjectKey) (bool, error) {
+	paused, err := ClusterPaused(ctx, c, key)
+	if err != nil {
+		return false, err
+	}
+
+	if paused {
+		logger.Debug("Cluster is paused, skipping reconciliation",
+			zap.String("cluster", key.String()),
+			zap.Duration("requeueAfter", pausedRequeueAfter))
+	}
+
+	return paused, nil
+}
+
+// pausedResult is the result of reconcilers skipping a paused Cluster.
+func pausedResult() ctrl.Result {
+	return ctrl.Result{RequeueAfter: pausedRequeueAfter}
+}
@@ -0,0 +1,51 @@
+//nolint:testpackage // white-box tests cover the pause check of the package
+package reconciler
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterPaused(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+
+	err := clusterv1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding to scheme: %v", err)
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: testPlanNamespace},
+		},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: testPlanNamespace},
+			Spec:       clusterv1.ClusterSpec{Paused: true},
+		},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "annotated",
+				Namespace:   testPlanNamespace,
+				Annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+			},
+		},
+	).Build()
+
+	for name, want := range map[string]bool{"running": false, "paused": true, "annotated": true, "missing": false} {
+		paused, err := ClusterPaused(t.Context(), kubeClient, client.ObjectKey{Namespace: testPlanNamespace, Name: name})
+		if err != nil {
+			t.Fatalf("failed to check cluster %s: %v", name, err)
+		}
+
+		if paused != want {
+			t.Fatalf("expected cluster %s paused=%t, got %t", name, want, paused)
+		}
+	}
+}
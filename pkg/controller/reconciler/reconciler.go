@@ -7,6 +7,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/kommodity-io/kommodity/pkg/backup"
 	"github.com/kommodity-io/kommodity/pkg/config"
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	"go.uber.org/zap"
@@ -60,7 +61,7 @@ func SetupReconcilers(ctx context.Context,
 		}
 	}
 
-	err = setUpExtraReconcilers(ctx, cfg, manager, controllerOpts, signingKeyDeps)
+	err = setUpExtraReconcilers(ctx, cfg, manager, clusterCache, controllerOpts, signingKeyDeps)
 	if err != nil {
 		return fmt.Errorf("failed to setup extra reconcilers: %w", err)
 	}
@@ -74,9 +75,11 @@ func azureProviderEnabled(cfg *config.KommodityConfig) bool {
 	return slices.Contains(cfg.InfrastructureProviders, config.ProviderAzure)
 }
 
+//nolint:funlen,cyclop // One error check per reconciler, no real complexity here.
 func setUpExtraReconcilers(ctx context.Context,
 	cfg *config.KommodityConfig,
 	manager *ctrl.Manager,
+	clusterCache clustercache.ClusterCache,
 	controllerOpts controller.Options,
 	signingKeyDeps SigningKeyDeps) error {
 	err := (&CCMCRSReconciler{
@@ -94,6 +97,42 @@ func setUpExtraReconcilers(ctx context.Context,
 		return fmt.Errorf("failed to setup Autoscaler reconciler: %w", err)
 	}
 
+	err = (&CCMReconciler{
+		Client: (*manager).GetClient(),
+		cfg:    cfg,
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup CCM reconciler: %w", err)
+	}
+
+	err = (&CNIReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup CNI reconciler: %w", err)
+	}
+
+	err = (&CSIReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup CSI reconciler: %w", err)
+	}
+
+	err = (&ClientCacheReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup downstream client cache reconciler: %w", err)
+	}
+
+	err = (&AddonReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup ClusterAddon reconciler: %w", err)
+	}
+
 	if azureProviderEnabled(cfg) {
 		err = (&AzureCredentialMaterializer{
 			Client: (*manager).GetClient(),
@@ -112,5 +151,87 @@ func setUpExtraReconcilers(ctx context.Context,
 		return fmt.Errorf("failed to setup SigningKey reconciler: %w", err)
 	}
 
+	err = (&MachineUpgradeReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup Machine upgrade reconciler: %w", err)
+	}
+
+	err = (&MachineActionReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup Machine action reconciler: %w", err)
+	}
+
+	err = (&ClusterTeardownReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup Cluster teardown reconciler: %w", err)
+	}
+
+	err = (&TalosUpgradePlanReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup TalosUpgradePlan reconciler: %w", err)
+	}
+
+	err = (&ClusterHealthReconciler{
+		Client:       (*manager).GetClient(),
+		ClusterCache: clusterCache,
+		Interval:     cfg.ClusterHealthInterval,
+		Dynamic:      cfg.Dynamic,
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup ClusterHealth reconciler: %w", err)
+	}
+
+	if cfg.Dynamic != nil {
+		err = (&DynamicConfigReconciler{
+			Client: (*manager).GetClient(),
+			Config: cfg.Dynamic,
+		}).SetupWithManager(ctx, *manager, controllerOpts)
+		if err != nil {
+			return fmt.Errorf("failed to setup dynamic configuration reconciler: %w", err)
+		}
+	}
+
+	backupManager := backup.NewManager(cfg)
+	if backupManager.Enabled() {
+		err = (&EtcdBackupReconciler{
+			Client: (*manager).GetClient(),
+			Store:  backupManager,
+		}).SetupWithManager(ctx, *manager, controllerOpts)
+		if err != nil {
+			return fmt.Errorf("failed to setup etcd backup reconciler: %w", err)
+		}
+	}
+
+	err = (&ClusterInstanceReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup ClusterInstance reconciler: %w", err)
+	}
+
+	err = (&TenantTemplateReconciler{
+		Client: (*manager).GetClient(),
+	}).SetupWithManager(ctx, *manager, controllerOpts)
+	if err != nil {
+		return fmt.Errorf("failed to setup TenantTemplate reconciler: %w", err)
+	}
+
+	if cfg.EncryptionConfig.Enabled() {
+		err = (&SecretsReencryptionReconciler{
+			Client: (*manager).GetClient(),
+		}).SetupWithManager(ctx, *manager, controllerOpts)
+		if err != nil {
+			return fmt.Errorf("failed to setup Secrets re-encryption reconciler: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,232 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// SecretsReencryptionName is the name of the ConfigMap in the Kommodity namespace
+	// that requests a re-encryption of all Secrets and reports its progress.
+	SecretsReencryptionName = "secrets-reencryption"
+
+	// SecretsReencryptionRequestedKey is set by the operator to request a run. A run
+	// starts whenever its value differs from the completed one.
+	SecretsReencryptionRequestedKey = "requested"
+	// SecretsReencryptionCompletedKey holds the value of the last completed request.
+	SecretsReencryptionCompletedKey = "completed"
+	// SecretsReencryptionPhaseKey holds the phase of the current or last run.
+	SecretsReencryptionPhaseKey = "phase"
+	// SecretsReencryptionProcessedKey holds the number of Secrets rewritten so far.
+	SecretsReencryptionProcessedKey = "processed"
+	// SecretsReencryptionFailedKey holds the number of Secrets that could not be rewritten.
+	SecretsReencryptionFailedKey = "failed"
+	// SecretsReencryptionStartedAtKey holds the RFC 3339 start time of the run.
+	SecretsReencryptionStartedAtKey = "startedAt"
+	// SecretsReencryptionCompletedAtKey holds the RFC 3339 completion time of the run.
+	SecretsReencryptionCompletedAtKey = "completedAt"
+	// SecretsReencryptionLastErrorKey holds the last error encountered by the run.
+	SecretsReencryptionLastErrorKey = "lastError"
+
+	// SecretsReencryptionPhaseRunning means Secrets are being rewritten.
+	SecretsReencryptionPhaseRunning = "Running"
+	// SecretsReencryptionPhaseCompleted means every Secret was rewritten.
+	SecretsReencryptionPhaseCompleted = "Completed"
+	// SecretsReencryptionPhaseFailed means some Secrets could not be rewritten.
+	SecretsReencryptionPhaseFailed = "Failed"
+
+	secretsReencryptionControllerName = "kommodity-secrets-reencryption-controller"
+	secretsReencryptionPageSize       = 100
+)
+
+// SecretsReencryptionReconciler rewrites every Secret so that it is stored with the
+// current data encryption key. It is the second half of a key rotation: once the key
+// encryption key has been rotated, new writes already use a new data encryption key,
+// and this job migrates the data written before.
+//
+// Each Secret is written back unchanged. The storage layer reports values encrypted
+// under an outdated key, as well as values stored before encryption was enabled, as
+// stale and persists them again with the current key; up to date values are left
+// untouched. Progress is reported in the secrets-reencryption ConfigMap that
+// requested the run.
+type SecretsReencryptionReconciler struct {
+	client.Client
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *SecretsReencryptionReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up Secrets re-encryption reconciler")
+
+	isRequest := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == config.KommodityNamespace && obj.GetName() == SecretsReencryptionName
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(secretsReencryptionControllerName).
+		For(&corev1.ConfigMap{}, builder.OnlyMetadata, builder.WithPredicates(isRequest)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up Secrets re-encryption controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile runs a requested re-encryption to completion.
+func (r *SecretsReencryptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx)
+
+	request := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, req.NamespacedName, request)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	requested := request.Data[SecretsReencryptionRequestedKey]
+	if requested == "" || requested == request.Data[SecretsReencryptionCompletedKey] {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Starting re-encryption of Secrets", zap.String("request", requested))
+
+	status := map[string]string{
+		SecretsReencryptionPhaseKey:       SecretsReencryptionPhaseRunning,
+		SecretsReencryptionProcessedKey:   "0",
+		SecretsReencryptionFailedKey:      "0",
+		SecretsReencryptionStartedAtKey:   time.Now().UTC().Format(time.RFC3339),
+		SecretsReencryptionCompletedAtKey: "",
+		SecretsReencryptionLastErrorKey:   "",
+	}
+
+	err = r.updateStatus(ctx, req.NamespacedName, status)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	processed, failed, lastErr := r.reencryptAll(ctx, req, status)
+
+	status[SecretsReencryptionProcessedKey] = strconv.Itoa(processed)
+	status[SecretsReencryptionFailedKey] = strconv.Itoa(failed)
+	status[SecretsReencryptionCompletedAtKey] = time.Now().UTC().Format(time.RFC3339)
+	status[SecretsReencryptionCompletedKey] = requested
+	status[SecretsReencryptionPhaseKey] = SecretsReencryptionPhaseCompleted
+
+	if lastErr != nil {
+		status[SecretsReencryptionPhaseKey] = SecretsReencryptionPhaseFailed
+		status[SecretsReencryptionLastErrorKey] = lastErr.Error()
+	}
+
+	logger.Info("Finished re-encryption of Secrets",
+		zap.String("request", requested),
+		zap.String("phase", status[SecretsReencryptionPhaseKey]),
+		zap.Int("processed", processed),
+		zap.Int("failed", failed))
+
+	return ctrl.Result{}, r.updateStatus(ctx, req.NamespacedName, status)
+}
+
+// reencryptAll rewrites all Secrets page by page, publishing progress after every page.
+// Failures of individual Secrets are counted and do not stop the run; a failure to
+// list aborts it.
+func (r *SecretsReencryptionReconciler) reencryptAll(ctx context.Context, req ctrl.Request,
+	status map[string]string) (int, int, error) {
+	logger := logging.FromContext(ctx)
+
+	var (
+		processed, failed int
+		lastErr           error
+		continueToken     string
+	)
+
+	for {
+		secrets := &corev1.SecretList{}
+
+		err := r.List(ctx, secrets, client.Limit(secretsReencryptionPageSize), client.Continue(continueToken))
+		if err != nil {
+			return processed, failed, fmt.Errorf("failed to list Secrets: %w", err)
+		}
+
+		for i := range secrets.Items {
+			err = r.rewriteSecret(ctx, &secrets.Items[i])
+			if err != nil {
+				logger.Warn("Failed to re-encrypt Secret",
+					zap.String("secret", secrets.Items[i].Namespace+"/"+secrets.Items[i].Name),
+					zap.Error(err))
+
+				failed++
+				lastErr = err
+
+				continue
+			}
+
+			processed++
+		}
+
+		status[SecretsReencryptionProcessedKey] = strconv.Itoa(processed)
+		status[SecretsReencryptionFailedKey] = strconv.Itoa(failed)
+
+		err = r.updateStatus(ctx, req.NamespacedName, status)
+		if err != nil {
+			logger.Warn("Failed to report re-encryption progress", zap.Error(err))
+		}
+
+		continueToken = secrets.Continue
+		if continueToken == "" {
+			return processed, failed, lastErr
+		}
+	}
+}
+
+// rewriteSecret writes a Secret back unchanged. A conflict means the Secret was
+// written concurrently, which already stored it with the current key.
+func (r *SecretsReencryptionReconciler) rewriteSecret(ctx context.Context, secret *corev1.Secret) error {
+	err := r.Update(ctx, secret)
+	if err == nil || apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return nil
+	}
+
+	return fmt.Errorf("failed to rewrite Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+}
+
+func (r *SecretsReencryptionReconciler) updateStatus(ctx context.Context,
+	key client.ObjectKey, status map[string]string) error {
+	request := &corev1.ConfigMap{}
+
+	err := r.Get(ctx, key, request)
+	if err != nil {
+		return fmt.Errorf("failed to get re-encryption request: %w", err)
+	}
+
+	patched := request.DeepCopy()
+	if patched.Data == nil {
+		patched.Data = map[string]string{}
+	}
+
+	for k, v := range status {
+		patched.Data[k] = v
+	}
+
+	err = r.Patch(ctx, patched, client.MergeFrom(request))
+	if err != nil {
+		return fmt.Errorf("failed to update re-encryption status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+//nolint:testpackage // white-box tests exercise the re-encryption reconciler with a fake client
+package reconciler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testReencryptedSecrets = secretsReencryptionPageSize + 5
+
+func buildSecretsReencryptionReconciler(t *testing.T, request map[string]string) *SecretsReencryptionReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	err := corev1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("adding to scheme: %v", err)
+	}
+
+	objs := []client.Object{&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretsReencryptionName, Namespace: config.KommodityNamespace},
+		Data:       request,
+	}}
+
+	for i := range testReencryptedSecrets {
+		objs = append(objs, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("secret-%d", i), Namespace: "default"},
+			Data:       map[string][]byte{"key": []byte("value")},
+		})
+	}
+
+	return &SecretsReencryptionReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+	}
+}
+
+func reconcileReencryption(t *testing.T, reconciler *SecretsReencryptionReconciler) *corev1.ConfigMap {
+	t.Helper()
+
+	key := types.NamespacedName{Name: SecretsReencryptionName, Namespace: config.KommodityNamespace}
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	request := &corev1.ConfigMap{}
+
+	err = reconciler.Get(t.Context(), key, request)
+	if err != nil {
+		t.Fatalf("failed to get re-encryption request: %v", err)
+	}
+
+	return request
+}
+
+func TestSecretsReencryptionRewritesAllSecrets(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildSecretsReencryptionReconciler(t, map[string]string{
+		SecretsReencryptionRequestedKey: "rotation-1",
+	})
+
+	before := &corev1.Secret{}
+
+	err := reconciler.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "secret-0"}, before)
+	if err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+
+	status := reconcileReencryption(t, reconciler).Data
+
+	if status[SecretsReencryptionPhaseKey] != SecretsReencryptionPhaseCompleted {
+		t.Fatalf("expected phase %s, got %v", SecretsReencryptionPhaseCompleted, status)
+	}
+
+	if status[SecretsReencryptionProcessedKey] != fmt.Sprint(testReencryptedSecrets) ||
+		status[SecretsReencryptionFailedKey] != "0" {
+		t.Fatalf("unexpected progress %v", status)
+	}
+
+	if status[SecretsReencryptionCompletedKey] != "rotation-1" || status[SecretsReencryptionCompletedAtKey] == "" {
+		t.Fatalf("expected request to be marked completed, got %v", status)
+	}
+
+	after := &corev1.Secret{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "secret-0"}, after)
+	if err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+
+	if after.ResourceVersion == before.ResourceVersion {
+		t.Fatalf("expected Secret to be written back")
+	}
+
+	if string(after.Data["key"]) != "value" {
+		t.Fatalf("expected Secret data to be unchanged, got %q", after.Data["key"])
+	}
+}
+
+func TestSecretsReencryptionSkipsCompletedRequest(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildSecretsReencryptionReconciler(t, map[string]string{
+		SecretsReencryptionRequestedKey: "rotation-1",
+		SecretsReencryptionCompletedKey: "rotation-1",
+	})
+
+	status := reconcileReencryption(t, reconciler).Data
+
+	if _, ok := status[SecretsReencryptionPhaseKey]; ok {
+		t.Fatalf("expected no run for a completed request, got %v", status)
+	}
+}
@@ -0,0 +1,320 @@
+package reconciler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// talosUpgradePlanControllerName is the name used to register the controller.
+	talosUpgradePlanControllerName = "kommodity-talos-upgrade-plan-controller"
+	// talosUpgradePlanRecorderName is the component the events of the controller are reported as.
+	talosUpgradePlanRecorderName = "kommodity-talos-upgrade-plan"
+)
+
+// TalosUpgradePlanReconciler rolls Talos OS upgrades through the Machines of a cluster.
+// Machines are upgraded one at a time, control plane first, by requesting the upgrade on
+// the Machine like the machines/{name}/upgrade endpoint does; the MachineUpgradeReconciler
+// then calls the Talos API, which cordons and drains the node before installing the
+// image. The next Machine is only started once the node of the previous one is healthy
+// and the control plane is ready. A failed upgrade or a node that does not become
+// healthy in time halts the plan until its spec is changed.
+type TalosUpgradePlanReconciler struct {
+	client.Client
+
+	Recorder record.EventRecorder
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *TalosUpgradePlanReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up TalosUpgradePlan reconciler")
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(talosUpgradePlanRecorderName)
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(talosUpgradePlanControllerName).
+		For(&kommodityv1alpha1.TalosUpgradePlan{}).
+		Watches(&clusterv1.Machine{}, handler.EnqueueRequestsFromMapFunc(r.plansForMachine)).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up TalosUpgradePlan controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile advances the rollout of a single TalosUpgradePlan.
+func (r *TalosUpgradePlanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("talosUpgradePlan", req.String()))
+
+	plan := &kommodityv1alpha1.TalosUpgradePlan{}
+
+	err := r.Get(ctx, req.NamespacedName, plan)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !plan.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	paused, err := skipPausedCluster(ctx, r, logger,
+		client.ObjectKey{Namespace: plan.Namespace, Name: plan.Spec.ClusterName})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		return pausedResult(), nil
+	}
+
+	original := plan.DeepCopy()
+
+	if plan.Status.ObservedGeneration != plan.Generation {
+		// A new spec starts the rollout over, which also resumes a halted plan.
+		plan.Status = kommodityv1alpha1.TalosUpgradePlanStatus{
+			ObservedGeneration: plan.Generation,
+			Phase:              kommodityv1alpha1.TalosUpgradePlanPhasePending,
+		}
+	} else if plan.Status.Phase == kommodityv1alpha1.TalosUpgradePlanPhaseCompleted ||
+		plan.Status.Phase == kommodityv1alpha1.TalosUpgradePlanPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	result, err := r.advance(ctx, logger, plan)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.Status().Patch(ctx, plan, client.MergeFrom(original))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status of TalosUpgradePlan %s: %w", plan.Name, err)
+	}
+
+	return result, nil
+}
+
+// advance walks the Machines of the cluster in order and acts on the first one that does
+// not run the image with a healthy node yet. The outcome is recorded in the plan status.
+func (r *TalosUpgradePlanReconciler) advance(ctx context.Context, logger *zap.Logger,
+	plan *kommodityv1alpha1.TalosUpgradePlan) (ctrl.Result, error) {
+	machines, err := r.planMachines(ctx, plan)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	plan.Status.TotalMachines = int32(len(machines)) //nolint:gosec // A cluster never has 2^31 Machines.
+	plan.Status.UpgradedMachines = 0
+
+	for _, machine := range machines {
+		phase := machine.Annotations[AnnotationUpgradePhase]
+		requested := machine.Annotations[AnnotationUpgradeImage] == plan.Spec.Image
+		current := plan.Status.CurrentMachine == machine.Name
+
+		if requested && phase == UpgradePhaseCompleted &&
+			conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition) {
+			plan.Status.UpgradedMachines++
+
+			continue
+		}
+
+		// Failures of an earlier rollout are retried, failures of this one halt the plan.
+		if !requested || (phase == UpgradePhaseFailed && !current) {
+			return r.startMachine(ctx, logger, plan, machine)
+		}
+
+		if phase == UpgradePhaseFailed {
+			r.halt(plan, fmt.Sprintf("Upgrade of Machine %s failed: %s",
+				machine.Name, machine.Annotations[AnnotationUpgradeError]))
+
+			return ctrl.Result{}, nil
+		}
+
+		if !current {
+			// The upgrade was requested outside of this rollout, wait for it like for our own.
+			plan.Status.CurrentMachine = machine.Name
+			plan.Status.CurrentMachineStartTime = ptrToNow()
+		}
+
+		timeout := healthCheckTimeout(plan)
+		if time.Since(plan.Status.CurrentMachineStartTime.Time) > timeout {
+			r.halt(plan, fmt.Sprintf("Machine %s did not become healthy within %s", machine.Name, timeout))
+
+			return ctrl.Result{}, nil
+		}
+
+		plan.Status.Phase = kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading
+		plan.Status.Message = fmt.Sprintf("Waiting for Machine %s to be upgraded and healthy", machine.Name)
+
+		return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+	}
+
+	if plan.Status.Phase != kommodityv1alpha1.TalosUpgradePlanPhaseCompleted {
+		logger.Info("Talos upgrade plan completed", zap.String("image", plan.Spec.Image))
+		r.Recorder.Eventf(plan, corev1.EventTypeNormal, "UpgradeCompleted",
+			"All %d Machines run %s", len(machines), plan.Spec.Image)
+	}
+
+	plan.Status.Phase = kommodityv1alpha1.TalosUpgradePlanPhaseCompleted
+	plan.Status.Message = ""
+	plan.Status.CurrentMachine = ""
+	plan.Status.CurrentMachineStartTime = nil
+
+	return ctrl.Result{}, nil
+}
+
+// startMachine requests the upgrade of the next Machine once the control plane is ready.
+func (r *TalosUpgradePlanReconciler) startMachine(ctx context.Context, logger *zap.Logger,
+	plan *kommodityv1alpha1.TalosUpgradePlan, machine *clusterv1.Machine) (ctrl.Result, error) {
+	cluster := &clusterv1.Cluster{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: plan.Namespace, Name: plan.Spec.ClusterName}, cluster)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get Cluster %s: %w", plan.Spec.ClusterName, err)
+	}
+
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneReadyCondition) {
+		plan.Status.Phase = kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading
+		plan.Status.Message = fmt.Sprintf("Waiting for the control plane of Cluster %s to be ready", cluster.Name)
+
+		return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+	}
+
+	logger.Info("Upgrading Machine", zap.String("machine", machine.Name), zap.String("image", plan.Spec.Image))
+
+	patched := machine.DeepCopy()
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+
+	patched.Annotations[AnnotationUpgradeImage] = plan.Spec.Image
+	patched.Annotations[AnnotationUpgradePhase] = UpgradePhasePending
+	patched.Annotations[AnnotationAction] = MachineActionUpgrade
+	delete(patched.Annotations, AnnotationUpgradeError)
+	delete(patched.Annotations, AnnotationActionPhase)
+	delete(patched.Annotations, AnnotationActionError)
+
+	err = r.Patch(ctx, patched, client.MergeFrom(machine))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to request upgrade of Machine %s: %w", machine.Name, err)
+	}
+
+	r.Recorder.Eventf(plan, corev1.EventTypeNormal, "UpgradingMachine",
+		"Upgrading Machine %s to %s", machine.Name, plan.Spec.Image)
+
+	plan.Status.Phase = kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading
+	plan.Status.Message = fmt.Sprintf("Waiting for Machine %s to be upgraded and healthy", machine.Name)
+	plan.Status.CurrentMachine = machine.Name
+	plan.Status.CurrentMachineStartTime = ptrToNow()
+
+	return ctrl.Result{RequeueAfter: machineUpgradePollInterval}, nil
+}
+
+func (r *TalosUpgradePlanReconciler) halt(plan *kommodityv1alpha1.TalosUpgradePlan, message string) {
+	r.Recorder.Event(plan, corev1.EventTypeWarning, "UpgradeHalted", message)
+
+	plan.Status.Phase = kommodityv1alpha1.TalosUpgradePlanPhaseFailed
+	plan.Status.Message = message
+}
+
+// planMachines returns the Machines of the cluster of the plan, control plane Machines
+// first and otherwise ordered by name.
+func (r *TalosUpgradePlanReconciler) planMachines(ctx context.Context,
+	plan *kommodityv1alpha1.TalosUpgradePlan) ([]*clusterv1.Machine, error) {
+	machineList := &clusterv1.MachineList{}
+
+	err := r.List(ctx, machineList,
+		client.InNamespace(plan.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: plan.Spec.ClusterName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Machines of Cluster %s: %w", plan.Spec.ClusterName, err)
+	}
+
+	machines := make([]*clusterv1.Machine, 0, len(machineList.Items))
+
+	for i := range machineList.Items {
+		if machineList.Items[i].DeletionTimestamp.IsZero() {
+			machines = append(machines, &machineList.Items[i])
+		}
+	}
+
+	slices.SortFunc(machines, func(a, b *clusterv1.Machine) int {
+		_, aControlPlane := a.Labels[clusterv1.MachineControlPlaneLabel]
+		_, bControlPlane := b.Labels[clusterv1.MachineControlPlaneLabel]
+
+		if aControlPlane != bControlPlane {
+			if aControlPlane {
+				return -1
+			}
+
+			return 1
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return machines, nil
+}
+
+// plansForMachine maps a Machine to the plans of its cluster.
+func (r *TalosUpgradePlanReconciler) plansForMachine(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterName := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if clusterName == "" {
+		return nil
+	}
+
+	plans := &kommodityv1alpha1.TalosUpgradePlanList{}
+
+	err := r.List(ctx, plans, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list TalosUpgradePlans", zap.Error(err))
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, plan := range plans.Items {
+		if plan.Spec.ClusterName == clusterName {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&plan)})
+		}
+	}
+
+	return requests
+}
+
+func healthCheckTimeout(plan *kommodityv1alpha1.TalosUpgradePlan) time.Duration {
+	if plan.Spec.HealthCheckTimeout == nil || plan.Spec.HealthCheckTimeout.Duration <= 0 {
+		return kommodityv1alpha1.DefaultTalosUpgradeHealthCheckTimeout
+	}
+
+	return plan.Spec.HealthCheckTimeout.Duration
+}
+
+func ptrToNow() *metav1.Time {
+	now := metav1.Now()
+
+	return &now
+}
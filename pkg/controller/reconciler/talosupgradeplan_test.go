@@ -0,0 +1,265 @@
+//nolint:testpackage // white-box tests share the upgrade annotations of the package
+package reconciler
+
+import (
+	"testing"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testPlanNamespace = "default"
+	testPlanCluster   = "prod"
+	testPlanImage     = "ghcr.io/siderolabs/installer:v1.11.0"
+)
+
+func newPlanMachine(name string, controlPlane bool, annotations map[string]string, healthy bool) *clusterv1.Machine {
+	labels := map[string]string{clusterv1.ClusterNameLabel: testPlanCluster}
+	if controlPlane {
+		labels[clusterv1.MachineControlPlaneLabel] = ""
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testPlanNamespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: testPlanCluster},
+	}
+
+	if healthy {
+		machine.Status.Conditions = clusterv1.Conditions{
+			{Type: clusterv1.MachineNodeHealthyCondition, Status: corev1.ConditionTrue},
+		}
+	}
+
+	return machine
+}
+
+func upgradedAnnotations(phase string) map[string]string {
+	return map[string]string{AnnotationUpgradeImage: testPlanImage, AnnotationUpgradePhase: phase}
+}
+
+func buildTalosUpgradePlanReconciler(t *testing.T, status kommodityv1alpha1.TalosUpgradePlanStatus,
+	machines ...client.Object) *TalosUpgradePlanReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{clusterv1.AddToScheme, kommodityv1alpha1.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	plan := &kommodityv1alpha1.TalosUpgradePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Namespace: testPlanNamespace, Generation: 1},
+		Spec:       kommodityv1alpha1.TalosUpgradePlanSpec{ClusterName: testPlanCluster, Image: testPlanImage},
+		Status:     status,
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanCluster, Namespace: testPlanNamespace},
+		Status: clusterv1.ClusterStatus{Conditions: clusterv1.Conditions{
+			{Type: clusterv1.ControlPlaneReadyCondition, Status: corev1.ConditionTrue},
+		}},
+	}
+
+	return &TalosUpgradePlanReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(append(machines, plan, cluster)...).
+			WithStatusSubresource(plan).
+			Build(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func reconcilePlan(t *testing.T, reconciler *TalosUpgradePlanReconciler) *kommodityv1alpha1.TalosUpgradePlan {
+	t.Helper()
+
+	key := types.NamespacedName{Namespace: testPlanNamespace, Name: "upgrade"}
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	plan := &kommodityv1alpha1.TalosUpgradePlan{}
+
+	err = reconciler.Get(t.Context(), key, plan)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+
+	return plan
+}
+
+func machineUpgradeImage(t *testing.T, reconciler *TalosUpgradePlanReconciler, name string) string {
+	t.Helper()
+
+	machine := &clusterv1.Machine{}
+
+	err := reconciler.Get(t.Context(), types.NamespacedName{Namespace: testPlanNamespace, Name: name}, machine)
+	if err != nil {
+		t.Fatalf("failed to get machine: %v", err)
+	}
+
+	return machine.Annotations[AnnotationUpgradeImage]
+}
+
+func TestTalosUpgradePlanStartsWithControlPlane(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTalosUpgradePlanReconciler(t, kommodityv1alpha1.TalosUpgradePlanStatus{},
+		newPlanMachine("a-worker", false, nil, true),
+		newPlanMachine("z-control-plane", true, nil, true))
+
+	plan := reconcilePlan(t, reconciler)
+
+	if plan.Status.Phase != kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading ||
+		plan.Status.CurrentMachine != "z-control-plane" || plan.Status.TotalMachines != 2 {
+		t.Fatalf("expected the control plane Machine to be upgraded first, got %+v", plan.Status)
+	}
+
+	if machineUpgradeImage(t, reconciler, "z-control-plane") != testPlanImage ||
+		machineUpgradeImage(t, reconciler, "a-worker") != "" {
+		t.Fatalf("expected only the control plane Machine to be requested")
+	}
+}
+
+func TestTalosUpgradePlanProceedsOnceHealthy(t *testing.T) {
+	t.Parallel()
+
+	status := kommodityv1alpha1.TalosUpgradePlanStatus{
+		ObservedGeneration: 1,
+		Phase:              kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading,
+		CurrentMachine:     "control-plane",
+	}
+
+	reconciler := buildTalosUpgradePlanReconciler(t, status,
+		newPlanMachine("control-plane", true, upgradedAnnotations(UpgradePhaseCompleted), true),
+		newPlanMachine("worker", false, nil, true))
+
+	plan := reconcilePlan(t, reconciler)
+	if plan.Status.CurrentMachine != "worker" || plan.Status.UpgradedMachines != 1 {
+		t.Fatalf("expected the worker to be upgraded next, got %+v", plan.Status)
+	}
+}
+
+func TestTalosUpgradePlanWaitsForNodeHealth(t *testing.T) {
+	t.Parallel()
+
+	status := kommodityv1alpha1.TalosUpgradePlanStatus{
+		ObservedGeneration:      1,
+		Phase:                   kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading,
+		CurrentMachine:          "control-plane",
+		CurrentMachineStartTime: ptrToNow(),
+	}
+
+	reconciler := buildTalosUpgradePlanReconciler(t, status,
+		newPlanMachine("control-plane", true, upgradedAnnotations(UpgradePhaseCompleted), false),
+		newPlanMachine("worker", false, nil, true))
+
+	plan := reconcilePlan(t, reconciler)
+	if plan.Status.CurrentMachine != "control-plane" || machineUpgradeImage(t, reconciler, "worker") != "" {
+		t.Fatalf("expected the plan to wait for the unhealthy node, got %+v", plan.Status)
+	}
+}
+
+func TestTalosUpgradePlanHaltsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	status := kommodityv1alpha1.TalosUpgradePlanStatus{
+		ObservedGeneration: 1,
+		Phase:              kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading,
+		CurrentMachine:     "control-plane",
+	}
+
+	annotations := upgradedAnnotations(UpgradePhaseFailed)
+	annotations[AnnotationUpgradeError] = "boom"
+
+	reconciler := buildTalosUpgradePlanReconciler(t, status,
+		newPlanMachine("control-plane", true, annotations, true),
+		newPlanMachine("worker", false, nil, true))
+
+	plan := reconcilePlan(t, reconciler)
+	if plan.Status.Phase != kommodityv1alpha1.TalosUpgradePlanPhaseFailed ||
+		plan.Status.Message != "Upgrade of Machine control-plane failed: boom" {
+		t.Fatalf("expected the plan to halt, got %+v", plan.Status)
+	}
+
+	plan = reconcilePlan(t, reconciler)
+	if plan.Status.Phase != kommodityv1alpha1.TalosUpgradePlanPhaseFailed ||
+		machineUpgradeImage(t, reconciler, "worker") != "" {
+		t.Fatalf("expected a halted plan to stay halted, got %+v", plan.Status)
+	}
+}
+
+func TestTalosUpgradePlanCompletes(t *testing.T) {
+	t.Parallel()
+
+	status := kommodityv1alpha1.TalosUpgradePlanStatus{
+		ObservedGeneration: 1,
+		Phase:              kommodityv1alpha1.TalosUpgradePlanPhaseUpgrading,
+		CurrentMachine:     "worker",
+	}
+
+	reconciler := buildTalosUpgradePlanReconciler(t, status,
+		newPlanMachine("control-plane", true, upgradedAnnotations(UpgradePhaseCompleted), true),
+		newPlanMachine("worker", false, upgradedAnnotations(UpgradePhaseCompleted), true))
+
+	plan := reconcilePlan(t, reconciler)
+	if plan.Status.Phase != kommodityv1alpha1.TalosUpgradePlanPhaseCompleted ||
+		plan.Status.UpgradedMachines != 2 || plan.Status.CurrentMachine != "" {
+		t.Fatalf("expected the plan to complete, got %+v", plan.Status)
+	}
+}
+
+func TestTalosUpgradePlanWaitsWhileClusterPaused(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTalosUpgradePlanReconciler(t, kommodityv1alpha1.TalosUpgradePlanStatus{},
+		newPlanMachine("cp-0", true, nil, true))
+
+	cluster := &clusterv1.Cluster{}
+
+	err := reconciler.Get(t.Context(), types.NamespacedName{Namespace: testPlanNamespace, Name: testPlanCluster}, cluster)
+	if err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+
+	cluster.Spec.Paused = true
+
+	err = reconciler.Update(t.Context(), cluster)
+	if err != nil {
+		t.Fatalf("failed to pause cluster: %v", err)
+	}
+
+	result, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: testPlanNamespace, Name: "upgrade"},
+	})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	if result.RequeueAfter != pausedRequeueAfter {
+		t.Fatalf("expected requeue after %s, got %+v", pausedRequeueAfter, result)
+	}
+
+	if image := machineUpgradeImage(t, reconciler, "cp-0"); image != "" {
+		t.Fatalf("expected no upgrade while paused, got %q", image)
+	}
+}
@@ -0,0 +1,406 @@
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/template"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// LabelTenantTemplate selects the TenantTemplate applied to a Namespace. On a
+	// ConfigMap in the Kommodity namespace the value "true" marks it as a template.
+	// On objects created from a template it records the template name.
+	LabelTenantTemplate = "kommodity.io/tenant-template"
+
+	// AnnotationTenantTemplateApplied records the template ConfigMap resource
+	// version last applied to a Namespace.
+	AnnotationTenantTemplateApplied = "kommodity.io/tenant-template-applied"
+
+	tenantTemplateMarker         = "true"
+	tenantTemplateControllerName = "kommodity-tenant-template-controller"
+	tenantTemplateDecoderBuffer  = 4096
+)
+
+// TenantTemplateReconciler onboards tenants: labelling a Namespace with
+// kommodity.io/tenant-template=<name> applies the manifests of the TenantTemplate
+// <name> to it. A TenantTemplate is a ConfigMap in the Kommodity namespace labelled
+// kommodity.io/tenant-template=true whose values are YAML manifests, rendered as Go
+// templates with .Namespace and .Template. Typical content is RoleBindings for the
+// tenant's groups and placeholder Secrets for provider credentials.
+//
+// Objects are created in the tenant Namespace, or without a namespace for cluster-scoped
+// kinds, and kept in sync with the template, except Secrets, which are only created
+// when missing so that credentials filled in by the tenant are never overwritten.
+// Kinds the API server does not serve are skipped, which lets a single template be
+// shared with API servers that do serve them.
+type TenantTemplateReconciler struct {
+	client.Client
+}
+
+// tenantTemplateValues are the values available to TenantTemplate manifests.
+type tenantTemplateValues struct {
+	Namespace string
+	Template  string
+}
+
+// SetupWithManager sets up the reconciler with the provided manager.
+func (r *TenantTemplateReconciler) SetupWithManager(ctx context.Context,
+	mgr ctrl.Manager, opt controller.Options) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Setting up TenantTemplate reconciler")
+
+	hasTemplateLabel := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[LabelTenantTemplate] != ""
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(tenantTemplateControllerName).
+		For(&corev1.Namespace{}, builder.WithPredicates(hasTemplateLabel)).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.namespacesForTemplate),
+			builder.OnlyMetadata,
+			builder.WithPredicates(hasTemplateLabel),
+		).
+		WithOptions(opt).
+		Complete(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up TenantTemplate controller with manager: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile applies the TenantTemplate referenced by a Namespace.
+func (r *TenantTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logging.FromContext(ctx).With(zap.String("namespace", req.Name))
+
+	namespace := &corev1.Namespace{}
+
+	err := r.Get(ctx, req.NamespacedName, namespace)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	templateName := namespace.Labels[LabelTenantTemplate]
+	if templateName == "" || namespace.Status.Phase == corev1.NamespaceTerminating {
+		return ctrl.Result{}, nil
+	}
+
+	tenantTemplate := &corev1.ConfigMap{}
+
+	err = r.Get(ctx, client.ObjectKey{Namespace: config.KommodityNamespace, Name: templateName}, tenantTemplate)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("TenantTemplate not found, waiting for it to be created",
+				zap.String("template", templateName))
+
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to get TenantTemplate %s: %w", templateName, err)
+	}
+
+	if tenantTemplate.Labels[LabelTenantTemplate] != tenantTemplateMarker {
+		return ctrl.Result{}, fmt.Errorf("%w: ConfigMap %s/%s is not labelled %s=%s", ErrInvalidTenantTemplate,
+			config.KommodityNamespace, templateName, LabelTenantTemplate, tenantTemplateMarker)
+	}
+
+	objects, err := renderTenantTemplate(tenantTemplate, tenantTemplateValues{
+		Namespace: namespace.Name,
+		Template:  templateName,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, obj := range objects {
+		err = r.applyTenantObject(ctx, logger, namespace.Name, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if namespace.Annotations[AnnotationTenantTemplateApplied] != tenantTemplate.ResourceVersion {
+		patched := namespace.DeepCopy()
+		if patched.Annotations == nil {
+			patched.Annotations = map[string]string{}
+		}
+
+		patched.Annotations[AnnotationTenantTemplateApplied] = tenantTemplate.ResourceVersion
+
+		err = r.Patch(ctx, patched, client.MergeFrom(namespace))
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record applied TenantTemplate on %s: %w", namespace.Name, err)
+		}
+	}
+
+	logger.Info("Applied TenantTemplate",
+		zap.String("template", templateName),
+		zap.Int("objects", len(objects)))
+
+	return ctrl.Result{}, nil
+}
+
+// applyTenantObject creates or updates a single templated object. Namespaced objects
+// are created in the tenant namespace, cluster-scoped ones without a namespace.
+func (r *TenantTemplateReconciler) applyTenantObject(ctx context.Context, logger *zap.Logger,
+	tenantNamespace string, desired *unstructured.Unstructured) error {
+	objLogger := logger.With(
+		zap.String("kind", desired.GetKind()),
+		zap.String("name", desired.GetName()))
+
+	namespaced, err := r.IsObjectNamespaced(desired)
+
+	switch {
+	case meta.IsNoMatchError(err):
+		objLogger.Warn("Kind is not served by the API server, skipping TenantTemplate object")
+
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get the scope of %s %s: %w", desired.GetKind(), desired.GetName(), err)
+	case namespaced:
+		desired.SetNamespace(tenantNamespace)
+	default:
+		desired.SetNamespace("")
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(desired.GroupVersionKind())
+
+	err = r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+
+	switch {
+	case apierrors.IsNotFound(err):
+		err = r.Create(ctx, desired)
+		if err != nil {
+			return fmt.Errorf("failed to create %s %s/%s: %w", desired.GetKind(),
+				desired.GetNamespace(), desired.GetName(), err)
+		}
+
+		objLogger.Info("Created TenantTemplate object")
+
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get %s %s/%s: %w", desired.GetKind(),
+			desired.GetNamespace(), desired.GetName(), err)
+	}
+
+	// Secrets are placeholders for tenant provided credentials, never overwrite them.
+	if desired.GroupVersionKind().GroupKind() == corev1.SchemeGroupVersion.WithKind("Secret").GroupKind() {
+		return nil
+	}
+
+	updated := mergeTenantObject(existing, desired)
+
+	unchanged, err := equalTenantObjects(existing, updated)
+	if err != nil || unchanged {
+		return err
+	}
+
+	err = r.Update(ctx, updated)
+	if err != nil {
+		return fmt.Errorf("failed to update %s %s/%s: %w", desired.GetKind(),
+			desired.GetNamespace(), desired.GetName(), err)
+	}
+
+	return nil
+}
+
+// namespacesForTemplate enqueues every Namespace using the changed TenantTemplate.
+func (r *TenantTemplateReconciler) namespacesForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != config.KommodityNamespace {
+		return nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+
+	err := r.List(ctx, namespaces, client.MatchingLabels{LabelTenantTemplate: obj.GetName()})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list Namespaces for TenantTemplate watch",
+			zap.String("template", obj.GetName()),
+			zap.Error(err))
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(namespaces.Items))
+
+	for i := range namespaces.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&namespaces.Items[i]),
+		})
+	}
+
+	return requests
+}
+
+// renderTenantTemplate renders all manifests of a TenantTemplate into objects in
+// the tenant namespace. Keys are processed in sorted order for stable results.
+func renderTenantTemplate(tenantTemplate *corev1.ConfigMap,
+	values tenantTemplateValues) ([]*unstructured.Unstructured, error) {
+	keys := make([]string, 0, len(tenantTemplate.Data))
+	for key := range tenantTemplate.Data {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	objects := []*unstructured.Unstructured{}
+
+	for _, key := range keys {
+		tpl, err := template.New(key).Option("missingkey=error").Parse(tenantTemplate.Data[key])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTenantTemplate, key, err)
+		}
+
+		rendered := &bytes.Buffer{}
+
+		err = tpl.Execute(rendered, values)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTenantTemplate, key, err)
+		}
+
+		decoded, err := decodeTenantObjects(rendered, values)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTenantTemplate, key, err)
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return objects, nil
+}
+
+// decodeManifests decodes the objects of a multi-document YAML or JSON manifest.
+func decodeManifests(manifest io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(manifest, tenantTemplateDecoderBuffer)
+	objects := []*unstructured.Unstructured{}
+
+	for {
+		obj := &unstructured.Unstructured{}
+
+		err := decoder.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+
+		// Skip empty documents, e.g. a trailing "---".
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		objects = append(objects, obj)
+	}
+}
+
+func decodeTenantObjects(manifest io.Reader, values tenantTemplateValues) ([]*unstructured.Unstructured, error) {
+	objects, err := decodeManifests(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objects {
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, ErrTenantObjectMissingKindOrName
+		}
+
+		// The namespace is set once the scope of the kind is known, see applyTenantObject.
+		if obj.GetNamespace() != "" && obj.GetNamespace() != values.Namespace {
+			return nil, fmt.Errorf("%w: %s %s targets namespace %s", ErrTenantObjectOutsideNamespace,
+				obj.GetKind(), obj.GetName(), obj.GetNamespace())
+		}
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		labels[config.ManagedByLabel] = "kommodity"
+		labels[LabelTenantTemplate] = values.Template
+		obj.SetLabels(labels)
+	}
+
+	return objects, nil
+}
+
+// mergeTenantObject returns existing with all non-metadata fields replaced by the
+// template and the template labels and annotations added, preserving metadata
+// managed by the API server and other controllers.
+func mergeTenantObject(existing, desired *unstructured.Unstructured) *unstructured.Unstructured {
+	updated := existing.DeepCopy()
+
+	for field, value := range desired.Object {
+		if field == "metadata" || field == "status" {
+			continue
+		}
+
+		updated.Object[field] = value
+	}
+
+	labels := updated.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	for key, value := range desired.GetLabels() {
+		labels[key] = value
+	}
+
+	updated.SetLabels(labels)
+
+	annotations := updated.GetAnnotations()
+	if annotations == nil && len(desired.GetAnnotations()) > 0 {
+		annotations = map[string]string{}
+	}
+
+	for key, value := range desired.GetAnnotations() {
+		annotations[key] = value
+	}
+
+	updated.SetAnnotations(annotations)
+
+	return updated
+}
+
+// equalTenantObjects reports whether the merge left the live object unchanged, so that
+// no update is sent. Numbers decoded from a template are float64 while those read from
+// the API server are int64, so the objects are compared as JSON.
+func equalTenantObjects(existing, updated *unstructured.Unstructured) (bool, error) {
+	existingJSON, err := json.Marshal(existing.Object)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode %s %s: %w", existing.GetKind(), existing.GetName(), err)
+	}
+
+	updatedJSON, err := json.Marshal(updated.Object)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode %s %s: %w", updated.GetKind(), updated.GetName(), err)
+	}
+
+	return bytes.Equal(existingJSON, updatedJSON), nil
+}
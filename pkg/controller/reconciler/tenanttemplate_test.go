@@ -0,0 +1,311 @@
+//nolint:testpackage // white-box tests exercise unexported TenantTemplate rendering
+package reconciler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testTenantNamespace = "team-a"
+	testTenantTemplate  = "default-tenant"
+
+	testTenantManifests = `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: tenant-admins
+subjects:
+  - kind: Group
+    apiGroup: rbac.authorization.k8s.io
+    name: {{ .Namespace }}-admins
+roleRef:
+  kind: ClusterRole
+  apiGroup: rbac.authorization.k8s.io
+  name: admin
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: provider-credentials
+stringData:
+  token: CHANGE-ME
+`
+)
+
+func tenantTemplateScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, rbacv1.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	return scheme
+}
+
+func newTenantTemplateConfigMap(manifests string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testTenantTemplate,
+			Namespace: config.KommodityNamespace,
+			Labels:    map[string]string{LabelTenantTemplate: tenantTemplateMarker},
+		},
+		Data: map[string]string{"tenant.yaml": manifests},
+	}
+}
+
+func buildTenantTemplateReconciler(t *testing.T, objs ...client.Object) *TenantTemplateReconciler {
+	t.Helper()
+
+	objs = append(objs, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testTenantNamespace,
+			Labels: map[string]string{LabelTenantTemplate: testTenantTemplate},
+		},
+	})
+
+	// The fake client has no discovery, the scopes of the kinds of the templates are set up here.
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	restMapper.Add(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"), meta.RESTScopeNamespace)
+	restMapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+
+	return &TenantTemplateReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(tenantTemplateScheme(t)).
+			WithRESTMapper(restMapper).
+			WithObjects(objs...).
+			Build(),
+	}
+}
+
+func reconcileTenant(t *testing.T, reconciler *TenantTemplateReconciler) error {
+	t.Helper()
+
+	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: testTenantNamespace},
+	})
+
+	return err //nolint:wrapcheck // returned as is for assertions.
+}
+
+func TestTenantTemplateProvisionsNamespace(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTenantTemplateReconciler(t, newTenantTemplateConfigMap(testTenantManifests))
+
+	err := reconcileTenant(t, reconciler)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	binding := &rbacv1.RoleBinding{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Namespace: testTenantNamespace, Name: "tenant-admins"}, binding)
+	if err != nil {
+		t.Fatalf("expected RoleBinding to be created: %v", err)
+	}
+
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Name != "team-a-admins" {
+		t.Fatalf("expected templated subject, got %+v", binding.Subjects)
+	}
+
+	if binding.Labels[LabelTenantTemplate] != testTenantTemplate || binding.Labels[config.ManagedByLabel] != "kommodity" {
+		t.Fatalf("expected tenant template labels, got %v", binding.Labels)
+	}
+
+	secret := &corev1.Secret{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Namespace: testTenantNamespace, Name: "provider-credentials"}, secret)
+	if err != nil {
+		t.Fatalf("expected placeholder Secret to be created: %v", err)
+	}
+
+	namespace := &corev1.Namespace{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Name: testTenantNamespace}, namespace)
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+
+	if namespace.Annotations[AnnotationTenantTemplateApplied] == "" {
+		t.Fatalf("expected applied template annotation on namespace")
+	}
+}
+
+func TestTenantTemplateKeepsFilledInSecrets(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTenantTemplateReconciler(t,
+		newTenantTemplateConfigMap(testTenantManifests),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "provider-credentials", Namespace: testTenantNamespace},
+			Data:       map[string][]byte{"token": []byte("real-token")},
+		},
+	)
+
+	err := reconcileTenant(t, reconciler)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Namespace: testTenantNamespace, Name: "provider-credentials"}, secret)
+	if err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+
+	if string(secret.Data["token"]) != "real-token" {
+		t.Fatalf("expected tenant credentials to be preserved, got %q", secret.Data["token"])
+	}
+}
+
+func TestTenantTemplateUpdatesDriftedObjects(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTenantTemplateReconciler(t,
+		newTenantTemplateConfigMap(testTenantManifests),
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant-admins",
+				Namespace:   testTenantNamespace,
+				Annotations: map[string]string{"owner": "someone"},
+			},
+			Subjects: []rbacv1.Subject{{Kind: "User", Name: "intruder"}},
+			RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", APIGroup: rbacv1.GroupName, Name: "admin"},
+		},
+	)
+
+	err := reconcileTenant(t, reconciler)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	binding := &rbacv1.RoleBinding{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Namespace: testTenantNamespace, Name: "tenant-admins"}, binding)
+	if err != nil {
+		t.Fatalf("failed to get RoleBinding: %v", err)
+	}
+
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Name != "team-a-admins" {
+		t.Fatalf("expected subjects to be reset to the template, got %+v", binding.Subjects)
+	}
+
+	if binding.Annotations["owner"] != "someone" {
+		t.Fatalf("expected unrelated annotations to be preserved, got %v", binding.Annotations)
+	}
+}
+
+func TestTenantTemplateRejectsForeignNamespace(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTenantTemplateReconciler(t, newTenantTemplateConfigMap(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: escape
+  namespace: kube-system
+`))
+
+	err := reconcileTenant(t, reconciler)
+	if !errors.Is(err, ErrTenantObjectOutsideNamespace) {
+		t.Fatalf("expected ErrTenantObjectOutsideNamespace, got %v", err)
+	}
+}
+
+func TestTenantTemplateRequiresTemplateLabel(t *testing.T) {
+	t.Parallel()
+
+	unlabelled := newTenantTemplateConfigMap(testTenantManifests)
+	unlabelled.Labels = nil
+
+	reconciler := buildTenantTemplateReconciler(t, unlabelled)
+
+	err := reconcileTenant(t, reconciler)
+	if !errors.Is(err, ErrInvalidTenantTemplate) {
+		t.Fatalf("expected ErrInvalidTenantTemplate, got %v", err)
+	}
+}
+
+func TestTenantTemplateCreatesClusterScopedObjects(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTenantTemplateReconciler(t, newTenantTemplateConfigMap(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ .Namespace }}-viewer
+rules:
+  - apiGroups: [cluster.x-k8s.io]
+    resources: [clusters]
+    verbs: [get, list, watch]
+`))
+
+	err := reconcileTenant(t, reconciler)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	clusterRole := &rbacv1.ClusterRole{}
+
+	err = reconciler.Get(t.Context(), client.ObjectKey{Name: "team-a-viewer"}, clusterRole)
+	if err != nil {
+		t.Fatalf("expected ClusterRole to be created: %v", err)
+	}
+
+	if clusterRole.Namespace != "" {
+		t.Fatalf("expected ClusterRole without namespace, got %q", clusterRole.Namespace)
+	}
+}
+
+func TestTenantTemplateSkipsUnchangedObjects(t *testing.T) {
+	t.Parallel()
+
+	reconciler := buildTenantTemplateReconciler(t, newTenantTemplateConfigMap(testTenantManifests))
+
+	err := reconcileTenant(t, reconciler)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	key := client.ObjectKey{Namespace: testTenantNamespace, Name: "tenant-admins"}
+	binding := &rbacv1.RoleBinding{}
+
+	err = reconciler.Get(t.Context(), key, binding)
+	if err != nil {
+		t.Fatalf("failed to get RoleBinding: %v", err)
+	}
+
+	err = reconcileTenant(t, reconciler)
+	if err != nil {
+		t.Fatalf("second reconcile failed: %v", err)
+	}
+
+	reconciled := &rbacv1.RoleBinding{}
+
+	err = reconciler.Get(t.Context(), key, reconciled)
+	if err != nil {
+		t.Fatalf("failed to get RoleBinding: %v", err)
+	}
+
+	if reconciled.ResourceVersion != binding.ResourceVersion {
+		t.Fatalf("expected the unchanged RoleBinding not to be updated, resource version went from %s to %s",
+			binding.ResourceVersion, reconciled.ResourceVersion)
+	}
+}
@@ -0,0 +1,498 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// webhookMonitorInterval is how often the registered webhooks are checked.
+	webhookMonitorInterval = time.Minute
+
+	// webhookProbeTimeout bounds a single webhook probe.
+	webhookProbeTimeout = 5 * time.Second
+
+	// webhookFailureThreshold is the number of consecutive failed probes after which a
+	// webhook is reported as unhealthy. It absorbs the startup window in which the
+	// webhook server is not listening yet.
+	webhookFailureThreshold = 3
+
+	// webhookReadinessCheckName is the name of the webhook check on /readyz.
+	webhookReadinessCheckName = "webhooks"
+
+	// webhookMonitorRecorderName is the event source of the webhook monitor.
+	webhookMonitorRecorderName = "kommodity-webhook-monitor"
+
+	// Event reasons emitted by the webhook monitor.
+	reasonWebhookUnreachable = "WebhookUnreachable"
+	reasonWebhookRecovered   = "WebhookRecovered"
+	reasonCABundleRepaired   = "CABundleRepaired"
+
+	// Kinds of webhook configurations, used as metric label values.
+	kindMutatingWebhook   = "MutatingWebhookConfiguration"
+	kindValidatingWebhook = "ValidatingWebhookConfiguration"
+	kindConversionWebhook = "CustomResourceDefinition"
+)
+
+//nolint:gochecknoglobals // Metrics are registered once in the process wide legacy registry.
+var (
+	webhookProbeFailures = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "webhook_monitor",
+		Name:           "probe_failures_total",
+		Help:           "Number of failed probes of a registered webhook endpoint.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"kind", "webhook"})
+
+	webhookHealthy = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "webhook_monitor",
+		Name:           "healthy",
+		Help:           "Whether a registered webhook endpoint answered its last probes (1) or not (0).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"kind", "webhook"})
+
+	webhookCABundleRepairs = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "webhook_monitor",
+		Name:           "cabundle_repairs_total",
+		Help:           "Number of webhook caBundles repaired to match the webhook serving certificate.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"kind"})
+
+	registerWebhookMetricsOnce sync.Once
+)
+
+func registerWebhookMetrics() {
+	registerWebhookMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(webhookProbeFailures, webhookHealthy, webhookCABundleRepairs)
+	})
+}
+
+// webhookMonitorDeps groups the inputs required to set up the webhook monitor.
+type webhookMonitorDeps struct {
+	manager    ctrl.Manager
+	webhookURL string
+	caBundle   []byte
+}
+
+// setupWebhookMonitor registers a runnable that periodically checks every registered
+// admission and conversion webhook. Webhooks served by Kommodity itself get their
+// caBundle repaired when it drifts from the serving certificate, and every webhook
+// served over a URL is probed. Persistent probe failures are reported as Warning
+// events on the webhook configuration and through metrics.
+func setupWebhookMonitor(ctx context.Context, deps webhookMonitorDeps) error {
+	logger := logging.FromContext(ctx)
+
+	if deps.manager == nil {
+		return fmt.Errorf("%w: controller-runtime Manager is nil", ErrWebhookMonitorMissingDep)
+	}
+
+	if len(deps.caBundle) == 0 {
+		return fmt.Errorf("%w: webhook serving certificate is empty", ErrWebhookMonitorMissingDep)
+	}
+
+	logger.Info("Setting up webhook monitor", zap.String("webhookURL", deps.webhookURL))
+
+	registerWebhookMetrics()
+
+	monitor := newWebhookMonitor(deps.manager.GetClient(),
+		deps.manager.GetEventRecorderFor(webhookMonitorRecorderName),
+		deps.webhookURL, deps.caBundle)
+
+	err := deps.manager.Add(monitor)
+	if err != nil {
+		return fmt.Errorf("failed to register webhook monitor with manager: %w", err)
+	}
+
+	return nil
+}
+
+// webhookMonitor implements manager.Runnable.
+type webhookMonitor struct {
+	client     client.Client
+	recorder   record.EventRecorder
+	webhookURL string
+	caBundle   []byte
+
+	// webhooks holds the probe state of every webhook seen in the last pass. It is
+	// written by the monitor loop and read by the readiness check.
+	webhooksLock sync.Mutex
+	webhooks     map[string]*webhookState
+}
+
+// webhookState is the probe state of a single webhook.
+type webhookState struct {
+	kind string
+	name string
+	// owned is true for webhooks served by Kommodity itself.
+	owned bool
+	// failures counts consecutive failed probes.
+	failures int
+}
+
+func newWebhookMonitor(c client.Client, recorder record.EventRecorder,
+	webhookURL string, caBundle []byte) *webhookMonitor {
+	return &webhookMonitor{
+		client:     c,
+		recorder:   recorder,
+		webhookURL: webhookURL,
+		caBundle:   caBundle,
+		webhooks:   make(map[string]*webhookState),
+	}
+}
+
+// Compile-time assertion that the monitor implements manager.Runnable.
+var _ manager.Runnable = (*webhookMonitor)(nil)
+
+// Start checks the webhooks every webhookMonitorInterval until ctx is cancelled.
+func (m *webhookMonitor) Start(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("Starting webhook monitor")
+
+	err := combinedserver.RegisterReadinessCheck(webhookReadinessCheckName, m.Check)
+	if err != nil {
+		logger.Warn("Failed to register webhook readiness check", zap.Error(err))
+	} else {
+		defer combinedserver.UnregisterReadinessCheck(webhookReadinessCheckName)
+	}
+
+	ticker := time.NewTicker(webhookMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Webhook monitor stopped")
+
+			return nil
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// Check reports Kommodity's own webhooks that failed webhookFailureThreshold probes in
+// a row. Webhooks served elsewhere are only reported through events and metrics, as a
+// broken external webhook must not take Kommodity out of rotation.
+func (m *webhookMonitor) Check() error {
+	m.webhooksLock.Lock()
+	defer m.webhooksLock.Unlock()
+
+	var unreachable []string
+
+	for key, state := range m.webhooks {
+		if state.owned && state.failures >= webhookFailureThreshold {
+			unreachable = append(unreachable, key)
+		}
+	}
+
+	if len(unreachable) == 0 {
+		return nil
+	}
+
+	slices.Sort(unreachable)
+
+	return fmt.Errorf("%w: %s", ErrWebhookUnreachable, strings.Join(unreachable, ", "))
+}
+
+// webhookTarget is a single webhook endpoint found in a webhook configuration or CRD.
+type webhookTarget struct {
+	kind     string
+	name     string
+	url      string
+	caBundle []byte
+	owned    bool
+	object   client.Object
+}
+
+// check runs a single pass: repair drifted caBundles, then probe every endpoint, and
+// forget the webhooks that are no longer registered.
+func (m *webhookMonitor) check(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	targets, err := m.collectTargets(ctx)
+	if err != nil {
+		logger.Warn("Failed to collect webhooks", zap.Error(err))
+
+		return
+	}
+
+	registered := make(map[string]bool, len(targets))
+
+	for _, target := range targets {
+		err := m.probe(ctx, target)
+		m.recordProbe(ctx, target, err)
+
+		registered[target.key()] = true
+	}
+
+	m.prune(registered)
+}
+
+// key identifies the webhook of the target across passes.
+func (t webhookTarget) key() string {
+	return t.kind + "/" + t.name
+}
+
+// prune drops the state and metrics of the webhooks that are not registered anymore.
+func (m *webhookMonitor) prune(registered map[string]bool) {
+	m.webhooksLock.Lock()
+	defer m.webhooksLock.Unlock()
+
+	for key, state := range m.webhooks {
+		if registered[key] {
+			continue
+		}
+
+		labels := map[string]string{"kind": state.kind, "webhook": state.name}
+
+		delete(m.webhooks, key)
+		webhookHealthy.Delete(labels)
+		webhookProbeFailures.Delete(labels)
+	}
+}
+
+// collectTargets lists all URL based webhooks, repairing the caBundle of those served
+// by Kommodity on the way.
+func (m *webhookMonitor) collectTargets(ctx context.Context) ([]webhookTarget, error) {
+	targets := make([]webhookTarget, 0)
+
+	mutating := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+
+	err := m.client.List(ctx, mutating)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MutatingWebhookConfigurations: %w", err)
+	}
+
+	for i := range mutating.Items {
+		obj := &mutating.Items[i]
+		original := obj.DeepCopy()
+
+		for j := range obj.Webhooks {
+			targets = m.addAdmissionTarget(targets, kindMutatingWebhook, obj, &obj.Webhooks[j].ClientConfig,
+				obj.Webhooks[j].Name)
+		}
+
+		m.repair(ctx, kindMutatingWebhook, obj, original)
+	}
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+
+	err = m.client.List(ctx, validating)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ValidatingWebhookConfigurations: %w", err)
+	}
+
+	for i := range validating.Items {
+		obj := &validating.Items[i]
+		original := obj.DeepCopy()
+
+		for j := range obj.Webhooks {
+			targets = m.addAdmissionTarget(targets, kindValidatingWebhook, obj, &obj.Webhooks[j].ClientConfig,
+				obj.Webhooks[j].Name)
+		}
+
+		m.repair(ctx, kindValidatingWebhook, obj, original)
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+
+	err = m.client.List(ctx, crds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+
+	for i := range crds.Items {
+		obj := &crds.Items[i]
+
+		conversion := obj.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter ||
+			conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil ||
+			conversion.Webhook.ClientConfig.URL == nil {
+			continue
+		}
+
+		original := obj.DeepCopy()
+		clientConfig := conversion.Webhook.ClientConfig
+		owned := m.owns(*clientConfig.URL)
+
+		if owned && !bytes.Equal(clientConfig.CABundle, m.caBundle) {
+			clientConfig.CABundle = m.caBundle
+		}
+
+		targets = append(targets, webhookTarget{
+			kind:     kindConversionWebhook,
+			name:     obj.Name,
+			url:      *clientConfig.URL,
+			caBundle: clientConfig.CABundle,
+			owned:    owned,
+			object:   obj,
+		})
+
+		m.repair(ctx, kindConversionWebhook, obj, original)
+	}
+
+	return targets, nil
+}
+
+func (m *webhookMonitor) addAdmissionTarget(targets []webhookTarget, kind string, obj client.Object,
+	clientConfig *admissionregistrationv1.WebhookClientConfig, webhookName string) []webhookTarget {
+	// Service references are not routable from Kommodity, only URL webhooks are probed.
+	if clientConfig.URL == nil {
+		return targets
+	}
+
+	owned := m.owns(*clientConfig.URL)
+
+	if owned && !bytes.Equal(clientConfig.CABundle, m.caBundle) {
+		clientConfig.CABundle = m.caBundle
+	}
+
+	return append(targets, webhookTarget{
+		kind:     kind,
+		name:     obj.GetName() + "/" + webhookName,
+		url:      *clientConfig.URL,
+		caBundle: clientConfig.CABundle,
+		owned:    owned,
+		object:   obj,
+	})
+}
+
+// owns reports whether the URL points at Kommodity's own webhook server.
+func (m *webhookMonitor) owns(url string) bool {
+	return url == m.webhookURL || strings.HasPrefix(url, m.webhookURL+"/")
+}
+
+// repair persists the caBundles fixed while collecting targets, if any.
+func (m *webhookMonitor) repair(ctx context.Context, kind string, obj, original client.Object) {
+	logger := logging.FromContext(ctx)
+
+	patch := client.MergeFrom(original)
+
+	data, err := patch.Data(obj)
+	if err != nil || string(data) == "{}" {
+		return
+	}
+
+	err = m.client.Patch(ctx, obj, patch)
+	if err != nil {
+		logger.Warn("Failed to repair webhook caBundle",
+			zap.String("kind", kind), zap.String("name", obj.GetName()), zap.Error(err))
+
+		return
+	}
+
+	webhookCABundleRepairs.WithLabelValues(kind).Inc()
+	m.recorder.Eventf(obj, corev1.EventTypeNormal, reasonCABundleRepaired,
+		"Repaired webhook caBundle to match the Kommodity webhook serving certificate")
+	logger.Info("Repaired webhook caBundle", zap.String("kind", kind), zap.String("name", obj.GetName()))
+}
+
+// probe checks that the endpoint completes a TLS handshake against the webhook's
+// caBundle and answers HTTP. Any HTTP status counts as reachable: the probe carries no
+// AdmissionReview, so webhooks are expected to reject it.
+func (m *webhookMonitor) probe(ctx context.Context, target webhookTarget) error {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(target.caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(target.caBundle) {
+			return ErrWebhookInvalidCABundle
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	httpClient := &http.Client{
+		Timeout:   webhookProbeTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	defer httpClient.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.url, strings.NewReader("{}"))
+	if err != nil {
+		return fmt.Errorf("failed to build probe request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("probe failed: %w", err)
+	}
+
+	_ = resp.Body.Close()
+
+	return nil
+}
+
+// recordProbe updates metrics and emits events when a webhook crosses the failure
+// threshold or recovers from it.
+func (m *webhookMonitor) recordProbe(ctx context.Context, target webhookTarget, probeErr error) {
+	logger := logging.FromContext(ctx)
+
+	m.webhooksLock.Lock()
+	defer m.webhooksLock.Unlock()
+
+	state, found := m.webhooks[target.key()]
+	if !found {
+		state = &webhookState{kind: target.kind, name: target.name}
+		m.webhooks[target.key()] = state
+	}
+
+	state.owned = target.owned
+	previous := state.failures
+
+	if probeErr == nil {
+		state.failures = 0
+		webhookHealthy.WithLabelValues(target.kind, target.name).Set(1)
+
+		if previous >= webhookFailureThreshold {
+			m.recorder.Eventf(target.object, corev1.EventTypeNormal, reasonWebhookRecovered,
+				"Webhook %s at %s is reachable again", target.name, target.url)
+			logger.Info("Webhook recovered", zap.String("kind", target.kind), zap.String("webhook", target.name))
+		}
+
+		return
+	}
+
+	state.failures = previous + 1
+	webhookProbeFailures.WithLabelValues(target.kind, target.name).Inc()
+
+	if state.failures < webhookFailureThreshold {
+		return
+	}
+
+	webhookHealthy.WithLabelValues(target.kind, target.name).Set(0)
+
+	// Report once when the threshold is crossed, not on every failed probe afterwards.
+	if state.failures == webhookFailureThreshold {
+		m.recorder.Eventf(target.object, corev1.EventTypeWarning, reasonWebhookUnreachable,
+			"Webhook %s at %s failed %d consecutive probes: %v",
+			target.name, target.url, webhookFailureThreshold, probeErr)
+		logger.Warn("Webhook is unreachable",
+			zap.String("kind", target.kind), zap.String("webhook", target.name), zap.Error(probeErr))
+	}
+}
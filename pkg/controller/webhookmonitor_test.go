@@ -0,0 +1,223 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testWebhookConfigName = "provider-validating-webhook"
+
+func newTLSWebhookServer(t *testing.T) (*httptest.Server, []byte) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	return server, caBundle
+}
+
+func newWebhookMonitorClient(t *testing.T, url string, caBundle []byte) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+
+	for _, add := range []func(*runtime.Scheme) error{
+		admissionregistrationv1.AddToScheme,
+		apiextensionsv1.AddToScheme,
+	} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatalf("adding to scheme: %v", err)
+		}
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigName},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name:         "validation.example.com",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{URL: &url, CABundle: caBundle},
+			}},
+		},
+	).Build()
+}
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	events := make([]string, 0)
+
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestWebhookMonitor_RepairsDriftedCABundle(t *testing.T) {
+	t.Parallel()
+
+	server, caBundle := newTLSWebhookServer(t)
+	c := newWebhookMonitorClient(t, server.URL+"/validate", []byte("stale"))
+	recorder := record.NewFakeRecorder(10)
+
+	controller.NewWebhookMonitorForTest(c, recorder, server.URL, caBundle).Check(t.Context())
+
+	repaired := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+
+	err := c.Get(t.Context(), client.ObjectKey{Name: testWebhookConfigName}, repaired)
+	if err != nil {
+		t.Fatalf("failed to get webhook configuration: %v", err)
+	}
+
+	if !bytes.Equal(repaired.Webhooks[0].ClientConfig.CABundle, caBundle) {
+		t.Fatalf("expected caBundle to be repaired, got %q", repaired.Webhooks[0].ClientConfig.CABundle)
+	}
+
+	events := drainEvents(recorder)
+	if len(events) != 1 || !strings.Contains(events[0], "CABundleRepaired") {
+		t.Fatalf("expected a single CABundleRepaired event, got %v", events)
+	}
+}
+
+func TestWebhookMonitor_LeavesForeignWebhooksAlone(t *testing.T) {
+	t.Parallel()
+
+	server, caBundle := newTLSWebhookServer(t)
+	c := newWebhookMonitorClient(t, server.URL+"/validate", caBundle)
+	recorder := record.NewFakeRecorder(10)
+
+	controller.NewWebhookMonitorForTest(c, recorder, "https://localhost:1", []byte("other")).Check(t.Context())
+
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+
+	err := c.Get(t.Context(), client.ObjectKey{Name: testWebhookConfigName}, webhook)
+	if err != nil {
+		t.Fatalf("failed to get webhook configuration: %v", err)
+	}
+
+	if !bytes.Equal(webhook.Webhooks[0].ClientConfig.CABundle, caBundle) {
+		t.Fatalf("expected caBundle of a foreign webhook to be kept, got %q", webhook.Webhooks[0].ClientConfig.CABundle)
+	}
+
+	if events := drainEvents(recorder); len(events) != 0 {
+		t.Fatalf("expected no events for a healthy webhook, got %v", events)
+	}
+}
+
+func TestWebhookMonitor_ReportsPersistentFailures(t *testing.T) {
+	t.Parallel()
+
+	server, caBundle := newTLSWebhookServer(t)
+	url := server.URL + "/validate"
+	server.Close()
+
+	c := newWebhookMonitorClient(t, url, caBundle)
+	recorder := record.NewFakeRecorder(10)
+	monitor := controller.NewWebhookMonitorForTest(c, recorder, server.URL, caBundle)
+
+	for range controller.WebhookFailureThreshold - 1 {
+		monitor.Check(t.Context())
+	}
+
+	if events := drainEvents(recorder); len(events) != 0 {
+		t.Fatalf("expected no events below the failure threshold, got %v", events)
+	}
+
+	err := monitor.Ready()
+	if err != nil {
+		t.Fatalf("expected webhooks to be ready below the failure threshold, got %v", err)
+	}
+
+	monitor.Check(t.Context())
+	monitor.Check(t.Context())
+
+	events := drainEvents(recorder)
+	if len(events) != 1 || !strings.Contains(events[0], "WebhookUnreachable") {
+		t.Fatalf("expected a single WebhookUnreachable event, got %v", events)
+	}
+
+	err = monitor.Ready()
+	if !errors.Is(err, controller.ErrWebhookUnreachable) || !strings.Contains(err.Error(), testWebhookConfigName) {
+		t.Fatalf("expected readiness check to report the unreachable webhook, got %v", err)
+	}
+}
+
+func TestWebhookMonitor_ForgetsDeletedWebhooks(t *testing.T) {
+	t.Parallel()
+
+	server, caBundle := newTLSWebhookServer(t)
+	url := server.URL + "/validate"
+	server.Close()
+
+	c := newWebhookMonitorClient(t, url, caBundle)
+	monitor := controller.NewWebhookMonitorForTest(c, record.NewFakeRecorder(10), server.URL, caBundle)
+
+	for range controller.WebhookFailureThreshold {
+		monitor.Check(t.Context())
+	}
+
+	err := monitor.Ready()
+	if !errors.Is(err, controller.ErrWebhookUnreachable) {
+		t.Fatalf("expected readiness check to report the unreachable webhook, got %v", err)
+	}
+
+	err = c.Delete(t.Context(), &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigName},
+	})
+	if err != nil {
+		t.Fatalf("failed to delete webhook configuration: %v", err)
+	}
+
+	monitor.Check(t.Context())
+
+	err = monitor.Ready()
+	if err != nil {
+		t.Fatalf("expected webhooks to be ready once the unreachable webhook is deleted, got %v", err)
+	}
+}
+
+func TestWebhookMonitor_ForeignWebhooksDoNotGateReadiness(t *testing.T) {
+	t.Parallel()
+
+	server, caBundle := newTLSWebhookServer(t)
+	url := server.URL + "/validate"
+	server.Close()
+
+	c := newWebhookMonitorClient(t, url, caBundle)
+	recorder := record.NewFakeRecorder(10)
+	monitor := controller.NewWebhookMonitorForTest(c, recorder, "https://localhost:1", []byte("other"))
+
+	for range controller.WebhookFailureThreshold {
+		monitor.Check(t.Context())
+	}
+
+	events := drainEvents(recorder)
+	if len(events) != 1 || !strings.Contains(events[0], "WebhookUnreachable") {
+		t.Fatalf("expected a single WebhookUnreachable event, got %v", events)
+	}
+
+	err := monitor.Ready()
+	if err != nil {
+		t.Fatalf("expected an unreachable foreign webhook not to fail readiness, got %v", err)
+	}
+}
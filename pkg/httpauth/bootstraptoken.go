@@ -0,0 +1,51 @@
+package httpauth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/plugin/pkg/auth/authenticator/token/bootstrap"
+)
+
+// NewBootstrapTokenAuthenticator creates a token authenticator that accepts the
+// bootstrap tokens stored as Secrets in the Kommodity namespace, such as the join
+// tokens handed out to machines after attestation. Tokens authenticate as
+// system:bootstrap:<id> in the system:bootstrappers group and the groups of the token.
+func NewBootstrapTokenAuthenticator(cfg *config.KommodityConfig) authenticator.Token {
+	return bootstrap.NewTokenAuthenticator(bootstrapTokenSecrets{cfg: cfg})
+}
+
+// bootstrapTokenSecrets gets the Secrets of bootstrap tokens with the loopback client,
+// which is only set up once the API server is configured. The authenticator only
+// gets Secrets by name.
+type bootstrapTokenSecrets struct {
+	cfg *config.KommodityConfig
+}
+
+func (s bootstrapTokenSecrets) List(labels.Selector) ([]*corev1.Secret, error) {
+	return nil, ErrListNotSupported
+}
+
+func (s bootstrapTokenSecrets) Get(name string) (*corev1.Secret, error) {
+	if s.cfg.ClientConfig == nil || s.cfg.ClientConfig.LoopbackClientConfig == nil {
+		return nil, ErrAPIServerNotConfigured
+	}
+
+	client, err := kubernetes.NewForConfig(s.cfg.ClientConfig.LoopbackClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for bootstrap tokens: %w", err)
+	}
+
+	secret, err := client.CoreV1().Secrets(config.KommodityNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bootstrap token Secret %s: %w", name, err)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,226 @@
+package httpauth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"github.com/kommodity-io/kommodity/pkg/spiffe"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// ClientCASecretKey is the key of the CA bundle in the client CA Secret.
+	ClientCASecretKey = "ca.crt"
+	// MachineUserPrefix prefixes the common name of a client certificate to form the
+	// name of the authenticated machine.
+	MachineUserPrefix = "system:machine:"
+	// MachinesGroup is the group of machines authenticated with a client certificate.
+	MachinesGroup = "kommodity:machines"
+
+	// clientCASecretSyncInterval is how often the client CA Secret is read again.
+	clientCASecretSyncInterval = time.Minute
+)
+
+// ClientCAs holds the CA bundle client certificates of machine agents are verified
+// against, combining the configured file and Secret and the SPIFFE trust bundle.
+type ClientCAs struct {
+	secretName string
+	filePEM    []byte
+	pool       atomic.Pointer[x509.CertPool]
+
+	spiffeSource     *spiffe.Source
+	allowedSPIFFEIDs []string
+}
+
+// NewClientCAs loads the client CA file. The Secret is only read once the API server
+// runs, see NewHTTPMuxFactory. The SPIFFE source is nil unless SPIFFE is configured.
+// Returns nil when client certificates are not configured.
+func NewClientCAs(cfg *config.KommodityConfig, spiffeSource *spiffe.Source) (*ClientCAs, error) {
+	httpAuthConfig := cfg.AuthConfig.HTTPAuthConfig
+	if !httpAuthConfig.ClientCertificatesEnabled() && spiffeSource == nil {
+		return nil, nil //nolint:nilnil // Client certificates are optional.
+	}
+
+	clientCAs := &ClientCAs{spiffeSource: spiffeSource}
+
+	if spiffeSource != nil {
+		clientCAs.allowedSPIFFEIDs = cfg.SPIFFEConfig.AllowedIDs
+	}
+
+	if httpAuthConfig != nil {
+		clientCAs.secretName = httpAuthConfig.ClientCASecret
+	}
+
+	if httpAuthConfig != nil && httpAuthConfig.ClientCAFile != "" {
+		filePEM, err := os.ReadFile(httpAuthConfig.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+
+		clientCAs.filePEM = filePEM
+	}
+
+	err := clientCAs.update(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientCAs, nil
+}
+
+// PoolFunc returns the function the combined server gets the current CA bundle with,
+// or nil when client certificates are not configured.
+func (c *ClientCAs) PoolFunc() func() *x509.CertPool {
+	if c == nil {
+		return nil
+	}
+
+	if c.spiffeSource == nil {
+		return c.pool.Load
+	}
+
+	// The SPIFFE trust bundle rotates on its own, so it is added on every handshake.
+	return func() *x509.CertPool {
+		pool := c.pool.Load().Clone()
+
+		for _, cert := range c.spiffeSource.Bundle() {
+			pool.AddCert(cert)
+		}
+
+		return pool
+	}
+}
+
+// VerifyFunc returns the function the combined server checks verified client
+// certificates with, or nil when SPIFFE is not configured. SVIDs are only accepted
+// with an allowed SPIFFE ID, or one of the trust domain of Kommodity when no IDs are
+// configured. Certificates without a SPIFFE ID are left to the client CAs.
+func (c *ClientCAs) VerifyFunc() func([]*x509.Certificate) error {
+	if c == nil || c.spiffeSource == nil {
+		return nil
+	}
+
+	return func(chain []*x509.Certificate) error {
+		id, ok := spiffe.IDFromCertificate(chain[0])
+		if !ok {
+			return nil
+		}
+
+		allowed := c.allowedSPIFFEIDs
+		if len(allowed) == 0 {
+			allowed = []string{spiffe.TrustDomain(c.spiffeSource.ID())}
+		}
+
+		if !spiffe.MatchesID(id, allowed) {
+			return fmt.Errorf("%w: %s", ErrSPIFFEIDNotAllowed, id)
+		}
+
+		return nil
+	}
+}
+
+// NewHTTPMuxFactory reads the client CA Secret every minute, so that CAs can be
+// rotated without a restart. It has to come after the API server factory, which
+// sets up the loopback client config.
+func (c *ClientCAs) NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
+	return func(*http.ServeMux) error {
+		if c == nil || c.secretName == "" {
+			return nil
+		}
+
+		client, err := kubernetes.NewForConfig(cfg.ClientConfig.LoopbackClientConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create client for the client CA Secret: %w", err)
+		}
+
+		go c.syncSecret(ctx, client.CoreV1())
+
+		return nil
+	}
+}
+
+func (c *ClientCAs) syncSecret(ctx context.Context, client corev1client.SecretsGetter) {
+	logger := logging.FromContext(ctx).With(zap.String("secret", c.secretName))
+
+	ticker := time.NewTicker(clientCASecretSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		err := c.readSecret(ctx, client)
+		if err != nil {
+			logger.Warn("Failed to read client CA Secret", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readSecret updates the CA bundle with the Secret. A missing Secret removes its CAs,
+// the CAs of the file are always kept.
+func (c *ClientCAs) readSecret(ctx context.Context, client corev1client.SecretsGetter) error {
+	secret, err := client.Secrets(config.KommodityNamespace).Get(ctx, c.secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return c.update(nil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get client CA Secret: %w", err)
+	}
+
+	return c.update(secret.Data[ClientCASecretKey])
+}
+
+func (c *ClientCAs) update(secretPEM []byte) error {
+	pool := x509.NewCertPool()
+
+	for source, bundle := range map[string][]byte{"file": c.filePEM, "Secret": secretPEM} {
+		if len(bundle) > 0 && !pool.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("%w: no certificates in the client CA %s", ErrInvalidClientCA, source)
+		}
+	}
+
+	c.pool.Store(pool)
+
+	return nil
+}
+
+// ClientCertificateAuthenticator authenticates requests with a client certificate the
+// listener verified against the client CAs. The SPIFFE ID of the certificate, or its
+// common name when it has none, names the machine.
+func ClientCertificateAuthenticator() authenticator.Request {
+	return authenticator.RequestFunc(func(request *http.Request) (*authenticator.Response, bool, error) {
+		if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 || len(request.TLS.VerifiedChains[0]) == 0 {
+			return nil, false, nil
+		}
+
+		cert := request.TLS.VerifiedChains[0][0]
+
+		name, ok := spiffe.IDFromCertificate(cert)
+		if !ok {
+			name = cert.Subject.CommonName
+		}
+
+		return &authenticator.Response{User: &user.DefaultInfo{
+			Name:   MachineUserPrefix + name,
+			Groups: []string{MachinesGroup},
+		}}, true, nil
+	})
+}
@@ -0,0 +1,151 @@
+package httpauth_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/fake"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func newCAPEM(t *testing.T, name string) []byte {
+	t.Helper()
+
+	certPEM, _, err := certutil.GenerateSelfSignedCertKey(name, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate CA: %v", err)
+	}
+
+	return certPEM
+}
+
+func newClientCAConfig(caFile, secret string) *config.KommodityConfig {
+	return &config.KommodityConfig{AuthConfig: &config.AuthConfig{
+		HTTPAuthConfig: &config.HTTPAuthConfig{ClientCAFile: caFile, ClientCASecret: secret},
+	}}
+}
+
+func TestNewClientCAsDisabled(t *testing.T) {
+	t.Parallel()
+
+	clientCAs, err := httpauth.NewClientCAs(newClientCAConfig("", ""), nil)
+	if err != nil || clientCAs != nil {
+		t.Fatalf("expected no client CAs, got %v (%v)", clientCAs, err)
+	}
+
+	if clientCAs.PoolFunc() != nil {
+		t.Fatal("expected no pool function without client CAs")
+	}
+}
+
+func TestNewClientCAsRejectsInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+
+	err := os.WriteFile(caFile, []byte("not a certificate"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	_, err = httpauth.NewClientCAs(newClientCAConfig(caFile, ""), nil)
+	if err == nil {
+		t.Fatal("expected an error for a CA file without certificates")
+	}
+}
+
+func TestClientCAsFollowSecret(t *testing.T) {
+	t.Parallel()
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+
+	err := os.WriteFile(caFile, newCAPEM(t, "file-ca"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	clientCAs, err := httpauth.NewClientCAs(newClientCAConfig(caFile, "machine-ca"), nil)
+	if err != nil {
+		t.Fatalf("failed to load client CAs: %v", err)
+	}
+
+	pool := clientCAs.PoolFunc()
+	fromFile := pool()
+
+	client := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-ca", Namespace: config.KommodityNamespace},
+		Data:       map[string][]byte{httpauth.ClientCASecretKey: newCAPEM(t, "secret-ca")},
+	})
+
+	err = httpauth.ReadClientCASecret(clientCAs, t.Context(), client.CoreV1())
+	if err != nil {
+		t.Fatalf("failed to read client CA Secret: %v", err)
+	}
+
+	withSecret := pool()
+	if withSecret.Equal(fromFile) {
+		t.Fatal("expected the CAs of the Secret to be added")
+	}
+
+	err = client.CoreV1().Secrets(config.KommodityNamespace).Delete(t.Context(), "machine-ca", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("failed to delete client CA Secret: %v", err)
+	}
+
+	err = httpauth.ReadClientCASecret(clientCAs, t.Context(), client.CoreV1())
+	if err != nil {
+		t.Fatalf("failed to read deleted client CA Secret: %v", err)
+	}
+
+	if !pool().Equal(fromFile) {
+		t.Fatal("expected only the CAs of the file to be kept once the Secret is deleted")
+	}
+}
+
+func TestClientCertificateAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	middleware := httpauth.RequireBearerToken(httpauth.ClientCertificateAuthenticator(), nil)
+
+	var userName string
+
+	handler := middleware(func(response http.ResponseWriter, request *http.Request) {
+		if requestUser, ok := genericapirequest.UserFrom(request.Context()); ok {
+			userName = requestUser.GetName()
+		}
+
+		response.WriteHeader(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/trust/10.0.0.5", nil)
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{
+		{{Subject: pkix.Name{CommonName: "10.0.0.5"}}},
+	}}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusOK || userName != httpauth.MachineUserPrefix+"10.0.0.5" {
+		t.Fatalf("expected the machine to be authenticated, got %d as %q", recorder.Code, userName)
+	}
+
+	// Certificates the listener did not verify are not accepted.
+	request.TLS = &tls.ConnectionState{PeerCertificates: request.TLS.VerifiedChains[0]}
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an unverified certificate to be rejected, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,15 @@
+package httpauth
+
+import "errors"
+
+var (
+	// ErrInvalidClientCA is returned when a client CA bundle holds no certificates.
+	ErrInvalidClientCA = errors.New("invalid client CA")
+	// ErrSPIFFEIDNotAllowed is returned when an SVID has a SPIFFE ID that is not allowed.
+	ErrSPIFFEIDNotAllowed = errors.New("SPIFFE ID is not allowed")
+	// ErrListNotSupported is returned when bootstrap token Secrets are listed.
+	ErrListNotSupported = errors.New("listing bootstrap token Secrets is not supported")
+	// ErrAPIServerNotConfigured is returned when a bootstrap token is checked before
+	// the API server is configured.
+	ErrAPIServerNotConfigured = errors.New("API server is not configured yet")
+)
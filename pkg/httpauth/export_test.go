@@ -0,0 +1,6 @@
+package httpauth
+
+// Exported aliases for black-box testing.
+//
+//nolint:gochecknoglobals // test exports
+var ReadClientCASecret = (*ClientCAs).readSecret
@@ -0,0 +1,142 @@
+// Package httpauth authenticates requests to the plain HTTP endpoints of Kommodity
+// with the OIDC provider of the API server.
+package httpauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	bearertoken "k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/request/union"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	oidc "k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+)
+
+// Middleware wraps the handler of an endpoint.
+type Middleware func(http.HandlerFunc) http.HandlerFunc
+
+// NewOIDCAuthenticator creates a token authenticator that accepts ID tokens issued by
+// the configured OIDC provider for the configured client.
+func NewOIDCAuthenticator(ctx context.Context, oidcConfig *config.OIDCConfig) (authenticator.Token, error) {
+	prefix := ""
+
+	oidcAuth, err := oidc.New(ctx, oidc.Options{
+		JWTAuthenticator: apiserver.JWTAuthenticator{
+			Issuer: apiserver.Issuer{
+				URL:       oidcConfig.IssuerURL,
+				Audiences: []string{oidcConfig.ClientID},
+			},
+			ClaimMappings: apiserver.ClaimMappings{
+				Username: apiserver.PrefixedClaimOrExpression{
+					Claim:  oidcConfig.UsernameClaim,
+					Prefix: &prefix,
+				},
+				Groups: apiserver.PrefixedClaimOrExpression{
+					Claim:  oidcConfig.GroupsClaim,
+					Prefix: &prefix,
+				},
+			},
+			ClaimValidationRules: []apiserver.ClaimValidationRule{
+				{
+					Claim:         "aud",
+					RequiredValue: oidcConfig.ClientID,
+				},
+			},
+		},
+		SupportedSigningAlgs: []string{"RS256"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup oidc authenticator: %w", err)
+	}
+
+	return oidcAuth, nil
+}
+
+// NewMiddleware returns the middleware protecting the metadata and attestation
+// endpoints. Unless HTTP authentication is enabled, it leaves the handlers as they are.
+// Machine agents can authenticate with a client certificate or a join token instead
+// of an OIDC token.
+func NewMiddleware(ctx context.Context, cfg *config.KommodityConfig) (Middleware, error) {
+	httpAuthConfig := cfg.AuthConfig.HTTPAuthConfig
+	if httpAuthConfig == nil || !httpAuthConfig.Enabled {
+		return func(handler http.HandlerFunc) http.HandlerFunc { return handler }, nil
+	}
+
+	var authenticators []authenticator.Request
+
+	if httpAuthConfig.ClientCertificatesEnabled() || cfg.SPIFFEConfig.Enabled() {
+		authenticators = append(authenticators, ClientCertificateAuthenticator())
+	}
+
+	if cfg.AuthConfig.OIDCConfig != nil {
+		oidcAuth, err := NewOIDCAuthenticator(ctx, cfg.AuthConfig.OIDCConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		authenticators = append(authenticators, bearertoken.New(oidcAuth))
+	}
+
+	if len(authenticators) == 0 {
+		return nil, config.ErrHTTPAuthWithoutOIDC
+	}
+
+	// Machines can also present the join token they were handed out after attestation.
+	authenticators = append(authenticators, bearertoken.New(NewBootstrapTokenAuthenticator(cfg)))
+
+	return RequireBearerToken(union.New(authenticators...), httpAuthConfig.ExemptPaths), nil
+}
+
+// RequireBearerToken rejects requests the authenticator does not accept with 401,
+// except for requests to the exempt paths. An exempt path ending in a slash exempts
+// every path below it. The authenticated user is added to the context of the request.
+func RequireBearerToken(auth authenticator.Request, exemptPaths []string) Middleware {
+	return func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(response http.ResponseWriter, request *http.Request) {
+			if isExempt(request.URL.Path, exemptPaths) {
+				handler(response, request)
+
+				return
+			}
+
+			authResponse, ok, err := auth.AuthenticateRequest(request)
+			if err != nil || !ok {
+				response.Header().Set("WWW-Authenticate", `Bearer realm="kommodity"`)
+				http.Error(response, "Unauthorized", http.StatusUnauthorized)
+
+				return
+			}
+
+			handler(response, request.WithContext(genericapirequest.WithUser(request.Context(), authResponse.User)))
+		}
+	}
+}
+
+func isExempt(path string, exemptPaths []string) bool {
+	return slices.ContainsFunc(exemptPaths, func(exemptPath string) bool {
+		return path == exemptPath || (strings.HasSuffix(exemptPath, "/") && strings.HasPrefix(path, exemptPath))
+	})
+}
+
+// RequireGroup rejects requests of users outside the group with 403. It expects the
+// user to be authenticated already.
+func RequireGroup(group string) Middleware {
+	return func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(response http.ResponseWriter, request *http.Request) {
+			requestUser, found := genericapirequest.UserFrom(request.Context())
+			if !found || !slices.Contains(requestUser.GetGroups(), group) {
+				http.Error(response, "Forbidden", http.StatusForbidden)
+
+				return
+			}
+
+			handler(response, request)
+		}
+	}
+}
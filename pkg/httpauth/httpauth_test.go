@@ -0,0 +1,113 @@
+package httpauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const validToken = "valid-token"
+
+// tokenAuthenticator accepts the valid token and nothing else.
+//
+//nolint:gochecknoglobals // Shared by all tests.
+var tokenAuthenticator = authenticator.RequestFunc(func(request *http.Request) (*authenticator.Response, bool, error) {
+	if request.Header.Get("Authorization") != "Bearer "+validToken {
+		return nil, false, nil
+	}
+
+	return &authenticator.Response{User: &user.DefaultInfo{Name: "operator"}}, true, nil
+})
+
+func serve(middleware httpauth.Middleware, path, token string) (*httptest.ResponseRecorder, string) {
+	var userName string
+
+	handler := middleware(func(response http.ResponseWriter, request *http.Request) {
+		if requestUser, ok := genericapirequest.UserFrom(request.Context()); ok {
+			userName = requestUser.GetName()
+		}
+
+		response.WriteHeader(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	return recorder, userName
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	t.Parallel()
+
+	middleware := httpauth.RequireBearerToken(tokenAuthenticator, []string{"/nonce", "/configs/user-data", "/latest/"})
+
+	tests := map[string]struct {
+		path         string
+		token        string
+		expectedCode int
+		expectedUser string
+	}{
+		"exempt path":                 {path: "/nonce", expectedCode: http.StatusOK},
+		"exempt path with token":      {path: "/configs/user-data", token: "invalid", expectedCode: http.StatusOK},
+		"missing token":               {path: "/report/10.0.0.1/trust", expectedCode: http.StatusUnauthorized},
+		"invalid token":               {path: "/report/10.0.0.1/trust", token: "invalid", expectedCode: http.StatusUnauthorized},
+		"valid token":                 {path: "/report/10.0.0.1/trust", token: validToken, expectedCode: http.StatusOK, expectedUser: "operator"},
+		"exempt path is not a prefix": {path: "/nonce/other", expectedCode: http.StatusUnauthorized},
+		"exempt subtree":              {path: "/latest/meta-data/hostname", expectedCode: http.StatusOK},
+		"exempt subtree root":         {path: "/latest", expectedCode: http.StatusUnauthorized},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder, userName := serve(middleware, test.path, test.token)
+
+			if recorder.Code != test.expectedCode {
+				t.Fatalf("expected status %d, got %d", test.expectedCode, recorder.Code)
+			}
+
+			if userName != test.expectedUser {
+				t.Fatalf("expected user %q, got %q", test.expectedUser, userName)
+			}
+
+			if test.expectedCode == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Fatalf("expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestNewMiddleware(t *testing.T) {
+	t.Parallel()
+
+	middleware, err := httpauth.NewMiddleware(t.Context(), &config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{HTTPAuthConfig: &config.HTTPAuthConfig{}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create middleware: %v", err)
+	}
+
+	recorder, _ := serve(middleware, "/report/10.0.0.1/trust", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected disabled authentication to pass requests through, got %d", recorder.Code)
+	}
+
+	_, err = httpauth.NewMiddleware(t.Context(), &config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{HTTPAuthConfig: &config.HTTPAuthConfig{Enabled: true}},
+	})
+	if err == nil {
+		t.Fatalf("expected enabled authentication without OIDC to fail")
+	}
+}
@@ -0,0 +1,61 @@
+package httpauth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// OperatorCAs holds the CA bundle of KOMMODITY_CLIENT_CA_FILE that client certificates
+// of operators are verified against. It is a trust domain of its own: certificates of
+// operators are never accepted as the identity of a machine, and certificates of
+// machines never authenticate an operator. The file is watched, so that the CAs can be
+// rotated without a restart.
+type OperatorCAs struct {
+	content *dynamiccertificates.DynamicFileCAContent
+}
+
+// NewOperatorCAs loads the client CA file of operators and watches it until the
+// context is done. Returns nil when client certificates of operators are not accepted.
+func NewOperatorCAs(ctx context.Context, cfg *config.KommodityConfig) (*OperatorCAs, error) {
+	if !cfg.AuthConfig.Apply || cfg.AuthConfig.ClientCAFile == "" {
+		return nil, nil //nolint:nilnil // Client certificates of operators are optional.
+	}
+
+	content, err := dynamiccertificates.NewDynamicCAContentFromFile("operator-client-ca", cfg.AuthConfig.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load operator client CA file %s: %w", cfg.AuthConfig.ClientCAFile, err)
+	}
+
+	go content.Run(ctx, 1)
+
+	return &OperatorCAs{content: content}, nil
+}
+
+// VerifyOptions returns the options client certificates of operators are verified
+// with, and false when no CAs are loaded.
+func (c *OperatorCAs) VerifyOptions() (x509.VerifyOptions, bool) {
+	return c.content.VerifyOptions()
+}
+
+// CertificatesFunc returns the function the combined server gets the current CAs of
+// operators with, or nil when client certificates of operators are not accepted.
+func (c *OperatorCAs) CertificatesFunc() func() []*x509.Certificate {
+	if c == nil {
+		return nil
+	}
+
+	return func() []*x509.Certificate {
+		// The file is only loaded when it holds certificates, see NewOperatorCAs.
+		certs, err := certutil.ParseCertsPEM(c.content.CurrentCABundleContent())
+		if err != nil {
+			return nil
+		}
+
+		return certs
+	}
+}
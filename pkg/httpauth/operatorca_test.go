@@ -0,0 +1,64 @@
+package httpauth_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+)
+
+func TestNewOperatorCAsDisabled(t *testing.T) {
+	t.Parallel()
+
+	operatorCAs, err := httpauth.NewOperatorCAs(t.Context(), &config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{Apply: true},
+	})
+	if err != nil || operatorCAs != nil {
+		t.Fatalf("expected no operator CAs, got %v (%v)", operatorCAs, err)
+	}
+
+	if operatorCAs.CertificatesFunc() != nil {
+		t.Fatal("expected no certificates function without operator CAs")
+	}
+}
+
+func TestOperatorCAsFollowFile(t *testing.T) {
+	t.Parallel()
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+
+	err := os.WriteFile(caFile, newCAPEM(t, "operator"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	operatorCAs, err := httpauth.NewOperatorCAs(t.Context(), &config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{Apply: true, ClientCAFile: caFile},
+	})
+	if err != nil || operatorCAs == nil {
+		t.Fatalf("expected operator CAs, got %v (%v)", operatorCAs, err)
+	}
+
+	certificates := operatorCAs.CertificatesFunc()
+	if len(certificates()) == 0 || !strings.HasPrefix(certificates()[0].Subject.CommonName, "operator@") {
+		t.Fatalf("expected the CA of the file, got %v", certificates())
+	}
+
+	err = os.WriteFile(caFile, newCAPEM(t, "rotated"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to rotate CA file: %v", err)
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for !strings.HasPrefix(certificates()[0].Subject.CommonName, "rotated@") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the rotated CA to be loaded")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
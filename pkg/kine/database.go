@@ -0,0 +1,66 @@
+package kine
+
+import (
+	"strconv"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+// postgresStatementTimeout is the PostgreSQL setting aborting statements running
+// longer, in milliseconds. It is passed on to the server with the connection URI.
+const postgresStatementTimeout = "statement_timeout"
+
+// kineArgs returns the flags kine is started with. A nil database config keeps the
+// defaults of kine.
+func kineArgs(cfg *config.KommodityConfig) []string {
+	args := []string{
+		"--listen-address=" + cfg.KineURI,
+		"--endpoint=" + databaseEndpoint(cfg),
+		"--metrics-bind-address=0",
+	}
+
+	dbConfig := cfg.DatabaseConfig
+	if dbConfig == nil {
+		return args
+	}
+
+	// Kine keeps the default of database/sql for zero idle connections, and none for
+	// negative ones.
+	maxIdle := dbConfig.MaxIdleConnections
+	if maxIdle == 0 {
+		maxIdle = -1
+	}
+
+	return append(args,
+		"--datastore-max-open-connections="+strconv.Itoa(dbConfig.MaxOpenConnections),
+		"--datastore-max-idle-connections="+strconv.Itoa(maxIdle),
+		"--datastore-connection-max-lifetime="+dbConfig.ConnectionMaxLifetime.String(),
+		"--slow-sql-threshold="+dbConfig.SlowQueryThreshold.String(),
+		// Kommodity schedules the compactions itself, as kine ignores the compactions
+		// requested through its API while it compacts by itself.
+		"--compact-interval=0",
+		"--compact-min-retain="+strconv.Itoa(dbConfig.CompactionRetention),
+	)
+}
+
+// databaseEndpoint returns the database URI kine connects to. The statement timeout is
+// set on PostgreSQL connections, unless the URI sets one itself.
+func databaseEndpoint(cfg *config.KommodityConfig) string {
+	if cfg.DatabaseConfig == nil || cfg.DatabaseConfig.StatementTimeout == 0 ||
+		(cfg.DBURI.Scheme != "postgres" && cfg.DBURI.Scheme != "postgresql") {
+		return cfg.DBURI.String()
+	}
+
+	query := cfg.DBURI.Query()
+	if query.Has(postgresStatementTimeout) {
+		return cfg.DBURI.String()
+	}
+
+	query.Set(postgresStatementTimeout,
+		strconv.FormatInt(cfg.DatabaseConfig.StatementTimeout.Milliseconds(), 10))
+
+	endpoint := *cfg.DBURI
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
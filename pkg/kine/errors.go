@@ -0,0 +1,12 @@
+package kine
+
+import "errors"
+
+var (
+	// ErrKineNotRunning is returned by the readiness check while kine is starting or restarting.
+	ErrKineNotRunning = errors.New("kine is not running")
+	// ErrDatabaseUnreachable is returned by the readiness check while kine cannot read from the database.
+	ErrDatabaseUnreachable = errors.New("database is unreachable through kine")
+	// ErrKineRestartsExhausted is returned when kine keeps failing after the configured number of restarts.
+	ErrKineRestartsExhausted = errors.New("kine failed too many times")
+)
@@ -2,11 +2,17 @@
 package kine
 
 import (
+	"time"
+
 	"github.com/kommodity-io/kommodity/pkg/config"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 )
 
+// countPollPeriod is how often the stores served through the RESTOptionsGetter count
+// their objects, like the default of the API server for etcd.
+const countPollPeriod = time.Minute
+
 // NewKineStorageConfig creates the storage configurations to connect to Kine.
 func NewKineStorageConfig(cfg *config.KommodityConfig, codec runtime.Codec) (*storagebackend.Config, error) {
 	return &storagebackend.Config{
@@ -16,5 +22,6 @@ func NewKineStorageConfig(cfg *config.KommodityConfig, codec runtime.Codec) (*st
 		Transport: storagebackend.TransportConfig{
 			ServerList: []string{cfg.KineURI},
 		},
+		CountMetricPollPeriod: countPollPeriod,
 	}, nil
 }
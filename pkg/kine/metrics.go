@@ -0,0 +1,51 @@
+package kine
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"k8s.io/component-base/metrics"
+)
+
+// MetricsPath is the path of the metrics of kine, like the statistics of its database
+// connection pool (go_sql_*) and the duration of its queries (kine_sql_time_seconds).
+const MetricsPath = "/kine/metrics"
+
+// currentRegistry holds the metrics registry of the running kine.
+type currentRegistry struct {
+	lock     sync.RWMutex
+	registry metrics.KubeRegistry
+}
+
+func (r *currentRegistry) set(registry metrics.KubeRegistry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.registry = registry
+}
+
+// ServeHTTP serves the metrics of the running kine, and fails before it first started.
+func (r *currentRegistry) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	r.lock.RLock()
+	registry := r.registry
+	r.lock.RUnlock()
+
+	if registry == nil {
+		http.Error(writer, ErrKineNotRunning.Error(), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	metrics.HandlerFor(registry, metrics.HandlerOpts{}).ServeHTTP(writer, req)
+}
+
+// NewHTTPMuxFactory serves the metrics of kine on the combined server, as kine does not
+// serve them itself.
+func (ks *Server) NewHTTPMuxFactory() combinedserver.HTTPMuxFactory {
+	return func(mux *http.ServeMux) error {
+		mux.Handle(MetricsPath, &ks.metricsRegistry)
+
+		return nil
+	}
+}
@@ -3,6 +3,7 @@ package kine
 import (
 	"fmt"
 
+	"github.com/kommodity-io/kommodity/pkg/config"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic"
@@ -15,6 +16,8 @@ import (
 type RESTOptionsGetter struct {
 	StorageConfig storagebackend.Config
 	Options       *options.StorageFactoryRestOptionsFactory
+	// WatchCacheConfig disables the watch cache of the resources it lists as disabled.
+	WatchCacheConfig *config.WatchCacheConfig
 }
 
 // GetRESTOptions returns RESTOptions for the given resource.
@@ -30,17 +33,26 @@ func (g *RESTOptionsGetter) GetRESTOptions(resource schema.GroupResource,
 		return options, nil
 	}
 
+	decorator := registry.StorageWithCacher()
+	if !g.WatchCacheConfig.Enabled(resource, true) {
+		decorator = genericregistry.UndecoratedStorage
+	}
+
 	return genericregistry.RESTOptions{
-		StorageConfig:           g.StorageConfig.ForResource(resource),
-		DeleteCollectionWorkers: 1,
-		EnableGarbageCollection: true,
-		Decorator:               registry.StorageWithCacher(),
-		ResourcePrefix:          resource.Resource,
+		StorageConfig:             g.StorageConfig.ForResource(resource),
+		DeleteCollectionWorkers:   1,
+		EnableGarbageCollection:   true,
+		Decorator:                 decorator,
+		ResourcePrefix:            resource.Resource,
+		CountMetricPollPeriod:     g.StorageConfig.CountMetricPollPeriod,
+		StorageObjectCountTracker: g.StorageConfig.StorageObjectCountTracker,
 	}, nil
 }
 
 // NewKineRESTOptionsGetter creates a new Kine-backed RESTOptionsGetter.
-// Pass in the storagebackend.Config you already use for Namespaces.
-func NewKineRESTOptionsGetter(cfg storagebackend.Config) genericregistry.RESTOptionsGetter {
-	return &RESTOptionsGetter{StorageConfig: cfg}
+// Pass in the storagebackend.Config you already use for Namespaces. Resources are
+// served from a watch cache unless the watch cache config disables it.
+func NewKineRESTOptionsGetter(cfg storagebackend.Config,
+	watchCache *config.WatchCacheConfig) genericregistry.RESTOptionsGetter {
+	return &RESTOptionsGetter{StorageConfig: cfg, WatchCacheConfig: watchCache}
 }
@@ -0,0 +1,54 @@
+package kine_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kine"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+)
+
+func TestRESTOptionsGetterWatchCache(t *testing.T) {
+	t.Parallel()
+
+	watchCache := &config.WatchCacheConfig{Resources: map[string]bool{
+		"secrets":                   true,
+		"clusters.cluster.x-k8s.io": false,
+	}}
+
+	tests := map[string]struct {
+		resource schema.GroupResource
+		cached   bool
+	}{
+		"machines cached by default": {
+			resource: schema.GroupResource{Group: "cluster.x-k8s.io", Resource: "machines"},
+			cached:   true,
+		},
+		"clusters disabled": {
+			resource: schema.GroupResource{Group: "cluster.x-k8s.io", Resource: "clusters"},
+			cached:   false,
+		},
+	}
+
+	getter := kine.NewKineRESTOptionsGetter(storagebackend.Config{}, watchCache)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options, err := getter.GetRESTOptions(test.resource, nil)
+			if err != nil {
+				t.Fatalf("failed to get REST options: %v", err)
+			}
+
+			undecorated := reflect.ValueOf(options.Decorator).Pointer() ==
+				reflect.ValueOf(genericregistry.UndecoratedStorage).Pointer()
+			if undecorated == test.cached {
+				t.Fatalf("expected %s to be cached: %v", test.resource, test.cached)
+			}
+		})
+	}
+}
@@ -3,74 +3,283 @@ package kine
 import (
 	"context"
 	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/grpc/credentials/insecure"
 
 	kineconfig "github.com/k3s-io/kine/pkg/app"
+	"github.com/k3s-io/kine/pkg/endpoint"
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
 	"github.com/kommodity-io/kommodity/pkg/config"
 	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"k8s.io/component-base/metrics"
 )
 
 const (
 	kineDialTimeout = 2 * time.Second
+
+	// kineHealthInterval is how often the supervisor checks that kine still serves requests.
+	kineHealthInterval = 10 * time.Second
+
+	// kineHealthFailureThreshold is the number of consecutive failed health checks after
+	// which kine is restarted.
+	kineHealthFailureThreshold = 3
+
+	// kineInitialBackoff and kineMaxBackoff bound the exponential delay between restarts.
+	kineInitialBackoff = time.Second
+	kineMaxBackoff     = time.Minute
+
+	// Names of the checks on /readyz.
+	kineReadinessCheckName     = "kine"
+	databaseReadinessCheckName = "database"
 )
 
 // Server represents a Kine server instance.
 type Server struct {
 	cfg *config.KommodityConfig
+
+	listening      atomic.Bool
+	dbHealthy      atomic.Bool
+	initialBackoff time.Duration
+	healthInterval time.Duration
+
+	metricsRegistry currentRegistry
 }
 
 // NewServer creates a new Kine server instance.
 func NewServer(cfg *config.KommodityConfig) *Server {
 	return &Server{
-		cfg: cfg,
+		cfg:            cfg,
+		initialBackoff: kineInitialBackoff,
+		healthInterval: kineHealthInterval,
+	}
+}
+
+// StartKine runs kine in-process until the context is cancelled. Kine is restarted with
+// exponential backoff when it fails to start or stops answering health checks, and an
+// error is only returned once it failed more than the configured number of restarts in
+// a row. The state of kine is reported on /readyz.
+func (ks *Server) StartKine(ctx context.Context) error {
+	ctx = logging.WithModule(ctx, logging.ModuleKine)
+	logger := logging.FromContext(ctx)
+
+	for name, check := range map[string]combinedserver.ReadinessCheckFunc{
+		kineReadinessCheckName:     ks.Check,
+		databaseReadinessCheckName: ks.CheckDatabase,
+	} {
+		err := combinedserver.RegisterReadinessCheck(name, check)
+		if err != nil {
+			logger.Warn("Failed to register readiness check", zap.String("check", name), zap.Error(err))
+
+			continue
+		}
+
+		defer combinedserver.UnregisterReadinessCheck(name)
+	}
+
+	for restarts := 0; ; restarts++ {
+		if restarts > 0 {
+			backoff := restartBackoff(ks.initialBackoff, restarts)
+
+			logger.Warn("Restarting kine", zap.Int("restart", restarts), zap.Duration("backoff", backoff))
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+		}
+
+		healthy, err := ks.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if healthy {
+			// Only failures in a row count towards the limit.
+			restarts = 0
+		}
+
+		if restarts >= ks.cfg.KineMaxRestarts {
+			return fmt.Errorf("%w: %w", ErrKineRestartsExhausted, err)
+		}
+
+		logger.Error("Kine failed", zap.Error(err))
+	}
+}
+
+// restartBackoff returns the delay before the given restart in a row, doubling from the
+// initial backoff up to kineMaxBackoff. It stops doubling at the maximum, so the delay
+// cannot overflow however often kine is restarted.
+func restartBackoff(initial time.Duration, restarts int) time.Duration {
+	backoff := initial
+
+	for range restarts - 1 {
+		if backoff >= kineMaxBackoff {
+			break
+		}
+
+		backoff *= 2
+	}
+
+	return min(backoff, kineMaxBackoff)
+}
+
+// Check reports whether kine is running and accepts connections on its socket.
+func (ks *Server) Check() error {
+	if !ks.listening.Load() {
+		return ErrKineNotRunning
+	}
+
+	network, address := "tcp", ks.cfg.KineURI
+	if socket, found := strings.CutPrefix(ks.cfg.KineURI, "unix://"); found {
+		network, address = "unix", socket
+	} else if _, hostPort, found := strings.Cut(ks.cfg.KineURI, "://"); found {
+		address = hostPort
+	}
+
+	conn, err := net.DialTimeout(network, address, kineDialTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrKineNotRunning, err)
 	}
+
+	_ = conn.Close()
+
+	return nil
 }
 
-// StartKine starts a Kine server based on the provided Kommodity configuration.
-func (ks *Server) StartKine() error {
-	kineApp := kineconfig.New()
+// CheckDatabase reports whether kine could read from the database during its last
+// health check.
+func (ks *Server) CheckDatabase() error {
+	if !ks.dbHealthy.Load() {
+		return ErrDatabaseUnreachable
+	}
+
+	return nil
+}
+
+// runOnce starts kine and supervises it until it fails its health checks or the context
+// is cancelled. It reports whether kine was healthy at some point before it failed.
+func (ks *Server) runOnce(ctx context.Context) (bool, error) {
+	kineConfig := kineconfig.Config(kineArgs(ks.cfg))
+
+	// Every start gets a fresh registry, as kine registers the statistics of the
+	// connection pool it opens.
+	registry := metrics.NewKubeRegistry()
+	kineConfig.MetricsRegisterer = registry.Registerer()
+	ks.metricsRegistry.set(registry)
+
+	runCtx, cancel := context.WithCancel(ctx)
+
+	var waitGroup sync.WaitGroup
 
-	err := kineApp.Run([]string{
-		"kine",
-		"--listen-address=" + ks.cfg.KineURI,
-		"--endpoint=" + ks.cfg.DBURI.String(),
-		"--metrics-bind-address=0",
+	kineConfig.WaitGroup = &waitGroup
+
+	// Kine stops its server and backend when the context is cancelled; wait for it so
+	// that the listener is released before the next start.
+	defer func() {
+		ks.listening.Store(false)
+		ks.dbHealthy.Store(false)
+		cancel()
+		waitGroup.Wait()
+	}()
+
+	_, err := endpoint.Listen(runCtx, kineConfig)
+	if err != nil {
+		return false, fmt.Errorf("failed to start kine: %w", err)
+	}
+
+	ks.listening.Store(true)
+
+	healthy := false
+	failures := 0
+
+	ticker := time.NewTicker(ks.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		err = ks.ping(ctx)
+		if err == nil {
+			healthy = true
+			failures = 0
+		} else {
+			failures++
+		}
+
+		ks.dbHealthy.Store(err == nil)
+
+		if failures >= kineHealthFailureThreshold {
+			return healthy, fmt.Errorf("kine failed %d health checks: %w", failures, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return healthy, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewClient returns an etcd client connected to kine. The caller must close it.
+func NewClient(cfg *config.KommodityConfig) (*clientv3.Client, error) {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{cfg.KineURI},
+		DialTimeout: kineDialTimeout,
+		DialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to start kine: %w", err)
+		return nil, fmt.Errorf("failed to connect to kine: %w", err)
+	}
+
+	return cli, nil
+}
+
+// ping checks that kine accepts connections and serves reads.
+func (ks *Server) ping(ctx context.Context) error {
+	cli, err := NewClient(ks.cfg)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	getCtx, cancel := context.WithTimeout(ctx, kineDialTimeout)
+	defer cancel()
+
+	_, err = cli.Get(getCtx, "health-check")
+	if err != nil {
+		return fmt.Errorf("failed to read from kine: %w", err)
 	}
 
 	return nil
 }
 
-// WaitForKine waits until the Kine server is ready to accept TCP connections.
+// WaitForKine waits until the Kine server is ready to accept TCP connections. It gives
+// up without closing the ready channel when the context is cancelled.
 func (ks *Server) WaitForKine(ctx context.Context, readyChan chan struct{}) {
 	go func() {
-		logger := logging.FromContext(ctx)
+		logger := logging.ForModule(logging.FromContext(ctx), logging.ModuleKine)
 		for {
 			logger.Info("Waiting for Kine to be ready (grpc health check)...")
 
-			cli, err := clientv3.New(clientv3.Config{
-				Endpoints:   []string{ks.cfg.KineURI},
-				DialTimeout: kineDialTimeout,
-				DialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
-			})
-			if err == nil {
-				_, err := cli.Get(ctx, "health-check")
-				_ = cli.Close()
+			if ks.ping(ctx) == nil {
+				close(readyChan)
 
-				if err == nil {
-					close(readyChan)
-
-					return
-				}
+				return
 			}
 
-			time.Sleep(kineDialTimeout)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(kineDialTimeout):
+			}
 		}
 	}()
 }
@@ -0,0 +1,191 @@
+//nolint:testpackage // white-box tests shorten the backoff of the supervisor
+package kine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+func newTestServer(t *testing.T, dbURI string, maxRestarts int) *Server {
+	t.Helper()
+
+	// Unix socket paths are limited to about 100 bytes, which the directories created
+	// by t.TempDir easily exceed.
+	dir, err := os.MkdirTemp("", "kine")
+	if err != nil {
+		t.Fatalf("failed to create kine directory: %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	parsed, err := url.Parse(dbURI)
+	if err != nil {
+		t.Fatalf("failed to parse database URI: %v", err)
+	}
+
+	if parsed.Scheme == "sqlite" {
+		parsed.Host = ""
+		parsed.Path = filepath.Join(dir, "state.db")
+	}
+
+	server := NewServer(&config.KommodityConfig{
+		DBURI:           parsed,
+		KineURI:         "unix://" + filepath.Join(dir, "kine.sock"),
+		KineMaxRestarts: maxRestarts,
+	})
+	server.initialBackoff = time.Millisecond
+	server.healthInterval = 10 * time.Millisecond
+
+	return server
+}
+
+func TestStartKineGivesUpAfterMaxRestarts(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, "unknown://database", 2)
+
+	err := server.StartKine(t.Context())
+	if !errors.Is(err, ErrKineRestartsExhausted) {
+		t.Fatalf("expected %v, got %v", ErrKineRestartsExhausted, err)
+	}
+
+	if !errors.Is(server.Check(), ErrKineNotRunning) {
+		t.Fatalf("expected readiness check to fail after kine gave up")
+	}
+}
+
+func TestStartKineRunsUntilCancelled(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, "sqlite://state.db", 0)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.StartKine(ctx)
+	}()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for server.CheckDatabase() != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("kine did not become ready: %v", server.CheckDatabase())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := server.Check()
+	if err != nil {
+		t.Fatalf("expected kine to accept connections: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.metricsRegistry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+
+	if !strings.Contains(recorder.Body.String(), "go_sql_open_connections") {
+		t.Fatalf("expected the connection pool statistics in the metrics, got %s", recorder.Body.String())
+	}
+
+	cancel()
+
+	err = <-done
+	if err != nil {
+		t.Fatalf("expected kine to stop without error, got %v", err)
+	}
+
+	if server.Check() == nil {
+		t.Fatalf("expected readiness check to fail after kine stopped")
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		restarts int
+		expected time.Duration
+	}{
+		"first restart":   {restarts: 1, expected: kineInitialBackoff},
+		"doubled":         {restarts: 3, expected: 4 * kineInitialBackoff},
+		"capped":          {restarts: 10, expected: kineMaxBackoff},
+		"beyond 64 bits":  {restarts: 100, expected: kineMaxBackoff},
+		"unlimited retry": {restarts: 1 << 20, expected: kineMaxBackoff},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			backoff := restartBackoff(kineInitialBackoff, test.restarts)
+			if backoff != test.expected {
+				t.Fatalf("expected a backoff of %s for restart %d, got %s", test.expected, test.restarts, backoff)
+			}
+		})
+	}
+}
+
+func TestKineArgsConfigureTheDatabase(t *testing.T) {
+	t.Parallel()
+
+	dbURI, err := url.Parse("postgres://kommodity@localhost:5432/kommodity?sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to parse database URI: %v", err)
+	}
+
+	args := kineArgs(&config.KommodityConfig{
+		DBURI:   dbURI,
+		KineURI: "unix://kine.sock",
+		DatabaseConfig: &config.DatabaseConfig{
+			MaxOpenConnections:    20,
+			ConnectionMaxLifetime: time.Hour,
+			StatementTimeout:      30 * time.Second,
+			SlowQueryThreshold:    500 * time.Millisecond,
+		},
+	})
+
+	for _, expected := range []string{
+		"--endpoint=postgres://kommodity@localhost:5432/kommodity?sslmode=disable&statement_timeout=30000",
+		"--datastore-max-open-connections=20",
+		"--datastore-max-idle-connections=-1",
+		"--datastore-connection-max-lifetime=1h0m0s",
+		"--slow-sql-threshold=500ms",
+	} {
+		if !slices.Contains(args, expected) {
+			t.Fatalf("expected %q in %v", expected, args)
+		}
+	}
+}
+
+func TestDatabaseEndpointKeepsStatementTimeoutOfURI(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURI := range []string{
+		"postgres://localhost/kommodity?statement_timeout=1000",
+		"sqlite:///var/lib/kommodity/state.db",
+	} {
+		dbURI, err := url.Parse(rawURI)
+		if err != nil {
+			t.Fatalf("failed to parse database URI: %v", err)
+		}
+
+		endpoint := databaseEndpoint(&config.KommodityConfig{
+			DBURI:          dbURI,
+			DatabaseConfig: &config.DatabaseConfig{StatementTimeout: time.Minute},
+		})
+		if endpoint != rawURI {
+			t.Fatalf("expected endpoint %q, got %q", rawURI, endpoint)
+		}
+	}
+}
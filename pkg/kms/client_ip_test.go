@@ -229,3 +229,31 @@ func TestSanitizeIPInvalid(t *testing.T) {
 		t.Fatalf("expected empty string for invalid IP, got %s", result)
 	}
 }
+
+// FuzzExtractClientIP checks that the client IP resolved from the proxy headers of
+// untrusted machines is always empty or a canonical IP address.
+func FuzzExtractClientIP(f *testing.F) {
+	f.Add("10.0.0.2, 10.0.0.3", "10.0.0.4", "10.0.0.5:80")
+	f.Add("", "", "[fd00::1]:443")
+	f.Add("fe80::1%eth0", "[fe80::1%eth0]:80", "")
+	f.Add(",,,", " ", "not-an-ip")
+
+	f.Fuzz(func(t *testing.T, forwardedFor, realIP, envoyAddr string) {
+		incomingMD := metadata.Pairs(
+			"x-forwarded-for", forwardedFor,
+			"x-real-ip", realIP,
+			"x-envoy-external-address", envoyAddr,
+		)
+		ctx := metadata.NewIncomingContext(context.Background(), incomingMD)
+
+		clientIP, err := kms.ExtractClientIP(ctx)
+		if err != nil {
+			return
+		}
+
+		parsed := net.ParseIP(clientIP)
+		if parsed == nil || parsed.String() != clientIP {
+			t.Fatalf("resolved %q, which is not a canonical IP", clientIP)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+	"k8s.io/apiserver/pkg/storage/value"
+	kmsservice "k8s.io/kms/pkg/service"
+)
+
+const (
+	// EncryptionProviderName is the KMS provider name recorded in the prefix of
+	// every encrypted value, k8s:enc:kms:v2:<name>:.
+	EncryptionProviderName = "kommodity"
+
+	encryptionReadinessCheckName = "kms-encryption"
+	encryptionSocketName         = "kms.sock"
+	encryptionConfigFileName     = "encryption-config.yaml"
+	encryptionPluginTimeout      = 10 * time.Second
+	encryptionSocketWaitTimeout  = 5 * time.Second
+	encryptionSocketPollInterval = 50 * time.Millisecond
+	encryptionConfigFileMode     = 0o600
+
+	// encryptionConfigTemplate encrypts Secrets with the in-process KMS v2 plugin.
+	// The identity provider keeps Secrets written before encryption was enabled
+	// readable; they are encrypted the next time they are written.
+	encryptionConfigTemplate = `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+  - resources:
+      - secrets
+    providers:
+      - kms:
+          apiVersion: v2
+          name: %s
+          endpoint: unix://%s
+          timeout: %s
+      - identity: {}
+`
+)
+
+// NewSecretsTransformer sets up envelope encryption of Secrets at rest and returns
+// the storage transformer to install on the Secrets storage. It returns nil when
+// encryption is disabled.
+//
+// The key encryption key backend is served as a KMS v2 plugin on a private unix
+// socket and consumed through the API server's own KMS v2 implementation, so data
+// encryption keys are generated, cached and rotated exactly as in upstream
+// Kubernetes: a change of the backend key ID makes the next write use a new DEK,
+// while values written under earlier keys remain decryptable. The plugin is
+// stopped when ctx is cancelled.
+func NewSecretsTransformer(ctx context.Context, cfg *config.EncryptionConfig,
+	apiServerID string) (value.Transformer, error) {
+	if !cfg.Enabled() {
+		return nil, nil //nolint:nilnil // nil transformer means data is stored as is.
+	}
+
+	logger := logging.FromContext(ctx)
+
+	kek, err := newKeyEncryptionKey(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key encryption key: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "kommodity-kms-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS plugin directory: %w", err)
+	}
+
+	socket := filepath.Join(dir, encryptionSocketName)
+
+	err = startEnvelopePlugin(ctx, socket, &envelopeService{kek: kek}, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath := filepath.Join(dir, encryptionConfigFileName)
+
+	err = os.WriteFile(configPath, fmt.Appendf(nil, encryptionConfigTemplate,
+		EncryptionProviderName, socket, encryptionPluginTimeout), encryptionConfigFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write encryption configuration: %w", err)
+	}
+
+	encryptionConfig, err := encryptionconfig.LoadEncryptionConfig(ctx, configPath, false, apiServerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption configuration: %w", err)
+	}
+
+	transformer, ok := encryptionConfig.Transformers[corev1.Resource("secrets")]
+	if !ok {
+		return nil, ErrNoSecretsTransformer
+	}
+
+	registerEncryptionReadinessCheck(ctx, encryptionConfig.HealthChecks)
+
+	logger.Info("Encryption at rest enabled for secrets",
+		zap.String("provider", string(cfg.Provider)))
+
+	return transformer, nil
+}
+
+// startEnvelopePlugin serves the KMS v2 gRPC API on socket and waits until it accepts
+// connections, so that the API server can prime its first data encryption key.
+func startEnvelopePlugin(ctx context.Context, socket string, service kmsservice.Service, dir string) error {
+	logger := logging.FromContext(ctx)
+
+	grpcService := kmsservice.NewGRPCService(socket, encryptionPluginTimeout, service)
+
+	go func() {
+		err := grpcService.ListenAndServe()
+		if err != nil && ctx.Err() == nil {
+			logger.Error("KMS plugin stopped", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		grpcService.Shutdown()
+
+		_ = os.RemoveAll(dir)
+	}()
+
+	err := wait.PollUntilContextTimeout(ctx, encryptionSocketPollInterval, encryptionSocketWaitTimeout, true,
+		func(_ context.Context) (bool, error) {
+			_, statErr := os.Stat(socket)
+
+			return statErr == nil, nil
+		})
+	if err != nil {
+		return fmt.Errorf("KMS plugin did not start listening on %s: %w", socket, err)
+	}
+
+	return nil
+}
+
+// registerEncryptionReadinessCheck reports the KMS plugin health on /readyz. While
+// the backend is unreachable no new data encryption key can be created, so writes
+// of Secrets would fail.
+func registerEncryptionReadinessCheck(ctx context.Context, checks []healthz.HealthChecker) {
+	logger := logging.FromContext(ctx)
+
+	err := combinedserver.RegisterReadinessCheck(encryptionReadinessCheckName, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, combinedserver.ReadyzPath, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create health check request: %w", err)
+		}
+
+		for _, check := range checks {
+			err = check.Check(req)
+			if err != nil {
+				return fmt.Errorf("%s: %w", check.Name(), err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Warn("Failed to register encryption readiness check", zap.Error(err))
+
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		combinedserver.UnregisterReadinessCheck(encryptionReadinessCheckName)
+	}()
+}
@@ -0,0 +1,240 @@
+package kms_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/kms"
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+const testSecretKey = "/secrets/default/db-credentials"
+
+func newStaticKey(t *testing.T, name string) config.StaticEncryptionKey {
+	t.Helper()
+
+	key := make([]byte, kms.KeySize)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return config.StaticEncryptionKey{Name: name, Key: key}
+}
+
+func newSecretsTransformer(t *testing.T, keys ...config.StaticEncryptionKey) value.Transformer {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	transformer, err := kms.NewSecretsTransformer(ctx, &config.EncryptionConfig{
+		Provider:   config.EncryptionProviderStatic,
+		StaticKeys: keys,
+	}, "test-apiserver")
+	if err != nil {
+		t.Fatalf("failed to create secrets transformer: %v", err)
+	}
+
+	return transformer
+}
+
+func TestSecretsTransformerDisabled(t *testing.T) {
+	t.Parallel()
+
+	transformer, err := kms.NewSecretsTransformer(t.Context(), &config.EncryptionConfig{}, "test-apiserver")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if transformer != nil {
+		t.Fatalf("expected no transformer when encryption is disabled")
+	}
+}
+
+func TestSecretsTransformerRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	transformer := newSecretsTransformer(t, newStaticKey(t, "key1"))
+	dataCtx := value.DefaultContext(testSecretKey)
+	plaintext := []byte(`{"data":{"password":"hunter2"}}`)
+
+	stored, err := transformer.TransformToStorage(t.Context(), plaintext, dataCtx)
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+
+	prefix := "k8s:enc:kms:v2:" + kms.EncryptionProviderName + ":"
+	if !bytes.HasPrefix(stored, []byte(prefix)) {
+		t.Fatalf("expected stored value to start with %q, got %q", prefix, stored[:min(len(stored), len(prefix))])
+	}
+
+	if bytes.Contains(stored, []byte("hunter2")) {
+		t.Fatalf("stored value contains the plaintext")
+	}
+
+	decrypted, stale, err := transformer.TransformFromStorage(t.Context(), stored, dataCtx)
+	if err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+
+	if stale {
+		t.Fatalf("expected freshly written value not to be stale")
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("decrypted value does not match: got %q, want %q", decrypted, plaintext)
+	}
+
+	_, _, err = transformer.TransformFromStorage(t.Context(), stored, value.DefaultContext("/secrets/default/other"))
+	if err == nil {
+		t.Fatalf("expected decrypting under another key path to fail")
+	}
+}
+
+func TestSecretsTransformerReadsPlaintextAsStale(t *testing.T) {
+	t.Parallel()
+
+	transformer := newSecretsTransformer(t, newStaticKey(t, "key1"))
+	plaintext := []byte(`{"kind":"Secret"}`)
+
+	decrypted, stale, err := transformer.TransformFromStorage(t.Context(), plaintext, value.DefaultContext(testSecretKey))
+	if err != nil {
+		t.Fatalf("failed to read plaintext value: %v", err)
+	}
+
+	if !stale {
+		t.Fatalf("expected plaintext value to be reported as stale")
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("unexpected value: got %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestSecretsTransformerKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	oldKey := newStaticKey(t, "key1")
+	newKey := newStaticKey(t, "key2")
+	dataCtx := value.DefaultContext(testSecretKey)
+	plaintext := []byte(`{"data":{"token":"abc"}}`)
+
+	stored, err := newSecretsTransformer(t, oldKey).TransformToStorage(t.Context(), plaintext, dataCtx)
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+
+	decrypted, _, err := newSecretsTransformer(t, newKey, oldKey).TransformFromStorage(t.Context(), stored, dataCtx)
+	if err != nil {
+		t.Fatalf("failed to decrypt after rotation: %v", err)
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("decrypted value does not match: got %q, want %q", decrypted, plaintext)
+	}
+
+	_, _, err = newSecretsTransformer(t, newKey).TransformFromStorage(t.Context(), stored, dataCtx)
+	if err == nil {
+		t.Fatalf("expected decryption to fail once the old key is removed")
+	}
+}
+
+func TestStaticKEKRejectsUnknownKeyID(t *testing.T) {
+	t.Parallel()
+
+	kek, err := kms.NewStaticKEK([]config.StaticEncryptionKey{newStaticKey(t, "key1")})
+	if err != nil {
+		t.Fatalf("failed to create static KEK: %v", err)
+	}
+
+	ciphertext, keyID, err := kek.Wrap(t.Context(), []byte("dek"))
+	if err != nil {
+		t.Fatalf("failed to wrap: %v", err)
+	}
+
+	if keyID != "key1" {
+		t.Fatalf("expected key ID key1, got %s", keyID)
+	}
+
+	_, err = kek.Unwrap(t.Context(), ciphertext, "key0")
+	if err == nil {
+		t.Fatalf("expected unwrap with unknown key ID to fail")
+	}
+}
+
+func TestVaultKEK(t *testing.T) {
+	t.Parallel()
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		body := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		var data map[string]any
+
+		switch r.URL.Path {
+		case "/v1/transit/keys/kommodity":
+			data = map[string]any{"latest_version": 3}
+		case "/v1/transit/encrypt/kommodity":
+			data = map[string]any{"ciphertext": "vault:v3:" + body["plaintext"]}
+		case "/v1/transit/decrypt/kommodity":
+			data = map[string]any{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v3:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(vault.Close)
+
+	kek := kms.NewVaultKEK(vault.Client(), vault.URL+"/", "root", "/transit/", "kommodity")
+
+	keyID, err := kek.KeyID(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get key ID: %v", err)
+	}
+
+	ciphertext, wrapKeyID, err := kek.Wrap(t.Context(), []byte("dek"))
+	if err != nil {
+		t.Fatalf("failed to wrap: %v", err)
+	}
+
+	if keyID != wrapKeyID || keyID != "vault:transit/kommodity:v3" {
+		t.Fatalf("expected matching key IDs, got %s and %s", keyID, wrapKeyID)
+	}
+
+	if string(ciphertext) != "vault:v3:"+base64.StdEncoding.EncodeToString([]byte("dek")) {
+		t.Fatalf("unexpected ciphertext %q", ciphertext)
+	}
+
+	plaintext, err := kek.Unwrap(t.Context(), ciphertext, keyID)
+	if err != nil {
+		t.Fatalf("failed to unwrap: %v", err)
+	}
+
+	if string(plaintext) != "dek" {
+		t.Fatalf("unexpected plaintext %q", plaintext)
+	}
+
+	_, err = kms.NewVaultKEK(vault.Client(), vault.URL, "wrong", "transit", "kommodity").KeyID(t.Context())
+	if err == nil {
+		t.Fatalf("expected an error for an invalid token")
+	}
+}
@@ -17,4 +17,20 @@ var (
 	ErrNoVolumeKeySets = errors.New("no volume key sets found in secret")
 	// ErrNoValidClientIP is an error that indicates no valid client IP could be resolved.
 	ErrNoValidClientIP = errors.New("no valid client IP could be resolved")
+	// ErrEncryptionDisabled is an error that indicates encryption at rest is not configured.
+	ErrEncryptionDisabled = errors.New("encryption at rest is disabled")
+	// ErrUnknownEncryptionProvider is an error that indicates an unsupported key encryption key backend.
+	ErrUnknownEncryptionProvider = errors.New("unknown encryption provider")
+	// ErrNoEncryptionKeys is an error that indicates the static provider has no keys.
+	ErrNoEncryptionKeys = errors.New("no encryption keys configured")
+	// ErrInvalidEncryptionKey is an error that indicates a static key has the wrong size.
+	ErrInvalidEncryptionKey = errors.New("encryption key must be 32 bytes")
+	// ErrUnknownKeyID is an error that indicates data was wrapped with a key that is no longer configured.
+	ErrUnknownKeyID = errors.New("unknown key ID")
+	// ErrKEKBackend is an error that indicates the key encryption key backend rejected a request.
+	ErrKEKBackend = errors.New("key encryption key backend error")
+	// ErrMissingCredentials is an error that indicates no credentials are available for the backend.
+	ErrMissingCredentials = errors.New("missing key encryption key backend credentials")
+	// ErrNoSecretsTransformer is an error that indicates the encryption configuration has no secrets transformer.
+	ErrNoSecretsTransformer = errors.New("encryption configuration has no transformer for secrets")
 )
@@ -24,3 +24,11 @@ const (
 	LuksKeySuffix   = luksKeySuffix
 	SealedFromIPKey = sealedFromIPKey
 )
+
+// Key encryption key backends for black-box testing.
+//
+//nolint:gochecknoglobals // test exports
+var (
+	NewVaultKEK  = newVaultKEK
+	NewStaticKEK = newStaticKEK
+)
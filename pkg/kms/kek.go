@@ -0,0 +1,128 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	kmsservice "k8s.io/kms/pkg/service"
+)
+
+const (
+	kmsAPIVersion   = "v2"
+	kmsHealthzOK    = "ok"
+	kekHTTPTimeout  = 10 * time.Second
+	maxKEKBodyBytes = 1 << 20
+)
+
+// keyEncryptionKey wraps and unwraps data encryption keys with a key held by an
+// external backend. Implementations must be able to unwrap data wrapped with any
+// earlier version of the key, which is what makes rotation non-disruptive.
+type keyEncryptionKey interface {
+	// KeyID identifies the key version that Wrap currently uses. A change of
+	// the returned value makes the API server generate a new data encryption key.
+	KeyID(ctx context.Context) (string, error)
+	// Wrap encrypts plaintext and returns the ciphertext and the ID of the key used.
+	Wrap(ctx context.Context, plaintext []byte) ([]byte, string, error)
+	// Unwrap decrypts ciphertext previously returned by Wrap with the given key ID.
+	Unwrap(ctx context.Context, ciphertext []byte, keyID string) ([]byte, error)
+}
+
+// newKeyEncryptionKey builds the KEK backend selected in the configuration.
+func newKeyEncryptionKey(cfg *config.EncryptionConfig) (keyEncryptionKey, error) {
+	httpClient := &http.Client{Timeout: kekHTTPTimeout}
+
+	switch cfg.Provider {
+	case config.EncryptionProviderStatic:
+		return newStaticKEK(cfg.StaticKeys)
+	case config.EncryptionProviderVault:
+		return newVaultKEK(httpClient, cfg.VaultAddress, cfg.VaultToken, cfg.VaultMount, cfg.VaultKey), nil
+	case config.EncryptionProviderAWS:
+		return newAWSKEK(httpClient, cfg.AWSRegion, cfg.AWSKeyID)
+	case config.EncryptionProviderGCP:
+		return newGCPKEK(httpClient, cfg.GCPKeyName), nil
+	case config.EncryptionProviderNone:
+		return nil, ErrEncryptionDisabled
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEncryptionProvider, cfg.Provider)
+	}
+}
+
+// envelopeService exposes a keyEncryptionKey as a KMS v2 plugin. The API server
+// only sends its data encryption keys through the plugin; the Secrets themselves
+// are encrypted locally with those keys.
+type envelopeService struct {
+	kek keyEncryptionKey
+}
+
+var _ kmsservice.Service = &envelopeService{}
+
+// Encrypt wraps a data encryption key.
+func (s *envelopeService) Encrypt(ctx context.Context, _ string, data []byte) (*kmsservice.EncryptResponse, error) {
+	ciphertext, keyID, err := s.kek.Wrap(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data encryption key: %w", err)
+	}
+
+	return &kmsservice.EncryptResponse{
+		Ciphertext: ciphertext,
+		KeyID:      keyID,
+	}, nil
+}
+
+// Decrypt unwraps a data encryption key.
+func (s *envelopeService) Decrypt(ctx context.Context, _ string, req *kmsservice.DecryptRequest) ([]byte, error) {
+	plaintext, err := s.kek.Unwrap(ctx, req.Ciphertext, req.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data encryption key: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Status reports the current key ID, which is polled by the API server to detect rotations.
+func (s *envelopeService) Status(ctx context.Context) (*kmsservice.StatusResponse, error) {
+	keyID, err := s.kek.KeyID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key encryption key status: %w", err)
+	}
+
+	return &kmsservice.StatusResponse{
+		Version: kmsAPIVersion,
+		Healthz: kmsHealthzOK,
+		KeyID:   keyID,
+	}, nil
+}
+
+// doJSON executes a request against a KEK backend and decodes a JSON response.
+// Non-2xx responses are returned as ErrKEKBackend errors including the body.
+func doJSON(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrKEKBackend, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxKEKBodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", req.URL.Host, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned %d: %s", ErrKEKBackend, req.URL.Host, resp.StatusCode,
+			strings.TrimSpace(string(body)))
+	}
+
+	err = json.Unmarshal(body, out)
+	if err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Host, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	gcpKMSEndpoint        = "https://cloudkms.googleapis.com/v1/"
+	gcpDefaultMetadataURL = "http://metadata.google.internal"
+	gcpMetadataTokenPath  = "/computeMetadata/v1/instance/service-accounts/default/token"
+	envGCEMetadataHost    = "GCE_METADATA_HOST"
+	// gcpTokenExpiryMargin refreshes the access token before it actually expires.
+	gcpTokenExpiryMargin = time.Minute
+)
+
+// gcpKEK wraps keys with Google Cloud KMS using its REST API. Access tokens are
+// obtained from the metadata server, which covers GCE, GKE Workload Identity and
+// Cloud Run. Cloud KMS reports the primary key version as key ID, so promoting a
+// new version triggers a new data encryption key.
+type gcpKEK struct {
+	client      *http.Client
+	endpoint    string
+	metadataURL string
+	keyName     string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newGCPKEK(client *http.Client, keyName string) *gcpKEK {
+	metadataURL := gcpDefaultMetadataURL
+	if host := os.Getenv(envGCEMetadataHost); host != "" {
+		metadataURL = "http://" + host
+	}
+
+	return &gcpKEK{
+		client:      client,
+		endpoint:    gcpKMSEndpoint,
+		metadataURL: metadataURL,
+		keyName:     keyName,
+	}
+}
+
+func (k *gcpKEK) KeyID(ctx context.Context) (string, error) {
+	var response struct {
+		Primary struct {
+			Name  string `json:"name"`
+			State string `json:"state"`
+		} `json:"primary"`
+	}
+
+	err := k.call(ctx, http.MethodGet, k.keyName, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Primary.Name == "" {
+		return "", fmt.Errorf("%w: key %s has no primary version", ErrKEKBackend, k.keyName)
+	}
+
+	return response.Primary.Name, nil
+}
+
+func (k *gcpKEK) Wrap(ctx context.Context, plaintext []byte) ([]byte, string, error) {
+	var response struct {
+		Name       string `json:"name"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+
+	err := k.call(ctx, http.MethodPost, k.keyName+":encrypt", map[string]any{
+		"plaintext": plaintext,
+	}, &response)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return response.Ciphertext, response.Name, nil
+}
+
+func (k *gcpKEK) Unwrap(ctx context.Context, ciphertext []byte, _ string) ([]byte, error) {
+	var response struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+
+	// The key version is embedded in the ciphertext, decryption always goes through the key.
+	err := k.call(ctx, http.MethodPost, k.keyName+":decrypt", map[string]any{
+		"ciphertext": ciphertext,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Plaintext, nil
+}
+
+func (k *gcpKEK) call(ctx context.Context, method, resource string, input any, out any) error {
+	token, err := k.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+
+	if input != nil {
+		body, err = json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("failed to encode Cloud KMS request: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, k.endpoint+resource, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Cloud KMS request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	return doJSON(k.client, req, out)
+}
+
+// accessToken returns a cached metadata server token, refreshing it shortly before it expires.
+func (k *gcpKEK) accessToken(ctx context.Context) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.token != "" && time.Now().Before(k.tokenExpiry) {
+		return k.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.metadataURL+gcpMetadataTokenPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create metadata token request: %w", err)
+	}
+
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	err = doJSON(k.client, req, &response)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMissingCredentials, err)
+	}
+
+	k.token = response.AccessToken
+	k.tokenExpiry = time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - gcpTokenExpiryMargin)
+
+	return k.token, nil
+}
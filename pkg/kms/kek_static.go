@@ -0,0 +1,54 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+// staticKEK wraps keys with locally configured AES-256-GCM keys. The first key is
+// the active one; the others stay available to unwrap keys written before a rotation.
+type staticKEK struct {
+	keys []config.StaticEncryptionKey
+}
+
+func newStaticKEK(keys []config.StaticEncryptionKey) (*staticKEK, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoEncryptionKeys
+	}
+
+	for _, key := range keys {
+		if len(key.Key) != keySize {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEncryptionKey, key.Name)
+		}
+	}
+
+	return &staticKEK{keys: keys}, nil
+}
+
+func (k *staticKEK) KeyID(_ context.Context) (string, error) {
+	return k.keys[0].Name, nil
+}
+
+func (k *staticKEK) Wrap(_ context.Context, plaintext []byte) ([]byte, string, error) {
+	active := k.keys[0]
+
+	// The key name is bound as AAD so that a ciphertext cannot be replayed under another key ID.
+	ciphertext, err := encrypt(active.Key, plaintext, []byte(active.Name))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ciphertext, active.Name, nil
+}
+
+func (k *staticKEK) Unwrap(_ context.Context, ciphertext []byte, keyID string) ([]byte, error) {
+	for _, key := range k.keys {
+		if key.Name == keyID {
+			return decrypt(key.Key, ciphertext, []byte(key.Name))
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+}
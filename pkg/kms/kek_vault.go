@@ -0,0 +1,106 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/vault"
+)
+
+const vaultCiphertextParts = 3 // vault:v<version>:<payload>
+
+// vaultKEK wraps keys with the HashiCorp Vault transit secrets engine. Rotating
+// the transit key in Vault changes the reported key ID; Vault keeps older key
+// versions to decrypt existing ciphertexts.
+type vaultKEK struct {
+	client *vault.Client
+	mount  string
+	key    string
+}
+
+func newVaultKEK(client *http.Client, address, token, mount, key string) *vaultKEK {
+	return &vaultKEK{
+		client: vault.NewClient(client, address, token),
+		mount:  strings.Trim(mount, "/"),
+		key:    key,
+	}
+}
+
+func (k *vaultKEK) KeyID(ctx context.Context) (string, error) {
+	var response struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+		} `json:"data"`
+	}
+
+	err := k.do(ctx, http.MethodGet, "keys", nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return k.keyIDForVersion(strconv.Itoa(response.Data.LatestVersion)), nil
+}
+
+func (k *vaultKEK) Wrap(ctx context.Context, plaintext []byte) ([]byte, string, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+
+	err := k.do(ctx, http.MethodPost, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	}, &response)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Vault ciphertexts carry their key version: vault:v3:<payload>.
+	parts := strings.SplitN(response.Data.Ciphertext, ":", vaultCiphertextParts)
+	if len(parts) != vaultCiphertextParts || !strings.HasPrefix(parts[1], "v") {
+		return nil, "", fmt.Errorf("%w: unexpected vault ciphertext format", ErrKEKBackend)
+	}
+
+	return []byte(response.Data.Ciphertext), k.keyIDForVersion(strings.TrimPrefix(parts[1], "v")), nil
+}
+
+func (k *vaultKEK) Unwrap(ctx context.Context, ciphertext []byte, _ string) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+
+	err := k.do(ctx, http.MethodPost, "decrypt", map[string]string{
+		"ciphertext": string(ciphertext),
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode vault plaintext: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func (k *vaultKEK) keyIDForVersion(version string) string {
+	return "vault:" + k.mount + "/" + k.key + ":v" + version
+}
+
+// do calls <address>/v1/<mount>/<operation>/<key> and decodes the JSON response.
+func (k *vaultKEK) do(ctx context.Context, method, operation string, body any, out any) error {
+	err := k.client.Do(ctx, method, k.mount+"/"+operation+"/"+url.PathEscape(k.key), body, out)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrKEKBackend, err)
+	}
+
+	return nil
+}
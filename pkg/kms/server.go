@@ -2,7 +2,8 @@
 // a networked key management system for full disk encryption.
 //
 // This package includes a mock implementation of the gRPC server for
-// the SideroLabs KMS API.
+// the SideroLabs KMS API, and a KMS v2 plugin used by the API server for
+// envelope encryption of Secrets at rest.
 package kms
 
 import (
@@ -0,0 +1,6 @@
+package logging
+
+import "errors"
+
+// ErrUnknownModule is returned for a module that has no log level of its own.
+var ErrUnknownModule = errors.New("unknown log module")
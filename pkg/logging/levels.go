@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Module names a part of Kommodity whose log level can be set apart from the global one.
+type Module string
+
+const (
+	// ModuleKine is the embedded kine and its database.
+	ModuleKine Module = "kine"
+	// ModuleStorage is the storage of the resources served by the API server.
+	ModuleStorage Module = "storage"
+	// ModuleAPI is the handler chain of the API server, which logs the served requests.
+	ModuleAPI Module = "api"
+	// ModuleControllers are the reconcilers of the controller manager.
+	ModuleControllers Module = "controllers"
+	// ModuleGenericServer is the Kubernetes generic API server, which logs through klog.
+	ModuleGenericServer Module = "genericserver"
+)
+
+// Modules returns the modules whose log level can be set.
+func Modules() []Module {
+	return []Module{ModuleKine, ModuleStorage, ModuleAPI, ModuleControllers, ModuleGenericServer}
+}
+
+// Levels holds the global log level of Kommodity and the levels of its modules. A
+// module without a level of its own follows the global level. All levels can be
+// changed while the loggers are in use.
+type Levels struct {
+	global  zap.AtomicLevel
+	modules map[Module]*atomic.Pointer[zapcore.Level]
+}
+
+// NewLevels returns levels at the given global level, without module levels.
+func NewLevels(global zapcore.Level) *Levels {
+	modules := make(map[Module]*atomic.Pointer[zapcore.Level], len(Modules()))
+	for _, module := range Modules() {
+		modules[module] = &atomic.Pointer[zapcore.Level]{}
+	}
+
+	return &Levels{
+		global:  zap.NewAtomicLevelAt(global),
+		modules: modules,
+	}
+}
+
+// Level returns the global log level.
+func (l *Levels) Level() zapcore.Level {
+	return l.global.Level()
+}
+
+// SetLevel changes the global log level.
+func (l *Levels) SetLevel(level zapcore.Level) {
+	l.global.SetLevel(level)
+}
+
+// ModuleLevel returns the level set for the module, and false if the module follows
+// the global level.
+func (l *Levels) ModuleLevel(module Module) (zapcore.Level, bool) {
+	level, ok := l.modules[module]
+	if !ok || level.Load() == nil {
+		return l.Level(), false
+	}
+
+	return *level.Load(), true
+}
+
+// SetModuleLevel sets the level of the module. A nil level makes the module follow the
+// global level again.
+func (l *Levels) SetModuleLevel(module Module, level *zapcore.Level) error {
+	moduleLevel, ok := l.modules[module]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownModule, module)
+	}
+
+	if level == nil {
+		moduleLevel.Store(nil)
+
+		return nil
+	}
+
+	copied := *level
+	moduleLevel.Store(&copied)
+
+	return nil
+}
+
+// Enabled reports whether the module logs at the level. The empty module is the global one.
+func (l *Levels) Enabled(module Module, level zapcore.Level) bool {
+	moduleLevel, _ := l.ModuleLevel(module)
+
+	return level >= moduleLevel
+}
+
+// ParseModuleLevels parses comma-separated module=level pairs, such as
+// "kine=info,storage=debug".
+func ParseModuleLevels(raw string) (map[Module]zapcore.Level, error) {
+	levels := make(map[Module]zapcore.Level)
+
+	for pair := range strings.SplitSeq(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		rawModule, rawLevel, found := strings.Cut(pair, "=")
+
+		module := Module(strings.TrimSpace(rawModule))
+		if !found || !slices.Contains(Modules(), module) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownModule, pair)
+		}
+
+		level, err := zapcore.ParseLevel(strings.TrimSpace(rawLevel))
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of module %s: %w", module, err)
+		}
+
+		levels[module] = level
+	}
+
+	return levels, nil
+}
+
+// ForModule returns a logger named after the module, which logs at the level of the
+// module. Loggers that were not created by this package keep their level.
+func ForModule(logger *zap.Logger, module Module) *zap.Logger {
+	return logger.Named(string(module)).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		leveled, ok := core.(*levelCore)
+		if !ok {
+			return core
+		}
+
+		return &levelCore{Core: leveled.Core, levels: leveled.levels, module: module}
+	}))
+}
+
+// WithModule replaces the logger of the context with the logger of the module.
+func WithModule(ctx context.Context, module Module) context.Context {
+	return WithLogger(ctx, ForModule(FromContext(ctx), module))
+}
+
+// LevelsOf returns the levels the logger was created with, and false if the logger was
+// not created by this package.
+func LevelsOf(logger *zap.Logger) (*Levels, bool) {
+	leveled, ok := logger.Core().(*levelCore)
+	if !ok {
+		return nil, false
+	}
+
+	return leveled.levels, true
+}
+
+// permissiveLevel lets every entry pass the wrapped core; levelCore does the filtering.
+const permissiveLevel = zapcore.Level(math.MinInt8)
+
+// levelCore filters the entries of a core by the level of a module.
+type levelCore struct {
+	zapcore.Core
+
+	levels *Levels
+	module Module
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.Enabled(c.module, level)
+}
+
+// Level lets zap report the level of the logger without probing every level.
+func (c *levelCore) Level() zapcore.Level {
+	level, _ := c.levels.ModuleLevel(c.module)
+
+	return level
+}
+
+// With adds structured context to the core.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels, module: c.module}
+}
+
+// Check adds the core to the checked entry if the module logs at the entry's level.
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+
+	return c.Core.Check(entry, checked)
+}
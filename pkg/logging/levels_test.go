@@ -0,0 +1,88 @@
+package logging_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestModuleLevelsFollowGlobalLevel(t *testing.T) {
+	// Arrange.
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_MODULE_LEVELS", "kine=debug")
+
+	logger, levels := logging.NewLeveledLogger()
+	kine := logging.ForModule(logger, logging.ModuleKine)
+	storage := logging.ForModule(logger.With(zap.String("key", "value")), logging.ModuleStorage)
+
+	// Assert.
+	assert.Equal(t, zap.WarnLevel, logger.Level(), "should log at the global level")
+	assert.Equal(t, zap.DebugLevel, kine.Level(), "should log at the level of the module")
+	assert.Equal(t, zap.WarnLevel, storage.Level(), "should follow the global level without a module level")
+
+	// Act.
+	levels.SetLevel(zap.ErrorLevel)
+
+	err := levels.SetModuleLevel(logging.ModuleKine, nil)
+	require.NoError(t, err)
+
+	// Assert.
+	assert.Equal(t, zap.ErrorLevel, storage.Level(), "should follow a change of the global level")
+	assert.Equal(t, zap.ErrorLevel, kine.Level(), "should follow the global level once the module level is reset")
+
+	gotLevels, ok := logging.LevelsOf(storage)
+	require.True(t, ok)
+	assert.Same(t, levels, gotLevels, "should share the levels of the logger it was created from")
+}
+
+func TestForModuleReplacesTheModule(t *testing.T) {
+	// Arrange.
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("LOG_MODULE_LEVELS", "api=warn,storage=debug")
+
+	logger, _ := logging.NewLeveledLogger()
+
+	// Act.
+	api := logging.ForModule(logger, logging.ModuleAPI)
+	storage := logging.ForModule(api, logging.ModuleStorage)
+
+	// Assert.
+	assert.Equal(t, zap.WarnLevel, api.Level(), "should log at the level of the API module")
+	assert.Equal(t, zap.DebugLevel, storage.Level(), "should log at the level of the storage module only")
+}
+
+func TestSetUnknownModuleLevel(t *testing.T) {
+	// Arrange.
+	t.Parallel()
+
+	levels := logging.NewLevels(zap.InfoLevel)
+	level := zap.DebugLevel
+
+	// Act.
+	err := levels.SetModuleLevel("unknown", &level)
+
+	// Assert.
+	require.ErrorIs(t, err, logging.ErrUnknownModule)
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	// Arrange.
+	t.Parallel()
+
+	// Act.
+	levels, err := logging.ParseModuleLevels(" kine=info, controllers=debug ,")
+
+	// Assert.
+	require.NoError(t, err)
+	assert.Equal(t, zap.InfoLevel, levels[logging.ModuleKine])
+	assert.Equal(t, zap.DebugLevel, levels[logging.ModuleControllers])
+
+	_, err = logging.ParseModuleLevels("etcd=info")
+	require.ErrorIs(t, err, logging.ErrUnknownModule)
+
+	_, err = logging.ParseModuleLevels("kine=loud")
+	require.Error(t, err)
+}
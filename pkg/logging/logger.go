@@ -25,6 +25,14 @@ const (
 
 // NewLogger creates a new logger.
 func NewLogger() *zap.Logger {
+	logger, _ := NewLeveledLogger()
+
+	return logger
+}
+
+// NewLeveledLogger creates a new logger together with its levels, which can be changed
+// while the logger is in use.
+func NewLeveledLogger() (*zap.Logger, *Levels) {
 	config := zap.NewProductionConfig()
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
@@ -38,9 +46,14 @@ func NewLogger() *zap.Logger {
 	config.OutputPaths = []string{"stdout"}
 
 	level, levelInvalid, rawLevel := getLevel()
-	config.Level = level
+	levels := NewLevels(level.Level())
+
+	// The levels filter the entries, modules may log below the global level.
+	config.Level = zap.NewAtomicLevelAt(permissiveLevel)
 
-	logger, err := config.Build()
+	logger, err := config.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, levels: levels}
+	}))
 	if err != nil {
 		//nolint:forbidigo // This is required if the logger cannot be created.
 		fmt.Printf(fatalErrorTemplate, time.Now().Format(time.RFC3339), err)
@@ -59,7 +72,16 @@ func NewLogger() *zap.Logger {
 		logger.Warn("Using default level", zap.String("level", level.String()))
 	}
 
-	return logger
+	moduleLevels, err := ParseModuleLevels(os.Getenv("LOG_MODULE_LEVELS"))
+	if err != nil {
+		logger.Warn("Invalid module log levels, modules follow the log level", zap.Error(err))
+	}
+
+	for module, moduleLevel := range moduleLevels {
+		_ = levels.SetModuleLevel(module, &moduleLevel)
+	}
+
+	return logger, levels
 }
 
 // getFormat extracts the log format from the environment variable
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDHeader carries the ID of a request. It is kept when a client or proxy
+	// already set it, and returned in the response.
+	RequestIDHeader = "X-Request-Id"
+	// RequestIDMetadataKey carries the ID of a gRPC call in its metadata.
+	RequestIDMetadataKey = "x-request-id"
+
+	// maxRequestIDLength bounds request IDs taken over from callers.
+	maxRequestIDLength = 128
+)
+
+// requestIDKey is the key of the request ID in the context.
+type requestIDKey struct{}
+
+// RequestID returns the given request ID if it can be taken over, or a new one.
+func RequestID(given string) string {
+	if given == "" || len(given) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+
+	for _, char := range given {
+		// Only printable ASCII ends up in logs and headers.
+		if char < ' ' || char > '~' {
+			return uuid.NewString()
+		}
+	}
+
+	return given
+}
+
+// WithRequestID adds the request ID to the context and to the logger of the context,
+// so that everything logged on behalf of the request can be correlated.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+
+	return WithLogger(ctx, FromContext(ctx).With(zap.String("requestID", requestID)))
+}
+
+// RequestIDFromContext returns the request ID of the context, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+
+	return requestID, ok
+}
+
+// ResponseRecorder records the status code and size of a response for request logs.
+// Handlers that need to flush or hijack the response get it wrapped with
+// responsewriter.WrapForHTTP1Or2 of the API server.
+type ResponseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	size   int
+}
+
+// NewResponseRecorder returns a recorder writing to w.
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{ResponseWriter: w}
+}
+
+// WriteHeader records the status code.
+func (r *ResponseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the size of the body.
+func (r *ResponseRecorder) Write(body []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(body)
+	r.size += n
+
+	return n, err //nolint:wrapcheck // Errors of the wrapped writer are returned as is.
+}
+
+// Hijack takes over the connection of an upgraded request, such as exec or port-forward.
+func (r *ResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %T", http.ErrNotSupported, r.ResponseWriter)
+	}
+
+	r.status = http.StatusSwitchingProtocols
+
+	return hijacker.Hijack() //nolint:wrapcheck // Errors of the wrapped writer are returned as is.
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer, e.g. to flush it.
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Status returns the status code of the response, 200 if nothing was written.
+func (r *ResponseRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
+
+// Size returns the number of body bytes written.
+func (r *ResponseRecorder) Size() int {
+	return r.size
+}
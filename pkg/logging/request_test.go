@@ -0,0 +1,18 @@
+package logging_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	// Arrange.
+	t.Parallel()
+
+	// Act & Assert.
+	assert.Equal(t, "abc-123", logging.RequestID("abc-123"), "should keep a valid request ID")
+	assert.NotEqual(t, "bad\nid", logging.RequestID("bad\nid"), "should replace a request ID with control characters")
+	assert.NotEmpty(t, logging.RequestID(""), "should generate a request ID")
+}
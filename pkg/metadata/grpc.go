@@ -0,0 +1,110 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	restutils "github.com/kommodity-io/kommodity/pkg/metadata/rest"
+	restuserdata "github.com/kommodity-io/kommodity/pkg/metadata/rest/userdata"
+	"github.com/kommodity-io/kommodity/pkg/net"
+	metadatav1alpha1 "github.com/kommodity-io/kommodity/proto/metadata/v1alpha1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// machineConfigServer serves the machine configuration of the calling machine over gRPC,
+// as /configs/user-data does over HTTP.
+type machineConfigServer struct {
+	metadatav1alpha1.UnimplementedMetadataServiceServer
+
+	config *config.KommodityConfig
+}
+
+// NewGRPCServerFactory returns an initializer function that registers the metadata service.
+func NewGRPCServerFactory(cfg *config.KommodityConfig) combinedserver.GRPCServerFactory {
+	return func(srv *grpc.Server) error {
+		metadatav1alpha1.RegisterMetadataServiceServer(srv, &machineConfigServer{config: cfg})
+
+		return nil
+	}
+}
+
+// GetMachineConfig returns the machine configuration of the calling machine.
+func (s *machineConfigServer) GetMachineConfig(ctx context.Context,
+	_ *metadatav1alpha1.GetMachineConfigRequest) (*metadatav1alpha1.MachineConfig, error) {
+	_, machine, err := s.trustedMachine(ctx)
+	if err != nil {
+		return nil, machineConfigStatus(err)
+	}
+
+	userData, version, err := restuserdata.UserData(ctx, s.config, machine)
+	if err != nil {
+		return nil, machineConfigStatus(err)
+	}
+
+	return &metadatav1alpha1.MachineConfig{Config: userData, Version: version}, nil
+}
+
+// WatchMachineConfig streams the machine configuration of the calling machine whenever it
+// changes. The stream ends once the machine is no longer trusted.
+func (s *machineConfigServer) WatchMachineConfig(_ *metadatav1alpha1.WatchMachineConfigRequest,
+	stream grpc.ServerStreamingServer[metadatav1alpha1.MachineConfig]) error {
+	ctx := stream.Context()
+
+	//nolint:varnamelen // Variable name ip is appropriate for the context.
+	ip, machine, err := s.trustedMachine(ctx)
+	if err != nil {
+		return machineConfigStatus(err)
+	}
+
+	err = restuserdata.WatchUserData(ctx, s.config, machine, func(userData []byte, version string) error {
+		_, err := restuserdata.TrustedMachine(ctx, s.config, ip)
+		if err != nil {
+			return err
+		}
+
+		return stream.Send(&metadatav1alpha1.MachineConfig{Config: userData, Version: version})
+	})
+	if err != nil {
+		return machineConfigStatus(err)
+	}
+
+	return nil
+}
+
+// trustedMachine returns the address of the caller and its Machine, if it is trusted.
+func (s *machineConfigServer) trustedMachine(ctx context.Context) (string, *clusterv1.Machine, error) {
+	//nolint:varnamelen // Variable name ip is appropriate for the context.
+	ip, err := net.GetOriginalIPFromContext(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	machine, err := restuserdata.TrustedMachine(ctx, s.config, ip)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ip, machine, nil
+}
+
+// machineConfigStatus returns the gRPC status of an error serving a machine configuration,
+// with the same meaning as the status codes of /configs/user-data.
+func machineConfigStatus(err error) error {
+	switch {
+	case errors.Is(err, net.ErrIPRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, restutils.ErrMachineNotTrusted):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, net.ErrNoMachineFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, restutils.ErrMachineConfigNotReady):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to serve machine config")
+	}
+}
@@ -0,0 +1,96 @@
+//nolint:testpackage // white-box tests exercise the unexported status mapping
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	restutils "github.com/kommodity-io/kommodity/pkg/metadata/rest"
+	kommoditynet "github.com/kommodity-io/kommodity/pkg/net"
+	metadatav1alpha1 "github.com/kommodity-io/kommodity/proto/metadata/v1alpha1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestMachineConfigStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"no address":  {err: kommoditynet.ErrIPRequired, code: codes.InvalidArgument},
+		"not trusted": {err: restutils.ErrMachineNotTrusted, code: codes.PermissionDenied},
+		"no machine": {
+			err:  fmt.Errorf("failed to find machine by IP: %w", kommoditynet.ErrNoMachineFound),
+			code: codes.NotFound,
+		},
+		"not ready": {err: restutils.ErrMachineConfigNotReady, code: codes.Unavailable},
+		"other":     {err: restutils.ErrUnexpectedResponse, code: codes.Internal},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			code := status.Code(machineConfigStatus(test.err))
+			if code != test.code {
+				t.Fatalf("expected code %s, got %s", test.code, code)
+			}
+		})
+	}
+}
+
+// TestMetadataServiceRequiresAddress asserts that the service is registered and rejects
+// callers whose address is unknown before looking up any machine.
+func TestMetadataServiceRequiresAddress(t *testing.T) {
+	t.Parallel()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+
+	err := NewGRPCServerFactory(&config.KommodityConfig{})(server)
+	if err != nil {
+		t.Fatalf("failed to register the metadata service: %v", err)
+	}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := metadatav1alpha1.NewMetadataServiceClient(conn)
+
+	_, err = client.GetMachineConfig(t.Context(), &metadatav1alpha1.GetMachineConfigRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an invalid argument error, got %v", err)
+	}
+
+	stream, err := client.WatchMachineConfig(t.Context(), &metadatav1alpha1.WatchMachineConfigRequest{})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	_, err = stream.Recv()
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an invalid argument error, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Package compat provides handlers serving machine metadata in the formats of cloud
+// metadata services, for images that read their metadata with cloud-init or Ignition.
+package compat
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	restuserdata "github.com/kommodity-io/kommodity/pkg/metadata/rest/userdata"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ec2AddressKeys are the EC2 metadata keys of the first address of each type.
+//
+//nolint:gochecknoglobals // Read-only mapping of the address types.
+var ec2AddressKeys = map[clusterv1.MachineAddressType]string{
+	clusterv1.MachineInternalIP: "local-ipv4",
+	clusterv1.MachineExternalIP: "public-ipv4",
+}
+
+// openStackMetaData is the subset of the OpenStack meta_data.json served for a machine.
+type openStackMetaData struct {
+	UUID             string            `json:"uuid"`
+	Name             string            `json:"name"`
+	Hostname         string            `json:"hostname"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Meta             map[string]string `json:"meta"`
+}
+
+// GetEC2MetaData godoc
+// @Summary  Get EC2 instance metadata of a machine
+// @Tags     Compatibility
+// @Produce  plain
+// @Param    key  path      string  true  "Metadata key, like instance-id or local-ipv4"
+// @Success  200  {string}  string  "Value of the key"
+// @Failure  404  {object}  string  "If the machine or the key is not found"
+// @Failure  500  {object}  string  "If there is a server error"
+// @Router   /latest/meta-data/{key} [get]
+//
+// GetEC2MetaData handles requests for the metadata of a machine in the format of the
+// EC2 instance metadata service. Without a key, the keys are listed.
+func GetEC2MetaData(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		machine, found := findMachine(response, request, cfg, config.MetadataFormatEC2)
+		if !found {
+			return
+		}
+
+		metaData := ec2MetaData(machine)
+		key := request.PathValue("key")
+
+		value, found := metaData[key]
+		if key == "" {
+			value, found = strings.Join(slices.Sorted(maps.Keys(metaData)), "\n"), true
+		}
+
+		if !found {
+			http.Error(response, "Metadata key not found", http.StatusNotFound)
+
+			return
+		}
+
+		response.Header().Set("Content-Type", "text/plain")
+
+		_, err := response.Write([]byte(value))
+		if err != nil {
+			http.Error(response, "Failed to write metadata", http.StatusInternalServerError)
+		}
+	}
+}
+
+// GetOpenStackMetaData godoc
+// @Summary  Get OpenStack metadata of a machine
+// @Tags     Compatibility
+// @Produce  json
+// @Success  200  {object}  string  "meta_data.json of the machine"
+// @Failure  404  {object}  string  "If the machine is not found"
+// @Failure  500  {object}  string  "If there is a server error"
+// @Router   /openstack/latest/meta_data.json [get]
+//
+// GetOpenStackMetaData handles requests for the metadata of a machine in the format of
+// the OpenStack metadata service.
+func GetOpenStackMetaData(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		machine, found := findMachine(response, request, cfg, config.MetadataFormatOpenStack)
+		if !found {
+			return
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+
+		err := json.NewEncoder(response).Encode(newOpenStackMetaData(machine))
+		if err != nil {
+			http.Error(response, "Failed to encode metadata", http.StatusInternalServerError)
+		}
+	}
+}
+
+// GetUserData godoc
+// @Summary  Get user-data for Talos machine config in a cloud metadata format
+// @Tags     Compatibility
+// @Produce  application/x-yaml
+// @Success  200  {string}  string  "YAML config for Talos machine config"
+// @Failure  404  {object}  string  "If the machine is not found"
+// @Failure  500  {object}  string  "If there is a server error"
+// @Router   /latest/user-data [get]
+// @Router   /openstack/latest/user_data [get]
+//
+// GetUserData handles requests for the user data of a machine whose provider serves
+// its metadata in the format.
+func GetUserData(cfg *config.KommodityConfig, format config.MetadataFormat) func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, request *http.Request) {
+		machine, found := findMachine(response, request, cfg, format)
+		if !found {
+			return
+		}
+
+		restuserdata.WriteUserData(response, request, cfg, machine)
+	}
+}
+
+// findMachine returns the trusted Machine the request comes from, if its provider serves
+// its metadata in the format. It answers the request with an error and reports false
+// otherwise.
+func findMachine(response http.ResponseWriter, request *http.Request, cfg *config.KommodityConfig,
+	format config.MetadataFormat) (*clusterv1.Machine, bool) {
+	machine, found := restuserdata.FindTrustedMachine(response, request, cfg)
+	if !found {
+		return nil, false
+	}
+
+	if cfg.MetadataCompatibility[machineProvider(machine)] != format {
+		http.Error(response, "Metadata format not enabled for the provider of the machine", http.StatusNotFound)
+
+		return nil, false
+	}
+
+	return machine, true
+}
+
+// machineProvider returns the infrastructure provider of the Machine, from the kind of
+// its infrastructure machine, like KubevirtMachine.
+func machineProvider(machine *clusterv1.Machine) config.Provider {
+	return config.Provider(strings.ToLower(strings.TrimSuffix(machine.Spec.InfrastructureRef.Kind, "Machine")))
+}
+
+// ec2MetaData returns the EC2 metadata keys of the Machine with their values.
+func ec2MetaData(machine *clusterv1.Machine) map[string]string {
+	metaData := map[string]string{
+		"instance-id":    string(machine.UID),
+		"hostname":       machine.Name,
+		"local-hostname": machine.Name,
+	}
+
+	for _, address := range machine.Status.Addresses {
+		key, isIP := ec2AddressKeys[address.Type]
+		if _, found := metaData[key]; isIP && !found {
+			metaData[key] = address.Address
+		}
+	}
+
+	if machine.Spec.FailureDomain != nil {
+		metaData["placement/availability-zone"] = *machine.Spec.FailureDomain
+	}
+
+	return metaData
+}
+
+func newOpenStackMetaData(machine *clusterv1.Machine) openStackMetaData {
+	metaData := openStackMetaData{
+		UUID:     string(machine.UID),
+		Name:     machine.Name,
+		Hostname: machine.Name,
+		Meta: map[string]string{
+			"cluster":   machine.Spec.ClusterName,
+			"namespace": machine.Namespace,
+		},
+	}
+
+	if machine.Spec.FailureDomain != nil {
+		metaData.AvailabilityZone = *machine.Spec.FailureDomain
+	}
+
+	return metaData
+}
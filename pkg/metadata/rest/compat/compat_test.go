@@ -0,0 +1,86 @@
+//nolint:testpackage // white-box tests exercise the unexported metadata mapping
+package compat
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func newTestMachine() *clusterv1.Machine {
+	failureDomain := "zone-a"
+
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-workers-abcde", Namespace: "team-a", UID: "8a3c6d1e"},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:       "prod",
+			FailureDomain:     &failureDomain,
+			InfrastructureRef: corev1.ObjectReference{Kind: "KubevirtMachine"},
+		},
+		Status: clusterv1.MachineStatus{Addresses: clusterv1.MachineAddresses{
+			{Type: clusterv1.MachineHostName, Address: "prod-workers-abcde"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.0.5"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.0.6"},
+			{Type: clusterv1.MachineExternalIP, Address: "203.0.113.5"},
+		}},
+	}
+}
+
+func TestMachineProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]config.Provider{
+		"KubevirtMachine": config.ProviderKubevirt,
+		"ScalewayMachine": config.ProviderScaleway,
+		"AzureMachine":    config.ProviderAzure,
+		"DockerMachine":   config.ProviderDocker,
+	}
+
+	for kind, expected := range tests {
+		t.Run(kind, func(t *testing.T) {
+			t.Parallel()
+
+			machine := &clusterv1.Machine{Spec: clusterv1.MachineSpec{
+				InfrastructureRef: corev1.ObjectReference{Kind: kind},
+			}}
+
+			provider := machineProvider(machine)
+			if provider != expected {
+				t.Fatalf("expected provider %q, got %q", expected, provider)
+			}
+		})
+	}
+}
+
+func TestEC2MetaData(t *testing.T) {
+	t.Parallel()
+
+	metaData := ec2MetaData(newTestMachine())
+
+	expected := map[string]string{
+		"instance-id":                 "8a3c6d1e",
+		"hostname":                    "prod-workers-abcde",
+		"local-hostname":              "prod-workers-abcde",
+		"local-ipv4":                  "10.0.0.5",
+		"public-ipv4":                 "203.0.113.5",
+		"placement/availability-zone": "zone-a",
+	}
+	if !maps.Equal(metaData, expected) {
+		t.Fatalf("expected %v, got %v", expected, metaData)
+	}
+}
+
+func TestOpenStackMetaData(t *testing.T) {
+	t.Parallel()
+
+	metaData := newOpenStackMetaData(newTestMachine())
+
+	if metaData.UUID != "8a3c6d1e" || metaData.Hostname != "prod-workers-abcde" ||
+		metaData.AvailabilityZone != "zone-a" || metaData.Meta["cluster"] != "prod" {
+		t.Fatalf("expected the metadata of the machine, got %+v", metaData)
+	}
+}
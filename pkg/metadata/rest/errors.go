@@ -6,4 +6,9 @@ import "errors"
 var (
 	// ErrUnexpectedResponse is returned when the response from the endpoint is unexpected.
 	ErrUnexpectedResponse = errors.New("unexpected response from endpoint")
+	// ErrMachineNotTrusted is returned when the machine asking for its configuration is not trusted.
+	ErrMachineNotTrusted = errors.New("machine is not trusted")
+	// ErrMachineConfigNotReady is returned when the bootstrap provider has not generated the
+	// configuration of the machine yet.
+	ErrMachineConfigNotReady = errors.New("machine config is not ready")
 )
@@ -2,6 +2,7 @@
 package userdata
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -15,7 +16,9 @@ import (
 	"github.com/siderolabs/talos/pkg/machinery/config/types/v1alpha1"
 	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	clientgoclientset "k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrlclint "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -30,8 +33,6 @@ import (
 // @Router   /configs/user-data [get]
 //
 // GetUserData handles requests for user data metadata.
-//
-//nolint:funlen,cyclop // Complexity is only apparent due to multiple error checks.
 func GetUserData(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Request) {
 	return func(response http.ResponseWriter, request *http.Request) {
 		if request.Method != http.MethodGet {
@@ -40,70 +41,181 @@ func GetUserData(cfg *config.KommodityConfig) func(http.ResponseWriter, *http.Re
 			return
 		}
 
-		//nolint:varnamelen // Variable name ip is appropriate for the context.
-		ip, err := net.GetOriginalIPFromRequest(request)
-		if err != nil {
-			http.Error(response, err.Error(), http.StatusBadRequest)
-
+		machine, found := FindTrustedMachine(response, request, cfg)
+		if !found {
 			return
 		}
 
-		trusted, err := isTrusted(request.Context(), ip, cfg)
-		if err != nil {
-			http.Error(response, "Failed to verify trust status", http.StatusInternalServerError)
+		WriteUserData(response, request, cfg, machine)
+	}
+}
 
-			return
-		}
+// FindTrustedMachine returns the Machine the request comes from, if it is trusted. It
+// answers the request with an error and reports false otherwise.
+func FindTrustedMachine(response http.ResponseWriter, request *http.Request,
+	cfg *config.KommodityConfig) (*clusterv1.Machine, bool) {
+	//nolint:varnamelen // Variable name ip is appropriate for the context.
+	ip, err := net.GetOriginalIPFromRequest(request)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
 
-		if !trusted {
-			http.Error(response, "Unauthorized: machine is not trusted", http.StatusUnauthorized)
+		return nil, false
+	}
 
-			return
+	machine, err := TrustedMachine(request.Context(), cfg, ip)
+	if err != nil {
+		switch {
+		case errors.Is(err, restutils.ErrMachineNotTrusted):
+			http.Error(response, "Unauthorized: machine is not trusted", http.StatusUnauthorized)
+		case errors.Is(err, net.ErrNoMachineFound):
+			http.Error(response, "Machine not found", http.StatusNotFound)
+		default:
+			http.Error(response, "Failed to find trusted machine", http.StatusInternalServerError)
 		}
 
-		ctrlClient, err := ctrlclint.New(cfg.ClientConfig.LoopbackClientConfig, ctrlclint.Options{})
-		if err != nil {
-			http.Error(response, "Failed to create controller client", http.StatusInternalServerError)
+		return nil, false
+	}
 
-			return
-		}
+	return machine, true
+}
 
-		machine, err := net.FindManagedMachineByIP(request.Context(), &ctrlClient, ip)
-		if err != nil {
-			if errors.Is(err, net.ErrNoMachineFound) {
-				http.Error(response, "Machine not found", http.StatusNotFound)
-			} else {
-				http.Error(response, "Failed to find machine by IP", http.StatusInternalServerError)
-			}
+// TrustedMachine returns the Machine with the IP address, if it is trusted.
+//
+//nolint:varnamelen // Variable name ip is appropriate for the context.
+func TrustedMachine(ctx context.Context, cfg *config.KommodityConfig, ip string) (*clusterv1.Machine, error) {
+	trusted, err := isTrusted(ctx, ip, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify trust status: %w", err)
+	}
 
-			return
-		}
+	if !trusted {
+		return nil, restutils.ErrMachineNotTrusted
+	}
 
-		machineConfig, err := fetchMachineConfig(request.Context(), cfg, machine)
-		if err != nil {
-			http.Error(response, "Failed to fetch machine config", http.StatusInternalServerError)
+	ctrlClient, err := ctrlclint.New(cfg.ClientConfig.LoopbackClientConfig, ctrlclint.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller client: %w", err)
+	}
 
-			return
-		}
+	machine, err := net.FindManagedMachineByIP(ctx, &ctrlClient, ip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find machine by IP: %w", err)
+	}
+
+	return machine, nil
+}
 
-		response.Header().Set("Content-Type", "application/x-yaml")
+// WriteUserData answers the request with the Talos machine configuration of the Machine.
+func WriteUserData(response http.ResponseWriter, request *http.Request, cfg *config.KommodityConfig,
+	machine *clusterv1.Machine) {
+	userData, _, err := UserData(request.Context(), cfg, machine)
+	if err != nil {
+		http.Error(response, "Failed to fetch machine config", http.StatusInternalServerError)
+
+		return
+	}
 
-		_, err = response.Write([]byte("#!talos\n"))
+	response.Header().Set("Content-Type", "application/x-yaml")
+
+	_, err = response.Write(userData)
+	if err != nil {
+		http.Error(response, "Failed to write machine config", http.StatusInternalServerError)
+
+		return
+	}
+}
+
+// UserData returns the Talos machine configuration of the Machine, with the resource
+// version of the bootstrap data Secret it was read from.
+func UserData(ctx context.Context, cfg *config.KommodityConfig, machine *clusterv1.Machine) ([]byte, string, error) {
+	secretAPI, err := bootstrapDataSecrets(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if machine.Spec.Bootstrap.DataSecretName == nil {
+		return nil, "", restutils.ErrMachineConfigNotReady
+	}
+
+	secret, err := secretAPI.Get(ctx, *machine.Spec.Bootstrap.DataSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	var machineConfig v1alpha1.Config
+
+	err = yaml.Unmarshal(secret.Data["value"], &machineConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal machine config: %w", err)
+	}
+
+	var userData bytes.Buffer
+
+	userData.WriteString("#!talos\n")
+
+	err = yaml.NewEncoder(&userData).Encode(&machineConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode machine config: %w", err)
+	}
+
+	return userData.Bytes(), secret.ResourceVersion, nil
+}
+
+// WatchUserData calls send with the Talos machine configuration of the Machine, and
+// again whenever it changes, until the context ends or send or a read fails.
+func WatchUserData(ctx context.Context, cfg *config.KommodityConfig, machine *clusterv1.Machine,
+	send func(userData []byte, version string) error) error {
+	secretAPI, err := bootstrapDataSecrets(cfg)
+	if err != nil {
+		return err
+	}
+
+	var sentVersion string
+
+	for {
+		userData, version, err := UserData(ctx, cfg, machine)
 		if err != nil {
-			http.Error(response, "Failed to write talos header", http.StatusInternalServerError)
+			return err
+		}
 
-			return
+		if version != sentVersion {
+			err = send(userData, version)
+			if err != nil {
+				return err
+			}
+
+			sentVersion = version
 		}
 
-		err = yaml.NewEncoder(response).Encode(machineConfig)
+		watcher, err := secretAPI.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", *machine.Spec.Bootstrap.DataSecretName).String(),
+			ResourceVersion: version,
+		})
 		if err != nil {
-			http.Error(response, "Failed to encode machine config", http.StatusInternalServerError)
+			return fmt.Errorf("failed to watch secret: %w", err)
+		}
 
-			return
+		// The configuration is read again on any event, or when the watch times out.
+		select {
+		case <-ctx.Done():
+			watcher.Stop()
+
+			return nil
+		case <-watcher.ResultChan():
+			watcher.Stop()
 		}
 	}
 }
 
+func bootstrapDataSecrets(cfg *config.KommodityConfig) (typedcorev1.SecretInterface, error) {
+	kubeClient, err := clientgoclientset.NewForConfig(cfg.ClientConfig.LoopbackClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kube client: %w", err)
+	}
+
+	return kubeClient.CoreV1().Secrets(config.KommodityNamespace), nil
+}
+
 func isTrusted(ctx context.Context, ip string, cfg *config.KommodityConfig) (bool, error) {
 	trustEndpoint := strings.Replace(attestation.AttestationTrustEndpoint, "{ip}", ip, 1)
 	trustURL := fmt.Sprintf("http://localhost:%d%s", cfg.ServerPort, trustEndpoint)
@@ -135,28 +247,3 @@ func isTrusted(ctx context.Context, ip string, cfg *config.KommodityConfig) (boo
 		return false, restutils.ErrUnexpectedResponse
 	}
 }
-
-func fetchMachineConfig(ctx context.Context, cfg *config.KommodityConfig,
-	machine *clusterv1.Machine) (*v1alpha1.Config, error) {
-	kubeClient, err := clientgoclientset.NewForConfig(cfg.ClientConfig.LoopbackClientConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kube client: %w", err)
-	}
-
-	secretName := *machine.Spec.Bootstrap.DataSecretName
-	secretAPI := kubeClient.CoreV1().Secrets(config.KommodityNamespace)
-
-	secret, err := secretAPI.Get(ctx, secretName, metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get secret: %w", err)
-	}
-
-	var machineConfig v1alpha1.Config
-
-	err = yaml.Unmarshal(secret.Data["value"], &machineConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal machine config: %w", err)
-	}
-
-	return &machineConfig, nil
-}
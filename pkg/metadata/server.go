@@ -1,17 +1,44 @@
 package metadata
 
 import (
+	"context"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 
 	"github.com/kommodity-io/kommodity/pkg/combinedserver"
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	restcompat "github.com/kommodity-io/kommodity/pkg/metadata/rest/compat"
 	restuserdata "github.com/kommodity-io/kommodity/pkg/metadata/rest/userdata"
 )
 
-// NewHTTPMuxFactory creates a new HTTP mux factory for the metadata server.
-func NewHTTPMuxFactory(cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
+// NewHTTPMuxFactory creates a new HTTP mux factory for the metadata server. When HTTP
+// authentication is enabled, endpoints that are not exempt require a bearer token. The
+// endpoints of the cloud metadata formats are only served if a provider uses them.
+func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
 	return func(mux *http.ServeMux) error {
-		mux.HandleFunc("GET /configs/user-data", restuserdata.GetUserData(cfg))
+		authenticate, err := httpauth.NewMiddleware(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to set up metadata authentication: %w", err)
+		}
+
+		mux.HandleFunc("GET /configs/user-data", authenticate(restuserdata.GetUserData(cfg)))
+
+		formats := slices.Collect(maps.Values(cfg.MetadataCompatibility))
+
+		if slices.Contains(formats, config.MetadataFormatEC2) {
+			mux.HandleFunc("GET /latest/meta-data/{key...}", authenticate(restcompat.GetEC2MetaData(cfg)))
+			mux.HandleFunc("GET /latest/user-data",
+				authenticate(restcompat.GetUserData(cfg, config.MetadataFormatEC2)))
+		}
+
+		if slices.Contains(formats, config.MetadataFormatOpenStack) {
+			mux.HandleFunc("GET /openstack/latest/meta_data.json", authenticate(restcompat.GetOpenStackMetaData(cfg)))
+			mux.HandleFunc("GET /openstack/latest/user_data",
+				authenticate(restcompat.GetUserData(cfg, config.MetadataFormatOpenStack)))
+		}
 
 		return nil
 	}
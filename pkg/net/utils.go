@@ -4,25 +4,96 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"strings"
 
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"k8s.io/apimachinery/pkg/labels"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrlclint "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// GetOriginalIPFromRequest extracts the IP address from the HTTP request, X-Forwarded-For if present.
+// GetOriginalIPFromRequest extracts the IP address from the HTTP request. The address a
+// verified client certificate names wins, as it cannot be spoofed. Otherwise the leftmost
+// valid address in X-Forwarded-For wins, then the address of the connection. Only
+// canonical addresses without port or zone are returned, as callers use them as map keys,
+// label values and in URLs.
 func GetOriginalIPFromRequest(request *http.Request) (string, error) {
-	//nolint:varnamelen // Variable name ip is appropriate for the context.
-	ip := request.Header.Get("X-Forwarded-For")
-	if ip == "" {
-		ip = request.RemoteAddr
-		if ip == "" {
-			return "", ErrIPRequired
+	if ip, ok := ClientCertificateIP(request); ok {
+		return ip, nil
+	}
+
+	for _, header := range request.Header.Values("X-Forwarded-For") {
+		for raw := range strings.SplitSeq(header, ",") {
+			if ip, ok := canonicalIP(strings.TrimSpace(raw)); ok {
+				return ip, nil
+			}
 		}
 	}
 
-	return ip, nil
+	if ip, ok := canonicalIP(request.RemoteAddr); ok {
+		return ip, nil
+	}
+
+	return "", ErrIPRequired
+}
+
+// GetOriginalIPFromContext extracts the IP address of the caller of a gRPC request, in the
+// same order as GetOriginalIPFromRequest: the verified client certificate, X-Forwarded-For
+// and then the address of the connection.
+func GetOriginalIPFromContext(ctx context.Context) (string, error) {
+	request := &http.Request{Header: http.Header{}}
+
+	if client, found := peer.FromContext(ctx); found {
+		request.RemoteAddr = client.Addr.String()
+
+		if tlsInfo, isTLS := client.AuthInfo.(credentials.TLSInfo); isTLS {
+			request.TLS = &tlsInfo.State
+		}
+	}
+
+	incomingMetadata, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range incomingMetadata.Get("X-Forwarded-For") {
+		request.Header.Add("X-Forwarded-For", value)
+	}
+
+	return GetOriginalIPFromRequest(request)
+}
+
+// ClientCertificateIP returns the machine address named by the verified client certificate
+// of the request: its first IP SAN, or its common name when that is an address.
+func ClientCertificateIP(request *http.Request) (string, bool) {
+	if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 || len(request.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	cert := request.TLS.VerifiedChains[0][0]
+
+	for _, ip := range cert.IPAddresses {
+		if addr, ok := netip.AddrFromSlice(ip); ok {
+			return addr.Unmap().String(), true
+		}
+	}
+
+	return canonicalIP(cert.Subject.CommonName)
+}
+
+// canonicalIP parses an address with or without port.
+func canonicalIP(raw string) (string, bool) {
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(raw)
+		if err != nil {
+			return "", false
+		}
+
+		addr = addrPort.Addr()
+	}
+
+	return addr.WithZone("").Unmap().String(), true
 }
 
 // FindManagedMachineByIP finds a managed-by-kommodity Machine by its IP address.
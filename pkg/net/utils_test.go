@@ -0,0 +1,182 @@
+package net_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	stdnet "net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/net"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestGetOriginalIPFromRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		forwardedFor []string
+		remoteAddr   string
+		expected     string
+	}{
+		"remote address":         {remoteAddr: "10.0.0.1:51234", expected: "10.0.0.1"},
+		"remote IPv6 address":    {remoteAddr: "[fd00::1]:51234", expected: "fd00::1"},
+		"forwarded for":          {forwardedFor: []string{"10.0.0.2"}, remoteAddr: "10.0.0.1:1", expected: "10.0.0.2"},
+		"leftmost forwarded for": {forwardedFor: []string{"10.0.0.2, 10.0.0.3"}, expected: "10.0.0.2"},
+		"invalid forwarded for":  {forwardedFor: []string{"unknown, 10.0.0.3"}, expected: "10.0.0.3"},
+		"forwarded with port":    {forwardedFor: []string{"10.0.0.2:80"}, expected: "10.0.0.2"},
+		"path in forwarded for":  {forwardedFor: []string{"10.0.0.2/../x"}, remoteAddr: "10.0.0.1:1", expected: "10.0.0.1"},
+		"zone":                   {forwardedFor: []string{"fe80::1%eth0"}, expected: "fe80::1"},
+		"IPv4-mapped IPv6":       {forwardedFor: []string{"::ffff:10.0.0.2"}, expected: "10.0.0.2"},
+	}
+
+	for name, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/nonce", nil)
+		request.RemoteAddr = test.remoteAddr
+
+		for _, value := range test.forwardedFor {
+			request.Header.Add("X-Forwarded-For", value)
+		}
+
+		ip, err := net.GetOriginalIPFromRequest(request)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if ip != test.expected {
+			t.Fatalf("%s: expected %s, got %s", name, test.expected, ip)
+		}
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/nonce", nil)
+	request.RemoteAddr = ""
+	request.Header.Set("X-Forwarded-For", "unknown")
+
+	_, err := net.GetOriginalIPFromRequest(request)
+	if err == nil {
+		t.Fatalf("expected an error for a request without a valid address")
+	}
+}
+
+func TestGetOriginalIPFromRequestPrefersClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cert     *x509.Certificate
+		expected string
+	}{
+		"IP SAN": {
+			cert:     &x509.Certificate{IPAddresses: []stdnet.IP{stdnet.ParseIP("10.0.0.5")}},
+			expected: "10.0.0.5",
+		},
+		"common name": {
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "fd00::5"}},
+			expected: "fd00::5",
+		},
+		"no address": {
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "worker-1"}},
+			expected: "10.0.0.2",
+		},
+	}
+
+	for name, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/nonce", nil)
+		request.Header.Set("X-Forwarded-For", "10.0.0.2")
+		request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{test.cert}}}
+
+		ip, err := net.GetOriginalIPFromRequest(request)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if ip != test.expected {
+			t.Fatalf("%s: expected %s, got %s", name, test.expected, ip)
+		}
+	}
+
+	// Certificates that were not verified do not name the machine.
+	request := httptest.NewRequest(http.MethodGet, "/nonce", nil)
+	request.RemoteAddr = "10.0.0.1:1"
+	request.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+		{IPAddresses: []stdnet.IP{stdnet.ParseIP("10.0.0.5")}},
+	}}
+
+	ip, err := net.GetOriginalIPFromRequest(request)
+	if err != nil || ip != "10.0.0.1" {
+		t.Fatalf("expected the connection address, got %s (%v)", ip, err)
+	}
+}
+
+func TestGetOriginalIPFromContext(t *testing.T) {
+	t.Parallel()
+
+	address := &stdnet.TCPAddr{IP: stdnet.ParseIP("10.0.0.1"), Port: 51234}
+	verifiedCert := &x509.Certificate{IPAddresses: []stdnet.IP{stdnet.ParseIP("10.0.0.5")}}
+
+	tests := map[string]struct {
+		peer         *peer.Peer
+		forwardedFor string
+		expected     string
+	}{
+		"peer address":  {peer: &peer.Peer{Addr: address}, expected: "10.0.0.1"},
+		"forwarded for": {peer: &peer.Peer{Addr: address}, forwardedFor: "10.0.0.2", expected: "10.0.0.2"},
+		"client certificate": {
+			peer: &peer.Peer{Addr: address, AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{verifiedCert}},
+			}}},
+			forwardedFor: "10.0.0.2",
+			expected:     "10.0.0.5",
+		},
+	}
+
+	for name, test := range tests {
+		ctx := peer.NewContext(t.Context(), test.peer)
+		if test.forwardedFor != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", test.forwardedFor))
+		}
+
+		ip, err := net.GetOriginalIPFromContext(ctx)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if ip != test.expected {
+			t.Fatalf("%s: expected %s, got %s", name, test.expected, ip)
+		}
+	}
+
+	_, err := net.GetOriginalIPFromContext(t.Context())
+	if err == nil {
+		t.Fatalf("expected an error for a request without a peer")
+	}
+}
+
+// FuzzGetOriginalIPFromRequest checks that only plain IP addresses are extracted from
+// the headers and addresses of requests sent by booting machines.
+func FuzzGetOriginalIPFromRequest(f *testing.F) {
+	f.Add("10.0.0.2, 10.0.0.3", "10.0.0.1:51234")
+	f.Add("", "[fd00::1]:51234")
+	f.Add("fe80::1%eth0", "")
+	f.Add("10.0.0.2/../../x?y", "127.0.0.1")
+
+	f.Fuzz(func(t *testing.T, forwardedFor, remoteAddr string) {
+		request := httptest.NewRequest(http.MethodGet, "/nonce", nil)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+
+		ip, err := net.GetOriginalIPFromRequest(request)
+		if err != nil {
+			return
+		}
+
+		parsed := stdnet.ParseIP(ip)
+		if parsed == nil || parsed.String() != ip {
+			t.Fatalf("extracted %q from X-Forwarded-For %q and remote address %q, which is not a canonical IP",
+				ip, forwardedFor, remoteAddr)
+		}
+	})
+}
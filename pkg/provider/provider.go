@@ -6,15 +6,18 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
+	"sync"
 
 	"github.com/kommodity-io/kommodity/pkg/config"
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	"go.uber.org/zap"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -22,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
 
 	"embed"
 )
@@ -31,6 +35,14 @@ import (
 // CRDs all declare this same service path; it must stay in sync with the webhook server.
 const conversionWebhookPath = "/convert"
 
+// kommodityCRDDirectory holds the CRDs of the kommodity.io API. Unlike provider CRDs they
+// are not fetched by scripts/fetch-providers.sh and are always applied.
+const kommodityCRDDirectory = "kommodity"
+
+// fieldManager owns the fields of the provider CRDs and webhook configurations that
+// Kommodity applies.
+const fieldManager = "kommodity"
+
 //go:embed crds/**/*.yaml
 var crds embed.FS
 
@@ -94,66 +106,116 @@ func (pc *Cache) LoadCache(ctx context.Context, cfg *config.KommodityConfig) err
 }
 
 // ApplyCRDProviders applies all provider CRDs to the given dynamic Kubernetes client.
-//
-//nolint:gocognit,funlen,cyclop,nestif,nolintlint
+// The groups are applied concurrently, the CRDs of a group one after another.
 func (pc *Cache) ApplyCRDProviders(ctx context.Context,
 	webhookURL string,
 	webhookCRT []byte,
 	client *dynamic.DynamicClient) error {
 	logger := logging.FromContext(ctx)
 
+	var (
+		mutex     sync.Mutex
+		waitGroup sync.WaitGroup
+		summary   crdApplySummary
+		errs      []error
+	)
+
 	for group, objs := range pc.providerCRDs {
-		logger.Info("Applying provider CRDs", zap.String("group", group), zap.Int("count", len(objs)))
+		waitGroup.Go(func() {
+			groupSummary, err := pc.applyGroupCRDs(ctx, group, objs, webhookURL, webhookCRT, client)
 
-		for _, obj := range objs {
-			logger.Info("Applying CRD", zap.String("group", group))
-
-			conversion, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "conversion")
-			if found && conversion != nil {
-				conversionStrategy, found, _ := unstructured.NestedString(obj.Object, "spec", "conversion", "strategy")
-				if found && conversionStrategy == "Webhook" {
-					webhook, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", "conversion", "webhook")
-					if err != nil || !found {
-						return fmt.Errorf("failed to extract webhook from crd configuration: %w", err)
-					}
-
-					webhookMap, success := webhook.(map[string]any)
-					if !success {
-						return ErrFailedToConvertWebhook
-					}
-
-					err = pc.updateWebhookWithClientData(webhookMap, webhookURL, webhookCRT)
-					if err != nil {
-						return fmt.Errorf("failed to update webhook with client data: %w", err)
-					}
-
-					err = unstructured.SetNestedField(obj.Object, webhookMap, "spec", "conversion", "webhook")
-					if err != nil {
-						return fmt.Errorf("failed to set webhook in crd configuration: %w", err)
-					}
-				}
-			}
+			mutex.Lock()
+			defer mutex.Unlock()
 
-			crdGVR := apiextensionsv1.SchemeGroupVersion.
-				WithResource("customresourcedefinitions")
+			summary.merge(groupSummary)
 
-			err := pc.load(ctx, client, crdGVR, &obj)
 			if err != nil {
-				return fmt.Errorf("failed to load CRD for group %s: %w", group, err)
+				errs = append(errs, err)
 			}
-		}
+		})
 	}
 
+	waitGroup.Wait()
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	logger.Info("Applied provider CRDs",
+		zap.Int("created", len(summary.created)),
+		zap.Int("updated", len(summary.updated)),
+		zap.Int("unchanged", summary.unchanged),
+		zap.Strings("changed", append(summary.created, summary.updated...)))
+
 	return nil
 }
 
+// applyGroupCRDs applies the CRDs of a provider group. The objects are changed in place
+// to point conversion webhooks at Kommodity.
+//
+//nolint:gocognit,nestif,nolintlint
+func (pc *Cache) applyGroupCRDs(ctx context.Context,
+	group string,
+	objs []unstructured.Unstructured,
+	webhookURL string,
+	webhookCRT []byte,
+	client *dynamic.DynamicClient) (crdApplySummary, error) {
+	logger := logging.FromContext(ctx)
+
+	var summary crdApplySummary
+
+	logger.Info("Applying provider CRDs", zap.String("group", group), zap.Int("count", len(objs)))
+
+	for _, obj := range objs {
+		logger.Info("Applying CRD", zap.String("group", group))
+
+		conversion, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "conversion")
+		if found && conversion != nil {
+			conversionStrategy, found, _ := unstructured.NestedString(obj.Object, "spec", "conversion", "strategy")
+			if found && conversionStrategy == "Webhook" {
+				webhook, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", "conversion", "webhook")
+				if err != nil || !found {
+					return summary, fmt.Errorf("failed to extract webhook from crd configuration: %w", err)
+				}
+
+				webhookMap, success := webhook.(map[string]any)
+				if !success {
+					return summary, ErrFailedToConvertWebhook
+				}
+
+				err = pc.updateWebhookWithClientData(webhookMap, webhookURL, webhookCRT)
+				if err != nil {
+					return summary, fmt.Errorf("failed to update webhook with client data: %w", err)
+				}
+
+				err = unstructured.SetNestedField(obj.Object, webhookMap, "spec", "conversion", "webhook")
+				if err != nil {
+					return summary, fmt.Errorf("failed to set webhook in crd configuration: %w", err)
+				}
+			}
+		}
+
+		crdGVR := apiextensionsv1.SchemeGroupVersion.
+			WithResource("customresourcedefinitions")
+
+		result, err := pc.applyCRD(ctx, client, crdGVR, &obj)
+		if err != nil {
+			return summary, fmt.Errorf("failed to load CRD for group %s: %w", group, err)
+		}
+
+		summary.record(obj.GetName(), result)
+	}
+
+	return summary, nil
+}
+
 // ReconcileConversionCABundles forcibly patches the conversion webhook clientConfig (URL + caBundle)
 // on every conversion-webhook provider CRD via a JSON merge patch, so the caBundle always matches the
 // current serving certificate — including on already-existing CRDs after a restart.
 //
-// A targeted merge patch is used instead of relying on the create-or-replace path in load(): a
-// full-object Update of an existing CRD was observed to leave the stored caBundle untouched (the
-// served cert and the persisted caBundle then diverge, breaking all CRD conversions). The merge
+// A targeted merge patch is used instead of relying on applyCRD alone: a full-object Update of an
+// existing CRD was observed to leave the stored caBundle untouched (the served cert and the
+// persisted caBundle then diverge, breaking all CRD conversions). The merge
 // patch is a server-side read-modify-write of just the clientConfig, which reliably updates it.
 func (pc *Cache) ReconcileConversionCABundles(
 	ctx context.Context,
@@ -241,10 +303,12 @@ func (pc *Cache) ApplyWebhookProviders(ctx context.Context,
 				WithResource("validatingwebhookconfigurations")
 		}
 
-		err = pc.load(ctx, client, webhookGVR, &obj)
+		result, err := pc.applyWebhook(ctx, client, webhookGVR, &obj)
 		if err != nil {
 			return fmt.Errorf("failed to load webhook %s: %w", obj.GetName(), err)
 		}
+
+		logger.Info("Applied webhook", zap.String("name", obj.GetName()), zap.Stringer("result", result))
 	}
 
 	return nil
@@ -325,7 +389,7 @@ func (pc *Cache) loadCRDCache(ctx context.Context, cfg *config.KommodityConfig)
 	for _, provider := range entries {
 		providerName := config.Provider(provider.Name())
 
-		if !slices.Contains(cfg.InfrastructureProviders, providerName) {
+		if provider.Name() != kommodityCRDDirectory && !slices.Contains(cfg.InfrastructureProviders, providerName) {
 			logger.Info("Skipping CRD, provider not in enabled providers",
 				zap.String("provider", provider.Name()))
 
@@ -493,32 +557,202 @@ func (pc *Cache) decodeCRD(crd []byte) (string, *unstructured.Unstructured, erro
 	return group, obj, nil
 }
 
-func (pc *Cache) load(ctx context.Context,
+// applyResult describes what applyCRD or applyWebhook did with a single object.
+type applyResult int
+
+const (
+	applyCreated applyResult = iota
+	applyUpdated
+	applyUnchanged
+)
+
+func (r applyResult) String() string {
+	switch r {
+	case applyCreated:
+		return "created"
+	case applyUpdated:
+		return "updated"
+	case applyUnchanged:
+		return "unchanged"
+	}
+
+	return "unknown"
+}
+
+// crdApplySummary tallies the outcome of applying all provider CRDs.
+type crdApplySummary struct {
+	created   []string
+	updated   []string
+	unchanged int
+}
+
+func (s *crdApplySummary) record(name string, result applyResult) {
+	switch result {
+	case applyCreated:
+		s.created = append(s.created, name)
+	case applyUpdated:
+		s.updated = append(s.updated, name)
+	case applyUnchanged:
+		s.unchanged++
+	}
+}
+
+func (s *crdApplySummary) merge(other crdApplySummary) {
+	s.created = append(s.created, other.created...)
+	s.updated = append(s.updated, other.updated...)
+	s.unchanged += other.unchanged
+}
+
+// applyCRD server-side applies the CRD when it is missing or the stored definition differs
+// from the embedded one. Unchanged CRDs are left alone: every write bumps the generation
+// and makes the apiextensions controllers and all watchers of the CRD re-converge, which
+// is costly in large installs when nothing changed.
+func (pc *Cache) applyCRD(ctx context.Context,
 	client *dynamic.DynamicClient,
 	gvr schema.GroupVersionResource,
-	obj *unstructured.Unstructured) error {
-	_, err := client.Resource(gvr).Create(ctx, obj, metav1.CreateOptions{})
-	if err == nil {
-		return nil
+	obj *unstructured.Unstructured) (applyResult, error) {
+	existing, err := client.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return applyCreated, serverSideApply(ctx, client.Resource(gvr), obj)
 	}
 
-	if errors.IsAlreadyExists(err) {
-		// Fetch the existing CRD to get its resourceVersion
-		existing, getErr := client.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
-		if getErr != nil {
-			return fmt.Errorf("failed to get existing CRD: %w", getErr)
+	if err != nil {
+		return applyUpdated, fmt.Errorf("failed to get existing CRD: %w", err)
+	}
+
+	upToDate, err := crdUpToDate(obj, existing)
+	if err != nil {
+		return applyUpdated, err
+	}
+
+	if upToDate {
+		return applyUnchanged, nil
+	}
+
+	return applyUpdated, serverSideApply(ctx, client.Resource(gvr), obj)
+}
+
+// applyWebhook server-side applies the webhook configuration when it is missing or the
+// stored webhooks differ from the embedded ones, for the same reason as applyCRD.
+func (pc *Cache) applyWebhook(ctx context.Context,
+	client *dynamic.DynamicClient,
+	gvr schema.GroupVersionResource,
+	obj *unstructured.Unstructured) (applyResult, error) {
+	existing, err := client.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return applyCreated, serverSideApply(ctx, client.Resource(gvr), obj)
+	}
+
+	if err != nil {
+		return applyUpdated, fmt.Errorf("failed to get existing webhook configuration: %w", err)
+	}
+
+	if webhookUpToDate(obj, existing) {
+		return applyUnchanged, nil
+	}
+
+	return applyUpdated, serverSideApply(ctx, client.Resource(gvr), obj)
+}
+
+// serverSideApply applies the object as the Kommodity field manager. Conflicts are forced,
+// as the embedded objects are the source of truth for the fields they set.
+func serverSideApply(ctx context.Context, resource dynamic.ResourceInterface, obj *unstructured.Unstructured) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	_, err = resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        ptr.To(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// crdUpToDate reports whether the stored CRD already matches the desired one. Both specs
+// are defaulted the way the API server defaults them before being compared semantically,
+// so server-populated fields do not count as changes. Status and server-managed metadata
+// are ignored; labels and annotations only need to be present on the stored object.
+func crdUpToDate(desired, existing *unstructured.Unstructured) (bool, error) {
+	var desiredCRD, existingCRD apiextensionsv1.CustomResourceDefinition
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(desired.Object, &desiredCRD)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert CRD %s: %w", desired.GetName(), err)
+	}
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, &existingCRD)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert existing CRD %s: %w", existing.GetName(), err)
+	}
+
+	apiextensionsv1.SetObjectDefaults_CustomResourceDefinition(&desiredCRD)
+	apiextensionsv1.SetObjectDefaults_CustomResourceDefinition(&existingCRD)
+
+	if !equality.Semantic.DeepEqual(desiredCRD.Spec, existingCRD.Spec) {
+		return false, nil
+	}
+
+	return containsAll(existingCRD.Labels, desiredCRD.Labels) &&
+		containsAll(existingCRD.Annotations, desiredCRD.Annotations), nil
+}
+
+// webhookUpToDate reports whether the stored webhook configuration already matches the
+// desired one. There are no defaulting functions for webhook configurations outside of
+// the API server, so the desired webhooks only need to be contained in the stored ones,
+// which additionally carry the defaulted fields.
+func webhookUpToDate(desired, existing *unstructured.Unstructured) bool {
+	return containsFields(existing.Object["webhooks"], desired.Object["webhooks"]) &&
+		containsAll(existing.GetLabels(), desired.GetLabels()) &&
+		containsAll(existing.GetAnnotations(), desired.GetAnnotations())
+}
+
+// containsFields reports whether every field set in want has the same value in have.
+// Lists must have the same length and their items are compared in order.
+func containsFields(have, want any) bool {
+	switch want := want.(type) {
+	case map[string]any:
+		haveMap, ok := have.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		for key, value := range want {
+			current, ok := haveMap[key]
+			if !ok || !containsFields(current, value) {
+				return false
+			}
 		}
 
-		// Set the resourceVersion to ensure we update the correct version
-		obj.SetResourceVersion(existing.GetResourceVersion())
+		return true
+	case []any:
+		haveSlice, ok := have.([]any)
+		if !ok || len(haveSlice) != len(want) {
+			return false
+		}
 
-		_, err = client.Resource(gvr).Update(ctx, obj, metav1.UpdateOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to update CRD: %w", err)
+		for index := range want {
+			if !containsFields(haveSlice[index], want[index]) {
+				return false
+			}
 		}
 
-		return nil
+		return true
+	default:
+		return equality.Semantic.DeepEqual(have, want)
+	}
+}
+
+func containsAll(have, want map[string]string) bool {
+	for key, value := range want {
+		if current, ok := have[key]; !ok || current != value {
+			return false
+		}
 	}
 
-	return fmt.Errorf("failed to create CRD: %w", err)
+	return true
 }
@@ -0,0 +1,247 @@
+//nolint:testpackage // white-box tests exercise the unexported CRD and webhook diffs
+package provider
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestCRD() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name":   "widgets.example.com",
+			"labels": map[string]any{"cluster.x-k8s.io/provider": "infrastructure-example"},
+		},
+		"spec": map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"kind": "Widget", "plural": "widgets"},
+			"scope": "Namespaced",
+			"versions": []any{map[string]any{
+				"name":    "v1",
+				"served":  true,
+				"storage": true,
+				"schema": map[string]any{"openAPIV3Schema": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"spec": map[string]any{"type": "string"},
+					},
+				}},
+			}},
+		},
+	}}
+}
+
+// newStoredCRD returns the CRD as the API server would return it: defaulted, with
+// server-managed metadata and a status.
+func newStoredCRD(t *testing.T) *unstructured.Unstructured {
+	t.Helper()
+
+	stored := newTestCRD()
+	stored.SetResourceVersion("42")
+	stored.SetGeneration(3)
+	stored.SetUID("0b7a5c6e-0000-0000-0000-000000000000")
+	stored.SetAnnotations(map[string]string{"controller-gen.kubebuilder.io/version": "v0.16.0"})
+
+	for path, value := range map[string]any{
+		"conversion": map[string]any{"strategy": "None"},
+		"names":      map[string]any{"kind": "Widget", "plural": "widgets", "listKind": "WidgetList", "singular": "widget"},
+	} {
+		err := unstructured.SetNestedField(stored.Object, value, "spec", path)
+		if err != nil {
+			t.Fatalf("failed to set spec.%s: %v", path, err)
+		}
+	}
+
+	err := unstructured.SetNestedField(stored.Object, map[string]any{
+		"acceptedNames": map[string]any{"kind": "Widget", "plural": "widgets"},
+	}, "status")
+	if err != nil {
+		t.Fatalf("failed to set status: %v", err)
+	}
+
+	return stored
+}
+
+func TestCRDUpToDateIgnoresServerNoise(t *testing.T) {
+	t.Parallel()
+
+	upToDate, err := crdUpToDate(newTestCRD(), newStoredCRD(t))
+	if err != nil {
+		t.Fatalf("crdUpToDate failed: %v", err)
+	}
+
+	if !upToDate {
+		t.Fatalf("expected CRD differing only in defaults, status and metadata to be up to date")
+	}
+}
+
+func TestCRDUpToDateDetectsChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(t *testing.T, desired *unstructured.Unstructured){
+		"schema": func(t *testing.T, desired *unstructured.Unstructured) {
+			t.Helper()
+
+			versions, _, _ := unstructured.NestedSlice(desired.Object, "spec", "versions")
+			version, _ := versions[0].(map[string]any)
+
+			err := unstructured.SetNestedField(version, "integer",
+				"schema", "openAPIV3Schema", "properties", "spec", "type")
+			if err != nil {
+				t.Fatalf("failed to change schema: %v", err)
+			}
+
+			err = unstructured.SetNestedSlice(desired.Object, versions, "spec", "versions")
+			if err != nil {
+				t.Fatalf("failed to set versions: %v", err)
+			}
+		},
+		"conversion": func(t *testing.T, desired *unstructured.Unstructured) {
+			t.Helper()
+
+			err := unstructured.SetNestedMap(desired.Object, map[string]any{
+				"strategy": "Webhook",
+				"webhook": map[string]any{
+					"conversionReviewVersions": []any{"v1"},
+					"clientConfig":             map[string]any{"url": "https://127.0.0.1:9443/convert"},
+				},
+			}, "spec", "conversion")
+			if err != nil {
+				t.Fatalf("failed to set conversion: %v", err)
+			}
+		},
+		"label": func(_ *testing.T, desired *unstructured.Unstructured) {
+			desired.SetLabels(map[string]string{"cluster.x-k8s.io/provider": "infrastructure-other"})
+		},
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			desired := newTestCRD()
+			mutate(t, desired)
+
+			upToDate, err := crdUpToDate(desired, newStoredCRD(t))
+			if err != nil {
+				t.Fatalf("crdUpToDate failed: %v", err)
+			}
+
+			if upToDate {
+				t.Fatalf("expected %s change to require an update", name)
+			}
+		})
+	}
+}
+
+func newTestWebhook() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "admissionregistration.k8s.io/v1",
+		"kind":       "ValidatingWebhookConfiguration",
+		"metadata":   map[string]any{"name": "example-validating-webhook-configuration"},
+		"webhooks": []any{map[string]any{
+			"name":                    "validation.widget.example.com",
+			"admissionReviewVersions": []any{"v1"},
+			"sideEffects":             "None",
+			"clientConfig": map[string]any{
+				"url":      "https://127.0.0.1:9443/validate-widget",
+				"caBundle": "Y2VydA==",
+			},
+			"namespaceSelector": map[string]any{},
+			"rules": []any{map[string]any{
+				"apiGroups":   []any{"example.com"},
+				"apiVersions": []any{"v1"},
+				"operations":  []any{"CREATE", "UPDATE"},
+				"resources":   []any{"widgets"},
+			}},
+		}},
+	}}
+}
+
+// newStoredWebhook returns the webhook configuration as the API server would return it,
+// with the defaulted fields of the webhook.
+func newStoredWebhook(t *testing.T) *unstructured.Unstructured {
+	t.Helper()
+
+	stored := newTestWebhook()
+	stored.SetResourceVersion("7")
+
+	webhooks, _, _ := unstructured.NestedSlice(stored.Object, "webhooks")
+	webhook, _ := webhooks[0].(map[string]any)
+	webhook["failurePolicy"] = "Fail"
+	webhook["matchPolicy"] = "Equivalent"
+	webhook["timeoutSeconds"] = int64(10)
+	webhook["objectSelector"] = map[string]any{}
+
+	rules, _ := webhook["rules"].([]any)
+	rule, _ := rules[0].(map[string]any)
+	rule["scope"] = "*"
+
+	err := unstructured.SetNestedSlice(stored.Object, webhooks, "webhooks")
+	if err != nil {
+		t.Fatalf("failed to set webhooks: %v", err)
+	}
+
+	return stored
+}
+
+func TestWebhookUpToDateIgnoresDefaults(t *testing.T) {
+	t.Parallel()
+
+	if !webhookUpToDate(newTestWebhook(), newStoredWebhook(t)) {
+		t.Fatalf("expected webhook configuration differing only in defaults to be up to date")
+	}
+}
+
+func TestWebhookUpToDateDetectsChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(t *testing.T, desired *unstructured.Unstructured){
+		"caBundle": func(t *testing.T, desired *unstructured.Unstructured) {
+			t.Helper()
+
+			webhooks, _, _ := unstructured.NestedSlice(desired.Object, "webhooks")
+			webhook, _ := webhooks[0].(map[string]any)
+
+			err := unstructured.SetNestedField(webhook, "bmV3IGNlcnQ=", "clientConfig", "caBundle")
+			if err != nil {
+				t.Fatalf("failed to change caBundle: %v", err)
+			}
+
+			err = unstructured.SetNestedSlice(desired.Object, webhooks, "webhooks")
+			if err != nil {
+				t.Fatalf("failed to set webhooks: %v", err)
+			}
+		},
+		"webhook added": func(t *testing.T, desired *unstructured.Unstructured) {
+			t.Helper()
+
+			webhooks, _, _ := unstructured.NestedSlice(desired.Object, "webhooks")
+
+			err := unstructured.SetNestedSlice(desired.Object,
+				append(webhooks, map[string]any{"name": "default.widget.example.com"}), "webhooks")
+			if err != nil {
+				t.Fatalf("failed to set webhooks: %v", err)
+			}
+		},
+		"label": func(_ *testing.T, desired *unstructured.Unstructured) {
+			desired.SetLabels(map[string]string{"cluster.x-k8s.io/provider": "infrastructure-example"})
+		},
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			desired := newTestWebhook()
+			mutate(t, desired)
+
+			if webhookUpToDate(desired, newStoredWebhook(t)) {
+				t.Fatalf("expected %s change to require an update", name)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"fmt"
+	"slices"
 
+	"k8s.io/apiserver/pkg/admission/plugin/namespace/lifecycle"
 	"k8s.io/apiserver/pkg/admission/plugin/policy/mutating"
 	"k8s.io/apiserver/pkg/admission/plugin/policy/validating"
 	webhookinit "k8s.io/apiserver/pkg/admission/plugin/webhook/initializer"
@@ -26,9 +28,35 @@ func applyAdmission(genericServerConfig *genericapiserver.RecommendedConfig,
 			nil, nil, genericServerConfig.LoopbackClientConfig, nil),
 		webhookutil.NewDefaultServiceResolver())
 
+	// ValidatingAdmissionPolicies are evaluated in process, before the validating webhooks as
+	// upstream. MutatingAdmissionPolicies are still alpha and not served. The kommodity.io
+	// API is defaulted right after the namespace checks, so that webhooks and policies see
+	// the defaulted objects. Provider credentials are filled in before the webhooks of the
+	// providers validate the objects, and registry mirrors and machine config patches are
+	// added to the Talos machine configurations before the bootstrap provider validates
+	// them. New KubevirtMachines are placed on an InfraCluster before the webhooks of
+	// the providers validate them. Deletions of protected Clusters are rejected before any webhook sees them.
+	// ResourceQuotas are enforced last, as upstream, so that objects rejected by any
+	// other plugin do not count.
 	admissionOpts := options.NewAdmissionOptions()
-	admissionOpts.EnablePlugins = []string{"NamespaceLifecycle", "MutatingAdmissionWebhook", "ValidatingAdmissionWebhook"}
-	admissionOpts.DisablePlugins = []string{validating.PluginName, mutating.PluginName}
+	admissionOpts.EnablePlugins = []string{"NamespaceLifecycle", defaultingPluginName,
+		providerCredentialsPluginName, registryMirrorsPluginName, machineConfigPatchesPluginName,
+		infraPlacementPluginName, deletionProtectionPluginName, "MutatingAdmissionWebhook", validating.PluginName,
+		"ValidatingAdmissionWebhook", resourceQuotaPluginName}
+	admissionOpts.DisablePlugins = []string{mutating.PluginName}
+	admissionOpts.RecommendedPluginOrder = slices.Insert(admissionOpts.RecommendedPluginOrder,
+		slices.Index(admissionOpts.RecommendedPluginOrder, lifecycle.PluginName)+1, defaultingPluginName,
+		providerCredentialsPluginName, registryMirrorsPluginName, machineConfigPatchesPluginName,
+		infraPlacementPluginName, deletionProtectionPluginName)
+	admissionOpts.RecommendedPluginOrder = append(admissionOpts.RecommendedPluginOrder, resourceQuotaPluginName)
+
+	registerDefaultingPlugin(admissionOpts.Plugins)
+	registerProviderCredentialsPlugin(admissionOpts.Plugins)
+	registerRegistryMirrorsPlugin(admissionOpts.Plugins)
+	registerMachineConfigPatchesPlugin(admissionOpts.Plugins)
+	registerInfraPlacementPlugin(admissionOpts.Plugins)
+	registerDeletionProtectionPlugin(admissionOpts.Plugins)
+	registerResourceQuotaPlugin(admissionOpts.Plugins)
 
 	err = admissionOpts.ApplyTo(&genericServerConfig.Config, genericServerConfig.SharedInformerFactory,
 		kubeClient, dynamicClient, genericServerConfig.FeatureGate, webhookInitializer)
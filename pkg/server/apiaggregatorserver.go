@@ -20,6 +20,7 @@ import (
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -27,6 +28,7 @@ import (
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/discovery"
 	restclientdynamic "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
@@ -34,6 +36,7 @@ import (
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	aggregatorapiserver "k8s.io/kube-aggregator/pkg/apiserver"
 	apiregistrationclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/typed/apiregistration/v1"
+	apiregistrationinformers "k8s.io/kube-aggregator/pkg/client/informers/externalversions/apiregistration/v1"
 	"k8s.io/kube-aggregator/pkg/controllers/autoregister"
 	controllersa "k8s.io/kubernetes/pkg/controller/serviceaccount"
 	"k8s.io/kubernetes/pkg/controlplane/controller/crdregistration"
@@ -41,49 +44,12 @@ import (
 )
 
 const (
-	defaultWaitTime = 500 * time.Millisecond
-	retryInterval   = 30 * time.Second
+	retryInterval = 30 * time.Second
 )
 
-type validatingResources struct {
-	providerGroupResources map[string][]string
-	providerGroups         []string
-}
-
-func (v *validatingResources) hasSameAPIGroups(apiGroupNames []string) bool {
-	return ContainsAll(apiGroupNames, v.providerGroups)
-}
-
-func (v *validatingResources) hasSameAPIGroupResources(apiGroupResources []*metav1.APIResourceList) bool {
-	apiGroupResourcesMap := make(map[string][]string)
-
-	for _, group := range apiGroupResources {
-		groupVersion, err := schema.ParseGroupVersion(group.GroupVersion)
-		if err != nil {
-			return false
-		}
-
-		resourceKinds := make([]string, len(group.APIResources))
-		for i, resource := range group.APIResources {
-			resourceKinds[i] = resource.Kind
-		}
-
-		apiGroupResourcesMap[groupVersion.Group] = append(apiGroupResourcesMap[groupVersion.Group], resourceKinds...)
-	}
-
-	for group, resources := range v.providerGroupResources {
-		apiGroupResources := apiGroupResourcesMap[group]
-
-		if !ContainsAll(apiGroupResources, resources) {
-			return false
-		}
-	}
-
-	return true
-}
-
 //nolint:funlen,cyclop
-func newAPIAggregatorServer(cfg *config.KommodityConfig,
+func newAPIAggregatorServer(ctx context.Context,
+	cfg *config.KommodityConfig,
 	genericServerConfig *genericapiserver.RecommendedConfig,
 	providerCache *provider.Cache,
 	scheme *runtime.Scheme,
@@ -91,7 +57,7 @@ func newAPIAggregatorServer(cfg *config.KommodityConfig,
 	delegationTarget genericapiserver.DelegationTarget,
 	crds apiextensionsinformers.CustomResourceDefinitionInformer,
 	signingKey *rsa.PrivateKey) (*aggregatorapiserver.APIAggregator, error) {
-	config, err := setupAPIAggregatorConfig(cfg, genericServerConfig, codecs)
+	config, err := setupAPIAggregatorConfig(ctx, cfg, genericServerConfig, codecs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup API aggregator config: %w", err)
 	}
@@ -138,33 +104,44 @@ func newAPIAggregatorServer(cfg *config.KommodityConfig,
 		return nil, fmt.Errorf("failed to add post start hook for auto-registration: %w", err)
 	}
 
-	err = aggregatorServer.GenericAPIServer.AddPostStartHook(
-		"bootstrap-required-resources", bootstrapRequiredResourcesHook(genericServerConfig))
+	// The hooks run concurrently; the report logs how long each of them took.
+	report := newStartupReport(logging.FromContext(ctx))
+
+	err = aggregatorServer.GenericAPIServer.AddPostStartHook("bootstrap-required-resources",
+		report.time("bootstrap-required-resources", bootstrapRequiredResourcesHook(genericServerConfig)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add post start hook for bootstrapping required resources: %w", err)
 	}
 
-	err = aggregatorServer.GenericAPIServer.AddPostStartHook(
-		"apply-crds", applyCRDsHook(cfg, genericServerConfig, providerCache, crds))
+	err = aggregatorServer.GenericAPIServer.AddPostStartHook("apply-crds",
+		report.time("apply-crds", applyCRDsHook(cfg, genericServerConfig, providerCache, crds)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add post start hook for applying CRDs: %w", err)
 	}
 
-	err = aggregatorServer.GenericAPIServer.AddPostStartHook(
-		"start-controller-managers", startControllerManagersHook(cfg, genericServerConfig, providerCache, scheme))
+	managers := &controllerManagers{}
+
+	err = aggregatorServer.GenericAPIServer.AddPostStartHook("start-controller-managers",
+		report.time("start-controller-managers", startControllerManagersHook(cfg, genericServerConfig, providerCache,
+			scheme, crds, apiServiceInformer, managers)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add post start hook for starting controller managers: %w", err)
 	}
 
-	err = aggregatorServer.GenericAPIServer.AddPostStartHook(
-		"start-token-controller", startTokenControllerHook(genericServerConfig, signingKey))
+	err = aggregatorServer.GenericAPIServer.AddPreShutdownHook("stop-controller-managers", managers.stop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add pre shutdown hook for stopping controller managers: %w", err)
+	}
+
+	err = aggregatorServer.GenericAPIServer.AddPostStartHook("start-token-controller",
+		report.time("start-token-controller", startTokenControllerHook(genericServerConfig, signingKey)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add post start hook for starting token controller: %w", err)
 	}
 
 	if signingKey != nil {
-		err = aggregatorServer.GenericAPIServer.AddPostStartHook(
-			"persist-signing-key", persistSigningKeyHook(genericServerConfig, signingKey))
+		err = aggregatorServer.GenericAPIServer.AddPostStartHook("persist-signing-key",
+			report.time("persist-signing-key", persistSigningKeyHook(genericServerConfig, signingKey)))
 		if err != nil {
 			return nil, fmt.Errorf("failed to add post start hook for persisting signing key: %w", err)
 		}
@@ -251,6 +228,8 @@ func applyCRDsHook(cfg *config.KommodityConfig,
 	providerCache *provider.Cache,
 	crds apiextensionsinformers.CustomResourceDefinitionInformer) genericapiserver.PostStartHookFunc {
 	return func(ctx genericapiserver.PostStartHookContext) error {
+		registerCRDReadinessCheck(ctx, crds, slices.Collect(maps.Keys(providerCache.GetProviderGroupResources())))
+
 		dynamicClient, err := restclientdynamic.NewForConfig(genericServerConfig.LoopbackClientConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create dynamic rest client: %w", err)
@@ -315,18 +294,20 @@ func applyCRDsHook(cfg *config.KommodityConfig,
 func startControllerManagersHook(cfg *config.KommodityConfig,
 	genericServerConfig *genericapiserver.RecommendedConfig,
 	providerCache *provider.Cache,
-	scheme *runtime.Scheme) genericapiserver.PostStartHookFunc {
+	scheme *runtime.Scheme,
+	crds apiextensionsinformers.CustomResourceDefinitionInformer,
+	apiServices apiregistrationinformers.APIServiceInformer,
+	managers *controllerManagers) genericapiserver.PostStartHookFunc {
 	return func(ctx genericapiserver.PostStartHookContext) error {
-		logger := logging.FromContext(ctx)
-
 		discoveryClient, err := discovery.NewDiscoveryClientForConfig(genericServerConfig.LoopbackClientConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create discovery client: %w", err)
 		}
 
-		err = waitForProviderCRDsAreEstablished(ctx, discoveryClient, providerCache)
+		err = waitForProviderCRDsAreServed(ctx, crds, apiServices, discoveryClient,
+			providerCache.GetProviderGroupResources())
 		if err != nil {
-			return fmt.Errorf("failed to waiting for provider CRDs are established: %w", err)
+			return fmt.Errorf("failed to wait for provider CRDs to be served: %w", err)
 		}
 
 		// Create kubernetes client for SigningKeyReconciler
@@ -361,17 +342,7 @@ func startControllerManagersHook(cfg *config.KommodityConfig,
 			return fmt.Errorf("failed to create controller manager: %w", err)
 		}
 
-		go func() {
-			runCtx, cancel := context.WithCancelCause(ctx)
-			defer cancel(nil)
-
-			err = ctlMgr.Start(runCtx)
-			if err != nil {
-				errorMsg := "failed to start controller manager:"
-				logger.Error(errorMsg, zap.Error(err))
-				cancel(fmt.Errorf("%s %w", errorMsg, err))
-			}
-		}()
+		managers.start(ctx, ctlMgr)
 
 		return nil
 	}
@@ -380,7 +351,7 @@ func startControllerManagersHook(cfg *config.KommodityConfig,
 //nolint:lll // Not possible to shorten the signature
 func startTokenControllerHook(genericServerConfig *genericapiserver.RecommendedConfig, signingKey *rsa.PrivateKey) genericapiserver.PostStartHookFunc {
 	return func(ctx genericapiserver.PostStartHookContext) error {
-		kubeClient, err := kubernetes.NewForConfig(genericServerConfig.LoopbackClientConfig)
+		kubeClient, err := kubernetes.NewForConfig(newProtobufClientConfig(genericServerConfig.LoopbackClientConfig))
 		if err != nil {
 			return fmt.Errorf("failed to create kubernetes client for tokens controller: %w", err)
 		}
@@ -396,9 +367,13 @@ func startTokenControllerHook(genericServerConfig *genericapiserver.RecommendedC
 			return fmt.Errorf("failed to build token generator: %w", err)
 		}
 
+		// The tokens controller only needs the service account token Secrets, so it gets
+		// its own informer instead of caching every Secret of the fleet.
+		tokenSecretsInformerFactory := newTokenSecretsInformerFactory(kubeClient)
+
 		tokenController, err := controllersa.NewTokensController(
 			genericServerConfig.SharedInformerFactory.Core().V1().ServiceAccounts(),
-			genericServerConfig.SharedInformerFactory.Core().V1().Secrets(),
+			tokenSecretsInformerFactory.Core().V1().Secrets(),
 			kubeClient,
 			controllersa.TokensControllerOptions{
 				ServiceAccountResync: retryInterval,
@@ -411,6 +386,7 @@ func startTokenControllerHook(genericServerConfig *genericapiserver.RecommendedC
 		}
 
 		genericServerConfig.SharedInformerFactory.Start(ctx.Done())
+		tokenSecretsInformerFactory.Start(ctx.Done())
 
 		go func() {
 			runCtx, cancel := context.WithCancelCause(ctx)
@@ -423,6 +399,16 @@ func startTokenControllerHook(genericServerConfig *genericapiserver.RecommendedC
 	}
 }
 
+// newTokenSecretsInformerFactory returns an informer factory whose Secrets informer only
+// lists and watches service account token Secrets.
+func newTokenSecretsInformerFactory(kubeClient kubernetes.Interface) informers.SharedInformerFactory {
+	return informers.NewSharedInformerFactoryWithOptions(kubeClient, defaultResyncPeriod*time.Minute,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector(
+				"type", string(corev1.SecretTypeServiceAccountToken)).String()
+		}))
+}
+
 // persistSigningKeyHook persists the in-memory signing key to a Kubernetes Secret.
 // This runs after the server is listening, so the loopback client can connect.
 func persistSigningKeyHook(
@@ -450,73 +436,6 @@ func persistSigningKeyHook(
 	}
 }
 
-func waitForProviderCRDsAreEstablished(ctx context.Context,
-	discoveryClient *discovery.DiscoveryClient,
-	providerCache *provider.Cache) error {
-	logger := logging.FromContext(ctx)
-
-	validator := validatingResources{
-		providerGroupResources: providerCache.GetProviderGroupResources(),
-		providerGroups:         slices.Collect(maps.Keys(providerCache.GetProviderGroupResources())),
-	}
-
-	logger.Info("Waiting for CRD discovery", zap.Strings("apiGroups", validator.providerGroups))
-
-	for {
-		apiResources, err := discoveryClient.ServerPreferredResources()
-		if err != nil {
-			return fmt.Errorf("failed to discover server groups: %w", err)
-		}
-
-		apiGroupNames, err := getAPIGroupNamesFromAPIResourceLists(apiResources)
-		if err != nil {
-			return fmt.Errorf("failed to get API group names from API resource lists: %w", err)
-		}
-
-		logger.Info("Discovered API groups", zap.Any("groups", apiGroupNames))
-
-		if validator.hasSameAPIGroups(apiGroupNames) && validator.hasSameAPIGroupResources(apiResources) {
-			logger.Info("All provider CRDs are available",
-				zap.Strings("expectedGroups", validator.providerGroups),
-				zap.Any("discoveredGroups", apiGroupNames))
-
-			break
-		}
-
-		logger.Info("Not all provider CRDs are available yet, waiting...",
-			zap.Int("expectedCount", len(validator.providerGroups)),
-			zap.Strings("expectedGroups", validator.providerGroups),
-			zap.Any("discoveredGroups", apiGroupNames))
-		time.Sleep(defaultWaitTime)
-	}
-
-	return nil
-}
-
-func getAPIGroupNamesFromAPIResourceLists(apiGroupResourcesList []*metav1.APIResourceList) ([]string, error) {
-	apiGroupNames := make([]string, 0)
-
-	for _, group := range apiGroupResourcesList {
-		if group == nil {
-			continue
-		}
-
-		groupVersion, err := schema.ParseGroupVersion(group.GroupVersion)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse group version %q: %w", group.GroupVersion, err)
-		}
-
-		if slices.Contains(apiGroupNames, groupVersion.Group) {
-			// We could have multiple versions for the same group, skip duplicates
-			continue
-		}
-
-		apiGroupNames = append(apiGroupNames, groupVersion.Group)
-	}
-
-	return apiGroupNames, nil
-}
-
 func registerAPIServicesAndVersions(delegationTarget genericapiserver.DelegationTarget,
 	discoveryManager discoveryendpoint.ResourceManager) []*apiregistrationv1.APIService {
 	apiVersionPriorities := defaultGenericAPIServicePriorities()
@@ -562,6 +481,7 @@ func registerAPIServicesAndVersions(delegationTarget genericapiserver.Delegation
 }
 
 func setupAPIAggregatorConfig(
+	ctx context.Context,
 	cfg *config.KommodityConfig,
 	genericServerConfig *genericapiserver.RecommendedConfig,
 	codecs serializer.CodecFactory) (*aggregatorapiserver.Config, error) {
@@ -573,21 +493,28 @@ func setupAPIAggregatorConfig(
 		return nil, fmt.Errorf("unable to create Kine legacy storage config: %w", err)
 	}
 
+	// The object counts of all servers are shared, the load shedding of the aggregator
+	// handler chain reads them.
+	kineStorageConfig.StorageObjectCountTracker = genericServerConfig.StorageObjectCountTracker
+
 	aggregatorGenericConfig := genericapiserver.NewRecommendedConfig(codecs)
 	aggregatorGenericConfig.SecureServing = genericServerConfig.SecureServing
 	aggregatorGenericConfig.Authentication = genericServerConfig.Authentication
 	aggregatorGenericConfig.Authorization = genericServerConfig.Authorization
 	aggregatorGenericConfig.LoopbackClientConfig = genericServerConfig.LoopbackClientConfig
 	aggregatorGenericConfig.EffectiveVersion = genericServerConfig.EffectiveVersion
+	aggregatorGenericConfig.EnableProfiling = genericServerConfig.EnableProfiling
+	aggregatorGenericConfig.ShutdownWatchTerminationGracePeriod = genericServerConfig.ShutdownWatchTerminationGracePeriod
 	aggregatorGenericConfig.OpenAPIV3Config = genericServerConfig.OpenAPIV3Config
 	aggregatorGenericConfig.EquivalentResourceRegistry = genericServerConfig.EquivalentResourceRegistry
-	aggregatorGenericConfig.RESTOptionsGetter = kine.NewKineRESTOptionsGetter(*kineStorageConfig)
+	aggregatorGenericConfig.RESTOptionsGetter = kine.NewKineRESTOptionsGetter(*kineStorageConfig, cfg.WatchCacheConfig)
 	aggregatorGenericConfig.AggregatedDiscoveryGroupManager = genericServerConfig.AggregatedDiscoveryGroupManager
 	aggregatorGenericConfig.MergedResourceConfig = genericServerConfig.MergedResourceConfig
-	aggregatorGenericConfig.BuildHandlerChainFunc = genericapiserver.BuildHandlerChainWithStorageVersionPrecondition
+	aggregatorGenericConfig.BuildHandlerChainFunc = newHandlerChainBuilder(ctx, cfg, codecs)
 	aggregatorGenericConfig.SharedInformerFactory = genericServerConfig.SharedInformerFactory
 	aggregatorGenericConfig.SkipOpenAPIInstallation = true
 	aggregatorGenericConfig.FeatureGate = genericServerConfig.FeatureGate
+	aggregatorGenericConfig.StorageObjectCountTracker = genericServerConfig.StorageObjectCountTracker
 
 	if genericServerConfig.AuditPolicyRuleEvaluator != nil {
 		aggregatorGenericConfig.AuditPolicyRuleEvaluator = genericServerConfig.AuditPolicyRuleEvaluator
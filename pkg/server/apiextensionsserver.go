@@ -7,6 +7,7 @@ import (
 	"github.com/kommodity-io/kommodity/pkg/kine"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	openapiv3controller "k8s.io/apiextensions-apiserver/pkg/controller/openapiv3"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -47,9 +48,37 @@ func newAPIExtensionServer(cfg *config.KommodityConfig,
 		return nil, fmt.Errorf("failed to build apiextensions (CRD) server: %w", err)
 	}
 
+	err = server.GenericAPIServer.AddPostStartHook("start-crd-openapiv3-controller",
+		startCRDOpenAPIV3ControllerHook(server))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add post start hook for the CRD OpenAPI v3 controller: %w", err)
+	}
+
 	return server, nil
 }
 
+// startCRDOpenAPIV3ControllerHook publishes the schemas of the CRDs in the OpenAPI v3
+// documents of their groups, which kubectl explain and client-side validation read.
+// The apiextensions server only runs its OpenAPI v3 controller next to the v2 one, and
+// Kommodity serves no OpenAPI v2, so the controller is started here instead. The
+// aggregator picks the documents up from the delegate like the built-in groups.
+func startCRDOpenAPIV3ControllerHook(
+	server *apiextensionsapiserver.CustomResourceDefinitions) genericapiserver.PostStartHookFunc {
+	return func(ctx genericapiserver.PostStartHookContext) error {
+		// Set when the server is prepared to run. Upstream already runs the controller
+		// whenever the v2 spec is served.
+		if server.GenericAPIServer.OpenAPIV3VersionedService == nil || server.GenericAPIServer.StaticOpenAPISpec != nil {
+			return nil
+		}
+
+		controller := openapiv3controller.NewController(server.Informers.Apiextensions().V1().CustomResourceDefinitions())
+
+		go controller.Run(server.GenericAPIServer.OpenAPIV3VersionedService, ctx.Done())
+
+		return nil
+	}
+}
+
 func setupAPIExtensionConfig(cfg *config.KommodityConfig,
 	genericServerConfig *genericapiserver.RecommendedConfig,
 	codecs serializer.CodecFactory) (*apiextensionsapiserver.Config, error) {
@@ -61,14 +90,16 @@ func setupAPIExtensionConfig(cfg *config.KommodityConfig,
 		return nil, fmt.Errorf("unable to create CRD Kine storage config: %w", err)
 	}
 
-	crdROG := kine.NewKineRESTOptionsGetter(*crdStorageCfg)
+	crdStorageCfg.StorageObjectCountTracker = genericServerConfig.StorageObjectCountTracker
+	crdROG := kine.NewKineRESTOptionsGetter(*crdStorageCfg, cfg.WatchCacheConfig)
 
 	crStorageCfg, err := kine.NewKineStorageConfig(cfg, unstructured.UnstructuredJSONScheme)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create CR Kine storage config: %w", err)
 	}
 
-	crROG := kine.NewKineRESTOptionsGetter(*crStorageCfg)
+	crStorageCfg.StorageObjectCountTracker = genericServerConfig.StorageObjectCountTracker
+	crROG := kine.NewKineRESTOptionsGetter(*crStorageCfg, cfg.WatchCacheConfig)
 
 	restOptionsGetter := dispatchingRESTOptionsGetter{crd: crdROG, cr: crROG}
 
@@ -79,6 +110,8 @@ func setupAPIExtensionConfig(cfg *config.KommodityConfig,
 	crdRecommended.Authorization = genericServerConfig.Authorization
 	crdRecommended.LoopbackClientConfig = genericServerConfig.LoopbackClientConfig
 	crdRecommended.EffectiveVersion = genericServerConfig.EffectiveVersion
+	crdRecommended.EnableProfiling = genericServerConfig.EnableProfiling
+	crdRecommended.ShutdownWatchTerminationGracePeriod = genericServerConfig.ShutdownWatchTerminationGracePeriod
 	crdRecommended.OpenAPIV3Config = genericServerConfig.OpenAPIV3Config
 	crdRecommended.EquivalentResourceRegistry = genericServerConfig.EquivalentResourceRegistry
 	crdRecommended.RESTOptionsGetter = restOptionsGetter
@@ -87,6 +120,7 @@ func setupAPIExtensionConfig(cfg *config.KommodityConfig,
 	crdRecommended.BuildHandlerChainFunc = genericapiserver.BuildHandlerChainWithStorageVersionPrecondition
 	crdRecommended.SharedInformerFactory = genericServerConfig.SharedInformerFactory
 	crdRecommended.AdmissionControl = genericServerConfig.AdmissionControl
+	crdRecommended.StorageObjectCountTracker = genericServerConfig.StorageObjectCountTracker
 
 	if genericServerConfig.AuditPolicyRuleEvaluator != nil {
 		crdRecommended.AuditPolicyRuleEvaluator = genericServerConfig.AuditPolicyRuleEvaluator
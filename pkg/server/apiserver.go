@@ -46,6 +46,12 @@ func setupAPIServerConfig(ctx context.Context,
 	genericServerConfig.EquivalentResourceRegistry = runtime.NewEquivalentResourceRegistry()
 	genericServerConfig.AggregatedDiscoveryGroupManager = aggregated.NewResourceManager("apis")
 	genericServerConfig.EffectiveVersion = componentbaseversion.DefaultBuildEffectiveVersion()
+	// Profiles are served under /debug/pprof, which the authorizer only allows admins.
+	genericServerConfig.EnableProfiling = cfg.EnablePprof
+	// The combined server drains its connections before the API server is stopped, so
+	// the API server stops right away. Watches are ended gracefully, which lets clients
+	// watching through the combined server see them close instead of being cut off.
+	genericServerConfig.ShutdownWatchTerminationGracePeriod = cfg.ShutdownConfig.Timeout
 	genericServerConfig.OpenAPIV3Config = genericapiserver.DefaultOpenAPIV3Config(
 		openAPISpec.GetOpenAPIDefinitions,
 		openapi.NewDefinitionNamer(scheme),
@@ -71,6 +77,12 @@ func setupAPIServerConfig(ctx context.Context,
 		return nil, nil, fmt.Errorf("failed to apply secure serving config: %w", err)
 	}
 
+	// The serving stack of the API server has no maximum TLS version.
+	if cfg.TLSConfig != nil {
+		genericServerConfig.SecureServing.MinTLSVersion = cfg.TLSConfig.MinVersion
+		genericServerConfig.SecureServing.CipherSuites = cfg.TLSConfig.CipherSuites
+	}
+
 	loopbackConfig, err := setupNewLoopbackClientConfig(
 		genericServerConfig.SecureServing, secureServing.ServerCert.CertKey)
 	if err != nil {
@@ -90,7 +102,7 @@ func setupAPIServerConfig(ctx context.Context,
 		return nil, nil, fmt.Errorf("failed to apply authentication/authorization config: %w", err)
 	}
 
-	kubeClient, err := clientgoclientset.NewForConfig(loopbackConfig)
+	kubeClient, err := clientgoclientset.NewForConfig(newProtobufClientConfig(loopbackConfig))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create kube client: %w", err)
 	}
@@ -133,3 +145,15 @@ func setupNewLoopbackClientConfig(secureServing *genericapiserver.SecureServingI
 
 	return loopbackConfig, nil
 }
+
+// newProtobufClientConfig returns a copy of the config for clients of built-in types
+// only, which talk protobuf like the loopback clients of the kube-apiserver. The lists
+// of the informers are several times smaller than in JSON. Responses protobuf cannot
+// encode are still accepted in JSON.
+func newProtobufClientConfig(config *restclient.Config) *restclient.Config {
+	protobufConfig := restclient.CopyConfig(config)
+	protobufConfig.ContentType = runtime.ContentTypeProtobuf
+	protobufConfig.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+
+	return protobufConfig
+}
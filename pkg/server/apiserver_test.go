@@ -0,0 +1,106 @@
+//nolint:testpackage // white-box tests exercise the unexported API server setup
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/apis/audit"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestServedKindsEncodeToProtobuf(t *testing.T) {
+	t.Parallel()
+
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	codecs := serializer.NewCodecFactory(scheme)
+
+	info, found := runtime.SerializerInfoForMediaType(codecs.SupportedMediaTypes(), runtime.ContentTypeProtobuf)
+	if !found {
+		t.Fatalf("expected the API server to support %s", runtime.ContentTypeProtobuf)
+	}
+
+	for _, groupVersion := range getSupportedGroupKindVersions() {
+		// The audit policy is read from a file, it is not served.
+		if groupVersion == audit.SchemeGroupVersion {
+			continue
+		}
+
+		for kind := range scheme.KnownTypes(groupVersion) {
+			obj, err := scheme.New(groupVersion.WithKind(kind))
+			if err != nil {
+				t.Fatalf("failed to create %s %s: %v", groupVersion, kind, err)
+			}
+
+			var buf bytes.Buffer
+
+			err = codecs.EncoderForVersion(info.Serializer, groupVersion).Encode(obj, &buf)
+			if err != nil {
+				t.Fatalf("expected %s %s to encode to protobuf: %v", groupVersion, kind, err)
+			}
+		}
+	}
+}
+
+func TestProtobufRoundTripOfInternalAliases(t *testing.T) {
+	t.Parallel()
+
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	codecs := serializer.NewCodecFactory(scheme)
+	info, _ := runtime.SerializerInfoForMediaType(codecs.SupportedMediaTypes(), runtime.ContentTypeProtobuf)
+
+	secrets := &corev1.SecretList{Items: []corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: metav1.NamespaceDefault},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data:       map[string][]byte{"token": []byte("jwt")},
+	}}}
+
+	var buf bytes.Buffer
+
+	err = codecs.EncoderForVersion(info.Serializer, corev1.SchemeGroupVersion).Encode(secrets, &buf)
+	if err != nil {
+		t.Fatalf("failed to encode Secrets to protobuf: %v", err)
+	}
+
+	decoded, _, err := codecs.UniversalDecoder(corev1.SchemeGroupVersion).Decode(buf.Bytes(), nil, nil)
+	if err != nil {
+		t.Fatalf("failed to decode Secrets from protobuf: %v", err)
+	}
+
+	list, isList := decoded.(*corev1.SecretList)
+	if !isList || len(list.Items) != 1 || string(list.Items[0].Data["token"]) != "jwt" {
+		t.Fatalf("expected the Secrets to survive the round trip, got %#v", decoded)
+	}
+}
+
+func TestNewProtobufClientConfig(t *testing.T) {
+	t.Parallel()
+
+	loopbackConfig := &restclient.Config{Host: "https://127.0.0.1:8443", BearerToken: "token"}
+
+	protobufConfig := newProtobufClientConfig(loopbackConfig)
+
+	if protobufConfig.ContentType != runtime.ContentTypeProtobuf {
+		t.Fatalf("expected requests in protobuf, got %q", protobufConfig.ContentType)
+	}
+
+	if protobufConfig.AcceptContentTypes != runtime.ContentTypeProtobuf+","+runtime.ContentTypeJSON {
+		t.Fatalf("expected protobuf responses with a JSON fallback, got %q", protobufConfig.AcceptContentTypes)
+	}
+
+	if loopbackConfig.ContentType != "" || protobufConfig.BearerToken != loopbackConfig.BearerToken {
+		t.Fatalf("expected a copy of the loopback config, got %+v", protobufConfig)
+	}
+}
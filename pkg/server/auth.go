@@ -9,18 +9,23 @@ import (
 	"strings"
 
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
 	"github.com/kommodity-io/kommodity/pkg/storage/selfsubjectaccessreviews"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apiserver/pkg/apis/apiserver"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	bearertoken "k8s.io/apiserver/pkg/authentication/request/bearertoken"
 	authunion "k8s.io/apiserver/pkg/authentication/request/union"
 	"k8s.io/apiserver/pkg/authentication/user"
 	auth "k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	authorizationcel "k8s.io/apiserver/pkg/authorization/cel"
+	authzunion "k8s.io/apiserver/pkg/authorization/union"
 	genericapiserver "k8s.io/apiserver/pkg/server"
-	oidc "k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+	"k8s.io/apiserver/pkg/server/options"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/apiserver/plugin/pkg/authorizer/webhook"
+	webhookmetrics "k8s.io/apiserver/plugin/pkg/authorizer/webhook/metrics"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
@@ -30,6 +35,11 @@ import (
 const (
 	systemPrivilegedGroup      = "system:masters"
 	systemServiceAccountsGroup = "system:serviceaccounts"
+	authzWebhookVersion        = "v1"
+	authzWebhookName           = "webhook"
+	// debugPathPrefix holds the profiles, runtime variables and log levels of Kommodity,
+	// which are only served to admins.
+	debugPathPrefix = "/debug/"
 )
 
 // healthPaths returns endpoints that must be accessible without authentication
@@ -106,6 +116,9 @@ func (anonymousReqAuth) AuthenticateRequest(_ *http.Request) (*authenticator.Res
 
 type adminAuthorizer struct {
 	cfg *config.KommodityConfig
+	// delegate leaves requests of users outside the admin groups to the next
+	// authorizer instead of denying them.
+	delegate bool
 }
 
 //nolint:cyclop // Function complexity is acceptable for this authorizer.
@@ -144,20 +157,56 @@ func (a adminAuthorizer) Authorize(_ context.Context, attrs auth.Attributes) (au
 	}
 
 	// Allow authenticated ServiceAccounts (e.g., cluster autoscaler)
-	if slices.Contains(user.GetGroups(), systemServiceAccountsGroup) {
+	if slices.Contains(user.GetGroups(), systemServiceAccountsGroup) && !isDebugRequest(attrs) {
 		return auth.DecisionAllow, "allowed: user is an authenticated service account", nil
 	}
 
+	if a.delegate {
+		return auth.DecisionNoOpinion, "user is not in admin group, system:masters group, or a service account", nil
+	}
+
 	return auth.DecisionDeny, "forbidden: user is not in admin group, system:masters group, or a service account", nil
 }
 
-// NewSelfSubjectAccessReviewREST creates a new REST storage for SelfSubjectAccessReview.
-func NewSelfSubjectAccessReviewREST(cfg *config.KommodityConfig) *selfsubjectaccessreviews.SelfSubjectAccessReviewREST {
+// isDebugRequest reports whether the request is for the debug endpoints of Kommodity.
+func isDebugRequest(attrs auth.Attributes) bool {
+	return !attrs.IsResourceRequest() && strings.HasPrefix(attrs.GetPath(), debugPathPrefix)
+}
+
+// NewSelfSubjectAccessReviewREST creates a new REST storage for SelfSubjectAccessReview
+// that answers with the authorizer of the API server.
+func NewSelfSubjectAccessReviewREST(authorizer auth.Authorizer) *selfsubjectaccessreviews.SelfSubjectAccessReviewREST {
 	return &selfsubjectaccessreviews.SelfSubjectAccessReviewREST{
-		Authorizer: adminAuthorizer{cfg: cfg},
+		Authorizer: authorizer,
 	}
 }
 
+// newAuthorizer returns the authorizer of the API server. Requests the admin group
+// check does not allow are sent to the authorization webhook when one is configured.
+func newAuthorizer(cfg *config.KommodityConfig) (auth.Authorizer, error) {
+	webhookConfig := cfg.AuthConfig.AuthzWebhookConfig
+	if webhookConfig == nil {
+		return adminAuthorizer{cfg: cfg}, nil
+	}
+
+	clientConfig, err := webhookutil.LoadKubeconfig(webhookConfig.KubeconfigFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load authorization webhook kubeconfig %s: %w",
+			webhookConfig.KubeconfigFile, err)
+	}
+
+	// The values mirror the defaults of --authorization-webhook-* in kube-apiserver.
+	webhookAuthorizer, err := webhook.New(clientConfig, authzWebhookVersion,
+		webhookConfig.AuthorizedTTL, webhookConfig.UnauthorizedTTL,
+		*options.DefaultAuthWebhookRetryBackoff(), auth.DecisionNoOpinion, nil, authzWebhookName,
+		webhookmetrics.NoopAuthorizerMetrics{}, authorizationcel.NewDefaultCompiler())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authorization webhook: %w", err)
+	}
+
+	return authzunion.New(adminAuthorizer{cfg: cfg, delegate: true}, webhookAuthorizer), nil
+}
+
 //nolint:funlen
 func applyAuth(ctx context.Context, cfg *config.KommodityConfig,
 	config *genericapiserver.RecommendedConfig, signingKey *rsa.PrivateKey) error {
@@ -176,57 +225,48 @@ func applyAuth(ctx context.Context, cfg *config.KommodityConfig,
 
 	bearerSA := bearertoken.New(saAuthenticator)
 
-	// Build list of authenticators - ServiceAccount first, then OIDC if configured
-	authenticators := []authenticator.Request{bearerSA}
+	// Build list of authenticators - client certificates, ServiceAccount and bootstrap tokens, then OIDC if configured
+	authenticators := []authenticator.Request{}
 
-	oidcConfig := cfg.AuthConfig.OIDCConfig
-	if oidcConfig != nil {
-		prefix := ""
-
-		jwtAuthenticator := apiserver.JWTAuthenticator{
-			Issuer: apiserver.Issuer{
-				URL:       oidcConfig.IssuerURL,
-				Audiences: []string{oidcConfig.ClientID},
-			},
-			ClaimMappings: apiserver.ClaimMappings{
-				Username: apiserver.PrefixedClaimOrExpression{
-					Claim:  oidcConfig.UsernameClaim,
-					Prefix: &prefix,
-				},
-				Groups: apiserver.PrefixedClaimOrExpression{
-					Claim:  oidcConfig.GroupsClaim,
-					Prefix: &prefix,
-				},
-			},
-			ClaimValidationRules: []apiserver.ClaimValidationRule{
-				{
-					Claim:         "aud",
-					RequiredValue: oidcConfig.ClientID,
-				},
-			},
+	// Client certificates are verified by the combined server, which passes the
+	// identity on through its proxy.
+	if cfg.AuthConfig.ClientCAFile != "" {
+		frontProxyAuthenticator, err := setupFrontProxyAuth(cfg, config)
+		if err != nil {
+			return fmt.Errorf("failed to setup front proxy authenticator: %w", err)
 		}
 
-		oidcAuth, err := oidc.New(ctx, oidc.Options{
-			JWTAuthenticator:     jwtAuthenticator,
-			SupportedSigningAlgs: []string{"RS256"},
-		})
+		authenticators = append(authenticators, frontProxyAuthenticator)
+	}
+
+	authenticators = append(authenticators, bearerSA)
+
+	// Join tokens handed out to machines after attestation are bootstrap tokens.
+	authenticators = append(authenticators, bearertoken.New(httpauth.NewBootstrapTokenAuthenticator(cfg)))
+
+	oidcConfig := cfg.AuthConfig.OIDCConfig
+	if oidcConfig != nil {
+		oidcAuth, err := httpauth.NewOIDCAuthenticator(ctx, oidcConfig)
 		if err != nil {
-			return fmt.Errorf("failed to setup oidc authenticator: %w", err)
+			return fmt.Errorf("failed to create OIDC authenticator: %w", err)
 		}
 
 		bearerOIDC := bearertoken.New(oidcAuth)
 		authenticators = append(authenticators, bearerOIDC)
 
-		config.Authentication.APIAudiences = authenticator.Audiences(jwtAuthenticator.Issuer.Audiences)
+		config.Authentication.APIAudiences = authenticator.Audiences{oidcConfig.ClientID}
 	}
 
 	// Always add anonymous authenticator as fallback
 	authenticators = append(authenticators, anonymousReqAuth{})
 
-	config.Authorization.Authorizer = &adminAuthorizer{
-		cfg: cfg,
+	authorizer, err := newAuthorizer(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to setup authorizer: %w", err)
 	}
 
+	config.Authorization.Authorizer = authorizer
+
 	config.Authentication.Authenticator = authunion.New(authenticators...)
 
 	return nil
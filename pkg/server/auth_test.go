@@ -0,0 +1,178 @@
+//nolint:testpackage // white-box tests exercise the unexported authorizer setup
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	auth "k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testAdminGroup = "kommodity-admins"
+
+// newAuthzWebhook serves SubjectAccessReviews, allowing the given users only, and
+// returns a kubeconfig pointing at it together with the number of reviews served.
+func newAuthzWebhook(t *testing.T, allowedUsers ...string) (string, *atomic.Int32) {
+	t.Helper()
+
+	var reviews atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		reviews.Add(1)
+
+		var review authorizationv1.SubjectAccessReview
+
+		err := json.NewDecoder(request.Body).Decode(&review)
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		for _, allowed := range allowedUsers {
+			review.Status.Allowed = review.Status.Allowed || review.Spec.User == allowed
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(response).Encode(review)
+	}))
+	t.Cleanup(server.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "authz-webhook.yaml")
+
+	err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: authorizer
+  cluster:
+    server: `+server.URL+`
+users:
+- name: kommodity
+contexts:
+- name: webhook
+  context:
+    cluster: authorizer
+    user: kommodity
+current-context: webhook
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	return kubeconfig, &reviews
+}
+
+func authorize(t *testing.T, authorizer auth.Authorizer, name string, groups ...string) auth.Decision {
+	t.Helper()
+
+	decision, _, err := authorizer.Authorize(t.Context(), auth.AttributesRecord{
+		User:            &user.DefaultInfo{Name: name, Groups: groups},
+		Verb:            "get",
+		Resource:        "secrets",
+		Namespace:       "default",
+		ResourceRequest: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to authorize %s: %v", name, err)
+	}
+
+	return decision
+}
+
+func TestNewAuthorizerWithWebhook(t *testing.T) {
+	t.Parallel()
+
+	kubeconfig, reviews := newAuthzWebhook(t, "alice")
+
+	authorizer, err := newAuthorizer(&config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{
+			Apply:      true,
+			AdminGroup: testAdminGroup,
+			AuthzWebhookConfig: &config.AuthzWebhookConfig{
+				KubeconfigFile:  kubeconfig,
+				AuthorizedTTL:   time.Minute,
+				UnauthorizedTTL: time.Minute,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create authorizer: %v", err)
+	}
+
+	if decision := authorize(t, authorizer, "admin", testAdminGroup); decision != auth.DecisionAllow {
+		t.Fatalf("expected admin group member to be allowed, got %v", decision)
+	}
+
+	if reviews.Load() != 0 {
+		t.Fatalf("expected admin group member to be allowed without asking the webhook")
+	}
+
+	for range 2 {
+		if decision := authorize(t, authorizer, "alice"); decision != auth.DecisionAllow {
+			t.Fatalf("expected webhook to allow alice, got %v", decision)
+		}
+
+		if decision := authorize(t, authorizer, "bob"); decision == auth.DecisionAllow {
+			t.Fatalf("expected webhook to deny bob")
+		}
+	}
+
+	if got := reviews.Load(); got != 2 {
+		t.Fatalf("expected webhook decisions to be cached, got %d reviews", got)
+	}
+}
+
+func TestNewAuthorizerWithoutWebhook(t *testing.T) {
+	t.Parallel()
+
+	authorizer, err := newAuthorizer(&config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{Apply: true, AdminGroup: testAdminGroup},
+	})
+	if err != nil {
+		t.Fatalf("failed to create authorizer: %v", err)
+	}
+
+	if decision := authorize(t, authorizer, "alice"); decision != auth.DecisionDeny {
+		t.Fatalf("expected users outside the admin group to be denied, got %v", decision)
+	}
+}
+
+func TestAdminAuthorizerDebugPaths(t *testing.T) {
+	t.Parallel()
+
+	authorizer := adminAuthorizer{cfg: &config.KommodityConfig{
+		AuthConfig: &config.AuthConfig{Apply: true, AdminGroup: testAdminGroup},
+	}}
+
+	debugRequest := func(groups ...string) auth.Decision {
+		decision, _, _ := authorizer.Authorize(t.Context(), auth.AttributesRecord{
+			User: &user.DefaultInfo{Name: "caller", Groups: groups},
+			Verb: "get",
+			Path: "/debug/pprof/goroutine",
+		})
+
+		return decision
+	}
+
+	if decision := debugRequest(testAdminGroup); decision != auth.DecisionAllow {
+		t.Fatalf("expected admins to be allowed debug endpoints, got %v", decision)
+	}
+
+	if decision := debugRequest(systemServiceAccountsGroup); decision != auth.DecisionDeny {
+		t.Fatalf("expected service accounts to be denied debug endpoints, got %v", decision)
+	}
+
+	if decision := authorize(t, authorizer, "system:serviceaccount:default:autoscaler",
+		systemServiceAccountsGroup); decision != auth.DecisionAllow {
+		t.Fatalf("expected service accounts to keep access to resources, got %v", decision)
+	}
+}
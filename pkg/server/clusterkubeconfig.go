@@ -0,0 +1,348 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// clusterKubeconfigPattern is the mux pattern of the kubeconfig endpoint.
+	clusterKubeconfigPattern = "/apis/kommodity.io/v1alpha1/namespaces/{namespace}/clusters/{name}/kubeconfig"
+	// clusterKubeconfigSubresource is the Cluster subresource users need the get verb on.
+	clusterKubeconfigSubresource = "kubeconfig"
+	// selfSubjectReviewsPath is the path the caller's identity is looked up at.
+	selfSubjectReviewsPath = "/apis/authentication.k8s.io/v1/selfsubjectreviews"
+	// defaultKubeconfigExpiry is the lifetime of kubeconfigs requested without an expiry.
+	defaultKubeconfigExpiry = time.Hour
+	// maxKubeconfigExpiry bounds the lifetime of issued kubeconfigs.
+	maxKubeconfigExpiry = 24 * time.Hour
+	// kubeconfigCertBackdate tolerates clock skew between Kommodity and the workload cluster.
+	kubeconfigCertBackdate = 5 * time.Minute
+	// kubeconfigIssuedEventReason is the reason of the Event recorded on the Cluster.
+	kubeconfigIssuedEventReason = "KubeconfigIssued"
+	// kubeconfigAuditComponent is the source of the kubeconfig Events.
+	kubeconfigAuditComponent = "kommodity-kubeconfig-audit"
+	// systemPrefix marks the users and groups reserved by Kubernetes.
+	systemPrefix = "system:"
+)
+
+// kubeconfigIdentity is the user and groups a kubeconfig authenticates as.
+type kubeconfigIdentity struct {
+	Username string
+	Groups   []string
+}
+
+// clusterKubeconfigIssuer returns a kubeconfig of the workload cluster for the identity,
+// valid until notAfter.
+type clusterKubeconfigIssuer func(ctx context.Context, cluster types.NamespacedName,
+	identity kubeconfigIdentity, notAfter time.Time) ([]byte, error)
+
+// clusterSubresourceHandler serves the clusters/{name}/kubeconfig and clusters/{name}/pause
+// endpoints. Like the machine subresources, it talks to the API server with the caller's
+// credentials, so authentication, authorization and auditing apply. The kubeconfig it
+// returns authenticates with a short-lived client certificate for the caller's name and
+// groups, so the RBAC of the workload cluster decides what the caller may do there.
+type clusterSubresourceHandler struct {
+	*machineSubresourceHandler
+
+	issue    clusterKubeconfigIssuer
+	recorder record.EventRecorder
+}
+
+func newClusterSubresourceHandler(target *url.URL, transport http.RoundTripper,
+	issue clusterKubeconfigIssuer, recorder record.EventRecorder) *clusterSubresourceHandler {
+	return &clusterSubresourceHandler{
+		machineSubresourceHandler: newMachineSubresourceHandler(target, transport, nil),
+		issue:                     issue,
+		recorder:                  recorder,
+	}
+}
+
+// register adds the cluster subresource routes to the mux.
+func (h *clusterSubresourceHandler) register(mux *http.ServeMux) {
+	mux.HandleFunc(http.MethodGet+" "+clusterKubeconfigPattern, h.getKubeconfig)
+	mux.HandleFunc(http.MethodGet+" "+clusterPausePattern, h.getPause)
+	mux.HandleFunc(http.MethodPost+" "+clusterPausePattern, h.postPause)
+	mux.HandleFunc(http.MethodDelete+" "+clusterPausePattern, h.deletePause)
+}
+
+// getKubeconfig returns a kubeconfig of the Cluster for the caller. The caller needs
+// the get verb on the clusters/kubeconfig subresource.
+func (h *clusterSubresourceHandler) getKubeconfig(w http.ResponseWriter, r *http.Request) {
+	expiry, err := kubeconfigExpiryFromQuery(r)
+	if err != nil {
+		writeStatusError(w, apierrors.NewBadRequest(err.Error()))
+
+		return
+	}
+
+	if !h.reviewAccess(w, r, "clusters", clusterKubeconfigSubresource) {
+		return
+	}
+
+	identity, ok := h.reviewIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	cluster := types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+	notAfter := time.Now().Add(expiry).Truncate(time.Second)
+	logger := logging.FromContext(r.Context())
+
+	content, err := h.issue(r.Context(), cluster, identity, notAfter)
+	if apierrors.IsNotFound(err) {
+		writeStatusError(w, apierrors.NewNotFound(schema.GroupResource{
+			Group:    clusterv1.GroupVersion.Group,
+			Resource: "clusters/" + clusterKubeconfigSubresource,
+		}, cluster.Name))
+
+		return
+	}
+
+	if err != nil {
+		logger.Error("Failed to issue kubeconfig", zap.String("cluster", cluster.String()), zap.Error(err))
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return
+	}
+
+	logger.Info("Kubeconfig issued",
+		zap.String("namespace", cluster.Namespace),
+		zap.String("cluster", cluster.Name),
+		zap.String("user", identity.Username),
+		zap.Strings("groups", identity.Groups),
+		zap.Time("expiresAt", notAfter),
+		zap.Strings("sourceIPs", sourceIPs(r)),
+		zap.String("userAgent", r.UserAgent()))
+
+	if h.recorder != nil {
+		h.recorder.Eventf(&corev1.ObjectReference{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+			Namespace:  cluster.Namespace,
+			Name:       cluster.Name,
+		}, corev1.EventTypeNormal, kubeconfigIssuedEventReason, "Kubeconfig issued to %s, valid until %s",
+			identity.Username, notAfter.UTC().Format(time.RFC3339))
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(content)
+}
+
+// kubeconfigExpiryFromQuery reads the lifetime of the kubeconfig from the expiry
+// query parameter, as a duration like 30m or 8h.
+func kubeconfigExpiryFromQuery(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get("expiry")
+	if value == "" {
+		return defaultKubeconfigExpiry, nil
+	}
+
+	expiry, err := time.ParseDuration(value)
+	if err != nil || expiry <= 0 || expiry > maxKubeconfigExpiry {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidKubeconfigExpiry, value)
+	}
+
+	return expiry, nil
+}
+
+// reviewIdentity asks the API server, with the caller's credentials, who the caller
+// is. Groups reserved by Kubernetes are dropped, and system users are refused, so that
+// a kubeconfig never grants more in the workload cluster than the caller's own bindings.
+func (h *clusterSubresourceHandler) reviewIdentity(w http.ResponseWriter,
+	r *http.Request) (kubeconfigIdentity, bool) {
+	body, err := json.Marshal(&authenticationv1.SelfSubjectReview{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "SelfSubjectReview",
+			APIVersion: authenticationv1.SchemeGroupVersion.String(),
+		},
+	})
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return kubeconfigIdentity{}, false
+	}
+
+	respBody, ok := h.do(w, r, http.MethodPost, h.target.JoinPath(selfSubjectReviewsPath).String(),
+		"application/json", body, http.StatusCreated)
+	if !ok {
+		return kubeconfigIdentity{}, false
+	}
+
+	review := &authenticationv1.SelfSubjectReview{}
+
+	err = json.Unmarshal(respBody, review)
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return kubeconfigIdentity{}, false
+	}
+
+	userInfo := review.Status.UserInfo
+	if userInfo.Username == "" || strings.HasPrefix(userInfo.Username, systemPrefix) {
+		writeStatusError(w, apierrors.NewForbidden(schema.GroupResource{
+			Group:    clusterv1.GroupVersion.Group,
+			Resource: "clusters/" + clusterKubeconfigSubresource,
+		}, r.PathValue("name"), ErrKubeconfigForSystemUser))
+
+		return kubeconfigIdentity{}, false
+	}
+
+	return kubeconfigIdentity{
+		Username: userInfo.Username,
+		Groups: slices.DeleteFunc(slices.Clone(userInfo.Groups), func(group string) bool {
+			return strings.HasPrefix(group, systemPrefix)
+		}),
+	}, true
+}
+
+// newClusterCAKubeconfigIssuer signs client certificates with the CA of the Cluster,
+// which its API server trusts, and points them at the endpoint of the kubeconfig
+// secret maintained by the control plane provider.
+func newClusterCAKubeconfigIssuer(loopback *rest.Config) clusterKubeconfigIssuer {
+	return func(ctx context.Context, cluster types.NamespacedName,
+		identity kubeconfigIdentity, notAfter time.Time) ([]byte, error) {
+		scheme := runtime.NewScheme()
+
+		err := clientgoscheme.AddToScheme(scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build scheme: %w", err)
+		}
+
+		kubeClient, err := client.New(loopback, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+		adminKubeconfig, err := kubeconfig.FromSecret(ctx, kubeClient, cluster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kubeconfig of cluster %s: %w", cluster, err)
+		}
+
+		adminConfig, err := clientcmd.Load(adminKubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig of cluster %s: %w", cluster, err)
+		}
+
+		currentContext, found := adminConfig.Contexts[adminConfig.CurrentContext]
+		if !found || adminConfig.Clusters[currentContext.Cluster] == nil {
+			return nil, fmt.Errorf("%w: %s", ErrKubeconfigClusterNotFound, cluster)
+		}
+
+		caSecret, err := secret.Get(ctx, kubeClient, cluster, secret.ClusterCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CA of cluster %s: %w", cluster, err)
+		}
+
+		caCert, err := certs.DecodeCertPEM(caSecret.Data[secret.TLSCrtDataName])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode CA certificate of cluster %s: %w", cluster, err)
+		}
+
+		caKey, err := certs.DecodePrivateKeyPEM(caSecret.Data[secret.TLSKeyDataName])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode CA key of cluster %s: %w", cluster, err)
+		}
+
+		return mintKubeconfig(cluster.Name, adminConfig.Clusters[currentContext.Cluster],
+			caCert, caKey, identity, notAfter)
+	}
+}
+
+// mintKubeconfig returns a kubeconfig for the API server with a new client certificate
+// for the identity, signed by the CA and valid until notAfter.
+func mintKubeconfig(clusterName string, apiServer *api.Cluster, caCert *x509.Certificate,
+	caKey crypto.Signer, identity kubeconfigIdentity, notAfter time.Time) ([]byte, error) {
+	key, err := generateRSAPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client key: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), webhookCertSerialBits))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   identity.Username,
+			Organization: identity.Groups,
+		},
+		NotBefore:   time.Now().Add(-kubeconfigCertBackdate),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign client certificate: %w", err)
+	}
+
+	clientCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+	}
+
+	contextName := identity.Username + "@" + clusterName
+
+	content, err := clientcmd.Write(api.Config{
+		Clusters: map[string]*api.Cluster{
+			clusterName: {
+				Server:                   apiServer.Server,
+				TLSServerName:            apiServer.TLSServerName,
+				CertificateAuthorityData: apiServer.CertificateAuthorityData,
+			},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			contextName: {
+				ClientCertificateData: certs.EncodeCertPEM(clientCert),
+				ClientKeyData:         certs.EncodePrivateKeyPEM(key),
+			},
+		},
+		Contexts: map[string]*api.Context{
+			contextName: {
+				Cluster:  clusterName,
+				AuthInfo: contextName,
+			},
+		},
+		CurrentContext: contextName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,269 @@
+//nolint:testpackage // white-box tests exercise the unexported cluster kubeconfig handler
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/cluster-api/util/certs"
+)
+
+const testClusterKubeconfigPath = "/apis/kommodity.io/v1alpha1/namespaces/default/clusters/prod/kubeconfig"
+
+// newFakeKubeconfigAPIServer answers access reviews with allowed and identity
+// reviews with the user.
+func newFakeKubeconfigAPIServer(t *testing.T, allowed bool, userInfo authenticationv1.UserInfo,
+	reviews *[]authorizationv1.SelfSubjectAccessReview) *url.URL {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer caller-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case selfSubjectAccessReviewsPath:
+			review := authorizationv1.SelfSubjectAccessReview{}
+			_ = json.NewDecoder(r.Body).Decode(&review)
+			*reviews = append(*reviews, review)
+
+			review.Status.Allowed = allowed
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&review)
+		case selfSubjectReviewsPath:
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&authenticationv1.SelfSubjectReview{
+				Status: authenticationv1.SelfSubjectReviewStatus{UserInfo: userInfo},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse upstream URL: %v", err)
+	}
+
+	return target
+}
+
+type issuedKubeconfig struct {
+	cluster  types.NamespacedName
+	identity kubeconfigIdentity
+	notAfter time.Time
+}
+
+func serveClusterKubeconfig(t *testing.T, target *url.URL, query string, issued *issuedKubeconfig,
+	issueErr error) *httptest.ResponseRecorder {
+	t.Helper()
+
+	issue := func(_ context.Context, cluster types.NamespacedName,
+		identity kubeconfigIdentity, notAfter time.Time) ([]byte, error) {
+		*issued = issuedKubeconfig{cluster: cluster, identity: identity, notAfter: notAfter}
+
+		return []byte("kind: Config\n"), issueErr
+	}
+
+	mux := http.NewServeMux()
+	newClusterSubresourceHandler(target, http.DefaultTransport, issue, nil).register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, testClusterKubeconfigPath+query, nil)
+	req.Header.Set("Authorization", "Bearer caller-token")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestClusterKubeconfigIssuesForCaller(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeKubeconfigAPIServer(t, true, authenticationv1.UserInfo{
+		Username: "jane@example.com",
+		Groups:   []string{"platform", "system:masters", "system:authenticated"},
+	}, &reviews)
+
+	issued := issuedKubeconfig{}
+	start := time.Now()
+
+	recorder := serveClusterKubeconfig(t, target, "?expiry=30m", &issued, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder.Body.String() != "kind: Config\n" || recorder.Header().Get("Content-Type") != "application/yaml" {
+		t.Fatalf("unexpected kubeconfig %q", recorder.Body.String())
+	}
+
+	if issued.cluster != (types.NamespacedName{Namespace: "default", Name: "prod"}) ||
+		issued.identity.Username != "jane@example.com" || !slices.Equal(issued.identity.Groups, []string{"platform"}) {
+		t.Fatalf("unexpected issued kubeconfig %+v", issued)
+	}
+
+	if issued.notAfter.Before(start.Add(29*time.Minute)) || issued.notAfter.After(start.Add(31*time.Minute)) {
+		t.Fatalf("expected kubeconfig to expire in 30m, got %s", issued.notAfter)
+	}
+
+	if len(reviews) != 1 {
+		t.Fatalf("expected one access review, got %d", len(reviews))
+	}
+
+	attributes := reviews[0].Spec.ResourceAttributes
+	if attributes == nil || attributes.Resource != "clusters" ||
+		attributes.Subresource != clusterKubeconfigSubresource || attributes.Name != "prod" {
+		t.Fatalf("unexpected access review %+v", attributes)
+	}
+}
+
+func TestClusterKubeconfigRequiresAccess(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeKubeconfigAPIServer(t, false, authenticationv1.UserInfo{Username: "jane"}, &reviews)
+
+	issued := issuedKubeconfig{}
+
+	recorder := serveClusterKubeconfig(t, target, "", &issued, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if issued.identity.Username != "" {
+		t.Fatalf("expected no kubeconfig to be issued, got %+v", issued)
+	}
+}
+
+func TestClusterKubeconfigRefusesSystemUsers(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeKubeconfigAPIServer(t, true, authenticationv1.UserInfo{
+		Username: "system:serviceaccount:default:ci",
+	}, &reviews)
+
+	issued := issuedKubeconfig{}
+
+	recorder := serveClusterKubeconfig(t, target, "", &issued, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestClusterKubeconfigRejectsInvalidExpiry(t *testing.T) {
+	t.Parallel()
+
+	for _, expiry := range []string{"soon", "-1h", "0s", "25h"} {
+		reviews := []authorizationv1.SelfSubjectAccessReview{}
+		target := newFakeKubeconfigAPIServer(t, true, authenticationv1.UserInfo{Username: "jane"}, &reviews)
+
+		issued := issuedKubeconfig{}
+
+		recorder := serveClusterKubeconfig(t, target, "?expiry="+expiry, &issued, nil)
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for expiry %q, got %d", expiry, recorder.Code)
+		}
+	}
+}
+
+func TestClusterKubeconfigReportsMissingCluster(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeKubeconfigAPIServer(t, true, authenticationv1.UserInfo{Username: "jane"}, &reviews)
+
+	issued := issuedKubeconfig{}
+
+	recorder := serveClusterKubeconfig(t, target, "", &issued,
+		apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "prod-kubeconfig"))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestMintKubeconfigSignsClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	caKey, err := generateRSAPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+
+	now := time.Now()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour * 24),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+
+	notAfter := now.Add(time.Hour).Truncate(time.Second)
+
+	content, err := mintKubeconfig("prod", &api.Cluster{
+		Server:                   "https://prod.example.com:6443",
+		CertificateAuthorityData: certs.EncodeCertPEM(caCert),
+	}, caCert, caKey, kubeconfigIdentity{Username: "jane", Groups: []string{"platform"}}, notAfter)
+	if err != nil {
+		t.Fatalf("failed to mint kubeconfig: %v", err)
+	}
+
+	kubeconfig, err := clientcmd.Load(content)
+	if err != nil {
+		t.Fatalf("failed to load kubeconfig: %v", err)
+	}
+
+	if kubeconfig.CurrentContext != "jane@prod" ||
+		kubeconfig.Clusters["prod"].Server != "https://prod.example.com:6443" {
+		t.Fatalf("unexpected kubeconfig %+v", kubeconfig)
+	}
+
+	clientCert, err := certs.DecodeCertPEM(kubeconfig.AuthInfos["jane@prod"].ClientCertificateData)
+	if err != nil {
+		t.Fatalf("failed to decode client certificate: %v", err)
+	}
+
+	if clientCert.Subject.CommonName != "jane" || !slices.Equal(clientCert.Subject.Organization, []string{"platform"}) ||
+		!clientCert.NotAfter.Equal(notAfter) {
+		t.Fatalf("unexpected client certificate %+v", clientCert.Subject)
+	}
+
+	err = clientCert.CheckSignatureFrom(caCert)
+	if err != nil {
+		t.Fatalf("expected client certificate signed by the cluster CA: %v", err)
+	}
+}
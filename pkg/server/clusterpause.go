@@ -0,0 +1,158 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// clusterPauseKind is the kind reported by the clusters/{name}/pause subresource.
+	clusterPauseKind = "ClusterPause"
+	// clustersPath is the collection path of CAPI Clusters, relative to a namespace.
+	clustersPath = "/apis/cluster.x-k8s.io/v1beta1/namespaces/%s/clusters/%s"
+	// clusterPausePattern is the mux pattern of the pause subresource.
+	clusterPausePattern = "/apis/cluster.x-k8s.io/v1beta1/namespaces/{namespace}/clusters/{name}/pause"
+)
+
+// ClusterPause is the representation of the clusters/{name}/pause subresource.
+type ClusterPause struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterPauseSpec   `json:"spec"`
+	Status ClusterPauseStatus `json:"status"`
+}
+
+// ClusterPauseSpec holds the requested pause.
+type ClusterPauseSpec struct {
+	// Reason tells other operators why the cluster is paused.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ClusterPauseStatus holds the observed pause.
+type ClusterPauseStatus struct {
+	// Paused reports whether reconciliation of the cluster is paused.
+	Paused bool `json:"paused"`
+}
+
+func (h *clusterSubresourceHandler) getPause(w http.ResponseWriter, r *http.Request) {
+	cluster, ok := h.fetchCluster(w, r, http.MethodGet, nil)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clusterPauseFromCluster(cluster))
+}
+
+// postPause pauses reconciliation of the Cluster by Cluster API, its providers and the
+// Kommodity reconcilers that change the workload cluster.
+func (h *clusterSubresourceHandler) postPause(w http.ResponseWriter, r *http.Request) {
+	request := &ClusterPause{}
+
+	err := json.NewDecoder(io.LimitReader(r.Body, maxMachineUpgradeBodySize)).Decode(request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeStatusError(w, apierrors.NewBadRequest(fmt.Sprintf("invalid ClusterPause: %v", err)))
+
+		return
+	}
+
+	var reason any
+	if request.Spec.Reason != "" {
+		reason = request.Spec.Reason
+	}
+
+	h.patchPause(w, r, true, reason)
+}
+
+// deletePause resumes reconciliation of the Cluster.
+func (h *clusterSubresourceHandler) deletePause(w http.ResponseWriter, r *http.Request) {
+	h.patchPause(w, r, false, nil)
+}
+
+// patchPause sets spec.paused of the Cluster along with the reason, which is removed
+// when nil.
+func (h *clusterSubresourceHandler) patchPause(w http.ResponseWriter, r *http.Request, paused bool, reason any) {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{
+				reconciler.AnnotationPauseReason: reason,
+			},
+		},
+		"spec": map[string]any{
+			"paused": paused,
+		},
+	})
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return
+	}
+
+	cluster, ok := h.fetchCluster(w, r, http.MethodPatch, patch)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clusterPauseFromCluster(cluster))
+}
+
+// fetchCluster performs the request against the Cluster and returns the Cluster in the
+// response. Error responses are passed through unchanged and reported as not ok.
+func (h *clusterSubresourceHandler) fetchCluster(w http.ResponseWriter, r *http.Request,
+	method string, body []byte) (*clusterv1.Cluster, bool) {
+	upstreamURL := h.target.JoinPath(fmt.Sprintf(clustersPath,
+		url.PathEscape(r.PathValue("namespace")), url.PathEscape(r.PathValue("name"))))
+
+	contentType := ""
+	if method == http.MethodPatch {
+		contentType = "application/merge-patch+json"
+	}
+
+	respBody, ok := h.do(w, r, method, upstreamURL.String(), contentType, body, http.StatusOK)
+	if !ok {
+		return nil, false
+	}
+
+	cluster := &clusterv1.Cluster{}
+
+	err := json.Unmarshal(respBody, cluster)
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return nil, false
+	}
+
+	return cluster, true
+}
+
+// clusterPauseFromCluster builds the subresource view of a Cluster.
+func clusterPauseFromCluster(cluster *clusterv1.Cluster) *ClusterPause {
+	return &ClusterPause{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       clusterPauseKind,
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              cluster.Name,
+			Namespace:         cluster.Namespace,
+			UID:               cluster.UID,
+			ResourceVersion:   cluster.ResourceVersion,
+			CreationTimestamp: cluster.CreationTimestamp,
+		},
+		Spec: ClusterPauseSpec{
+			Reason: cluster.Annotations[reconciler.AnnotationPauseReason],
+		},
+		Status: ClusterPauseStatus{
+			Paused: cluster.Spec.Paused,
+		},
+	}
+}
@@ -0,0 +1,133 @@
+//nolint:testpackage // white-box tests exercise the unexported cluster subresource handler
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const testClusterPath = "/apis/cluster.x-k8s.io/v1beta1/namespaces/default/clusters/prod"
+
+// newFakeClusterAPIServer records the request and returns the Cluster paused for maintenance.
+func newFakeClusterAPIServer(t *testing.T, recorded *recordedRequest) *url.URL {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*recorded = recordedRequest{
+			method:        r.Method,
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			contentType:   r.Header.Get("Content-Type"),
+			body:          string(body),
+		}
+
+		_ = json.NewEncoder(w).Encode(&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "prod",
+				Namespace:   "default",
+				Annotations: map[string]string{reconciler.AnnotationPauseReason: "etcd maintenance"},
+			},
+			Spec: clusterv1.ClusterSpec{Paused: true},
+		})
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse upstream URL: %v", err)
+	}
+
+	return target
+}
+
+func serveClusterPause(t *testing.T, target *url.URL, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	newClusterSubresourceHandler(target, http.DefaultTransport, nil, nil).register(mux)
+
+	req := httptest.NewRequest(method, testClusterPath+"/pause", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer caller-token")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func decodeClusterPause(t *testing.T, recorder *httptest.ResponseRecorder) *ClusterPause {
+	t.Helper()
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	pause := &ClusterPause{}
+
+	err := json.Unmarshal(recorder.Body.Bytes(), pause)
+	if err != nil {
+		t.Fatalf("failed to decode ClusterPause: %v", err)
+	}
+
+	return pause
+}
+
+func TestClusterPauseGetReportsPause(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeClusterAPIServer(t, recorded)
+
+	pause := decodeClusterPause(t, serveClusterPause(t, target, http.MethodGet, ""))
+
+	if pause.Kind != clusterPauseKind || !pause.Status.Paused || pause.Spec.Reason != "etcd maintenance" {
+		t.Fatalf("unexpected ClusterPause %+v", pause)
+	}
+
+	if recorded.method != http.MethodGet || recorded.path != testClusterPath ||
+		recorded.authorization != "Bearer caller-token" {
+		t.Fatalf("unexpected upstream request %+v", recorded)
+	}
+}
+
+func TestClusterPausePostPausesCluster(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeClusterAPIServer(t, recorded)
+
+	decodeClusterPause(t, serveClusterPause(t, target, http.MethodPost, `{"spec":{"reason":"etcd maintenance"}}`))
+
+	if recorded.method != http.MethodPatch || recorded.contentType != "application/merge-patch+json" {
+		t.Fatalf("unexpected upstream request %+v", recorded)
+	}
+
+	want := `{"metadata":{"annotations":{"kommodity.io/pause-reason":"etcd maintenance"}},"spec":{"paused":true}}`
+	if recorded.body != want {
+		t.Fatalf("unexpected patch %s", recorded.body)
+	}
+}
+
+func TestClusterPauseDeleteResumesCluster(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeClusterAPIServer(t, recorded)
+
+	decodeClusterPause(t, serveClusterPause(t, target, http.MethodDelete, ""))
+
+	want := `{"metadata":{"annotations":{"kommodity.io/pause-reason":null}},"spec":{"paused":false}}`
+	if recorded.method != http.MethodPatch || recorded.body != want {
+		t.Fatalf("unexpected upstream request %+v", recorded)
+	}
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/endpoints/responsewriter"
+)
+
+const (
+	watchVerb = "watch"
+
+	gzipEncoding = "gzip"
+)
+
+//nolint:gochecknoglobals // Writers are pooled process wide, as by the API server.
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		// The level of the API server: large lists compress well already, and the
+		// event streams of watches are compressed as they are written.
+		writer, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+
+		return writer
+	},
+}
+
+// withResponseCompression gzips the LIST responses above the minimum size, and the
+// event streams of WATCH requests, for clients accepting gzip. It replaces the
+// compression of the API server, which only covers LIST responses above a fixed size,
+// by hiding the Accept-Encoding header of the requests it compresses from it. As the
+// aggregator serves every API, this covers the CRDs and the aggregated APIs as well.
+// It expects the request info to be resolved already, so it must be installed inside
+// the generic handler chain. If compression is disabled the handler is returned
+// unchanged.
+func withResponseCompression(handler http.Handler, cfg *config.CompressionConfig) http.Handler {
+	if cfg == nil || !cfg.Enabled {
+		return handler
+	}
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		requestInfo, found := request.RequestInfoFrom(req.Context())
+		if !found || !requestInfo.IsResourceRequest || !acceptsGzip(req) || req.Header.Get("Upgrade") != "" {
+			handler.ServeHTTP(writer, req)
+
+			return
+		}
+
+		watch := requestInfo.Verb == watchVerb
+		if requestInfo.Verb != listVerb && (!watch || !cfg.Watches) {
+			handler.ServeHTTP(writer, req)
+
+			return
+		}
+
+		req = req.Clone(req.Context())
+		req.Header.Del("Accept-Encoding")
+
+		compressor := &compressingResponseWriter{ResponseWriter: writer, minSize: cfg.MinSize, watch: watch}
+		defer compressor.close()
+
+		handler.ServeHTTP(responsewriter.WrapForHTTP1Or2(compressor), req)
+	})
+}
+
+// acceptsGzip reports whether the request accepts gzip encoded responses.
+func acceptsGzip(req *http.Request) bool {
+	for _, value := range req.Header.Values("Accept-Encoding") {
+		for encoding := range strings.SplitSeq(value, ",") {
+			name, params, _ := strings.Cut(encoding, ";")
+			if strings.TrimSpace(name) != gzipEncoding {
+				continue
+			}
+
+			quality := strings.ReplaceAll(params, " ", "")
+
+			return quality != "q=0" && quality != "q=0.0" && quality != "q=0.00" && quality != "q=0.000"
+		}
+	}
+
+	return false
+}
+
+// compressingResponseWriter buffers a response until it is known to be large enough to
+// be compressed. Successful watch responses are compressed from their first byte and
+// flushed event by event.
+type compressingResponseWriter struct {
+	http.ResponseWriter
+
+	minSize int
+	watch   bool
+
+	status int
+	buffer []byte
+	// decided is set once the response is written, compressed or as is.
+	decided    bool
+	gzipWriter *gzip.Writer
+}
+
+var _ responsewriter.UserProvidedDecorator = &compressingResponseWriter{}
+
+// Unwrap returns the response writer the response is written to.
+func (w *compressingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WriteHeader records the status. Failed or already encoded responses are written as is.
+func (w *compressingResponseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+
+	w.status = status
+
+	switch {
+	case status != http.StatusOK || w.Header().Get("Content-Encoding") != "":
+		_ = w.writeUncompressed()
+	case w.watch:
+		_ = w.writeCompressed()
+	}
+}
+
+// Write buffers the response until it reaches the minimum size.
+func (w *compressingResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.decided {
+		if w.gzipWriter != nil {
+			return w.gzipWriter.Write(data) //nolint:wrapcheck // Written on behalf of the handler.
+		}
+
+		return w.ResponseWriter.Write(data) //nolint:wrapcheck // Written on behalf of the handler.
+	}
+
+	w.buffer = append(w.buffer, data...)
+
+	if len(w.buffer) >= w.minSize {
+		err := w.writeCompressed()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Flush writes what was written so far, uncompressed if the response was not known to
+// be large enough yet.
+func (w *compressingResponseWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		_ = w.writeUncompressed()
+	}
+
+	if w.gzipWriter != nil {
+		_ = w.gzipWriter.Flush()
+	}
+
+	if flusher, isFlusher := w.ResponseWriter.(http.Flusher); isFlusher {
+		flusher.Flush()
+	}
+}
+
+func (w *compressingResponseWriter) writeCompressed() error {
+	w.decided = true
+
+	header := w.Header()
+	header.Set("Content-Encoding", gzipEncoding)
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	w.gzipWriter, _ = gzipWriterPool.Get().(*gzip.Writer)
+	w.gzipWriter.Reset(w.ResponseWriter)
+
+	return w.flushBuffer(w.gzipWriter)
+}
+
+func (w *compressingResponseWriter) writeUncompressed() error {
+	w.decided = true
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	return w.flushBuffer(w.ResponseWriter)
+}
+
+func (w *compressingResponseWriter) flushBuffer(writer io.Writer) error {
+	buffer := w.buffer
+	w.buffer = nil
+
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	_, err := writer.Write(buffer)
+
+	return err //nolint:wrapcheck // Written on behalf of the handler.
+}
+
+// close writes the rest of the response once the handler returned.
+func (w *compressingResponseWriter) close() {
+	if !w.decided && w.status != 0 {
+		_ = w.writeUncompressed()
+	}
+
+	if w.gzipWriter != nil {
+		_ = w.gzipWriter.Close()
+
+		w.gzipWriter.Reset(io.Discard)
+		gzipWriterPool.Put(w.gzipWriter)
+		w.gzipWriter = nil
+	}
+}
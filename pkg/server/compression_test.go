@@ -0,0 +1,198 @@
+//nolint:testpackage // white-box tests exercise the unexported response compression filter
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const compressionTestMinSize = 1024
+
+func newCompressionRequest(verb string, acceptEncoding string) *http.Request {
+	req := newLoadSheddingRequest("/api/v1/configmaps", &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              verb,
+		APIVersion:        "v1",
+		Resource:          "configmaps",
+	})
+
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	return req
+}
+
+func newCompressionHandler(inner http.Handler) http.Handler {
+	return withResponseCompression(inner, &config.CompressionConfig{
+		Enabled: true,
+		MinSize: compressionTestMinSize,
+		Watches: true,
+	})
+}
+
+func writeBody(body string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(writer, body)
+	})
+}
+
+// TestResponseCompressionGzipsLargeLists asserts that a LIST response above the
+// minimum size is gzipped, and that the API server does not see Accept-Encoding.
+func TestResponseCompressionGzipsLargeLists(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("a", 2*compressionTestMinSize)
+	recorder := httptest.NewRecorder()
+
+	inner := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Accept-Encoding") != "" {
+			http.Error(writer, "Accept-Encoding leaked to the API server", http.StatusInternalServerError)
+
+			return
+		}
+
+		writeBody(body).ServeHTTP(writer, req)
+	})
+
+	newCompressionHandler(inner).ServeHTTP(recorder, newCompressionRequest(listVerb, "gzip, deflate"))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if encoding := recorder.Header().Get("Content-Encoding"); encoding != gzipEncoding {
+		t.Fatalf("expected gzip Content-Encoding, got %q", encoding)
+	}
+
+	reader, err := gzip.NewReader(recorder.Body)
+	if err != nil {
+		t.Fatalf("failed to read gzip response: %v", err)
+	}
+
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to decompress response: %v", err)
+	}
+
+	if string(decompressed) != body {
+		t.Fatalf("expected the decompressed body to match, got %d bytes", len(decompressed))
+	}
+}
+
+// TestResponseCompressionSkipsSmallAndUnacceptedResponses asserts that small LIST
+// responses, clients not accepting gzip and other verbs are served as is.
+func TestResponseCompressionSkipsSmallAndUnacceptedResponses(t *testing.T) {
+	t.Parallel()
+
+	large := strings.Repeat("a", 2*compressionTestMinSize)
+
+	tests := []struct {
+		name           string
+		verb           string
+		acceptEncoding string
+		body           string
+	}{
+		{name: "small list", verb: listVerb, acceptEncoding: gzipEncoding, body: "{}"},
+		{name: "no accept encoding", verb: listVerb, body: large},
+		{name: "gzip refused", verb: listVerb, acceptEncoding: "gzip;q=0", body: large},
+		{name: "get", verb: "get", acceptEncoding: gzipEncoding, body: large},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+
+			newCompressionHandler(writeBody(test.body)).ServeHTTP(recorder,
+				newCompressionRequest(test.verb, test.acceptEncoding))
+
+			if encoding := recorder.Header().Get("Content-Encoding"); encoding != "" {
+				t.Fatalf("expected no Content-Encoding, got %q", encoding)
+			}
+
+			if recorder.Body.String() != test.body {
+				t.Fatalf("expected the body as is, got %d bytes", recorder.Body.Len())
+			}
+		})
+	}
+}
+
+// TestResponseCompressionFlushesWatchEvents asserts that each flushed event of a WATCH
+// can be read by the client before the stream ends.
+func TestResponseCompressionFlushesWatchEvents(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		newCompressionHandler(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			flusher, _ := writer.(http.Flusher)
+
+			writer.WriteHeader(http.StatusOK)
+			flusher.Flush()
+
+			for event := range events {
+				_, _ = io.WriteString(writer, event+"\n")
+
+				flusher.Flush()
+			}
+		})).ServeHTTP(writer, req.WithContext(request.WithRequestInfo(req.Context(), &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              watchVerb,
+			APIVersion:        "v1",
+			Resource:          "configmaps",
+		})))
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	// Set explicitly, so the transport leaves the response compressed.
+	req.Header.Set("Accept-Encoding", gzipEncoding)
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != gzipEncoding {
+		t.Fatalf("expected gzip Content-Encoding, got %q", encoding)
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read gzip response: %v", err)
+	}
+
+	lines := bufio.NewReader(reader)
+
+	for _, event := range []string{"first", "second"} {
+		events <- event
+
+		line, err := lines.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event %q: %v", event, err)
+		}
+
+		if line != event+"\n" {
+			t.Fatalf("expected event %q, got %q", event, line)
+		}
+	}
+
+	close(events)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// defaultingPluginName is the name of the admission plugin defaulting the kommodity.io API.
+const defaultingPluginName = "KommodityDefaulting"
+
+// defaultingPlugin applies the defaulting functions registered in its scheme to custom
+// resources, like the API server does for built-in types when decoding them. Kommodity's
+// own types are defaulted in process instead of by a webhook served on localhost, so
+// creating them neither waits for a webhook round trip nor fails when it is unreachable.
+type defaultingPlugin struct {
+	*admission.Handler
+
+	scheme *runtime.Scheme
+}
+
+var _ admission.MutationInterface = &defaultingPlugin{}
+
+// registerDefaultingPlugin registers the plugin with the admission plugins.
+func registerDefaultingPlugin(plugins *admission.Plugins) {
+	plugins.Register(defaultingPluginName, func(io.Reader) (admission.Interface, error) {
+		return newDefaultingPlugin(kommodityv1alpha1.AddToScheme)
+	})
+}
+
+func newDefaultingPlugin(addToSchemes ...func(*runtime.Scheme) error) (*defaultingPlugin, error) {
+	scheme := runtime.NewScheme()
+
+	for _, addToScheme := range addToSchemes {
+		err := addToScheme(scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add defaulted types to scheme: %w", err)
+		}
+	}
+
+	return &defaultingPlugin{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+		scheme:  scheme,
+	}, nil
+}
+
+// Admit defaults the spec of objects whose kind is registered in the scheme of the
+// plugin. Only the spec is written back, the rest of the object is left as decoded.
+func (p *defaultingPlugin) Admit(_ context.Context, attrs admission.Attributes, _ admission.ObjectInterfaces) error {
+	if attrs.GetSubresource() != "" || !p.scheme.Recognizes(attrs.GetKind()) {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	typed, err := p.scheme.New(attrs.GetKind())
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", attrs.GetKind(), err)
+	}
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed)
+	if err != nil {
+		return admission.NewForbidden(attrs, fmt.Errorf("failed to decode %s: %w", attrs.GetKind().Kind, err))
+	}
+
+	p.scheme.Default(typed)
+
+	defaulted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed)
+	if err != nil {
+		return fmt.Errorf("failed to encode defaulted %s: %w", attrs.GetKind().Kind, err)
+	}
+
+	spec, found := defaulted["spec"]
+	if found {
+		obj.Object["spec"] = spec
+	}
+
+	return nil
+}
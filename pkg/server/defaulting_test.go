@@ -0,0 +1,114 @@
+//nolint:testpackage // white-box tests exercise the unexported defaulting plugin
+package server
+
+import (
+	"testing"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+func newDefaultingAttributes(obj *unstructured.Unstructured, subresource string) admission.Attributes {
+	kind := obj.GroupVersionKind()
+
+	return admission.NewAttributesRecord(obj, nil, kind, obj.GetNamespace(), obj.GetName(),
+		schema.GroupVersionResource{Group: kind.Group, Version: kind.Version, Resource: "clusteraddons"},
+		subresource, admission.Create, nil, false, nil)
+}
+
+func newTestClusterAddon() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": kommodityv1alpha1.GroupVersion.String(),
+		"kind":       "ClusterAddon",
+		"metadata":   map[string]any{"name": "ingress", "namespace": "default"},
+		"spec": map[string]any{
+			"clusterName": "my-cluster",
+			"chart":       map[string]any{"repository": "https://charts.example.com", "name": "ingress-nginx"},
+		},
+	}}
+}
+
+func TestDefaultingPluginDefaultsKommodityTypes(t *testing.T) {
+	t.Parallel()
+
+	plugin, err := newDefaultingPlugin(kommodityv1alpha1.AddToScheme)
+	if err != nil {
+		t.Fatalf("failed to create plugin: %v", err)
+	}
+
+	addon := newTestClusterAddon()
+
+	err = plugin.Admit(t.Context(), newDefaultingAttributes(addon, ""), nil)
+	if err != nil {
+		t.Fatalf("admit failed: %v", err)
+	}
+
+	releaseName, _, _ := unstructured.NestedString(addon.Object, "spec", "releaseName")
+	namespace, _, _ := unstructured.NestedString(addon.Object, "spec", "namespace")
+	repository, _, _ := unstructured.NestedString(addon.Object, "spec", "chart", "repository")
+
+	if releaseName != "ingress-nginx" || namespace != "ingress-nginx" {
+		t.Fatalf("expected the release and namespace to default to the chart name, got %q and %q",
+			releaseName, namespace)
+	}
+
+	if repository != "https://charts.example.com" || addon.GetName() != "ingress" {
+		t.Fatalf("expected the rest of the object to be kept, got %v", addon.Object)
+	}
+
+	plan := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": kommodityv1alpha1.GroupVersion.String(),
+		"kind":       "TalosUpgradePlan",
+		"metadata":   map[string]any{"name": "upgrade", "namespace": "default"},
+		"spec":       map[string]any{"clusterName": "my-cluster", "image": "ghcr.io/siderolabs/installer:v1.10.0"},
+	}}
+
+	err = plugin.Admit(t.Context(), newDefaultingAttributes(plan, ""), nil)
+	if err != nil {
+		t.Fatalf("admit failed: %v", err)
+	}
+
+	timeout, _, _ := unstructured.NestedString(plan.Object, "spec", "healthCheckTimeout")
+	if timeout != "15m0s" {
+		t.Fatalf("expected the health check timeout to be defaulted, got %q", timeout)
+	}
+}
+
+func TestDefaultingPluginSkipsOtherObjects(t *testing.T) {
+	t.Parallel()
+
+	plugin, err := newDefaultingPlugin(kommodityv1alpha1.AddToScheme)
+	if err != nil {
+		t.Fatalf("failed to create plugin: %v", err)
+	}
+
+	status := newTestClusterAddon()
+
+	err = plugin.Admit(t.Context(), newDefaultingAttributes(status, "status"), nil)
+	if err != nil {
+		t.Fatalf("admit failed: %v", err)
+	}
+
+	cluster := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "Cluster",
+		"metadata":   map[string]any{"name": "my-cluster", "namespace": "default"},
+		"spec":       map[string]any{"paused": true},
+	}}
+
+	err = plugin.Admit(t.Context(), newDefaultingAttributes(cluster, ""), nil)
+	if err != nil {
+		t.Fatalf("admit failed: %v", err)
+	}
+
+	_, found, _ := unstructured.NestedString(status.Object, "spec", "releaseName")
+	if found {
+		t.Fatalf("expected status updates not to be defaulted, got %v", status.Object)
+	}
+
+	if len(cluster.Object["spec"].(map[string]any)) != 1 { //nolint:forcetypeassert // Set above.
+		t.Fatalf("expected kinds without defaults to be left alone, got %v", cluster.Object)
+	}
+}
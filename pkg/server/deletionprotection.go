@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// deletionProtectionPluginName is the name of the admission plugin guarding protected
+// Clusters against deletion.
+const deletionProtectionPluginName = "KommodityDeletionProtection"
+
+// deletionProtectionPlugin rejects the deletion of Clusters with deletion protection,
+// unless it was confirmed with the deletion-confirmed-at annotation shortly before. An
+// accidental kubectl delete of a protected Cluster thus leaves its machines untouched,
+// and a deletion takes two steps: confirming it, then deleting the Cluster.
+type deletionProtectionPlugin struct {
+	*admission.Handler
+
+	// now returns the current time, defaults to time.Now.
+	now func() time.Time
+}
+
+var _ admission.ValidationInterface = &deletionProtectionPlugin{}
+
+// registerDeletionProtectionPlugin registers the plugin with the admission plugins.
+func registerDeletionProtectionPlugin(plugins *admission.Plugins) {
+	plugins.Register(deletionProtectionPluginName, func(io.Reader) (admission.Interface, error) {
+		return newDeletionProtectionPlugin(), nil
+	})
+}
+
+func newDeletionProtectionPlugin() *deletionProtectionPlugin {
+	return &deletionProtectionPlugin{
+		Handler: admission.NewHandler(admission.Delete),
+		now:     time.Now,
+	}
+}
+
+// Validate rejects deleting a protected Cluster without a recent confirmation. The
+// deleted Cluster is the old object of the request, for collection deletes as well.
+func (p *deletionProtectionPlugin) Validate(_ context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetOperation() != admission.Delete || attrs.GetSubresource() != "" ||
+		attrs.GetResource().GroupResource() != clustersResource.GroupResource() {
+		return nil
+	}
+
+	cluster, ok := attrs.GetOldObject().(metav1.Object)
+	if !ok {
+		return nil
+	}
+
+	annotations := cluster.GetAnnotations()
+	if annotations[reconciler.AnnotationDeletionProtection] != "true" {
+		return nil
+	}
+
+	confirmedAt, err := time.Parse(time.RFC3339, annotations[reconciler.AnnotationDeletionConfirmedAt])
+	if err != nil {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: annotate it with %s set to the current time first",
+			ErrClusterDeletionProtected, reconciler.AnnotationDeletionConfirmedAt))
+	}
+
+	// A confirmation far in the future would allow deleting the Cluster at any time.
+	age := p.now().Sub(confirmedAt)
+	if age > reconciler.DeletionConfirmationWindow || age < -reconciler.DeletionConfirmationWindow {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: the confirmation at %s is not within %s",
+			ErrClusterDeletionProtected, confirmedAt.Format(time.RFC3339), reconciler.DeletionConfirmationWindow))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+//nolint:testpackage // white-box tests exercise the unexported deletion protection plugin
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+func TestDeletionProtectionPlugin(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	plugin := newDeletionProtectionPlugin()
+	plugin.now = func() time.Time { return now }
+
+	tests := []struct {
+		name        string
+		annotations map[string]any
+		rejected    bool
+	}{
+		{name: "unprotected"},
+		{name: "protected", annotations: map[string]any{
+			reconciler.AnnotationDeletionProtection: "true",
+		}, rejected: true},
+		{name: "confirmed", annotations: map[string]any{
+			reconciler.AnnotationDeletionProtection:  "true",
+			reconciler.AnnotationDeletionConfirmedAt: now.Add(-time.Minute).Format(time.RFC3339),
+		}},
+		{name: "confirmation expired", annotations: map[string]any{
+			reconciler.AnnotationDeletionProtection:  "true",
+			reconciler.AnnotationDeletionConfirmedAt: now.Add(-time.Hour).Format(time.RFC3339),
+		}, rejected: true},
+		{name: "confirmation in the future", annotations: map[string]any{
+			reconciler.AnnotationDeletionProtection:  "true",
+			reconciler.AnnotationDeletionConfirmedAt: now.AddDate(1, 0, 0).Format(time.RFC3339),
+		}, rejected: true},
+		{name: "invalid confirmation", annotations: map[string]any{
+			reconciler.AnnotationDeletionProtection:  "true",
+			reconciler.AnnotationDeletionConfirmedAt: "yes",
+		}, rejected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cluster := &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "cluster.x-k8s.io/v1beta1",
+				"kind":       "Cluster",
+				"metadata":   map[string]any{"name": "prod", "namespace": "team-a", "annotations": test.annotations},
+			}}
+			attrs := admission.NewAttributesRecord(nil, cluster, cluster.GroupVersionKind(), "team-a", "prod",
+				clustersResource, "", admission.Delete, nil, false, nil)
+
+			err := plugin.Validate(t.Context(), attrs, nil)
+
+			rejected := apierrors.IsForbidden(err) && strings.Contains(err.Error(), ErrClusterDeletionProtected.Error())
+			if test.rejected != rejected || (!rejected && err != nil) {
+				t.Fatalf("expected rejected %t, got %v", test.rejected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// devPathPrefix prefixes the development endpoints that front the API server.
+const devPathPrefix = "/dev"
+
+// devEndpoint is an endpoint published on the public listener in development mode.
+type devEndpoint struct {
+	pattern     string
+	description string
+	handler     http.Handler
+}
+
+// registerDevelopmentEndpoints publishes convenience endpoints for local development
+// and logs a summary of everything Kommodity serves. The endpoints call the API server
+// with the credentials of the caller, through the transport of the API server proxy.
+// Profiles are left to the API server, see KOMMODITY_ENABLE_PPROF.
+func registerDevelopmentEndpoints(ctx context.Context,
+	mux *http.ServeMux,
+	cfg *config.KommodityConfig,
+	target *url.URL,
+	transport http.RoundTripper) {
+	if !cfg.DevelopmentMode {
+		return
+	}
+
+	endpoints := developmentEndpoints(target, transport)
+
+	for _, endpoint := range endpoints {
+		mux.Handle(endpoint.pattern, endpoint.handler)
+	}
+
+	logServedEndpoints(ctx, cfg, endpoints)
+}
+
+func developmentEndpoints(target *url.URL, transport http.RoundTripper) []devEndpoint {
+	openAPIProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.URL.Path = strings.TrimPrefix(r.In.URL.Path, devPathPrefix)
+			r.Out.URL.RawPath = ""
+		},
+		Transport: transport,
+	}
+
+	return []devEndpoint{
+		{
+			pattern:     "GET " + devPathPrefix + "/openapi/",
+			description: "OpenAPI specs of the API server",
+			handler:     openAPIProxy,
+		},
+		{
+			pattern:     "GET " + devPathPrefix + "/discovery",
+			description: "All served groups, versions and resources with their verbs",
+			handler: discoveryHandler(func(r *http.Request) (discovery.DiscoveryInterface, error) {
+				return discovery.NewDiscoveryClientForConfig(callerConfig(target, transport, r))
+			}),
+		},
+	}
+}
+
+// callerConfig returns a client configuration for the API server that authenticates
+// with the credentials the request carries.
+func callerConfig(target *url.URL, transport http.RoundTripper, r *http.Request) *rest.Config {
+	return &rest.Config{
+		Host:      target.String(),
+		Transport: &callerCredentialsRoundTripper{caller: r, next: transport},
+	}
+}
+
+// callerCredentialsRoundTripper forwards the credentials of the caller on requests: its
+// bearer token, or the operator its client certificate names.
+type callerCredentialsRoundTripper struct {
+	caller *http.Request
+	next   http.RoundTripper
+}
+
+func (rt *callerCredentialsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorization := rt.caller.Header.Get("Authorization")
+	if authorization != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", authorization)
+	}
+
+	if operator := rt.caller.Context().Value(clientCertificateUserKey{}); operator != nil {
+		req = req.WithContext(context.WithValue(req.Context(), clientCertificateUserKey{}, operator))
+	}
+
+	//nolint:wrapcheck // The error of the next round tripper is returned as is.
+	return rt.next.RoundTrip(req)
+}
+
+// logServedEndpoints logs where each listener is reachable, the published development
+// endpoints and the enabled providers.
+func logServedEndpoints(ctx context.Context, cfg *config.KommodityConfig, endpoints []devEndpoint) {
+	logger := logging.FromContext(ctx)
+
+	scheme := "http"
+	if cfg.ACMEConfig != nil && len(cfg.ACMEConfig.Domains) > 0 {
+		scheme = "https"
+	}
+
+	publicURL := scheme + "://localhost:" + strconv.Itoa(cfg.ServerPort)
+
+	published := make([]string, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		// Endpoints without a description are implementation details of another one.
+		if endpoint.description == "" {
+			continue
+		}
+
+		_, path, _ := strings.Cut(endpoint.pattern, " ")
+		if path == "" {
+			path = endpoint.pattern
+		}
+
+		published = append(published, publicURL+path+" ("+endpoint.description+")")
+	}
+
+	providers := make([]string, 0, len(cfg.InfrastructureProviders))
+	for _, provider := range cfg.InfrastructureProviders {
+		providers = append(providers, string(provider))
+	}
+
+	logger.Info("Development mode endpoints",
+		zap.String("publicURL", publicURL),
+		zap.String("ui", publicURL+"/ui"),
+		zap.String("kubernetesAPI", publicURL),
+		zap.String("apiServerURL", "https://localhost:"+strconv.Itoa(cfg.APIServerPort)),
+		zap.String("webhookURL", "https://localhost:"+strconv.Itoa(cfg.WebhookPort)),
+		zap.Strings("endpoints", published),
+		zap.Strings("providers", providers))
+}
+
+// discoveryGroupVersion is a served group version in the verbose discovery listing.
+type discoveryGroupVersion struct {
+	GroupVersion string              `json:"groupVersion"`
+	Resources    []discoveryResource `json:"resources"`
+}
+
+// discoveryResource is a served resource in the verbose discovery listing.
+type discoveryResource struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
+	Verbs      []string `json:"verbs"`
+}
+
+// discoveryListing is the verbose discovery listing. Groups whose discovery failed are
+// listed with their error instead of failing the whole listing.
+type discoveryListing struct {
+	GroupVersions []discoveryGroupVersion `json:"groupVersions"`
+	Failed        map[string]string       `json:"failed,omitempty"`
+}
+
+// discoveryHandler serves the verbose discovery listing, discovered with the client
+// returned for the request.
+func discoveryHandler(newClient func(r *http.Request) (discovery.DiscoveryInterface, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, err := newClient(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to create discovery client: %v", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		_, resourceLists, err := client.ServerGroupsAndResources()
+
+		listing := discoveryListing{GroupVersions: make([]discoveryGroupVersion, 0, len(resourceLists))}
+
+		var groupErr *discovery.ErrGroupDiscoveryFailed
+
+		switch {
+		case errors.As(err, &groupErr):
+			listing.Failed = make(map[string]string, len(groupErr.Groups))
+			for groupVersion, failure := range groupErr.Groups {
+				listing.Failed[groupVersion.String()] = failure.Error()
+			}
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadGateway)
+
+			return
+		}
+
+		for _, resourceList := range resourceLists {
+			groupVersion := discoveryGroupVersion{
+				GroupVersion: resourceList.GroupVersion,
+				Resources:    make([]discoveryResource, 0, len(resourceList.APIResources)),
+			}
+
+			for _, resource := range resourceList.APIResources {
+				groupVersion.Resources = append(groupVersion.Resources, discoveryResource{
+					Name:       resource.Name,
+					Kind:       resource.Kind,
+					Namespaced: resource.Namespaced,
+					Verbs:      resource.Verbs,
+				})
+			}
+
+			listing.GroupVersions = append(listing.GroupVersions, groupVersion)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(listing)
+	})
+}
@@ -0,0 +1,118 @@
+//nolint:testpackage // white-box tests exercise the unexported development endpoints
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestDevelopmentEndpointsRequireDevelopmentMode(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+
+	registerDevelopmentEndpoints(t.Context(), mux, &config.KommodityConfig{},
+		&url.URL{Scheme: "https", Host: "localhost:8443"}, http.DefaultTransport)
+
+	for _, path := range []string{"/dev/openapi/v3", "/dev/discovery"} {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, path, nil))
+		if pattern != "" {
+			t.Fatalf("expected %s not to be served outside development mode, matched %q", path, pattern)
+		}
+	}
+}
+
+// TestDevelopmentEndpointsUseCallerCredentials asserts that the API server is called
+// with the credentials of the caller, and that no profiles are served.
+func TestDevelopmentEndpointsUseCallerCredentials(t *testing.T) {
+	t.Parallel()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer caller" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	t.Cleanup(apiServer.Close)
+
+	target, err := url.Parse(apiServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	registerDevelopmentEndpoints(t.Context(), mux, &config.KommodityConfig{DevelopmentMode: true},
+		target, http.DefaultTransport)
+
+	for authorization, expectedCode := range map[string]int{"": http.StatusUnauthorized, "Bearer caller": http.StatusOK} {
+		request := httptest.NewRequest(http.MethodGet, "/dev/openapi/v3", nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+
+		if recorder.Code != expectedCode {
+			t.Fatalf("expected %d with authorization %q, got %d", expectedCode, authorization, recorder.Code)
+		}
+
+		if expectedCode == http.StatusOK && recorder.Body.String() != "/openapi/v3" {
+			t.Fatalf("expected the request to be proxied to /openapi/v3, got %q", recorder.Body.String())
+		}
+	}
+
+	_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil))
+	if pattern != "" {
+		t.Fatalf("expected no profiles to be served, matched %q", pattern)
+	}
+}
+
+func TestDiscoveryHandlerListsResources(t *testing.T) {
+	t.Parallel()
+
+	client := &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{}}
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: []string{"get", "list"}},
+		},
+	}}
+
+	recorder := httptest.NewRecorder()
+	discoveryHandler(func(*http.Request) (discovery.DiscoveryInterface, error) {
+		return client, nil
+	}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/dev/discovery", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var listing discoveryListing
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &listing)
+	if err != nil {
+		t.Fatalf("failed to decode listing: %v", err)
+	}
+
+	if len(listing.GroupVersions) != 1 || len(listing.GroupVersions[0].Resources) != 1 {
+		t.Fatalf("unexpected listing %+v", listing)
+	}
+
+	secrets := listing.GroupVersions[0].Resources[0]
+	if secrets.Name != "secrets" || secrets.Kind != "Secret" || !secrets.Namespaced || len(secrets.Verbs) != 2 {
+		t.Fatalf("unexpected resource %+v", secrets)
+	}
+}
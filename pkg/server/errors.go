@@ -11,6 +11,8 @@ var (
 	ErrMissingOIDCConfig = errors.New("OIDC configuration is not set")
 	// ErrTimeoutWaitingForCRD indicates that the timeout waiting for CRD to be established.
 	ErrTimeoutWaitingForCRD = errors.New("timeout waiting for CRD to be established")
+	// ErrCRDsNotEstablished indicates that provider CRDs are missing or not established yet.
+	ErrCRDsNotEstablished = errors.New("provider CRDs are not established")
 	// ErrTimeoutWaitingForWebhook indicates that the timeout waiting for webhook to be ready.
 	ErrTimeoutWaitingForWebhook = errors.New("timeout waiting for webhook to be ready")
 	// ErrNoAdminGroupConfigured indicates that no admin group is configured.
@@ -29,4 +31,50 @@ var (
 	ErrNotSupportedInKommodity = errors.New("not supported in Kommodity")
 	// ErrDataMissingFromSecret indicates that expected data is missing from a secret.
 	ErrDataMissingFromSecret = errors.New("expected data missing from secret")
+	// ErrSecureServingNotConfigured indicates that secure serving must be set up before it is used.
+	ErrSecureServingNotConfigured = errors.New("secure serving is not configured")
+	// ErrUnknownMachineAction indicates that a MachineAction requests an unsupported action.
+	ErrUnknownMachineAction = errors.New("spec.action must be one of reboot, reset or upgrade")
+	// ErrMachineActionImageRequired indicates that an upgrade MachineAction has no image.
+	ErrMachineActionImageRequired = errors.New("spec.image is required for upgrades")
+	// ErrInvalidTailLines indicates that the tailLines of a machine log request is not a non-negative number.
+	ErrInvalidTailLines = errors.New("tailLines must be a non-negative number")
+	// ErrAPIServerShuttingDown cancels the controller managers when the API server shuts down.
+	ErrAPIServerShuttingDown = errors.New("API server is shutting down")
+	// ErrAPIServerShutdownTimeout indicates that the API server did not shut down in time.
+	ErrAPIServerShutdownTimeout = errors.New("API server did not shut down in time")
+	// ErrQuotaClientsNotSet indicates that the resource quota plugin was not given its clients.
+	ErrQuotaClientsNotSet = errors.New(
+		"resource quota plugin requires a kube informer factory, a dynamic client and a drained notification")
+	// ErrQuotaNotReady indicates that the resource quota plugin has not read the quotas or objects yet.
+	ErrQuotaNotReady = errors.New("resource quotas are not synced yet")
+	// ErrQuotaExceeded indicates that a create would exceed a ResourceQuota of the namespace.
+	ErrQuotaExceeded = errors.New("exceeded quota")
+	// ErrProviderCredentialsClientNotSet indicates that the provider credentials plugin was not given its client.
+	ErrProviderCredentialsClientNotSet = errors.New("provider credentials plugin requires a dynamic client")
+	// ErrCrossNamespaceCredentials indicates that an object refers to credentials in another namespace.
+	ErrCrossNamespaceCredentials = errors.New("credentials must be in the namespace of the object")
+	// ErrDuplicateProviderCredential indicates that the namespace already has a ProviderCredential for the provider.
+	ErrDuplicateProviderCredential = errors.New("namespace already has a ProviderCredential for the provider")
+	// ErrRegistryMirrorsClientNotSet indicates that the registry mirrors plugin was not given its client.
+	ErrRegistryMirrorsClientNotSet = errors.New("registry mirrors plugin requires a dynamic client")
+	// ErrMachineConfigPatchesClientNotSet indicates that the machine config patches plugin was not given its client.
+	ErrMachineConfigPatchesClientNotSet = errors.New("machine config patches plugin requires a dynamic client")
+	// ErrInvalidMachineConfigPatch indicates that a MachineConfigPatch cannot be applied to machine configurations.
+	ErrInvalidMachineConfigPatch = errors.New("invalid machine config patch")
+	// ErrInvalidKubeconfigExpiry indicates that the expiry of a kubeconfig request is not a valid duration.
+	ErrInvalidKubeconfigExpiry = errors.New("expiry must be a positive duration of at most 24h")
+	// ErrKubeconfigForSystemUser indicates that a kubeconfig was requested by a system user, whose
+	// name and groups are reserved in the workload clusters.
+	ErrKubeconfigForSystemUser = errors.New("kubeconfigs are not issued to system users")
+	// ErrKubeconfigClusterNotFound indicates that the kubeconfig secret of a Cluster has no cluster entry.
+	ErrKubeconfigClusterNotFound = errors.New("kubeconfig has no cluster for its current context")
+	// ErrInfraPlacementClientNotSet indicates that the infra placement plugin was not given its client.
+	ErrInfraPlacementClientNotSet = errors.New("infra placement plugin requires a dynamic client")
+	// ErrInvalidInfraPlacement indicates that the placement annotations of a KubevirtMachine are invalid.
+	ErrInvalidInfraPlacement = errors.New("invalid infra cluster placement")
+	// ErrNoInfraClusterAvailable indicates that no InfraCluster can take a new KubevirtMachine.
+	ErrNoInfraClusterAvailable = errors.New("no infra cluster available")
+	// ErrClusterDeletionProtected indicates that a protected Cluster was deleted without a recent confirmation.
+	ErrClusterDeletionProtected = errors.New("cluster has deletion protection")
 )
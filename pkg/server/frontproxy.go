@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+)
+
+const (
+	// frontProxyUser is the common name of the certificate the proxy of the combined
+	// server authenticates to the API server with. Only it may set X-Remote-* headers.
+	frontProxyUser = "kommodity:front-proxy"
+	// frontProxyCertValidity is the lifetime of the front proxy certificate. A new one is
+	// generated on every start, it is never persisted.
+	frontProxyCertValidity = 10 * 365 * 24 * time.Hour
+)
+
+//nolint:gochecknoglobals // Header names shared by the proxy and the API server.
+var (
+	remoteUserHeaders         = headerrequest.StaticStringSlice{"X-Remote-User"}
+	remoteGroupHeaders        = headerrequest.StaticStringSlice{"X-Remote-Group"}
+	remoteExtraHeaderPrefixes = headerrequest.StaticStringSlice{"X-Remote-Extra-"}
+)
+
+// clientCertificateUserKey holds the operator authenticated with a client certificate
+// in the context of a request to the API server proxy.
+type clientCertificateUserKey struct{}
+
+// setupFrontProxyAuth returns the authenticator of the API server for operators the
+// combined server authenticated with a client certificate. Their identity is passed on
+// in X-Remote-* headers, which are only trusted from the proxy. The proxy presents a
+// certificate generated on start, which is also the only CA trusted for it.
+func setupFrontProxyAuth(cfg *config.KommodityConfig,
+	config *genericapiserver.RecommendedConfig) (authenticator.Request, error) {
+	if config.SecureServing == nil {
+		return nil, ErrSecureServingNotConfigured
+	}
+
+	certPEM, keyPEM, err := generateFrontProxyCert()
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load front proxy certificate: %w", err)
+	}
+
+	caProvider, err := dynamiccertificates.NewStaticCAContent("front-proxy-ca", certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load front proxy CA: %w", err)
+	}
+
+	// The API server requests client certificates, loopback clients still use tokens.
+	config.SecureServing.ClientCA = caProvider
+	cfg.ClientConfig.FrontProxyCertificate = &certificate
+
+	return headerrequest.NewDynamicVerifyOptionsSecure(caProvider.VerifyOptions,
+		headerrequest.StaticStringSlice{frontProxyUser}, remoteUserHeaders, headerrequest.StaticStringSlice{},
+		remoteGroupHeaders, remoteExtraHeaderPrefixes), nil
+}
+
+// generateFrontProxyCert generates a self-signed client certificate for the proxy,
+// which is its own CA. Returns the certificate PEM and the private key PEM.
+func generateFrontProxyCert() ([]byte, []byte, error) {
+	key, err := generateRSAPrivateKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate front proxy private key: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), webhookCertSerialBits))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate certificate serial number: %w", err)
+	}
+
+	now := time.Now()
+
+	template := x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: frontProxyUser},
+		NotBefore:             now.Add(-webhookCertBackdate),
+		NotAfter:              now.Add(frontProxyCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create front proxy certificate: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), convertRSAKeyToPEM(key), nil
+}
+
+// newClientCertificateAuthenticator returns the authenticator for client certificates
+// of operators, verified against the operator CAs only. The CN names the user and O the
+// groups. Returns nil when client certificates of operators are not accepted.
+func newClientCertificateAuthenticator(operatorCAs *httpauth.OperatorCAs) authenticator.Request {
+	if operatorCAs == nil {
+		return nil
+	}
+
+	return x509request.NewDynamic(operatorCAs.VerifyOptions, x509request.CommonNameUserConversion)
+}
+
+// authenticateClientCertificate adds the operator the client certificate of a request
+// names to its context, for the proxy to pass on to the API server. Requests without a
+// client certificate of an operator are passed on as they are.
+func authenticateClientCertificate(auth authenticator.Request, next http.Handler) http.Handler {
+	if auth == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Certificates of other CAs, such as those of machines, fail verification.
+		response, ok, err := auth.AuthenticateRequest(r)
+		if err == nil && ok {
+			r = r.WithContext(context.WithValue(r.Context(), clientCertificateUserKey{}, response.User))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// frontProxyRoundTripper passes the operator authenticated with a client certificate on
+// to the API server in X-Remote-* headers. The headers callers send are always removed.
+type frontProxyRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *frontProxyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	headerrequest.ClearAuthenticationHeaders(req.Header, remoteUserHeaders, headerrequest.StaticStringSlice{},
+		remoteGroupHeaders, remoteExtraHeaderPrefixes)
+
+	if operator, found := req.Context().Value(clientCertificateUserKey{}).(user.Info); found {
+		req.Header.Set(remoteUserHeaders[0], operator.GetName())
+
+		for _, group := range operator.GetGroups() {
+			req.Header.Add(remoteGroupHeaders[0], group)
+		}
+	}
+
+	//nolint:wrapcheck // The error of the next round tripper is returned as is.
+	return rt.next.RoundTrip(req)
+}
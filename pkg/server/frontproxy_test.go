@@ -0,0 +1,230 @@
+//nolint:testpackage // white-box tests wire the proxy to a stand-in for the API server
+package server
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
+	authunion "k8s.io/apiserver/pkg/authentication/request/union"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/rest"
+)
+
+// newTestCertificate returns a certificate for the subject, signed by the parent or
+// self-signed as a CA when the parent is nil.
+func newTestCertificate(t *testing.T, subject pkix.Name, parent *tls.Certificate) *tls.Certificate {
+	t.Helper()
+
+	key, err := generateRSAPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	issuer, signer := template, any(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// newTestAPIServer stands in for the API server: it authenticates requests like the
+// API server does with a client CA file, and answers with the authenticated user.
+func newTestAPIServer(t *testing.T, cfg *config.KommodityConfig) *httptest.Server {
+	t.Helper()
+
+	recommendedConfig := &genericapiserver.RecommendedConfig{}
+	recommendedConfig.SecureServing = &genericapiserver.SecureServingInfo{}
+
+	frontProxyAuthenticator, err := setupFrontProxyAuth(cfg, recommendedConfig)
+	if err != nil {
+		t.Fatalf("failed to setup front proxy authenticator: %v", err)
+	}
+
+	auth := authunion.New(frontProxyAuthenticator, anonymousReqAuth{})
+
+	apiServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, _, err := auth.AuthenticateRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(response.User)
+	}))
+	apiServer.TLS = &tls.Config{ClientAuth: tls.RequestClientCert, MinVersion: tls.VersionTLS12}
+	apiServer.StartTLS()
+	t.Cleanup(apiServer.Close)
+
+	return apiServer
+}
+
+func TestClientCertificateUserIsAuthenticatedByAPIServer(t *testing.T) {
+	t.Parallel()
+
+	operatorCA := newTestCertificate(t, pkix.Name{CommonName: "operator-ca"}, nil)
+	machineCA := newTestCertificate(t, pkix.Name{CommonName: "machine-ca"}, nil)
+
+	tests := map[string]struct {
+		certificate   *tls.Certificate
+		header        http.Header
+		expectedUser  string
+		expectedGroup string
+	}{
+		"operator certificate": {
+			certificate:   newTestCertificate(t, pkix.Name{CommonName: "alice", Organization: []string{"admins"}}, operatorCA),
+			expectedUser:  "alice",
+			expectedGroup: "admins",
+		},
+		"machine certificate": {
+			certificate:   newTestCertificate(t, pkix.Name{CommonName: "10.0.0.5"}, machineCA),
+			expectedUser:  user.Anonymous,
+			expectedGroup: user.AllUnauthenticated,
+		},
+		"forged identity headers": {
+			header:        http.Header{"X-Remote-User": {"admin"}, "X-Remote-Group": {"system:masters"}},
+			expectedUser:  user.Anonymous,
+			expectedGroup: user.AllUnauthenticated,
+		},
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+
+	err := os.WriteFile(caFile, certificatePEM(operatorCA), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	cfg := &config.KommodityConfig{
+		AuthConfig:   &config.AuthConfig{Apply: true, ClientCAFile: caFile},
+		ClientConfig: &config.ClientConfig{},
+	}
+
+	apiServer := newTestAPIServer(t, cfg)
+
+	apiServerURL, err := url.Parse(apiServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse API server URL: %v", err)
+	}
+
+	cfg.APIServerPort, err = strconv.Atoi(apiServerURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse API server port: %v", err)
+	}
+
+	proxy, err := setupProxy(t.Context(), cfg, rest.TLSClientConfig{Insecure: true})
+	if err != nil {
+		t.Fatalf("failed to setup proxy: %v", err)
+	}
+
+	t.Cleanup(proxy.Transport.(*frontProxyRoundTripper).next.(*http.Transport).CloseIdleConnections)
+
+	operatorCAs, err := httpauth.NewOperatorCAs(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("failed to load operator CAs: %v", err)
+	}
+
+	certAuthenticator := newClientCertificateAuthenticator(operatorCAs)
+
+	// The combined server verifies client certificates against the operator and machine CAs.
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(operatorCA.Leaf)
+	clientCAs.AddCert(machineCA.Leaf)
+
+	combinedServer := httptest.NewUnstartedServer(authenticateClientCertificate(certAuthenticator, proxy))
+	combinedServer.TLS = &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	combinedServer.StartTLS()
+	t.Cleanup(combinedServer.Close)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := combinedServer.Client()
+			transport := client.Transport.(*http.Transport).Clone()
+
+			if test.certificate != nil {
+				transport.TLSClientConfig.Certificates = []tls.Certificate{*test.certificate}
+			}
+
+			t.Cleanup(transport.CloseIdleConnections)
+
+			request, err := http.NewRequestWithContext(t.Context(), http.MethodGet, combinedServer.URL+"/api", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			request.Header = test.header.Clone()
+			if request.Header == nil {
+				request.Header = http.Header{}
+			}
+
+			response, err := (&http.Client{Transport: transport}).Do(request)
+			if err != nil {
+				t.Fatalf("failed to call the combined server: %v", err)
+			}
+
+			defer func() { _ = response.Body.Close() }()
+
+			authenticated := &user.DefaultInfo{}
+
+			err = json.NewDecoder(response.Body).Decode(authenticated)
+			if err != nil {
+				t.Fatalf("failed to decode user (status %d): %v", response.StatusCode, err)
+			}
+
+			if authenticated.Name != test.expectedUser || !slices.Contains(authenticated.Groups, test.expectedGroup) {
+				t.Fatalf("expected user %s in group %s, got %s in %v",
+					test.expectedUser, test.expectedGroup, authenticated.Name, authenticated.Groups)
+			}
+		})
+	}
+}
+
+func certificatePEM(certificate *tls.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]})
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/endpoints/responsewriter"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+)
+
+// newHandlerChainBuilder returns the handler chain used by the API aggregator.
+// The aggregator is the outermost server of the delegation chain, so its filters are
+// the only ones that see every request; delegated servers are reached through their
+// unprotected handlers. Kommodity specific filters wrap the API handler and therefore
+// run after authentication, authorization and request info resolution.
+func newHandlerChainBuilder(
+	ctx context.Context,
+	cfg *config.KommodityConfig,
+	serializer runtime.NegotiatedSerializer,
+) func(http.Handler, *genericapiserver.Config) http.Handler {
+	return func(apiHandler http.Handler, genericConfig *genericapiserver.Config) http.Handler {
+		handler := withLogger(apiHandler, logging.ForModule(logging.FromContext(ctx), logging.ModuleAPI))
+		handler = withSecretReadAudit(handler, cfg.SecretReadAuditConfig, logging.FromContext(ctx),
+			newSecretReadRecorder(ctx, cfg.SecretReadAuditConfig, genericConfig.LoopbackClientConfig))
+		handler = withListLoadShedding(handler, cfg.LoadSheddingConfig, serializer, genericConfig.LongRunningFunc,
+			genericConfig.StorageObjectCountTracker)
+		handler = withPriorityQueueing(handler, cfg.PriorityQueueingConfig, serializer, genericConfig.LongRunningFunc)
+		handler = withRateLimiting(handler, cfg.RateLimitConfig, cfg.Dynamic, serializer, genericConfig.LongRunningFunc)
+		handler = withResponseCompression(handler, cfg.CompressionConfig)
+
+		return genericapiserver.BuildHandlerChainWithStorageVersionPrecondition(handler, genericConfig)
+	}
+}
+
+// withLogger makes the logger available to the handlers and the storage through the
+// request context, which the API server does not derive from the context of Kommodity. The logger
+// carries the request ID set by the combined server and the authenticated user, and
+// logs the served request at debug level.
+func withLogger(handler http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestLogger := logger
+		if requestUser, ok := request.UserFrom(r.Context()); ok {
+			requestLogger = requestLogger.With(zap.String("user", requestUser.GetName()))
+		}
+
+		ctx := logging.WithLogger(r.Context(), requestLogger)
+		if requestID := r.Header.Get(logging.RequestIDHeader); requestID != "" {
+			ctx = logging.WithRequestID(ctx, logging.RequestID(requestID))
+		}
+
+		recorder := logging.NewResponseRecorder(w)
+
+		handler.ServeHTTP(responsewriter.WrapForHTTP1Or2(recorder), r.WithContext(ctx))
+
+		logging.FromContext(ctx).Debug("Served API request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", recorder.Status()),
+			zap.Duration("latency", time.Since(start)))
+	})
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/client-go/dynamic"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// infraPlacementPluginName is the name of the admission plugin placing KubevirtMachines
+// on InfraClusters.
+const infraPlacementPluginName = "KommodityInfraPlacement"
+
+//nolint:gochecknoglobals // Read-only resources handled by the plugin.
+var (
+	infraClustersResource    = kommodityv1alpha1.GroupVersion.WithResource("infraclusters")
+	kubevirtMachinesResource = schema.GroupVersionResource{
+		Group: infrastructureGroup, Version: "v1alpha1", Resource: "kubevirtmachines",
+	}
+)
+
+// infraPlacementPlugin places new KubevirtMachines that ask for it with the infra
+// cluster selector annotation on one of the InfraClusters of their namespace, by setting
+// their spec.infraClusterSecretRef to the kubeconfig Secret of the InfraCluster. Only
+// InfraClusters matching the selector, not unschedulable and below their maxMachines
+// are considered, and the placement strategy annotation picks one of them. The
+// KubevirtMachines are labeled with the InfraCluster they were placed on, which is
+// how the machines of an InfraCluster are counted.
+type infraPlacementPlugin struct {
+	*admission.Handler
+
+	dynamicClient dynamic.Interface
+}
+
+// infraClusterUsage is an InfraCluster with the number of machines placed on it.
+type infraClusterUsage struct {
+	infraCluster kommodityv1alpha1.InfraCluster
+	// machines is the number of KubevirtMachines of the namespace placed on it.
+	machines int
+	// poolMachines is the number of those of the MachineDeployment of the placed machine.
+	poolMachines int
+}
+
+var (
+	_ admission.MutationInterface    = &infraPlacementPlugin{}
+	_ initializer.WantsDynamicClient = &infraPlacementPlugin{}
+)
+
+// registerInfraPlacementPlugin registers the plugin with the admission plugins.
+func registerInfraPlacementPlugin(plugins *admission.Plugins) {
+	plugins.Register(infraPlacementPluginName, func(io.Reader) (admission.Interface, error) {
+		return newInfraPlacementPlugin(), nil
+	})
+}
+
+func newInfraPlacementPlugin() *infraPlacementPlugin {
+	return &infraPlacementPlugin{
+		Handler: admission.NewHandler(admission.Create),
+	}
+}
+
+// SetDynamicClient sets the client the InfraClusters and KubevirtMachines are read with.
+func (p *infraPlacementPlugin) SetDynamicClient(client dynamic.Interface) {
+	p.dynamicClient = client
+}
+
+// ValidateInitialization ensures the plugin was given its client.
+func (p *infraPlacementPlugin) ValidateInitialization() error {
+	if p.dynamicClient == nil {
+		return ErrInfraPlacementClientNotSet
+	}
+
+	return nil
+}
+
+// Admit places a new KubevirtMachine with the infra cluster selector annotation and no
+// infraClusterSecretRef on an InfraCluster.
+func (p *infraPlacementPlugin) Admit(ctx context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetOperation() != admission.Create || attrs.GetSubresource() != "" ||
+		attrs.GetResource().GroupResource() != kubevirtMachinesResource.GroupResource() {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	selectorValue, found := obj.GetAnnotations()[kommodityv1alpha1.AnnotationInfraClusterSelector]
+	if !found {
+		return nil
+	}
+
+	_, found, _ = unstructured.NestedFieldNoCopy(obj.Object, "spec", "infraClusterSecretRef")
+	if found {
+		return nil
+	}
+
+	selector, err := labels.Parse(selectorValue)
+	if err != nil {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: annotation %s: %w",
+			ErrInvalidInfraPlacement, kommodityv1alpha1.AnnotationInfraClusterSelector, err))
+	}
+
+	strategy := cmp.Or(obj.GetAnnotations()[kommodityv1alpha1.AnnotationInfraPlacement],
+		kommodityv1alpha1.InfraPlacementSpread)
+	if strategy != kommodityv1alpha1.InfraPlacementSpread && strategy != kommodityv1alpha1.InfraPlacementPack {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: annotation %s must be %s or %s, not %q",
+			ErrInvalidInfraPlacement, kommodityv1alpha1.AnnotationInfraPlacement,
+			kommodityv1alpha1.InfraPlacementSpread, kommodityv1alpha1.InfraPlacementPack, strategy))
+	}
+
+	candidates, err := p.candidates(ctx, attrs.GetNamespace(), selector, obj.GetLabels())
+	if err != nil {
+		return err
+	}
+
+	placed := placeMachine(strategy, candidates)
+	if placed == nil {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: none of the %d InfraClusters matching %q has capacity left",
+			ErrNoInfraClusterAvailable, len(candidates), selectorValue))
+	}
+
+	err = unstructured.SetNestedStringMap(obj.Object, map[string]string{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"namespace":  attrs.GetNamespace(),
+		"name":       placed.Spec.SecretName,
+	}, "spec", "infraClusterSecretRef")
+	if err != nil {
+		return fmt.Errorf("failed to place KubevirtMachine %s: %w", obj.GetName(), err)
+	}
+
+	machineLabels := obj.GetLabels()
+	if machineLabels == nil {
+		machineLabels = map[string]string{}
+	}
+
+	machineLabels[kommodityv1alpha1.LabelInfraCluster] = placed.Name
+	obj.SetLabels(machineLabels)
+
+	return nil
+}
+
+// candidates returns the schedulable InfraClusters of the namespace matching the
+// selector, with the machines placed on them. The machines of the MachineDeployment of
+// the machine with the given labels are counted separately.
+func (p *infraPlacementPlugin) candidates(ctx context.Context, namespace string, selector labels.Selector,
+	machineLabels map[string]string) ([]infraClusterUsage, error) {
+	list, err := p.dynamicClient.Resource(infraClustersResource).Namespace(namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infra clusters of namespace %s: %w", namespace, err)
+	}
+
+	machines, err := p.dynamicClient.Resource(kubevirtMachinesResource).Namespace(namespace).
+		List(ctx, metav1.ListOptions{LabelSelector: kommodityv1alpha1.LabelInfraCluster})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KubevirtMachines of namespace %s: %w", namespace, err)
+	}
+
+	var candidates []infraClusterUsage
+
+	for _, item := range list.Items {
+		usage := infraClusterUsage{}
+
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &usage.infraCluster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode infra cluster %s/%s: %w", namespace, item.GetName(), err)
+		}
+
+		if usage.infraCluster.Spec.Unschedulable || !selector.Matches(labels.Set(item.GetLabels())) {
+			continue
+		}
+
+		for _, machine := range machines.Items {
+			if machine.GetLabels()[kommodityv1alpha1.LabelInfraCluster] != item.GetName() {
+				continue
+			}
+
+			usage.machines++
+
+			if samePool(machine.GetLabels(), machineLabels) {
+				usage.poolMachines++
+			}
+		}
+
+		candidates = append(candidates, usage)
+	}
+
+	return candidates, nil
+}
+
+// samePool reports whether two machines belong to the same MachineDeployment.
+func samePool(a, b map[string]string) bool {
+	deployment := b[clusterv1.MachineDeploymentNameLabel]
+
+	return deployment != "" && a[clusterv1.MachineDeploymentNameLabel] == deployment &&
+		a[clusterv1.ClusterNameLabel] == b[clusterv1.ClusterNameLabel]
+}
+
+// placeMachine returns the InfraCluster with capacity left that the strategy picks, or nil
+// if there is none. Ties are broken by name, so that placements are predictable.
+func placeMachine(strategy string, candidates []infraClusterUsage) *kommodityv1alpha1.InfraCluster {
+	var available []infraClusterUsage
+
+	for _, candidate := range candidates {
+		maxMachines := candidate.infraCluster.Spec.MaxMachines
+		if maxMachines == nil || candidate.machines < int(*maxMachines) {
+			available = append(available, candidate)
+		}
+	}
+
+	if len(available) == 0 {
+		return nil
+	}
+
+	placed := slices.MinFunc(available, func(a, b infraClusterUsage) int {
+		if strategy == kommodityv1alpha1.InfraPlacementPack {
+			return cmp.Or(cmp.Compare(b.machines, a.machines), cmp.Compare(a.infraCluster.Name, b.infraCluster.Name))
+		}
+
+		return cmp.Or(cmp.Compare(a.poolMachines, b.poolMachines), cmp.Compare(a.machines, b.machines),
+			cmp.Compare(a.infraCluster.Name, b.infraCluster.Name))
+	})
+
+	return &placed.infraCluster
+}
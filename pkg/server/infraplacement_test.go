@@ -0,0 +1,209 @@
+//nolint:testpackage // white-box tests exercise the unexported infra placement plugin
+package server
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newInfraCluster(name string, labels map[string]any, spec map[string]any) *unstructured.Unstructured {
+	spec["secretName"] = name + "-kubeconfig"
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kommodity.io/v1alpha1",
+		"kind":       "InfraCluster",
+		"metadata":   map[string]any{"name": name, "namespace": "team-a", "labels": labels},
+		"spec":       spec,
+	}}
+}
+
+// newPlacedKubevirtMachine returns a KubevirtMachine of the MachineDeployment placed on
+// the InfraCluster.
+func newPlacedKubevirtMachine(name, deploymentName, infraCluster string) *unstructured.Unstructured {
+	labels := map[string]any{
+		"cluster.x-k8s.io/cluster-name":    "prod",
+		"cluster.x-k8s.io/deployment-name": deploymentName,
+	}
+	if infraCluster != "" {
+		labels[kommodityv1alpha1.LabelInfraCluster] = infraCluster
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha1",
+		"kind":       "KubevirtMachine",
+		"metadata":   map[string]any{"name": name, "namespace": "team-a", "labels": labels},
+		"spec":       map[string]any{},
+	}}
+}
+
+func newTestInfraPlacementPlugin(t *testing.T, objects ...runtime.Object) *infraPlacementPlugin {
+	t.Helper()
+
+	plugin := newInfraPlacementPlugin()
+
+	err := plugin.ValidateInitialization()
+	if !errors.Is(err, ErrInfraPlacementClientNotSet) {
+		t.Fatalf("expected the plugin to require its client, got %v", err)
+	}
+
+	plugin.SetDynamicClient(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			infraClustersResource:    "InfraClusterList",
+			kubevirtMachinesResource: "KubevirtMachineList",
+		}, objects...))
+
+	return plugin
+}
+
+// admitKubevirtMachine places a new KubevirtMachine of the MachineDeployment with the
+// placement annotations, and returns the InfraCluster it was placed on.
+func admitKubevirtMachine(t *testing.T, plugin *infraPlacementPlugin, deploymentName string,
+	annotations map[string]string) (string, error) {
+	t.Helper()
+
+	machine := newPlacedKubevirtMachine("new", deploymentName, "")
+	machine.SetAnnotations(annotations)
+
+	attrs := admission.NewAttributesRecord(machine, nil, machine.GroupVersionKind(), "team-a", "new",
+		kubevirtMachinesResource, "", admission.Create, nil, false, nil)
+
+	err := plugin.Admit(t.Context(), attrs, nil)
+	if err != nil {
+		return "", err
+	}
+
+	placed := machine.GetLabels()[kommodityv1alpha1.LabelInfraCluster]
+
+	secretName, _, _ := unstructured.NestedString(machine.Object, "spec", "infraClusterSecretRef", "name")
+	if placed != "" && secretName != placed+"-kubeconfig" {
+		t.Fatalf("expected the machine to refer to the kubeconfig of %s, got %q", placed, secretName)
+	}
+
+	return placed, nil
+}
+
+func TestInfraPlacementPluginPlacesMachines(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestInfraPlacementPlugin(t,
+		newInfraCluster("eu-1", map[string]any{"region": "eu"}, map[string]any{"maxMachines": int64(4)}),
+		newInfraCluster("eu-2", map[string]any{"region": "eu"}, map[string]any{}),
+		newInfraCluster("eu-3", map[string]any{"region": "eu"}, map[string]any{"unschedulable": true}),
+		newInfraCluster("us-1", map[string]any{"region": "us"}, map[string]any{}),
+		newPlacedKubevirtMachine("workers-a", "workers", "eu-1"),
+		newPlacedKubevirtMachine("workers-b", "workers", "eu-2"),
+		newPlacedKubevirtMachine("workers-c", "workers", "eu-2"),
+		newPlacedKubevirtMachine("gpu-a", "gpu", "eu-1"),
+		newPlacedKubevirtMachine("gpu-b", "gpu", "eu-1"),
+	)
+
+	tests := []struct {
+		name        string
+		deployment  string
+		annotations map[string]string
+		placed      string
+	}{
+		{name: "without selector", deployment: "workers", annotations: nil, placed: ""},
+		{
+			name:        "spread over the deployment",
+			deployment:  "workers",
+			annotations: map[string]string{kommodityv1alpha1.AnnotationInfraClusterSelector: "region=eu"},
+			placed:      "eu-1",
+		},
+		{
+			name:        "spread over all machines",
+			deployment:  "storage",
+			annotations: map[string]string{kommodityv1alpha1.AnnotationInfraClusterSelector: "region=eu"},
+			placed:      "eu-2",
+		},
+		{
+			name:       "pack",
+			deployment: "workers",
+			annotations: map[string]string{
+				kommodityv1alpha1.AnnotationInfraClusterSelector: "region=eu",
+				kommodityv1alpha1.AnnotationInfraPlacement:       kommodityv1alpha1.InfraPlacementPack,
+			},
+			placed: "eu-1",
+		},
+		{
+			name:        "empty selector",
+			deployment:  "gpu",
+			annotations: map[string]string{kommodityv1alpha1.AnnotationInfraClusterSelector: ""},
+			placed:      "us-1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			placed, err := admitKubevirtMachine(t, plugin, test.deployment, test.annotations)
+			if err != nil {
+				t.Fatalf("expected the machine to be admitted, got %v", err)
+			}
+
+			if placed != test.placed {
+				t.Fatalf("expected the machine to be placed on %q, got %q", test.placed, placed)
+			}
+		})
+	}
+}
+
+func TestInfraPlacementPluginRejectsMachines(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestInfraPlacementPlugin(t,
+		newInfraCluster("eu-1", map[string]any{"region": "eu"}, map[string]any{"maxMachines": int64(1)}),
+		newPlacedKubevirtMachine("workers-a", "workers", "eu-1"),
+	)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		err         error
+	}{
+		{
+			name:        "full",
+			annotations: map[string]string{kommodityv1alpha1.AnnotationInfraClusterSelector: "region=eu"},
+			err:         ErrNoInfraClusterAvailable,
+		},
+		{
+			name:        "no match",
+			annotations: map[string]string{kommodityv1alpha1.AnnotationInfraClusterSelector: "region=us"},
+			err:         ErrNoInfraClusterAvailable,
+		},
+		{
+			name:        "invalid selector",
+			annotations: map[string]string{kommodityv1alpha1.AnnotationInfraClusterSelector: "region in eu"},
+			err:         ErrInvalidInfraPlacement,
+		},
+		{
+			name: "unknown strategy",
+			annotations: map[string]string{
+				kommodityv1alpha1.AnnotationInfraClusterSelector: "",
+				kommodityv1alpha1.AnnotationInfraPlacement:       "Random",
+			},
+			err: ErrInvalidInfraPlacement,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := admitKubevirtMachine(t, plugin, "workers", test.annotations)
+			if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), test.err.Error()) {
+				t.Fatalf("expected a forbidden error for %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	flowcontrolrequest "k8s.io/apiserver/pkg/util/flowcontrol/request"
+)
+
+const (
+	listVerb = "list"
+
+	labelSelectorParam = "labelSelector"
+	fieldSelectorParam = "fieldSelector"
+	limitParam         = "limit"
+)
+
+// listLoadShedder rejects unbounded LIST requests for large collections of configured
+// resources while the number of in-flight (non long-running) requests is above a
+// per-resource threshold. It protects the database from full-table scans during
+// incident storms, when many clients tend to relist at the same time. Collections are
+// large from the configured number of stored objects on, as periodically counted by
+// the stores; LISTs of small collections cost about as much as a GET and are served.
+type listLoadShedder struct {
+	handler      http.Handler
+	config       *config.LoadSheddingConfig
+	serializer   runtime.NegotiatedSerializer
+	longRunning  request.LongRunningRequestCheck
+	objectCounts flowcontrolrequest.StorageObjectCountTracker
+	inFlight     atomic.Int64
+}
+
+// withListLoadShedding wraps the handler with LIST load shedding. It expects the
+// request info to be resolved already, so it must be installed inside the generic
+// handler chain. If no resources are configured the handler is returned unchanged.
+// Without object counts, every collection is treated as large.
+func withListLoadShedding(
+	handler http.Handler,
+	cfg *config.LoadSheddingConfig,
+	serializer runtime.NegotiatedSerializer,
+	longRunning request.LongRunningRequestCheck,
+	objectCounts flowcontrolrequest.StorageObjectCountTracker,
+) http.Handler {
+	if cfg == nil || len(cfg.Resources) == 0 {
+		return handler
+	}
+
+	return &listLoadShedder{
+		handler:      handler,
+		config:       cfg,
+		serializer:   serializer,
+		longRunning:  longRunning,
+		objectCounts: objectCounts,
+	}
+}
+
+func (l *listLoadShedder) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	requestInfo, found := request.RequestInfoFrom(req.Context())
+	if !found || !requestInfo.IsResourceRequest {
+		l.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	if l.longRunning != nil && l.longRunning(req, requestInfo) {
+		l.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	inFlight := l.inFlight.Add(1)
+	defer l.inFlight.Add(-1)
+
+	threshold, shed := l.thresholdFor(requestInfo)
+	if shed && inFlight > int64(threshold) && isUnboundedList(req, requestInfo) && l.isLarge(requestInfo) {
+		retryAfter := int(l.config.RetryAfter.Seconds())
+		err := apierrors.NewTooManyRequests(
+			fmt.Sprintf("unbounded list of %s rejected while the server is under load, "+
+				"use a label or field selector or paginate with limit", requestInfo.Resource),
+			retryAfter,
+		)
+
+		responsewriters.ErrorNegotiated(err, l.serializer, schema.GroupVersion{
+			Group:   requestInfo.APIGroup,
+			Version: requestInfo.APIVersion,
+		}, writer, req)
+
+		return
+	}
+
+	l.handler.ServeHTTP(writer, req)
+}
+
+// thresholdFor returns the in-flight threshold configured for the requested resource,
+// looking up the group-qualified name first and the bare resource name second.
+func (l *listLoadShedder) thresholdFor(requestInfo *request.RequestInfo) (int, bool) {
+	if requestInfo.APIGroup != "" {
+		threshold, found := l.config.Resources[requestInfo.Resource+"."+requestInfo.APIGroup]
+		if found {
+			return threshold, true
+		}
+	}
+
+	threshold, found := l.config.Resources[requestInfo.Resource]
+
+	return threshold, found
+}
+
+// isLarge reports whether the requested collection holds at least the configured
+// number of objects. Collections whose count is unknown or stale, such as those of
+// resources whose stores do not count their objects, are treated as large.
+func (l *listLoadShedder) isLarge(requestInfo *request.RequestInfo) bool {
+	if l.config.MinObjects <= 0 || l.objectCounts == nil {
+		return true
+	}
+
+	count, err := l.objectCounts.Get(schema.GroupResource{
+		Group:    requestInfo.APIGroup,
+		Resource: requestInfo.Resource,
+	}.String())
+	if err != nil {
+		return true
+	}
+
+	return count >= int64(l.config.MinObjects)
+}
+
+// isUnboundedList reports whether the request lists a whole collection, i.e. it has
+// no label selector, no field selector and no page size.
+func isUnboundedList(req *http.Request, requestInfo *request.RequestInfo) bool {
+	if requestInfo.Verb != listVerb || requestInfo.Name != "" {
+		return false
+	}
+
+	query := req.URL.Query()
+	if query.Get(labelSelectorParam) != "" || query.Get(fieldSelectorParam) != "" {
+		return false
+	}
+
+	limit := query.Get(limitParam)
+
+	return limit == "" || limit == "0"
+}
@@ -0,0 +1,171 @@
+//nolint:testpackage // white-box tests exercise the unexported load shedding filter
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	flowcontrolrequest "k8s.io/apiserver/pkg/util/flowcontrol/request"
+)
+
+func newLoadSheddingRequest(target string, requestInfo *request.RequestInfo) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+
+	return req.WithContext(request.WithRequestInfo(req.Context(), requestInfo))
+}
+
+func newListRequestInfo(resource string) *request.RequestInfo {
+	return &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              listVerb,
+		APIVersion:        "v1",
+		Resource:          resource,
+	}
+}
+
+// TestListLoadSheddingRejectsUnboundedListUnderLoad holds one request in flight and
+// asserts that an unbounded LIST for a configured resource is rejected with 429 while
+// a paginated LIST for the same resource is still served.
+func TestListLoadSheddingRejectsUnboundedListUnderLoad(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+
+	release := make(chan struct{})
+	blocking := make(chan struct{})
+
+	inner := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/block" {
+			close(blocking)
+			<-release
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	handler := withListLoadShedding(inner, &config.LoadSheddingConfig{
+		Resources:  map[string]int{"events": 1},
+		RetryAfter: 7 * time.Second,
+	}, serializer.NewCodecFactory(scheme), nil, nil)
+
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Go(func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newLoadSheddingRequest("/block", &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              "get",
+			Resource:          "secrets",
+			Name:              "example",
+		}))
+	})
+
+	<-blocking
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newLoadSheddingRequest("/api/v1/events", newListRequestInfo("events")))
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+
+	if recorder.Header().Get("Retry-After") != "7" {
+		t.Errorf("expected Retry-After 7, got %q", recorder.Header().Get("Retry-After"))
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newLoadSheddingRequest("/api/v1/events?limit=500", newListRequestInfo("events")))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected paginated list to be served, got status %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newLoadSheddingRequest("/api/v1/secrets", newListRequestInfo("secrets")))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected list of unconfigured resource to be served, got status %d", recorder.Code)
+	}
+
+	close(release)
+	waitGroup.Wait()
+}
+
+func TestListLoadSheddingDisabledWithoutResources(t *testing.T) {
+	t.Parallel()
+
+	inner := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler := withListLoadShedding(inner, &config.LoadSheddingConfig{}, nil, nil, nil)
+	if _, wrapped := handler.(*listLoadShedder); wrapped {
+		t.Fatal("expected handler to be returned unchanged when no resources are configured")
+	}
+}
+
+func TestListLoadSheddingServesSmallCollections(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+
+	objectCounts := flowcontrolrequest.NewStorageObjectCountTracker()
+	objectCounts.Set("events", 10)
+	objectCounts.Set("machines.cluster.x-k8s.io", 1000)
+
+	inner := http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	// A threshold of zero puts the server under load with the shed request alone.
+	handler := withListLoadShedding(inner, &config.LoadSheddingConfig{
+		Resources:  map[string]int{"events": 0, "machines.cluster.x-k8s.io": 0, "configmaps": 0},
+		RetryAfter: time.Second,
+		MinObjects: 100,
+	}, serializer.NewCodecFactory(scheme), nil, objectCounts)
+
+	tests := map[string]struct {
+		requestInfo    *request.RequestInfo
+		expectedStatus int
+	}{
+		"small collection": {
+			requestInfo:    newListRequestInfo("events"),
+			expectedStatus: http.StatusOK,
+		},
+		"large collection": {
+			requestInfo: &request.RequestInfo{
+				IsResourceRequest: true,
+				Verb:              listVerb,
+				APIGroup:          "cluster.x-k8s.io",
+				APIVersion:        "v1beta1",
+				Resource:          "machines",
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		"collection of unknown size": {
+			requestInfo:    newListRequestInfo("configmaps"),
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, newLoadSheddingRequest("/list", test.requestInfo))
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("expected status %d, got %d", test.expectedStatus, recorder.Code)
+			}
+		})
+	}
+}
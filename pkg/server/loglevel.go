@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/go-logr/zapr"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// logLevelPath is served by the API server, so callers are authenticated and need
+	// to be allowed the non-resource URL.
+	logLevelPath = "/debug/loglevel"
+	// maxLogLevelBodySize bounds the body of log level changes.
+	maxLogLevelBodySize = 1 << 12
+)
+
+// LogLevels is the representation of the log levels served on /debug/loglevel.
+type LogLevels struct {
+	// Level is the global log level. It is kept when empty in a change.
+	Level string `json:"level,omitempty"`
+	// Modules holds the modules with a level of their own. In a change, an empty
+	// level makes the module follow the global level again.
+	Modules map[logging.Module]string `json:"modules,omitempty"`
+}
+
+// logLevelHandler shows the log levels on GET and changes them on POST.
+func logLevelHandler(levels *logging.Levels) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			err := changeLogLevels(levels, r.Body)
+			if err != nil {
+				writeStatusError(w, apierrors.NewBadRequest(err.Error()))
+
+				return
+			}
+		default:
+			w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, currentLogLevels(levels))
+	})
+}
+
+// changeLogLevels applies the change in body. The change is validated as a whole
+// before any level is changed.
+func changeLogLevels(levels *logging.Levels, body io.Reader) error {
+	change := &LogLevels{}
+
+	err := json.NewDecoder(io.LimitReader(body, maxLogLevelBodySize)).Decode(change)
+	if err != nil {
+		return fmt.Errorf("invalid log levels: %w", err)
+	}
+
+	var global *zapcore.Level
+
+	if change.Level != "" {
+		level, err := zapcore.ParseLevel(change.Level)
+		if err != nil {
+			return fmt.Errorf("invalid log level: %w", err)
+		}
+
+		global = &level
+	}
+
+	modules := make(map[logging.Module]*zapcore.Level, len(change.Modules))
+
+	for module, rawLevel := range change.Modules {
+		if !slices.Contains(logging.Modules(), module) {
+			return fmt.Errorf("%w: %q", logging.ErrUnknownModule, module)
+		}
+
+		if rawLevel == "" {
+			modules[module] = nil
+
+			continue
+		}
+
+		level, err := zapcore.ParseLevel(rawLevel)
+		if err != nil {
+			return fmt.Errorf("invalid log level of module %s: %w", module, err)
+		}
+
+		modules[module] = &level
+	}
+
+	if global != nil {
+		levels.SetLevel(*global)
+	}
+
+	for module, level := range modules {
+		_ = levels.SetModuleLevel(module, level)
+	}
+
+	return nil
+}
+
+func currentLogLevels(levels *logging.Levels) *LogLevels {
+	current := &LogLevels{
+		Level:   levels.Level().String(),
+		Modules: map[logging.Module]string{},
+	}
+
+	for _, module := range logging.Modules() {
+		level, ok := levels.ModuleLevel(module)
+		if ok {
+			current.Modules[module] = level.String()
+		}
+	}
+
+	return current
+}
+
+// registerLogLevelEndpoint serves the log levels of the logger on the API server and
+// routes the klog output of the generic API server through the logger, so that the
+// levels apply to it as well.
+func registerLogLevelEndpoint(logger *zap.Logger, genericServer *genericapiserver.GenericAPIServer) {
+	levels, ok := logging.LevelsOf(logger)
+	if !ok {
+		return
+	}
+
+	genericServer.Handler.NonGoRestfulMux.Handle(logLevelPath, logLevelHandler(levels))
+
+	klog.SetLogger(zapr.NewLogger(logging.ForModule(logger, logging.ModuleGenericServer)))
+}
@@ -0,0 +1,92 @@
+//nolint:testpackage // white-box tests exercise the unexported log level handler
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap/zapcore"
+)
+
+func serveLogLevels(t *testing.T, handler http.Handler, method, body string) (int, *LogLevels) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequestWithContext(t.Context(), method, logLevelPath,
+		strings.NewReader(body)))
+
+	if recorder.Code != http.StatusOK {
+		return recorder.Code, nil
+	}
+
+	levels := &LogLevels{}
+
+	err := json.NewDecoder(recorder.Body).Decode(levels)
+	if err != nil {
+		t.Fatalf("failed to decode log levels: %v", err)
+	}
+
+	return recorder.Code, levels
+}
+
+func TestLogLevelEndpoint(t *testing.T) {
+	t.Parallel()
+
+	levels := logging.NewLevels(zapcore.WarnLevel)
+	handler := logLevelHandler(levels)
+
+	_, current := serveLogLevels(t, handler, http.MethodGet, "")
+	if current.Level != "warn" || len(current.Modules) != 0 {
+		t.Fatalf("expected the global level only, got %+v", current)
+	}
+
+	_, current = serveLogLevels(t, handler, http.MethodPost, `{"level":"info","modules":{"kine":"debug"}}`)
+	if current.Level != "info" || current.Modules[logging.ModuleKine] != "debug" {
+		t.Fatalf("expected the levels to change, got %+v", current)
+	}
+
+	if level, ok := levels.ModuleLevel(logging.ModuleKine); !ok || level != zapcore.DebugLevel {
+		t.Fatalf("expected kine to log at debug, got %s", level)
+	}
+
+	_, current = serveLogLevels(t, handler, http.MethodPost, `{"modules":{"kine":""}}`)
+	if current.Level != "info" || len(current.Modules) != 0 {
+		t.Fatalf("expected kine to follow the global level again, got %+v", current)
+	}
+}
+
+func TestLogLevelEndpointRejectsInvalidChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		method string
+		body   string
+		code   int
+	}{
+		"invalid level":  {method: http.MethodPost, body: `{"level":"loud"}`, code: http.StatusBadRequest},
+		"unknown module": {method: http.MethodPost, body: `{"level":"debug","modules":{"etcd":"debug"}}`, code: http.StatusBadRequest},
+		"invalid body":   {method: http.MethodPost, body: `level=debug`, code: http.StatusBadRequest},
+		"other method":   {method: http.MethodDelete, code: http.StatusMethodNotAllowed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			levels := logging.NewLevels(zapcore.WarnLevel)
+
+			code, _ := serveLogLevels(t, logLevelHandler(levels), test.method, test.body)
+			if code != test.code {
+				t.Fatalf("expected status %d, got %d", test.code, code)
+			}
+
+			if levels.Level() != zapcore.WarnLevel {
+				t.Fatalf("expected a rejected change to keep the level, got %s", levels.Level())
+			}
+		})
+	}
+}
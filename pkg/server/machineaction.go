@@ -0,0 +1,205 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// machineActionKind is the kind reported by the machines/{name}/actions subresource.
+	machineActionKind = "MachineAction"
+	// machineActionsPattern is the mux pattern of the actions subresource.
+	machineActionsPattern = "/apis/cluster.x-k8s.io/v1beta1/namespaces/{namespace}/machines/{name}/actions"
+
+	// MachineActionConditionCompleted is the condition reporting the outcome of the last action.
+	MachineActionConditionCompleted = "Completed"
+)
+
+// MachineAction is the representation of the machines/{name}/actions subresource.
+type MachineAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MachineActionSpec   `json:"spec"`
+	Status MachineActionStatus `json:"status"`
+}
+
+// MachineActionSpec holds the requested action.
+type MachineActionSpec struct {
+	// Action is one of reboot, reset or upgrade.
+	Action string `json:"action"`
+	// Image is the Talos installer image of an upgrade.
+	Image string `json:"image,omitempty"`
+	// Graceful cordons and drains the node before a reset. Defaults to true.
+	Graceful *bool `json:"graceful,omitempty"`
+	// Reboot reboots the node after a reset instead of powering it off.
+	Reboot bool `json:"reboot,omitempty"`
+}
+
+// MachineActionStatus holds the observed progress of the action.
+type MachineActionStatus struct {
+	// Phase is Pending, InProgress, Completed or Failed, or one of the upgrade phases for upgrades.
+	Phase string `json:"phase,omitempty"`
+	// Error is the last error reported by the Talos API.
+	Error string `json:"error,omitempty"`
+	// Conditions hold the Completed condition of the action.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+func (h *machineSubresourceHandler) getAction(w http.ResponseWriter, r *http.Request) {
+	h.forward(w, r, http.MethodGet, nil, http.StatusOK, machineActionView)
+}
+
+func (h *machineSubresourceHandler) postAction(w http.ResponseWriter, r *http.Request) {
+	request := &MachineAction{}
+
+	err := json.NewDecoder(io.LimitReader(r.Body, maxMachineUpgradeBodySize)).Decode(request)
+	if err != nil {
+		writeStatusError(w, apierrors.NewBadRequest(fmt.Sprintf("invalid MachineAction: %v", err)))
+
+		return
+	}
+
+	annotations, err := machineActionAnnotations(&request.Spec)
+	if err != nil {
+		writeStatusError(w, apierrors.NewBadRequest(err.Error()))
+
+		return
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return
+	}
+
+	h.forward(w, r, http.MethodPatch, patch, http.StatusAccepted, machineActionView)
+}
+
+// machineActionAnnotations returns the annotations requesting the action. Upgrades are
+// requested like through the upgrade subresource and only recorded as the last action.
+func machineActionAnnotations(spec *MachineActionSpec) (map[string]any, error) {
+	annotations := map[string]any{
+		reconciler.AnnotationAction:               spec.Action,
+		reconciler.AnnotationActionPhase:          reconciler.ActionPhasePending,
+		reconciler.AnnotationActionError:          nil,
+		reconciler.AnnotationActionGraceful:       nil,
+		reconciler.AnnotationActionReboot:         nil,
+		reconciler.AnnotationActionTransitionTime: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	switch spec.Action {
+	case reconciler.MachineActionReboot:
+	case reconciler.MachineActionReset:
+		if spec.Graceful != nil && !*spec.Graceful {
+			annotations[reconciler.AnnotationActionGraceful] = strconv.FormatBool(false)
+		}
+
+		if spec.Reboot {
+			annotations[reconciler.AnnotationActionReboot] = strconv.FormatBool(true)
+		}
+	case reconciler.MachineActionUpgrade:
+		if spec.Image == "" {
+			return nil, ErrMachineActionImageRequired
+		}
+
+		for key, value := range upgradeAnnotations(spec.Image) {
+			annotations[key] = value
+		}
+
+		// The phase of an upgrade is tracked by the upgrade annotations.
+		annotations[reconciler.AnnotationActionPhase] = nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMachineAction, spec.Action)
+	}
+
+	return annotations, nil
+}
+
+func machineActionView(machine *clusterv1.Machine) any {
+	return machineActionFromMachine(machine)
+}
+
+// machineActionFromMachine builds the subresource view of a Machine.
+func machineActionFromMachine(machine *clusterv1.Machine) *MachineAction {
+	annotations := machine.Annotations
+	action := &MachineAction{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       machineActionKind,
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              machine.Name,
+			Namespace:         machine.Namespace,
+			UID:               machine.UID,
+			ResourceVersion:   machine.ResourceVersion,
+			CreationTimestamp: machine.CreationTimestamp,
+		},
+		Spec: MachineActionSpec{
+			Action: annotations[reconciler.AnnotationAction],
+			Reboot: annotations[reconciler.AnnotationActionReboot] == strconv.FormatBool(true),
+		},
+		Status: MachineActionStatus{
+			Phase: annotations[reconciler.AnnotationActionPhase],
+			Error: annotations[reconciler.AnnotationActionError],
+		},
+	}
+
+	switch action.Spec.Action {
+	case "":
+		return action
+	case reconciler.MachineActionReset:
+		graceful := annotations[reconciler.AnnotationActionGraceful] != strconv.FormatBool(false)
+		action.Spec.Graceful = &graceful
+	case reconciler.MachineActionUpgrade:
+		action.Spec.Image = annotations[reconciler.AnnotationUpgradeImage]
+		action.Status.Phase = annotations[reconciler.AnnotationUpgradePhase]
+		action.Status.Error = annotations[reconciler.AnnotationUpgradeError]
+	}
+
+	action.Status.Conditions = []metav1.Condition{machineActionCondition(machine, &action.Status)}
+
+	return action
+}
+
+// machineActionCondition reports the phase of the action as the Completed condition.
+func machineActionCondition(machine *clusterv1.Machine, status *MachineActionStatus) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               MachineActionConditionCompleted,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: machine.Generation,
+		LastTransitionTime: machine.CreationTimestamp,
+		Reason:             status.Phase,
+	}
+
+	transitionTime, err := time.Parse(time.RFC3339, machine.Annotations[reconciler.AnnotationActionTransitionTime])
+	if err == nil {
+		condition.LastTransitionTime = metav1.NewTime(transitionTime)
+	}
+
+	switch status.Phase {
+	case reconciler.ActionPhaseCompleted:
+		condition.Status = metav1.ConditionTrue
+	case reconciler.ActionPhaseFailed:
+		condition.Status = metav1.ConditionFalse
+		condition.Message = status.Error
+	case "":
+		condition.Reason = reconciler.ActionPhasePending
+	}
+
+	return condition
+}
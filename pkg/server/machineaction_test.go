@@ -0,0 +1,144 @@
+//nolint:testpackage // white-box tests exercise the unexported machine subresource handler
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestMachineActionPostPatchesAnnotations(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeMachineAPIServer(t, http.StatusOK, recorded)
+
+	recorder := serveMachineSubresource(t, target, "actions", http.MethodPost,
+		`{"spec":{"action":"reset","graceful":false,"reboot":true}}`)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorded.method != http.MethodPatch || recorded.path != testMachinePath ||
+		recorded.authorization != "Bearer caller-token" {
+		t.Fatalf("unexpected upstream request %s %s", recorded.method, recorded.path)
+	}
+
+	patch := struct {
+		Metadata struct {
+			Annotations map[string]*string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+
+	err := json.Unmarshal([]byte(recorded.body), &patch)
+	if err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+
+	annotations := patch.Metadata.Annotations
+	for key, expected := range map[string]string{
+		reconciler.AnnotationAction:         reconciler.MachineActionReset,
+		reconciler.AnnotationActionPhase:    reconciler.ActionPhasePending,
+		reconciler.AnnotationActionGraceful: "false",
+		reconciler.AnnotationActionReboot:   "true",
+	} {
+		if value := annotations[key]; value == nil || *value != expected {
+			t.Fatalf("expected %s=%s in patch, got %s", key, expected, recorded.body)
+		}
+	}
+
+	action := &MachineAction{}
+
+	err = json.NewDecoder(recorder.Body).Decode(action)
+	if err != nil || action.Kind != machineActionKind {
+		t.Fatalf("expected a MachineAction, got %s (%v)", recorder.Body.String(), err)
+	}
+}
+
+func TestMachineActionPostRejectsInvalidActions(t *testing.T) {
+	t.Parallel()
+
+	for name, body := range map[string]string{
+		"unknown action":        `{"spec":{"action":"shutdown"}}`,
+		"upgrade without image": `{"spec":{"action":"upgrade"}}`,
+		"invalid body":          `{"spec":`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recorded := &recordedRequest{}
+			target := newFakeMachineAPIServer(t, http.StatusOK, recorded)
+
+			recorder := serveMachineSubresource(t, target, "actions", http.MethodPost, body)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", recorder.Code)
+			}
+
+			if recorded.method != "" {
+				t.Fatalf("expected no upstream request, got %s", recorded.method)
+			}
+		})
+	}
+}
+
+func TestMachineActionFromMachine(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		annotations     map[string]string
+		expectedPhase   string
+		expectedStatus  metav1.ConditionStatus
+		expectedMessage string
+	}{
+		"pending reboot": {
+			annotations: map[string]string{
+				reconciler.AnnotationAction:      reconciler.MachineActionReboot,
+				reconciler.AnnotationActionPhase: reconciler.ActionPhasePending,
+			},
+			expectedPhase:  reconciler.ActionPhasePending,
+			expectedStatus: metav1.ConditionUnknown,
+		},
+		"failed reset": {
+			annotations: map[string]string{
+				reconciler.AnnotationAction:      reconciler.MachineActionReset,
+				reconciler.AnnotationActionPhase: reconciler.ActionPhaseFailed,
+				reconciler.AnnotationActionError: "connection refused",
+			},
+			expectedPhase:   reconciler.ActionPhaseFailed,
+			expectedStatus:  metav1.ConditionFalse,
+			expectedMessage: "connection refused",
+		},
+		"completed upgrade": {
+			annotations: map[string]string{
+				reconciler.AnnotationAction:       reconciler.MachineActionUpgrade,
+				reconciler.AnnotationUpgradeImage: "ghcr.io/siderolabs/installer:v1.11.0",
+				reconciler.AnnotationUpgradePhase: reconciler.UpgradePhaseCompleted,
+			},
+			expectedPhase:  reconciler.UpgradePhaseCompleted,
+			expectedStatus: metav1.ConditionTrue,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			action := machineActionFromMachine(&clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "cp-0", Annotations: test.annotations},
+			})
+
+			if action.Status.Phase != test.expectedPhase || len(action.Status.Conditions) != 1 {
+				t.Fatalf("unexpected status %+v", action.Status)
+			}
+
+			condition := action.Status.Conditions[0]
+			if condition.Status != test.expectedStatus || condition.Message != test.expectedMessage {
+				t.Fatalf("unexpected condition %+v", condition)
+			}
+		})
+	}
+}
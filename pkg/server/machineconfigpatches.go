@@ -0,0 +1,296 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/client-go/dynamic"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// machineConfigPatchesPluginName is the name of the admission plugin adding the
+// MachineConfigPatches to the Talos machine configurations.
+const machineConfigPatchesPluginName = "KommodityMachineConfigPatches"
+
+//nolint:gochecknoglobals // Read-only resources handled by the plugin.
+var (
+	machineConfigPatchesResource = kommodityv1alpha1.GroupVersion.WithResource("machineconfigpatches")
+	talosControlPlaneGroupKind   = schema.GroupKind{Group: "controlplane.cluster.x-k8s.io", Kind: "TalosControlPlane"}
+)
+
+// machineConfigPatchesPlugin adds the patches of the MachineConfigPatches selecting the
+// cluster and the machine of a new TalosConfig to its patches, before the Talos bootstrap
+// provider generates the machine configuration. As for RegistryMirrors, the
+// MachineConfigPatches of the Kommodity namespace apply to every namespace and are added
+// first, so that those of the namespace of the cluster are applied after them.
+type machineConfigPatchesPlugin struct {
+	*admission.Handler
+
+	dynamicClient dynamic.Interface
+}
+
+var (
+	_ admission.MutationInterface    = &machineConfigPatchesPlugin{}
+	_ admission.ValidationInterface  = &machineConfigPatchesPlugin{}
+	_ initializer.WantsDynamicClient = &machineConfigPatchesPlugin{}
+)
+
+// registerMachineConfigPatchesPlugin registers the plugin with the admission plugins.
+func registerMachineConfigPatchesPlugin(plugins *admission.Plugins) {
+	plugins.Register(machineConfigPatchesPluginName, func(io.Reader) (admission.Interface, error) {
+		return newMachineConfigPatchesPlugin(), nil
+	})
+}
+
+func newMachineConfigPatchesPlugin() *machineConfigPatchesPlugin {
+	return &machineConfigPatchesPlugin{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}
+}
+
+// SetDynamicClient sets the client the MachineConfigPatches, Clusters and control planes
+// are read with.
+func (p *machineConfigPatchesPlugin) SetDynamicClient(client dynamic.Interface) {
+	p.dynamicClient = client
+}
+
+// ValidateInitialization ensures the plugin was given its client.
+func (p *machineConfigPatchesPlugin) ValidateInitialization() error {
+	if p.dynamicClient == nil {
+		return ErrMachineConfigPatchesClientNotSet
+	}
+
+	return nil
+}
+
+// Admit appends the patches of the MachineConfigPatches of the cluster and machine of
+// new TalosConfigs to their strategic and JSON patches.
+func (p *machineConfigPatchesPlugin) Admit(ctx context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetOperation() != admission.Create || attrs.GetSubresource() != "" ||
+		attrs.GetResource().GroupResource() != talosConfigsResource {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	machineLabels, err := p.machineLabels(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	patches, err := p.patchesFor(ctx, attrs.GetNamespace(), machineLabels)
+	if err != nil || len(patches) == 0 {
+		return err
+	}
+
+	strategicPatches, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "strategicPatches")
+	configPatches, _, _ := unstructured.NestedSlice(obj.Object, "spec", "configPatches")
+
+	for _, patch := range patches {
+		strategicPatches = append(strategicPatches, patch.Spec.StrategicPatches...)
+
+		for _, jsonPatch := range patch.Spec.JSONPatches {
+			configPatch, err := toConfigPatch(jsonPatch)
+			if err != nil {
+				return fmt.Errorf("invalid JSON patch of machine config patch %s/%s: %w",
+					patch.Namespace, patch.Name, err)
+			}
+
+			configPatches = append(configPatches, configPatch)
+		}
+	}
+
+	err = unstructured.SetNestedStringSlice(obj.Object, strategicPatches, "spec", "strategicPatches")
+	if err == nil {
+		err = unstructured.SetNestedSlice(obj.Object, configPatches, "spec", "configPatches")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to add the machine config patches to TalosConfig %s: %w", obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// Validate rejects MachineConfigPatches whose selectors or strategic patches are invalid,
+// as they would only fail when the bootstrap provider generates a machine configuration.
+func (p *machineConfigPatchesPlugin) Validate(_ context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetSubresource() != "" ||
+		attrs.GetResource().GroupResource() != machineConfigPatchesResource.GroupResource() {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	patch := kommodityv1alpha1.MachineConfigPatch{}
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &patch)
+	if err == nil {
+		err = validateMachineConfigPatch(&patch)
+	}
+
+	if err != nil {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: %w", ErrInvalidMachineConfigPatch, err))
+	}
+
+	return nil
+}
+
+func validateMachineConfigPatch(patch *kommodityv1alpha1.MachineConfigPatch) error {
+	_, err := metav1.LabelSelectorAsSelector(&patch.Spec.ClusterSelector)
+	if err != nil {
+		return fmt.Errorf("invalid cluster selector: %w", err)
+	}
+
+	_, err = metav1.LabelSelectorAsSelector(&patch.Spec.MachineSelector)
+	if err != nil {
+		return fmt.Errorf("invalid machine selector: %w", err)
+	}
+
+	for i, strategicPatch := range patch.Spec.StrategicPatches {
+		document := map[string]any{}
+
+		err = yaml.Unmarshal([]byte(strategicPatch), &document)
+		if err != nil {
+			return fmt.Errorf("strategic patch %d is not a YAML document: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// machineLabels returns the labels of the machine a TalosConfig is created for. The
+// machine sets copy their labels to the TalosConfigs of their machines. The
+// TalosControlPlanes create theirs without labels, so those get the labels of the control
+// plane machines instead.
+func (p *machineConfigPatchesPlugin) machineLabels(ctx context.Context,
+	obj *unstructured.Unstructured) (labels.Set, error) {
+	machineLabels := labels.Set{}
+	for key, value := range obj.GetLabels() {
+		machineLabels[key] = value
+	}
+
+	for _, owner := range obj.GetOwnerReferences() {
+		ownerGroupVersion, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil || ownerGroupVersion.WithKind(owner.Kind).GroupKind() != talosControlPlaneGroupKind {
+			continue
+		}
+
+		controlPlane, err := p.dynamicClient.Resource(ownerGroupVersion.WithResource("taloscontrolplanes")).
+			Namespace(obj.GetNamespace()).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get control plane %s/%s: %w", obj.GetNamespace(), owner.Name, err)
+		}
+
+		if _, found := machineLabels[clusterv1.ClusterNameLabel]; !found {
+			machineLabels[clusterv1.ClusterNameLabel] = controlPlane.GetLabels()[clusterv1.ClusterNameLabel]
+		}
+
+		machineLabels[clusterv1.MachineControlPlaneLabel] = ""
+		machineLabels[clusterv1.MachineControlPlaneNameLabel] = owner.Name
+	}
+
+	return machineLabels, nil
+}
+
+// patchesFor returns the MachineConfigPatches of the Kommodity namespace and then of the
+// namespace that select the cluster and the machine with the given labels.
+func (p *machineConfigPatchesPlugin) patchesFor(ctx context.Context, namespace string,
+	machineLabels labels.Set) ([]kommodityv1alpha1.MachineConfigPatch, error) {
+	namespaces := []string{config.KommodityNamespace}
+	if namespace != config.KommodityNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+
+	clusterLabels, err := getClusterLabels(ctx, p.dynamicClient, namespace, machineLabels[clusterv1.ClusterNameLabel])
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []kommodityv1alpha1.MachineConfigPatch
+
+	for _, patchNamespace := range namespaces {
+		list, err := p.dynamicClient.Resource(machineConfigPatchesResource).Namespace(patchNamespace).
+			List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list machine config patches of namespace %s: %w", patchNamespace, err)
+		}
+
+		for _, item := range list.Items {
+			patch := kommodityv1alpha1.MachineConfigPatch{}
+
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &patch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode machine config patch %s/%s: %w",
+					patchNamespace, item.GetName(), err)
+			}
+
+			matches, err := selectsMachine(&patch, clusterLabels, machineLabels)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector of machine config patch %s/%s: %w",
+					patchNamespace, item.GetName(), err)
+			}
+
+			if matches {
+				selected = append(selected, patch)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// selectsMachine reports whether the MachineConfigPatch selects the cluster and the
+// machine with the given labels.
+func selectsMachine(patch *kommodityv1alpha1.MachineConfigPatch, clusterLabels,
+	machineLabels labels.Set) (bool, error) {
+	clusterSelector, err := metav1.LabelSelectorAsSelector(&patch.Spec.ClusterSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+
+	machineSelector, err := metav1.LabelSelectorAsSelector(&patch.Spec.MachineSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid machine selector: %w", err)
+	}
+
+	return clusterSelector.Matches(clusterLabels) && machineSelector.Matches(machineLabels), nil
+}
+
+// toConfigPatch converts a JSON patch operation to a configPatches entry of a TalosConfig.
+func toConfigPatch(jsonPatch kommodityv1alpha1.MachineConfigJSONPatch) (map[string]any, error) {
+	configPatch := map[string]any{"op": jsonPatch.Op, "path": jsonPatch.Path}
+
+	if jsonPatch.Value != nil {
+		var value any
+
+		err := json.Unmarshal(jsonPatch.Value.Raw, &value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the value of %s: %w", jsonPatch.Path, err)
+		}
+
+		configPatch["value"] = value
+	}
+
+	return configPatch, nil
+}
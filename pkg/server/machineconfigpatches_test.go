@@ -0,0 +1,206 @@
+//nolint:testpackage // white-box tests exercise the unexported machine config patches plugin
+package server
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const (
+	testSysctlsPatch    = "machine:\n  sysctls:\n    vm.max_map_count: \"262144\"\n"
+	testKernelArgsPatch = "machine:\n  install:\n    extraKernelArgs:\n      - iommu=pt\n"
+)
+
+func newMachineConfigPatch(namespace, name string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kommodity.io/v1alpha1",
+		"kind":       "MachineConfigPatch",
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+func newTestMachineConfigPatchesPlugin(t *testing.T, objects ...runtime.Object) *machineConfigPatchesPlugin {
+	t.Helper()
+
+	plugin := newMachineConfigPatchesPlugin()
+
+	err := plugin.ValidateInitialization()
+	if !errors.Is(err, ErrMachineConfigPatchesClientNotSet) {
+		t.Fatalf("expected the plugin to require its client, got %v", err)
+	}
+
+	// The fake client guesses the resources of the objects it is given from their kinds,
+	// which pluralizes MachineConfigPatch wrongly, so those are created instead.
+	var others []runtime.Object
+
+	patches := []*unstructured.Unstructured{}
+
+	for _, object := range objects {
+		if patch, isPatch := object.(*unstructured.Unstructured); isPatch && patch.GetKind() == "MachineConfigPatch" {
+			patches = append(patches, patch)
+		} else {
+			others = append(others, object)
+		}
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			machineConfigPatchesResource: "MachineConfigPatchList",
+			clustersResource:             "ClusterList",
+		}, others...)
+
+	for _, patch := range patches {
+		_, err = client.Resource(machineConfigPatchesResource).Namespace(patch.GetNamespace()).
+			Create(t.Context(), patch, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("failed to create machine config patch: %v", err)
+		}
+	}
+
+	plugin.SetDynamicClient(client)
+
+	return plugin
+}
+
+// newWorkerTalosConfig returns the TalosConfig a machine set creates for a machine of
+// the MachineDeployment.
+func newWorkerTalosConfig(namespace, clusterName, deploymentName string) *unstructured.Unstructured {
+	talosConfig := newTalosConfig(namespace, clusterName)
+	talosConfig.SetName(deploymentName + "-0")
+	talosConfig.SetLabels(map[string]string{
+		"cluster.x-k8s.io/cluster-name":    clusterName,
+		"cluster.x-k8s.io/deployment-name": deploymentName,
+	})
+
+	return talosConfig
+}
+
+func TestMachineConfigPatchesPluginPatchesTalosConfigs(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestMachineConfigPatchesPlugin(t,
+		newTestCluster("team-a", "prod", nil),
+		newMachineConfigPatch(config.KommodityNamespace, "site", map[string]any{
+			"strategicPatches": []any{testSysctlsPatch},
+		}),
+		newMachineConfigPatch("team-a", "gpu", map[string]any{
+			"machineSelector": map[string]any{
+				"matchLabels": map[string]any{"cluster.x-k8s.io/deployment-name": "gpu"},
+			},
+			"strategicPatches": []any{testKernelArgsPatch},
+			"jsonPatches": []any{map[string]any{
+				"op": "add", "path": "/machine/kubelet/extraArgs", "value": map[string]any{"max-pods": "250"},
+			}},
+		}),
+	)
+
+	talosConfig := newWorkerTalosConfig("team-a", "prod", "gpu")
+
+	patches := admitTalosConfig(t, plugin, talosConfig)
+	if !slices.Equal(patches, []string{testSysctlsPatch, testKernelArgsPatch}) {
+		t.Fatalf("expected the site patch before the namespace one, got %v", patches)
+	}
+
+	configPatches, _, _ := unstructured.NestedSlice(talosConfig.Object, "spec", "configPatches")
+	if len(configPatches) != 1 {
+		t.Fatalf("expected a single JSON patch, got %v", configPatches)
+	}
+
+	maxPods, _, _ := unstructured.NestedString(configPatches[0].(map[string]any), "value", "max-pods")
+	if maxPods != "250" {
+		t.Fatalf("expected the value of the JSON patch, got %v", configPatches[0])
+	}
+
+	patches = admitTalosConfig(t, plugin, newWorkerTalosConfig("team-a", "prod", "default"))
+	if !slices.Equal(patches, []string{testSysctlsPatch}) {
+		t.Fatalf("expected other machines to get the site patch only, got %v", patches)
+	}
+}
+
+// TestMachineConfigPatchesPluginSelectsControlPlanes asserts that the TalosConfigs of a
+// TalosControlPlane, which have no labels, are selected by the labels of its machines.
+func TestMachineConfigPatchesPluginSelectsControlPlanes(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestMachineConfigPatchesPlugin(t,
+		newTestCluster("team-a", "prod", map[string]any{"tier": "production"}),
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "controlplane.cluster.x-k8s.io/v1alpha3",
+			"kind":       "TalosControlPlane",
+			"metadata": map[string]any{
+				"name": "prod-cp", "namespace": "team-a",
+				"labels": map[string]any{"cluster.x-k8s.io/cluster-name": "prod"},
+			},
+		}},
+		newMachineConfigPatch("team-a", "control-planes", map[string]any{
+			"clusterSelector": map[string]any{"matchLabels": map[string]any{"tier": "production"}},
+			"machineSelector": map[string]any{"matchExpressions": []any{
+				map[string]any{"key": "cluster.x-k8s.io/control-plane", "operator": "Exists"},
+			}},
+			"strategicPatches": []any{testSysctlsPatch},
+		}),
+	)
+
+	controlPlaneConfig := newTalosConfig("team-a", "prod")
+	controlPlaneConfig.SetLabels(nil)
+	controlPlaneConfig.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3", Kind: "TalosControlPlane", Name: "prod-cp",
+	}})
+
+	patches := admitTalosConfig(t, plugin, controlPlaneConfig)
+	if !slices.Equal(patches, []string{testSysctlsPatch}) {
+		t.Fatalf("expected the control plane to be patched, got %v", patches)
+	}
+
+	patches = admitTalosConfig(t, plugin, newWorkerTalosConfig("team-a", "prod", "default"))
+	if len(patches) != 0 {
+		t.Fatalf("expected workers not to be patched, got %v", patches)
+	}
+}
+
+func TestMachineConfigPatchesPluginValidatesPatches(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestMachineConfigPatchesPlugin(t)
+
+	tests := []struct {
+		name    string
+		spec    map[string]any
+		invalid bool
+	}{
+		{name: "valid", spec: map[string]any{"strategicPatches": []any{testSysctlsPatch}}},
+		{name: "not a document", spec: map[string]any{"strategicPatches": []any{"- a list"}}, invalid: true},
+		{name: "invalid selector", spec: map[string]any{"machineSelector": map[string]any{
+			"matchExpressions": []any{map[string]any{"key": "pool", "operator": "Unknown"}},
+		}}, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			patch := newMachineConfigPatch("team-a", "patch", test.spec)
+			attrs := admission.NewAttributesRecord(patch, nil, patch.GroupVersionKind(), "team-a", "patch",
+				machineConfigPatchesResource, "", admission.Create, nil, false, nil)
+
+			err := plugin.Validate(t.Context(), attrs, nil)
+
+			invalid := apierrors.IsForbidden(err) && strings.Contains(err.Error(), ErrInvalidMachineConfigPatch.Error())
+			if test.invalid != invalid || (!invalid && err != nil) {
+				t.Fatalf("expected invalid %t, got %v", test.invalid, err)
+			}
+		})
+	}
+}
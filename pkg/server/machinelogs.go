@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"github.com/siderolabs/talos/pkg/machinery/api/common"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/constants"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// machineLogsPattern is the mux pattern of the logs endpoint.
+	machineLogsPattern = "/apis/kommodity.io/v1alpha1/namespaces/{namespace}/machines/{name}/logs"
+	// selfSubjectAccessReviewsPath is the path access reviews are created at.
+	selfSubjectAccessReviewsPath = "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+	// machineLogsSubresource is the Machine subresource users need the get verb on.
+	machineLogsSubresource = "log"
+	// defaultMachineLogService is the service whose logs are returned when none is given.
+	defaultMachineLogService = "kubelet"
+	// allMachineLogLines asks the Talos API for the whole log.
+	allMachineLogLines = -1
+)
+
+// machineLogOptions selects the logs returned by the logs endpoint.
+type machineLogOptions struct {
+	// Service is the Talos service, like kubelet, containerd or etcd.
+	Service string
+	// Follow keeps the stream open and returns new lines as they are written.
+	Follow bool
+	// TailLines limits the output to the last lines of the log, -1 returns all lines.
+	TailLines int32
+}
+
+// machineLogSource opens the log of a service on the node behind the Machine.
+type machineLogSource func(ctx context.Context, machine *clusterv1.Machine,
+	options machineLogOptions) (io.ReadCloser, error)
+
+// getLogs streams the service logs of the node behind the Machine as plain text. The
+// caller needs the get verb on machines and on the machines/log subresource.
+func (h *machineSubresourceHandler) getLogs(w http.ResponseWriter, r *http.Request) {
+	options, err := machineLogOptionsFromQuery(r)
+	if err != nil {
+		writeStatusError(w, apierrors.NewBadRequest(err.Error()))
+
+		return
+	}
+
+	if h.logs == nil {
+		writeStatusError(w, apierrors.NewServiceUnavailable("machine logs are not available"))
+
+		return
+	}
+
+	if !h.reviewAccess(w, r, "machines", machineLogsSubresource) {
+		return
+	}
+
+	machine, ok := h.fetchMachine(w, r, http.MethodGet, nil)
+	if !ok {
+		return
+	}
+
+	logger := logging.FromContext(r.Context()).With(
+		zap.String("machine", machine.Namespace+"/"+machine.Name),
+		zap.String("service", options.Service))
+
+	logs, err := h.logs(r.Context(), machine, options)
+	if err != nil {
+		logger.Error("Failed to open machine logs", zap.Error(err))
+		writeStatusError(w, apierrors.NewServiceUnavailable(fmt.Sprintf("failed to open machine logs: %v", err)))
+
+		return
+	}
+
+	defer func() { _ = logs.Close() }()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = io.Copy(&flushWriter{w: w, controller: http.NewResponseController(w)}, logs)
+	if err != nil && r.Context().Err() == nil {
+		logger.Warn("Machine log stream ended with an error", zap.Error(err))
+	}
+}
+
+// machineLogOptionsFromQuery reads the service, follow and tailLines query parameters.
+func machineLogOptionsFromQuery(r *http.Request) (machineLogOptions, error) {
+	query := r.URL.Query()
+
+	options := machineLogOptions{
+		Service:   query.Get("service"),
+		TailLines: allMachineLogLines,
+	}
+
+	if options.Service == "" {
+		options.Service = defaultMachineLogService
+	}
+
+	if follow := query.Get("follow"); follow != "" {
+		value, err := strconv.ParseBool(follow)
+		if err != nil {
+			return options, fmt.Errorf("invalid follow %q: %w", follow, err)
+		}
+
+		options.Follow = value
+	}
+
+	if tailLines := query.Get("tailLines"); tailLines != "" {
+		value, err := strconv.ParseInt(tailLines, 10, 32)
+		if err != nil || value < 0 {
+			return options, fmt.Errorf("%w: %q", ErrInvalidTailLines, tailLines)
+		}
+
+		options.TailLines = int32(value)
+	}
+
+	return options, nil
+}
+
+// reviewAccess asks the API server, with the caller's credentials, whether the caller
+// may get the subresource of the named CAPI resource. It writes the response and
+// returns false when the caller may not.
+func (h *machineSubresourceHandler) reviewAccess(w http.ResponseWriter, r *http.Request,
+	resource, subresource string) bool {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+
+	body, err := json.Marshal(&authorizationv1.SelfSubjectAccessReview{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "SelfSubjectAccessReview",
+			APIVersion: authorizationv1.SchemeGroupVersion.String(),
+		},
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "get",
+				Group:       clusterv1.GroupVersion.Group,
+				Resource:    resource,
+				Subresource: subresource,
+				Name:        name,
+			},
+		},
+	})
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return false
+	}
+
+	respBody, ok := h.do(w, r, http.MethodPost, h.target.JoinPath(selfSubjectAccessReviewsPath).String(),
+		"application/json", body, http.StatusCreated)
+	if !ok {
+		return false
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{}
+
+	err = json.Unmarshal(respBody, review)
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return false
+	}
+
+	if !review.Status.Allowed {
+		writeStatusError(w, apierrors.NewForbidden(schema.GroupResource{
+			Group:    clusterv1.GroupVersion.Group,
+			Resource: resource + "/" + subresource,
+		}, name, errors.New(review.Status.Reason))) //nolint:err113 // Reason reported by the API server.
+
+		return false
+	}
+
+	return true
+}
+
+// flushWriter flushes every write, so that followed logs reach the caller as they
+// are written.
+type flushWriter struct {
+	w          io.Writer
+	controller *http.ResponseController
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err //nolint:wrapcheck // Passed on to io.Copy.
+	}
+
+	err = f.controller.Flush()
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return n, fmt.Errorf("failed to flush logs: %w", err)
+	}
+
+	return n, nil
+}
+
+// newTalosLogSource reads logs from the Talos API of the node with the talosconfig of
+// its Cluster, through the Talos proxy for private networks.
+func newTalosLogSource(loopback *rest.Config) machineLogSource {
+	return func(ctx context.Context, machine *clusterv1.Machine, options machineLogOptions) (io.ReadCloser, error) {
+		scheme := runtime.NewScheme()
+
+		err := clientgoscheme.AddToScheme(scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build scheme: %w", err)
+		}
+
+		kubeClient, err := client.New(loopback, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+		talosClient, err := reconciler.TalosClientForMachine(ctx, kubeClient, machine)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Talos client: %w", err)
+		}
+
+		stream, err := talosClient.Logs(ctx, constants.SystemContainerdNamespace,
+			common.ContainerDriver_CONTAINERD, options.Service, options.Follow, options.TailLines)
+		if err != nil {
+			_ = talosClient.Close()
+
+			return nil, fmt.Errorf("failed to read logs of %s: %w", options.Service, err)
+		}
+
+		reader, err := talosclient.ReadStream(stream)
+		if err != nil {
+			_ = talosClient.Close()
+
+			return nil, fmt.Errorf("failed to read logs of %s: %w", options.Service, err)
+		}
+
+		return &talosLogReader{ReadCloser: reader, talosClient: talosClient}, nil
+	}
+}
+
+// talosLogReader closes the Talos client along with the log stream.
+type talosLogReader struct {
+	io.ReadCloser
+
+	talosClient *talosclient.Client
+}
+
+func (r *talosLogReader) Close() error {
+	return errors.Join(r.ReadCloser.Close(), r.talosClient.Close())
+}
@@ -0,0 +1,166 @@
+//nolint:testpackage // white-box tests exercise the unexported machine subresource handler
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const testMachineLogsPath = "/apis/kommodity.io/v1alpha1/namespaces/default/machines/cp-0/logs"
+
+// newFakeLogsAPIServer answers access reviews with allowed and returns the Machine.
+func newFakeLogsAPIServer(t *testing.T, allowed bool, reviews *[]authorizationv1.SelfSubjectAccessReview) *url.URL {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer caller-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case selfSubjectAccessReviewsPath:
+			review := authorizationv1.SelfSubjectAccessReview{}
+			_ = json.NewDecoder(r.Body).Decode(&review)
+			*reviews = append(*reviews, review)
+
+			review.Status.Allowed = allowed
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&review)
+		case testMachinePath:
+			_ = json.NewEncoder(w).Encode(&clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "cp-0", Namespace: "default"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse upstream URL: %v", err)
+	}
+
+	return target
+}
+
+func serveMachineLogs(t *testing.T, target *url.URL, query string,
+	opened *machineLogOptions) *httptest.ResponseRecorder {
+	t.Helper()
+
+	logs := func(_ context.Context, machine *clusterv1.Machine, options machineLogOptions) (io.ReadCloser, error) {
+		*opened = options
+
+		return io.NopCloser(strings.NewReader(machine.Name + ": " + options.Service + " started\n")), nil
+	}
+
+	mux := http.NewServeMux()
+	newMachineSubresourceHandler(target, http.DefaultTransport, logs).register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, testMachineLogsPath+query, nil)
+	req.Header.Set("Authorization", "Bearer caller-token")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestMachineLogsStreamsServiceLogs(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeLogsAPIServer(t, true, &reviews)
+
+	opened := machineLogOptions{}
+
+	recorder := serveMachineLogs(t, target, "?service=etcd&follow=true&tailLines=50", &opened)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder.Body.String() != "cp-0: etcd started\n" ||
+		!strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected logs %q", recorder.Body.String())
+	}
+
+	if opened != (machineLogOptions{Service: "etcd", Follow: true, TailLines: 50}) {
+		t.Fatalf("unexpected log options %+v", opened)
+	}
+
+	if len(reviews) != 1 {
+		t.Fatalf("expected one access review, got %d", len(reviews))
+	}
+
+	attributes := reviews[0].Spec.ResourceAttributes
+	if attributes == nil || attributes.Resource != "machines" || attributes.Subresource != machineLogsSubresource ||
+		attributes.Namespace != "default" || attributes.Name != "cp-0" {
+		t.Fatalf("unexpected access review %+v", attributes)
+	}
+}
+
+func TestMachineLogsDefaultsToKubelet(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeLogsAPIServer(t, true, &reviews)
+
+	opened := machineLogOptions{}
+
+	recorder := serveMachineLogs(t, target, "", &opened)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if opened != (machineLogOptions{Service: defaultMachineLogService, TailLines: allMachineLogLines}) {
+		t.Fatalf("unexpected log options %+v", opened)
+	}
+}
+
+func TestMachineLogsRequiresLogAccess(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeLogsAPIServer(t, false, &reviews)
+
+	opened := machineLogOptions{}
+
+	recorder := serveMachineLogs(t, target, "", &opened)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if opened.Service != "" {
+		t.Fatalf("expected logs not to be opened, got %+v", opened)
+	}
+}
+
+func TestMachineLogsRejectsInvalidTailLines(t *testing.T) {
+	t.Parallel()
+
+	reviews := []authorizationv1.SelfSubjectAccessReview{}
+	target := newFakeLogsAPIServer(t, true, &reviews)
+
+	opened := machineLogOptions{}
+
+	recorder := serveMachineLogs(t, target, "?tailLines=-5", &opened)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", recorder.Code)
+	}
+
+	if len(reviews) != 0 {
+		t.Fatalf("expected no access review, got %d", len(reviews))
+	}
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// machineUpgradeKind is the kind reported by the machines/{name}/upgrade subresource.
+	machineUpgradeKind = "MachineUpgrade"
+	// machinesPath is the collection path of CAPI Machines, relative to a namespace.
+	machinesPath = "/apis/cluster.x-k8s.io/v1beta1/namespaces/%s/machines/%s"
+	// machineUpgradePattern is the mux pattern of the upgrade subresource.
+	machineUpgradePattern = "/apis/cluster.x-k8s.io/v1beta1/namespaces/{namespace}/machines/{name}/upgrade"
+	// maxMachineUpgradeBodySize bounds request and upstream response bodies of the
+	// machine subresources.
+	maxMachineUpgradeBodySize = 1 << 20
+)
+
+// MachineUpgrade is the representation of the machines/{name}/upgrade subresource.
+type MachineUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MachineUpgradeSpec   `json:"spec"`
+	Status MachineUpgradeStatus `json:"status"`
+}
+
+// MachineUpgradeSpec holds the requested upgrade.
+type MachineUpgradeSpec struct {
+	// Image is the Talos installer image the node is upgraded to.
+	Image string `json:"image"`
+}
+
+// MachineUpgradeStatus holds the observed progress of the upgrade.
+type MachineUpgradeStatus struct {
+	// Phase is one of Pending, Upgrading, Completed or Failed.
+	Phase string `json:"phase,omitempty"`
+	// Error is the last error reported by the Talos API.
+	Error string `json:"error,omitempty"`
+}
+
+// machineSubresourceHandler serves the machines/{name}/upgrade and machines/{name}/actions
+// subresources. It does not talk to storage itself: every call is translated into a GET
+// or PATCH of the Machine on the API server with the caller's credentials, so
+// authentication, authorization and auditing apply as for any other Machine request.
+// Upgrades are carried out by the MachineUpgradeReconciler, other actions by the
+// MachineActionReconciler. The logs endpoint streams service logs from the node once
+// the API server allows the caller to read them.
+type machineSubresourceHandler struct {
+	target *url.URL
+	client *http.Client
+	logs   machineLogSource
+}
+
+func newMachineSubresourceHandler(target *url.URL,
+	transport http.RoundTripper, logs machineLogSource) *machineSubresourceHandler {
+	return &machineSubresourceHandler{
+		target: target,
+		client: &http.Client{Transport: transport},
+		logs:   logs,
+	}
+}
+
+// register adds the subresource routes to the mux.
+func (h *machineSubresourceHandler) register(mux *http.ServeMux) {
+	mux.HandleFunc(http.MethodGet+" "+machineUpgradePattern, h.getUpgrade)
+	mux.HandleFunc(http.MethodPost+" "+machineUpgradePattern, h.postUpgrade)
+	mux.HandleFunc(http.MethodGet+" "+machineActionsPattern, h.getAction)
+	mux.HandleFunc(http.MethodPost+" "+machineActionsPattern, h.postAction)
+	mux.HandleFunc(http.MethodGet+" "+machineLogsPattern, h.getLogs)
+}
+
+func (h *machineSubresourceHandler) getUpgrade(w http.ResponseWriter, r *http.Request) {
+	h.forward(w, r, http.MethodGet, nil, http.StatusOK, machineUpgradeView)
+}
+
+func (h *machineSubresourceHandler) postUpgrade(w http.ResponseWriter, r *http.Request) {
+	request := &MachineUpgrade{}
+
+	err := json.NewDecoder(io.LimitReader(r.Body, maxMachineUpgradeBodySize)).Decode(request)
+	if err != nil {
+		writeStatusError(w, apierrors.NewBadRequest(fmt.Sprintf("invalid MachineUpgrade: %v", err)))
+
+		return
+	}
+
+	if request.Spec.Image == "" {
+		writeStatusError(w, apierrors.NewBadRequest("spec.image is required"))
+
+		return
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": upgradeAnnotations(request.Spec.Image),
+		},
+	})
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return
+	}
+
+	h.forward(w, r, http.MethodPatch, patch, http.StatusAccepted, machineUpgradeView)
+}
+
+// upgradeAnnotations returns the annotations requesting an upgrade to the image.
+func upgradeAnnotations(image string) map[string]any {
+	return map[string]any{
+		reconciler.AnnotationUpgradeImage: image,
+		reconciler.AnnotationUpgradePhase: reconciler.UpgradePhasePending,
+		reconciler.AnnotationUpgradeError: nil,
+	}
+}
+
+// forward performs the request against the Machine and converts the returned
+// Machine with the view. Error responses are passed through unchanged.
+func (h *machineSubresourceHandler) forward(w http.ResponseWriter, r *http.Request,
+	method string, body []byte, successCode int, view func(*clusterv1.Machine) any) {
+	machine, ok := h.fetchMachine(w, r, method, body)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, successCode, view(machine))
+}
+
+// fetchMachine performs the request against the Machine and returns the Machine in the
+// response. Error responses are passed through unchanged and reported as not ok.
+func (h *machineSubresourceHandler) fetchMachine(w http.ResponseWriter, r *http.Request,
+	method string, body []byte) (*clusterv1.Machine, bool) {
+	upstreamURL := h.target.JoinPath(fmt.Sprintf(machinesPath,
+		url.PathEscape(r.PathValue("namespace")), url.PathEscape(r.PathValue("name"))))
+
+	contentType := ""
+	if method == http.MethodPatch {
+		contentType = "application/merge-patch+json"
+	}
+
+	respBody, ok := h.do(w, r, method, upstreamURL.String(), contentType, body, http.StatusOK)
+	if !ok {
+		return nil, false
+	}
+
+	machine := &clusterv1.Machine{}
+
+	err := json.Unmarshal(respBody, machine)
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return nil, false
+	}
+
+	return machine, true
+}
+
+// do performs a request against the API server with the caller's credentials and
+// returns the response body. Responses other than successCode are passed through
+// unchanged and reported as not ok.
+func (h *machineSubresourceHandler) do(w http.ResponseWriter, r *http.Request,
+	method, upstreamURL, contentType string, body []byte, successCode int) ([]byte, bool) {
+	upstreamReq, err := http.NewRequestWithContext(r.Context(), method, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return nil, false
+	}
+
+	upstreamReq.Header.Set("Accept", "application/json")
+	upstreamReq.Header.Set("Authorization", r.Header.Get("Authorization"))
+	upstreamReq.Header.Set(logging.RequestIDHeader, r.Header.Get(logging.RequestIDHeader))
+
+	if contentType != "" {
+		upstreamReq.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := h.client.Do(upstreamReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to reach API server for machine subresource", zap.Error(err))
+		writeStatusError(w, apierrors.NewServiceUnavailable("API server is not reachable"))
+
+		return nil, false
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxMachineUpgradeBodySize))
+	if err != nil {
+		writeStatusError(w, apierrors.NewInternalError(err))
+
+		return nil, false
+	}
+
+	if resp.StatusCode != successCode {
+		w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(respBody)
+
+		return nil, false
+	}
+
+	return respBody, true
+}
+
+func machineUpgradeView(machine *clusterv1.Machine) any {
+	return machineUpgradeFromMachine(machine)
+}
+
+// machineUpgradeFromMachine builds the subresource view of a Machine.
+func machineUpgradeFromMachine(machine *clusterv1.Machine) *MachineUpgrade {
+	return &MachineUpgrade{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       machineUpgradeKind,
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              machine.Name,
+			Namespace:         machine.Namespace,
+			UID:               machine.UID,
+			ResourceVersion:   machine.ResourceVersion,
+			CreationTimestamp: machine.CreationTimestamp,
+		},
+		Spec: MachineUpgradeSpec{
+			Image: machine.Annotations[reconciler.AnnotationUpgradeImage],
+		},
+		Status: MachineUpgradeStatus{
+			Phase: machine.Annotations[reconciler.AnnotationUpgradePhase],
+			Error: machine.Annotations[reconciler.AnnotationUpgradeError],
+		},
+	}
+}
+
+func writeStatusError(w http.ResponseWriter, statusErr *apierrors.StatusError) {
+	status := statusErr.Status()
+	status.TypeMeta = metav1.TypeMeta{
+		Kind:       "Status",
+		APIVersion: schema.GroupVersion{Version: "v1"}.String(),
+	}
+
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
@@ -0,0 +1,197 @@
+//nolint:testpackage // white-box tests exercise the unexported machine subresource handler
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const testMachinePath = "/apis/cluster.x-k8s.io/v1beta1/namespaces/default/machines/cp-0"
+
+type recordedRequest struct {
+	method        string
+	path          string
+	authorization string
+	contentType   string
+	body          string
+}
+
+func newFakeMachineAPIServer(t *testing.T, code int, recorded *recordedRequest) *url.URL {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*recorded = recordedRequest{
+			method:        r.Method,
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			contentType:   r.Header.Get("Content-Type"),
+			body:          string(body),
+		}
+
+		if code != http.StatusOK {
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{"kind":"Status","code":403}`))
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cp-0",
+				Namespace: "default",
+				Annotations: map[string]string{
+					reconciler.AnnotationUpgradeImage: "ghcr.io/siderolabs/installer:v1.11.0",
+					reconciler.AnnotationUpgradePhase: reconciler.UpgradePhaseFailed,
+					reconciler.AnnotationUpgradeError: "boom",
+				},
+			},
+		})
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse upstream URL: %v", err)
+	}
+
+	return target
+}
+
+func serveMachineUpgrade(t *testing.T, target *url.URL, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	return serveMachineSubresource(t, target, "upgrade", method, body)
+}
+
+func serveMachineSubresource(t *testing.T, target *url.URL,
+	subresource, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	newMachineSubresourceHandler(target, http.DefaultTransport, nil).register(mux)
+
+	req := httptest.NewRequest(method, testMachinePath+"/"+subresource, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer caller-token")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestMachineUpgradeGetReportsAnnotations(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeMachineAPIServer(t, http.StatusOK, recorded)
+
+	recorder := serveMachineUpgrade(t, target, http.MethodGet, "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorded.method != http.MethodGet || recorded.path != testMachinePath {
+		t.Fatalf("unexpected upstream request %s %s", recorded.method, recorded.path)
+	}
+
+	if recorded.authorization != "Bearer caller-token" {
+		t.Fatalf("expected caller credentials to be forwarded, got %q", recorded.authorization)
+	}
+
+	upgrade := &MachineUpgrade{}
+
+	err := json.NewDecoder(recorder.Body).Decode(upgrade)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if upgrade.Kind != machineUpgradeKind || upgrade.Name != "cp-0" {
+		t.Fatalf("unexpected object %s %s", upgrade.Kind, upgrade.Name)
+	}
+
+	if upgrade.Spec.Image != "ghcr.io/siderolabs/installer:v1.11.0" ||
+		upgrade.Status.Phase != reconciler.UpgradePhaseFailed ||
+		upgrade.Status.Error != "boom" {
+		t.Fatalf("unexpected upgrade %+v", upgrade)
+	}
+}
+
+func TestMachineUpgradePostPatchesAnnotations(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeMachineAPIServer(t, http.StatusOK, recorded)
+
+	recorder := serveMachineUpgrade(t, target, http.MethodPost,
+		`{"spec":{"image":"ghcr.io/siderolabs/installer:v1.11.0"}}`)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorded.method != http.MethodPatch || recorded.contentType != "application/merge-patch+json" {
+		t.Fatalf("unexpected upstream request %s %s", recorded.method, recorded.contentType)
+	}
+
+	patch := struct {
+		Metadata struct {
+			Annotations map[string]*string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+
+	err := json.Unmarshal([]byte(recorded.body), &patch)
+	if err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+
+	annotations := patch.Metadata.Annotations
+	if image := annotations[reconciler.AnnotationUpgradeImage]; image == nil ||
+		*image != "ghcr.io/siderolabs/installer:v1.11.0" {
+		t.Fatalf("expected image annotation in patch, got %s", recorded.body)
+	}
+
+	if phase := annotations[reconciler.AnnotationUpgradePhase]; phase == nil || *phase != reconciler.UpgradePhasePending {
+		t.Fatalf("expected pending phase in patch, got %s", recorded.body)
+	}
+
+	if upgradeError, ok := annotations[reconciler.AnnotationUpgradeError]; !ok || upgradeError != nil {
+		t.Fatalf("expected error annotation to be removed, got %s", recorded.body)
+	}
+}
+
+func TestMachineUpgradePostRequiresImage(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeMachineAPIServer(t, http.StatusOK, recorded)
+
+	recorder := serveMachineUpgrade(t, target, http.MethodPost, `{"spec":{}}`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", recorder.Code)
+	}
+
+	if recorded.method != "" {
+		t.Fatalf("expected no upstream request, got %s", recorded.method)
+	}
+}
+
+func TestMachineUpgradePassesThroughUpstreamErrors(t *testing.T) {
+	t.Parallel()
+
+	recorded := &recordedRequest{}
+	target := newFakeMachineAPIServer(t, http.StatusForbidden, recorded)
+
+	recorder := serveMachineUpgrade(t, target, http.MethodGet, "")
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", recorder.Code)
+	}
+}
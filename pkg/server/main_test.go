@@ -0,0 +1,15 @@
+//nolint:testpackage // TestMain must share the package of the white-box tests.
+package server
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m,
+		// The work queue of the watched operator CA file stops its metrics loop up to
+		// half a second after the queue is shut down.
+		goleak.IgnoreTopFunction("k8s.io/client-go/util/workqueue.(*Typed[...]).updateUnfinishedWorkLoop"))
+}
@@ -18,6 +18,7 @@ func defaultGenericAPIServicePriorities() map[schema.GroupVersion]apiServicePrio
 	return map[schema.GroupVersion]apiServicePriority{
 		{Group: "", Version: "v1"}: {Group: 18000, Version: 1},
 		// to my knowledge, nothing below here collides
+		{Group: "apps", Version: "v1"}:                               {Group: 17800, Version: 15},
 		{Group: "events.k8s.io", Version: "v1"}:                      {Group: 17750, Version: 15},
 		{Group: "events.k8s.io", Version: "v1beta1"}:                 {Group: 17750, Version: 5},
 		{Group: "authentication.k8s.io", Version: "v1"}:              {Group: 17700, Version: 15},
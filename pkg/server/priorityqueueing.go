@@ -0,0 +1,208 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// Priority levels, used as metric label values.
+	priorityLevelInteractive = "interactive"
+	priorityLevelWorkload    = "workload"
+
+	// systemUserPrefix prefixes the names of controllers, service accounts and other
+	// non-human users.
+	systemUserPrefix = "system:"
+)
+
+//nolint:gochecknoglobals // Metrics are registered once in the process wide legacy registry.
+var (
+	priorityQueueingRejected = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "priority_queueing",
+		Name:           "rejected_requests_total",
+		Help:           "Number of API requests rejected because the queue of their priority level was full or timed out.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"priority_level", "reason"})
+
+	priorityQueueingWaiting = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "priority_queueing",
+		Name:           "waiting_requests",
+		Help:           "Number of API requests waiting in the queue of their priority level.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"priority_level"})
+
+	registerPriorityQueueingMetricsOnce sync.Once
+)
+
+func registerPriorityQueueingMetrics() {
+	registerPriorityQueueingMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(priorityQueueingRejected, priorityQueueingWaiting)
+	})
+}
+
+// priorityLevel serves a bounded number of requests at a time and queues the rest in
+// arrival order. A nil priority level is unlimited.
+type priorityLevel struct {
+	name    string
+	seats   chan struct{}
+	queued  atomic.Int64
+	maxWait time.Duration
+	maxLen  int64
+}
+
+func newPriorityLevel(name string, concurrency int, cfg *config.PriorityQueueingConfig) *priorityLevel {
+	if concurrency <= 0 {
+		return nil
+	}
+
+	return &priorityLevel{
+		name:    name,
+		seats:   make(chan struct{}, concurrency),
+		maxWait: cfg.QueueTimeout,
+		maxLen:  int64(cfg.QueueLength),
+	}
+}
+
+// acquire takes a seat, waiting in the queue if none is free. It returns an empty
+// reason once the request holds a seat and the reason for rejecting it otherwise.
+func (p *priorityLevel) acquire(req *http.Request) string {
+	select {
+	case p.seats <- struct{}{}:
+		return ""
+	default:
+	}
+
+	if p.queued.Add(1) > p.maxLen {
+		p.queued.Add(-1)
+
+		return "queue-full"
+	}
+
+	priorityQueueingWaiting.WithLabelValues(p.name).Inc()
+	defer priorityQueueingWaiting.WithLabelValues(p.name).Dec()
+	defer p.queued.Add(-1)
+
+	timer := time.NewTimer(p.maxWait)
+	defer timer.Stop()
+
+	select {
+	case p.seats <- struct{}{}:
+		return ""
+	case <-timer.C:
+		return "time-out"
+	case <-req.Context().Done():
+		return "cancelled"
+	}
+}
+
+func (p *priorityLevel) release() {
+	<-p.seats
+}
+
+// priorityQueueing is a simplified take on API Priority and Fairness. Requests are
+// classified into an interactive level for human users and a workload level for
+// controllers and service accounts, each with its own concurrency limit and queue, so
+// that heavy controller traffic cannot starve kubectl and vice versa.
+type priorityQueueing struct {
+	handler     http.Handler
+	serializer  runtime.NegotiatedSerializer
+	longRunning request.LongRunningRequestCheck
+	retryAfter  int
+	interactive *priorityLevel
+	workload    *priorityLevel
+}
+
+// withPriorityQueueing wraps the handler with priority queueing. It expects the user and
+// the request info to be resolved already, so it must be installed inside the generic
+// handler chain. If neither level is limited the handler is returned unchanged.
+func withPriorityQueueing(
+	handler http.Handler,
+	cfg *config.PriorityQueueingConfig,
+	serializer runtime.NegotiatedSerializer,
+	longRunning request.LongRunningRequestCheck,
+) http.Handler {
+	if cfg == nil || (cfg.InteractiveConcurrency <= 0 && cfg.WorkloadConcurrency <= 0) {
+		return handler
+	}
+
+	registerPriorityQueueingMetrics()
+
+	return &priorityQueueing{
+		handler:     handler,
+		serializer:  serializer,
+		longRunning: longRunning,
+		retryAfter:  max(1, int(math.Ceil(cfg.QueueTimeout.Seconds()))),
+		interactive: newPriorityLevel(priorityLevelInteractive, cfg.InteractiveConcurrency, cfg),
+		workload:    newPriorityLevel(priorityLevelWorkload, cfg.WorkloadConcurrency, cfg),
+	}
+}
+
+func (p *priorityQueueing) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	requestInfo, found := request.RequestInfoFrom(req.Context())
+	if found && p.longRunning != nil && p.longRunning(req, requestInfo) {
+		p.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	level := p.interactive
+
+	requestUser, found := request.UserFrom(req.Context())
+	if !found || isWorkloadUser(requestUser) {
+		level = p.workload
+	}
+
+	if level == nil {
+		p.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	reason := level.acquire(req)
+	if reason != "" {
+		priorityQueueingRejected.WithLabelValues(level.name, reason).Inc()
+
+		err := apierrors.NewTooManyRequests(
+			fmt.Sprintf("too many requests of priority level %s, please try again later", level.name),
+			p.retryAfter,
+		)
+
+		groupVersion := schema.GroupVersion{}
+		if requestInfo != nil {
+			groupVersion = schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
+		}
+
+		responsewriters.ErrorNegotiated(err, p.serializer, groupVersion, writer, req)
+
+		return
+	}
+
+	defer level.release()
+
+	p.handler.ServeHTTP(writer, req)
+}
+
+// isWorkloadUser reports whether the user is a controller rather than a human. The
+// in-process controllers use the loopback client and are members of system:masters.
+func isWorkloadUser(requestUser user.Info) bool {
+	return strings.HasPrefix(requestUser.GetName(), systemUserPrefix) ||
+		slices.Contains(requestUser.GetGroups(), user.SystemPrivilegedGroup)
+}
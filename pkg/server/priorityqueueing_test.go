@@ -0,0 +1,156 @@
+//nolint:testpackage // white-box tests exercise the unexported priority queueing filter
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func newPriorityRequest(target string, requestUser user.Info) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+
+	ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "get",
+		APIVersion:        "v1",
+		Resource:          "secrets",
+		Name:              "example",
+	})
+
+	return req.WithContext(request.WithUser(ctx, requestUser))
+}
+
+// TestPriorityQueueingIsolatesLevels fills the interactive level and asserts that
+// workload requests are still served, that interactive requests queue until a seat is
+// free and that they are rejected with 429 once the queue is full.
+func TestPriorityQueueingIsolatesLevels(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+
+	release := make(chan struct{})
+	blocking := make(chan struct{})
+
+	inner := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/block" {
+			close(blocking)
+			<-release
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	handler := withPriorityQueueing(inner, &config.PriorityQueueingConfig{
+		InteractiveConcurrency: 1,
+		WorkloadConcurrency:    1,
+		QueueLength:            1,
+		QueueTimeout:           time.Minute,
+	}, serializer.NewCodecFactory(scheme), nil)
+
+	alice := &user.DefaultInfo{Name: "alice"}
+
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Go(func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newPriorityRequest("/block", alice))
+	})
+
+	<-blocking
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newPriorityRequest("/api", &user.DefaultInfo{Name: "system:serviceaccount:default:capi"}))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected workload request to be served, got status %d", recorder.Code)
+	}
+
+	queued := httptest.NewRecorder()
+
+	waitGroup.Go(func() {
+		handler.ServeHTTP(queued, newPriorityRequest("/api", alice))
+	})
+
+	interactive := handler.(*priorityQueueing).interactive //nolint:forcetypeassert // Always wrapped here.
+	for interactive.queued.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newPriorityRequest("/api", alice))
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d with a full queue, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+
+	if recorder.Header().Get("Retry-After") != "60" {
+		t.Fatalf("expected Retry-After 60, got %q", recorder.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	waitGroup.Wait()
+
+	if queued.Code != http.StatusOK {
+		t.Fatalf("expected queued request to be served once a seat is free, got status %d", queued.Code)
+	}
+}
+
+func TestPriorityQueueingTimesOut(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+
+	inner := http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	handler := withPriorityQueueing(inner, &config.PriorityQueueingConfig{
+		WorkloadConcurrency: 1,
+		QueueLength:         1,
+		QueueTimeout:        10 * time.Millisecond,
+	}, serializer.NewCodecFactory(scheme), nil)
+
+	// Hold the only seat of the workload level.
+	workload := handler.(*priorityQueueing).workload //nolint:forcetypeassert // Always wrapped here.
+	workload.seats <- struct{}{}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newPriorityRequest("/api", &user.DefaultInfo{
+		Name:   "system:apiserver",
+		Groups: []string{user.SystemPrivilegedGroup},
+	}))
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected queued request to time out with status %d, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newPriorityRequest("/api", &user.DefaultInfo{Name: "alice"}))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected unlimited interactive level to serve requests, got status %d", recorder.Code)
+	}
+}
+
+func TestPriorityQueueingDisabledWithoutLimits(t *testing.T) {
+	t.Parallel()
+
+	inner := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler := withPriorityQueueing(inner, &config.PriorityQueueingConfig{QueueLength: 1}, nil, nil)
+	if _, wrapped := handler.(*priorityQueueing); wrapped {
+		t.Fatal("expected handler to be returned unchanged when no level is limited")
+	}
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/client-go/dynamic"
+)
+
+// providerCredentialsPluginName is the name of the admission plugin binding provider
+// credentials to namespaces.
+const providerCredentialsPluginName = "KommodityProviderCredentials"
+
+// infrastructureGroup is the API group of the CAPI infrastructure providers.
+const infrastructureGroup = "infrastructure.cluster.x-k8s.io"
+
+//nolint:gochecknoglobals // Read-only resources handled by the plugin.
+var (
+	providerCredentialsResource      = kommodityv1alpha1.GroupVersion.WithResource("providercredentials")
+	kubevirtClustersResource         = schema.GroupResource{Group: infrastructureGroup, Resource: "kubevirtclusters"}
+	kubevirtClusterTemplatesResource = schema.GroupResource{
+		Group: infrastructureGroup, Resource: "kubevirtclustertemplates",
+	}
+	scalewayClustersResource = schema.GroupResource{Group: infrastructureGroup, Resource: "scalewayclusters"}
+)
+
+// providerCredentialsPlugin keeps tenants on the credentials of their own namespace. New
+// KubevirtClusters and ScalewayClusters that name no credentials get those of the
+// ProviderCredential of their namespace, instead of the credentials of the management
+// plane. References to credentials in another namespace are rejected, so that a tenant
+// cannot create clusters with the credentials of another one.
+type providerCredentialsPlugin struct {
+	*admission.Handler
+
+	dynamicClient dynamic.Interface
+}
+
+var (
+	_ admission.MutationInterface    = &providerCredentialsPlugin{}
+	_ admission.ValidationInterface  = &providerCredentialsPlugin{}
+	_ initializer.WantsDynamicClient = &providerCredentialsPlugin{}
+)
+
+// registerProviderCredentialsPlugin registers the plugin with the admission plugins.
+func registerProviderCredentialsPlugin(plugins *admission.Plugins) {
+	plugins.Register(providerCredentialsPluginName, func(io.Reader) (admission.Interface, error) {
+		return newProviderCredentialsPlugin(), nil
+	})
+}
+
+func newProviderCredentialsPlugin() *providerCredentialsPlugin {
+	return &providerCredentialsPlugin{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}
+}
+
+// SetDynamicClient sets the client the ProviderCredentials are read with.
+func (p *providerCredentialsPlugin) SetDynamicClient(client dynamic.Interface) {
+	p.dynamicClient = client
+}
+
+// ValidateInitialization ensures the plugin was given its client.
+func (p *providerCredentialsPlugin) ValidateInitialization() error {
+	if p.dynamicClient == nil {
+		return ErrProviderCredentialsClientNotSet
+	}
+
+	return nil
+}
+
+// Admit sets the credentials of new KubevirtClusters and ScalewayClusters that name none
+// to the Secret of the ProviderCredential of their namespace, if there is one.
+func (p *providerCredentialsPlugin) Admit(ctx context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetOperation() != admission.Create || attrs.GetSubresource() != "" {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	var (
+		provider string
+		set      func(secretName string) error
+	)
+
+	switch attrs.GetResource().GroupResource() {
+	case kubevirtClustersResource:
+		_, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "infraClusterSecretRef")
+		if found {
+			return nil
+		}
+
+		provider = kommodityv1alpha1.ProviderCredentialKubevirt
+		set = func(secretName string) error {
+			return unstructured.SetNestedStringMap(obj.Object, map[string]string{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"namespace":  attrs.GetNamespace(),
+				"name":       secretName,
+			}, "spec", "infraClusterSecretRef")
+		}
+	case scalewayClustersResource:
+		secretName, _, _ := unstructured.NestedString(obj.Object, "spec", "scalewaySecretName")
+		if secretName != "" {
+			return nil
+		}
+
+		provider = kommodityv1alpha1.ProviderCredentialScaleway
+		set = func(secretName string) error {
+			return unstructured.SetNestedField(obj.Object, secretName, "spec", "scalewaySecretName")
+		}
+	default:
+		return nil
+	}
+
+	credential, err := p.credentialFor(ctx, attrs.GetNamespace(), provider, "")
+	if err != nil || credential == nil {
+		return err
+	}
+
+	err = set(credential.Spec.SecretName)
+	if err != nil {
+		return fmt.Errorf("failed to set the credentials of %s: %w", attrs.GetResource().Resource, err)
+	}
+
+	return nil
+}
+
+// Validate rejects a second ProviderCredential for the same provider in a namespace, and
+// references to credentials in another namespace.
+func (p *providerCredentialsPlugin) Validate(ctx context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetSubresource() != "" || attrs.GetNamespace() == "" {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	credentialFrom := obj.GetAnnotations()[annotations.PerResourceSecret]
+	if namespace, _, found := strings.Cut(credentialFrom, "/"); found && namespace != attrs.GetNamespace() {
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: annotation %s refers to namespace %s",
+			ErrCrossNamespaceCredentials, annotations.PerResourceSecret, namespace))
+	}
+
+	switch attrs.GetResource().GroupResource() {
+	case providerCredentialsResource.GroupResource():
+		provider, _, _ := unstructured.NestedString(obj.Object, "spec", "provider")
+
+		existing, err := p.credentialFor(ctx, attrs.GetNamespace(), provider, obj.GetName())
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			return admission.NewForbidden(attrs, fmt.Errorf("%w: %s already binds the %s credentials",
+				ErrDuplicateProviderCredential, existing.Name, provider))
+		}
+	case kubevirtClustersResource:
+		return validateSecretRefNamespace(attrs, obj, "spec", "infraClusterSecretRef", "namespace")
+	case kubevirtClusterTemplatesResource:
+		return validateSecretRefNamespace(attrs, obj,
+			"spec", "template", "spec", "infraClusterSecretRef", "namespace")
+	}
+
+	return nil
+}
+
+// validateSecretRefNamespace rejects a Secret reference whose namespace, at the given
+// path, is not the namespace of the object.
+func validateSecretRefNamespace(attrs admission.Attributes, obj *unstructured.Unstructured,
+	path ...string) error {
+	namespace, _, _ := unstructured.NestedString(obj.Object, path...)
+	if namespace == "" || namespace == attrs.GetNamespace() {
+		return nil
+	}
+
+	return admission.NewForbidden(attrs, fmt.Errorf("%w: %s refers to namespace %s",
+		ErrCrossNamespaceCredentials, strings.Join(path, "."), namespace))
+}
+
+// credentialFor returns the ProviderCredential of the provider in the namespace, other
+// than the one named except, or nil if there is none.
+func (p *providerCredentialsPlugin) credentialFor(ctx context.Context, namespace, provider,
+	except string) (*kommodityv1alpha1.ProviderCredential, error) {
+	list, err := p.dynamicClient.Resource(providerCredentialsResource).Namespace(namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider credentials of namespace %s: %w", namespace, err)
+	}
+
+	for _, item := range list.Items {
+		credential := &kommodityv1alpha1.ProviderCredential{}
+
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, credential)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode provider credential %s/%s: %w", namespace, item.GetName(), err)
+		}
+
+		if credential.Spec.Provider == provider && credential.Name != except {
+			return credential, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil // No ProviderCredential binds the provider.
+}
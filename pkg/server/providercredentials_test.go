@@ -0,0 +1,174 @@
+//nolint:testpackage // white-box tests exercise the unexported provider credentials plugin
+package server
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newProviderCredential(namespace, name, provider, secretName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kommodity.io/v1alpha1",
+		"kind":       "ProviderCredential",
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"spec":       map[string]any{"provider": provider, "secretName": secretName},
+	}}
+}
+
+func newInfraObject(kind, namespace, name string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+func newTestProviderCredentialsPlugin(t *testing.T, objects ...runtime.Object) *providerCredentialsPlugin {
+	t.Helper()
+
+	plugin := newProviderCredentialsPlugin()
+
+	err := plugin.ValidateInitialization()
+	if !errors.Is(err, ErrProviderCredentialsClientNotSet) {
+		t.Fatalf("expected the plugin to require its client, got %v", err)
+	}
+
+	plugin.SetDynamicClient(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{providerCredentialsResource: "ProviderCredentialList"}, objects...))
+
+	err = plugin.ValidateInitialization()
+	if err != nil {
+		t.Fatalf("expected the plugin to be initialized, got %v", err)
+	}
+
+	return plugin
+}
+
+func newCredentialAttributes(obj *unstructured.Unstructured, resource schema.GroupResource,
+	operation admission.Operation) admission.Attributes {
+	return admission.NewAttributesRecord(obj, nil, obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(),
+		resource.WithVersion("v1alpha1"), "", operation, nil, false, nil)
+}
+
+func TestProviderCredentialsPluginDefaultsCredentials(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestProviderCredentialsPlugin(t,
+		newProviderCredential("team-a", "kubevirt", "kubevirt", "infra-kubeconfig"),
+		newProviderCredential("team-a", "scaleway", "scaleway", "scw-credentials"),
+	)
+
+	kubevirtCluster := newInfraObject("KubevirtCluster", "team-a", "cluster", map[string]any{})
+
+	err := plugin.Admit(t.Context(),
+		newCredentialAttributes(kubevirtCluster, kubevirtClustersResource, admission.Create), nil)
+	if err != nil {
+		t.Fatalf("expected the KubevirtCluster to be admitted, got %v", err)
+	}
+
+	ref, _, _ := unstructured.NestedStringMap(kubevirtCluster.Object, "spec", "infraClusterSecretRef")
+	if ref["name"] != "infra-kubeconfig" || ref["namespace"] != "team-a" {
+		t.Fatalf("expected the infra cluster secret of team-a, got %v", ref)
+	}
+
+	scalewayCluster := newInfraObject("ScalewayCluster", "team-a", "cluster",
+		map[string]any{"region": "fr-par"})
+
+	err = plugin.Admit(t.Context(),
+		newCredentialAttributes(scalewayCluster, scalewayClustersResource, admission.Create), nil)
+	if err != nil {
+		t.Fatalf("expected the ScalewayCluster to be admitted, got %v", err)
+	}
+
+	secretName, _, _ := unstructured.NestedString(scalewayCluster.Object, "spec", "scalewaySecretName")
+	if secretName != "scw-credentials" {
+		t.Fatalf("expected the scaleway secret of team-a, got %q", secretName)
+	}
+
+	otherCluster := newInfraObject("KubevirtCluster", "team-b", "cluster", map[string]any{})
+
+	err = plugin.Admit(t.Context(),
+		newCredentialAttributes(otherCluster, kubevirtClustersResource, admission.Create), nil)
+	if err != nil {
+		t.Fatalf("expected the KubevirtCluster to be admitted, got %v", err)
+	}
+
+	_, found, _ := unstructured.NestedFieldNoCopy(otherCluster.Object, "spec", "infraClusterSecretRef")
+	if found {
+		t.Fatal("expected namespaces without a ProviderCredential to keep the management plane")
+	}
+}
+
+func TestProviderCredentialsPluginRejectsCrossNamespaceReferences(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestProviderCredentialsPlugin(t)
+
+	tests := map[string]admission.Attributes{
+		"KubevirtCluster": newCredentialAttributes(newInfraObject("KubevirtCluster", "team-a", "cluster",
+			map[string]any{"infraClusterSecretRef": map[string]any{"name": "infra", "namespace": "team-b"}}),
+			kubevirtClustersResource, admission.Create),
+		"KubevirtClusterTemplate": newCredentialAttributes(newInfraObject("KubevirtClusterTemplate", "team-a",
+			"template", map[string]any{"template": map[string]any{"spec": map[string]any{
+				"infraClusterSecretRef": map[string]any{"name": "infra", "namespace": "team-b"},
+			}}}), kubevirtClusterTemplatesResource, admission.Update),
+	}
+
+	annotated := newInfraObject("AzureCluster", "team-a", "cluster", map[string]any{})
+	annotated.SetAnnotations(map[string]string{annotations.PerResourceSecret: "team-b/aso-secret"})
+	tests["credential-from annotation"] = newCredentialAttributes(annotated,
+		schema.GroupResource{Group: infrastructureGroup, Resource: "azureclusters"}, admission.Create)
+
+	for name, attrs := range tests {
+		err := plugin.Validate(t.Context(), attrs, nil)
+		// Forbidden errors only carry the message of the error they are built from.
+		if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), ErrCrossNamespaceCredentials.Error()) {
+			t.Fatalf("expected the %s to be rejected, got %v", name, err)
+		}
+	}
+
+	local := newInfraObject("KubevirtCluster", "team-a", "cluster",
+		map[string]any{"infraClusterSecretRef": map[string]any{"name": "infra", "namespace": "team-a"}})
+
+	err := plugin.Validate(t.Context(), newCredentialAttributes(local, kubevirtClustersResource, admission.Create), nil)
+	if err != nil {
+		t.Fatalf("expected references to the own namespace to be allowed, got %v", err)
+	}
+}
+
+func TestProviderCredentialsPluginRejectsDuplicateProviders(t *testing.T) {
+	t.Parallel()
+
+	existing := newProviderCredential("team-a", "kubevirt", "kubevirt", "infra-kubeconfig")
+	plugin := newTestProviderCredentialsPlugin(t, existing)
+
+	err := plugin.Validate(t.Context(), newCredentialAttributes(
+		newProviderCredential("team-a", "second", "kubevirt", "other-kubeconfig"),
+		providerCredentialsResource.GroupResource(), admission.Create), nil)
+	if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), ErrDuplicateProviderCredential.Error()) {
+		t.Fatalf("expected the second kubevirt credential to be rejected, got %v", err)
+	}
+
+	err = plugin.Validate(t.Context(), newCredentialAttributes(existing,
+		providerCredentialsResource.GroupResource(), admission.Update), nil)
+	if err != nil {
+		t.Fatalf("expected the existing credential to be updatable, got %v", err)
+	}
+
+	err = plugin.Validate(t.Context(), newCredentialAttributes(
+		newProviderCredential("team-b", "kubevirt", "kubevirt", "infra-kubeconfig"),
+		providerCredentialsResource.GroupResource(), admission.Create), nil)
+	if err != nil {
+		t.Fatalf("expected other namespaces to be independent, got %v", err)
+	}
+}
@@ -12,20 +12,32 @@ import (
 
 	"github.com/kommodity-io/kommodity/pkg/combinedserver"
 	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/httpauth"
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	"go.uber.org/zap"
 	"k8s.io/client-go/rest"
 )
 
-// NewHTTPMuxFactory creates a new HTTP mux proxy factory for the API server.
-func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combinedserver.HTTPMuxFactory {
-	return func(mux *http.ServeMux) error {
+// NewHTTPMuxFactory creates a new HTTP mux proxy factory for the API server. The API
+// server runs until the context is cancelled; the returned function waits for it to
+// shut down, or for the context it is given to be done. Operators presenting a client
+// certificate of the operator CAs, which may be nil, are passed on to the API server.
+//
+//nolint:lll // Not possible to shorten the signature
+func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig, operatorCAs *httpauth.OperatorCAs) (combinedserver.HTTPMuxFactory, func(context.Context) error) {
+	stopped := make(chan struct{})
+
+	factory := func(mux *http.ServeMux) error {
 		server, err := New(ctx, cfg)
 		if err != nil {
+			close(stopped)
+
 			return fmt.Errorf("failed to create server: %w", err)
 		}
 
 		go func() {
+			defer close(stopped)
+
 			logger := logging.FromContext(ctx)
 
 			runCtx, cancel := context.WithCancelCause(ctx)
@@ -36,6 +48,8 @@ func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combine
 				errorMsg := "failed to prepare generic server:"
 				logger.Error(errorMsg, zap.Error(err))
 				cancel(fmt.Errorf("%s %w", errorMsg, err))
+
+				return
 			}
 
 			err = preparedGenericServer.Run(runCtx)
@@ -52,21 +66,52 @@ func NewHTTPMuxFactory(ctx context.Context, cfg *config.KommodityConfig) combine
 			return fmt.Errorf("failed to setup proxy: %w", err)
 		}
 
-		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			proxy.ServeHTTP(w, r)
-		})
+		target, err := apiServerURL(cfg)
+		if err != nil {
+			return err
+		}
+
+		certAuthenticator := newClientCertificateAuthenticator(operatorCAs)
+
+		// Every path of the API server is served behind the client certificate check.
+		apiMux := http.NewServeMux()
+
+		newMachineSubresourceHandler(target, proxy.Transport,
+			newTalosLogSource(server.GenericAPIServer.LoopbackClientConfig)).register(apiMux)
+
+		newClusterSubresourceHandler(target, proxy.Transport,
+			newClusterCAKubeconfigIssuer(server.GenericAPIServer.LoopbackClientConfig),
+			newEventRecorder(ctx, server.GenericAPIServer.LoopbackClientConfig,
+				kubeconfigAuditComponent)).register(apiMux)
+
+		registerDevelopmentEndpoints(ctx, apiMux, cfg, target, proxy.Transport)
+
+		apiMux.Handle("/", proxy)
+
+		mux.Handle("/", authenticateClientCertificate(certAuthenticator, apiMux))
 
 		return nil
 	}
+
+	wait := func(waitCtx context.Context) error {
+		select {
+		case <-stopped:
+			return nil
+		case <-waitCtx.Done():
+			return fmt.Errorf("%w: %w", ErrAPIServerShutdownTimeout, context.Cause(waitCtx))
+		}
+	}
+
+	return factory, wait
 }
 
 func setupProxy(ctx context.Context,
 	cfg *config.KommodityConfig,
 	tlsClient rest.TLSClientConfig) (*httputil.ReverseProxy, error) {
 	// Target backend URL (where the proxy will forward requests)
-	target, err := url.Parse("https://localhost:" + strconv.Itoa(cfg.APIServerPort))
+	target, err := apiServerURL(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse target URL: %w", err)
+		return nil, err
 	}
 
 	// Create the reverse proxy
@@ -77,8 +122,16 @@ func setupProxy(ctx context.Context,
 		return nil, fmt.Errorf("failed to create TLS config: %w", err)
 	}
 
-	proxy.Transport = &http.Transport{
-		TLSClientConfig: tlsConfig,
+	// The proxy presents its certificate, so that the API server trusts the identity
+	// of operators authenticated with a client certificate it passes on.
+	if cfg.ClientConfig.FrontProxyCertificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*cfg.ClientConfig.FrontProxyCertificate}
+	}
+
+	proxy.Transport = &frontProxyRoundTripper{
+		next: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
 	}
 
 	proxy.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, e error) {
@@ -90,6 +143,16 @@ func setupProxy(ctx context.Context,
 	return proxy, nil
 }
 
+// apiServerURL returns the loopback URL of the API server behind the proxy.
+func apiServerURL(cfg *config.KommodityConfig) (*url.URL, error) {
+	target, err := url.Parse("https://localhost:" + strconv.Itoa(cfg.APIServerPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target URL: %w", err)
+	}
+
+	return target, nil
+}
+
 func tlsConfigFromREST(restTLS rest.TLSClientConfig) (*tls.Config, error) {
 	rootCAs := x509.NewCertPool()
 	if len(restTLS.CAData) > 0 {
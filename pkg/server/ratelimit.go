@@ -0,0 +1,269 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// rateLimiterCacheSize bounds the number of token buckets kept per kind of limit.
+	rateLimiterCacheSize = 4096
+
+	// rateLimiterIdleTTL is how long the token bucket of an idle client is kept. A
+	// bucket that is evicted is recreated full, so the TTL must exceed the time it
+	// takes to refill a bucket at any sensible QPS.
+	rateLimiterIdleTTL = 10 * time.Minute
+
+	// Kinds of rate limits, used as metric label values.
+	rateLimitKindUser = "user"
+	rateLimitKindIP   = "ip"
+)
+
+//nolint:gochecknoglobals // Metrics are registered once in the process wide legacy registry.
+var (
+	rateLimitedRequests = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kommodity",
+		Subsystem:      "rate_limiter",
+		Name:           "throttled_requests_total",
+		Help:           "Number of API requests rejected because a client exceeded its rate limit.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"kind"})
+
+	registerRateLimitMetricsOnce sync.Once
+)
+
+func registerRateLimitMetrics() {
+	registerRateLimitMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(rateLimitedRequests)
+	})
+}
+
+// limiterSet hands out one token bucket per key, forgetting buckets of idle keys.
+type limiterSet struct {
+	qps   rate.Limit
+	burst int
+
+	lock     sync.Mutex
+	limiters *cache.LRUExpireCache
+}
+
+func newLimiterSet(qps float64, burst int) *limiterSet {
+	if qps <= 0 {
+		return nil
+	}
+
+	return &limiterSet{
+		qps:      rate.Limit(qps),
+		burst:    burst,
+		limiters: cache.NewLRUExpireCache(rateLimiterCacheSize),
+	}
+}
+
+func (s *limiterSet) get(key string) *rate.Limiter {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	limiter, found := s.limiters.Get(key)
+	if !found {
+		limiter = rate.NewLimiter(s.qps, s.burst)
+	}
+
+	// Adding the bucket again on every request slides its expiry.
+	s.limiters.Add(key, limiter, rateLimiterIdleTTL)
+
+	return limiter.(*rate.Limiter) //nolint:forcetypeassert // Only limiters are added.
+}
+
+// rateLimiter throttles API requests with a token bucket per source IP and one per
+// authenticated user. It keeps misbehaving clients, typically controllers stuck in a
+// hot loop, from saturating the database for everybody else.
+type rateLimiter struct {
+	handler     http.Handler
+	serializer  runtime.NegotiatedSerializer
+	longRunning request.LongRunningRequestCheck
+	limits      atomic.Pointer[rateLimits]
+}
+
+// rateLimits are the token buckets of the configured limits. They are replaced as a
+// whole when the limits change.
+type rateLimits struct {
+	config config.RateLimitConfig
+	users  *limiterSet
+	ips    *limiterSet
+}
+
+// withRateLimiting wraps the handler with per-user and per-IP rate limiting. It expects
+// the user and the request info to be resolved already, so it must be installed inside
+// the generic handler chain. With a dynamic configuration the limits follow its
+// tunables. Otherwise, if neither limit is configured the handler is returned
+// unchanged.
+func withRateLimiting(
+	handler http.Handler,
+	cfg *config.RateLimitConfig,
+	dynamic *config.DynamicConfig,
+	serializer runtime.NegotiatedSerializer,
+	longRunning request.LongRunningRequestCheck,
+) http.Handler {
+	if dynamic == nil && (cfg == nil || (cfg.UserQPS <= 0 && cfg.IPQPS <= 0)) {
+		return handler
+	}
+
+	registerRateLimitMetrics()
+
+	limiter := &rateLimiter{
+		handler:     handler,
+		serializer:  serializer,
+		longRunning: longRunning,
+	}
+
+	if dynamic == nil {
+		limiter.setLimits(*cfg)
+
+		return limiter
+	}
+
+	dynamic.Subscribe(func(tunables config.Tunables) {
+		limiter.setLimits(tunables.RateLimit)
+	})
+
+	return limiter
+}
+
+// setLimits replaces the token buckets if the limits changed. Clients start over with
+// full buckets.
+func (l *rateLimiter) setLimits(cfg config.RateLimitConfig) {
+	if current := l.limits.Load(); current != nil && current.config == cfg {
+		return
+	}
+
+	l.limits.Store(&rateLimits{
+		config: cfg,
+		users:  newLimiterSet(cfg.UserQPS, cfg.UserBurst),
+		ips:    newLimiterSet(cfg.IPQPS, cfg.IPBurst),
+	})
+}
+
+func (l *rateLimiter) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	limits := l.limits.Load()
+	if limits.users == nil && limits.ips == nil {
+		l.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	requestInfo, found := request.RequestInfoFrom(req.Context())
+	if found && l.longRunning != nil && l.longRunning(req, requestInfo) {
+		l.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	requestUser, found := request.UserFrom(req.Context())
+	if found && isPrivilegedUser(requestUser) {
+		l.handler.ServeHTTP(writer, req)
+
+		return
+	}
+
+	ipReservation, allowed := l.reserve(writer, req, requestInfo, limits.ips, rateLimitKindIP, sourceIP(req))
+	if !allowed {
+		return
+	}
+
+	if found {
+		_, allowed = l.reserve(writer, req, requestInfo, limits.users, rateLimitKindUser, requestUser.GetName())
+		if !allowed {
+			// Return the token of the IP bucket, the request is not going to be served.
+			if ipReservation != nil {
+				ipReservation.Cancel()
+			}
+
+			return
+		}
+	}
+
+	l.handler.ServeHTTP(writer, req)
+}
+
+// reserve takes a token from the bucket of the key. If the bucket is empty the request
+// is rejected with 429 and a Retry-After of the time until the next token is available.
+func (l *rateLimiter) reserve(writer http.ResponseWriter, req *http.Request, requestInfo *request.RequestInfo,
+	limiters *limiterSet, kind, key string) (*rate.Reservation, bool) {
+	if limiters == nil {
+		return nil, true
+	}
+
+	reservation := limiters.get(key).Reserve()
+
+	delay := reservation.Delay()
+	if delay == 0 {
+		return reservation, true
+	}
+
+	reservation.Cancel()
+	rateLimitedRequests.WithLabelValues(kind).Inc()
+	l.reject(writer, req, requestInfo, kind, delay)
+
+	return nil, false
+}
+
+func (l *rateLimiter) reject(writer http.ResponseWriter, req *http.Request,
+	requestInfo *request.RequestInfo, kind string, delay time.Duration) {
+	retryAfter := max(1, int(math.Ceil(delay.Seconds())))
+	err := apierrors.NewTooManyRequests(fmt.Sprintf("%s rate limit exceeded", kind), retryAfter)
+
+	groupVersion := schema.GroupVersion{}
+	if requestInfo != nil {
+		groupVersion = schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
+	}
+
+	responsewriters.ErrorNegotiated(err, l.serializer, groupVersion, writer, req)
+}
+
+// isPrivilegedUser reports whether the user is exempt from rate limiting. Members of
+// system:masters are the in-process controllers and the loopback client of Kommodity.
+func isPrivilegedUser(requestUser user.Info) bool {
+	return slices.Contains(requestUser.GetGroups(), user.SystemPrivilegedGroup)
+}
+
+// sourceIP returns the address of the client. Requests to the combined server reach
+// the API server through a reverse proxy on the loopback interface, in which case the
+// client address is the last entry the proxy appended to X-Forwarded-For.
+func sourceIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	forwardedFor := req.Header.Get("X-Forwarded-For")
+	if forwardedFor == "" {
+		return host
+	}
+
+	entries := strings.Split(forwardedFor, ",")
+
+	return strings.TrimSpace(entries[len(entries)-1])
+}
@@ -0,0 +1,181 @@
+//nolint:testpackage // white-box tests exercise the unexported rate limiting filter
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func newRateLimitedHandler(cfg *config.RateLimitConfig) http.Handler {
+	return newDynamicRateLimitedHandler(cfg, nil)
+}
+
+func newDynamicRateLimitedHandler(cfg *config.RateLimitConfig, dynamic *config.DynamicConfig) http.Handler {
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+
+	inner := http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	return withRateLimiting(inner, cfg, dynamic, serializer.NewCodecFactory(scheme), nil)
+}
+
+func newRateLimitRequest(remoteAddr string, requestUser user.Info) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/secrets", nil)
+	req.RemoteAddr = remoteAddr
+
+	ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              listVerb,
+		APIVersion:        "v1",
+		Resource:          "secrets",
+	})
+	if requestUser != nil {
+		ctx = request.WithUser(ctx, requestUser)
+	}
+
+	return req.WithContext(ctx)
+}
+
+func serveRateLimited(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestRateLimitingPerUser(t *testing.T) {
+	t.Parallel()
+
+	handler := newRateLimitedHandler(&config.RateLimitConfig{UserQPS: 0.001, UserBurst: 1})
+
+	alice := &user.DefaultInfo{Name: "alice"}
+
+	recorder := serveRateLimited(handler, newRateLimitRequest("192.0.2.1:1234", alice))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected first request to be served, got status %d", recorder.Code)
+	}
+
+	// The bucket of a user is shared across source addresses.
+	recorder = serveRateLimited(handler, newRateLimitRequest("192.0.2.2:1234", alice))
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected a Retry-After header")
+	}
+
+	recorder = serveRateLimited(handler, newRateLimitRequest("192.0.2.1:1234", &user.DefaultInfo{Name: "bob"}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected other user to be served, got status %d", recorder.Code)
+	}
+
+	admin := &user.DefaultInfo{Name: "loopback", Groups: []string{user.SystemPrivilegedGroup}}
+	for range 3 {
+		recorder = serveRateLimited(handler, newRateLimitRequest("127.0.0.1:1234", admin))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected privileged user to be exempt, got status %d", recorder.Code)
+		}
+	}
+}
+
+func TestRateLimitingPerIP(t *testing.T) {
+	t.Parallel()
+
+	handler := newRateLimitedHandler(&config.RateLimitConfig{
+		UserQPS:   0.001,
+		UserBurst: 2,
+		IPQPS:     0.001,
+		IPBurst:   1,
+	})
+
+	recorder := serveRateLimited(handler, newRateLimitRequest("192.0.2.1:1234", &user.DefaultInfo{Name: "alice"}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected first request to be served, got status %d", recorder.Code)
+	}
+
+	recorder = serveRateLimited(handler, newRateLimitRequest("192.0.2.1:1234", &user.DefaultInfo{Name: "alice"}))
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+
+	// Requests forwarded by the combined server are limited by the original client.
+	forwarded := newRateLimitRequest("127.0.0.1:1234", &user.DefaultInfo{Name: "alice"})
+	forwarded.Header.Set("X-Forwarded-For", "192.0.2.1, 192.0.2.3")
+
+	recorder = serveRateLimited(handler, forwarded)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected forwarded request from another client to be served, got status %d", recorder.Code)
+	}
+
+	// Both tokens of the user bucket are used up now, whatever the source address.
+	recorder = serveRateLimited(handler, newRateLimitRequest("192.0.2.4:1234", &user.DefaultInfo{Name: "alice"}))
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected user bucket of alice to be exhausted, got status %d", recorder.Code)
+	}
+}
+
+func TestRateLimitingDisabled(t *testing.T) {
+	t.Parallel()
+
+	inner := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler := withRateLimiting(inner, &config.RateLimitConfig{UserBurst: 1, IPBurst: 1}, nil, nil, nil)
+	if _, wrapped := handler.(*rateLimiter); wrapped {
+		t.Fatal("expected handler to be returned unchanged when no limit is configured")
+	}
+}
+
+func TestRateLimitingFollowsDynamicConfig(t *testing.T) {
+	t.Parallel()
+
+	dynamic := config.NewDynamicConfig(config.Tunables{})
+	handler := newDynamicRateLimitedHandler(&config.RateLimitConfig{}, dynamic)
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRateLimitRequest("192.0.2.10:1234", nil))
+
+		return recorder.Code
+	}
+
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("expected requests to pass while no limit is configured, got status %d", code)
+	}
+
+	err := dynamic.Apply(t.Context(), map[string]string{
+		"KOMMODITY_RATE_LIMIT_IP_QPS":   "0.001",
+		"KOMMODITY_RATE_LIMIT_IP_BURST": "1",
+	})
+	if err != nil {
+		t.Fatalf("failed to apply dynamic configuration: %v", err)
+	}
+
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("expected the first request to take the only token, got status %d", code)
+	}
+
+	if code := serve(); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the IP limit of the dynamic configuration to apply, got status %d", code)
+	}
+
+	err = dynamic.Apply(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("failed to apply dynamic configuration: %v", err)
+	}
+
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("expected the limit to be lifted with the ConfigMap data, got status %d", code)
+	}
+}
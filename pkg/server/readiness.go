@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/combinedserver"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	apiextensionshelpers "k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// crdReadinessCheckName is the name of the provider CRD check on /readyz.
+const crdReadinessCheckName = "crds"
+
+// registerCRDReadinessCheck reports on /readyz whether the CRDs of every enabled
+// provider group are established. The check is removed when the context is cancelled.
+func registerCRDReadinessCheck(ctx context.Context,
+	crds apiextensionsinformers.CustomResourceDefinitionInformer, groups []string) {
+	logger := logging.FromContext(ctx)
+
+	err := combinedserver.RegisterReadinessCheck(crdReadinessCheckName, func() error {
+		if !crds.Informer().HasSynced() {
+			return fmt.Errorf("%w: CRD informer has not synced", ErrCRDsNotEstablished)
+		}
+
+		return checkCRDsEstablished(crds.Lister(), groups)
+	})
+	if err != nil {
+		logger.Warn("Failed to register CRD readiness check", zap.Error(err))
+
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		combinedserver.UnregisterReadinessCheck(crdReadinessCheckName)
+	}()
+}
+
+// checkCRDsEstablished returns an error naming the groups that have no CRDs yet and the
+// CRDs that are not established.
+func checkCRDsEstablished(lister apiextensionslisters.CustomResourceDefinitionLister, groups []string) error {
+	crds, err := lister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	missing := slices.Clone(groups)
+
+	var pending []string
+
+	for _, crd := range crds {
+		if !slices.Contains(groups, crd.Spec.Group) {
+			continue
+		}
+
+		missing = slices.DeleteFunc(missing, func(group string) bool { return group == crd.Spec.Group })
+
+		if !apiextensionshelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established) {
+			pending = append(pending, crd.Name)
+		}
+	}
+
+	if len(missing) == 0 && len(pending) == 0 {
+		return nil
+	}
+
+	slices.Sort(missing)
+	slices.Sort(pending)
+
+	return fmt.Errorf("%w: groups without CRDs [%s], CRDs not established [%s]", ErrCRDsNotEstablished,
+		strings.Join(missing, ", "), strings.Join(pending, ", "))
+}
@@ -0,0 +1,82 @@
+//nolint:testpackage // white-box tests exercise the unexported CRD readiness check
+package server
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newCRD(name, group string, established bool) *apiextensionsv1.CustomResourceDefinition {
+	status := apiextensionsv1.ConditionFalse
+	if established {
+		status = apiextensionsv1.ConditionTrue
+	}
+
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.Established, Status: status},
+			},
+		},
+	}
+}
+
+func newCRDLister(t *testing.T, crds ...*apiextensionsv1.CustomResourceDefinition) apiextensionslisters.CustomResourceDefinitionLister {
+	t.Helper()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+
+	for _, crd := range crds {
+		err := indexer.Add(crd)
+		if err != nil {
+			t.Fatalf("failed to add CRD: %v", err)
+		}
+	}
+
+	return apiextensionslisters.NewCustomResourceDefinitionLister(indexer)
+}
+
+func TestCheckCRDsEstablished(t *testing.T) {
+	t.Parallel()
+
+	groups := []string{"cluster.x-k8s.io", "infrastructure.cluster.x-k8s.io"}
+
+	lister := newCRDLister(t,
+		newCRD("clusters.cluster.x-k8s.io", "cluster.x-k8s.io", true),
+		newCRD("machines.cluster.x-k8s.io", "cluster.x-k8s.io", false),
+		newCRD("widgets.example.com", "example.com", false),
+	)
+
+	err := checkCRDsEstablished(lister, groups)
+	if !errors.Is(err, ErrCRDsNotEstablished) {
+		t.Fatalf("expected %v, got %v", ErrCRDsNotEstablished, err)
+	}
+
+	for _, expected := range []string{"infrastructure.cluster.x-k8s.io", "machines.cluster.x-k8s.io"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to name %s, got %v", expected, err)
+		}
+	}
+
+	if strings.Contains(err.Error(), "widgets.example.com") {
+		t.Fatalf("expected CRDs outside the provider groups to be ignored, got %v", err)
+	}
+
+	lister = newCRDLister(t,
+		newCRD("clusters.cluster.x-k8s.io", "cluster.x-k8s.io", true),
+		newCRD("awsclusters.infrastructure.cluster.x-k8s.io", "infrastructure.cluster.x-k8s.io", true),
+	)
+
+	err = checkCRDsEstablished(lister, groups)
+	if err != nil {
+		t.Fatalf("expected established CRDs to be ready, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
+	"github.com/kommodity-io/kommodity/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/client-go/dynamic"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// registryMirrorsPluginName is the name of the admission plugin adding the registry
+// mirrors to the Talos machine configurations.
+const registryMirrorsPluginName = "KommodityRegistryMirrors"
+
+//nolint:gochecknoglobals // Read-only resources handled by the plugin.
+var (
+	registryMirrorsResource = kommodityv1alpha1.GroupVersion.WithResource("registrymirrors")
+	clustersResource        = clusterv1.GroupVersion.WithResource("clusters")
+	talosConfigsResource    = schema.GroupResource{Group: "bootstrap.cluster.x-k8s.io", Resource: "talosconfigs"}
+)
+
+// registryMirrorsPlugin adds the RegistryMirrors selecting the cluster of a new TalosConfig
+// to its strategic patches, before the Talos bootstrap provider generates the machine
+// configuration. Talos writes the containerd configuration of the machine from it, so
+// that the machines of airgapped sites pull their images from the mirrors from the first
+// boot. The RegistryMirrors of the Kommodity namespace apply to every namespace, those of
+// the namespace of the cluster override them for the same registries and hosts.
+type registryMirrorsPlugin struct {
+	*admission.Handler
+
+	dynamicClient dynamic.Interface
+}
+
+var (
+	_ admission.MutationInterface    = &registryMirrorsPlugin{}
+	_ initializer.WantsDynamicClient = &registryMirrorsPlugin{}
+)
+
+// registerRegistryMirrorsPlugin registers the plugin with the admission plugins.
+func registerRegistryMirrorsPlugin(plugins *admission.Plugins) {
+	plugins.Register(registryMirrorsPluginName, func(io.Reader) (admission.Interface, error) {
+		return newRegistryMirrorsPlugin(), nil
+	})
+}
+
+func newRegistryMirrorsPlugin() *registryMirrorsPlugin {
+	return &registryMirrorsPlugin{
+		Handler: admission.NewHandler(admission.Create),
+	}
+}
+
+// SetDynamicClient sets the client the RegistryMirrors and Clusters are read with.
+func (p *registryMirrorsPlugin) SetDynamicClient(client dynamic.Interface) {
+	p.dynamicClient = client
+}
+
+// ValidateInitialization ensures the plugin was given its client.
+func (p *registryMirrorsPlugin) ValidateInitialization() error {
+	if p.dynamicClient == nil {
+		return ErrRegistryMirrorsClientNotSet
+	}
+
+	return nil
+}
+
+// Admit appends the registry mirrors of the cluster of new TalosConfigs to their strategic
+// patches.
+func (p *registryMirrorsPlugin) Admit(ctx context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetSubresource() != "" || attrs.GetResource().GroupResource() != talosConfigsResource {
+		return nil
+	}
+
+	obj, ok := attrs.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	mirrors, err := p.mirrorsFor(ctx, attrs.GetNamespace(), obj.GetLabels()[clusterv1.ClusterNameLabel])
+	if err != nil || len(mirrors) == 0 {
+		return err
+	}
+
+	patch, err := registriesPatch(mirrors)
+	if err != nil || patch == "" {
+		return err
+	}
+
+	patches, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "strategicPatches")
+
+	err = unstructured.SetNestedStringSlice(obj.Object, append(patches, patch), "spec", "strategicPatches")
+	if err != nil {
+		return fmt.Errorf("failed to add the registry mirrors to TalosConfig %s: %w", obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// mirrorsFor returns the RegistryMirrors of the Kommodity namespace and then of the
+// namespace that select the named cluster.
+func (p *registryMirrorsPlugin) mirrorsFor(ctx context.Context, namespace,
+	clusterName string) ([]kommodityv1alpha1.RegistryMirror, error) {
+	namespaces := []string{config.KommodityNamespace}
+	if namespace != config.KommodityNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+
+	clusterLabels, err := getClusterLabels(ctx, p.dynamicClient, namespace, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []kommodityv1alpha1.RegistryMirror
+
+	for _, mirrorNamespace := range namespaces {
+		list, err := p.dynamicClient.Resource(registryMirrorsResource).Namespace(mirrorNamespace).
+			List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list registry mirrors of namespace %s: %w", mirrorNamespace, err)
+		}
+
+		for _, item := range list.Items {
+			mirror := kommodityv1alpha1.RegistryMirror{}
+
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &mirror)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode registry mirror %s/%s: %w",
+					mirrorNamespace, item.GetName(), err)
+			}
+
+			selector, err := metav1.LabelSelectorAsSelector(&mirror.Spec.ClusterSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cluster selector of registry mirror %s/%s: %w",
+					mirrorNamespace, item.GetName(), err)
+			}
+
+			if selector.Matches(clusterLabels) {
+				selected = append(selected, mirror)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// registriesPatch renders the machine.registries of the Talos machine configuration for
+// the mirrors, or an empty patch if they mirror nothing. Later mirrors override earlier
+// ones for the same registries and hosts.
+func registriesPatch(mirrors []kommodityv1alpha1.RegistryMirror) (string, error) {
+	registryMirrors := map[string]any{}
+	registryConfig := map[string]any{}
+
+	for _, mirror := range mirrors {
+		for _, endpoints := range mirror.Spec.Mirrors {
+			registryMirror := map[string]any{"endpoints": endpoints.Endpoints}
+			if endpoints.OverridePath {
+				registryMirror["overridePath"] = true
+			}
+
+			if endpoints.SkipFallback {
+				registryMirror["skipFallback"] = true
+			}
+
+			registryMirrors[endpoints.Registry] = registryMirror
+		}
+
+		for _, tls := range mirror.Spec.TLS {
+			registryTLS := map[string]any{}
+			if tls.CA != "" {
+				registryTLS["ca"] = base64.StdEncoding.EncodeToString([]byte(tls.CA))
+			}
+
+			if tls.InsecureSkipVerify {
+				registryTLS["insecureSkipVerify"] = true
+			}
+
+			registryConfig[tls.Host] = map[string]any{"tls": registryTLS}
+		}
+	}
+
+	registries := map[string]any{}
+	if len(registryMirrors) > 0 {
+		registries["mirrors"] = registryMirrors
+	}
+
+	if len(registryConfig) > 0 {
+		registries["config"] = registryConfig
+	}
+
+	if len(registries) == 0 {
+		return "", nil
+	}
+
+	patch, err := yaml.Marshal(map[string]any{"machine": map[string]any{"registries": registries}})
+	if err != nil {
+		return "", fmt.Errorf("failed to render the registry mirrors patch: %w", err)
+	}
+
+	return string(patch), nil
+}
+
+// getClusterLabels returns the labels of the named cluster, or none if it is not named or
+// does not exist (yet).
+func getClusterLabels(ctx context.Context, client dynamic.Interface, namespace,
+	clusterName string) (labels.Set, error) {
+	if clusterName == "" {
+		return nil, nil //nolint:nilnil // Only empty cluster selectors match.
+	}
+
+	cluster, err := client.Resource(clustersResource).Namespace(namespace).Get(ctx, clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil //nolint:nilnil // Only empty cluster selectors match.
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s/%s: %w", namespace, clusterName, err)
+	}
+
+	return cluster.GetLabels(), nil
+}
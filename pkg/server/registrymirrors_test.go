@@ -0,0 +1,161 @@
+//nolint:testpackage // white-box tests exercise the unexported registry mirrors plugin
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+const testRegistryCA = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func newRegistryMirror(namespace, name string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kommodity.io/v1alpha1",
+		"kind":       "RegistryMirror",
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+func newTestCluster(namespace, name string, clusterLabels map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "Cluster",
+		"metadata":   map[string]any{"name": name, "namespace": namespace, "labels": clusterLabels},
+	}}
+}
+
+func newTalosConfig(namespace, clusterName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "bootstrap.cluster.x-k8s.io/v1alpha3",
+		"kind":       "TalosConfig",
+		"metadata": map[string]any{
+			"name": clusterName + "-cp-0", "namespace": namespace,
+			"labels": map[string]any{"cluster.x-k8s.io/cluster-name": clusterName},
+		},
+		"spec": map[string]any{"generateType": "controlplane"},
+	}}
+}
+
+func newTestRegistryMirrorsPlugin(t *testing.T, objects ...runtime.Object) *registryMirrorsPlugin {
+	t.Helper()
+
+	plugin := newRegistryMirrorsPlugin()
+
+	err := plugin.ValidateInitialization()
+	if !errors.Is(err, ErrRegistryMirrorsClientNotSet) {
+		t.Fatalf("expected the plugin to require its client, got %v", err)
+	}
+
+	plugin.SetDynamicClient(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			registryMirrorsResource: "RegistryMirrorList",
+			clustersResource:        "ClusterList",
+		}, objects...))
+
+	return plugin
+}
+
+// admitTalosConfig admits the TalosConfig and returns its strategic machine configuration
+// patches.
+func admitTalosConfig(t *testing.T, plugin admission.MutationInterface,
+	talosConfig *unstructured.Unstructured) []string {
+	t.Helper()
+
+	attrs := admission.NewAttributesRecord(talosConfig, nil, talosConfig.GroupVersionKind(),
+		talosConfig.GetNamespace(), talosConfig.GetName(), talosConfigsResource.WithVersion("v1alpha3"), "",
+		admission.Create, nil, false, nil)
+
+	err := plugin.Admit(t.Context(), attrs, nil)
+	if err != nil {
+		t.Fatalf("expected the TalosConfig to be admitted, got %v", err)
+	}
+
+	patches, _, _ := unstructured.NestedStringSlice(talosConfig.Object, "spec", "strategicPatches")
+
+	return patches
+}
+
+func TestRegistryMirrorsPluginPatchesTalosConfigs(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestRegistryMirrorsPlugin(t,
+		newTestCluster("team-a", "prod", nil),
+		newRegistryMirror(config.KommodityNamespace, "site", map[string]any{
+			"mirrors": []any{
+				map[string]any{"registry": "*", "endpoints": []any{"https://mirror.site:5000"}, "skipFallback": true},
+				map[string]any{"registry": "ghcr.io", "endpoints": []any{"https://mirror.site:5000"}},
+			},
+			"tls": []any{map[string]any{"host": "mirror.site:5000", "ca": testRegistryCA}},
+		}),
+		newRegistryMirror("team-a", "team", map[string]any{
+			"mirrors": []any{
+				map[string]any{"registry": "ghcr.io", "endpoints": []any{"https://ghcr.team-a"}, "overridePath": true},
+			},
+		}),
+	)
+
+	patches := admitTalosConfig(t, plugin, newTalosConfig("team-a", "prod"))
+	if len(patches) != 1 {
+		t.Fatalf("expected a single registries patch, got %v", patches)
+	}
+
+	patch := map[string]any{}
+
+	err := yaml.Unmarshal([]byte(patches[0]), &patch)
+	if err != nil {
+		t.Fatalf("expected the patch to be YAML, got %v", err)
+	}
+
+	registries, _, _ := unstructured.NestedMap(patch, "machine", "registries")
+
+	skipFallback, _, _ := unstructured.NestedBool(registries, "mirrors", "*", "skipFallback")
+	if !skipFallback {
+		t.Fatalf("expected the site mirror of every registry, got %v", registries)
+	}
+
+	endpoints, _, _ := unstructured.NestedStringSlice(registries, "mirrors", "ghcr.io", "endpoints")
+	if len(endpoints) != 1 || endpoints[0] != "https://ghcr.team-a" {
+		t.Fatalf("expected the namespace mirror to override the site one, got %v", endpoints)
+	}
+
+	ca, _, _ := unstructured.NestedString(registries, "config", "mirror.site:5000", "tls", "ca")
+	if ca != base64.StdEncoding.EncodeToString([]byte(testRegistryCA)) {
+		t.Fatalf("expected the CA of the mirror to be trusted, got %q", ca)
+	}
+}
+
+func TestRegistryMirrorsPluginSelectsClusters(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestRegistryMirrorsPlugin(t,
+		newTestCluster("team-a", "prod", map[string]any{"site": "airgapped"}),
+		newTestCluster("team-a", "dev", nil),
+		newRegistryMirror("team-a", "airgapped", map[string]any{
+			"clusterSelector": map[string]any{"matchLabels": map[string]any{"site": "airgapped"}},
+			"mirrors":         []any{map[string]any{"registry": "docker.io", "endpoints": []any{"https://mirror"}}},
+		}),
+		newRegistryMirror("team-b", "other", map[string]any{
+			"mirrors": []any{map[string]any{"registry": "quay.io", "endpoints": []any{"https://mirror"}}},
+		}),
+	)
+
+	patches := admitTalosConfig(t, plugin, newTalosConfig("team-a", "prod"))
+	if len(patches) != 1 {
+		t.Fatalf("expected the selected cluster to be patched, got %v", patches)
+	}
+
+	patches = admitTalosConfig(t, plugin, newTalosConfig("team-a", "dev"))
+	if len(patches) != 0 {
+		t.Fatalf("expected other clusters not to be patched, got %v", patches)
+	}
+}
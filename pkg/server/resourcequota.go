@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// resourceQuotaPluginName is the name of the admission plugin enforcing ResourceQuotas.
+	resourceQuotaPluginName = "KommodityResourceQuota"
+
+	// objectCountPrefix prefixes the quota resource names limiting the number of objects
+	// of a resource, like count/clusters.cluster.x-k8s.io.
+	objectCountPrefix = "count/"
+
+	// quotaReservationTTL is how long an admitted create counts against the quota before
+	// the object shows up in the informer. Creates that fail after admission are released
+	// once it passes, it matches the default request timeout of the API server.
+	quotaReservationTTL = time.Minute
+)
+
+// resourceQuotaPlugin enforces the object count limits of the ResourceQuotas of a
+// namespace, so that operators can cap how many Clusters, Machines or node pools
+// (MachineDeployments) a tenant creates through the shared management plane. The
+// objects are counted when they are created, from an informer started for each
+// resource a quota tracks, there is no quota controller keeping the usage in the
+// status. Creates of a resource in a namespace are checked one at a time, and each
+// admitted create counts until its object shows up in the informer, so concurrent
+// creates cannot exceed a limit together.
+type resourceQuotaPlugin struct {
+	*admission.Handler
+
+	quotaLister   corev1listers.ResourceQuotaLister
+	dynamicClient dynamic.Interface
+	drained       <-chan struct{}
+	now           func() time.Time
+
+	lock     sync.Mutex
+	counters map[schema.GroupResource]cache.SharedIndexInformer
+	usages   map[quotaUsageKey]*quotaUsage
+}
+
+// quotaUsageKey names the objects of a resource in a namespace.
+type quotaUsageKey struct {
+	namespace string
+	resource  schema.GroupResource
+}
+
+// quotaUsage serializes the creates of a resource in a namespace and holds the names of
+// the admitted objects the informer has not seen yet, with the time they were admitted.
+type quotaUsage struct {
+	lock     sync.Mutex
+	reserved map[string]time.Time
+}
+
+var (
+	_ admission.ValidationInterface                = &resourceQuotaPlugin{}
+	_ initializer.WantsExternalKubeInformerFactory = &resourceQuotaPlugin{}
+	_ initializer.WantsDynamicClient               = &resourceQuotaPlugin{}
+	_ initializer.WantsDrainedNotification         = &resourceQuotaPlugin{}
+)
+
+// registerResourceQuotaPlugin registers the plugin with the admission plugins.
+func registerResourceQuotaPlugin(plugins *admission.Plugins) {
+	plugins.Register(resourceQuotaPluginName, func(io.Reader) (admission.Interface, error) {
+		return newResourceQuotaPlugin(), nil
+	})
+}
+
+func newResourceQuotaPlugin() *resourceQuotaPlugin {
+	return &resourceQuotaPlugin{
+		Handler:  admission.NewHandler(admission.Create),
+		now:      time.Now,
+		counters: map[schema.GroupResource]cache.SharedIndexInformer{},
+		usages:   map[quotaUsageKey]*quotaUsage{},
+	}
+}
+
+// SetExternalKubeInformerFactory sets the informer the ResourceQuotas are read from.
+func (p *resourceQuotaPlugin) SetExternalKubeInformerFactory(factory informers.SharedInformerFactory) {
+	quotaInformer := factory.Core().V1().ResourceQuotas()
+	p.quotaLister = quotaInformer.Lister()
+	p.SetReadyFunc(quotaInformer.Informer().HasSynced)
+}
+
+// SetDynamicClient sets the client the objects of any resource are counted with.
+func (p *resourceQuotaPlugin) SetDynamicClient(client dynamic.Interface) {
+	p.dynamicClient = client
+}
+
+// SetDrainedNotification sets the channel the informers counting objects stop with.
+func (p *resourceQuotaPlugin) SetDrainedNotification(drained <-chan struct{}) {
+	p.drained = drained
+}
+
+// ValidateInitialization ensures the plugin was given its clients.
+func (p *resourceQuotaPlugin) ValidateInitialization() error {
+	if p.quotaLister == nil || p.dynamicClient == nil || p.drained == nil {
+		return ErrQuotaClientsNotSet
+	}
+
+	return nil
+}
+
+// Validate rejects the creation of an object when the namespace already holds as many
+// objects of its resource as one of its ResourceQuotas allows.
+func (p *resourceQuotaPlugin) Validate(ctx context.Context, attrs admission.Attributes,
+	_ admission.ObjectInterfaces) error {
+	if attrs.GetNamespace() == "" || attrs.GetSubresource() != "" {
+		return nil
+	}
+
+	if !p.WaitForReady() {
+		return admission.NewForbidden(attrs, ErrQuotaNotReady)
+	}
+
+	quotaResource := objectCountResourceName(attrs.GetResource().GroupResource())
+
+	quotas, err := p.quotaLister.ResourceQuotas(attrs.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list resource quotas of namespace %s: %w", attrs.GetNamespace(), err)
+	}
+
+	limiting := []*corev1.ResourceQuota{}
+
+	for _, quota := range quotas {
+		if _, found := quota.Spec.Hard[quotaResource]; found {
+			limiting = append(limiting, quota)
+		}
+	}
+
+	// Resources no quota tracks are neither counted nor serialized.
+	if len(limiting) == 0 {
+		return nil
+	}
+
+	counter, err := p.counterFor(ctx, attrs.GetResource())
+	if err != nil {
+		return err
+	}
+
+	usage := p.usageFor(quotaUsageKey{namespace: attrs.GetNamespace(), resource: attrs.GetResource().GroupResource()})
+
+	usage.lock.Lock()
+	defer usage.lock.Unlock()
+
+	used, err := usage.count(counter, attrs.GetNamespace(), p.now())
+	if err != nil {
+		return err
+	}
+
+	for _, quota := range limiting {
+		hard := quota.Spec.Hard[quotaResource]
+		if used+1 <= hard.Value() {
+			continue
+		}
+
+		return admission.NewForbidden(attrs, fmt.Errorf("%w: %s, requested: %s=1, used: %s=%d, limited: %s=%s",
+			ErrQuotaExceeded, quota.Name, quotaResource, quotaResource, used, quotaResource, hard.String()))
+	}
+
+	// Dry runs never store their object, so they must not hold back the quota.
+	if attrs.IsDryRun() {
+		return nil
+	}
+
+	usage.reserved[attrs.GetName()] = p.now()
+
+	return nil
+}
+
+// counterFor returns the informer the objects of the resource are counted with. It is
+// started on first use and stops when the API server is drained.
+func (p *resourceQuotaPlugin) counterFor(ctx context.Context,
+	resource schema.GroupVersionResource) (cache.SharedIndexInformer, error) {
+	p.lock.Lock()
+
+	counter, found := p.counters[resource.GroupResource()]
+	if !found {
+		counter = dynamicinformer.NewFilteredDynamicInformer(p.dynamicClient, resource, metav1.NamespaceAll, 0,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil).Informer()
+
+		// Only the names of the objects are needed to count them.
+		err := counter.SetTransform(stripToObjectMeta)
+		if err != nil {
+			p.lock.Unlock()
+
+			return nil, fmt.Errorf("failed to set up counting %s: %w", resource.Resource, err)
+		}
+
+		p.counters[resource.GroupResource()] = counter
+
+		go counter.Run(p.drained)
+	}
+
+	p.lock.Unlock()
+
+	if !cache.WaitForCacheSync(ctx.Done(), counter.HasSynced) {
+		return nil, fmt.Errorf("failed to count %s: %w", resource.Resource, ErrQuotaNotReady)
+	}
+
+	return counter, nil
+}
+
+func (p *resourceQuotaPlugin) usageFor(key quotaUsageKey) *quotaUsage {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	usage, found := p.usages[key]
+	if !found {
+		usage = &quotaUsage{reserved: map[string]time.Time{}}
+		p.usages[key] = usage
+	}
+
+	return usage
+}
+
+// count returns the number of objects in the namespace, including admitted creates the
+// informer has not seen yet. Reservations are released once the informer has seen their
+// object or they expired.
+func (u *quotaUsage) count(counter cache.SharedIndexInformer, namespace string, now time.Time) (int64, error) {
+	objects, err := counter.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count objects in namespace %s: %w", namespace, err)
+	}
+
+	for name, reservedAt := range u.reserved {
+		_, seen, err := counter.GetIndexer().GetByKey(namespace + "/" + name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to look up %s/%s: %w", namespace, name, err)
+		}
+
+		if seen || now.Sub(reservedAt) > quotaReservationTTL {
+			delete(u.reserved, name)
+		}
+	}
+
+	return int64(len(objects) + len(u.reserved)), nil
+}
+
+// stripToObjectMeta keeps only the name and namespace of the objects in the informer.
+func stripToObjectMeta(obj any) (any, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// Tombstones of deleted objects are passed on as they are.
+		return obj, nil //nolint:nilerr // Not an object to strip.
+	}
+
+	return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Name:            accessor.GetName(),
+		Namespace:       accessor.GetNamespace(),
+		ResourceVersion: accessor.GetResourceVersion(),
+	}}, nil
+}
+
+// objectCountResourceName returns the quota resource name limiting the number of
+// objects of the resource, count/<resource> for the core group and
+// count/<resource>.<group> otherwise.
+func objectCountResourceName(resource schema.GroupResource) corev1.ResourceName {
+	return corev1.ResourceName(objectCountPrefix + resource.String())
+}
@@ -0,0 +1,216 @@
+//nolint:testpackage // white-box tests exercise the unexported resource quota plugin
+package server
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+//nolint:gochecknoglobals // Read-only test fixture.
+var quotaClustersResource = schema.GroupVersionResource{
+	Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "clusters",
+}
+
+func newQuotaCluster(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "Cluster",
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+	}}
+}
+
+func newClusterQuota(namespace, name, clusters string) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+			"count/clusters.cluster.x-k8s.io": resource.MustParse(clusters),
+		}},
+	}
+}
+
+func newTestResourceQuotaPlugin(t *testing.T, quotas []runtime.Object,
+	objects ...runtime.Object) *resourceQuotaPlugin {
+	t.Helper()
+
+	plugin := newResourceQuotaPlugin()
+
+	err := plugin.ValidateInitialization()
+	if !errors.Is(err, ErrQuotaClientsNotSet) {
+		t.Fatalf("expected the plugin to require its clients, got %v", err)
+	}
+
+	drained := make(chan struct{})
+	t.Cleanup(func() { close(drained) })
+
+	factory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(quotas...), 0)
+
+	plugin.SetDrainedNotification(drained)
+	plugin.SetExternalKubeInformerFactory(factory)
+	plugin.SetDynamicClient(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{quotaClustersResource: "ClusterList"}, objects...))
+
+	err = plugin.ValidateInitialization()
+	if err != nil {
+		t.Fatalf("expected the plugin to be initialized, got %v", err)
+	}
+
+	factory.Start(drained)
+	factory.WaitForCacheSync(drained)
+
+	return plugin
+}
+
+func newQuotaAttributes(obj *unstructured.Unstructured, subresource string) admission.Attributes {
+	return admission.NewAttributesRecord(obj, nil, obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(),
+		quotaClustersResource, subresource, admission.Create, nil, false, nil)
+}
+
+func TestResourceQuotaPluginEnforcesObjectCounts(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestResourceQuotaPlugin(t,
+		[]runtime.Object{
+			newClusterQuota("team-a", "generous", "10"),
+			newClusterQuota("team-a", "clusters", "2"),
+		},
+		newQuotaCluster("team-a", "first"),
+		newQuotaCluster("team-a", "second"),
+		newQuotaCluster("team-b", "other"),
+	)
+
+	err := plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "third"), ""), nil)
+	// Forbidden errors only carry the message of the error they are built from.
+	if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), ErrQuotaExceeded.Error()+": clusters") {
+		t.Fatalf("expected the create to exceed the quota, got %v", err)
+	}
+
+	err = plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-b", "second"), ""), nil)
+	if err != nil {
+		t.Fatalf("expected namespaces without a quota to be unlimited, got %v", err)
+	}
+
+	err = plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "first"), "status"), nil)
+	if err != nil {
+		t.Fatalf("expected subresources to be ignored, got %v", err)
+	}
+}
+
+func TestResourceQuotaPluginAllowsCreatesWithinTheQuota(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestResourceQuotaPlugin(t,
+		[]runtime.Object{newClusterQuota("team-a", "clusters", "2")},
+		newQuotaCluster("team-a", "first"),
+	)
+
+	err := plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "second"), ""), nil)
+	if err != nil {
+		t.Fatalf("expected the create to fit the quota, got %v", err)
+	}
+}
+
+func TestResourceQuotaPluginCountsAdmittedCreates(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestResourceQuotaPlugin(t,
+		[]runtime.Object{newClusterQuota("team-a", "clusters", "2")},
+		newQuotaCluster("team-a", "first"),
+	)
+
+	start := time.Now()
+	plugin.now = func() time.Time { return start }
+
+	err := plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "second"), ""), nil)
+	if err != nil {
+		t.Fatalf("expected the create to fit the quota, got %v", err)
+	}
+
+	// The second Cluster is not stored yet, as if both creates were in flight.
+	err = plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "third"), ""), nil)
+	if !apierrors.IsForbidden(err) {
+		t.Fatalf("expected the admitted create to count against the quota, got %v", err)
+	}
+
+	// Creates that never store their object are released once the reservation expires.
+	plugin.now = func() time.Time { return start.Add(2 * quotaReservationTTL) }
+
+	err = plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "third"), ""), nil)
+	if err != nil {
+		t.Fatalf("expected the expired reservation to be released, got %v", err)
+	}
+}
+
+func TestResourceQuotaPluginDoesNotCountDryRuns(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestResourceQuotaPlugin(t,
+		[]runtime.Object{newClusterQuota("team-a", "clusters", "2")},
+		newQuotaCluster("team-a", "first"),
+	)
+
+	dryRun := newQuotaCluster("team-a", "second")
+
+	err := plugin.Validate(t.Context(), admission.NewAttributesRecord(dryRun, nil, dryRun.GroupVersionKind(),
+		dryRun.GetNamespace(), dryRun.GetName(), quotaClustersResource, "", admission.Create, nil, true, nil), nil)
+	if err != nil {
+		t.Fatalf("expected the dry run to fit the quota, got %v", err)
+	}
+
+	err = plugin.Validate(t.Context(), newQuotaAttributes(newQuotaCluster("team-a", "third"), ""), nil)
+	if err != nil {
+		t.Fatalf("expected the dry run not to count against the quota, got %v", err)
+	}
+}
+
+func TestResourceQuotaPluginSkipsUntrackedResources(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestResourceQuotaPlugin(t, []runtime.Object{newClusterQuota("team-a", "clusters", "0")})
+
+	machine := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "Machine",
+		"metadata":   map[string]any{"name": "worker-0", "namespace": "team-a"},
+	}}
+
+	err := plugin.Validate(t.Context(), admission.NewAttributesRecord(machine, nil, machine.GroupVersionKind(),
+		"team-a", "worker-0", quotaClustersResource.GroupVersion().WithResource("machines"), "",
+		admission.Create, nil, false, nil), nil)
+	if err != nil {
+		t.Fatalf("expected Machines to be unlimited, got %v", err)
+	}
+
+	if len(plugin.counters) != 0 {
+		t.Fatalf("expected no objects to be counted, got informers for %d resources", len(plugin.counters))
+	}
+}
+
+func TestObjectCountResourceName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[schema.GroupResource]corev1.ResourceName{
+		{Group: "cluster.x-k8s.io", Resource: "machinedeployments"}: "count/machinedeployments.cluster.x-k8s.io",
+		{Resource: "secrets"}: "count/secrets",
+	}
+
+	for groupResource, expected := range tests {
+		got := objectCountResourceName(groupResource)
+		if got != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, groupResource, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	secretReadAuditComponent = "kommodity-secret-read-audit"
+	secretReadEventReason    = "SecretRead"
+)
+
+// secretReadVerbs are the verbs that return the data of Secrets.
+//
+//nolint:gochecknoglobals // Read-only list of verbs.
+var secretReadVerbs = []string{"get", listVerb, "watch"}
+
+// withSecretReadAudit logs every get, list and watch of Secrets, as they hold the
+// kubeconfigs of the workload clusters. It expects the user and the request info to be
+// resolved already, so only authorized reads reach it. Reads of the excluded users and
+// groups are not logged. A recorder, if given, also records the reads as Events.
+func withSecretReadAudit(
+	handler http.Handler,
+	cfg *config.SecretReadAuditConfig,
+	logger *zap.Logger,
+	recorder record.EventRecorder,
+) http.Handler {
+	if cfg == nil || !cfg.Enabled {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestInfo, found := request.RequestInfoFrom(r.Context())
+		if !found || !requestInfo.IsResourceRequest || requestInfo.APIGroup != corev1.GroupName ||
+			requestInfo.Resource != "secrets" || requestInfo.Subresource != "" ||
+			!slices.Contains(secretReadVerbs, requestInfo.Verb) {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		requestUser, found := request.UserFrom(r.Context())
+		if !found || slices.Contains(cfg.ExcludeUsers, requestUser.GetName()) ||
+			slices.ContainsFunc(requestUser.GetGroups(), func(group string) bool {
+				return slices.Contains(cfg.ExcludeGroups, group)
+			}) {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		logger.Info("Secret read",
+			zap.String("verb", requestInfo.Verb),
+			zap.String("user", requestUser.GetName()),
+			zap.Strings("groups", requestUser.GetGroups()),
+			zap.String("namespace", requestInfo.Namespace),
+			zap.String("name", requestInfo.Name),
+			zap.Strings("sourceIPs", sourceIPs(r)),
+			zap.String("userAgent", r.UserAgent()))
+
+		if recorder != nil {
+			// Lists of all namespaces are recorded in the default namespace.
+			recorder.Eventf(&corev1.ObjectReference{
+				Kind:       "Secret",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Namespace:  requestInfo.Namespace,
+				Name:       requestInfo.Name,
+			}, corev1.EventTypeNormal, secretReadEventReason, "%s by %s", requestInfo.Verb, requestUser.GetName())
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func sourceIPs(r *http.Request) []string {
+	ips := []string{}
+
+	for _, ip := range utilnet.SourceIPs(r) {
+		ips = append(ips, ip.String())
+	}
+
+	return ips
+}
+
+// newSecretReadRecorder returns the recorder of the Secret read Events, which are
+// created through the loopback client until the context is done. It returns nil when
+// the Events are disabled.
+func newSecretReadRecorder(
+	ctx context.Context,
+	cfg *config.SecretReadAuditConfig,
+	loopbackConfig *rest.Config,
+) record.EventRecorder {
+	if cfg == nil || !cfg.Enabled || !cfg.Events {
+		return nil
+	}
+
+	return newEventRecorder(ctx, loopbackConfig, secretReadAuditComponent)
+}
+
+// newEventRecorder returns a recorder of Events from the component, which are created
+// through the loopback client until the context is done. It returns nil when the
+// client cannot be created.
+func newEventRecorder(ctx context.Context, loopbackConfig *rest.Config, component string) record.EventRecorder {
+	if loopbackConfig == nil {
+		return nil
+	}
+
+	client, err := kubernetes.NewForConfig(loopbackConfig)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create client for Events",
+			zap.String("component", component), zap.Error(err))
+
+		return nil
+	}
+
+	broadcaster := record.NewBroadcaster(record.WithContext(ctx))
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+
+	return broadcaster.NewRecorder(clientgoscheme.Scheme, corev1.EventSource{Component: component})
+}
@@ -0,0 +1,117 @@
+//nolint:testpackage // white-box tests exercise the unexported Secret read audit filter
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/record"
+)
+
+func serveSecretRead(handler http.Handler, requestInfo *request.RequestInfo, requestUser user.Info) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/secrets/kubeconfig", nil)
+	ctx := request.WithRequestInfo(req.Context(), requestInfo)
+	ctx = request.WithUser(ctx, requestUser)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req.WithContext(ctx))
+
+	return recorder.Code
+}
+
+func TestSecretReadAudit(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	events := record.NewFakeRecorder(10)
+
+	handler := withSecretReadAudit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), &config.SecretReadAuditConfig{
+		Enabled:       true,
+		Events:        true,
+		ExcludeUsers:  []string{"system:apiserver"},
+		ExcludeGroups: []string{"system:serviceaccounts:kube-system"},
+	}, zap.New(core), events)
+
+	get := &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "get",
+		APIVersion:        "v1",
+		Resource:          "secrets",
+		Namespace:         "default",
+		Name:              "kubeconfig",
+	}
+	operator := &user.DefaultInfo{Name: "alice@example.com", Groups: []string{"operators"}}
+
+	if status := serveSecretRead(handler, get, operator); status != http.StatusOK {
+		t.Fatalf("expected the read to be served, got %d", status)
+	}
+
+	entries := logs.FilterMessage("Secret read").AllUntimed()
+	if len(entries) != 1 || entries[0].ContextMap()["user"] != "alice@example.com" ||
+		entries[0].ContextMap()["name"] != "kubeconfig" || entries[0].ContextMap()["verb"] != "get" {
+		t.Fatalf("expected the read to be logged with user and name, got %v", entries)
+	}
+
+	if event := <-events.Events; event != "Normal SecretRead get by alice@example.com" {
+		t.Fatalf("expected an Event for the read, got %q", event)
+	}
+
+	// Excluded users and groups, other resources and writes are not logged.
+	serveSecretRead(handler, get, &user.DefaultInfo{Name: "system:apiserver"})
+	serveSecretRead(handler, get, &user.DefaultInfo{
+		Name:   "system:serviceaccount:kube-system:controller",
+		Groups: []string{"system:serviceaccounts:kube-system"},
+	})
+
+	configMap := *get
+	configMap.Resource = "configmaps"
+	serveSecretRead(handler, &configMap, operator)
+
+	update := *get
+	update.Verb = "update"
+	serveSecretRead(handler, &update, operator)
+
+	if logs.FilterMessage("Secret read").Len() != 1 || len(events.Events) != 0 {
+		t.Fatalf("expected only the first read to be audited, got %v", logs.All())
+	}
+
+	list := *get
+	list.Verb = listVerb
+	list.Namespace = ""
+	list.Name = ""
+	serveSecretRead(handler, &list, operator)
+
+	if logs.FilterMessage("Secret read").Len() != 2 || <-events.Events != "Normal SecretRead list by alice@example.com" {
+		t.Fatalf("expected lists of all namespaces to be audited, got %v", logs.All())
+	}
+}
+
+func TestSecretReadAuditDisabled(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+
+	handler := withSecretReadAudit(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		&config.SecretReadAuditConfig{}, zap.New(core), nil)
+
+	serveSecretRead(handler, &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "get",
+		APIVersion:        "v1",
+		Resource:          "secrets",
+		Namespace:         "default",
+		Name:              "kubeconfig",
+	}, &user.DefaultInfo{Name: "alice@example.com"})
+
+	if logs.Len() != 0 {
+		t.Fatalf("expected no reads to be logged, got %v", logs.All())
+	}
+}
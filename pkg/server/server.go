@@ -3,34 +3,46 @@ package server
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 
+	kommodityv1alpha1 "github.com/kommodity-io/kommodity/pkg/api/v1alpha1"
 	"github.com/kommodity-io/kommodity/pkg/config"
 	"github.com/kommodity-io/kommodity/pkg/kine"
+	"github.com/kommodity-io/kommodity/pkg/kms"
 	"github.com/kommodity-io/kommodity/pkg/logging"
 	generatedopenapi "github.com/kommodity-io/kommodity/pkg/openapi"
 	"github.com/kommodity-io/kommodity/pkg/provider"
+	"github.com/kommodity-io/kommodity/pkg/storage/admissionpolicies"
+	"github.com/kommodity-io/kommodity/pkg/storage/apps"
 	"github.com/kommodity-io/kommodity/pkg/storage/configmaps"
 	"github.com/kommodity-io/kommodity/pkg/storage/endpoints"
 	"github.com/kommodity-io/kommodity/pkg/storage/events"
 	"github.com/kommodity-io/kommodity/pkg/storage/namespaces"
 	"github.com/kommodity-io/kommodity/pkg/storage/rbac"
+	"github.com/kommodity-io/kommodity/pkg/storage/resourcequotas"
 	"github.com/kommodity-io/kommodity/pkg/storage/secrets"
+	"github.com/kommodity-io/kommodity/pkg/storage/selfsubjectreviews"
 	"github.com/kommodity-io/kommodity/pkg/storage/serviceaccount"
 	"github.com/kommodity-io/kommodity/pkg/storage/services"
 	"github.com/kommodity-io/kommodity/pkg/storage/storage"
 	"github.com/kommodity-io/kommodity/pkg/storage/webhookconfigurations"
 	"go.uber.org/zap"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	authorizationapiv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/storage/value"
 	aggregatorapiserver "k8s.io/kube-aggregator/pkg/apiserver"
 
 	// Used to register the API schemes to force init() to be called.
@@ -39,6 +51,8 @@ import (
 
 const (
 	defaultResyncPeriod = 10 // in minutes
+	// expvarPath serves the runtime variables of Kommodity next to the profiles.
+	expvarPath = "/debug/vars"
 )
 
 // New creates a new Kubernetes API Server.
@@ -54,15 +68,11 @@ func New(ctx context.Context, cfg *config.KommodityConfig) (*aggregatorapiserver
 		return nil, fmt.Errorf("failed to extract desired OpenAPI spec for server: %w", err)
 	}
 
-	scheme := runtime.NewScheme()
-
-	err = enhanceScheme(scheme)
+	scheme, err := NewScheme()
 	if err != nil {
-		return nil, fmt.Errorf("failed to enhance local scheme: %w", err)
+		return nil, err
 	}
 
-	mapInternalAliases(scheme)
-
 	providerCache, err := provider.NewProviderCache(scheme)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create provider cache: %w", err)
@@ -80,6 +90,11 @@ func New(ctx context.Context, cfg *config.KommodityConfig) (*aggregatorapiserver
 		return nil, fmt.Errorf("failed to setup config for the generic api server: %w", err)
 	}
 
+	secretsTransformer, err := kms.NewSecretsTransformer(ctx, cfg.EncryptionConfig, genericServerConfig.APIServerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup encryption at rest: %w", err)
+	}
+
 	crdServer, err := newAPIExtensionServer(cfg, genericServerConfig, codecs, genericapiserver.NewEmptyDelegate())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create apiextensions (CRD) server: %w", err)
@@ -91,9 +106,16 @@ func New(ctx context.Context, cfg *config.KommodityConfig) (*aggregatorapiserver
 		return nil, fmt.Errorf("failed to build the generic api server: %w", err)
 	}
 
+	registerLogLevelEndpoint(logger, genericServer)
+
+	if cfg.EnablePprof {
+		// Profiles are installed by the generic API server, the variables are added here.
+		genericServer.Handler.NonGoRestfulMux.Handle(expvarPath, expvar.Handler())
+	}
+
 	logger.Info("Setting up legacy API")
 
-	legacyAPI, err := setupLegacyAPI(cfg, scheme, codecs, logger)
+	legacyAPI, err := setupLegacyAPI(cfg, scheme, codecs, secretsTransformer, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup legacy API group info for the generic API server: %w", err)
 	}
@@ -105,9 +127,25 @@ func New(ctx context.Context, cfg *config.KommodityConfig) (*aggregatorapiserver
 		return nil, fmt.Errorf("failed to install legacy API group into the generic API server: %w", err)
 	}
 
+	logger.Info("Installing events API group")
+
+	eventsAPI := setupEventsAPIGroupInfo(legacyAPI.VersionedResourcesStorageMap["v1"]["events"], scheme, codecs)
+
+	err = genericServer.InstallAPIGroup(eventsAPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install events API group into the generic API server: %w", err)
+	}
+
+	logger.Info("Installing authentication API group")
+
+	err = genericServer.InstallAPIGroup(setupAuthenticationAPIGroupInfo(scheme, codecs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to install authentication API group into the generic API server: %w", err)
+	}
+
 	logger.Info("Installing authorization API group")
 
-	authorizationAPI := setupAuthorizationAPIGroupInfo(cfg, scheme, codecs)
+	authorizationAPI := setupAuthorizationAPIGroupInfo(genericServerConfig.Authorization.Authorizer, scheme, codecs)
 
 	err = genericServer.InstallAPIGroup(authorizationAPI)
 	if err != nil {
@@ -136,6 +174,16 @@ func New(ctx context.Context, cfg *config.KommodityConfig) (*aggregatorapiserver
 		return nil, fmt.Errorf("failed to install storage API group into the generic API server: %w", err)
 	}
 
+	appsAPI, err := setupAppsAPIGroupInfo(cfg, scheme, codecs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup apps API group info: %w", err)
+	}
+
+	err = genericServer.InstallAPIGroup(appsAPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install apps API group into the generic API server: %w", err)
+	}
+
 	admissionRegistrationAPI, err := setupAdmissionRegistrationAPIGroupInfo(cfg, scheme, codecs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup admissionregistration API group info: %w", err)
@@ -160,8 +208,13 @@ func New(ctx context.Context, cfg *config.KommodityConfig) (*aggregatorapiserver
 		return nil, fmt.Errorf("failed to add providers to controller scheme: %w", err)
 	}
 
-	//nolint:contextcheck // No need to pass context here as its not used in the function call
+	err = kommodityv1alpha1.AddToScheme(controllerScheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add kommodity.io API to controller scheme: %w", err)
+	}
+
 	aggregatorServer, err := newAPIAggregatorServer(
+		ctx,
 		cfg,
 		genericServerConfig,
 		providerCache,
@@ -183,6 +236,7 @@ func setupLegacyAPI(
 	cfg *config.KommodityConfig,
 	scheme *runtime.Scheme,
 	codecs serializer.CodecFactory,
+	secretsTransformer value.Transformer,
 	logger *zap.Logger,
 ) (*genericapiserver.APIGroupInfo, error) {
 	logger.Info("Creating Kine legacy storage config")
@@ -206,14 +260,25 @@ func setupLegacyAPI(
 
 	logger.Info("Creating REST storage service for core v1 namespaces")
 
-	namespacesStorage, err := namespaces.NewNamespacesREST(*kineStorageConfig, *scheme)
+	namespacesStorage, namespacesStatusStorage, namespacesFinalizeStorage, err := namespaces.NewNamespacesREST(
+		*kineStorageConfig, *scheme)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create REST storage service for core v1 namespaces: %w", err)
 	}
 
 	logger.Info("Creating REST storage service for core v1 secrets")
 
-	secretsStorage, err := secrets.NewSecretsREST(*kineStorageConfig, *scheme)
+	// Only Secrets are encrypted at rest, the transformer must not leak into other resources.
+	secretsStorageConfig := *kineStorageConfig
+	secretsStorageConfig.Transformer = secretsTransformer
+
+	externalSecrets, err := secrets.NewExternalStoreFromConfig(cfg.SecretsBackendConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create secrets backend: %w", err)
+	}
+
+	secretsStorage, err := secrets.NewSecretsREST(secretsStorageConfig, *scheme, externalSecrets,
+		cfg.WatchCacheConfig.Enabled(corev1.Resource("secrets"), false))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create REST storage service for core v1 secrets: %w", err)
 	}
@@ -227,14 +292,15 @@ func setupLegacyAPI(
 
 	logger.Info("Creating REST storage service for core v1 configmaps")
 
-	configmapsStorage, err := configmaps.NewConfigMapsREST(*kineStorageConfig, *scheme)
+	configmapsStorage, err := configmaps.NewConfigMapsREST(*kineStorageConfig, *scheme,
+		cfg.WatchCacheConfig.Enabled(corev1.Resource("configmaps"), false))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create REST storage service for core v1 configmaps: %w", err)
 	}
 
 	logger.Info("Creating REST storage service for core v1 events")
 
-	eventsStorage, err := events.NewEventsREST(*kineStorageConfig, *scheme)
+	eventsStorage, err := events.NewEventsREST(*kineStorageConfig, *scheme, cfg.EventTTL)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create REST storage service for core v1 events: %w", err)
 	}
@@ -246,20 +312,68 @@ func setupLegacyAPI(
 		return nil, fmt.Errorf("unable to create REST storage service for core v1 serviceaccounts: %w", err)
 	}
 
+	logger.Info("Creating REST storage service for core v1 resourcequotas")
+
+	resourceQuotasStorage, resourceQuotasStatusStorage, err := resourcequotas.NewResourceQuotasREST(
+		*kineStorageConfig, *scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create REST storage service for core v1 resourcequotas: %w", err)
+	}
+
 	coreAPIGroupInfo.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{
-		"endpoints":       endpointsStorage,
-		"namespaces":      namespacesStorage,
-		"services":        servicesStorage,
-		"secrets":         secretsStorage,
-		"configmaps":      configmapsStorage,
-		"events":          eventsStorage,
-		"serviceaccounts": serviceAccountStorage,
+		"endpoints":             endpointsStorage,
+		"namespaces":            namespacesStorage,
+		"namespaces/status":     namespacesStatusStorage,
+		"namespaces/finalize":   namespacesFinalizeStorage,
+		"services":              servicesStorage,
+		"secrets":               secretsStorage,
+		"configmaps":            configmapsStorage,
+		"events":                eventsStorage,
+		"serviceaccounts":       serviceAccountStorage,
+		"resourcequotas":        resourceQuotasStorage,
+		"resourcequotas/status": resourceQuotasStatusStorage,
 	}
 
 	return &coreAPIGroupInfo, nil
 }
 
-func setupAuthorizationAPIGroupInfo(cfg *config.KommodityConfig,
+// setupEventsAPIGroupInfo serves events.k8s.io/v1 from the storage of core events, so
+// that both API groups see the same events.
+func setupEventsAPIGroupInfo(eventsStorage rest.Storage,
+	scheme *runtime.Scheme,
+	codecs serializer.CodecFactory) *genericapiserver.APIGroupInfo {
+	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(
+		eventsv1.GroupName,
+		scheme,
+		runtime.NewParameterCodec(scheme),
+		codecs,
+	)
+	apiGroupInfo.PrioritizedVersions = []schema.GroupVersion{eventsv1.SchemeGroupVersion}
+
+	apiGroupInfo.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{
+		"events": eventsStorage,
+	}
+
+	return &apiGroupInfo
+}
+
+func setupAuthenticationAPIGroupInfo(scheme *runtime.Scheme,
+	codecs serializer.CodecFactory) *genericapiserver.APIGroupInfo {
+	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(
+		authenticationv1.GroupName,
+		scheme,
+		runtime.NewParameterCodec(scheme),
+		codecs,
+	)
+
+	apiGroupInfo.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{
+		"selfsubjectreviews": &selfsubjectreviews.SelfSubjectReviewREST{},
+	}
+
+	return &apiGroupInfo
+}
+
+func setupAuthorizationAPIGroupInfo(apiServerAuthorizer authorizer.Authorizer,
 	scheme *runtime.Scheme,
 	codecs serializer.CodecFactory) *genericapiserver.APIGroupInfo {
 	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(
@@ -270,7 +384,7 @@ func setupAuthorizationAPIGroupInfo(cfg *config.KommodityConfig,
 	)
 
 	apiGroupInfo.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{
-		"selfsubjectaccessreviews": NewSelfSubjectAccessReviewREST(cfg),
+		"selfsubjectaccessreviews": NewSelfSubjectAccessReviewREST(apiServerAuthorizer),
 	}
 
 	return &apiGroupInfo
@@ -322,6 +436,46 @@ func setupRBACAPIGroupInfo(cfg *config.KommodityConfig,
 	return &apiGroupInfo, nil
 }
 
+// setupAppsAPIGroupInfo serves Deployments and ReplicaSets. Kommodity schedules no
+// pods, the objects are only stored for external runners to reconcile.
+func setupAppsAPIGroupInfo(cfg *config.KommodityConfig,
+	scheme *runtime.Scheme,
+	codecs serializer.CodecFactory) (*genericapiserver.APIGroupInfo, error) {
+	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(
+		appsv1.GroupName,
+		scheme,
+		runtime.NewParameterCodec(scheme),
+		codecs,
+	)
+	apiGroupInfo.PrioritizedVersions = []schema.GroupVersion{appsv1.SchemeGroupVersion}
+
+	noConv := serializer.WithoutConversionCodecFactory{CodecFactory: codecs}
+
+	kineStorageConfig, err := kine.NewKineStorageConfig(cfg, noConv.LegacyCodec(appsv1.SchemeGroupVersion))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Kine legacy storage config: %w", err)
+	}
+
+	deploymentStorage, deploymentStatusStorage, err := apps.NewDeploymentREST(*kineStorageConfig, *scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create REST storage service for apps v1 deployments: %w", err)
+	}
+
+	replicaSetStorage, replicaSetStatusStorage, err := apps.NewReplicaSetREST(*kineStorageConfig, *scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create REST storage service for apps v1 replicasets: %w", err)
+	}
+
+	apiGroupInfo.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{
+		"deployments":        deploymentStorage,
+		"deployments/status": deploymentStatusStorage,
+		"replicasets":        replicaSetStorage,
+		"replicasets/status": replicaSetStatusStorage,
+	}
+
+	return &apiGroupInfo, nil
+}
+
 func setupAdmissionRegistrationAPIGroupInfo(cfg *config.KommodityConfig,
 	scheme *runtime.Scheme,
 	codecs serializer.CodecFactory) (*genericapiserver.APIGroupInfo, error) {
@@ -353,9 +507,24 @@ func setupAdmissionRegistrationAPIGroupInfo(cfg *config.KommodityConfig,
 		return nil, fmt.Errorf("unable to create validating webhook configuration REST storage: %w", err)
 	}
 
+	validatingAdmissionPolicyStorage, validatingAdmissionPolicyStatusStorage, err :=
+		admissionpolicies.NewValidatingAdmissionPolicyREST(*kineStorageConfig, *scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create validating admission policy REST storage: %w", err)
+	}
+
+	validatingAdmissionPolicyBindingStorage, err := admissionpolicies.NewValidatingAdmissionPolicyBindingREST(
+		*kineStorageConfig, *scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create validating admission policy binding REST storage: %w", err)
+	}
+
 	apiGroupInfo.VersionedResourcesStorageMap["v1"] = map[string]rest.Storage{
-		"mutatingwebhookconfigurations":   mutatingWebhookConfigStorage,
-		"validatingwebhookconfigurations": validatingWebhookConfigStorage,
+		"mutatingwebhookconfigurations":      mutatingWebhookConfigStorage,
+		"validatingwebhookconfigurations":    validatingWebhookConfigStorage,
+		"validatingadmissionpolicies":        validatingAdmissionPolicyStorage,
+		"validatingadmissionpolicies/status": validatingAdmissionPolicyStatusStorage,
+		"validatingadmissionpolicybindings":  validatingAdmissionPolicyBindingStorage,
 	}
 
 	return &apiGroupInfo, nil
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// controllerManagers runs the controller managers of the API server. They are stopped
+// by a pre-shutdown hook, while the API server still serves the requests they make to
+// finish the reconciles in their work queues.
+type controllerManagers struct {
+	mutex   sync.Mutex
+	stopped bool
+	cancels []context.CancelCauseFunc
+	running sync.WaitGroup
+}
+
+// start runs the manager until the API server shuts down. Its reconciles are given the
+// graceful shutdown timeout of the manager to finish.
+func (m *controllerManagers) start(ctx context.Context, manager ctrl.Manager) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stopped {
+		return
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	m.cancels = append(m.cancels, cancel)
+	m.running.Add(1)
+
+	go func() {
+		defer m.running.Done()
+		defer cancel(nil)
+
+		err := manager.Start(runCtx)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to start controller manager:", zap.Error(err))
+			cancel(fmt.Errorf("failed to start controller manager: %w", err))
+		}
+	}()
+}
+
+// stop stops the controller managers and waits for them to return.
+func (m *controllerManagers) stop() error {
+	m.mutex.Lock()
+	m.stopped = true
+
+	for _, cancel := range m.cancels {
+		cancel(ErrAPIServerShuttingDown)
+	}
+	m.mutex.Unlock()
+
+	m.running.Wait()
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/cache"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	apiregistrationhelper "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1/helper"
+	apiregistrationinformers "k8s.io/kube-aggregator/pkg/client/informers/externalversions/apiregistration/v1"
+	apiregistrationlisters "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
+)
+
+// discoveryBackoff spaces the discovery requests confirming that the provider CRDs are
+// served. Discovery usually catches up within milliseconds of the CRDs being established.
+//
+//nolint:gochecknoglobals,mnd // Constant backoff parameters.
+var discoveryBackoff = wait.Backoff{
+	Duration: 50 * time.Millisecond,
+	Factor:   2,
+	Steps:    math.MaxInt32,
+	Cap:      2 * time.Second,
+}
+
+// waitForProviderCRDsAreServed waits until the CRDs of every provider group are
+// established and the API services of the groups are available. That check is repeated
+// whenever one of the informers sees a change, rather than polling discovery. Discovery
+// is only asked once it passes, to confirm that it serves every kind, as it is updated
+// shortly after the CRDs are established.
+func waitForProviderCRDsAreServed(ctx context.Context,
+	crds apiextensionsinformers.CustomResourceDefinitionInformer,
+	apiServices apiregistrationinformers.APIServiceInformer,
+	discoveryClient discovery.DiscoveryInterface,
+	groupKinds map[string][]string) error {
+	logger := logging.FromContext(ctx)
+	start := time.Now()
+	groups := slices.Sorted(maps.Keys(groupKinds))
+
+	logger.Info("Waiting for provider CRDs to be served", zap.Strings("apiGroups", groups))
+
+	err := waitForInformers(ctx, func() error {
+		err := checkCRDsEstablished(crds.Lister(), groups)
+		if err != nil {
+			return err
+		}
+
+		return checkAPIServicesAvailable(apiServices.Lister(), groups)
+	}, crds.Informer(), apiServices.Informer())
+	if err != nil {
+		return err
+	}
+
+	var discoveryErr error
+
+	err = wait.ExponentialBackoffWithContext(ctx, discoveryBackoff, func(context.Context) (bool, error) {
+		resources, err := discoveryClient.ServerPreferredResources()
+		if err != nil {
+			discoveryErr = fmt.Errorf("failed to discover server resources: %w", err)
+
+			return false, nil
+		}
+
+		discoveryErr = checkDiscoveryServesKinds(resources, groupKinds)
+
+		return discoveryErr == nil, nil
+	})
+	if err != nil {
+		return errors.Join(err, discoveryErr)
+	}
+
+	logger.Info("All provider CRDs are served",
+		zap.Strings("apiGroups", groups),
+		zap.Duration("waited", time.Since(start)))
+
+	return nil
+}
+
+// waitForInformers waits until the informers have synced and the check passes. The
+// check is repeated on every event of the informers. The last error of the check is
+// returned when the context is done first.
+func waitForInformers(ctx context.Context, check func() error, informers ...cache.SharedIndexInformer) error {
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { notify() },
+		UpdateFunc: func(any, any) { notify() },
+		DeleteFunc: func(any) { notify() },
+	}
+
+	hasSynced := make([]cache.InformerSynced, 0, len(informers))
+
+	for _, informer := range informers {
+		registration, err := informer.AddEventHandler(handler)
+		if err != nil {
+			return fmt.Errorf("failed to watch informer: %w", err)
+		}
+
+		defer func() { _ = informer.RemoveEventHandler(registration) }()
+
+		hasSynced = append(hasSynced, informer.HasSynced)
+	}
+
+	if !cache.WaitForCacheSync(ctx.Done(), hasSynced...) {
+		return fmt.Errorf("%w: informers have not synced", ErrCRDsNotEstablished)
+	}
+
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-changed:
+		}
+	}
+}
+
+// checkAPIServicesAvailable returns an error naming the groups without an available
+// API service.
+func checkAPIServicesAvailable(lister apiregistrationlisters.APIServiceLister, groups []string) error {
+	apiServices, err := lister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list API services: %w", err)
+	}
+
+	missing := slices.Clone(groups)
+
+	for _, apiService := range apiServices {
+		if apiregistrationhelper.IsAPIServiceConditionTrue(apiService, apiregistrationv1.Available) {
+			missing = slices.DeleteFunc(missing, func(group string) bool { return group == apiService.Spec.Group })
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	slices.Sort(missing)
+
+	return fmt.Errorf("%w: groups without available API services [%s]", ErrCRDsNotEstablished,
+		strings.Join(missing, ", "))
+}
+
+// checkDiscoveryServesKinds returns an error naming the kinds of the groups that are
+// missing from discovery.
+func checkDiscoveryServesKinds(resources []*metav1.APIResourceList, groupKinds map[string][]string) error {
+	served := make(map[string][]string)
+
+	for _, list := range resources {
+		if list == nil {
+			continue
+		}
+
+		groupVersion, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return fmt.Errorf("failed to parse group version %q: %w", list.GroupVersion, err)
+		}
+
+		for _, resource := range list.APIResources {
+			served[groupVersion.Group] = append(served[groupVersion.Group], resource.Kind)
+		}
+	}
+
+	var missing []string
+
+	for group, kinds := range groupKinds {
+		for _, kind := range kinds {
+			if !slices.Contains(served[group], kind) {
+				missing = append(missing, kind+"."+group)
+			}
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	slices.Sort(missing)
+
+	return fmt.Errorf("%w: kinds not in discovery [%s]", ErrCRDsNotEstablished, strings.Join(missing, ", "))
+}
+
+// startupReport logs how long each post-start hook of the API server took, once all of
+// them returned.
+type startupReport struct {
+	logger  *zap.Logger
+	created time.Time
+
+	mutex     sync.Mutex
+	pending   int
+	durations map[string]time.Duration
+}
+
+func newStartupReport(logger *zap.Logger) *startupReport {
+	return &startupReport{
+		logger:    logger,
+		created:   time.Now(),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+// time returns the hook, recording how long it takes.
+func (r *startupReport) time(name string, hook genericapiserver.PostStartHookFunc) genericapiserver.PostStartHookFunc {
+	r.mutex.Lock()
+	r.pending++
+	r.mutex.Unlock()
+
+	return func(ctx genericapiserver.PostStartHookContext) error {
+		start := time.Now()
+
+		err := hook(ctx)
+
+		r.record(name, time.Since(start))
+
+		return err
+	}
+}
+
+func (r *startupReport) record(name string, duration time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.durations[name] = duration
+	r.pending--
+
+	if r.pending > 0 {
+		return
+	}
+
+	hooks := make([]zapcore.Field, 0, len(r.durations))
+	for _, name := range slices.Sorted(maps.Keys(r.durations)) {
+		hooks = append(hooks, zap.Duration(name, r.durations[name]))
+	}
+
+	r.logger.Info("Post-start hooks finished",
+		zap.Duration("sinceCreated", time.Since(r.created)),
+		zap.Dict("hooks", hooks...))
+}
@@ -0,0 +1,164 @@
+//nolint:testpackage // white-box tests exercise the unexported startup waits
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	apiregistrationlisters "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
+)
+
+func newAPIService(group string, available bool) *apiregistrationv1.APIService {
+	status := apiregistrationv1.ConditionFalse
+	if available {
+		status = apiregistrationv1.ConditionTrue
+	}
+
+	return &apiregistrationv1.APIService{
+		ObjectMeta: metav1.ObjectMeta{Name: "v1beta1." + group},
+		Spec:       apiregistrationv1.APIServiceSpec{Group: group, Version: "v1beta1"},
+		Status: apiregistrationv1.APIServiceStatus{
+			Conditions: []apiregistrationv1.APIServiceCondition{
+				{Type: apiregistrationv1.Available, Status: status},
+			},
+		},
+	}
+}
+
+func TestCheckAPIServicesAvailable(t *testing.T) {
+	t.Parallel()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+
+	for _, apiService := range []*apiregistrationv1.APIService{
+		newAPIService("cluster.x-k8s.io", true),
+		newAPIService("infrastructure.cluster.x-k8s.io", false),
+	} {
+		err := indexer.Add(apiService)
+		if err != nil {
+			t.Fatalf("failed to add API service: %v", err)
+		}
+	}
+
+	lister := apiregistrationlisters.NewAPIServiceLister(indexer)
+
+	err := checkAPIServicesAvailable(lister, []string{"cluster.x-k8s.io", "infrastructure.cluster.x-k8s.io"})
+	if !errors.Is(err, ErrCRDsNotEstablished) || !strings.Contains(err.Error(), "infrastructure.cluster.x-k8s.io") {
+		t.Fatalf("expected the unavailable group to be named, got %v", err)
+	}
+
+	err = checkAPIServicesAvailable(lister, []string{"cluster.x-k8s.io"})
+	if err != nil {
+		t.Fatalf("expected available API services to be ready, got %v", err)
+	}
+}
+
+func TestWaitForInformersWakesUpOnChanges(t *testing.T) {
+	t.Parallel()
+
+	crd := newCRD("clusters.cluster.x-k8s.io", "cluster.x-k8s.io", false)
+	client := apiextensionsfake.NewSimpleClientset(crd)
+	factory := apiextensionsinformers.NewSharedInformerFactory(client, 0)
+	crds := factory.Apiextensions().V1().CustomResourceDefinitions()
+	informer := crds.Informer()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer factory.Shutdown()
+	defer cancel()
+
+	factory.Start(ctx.Done())
+
+	check := func() error {
+		return checkCRDsEstablished(crds.Lister(), []string{"cluster.x-k8s.io"})
+	}
+
+	waited := make(chan error, 1)
+
+	go func() {
+		waited <- waitForInformers(ctx, check, informer)
+	}()
+
+	select {
+	case err := <-waited:
+		t.Fatalf("expected to wait for the CRD to be established, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	crd.Status.Conditions[0].Status = apiextensionsv1.ConditionTrue
+
+	_, err := client.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update CRD: %v", err)
+	}
+
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatalf("expected the wait to succeed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the wait to end once the CRD is established")
+	}
+}
+
+func TestWaitForInformersReturnsLastErrorWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	client := apiextensionsfake.NewSimpleClientset()
+	factory := apiextensionsinformers.NewSharedInformerFactory(client, 0)
+	crds := factory.Apiextensions().V1().CustomResourceDefinitions()
+	informer := crds.Informer()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer factory.Shutdown()
+	defer cancel()
+
+	factory.Start(ctx.Done())
+
+	err := waitForInformers(ctx, func() error {
+		return checkCRDsEstablished(crds.Lister(), []string{"cluster.x-k8s.io"})
+	}, informer)
+	if !errors.Is(err, ErrCRDsNotEstablished) || !strings.Contains(err.Error(), "cluster.x-k8s.io") {
+		t.Fatalf("expected the last error of the check, got %v", err)
+	}
+}
+
+func TestCheckDiscoveryServesKinds(t *testing.T) {
+	t.Parallel()
+
+	groupKinds := map[string][]string{
+		"infrastructure.cluster.x-k8s.io": {"ScalewayCluster", "DockerMachine"},
+	}
+
+	resources := []*metav1.APIResourceList{
+		nil,
+		{
+			GroupVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			APIResources: []metav1.APIResource{{Kind: "ScalewayCluster"}},
+		},
+	}
+
+	err := checkDiscoveryServesKinds(resources, groupKinds)
+	if !errors.Is(err, ErrCRDsNotEstablished) || !strings.Contains(err.Error(), "DockerMachine.infrastructure") {
+		t.Fatalf("expected the missing kind to be named, got %v", err)
+	}
+
+	resources = append(resources, &metav1.APIResourceList{
+		GroupVersion: "infrastructure.cluster.x-k8s.io/v1beta2",
+		APIResources: []metav1.APIResource{{Kind: "DockerMachine"}},
+	})
+
+	err = checkDiscoveryServesKinds(resources, groupKinds)
+	if err != nil {
+		t.Fatalf("expected all kinds to be served, got %v", err)
+	}
+}
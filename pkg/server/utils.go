@@ -6,14 +6,17 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/kommodity-io/kommodity/pkg/config"
 	"github.com/kommodity-io/kommodity/pkg/controller/reconciler"
+	"github.com/kommodity-io/kommodity/pkg/storage/events"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -43,6 +46,7 @@ import (
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/server/options"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	certutil "k8s.io/client-go/util/cert"
 	apiregistration "k8s.io/kube-aggregator/pkg/apis/apiregistration"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	coreapiv1 "k8s.io/kubernetes/pkg/apis/core/v1"
@@ -56,8 +60,29 @@ const (
 	rsaKeySize = 4096
 	// loopbackBindAddress is the IP address to use for the API server's loopback client.
 	loopbackBindAddress = "127.0.0.1"
+	// loopbackBindAddressV6 is the IPv6 loopback address, which localhost may resolve to.
+	loopbackBindAddressV6 = "::1"
 )
 
+// NewScheme returns the scheme the API server stores its built-in resources with.
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+
+	err := enhanceScheme(scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enhance local scheme: %w", err)
+	}
+
+	mapInternalAliases(scheme)
+
+	err = events.RegisterConversions(scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register event conversions: %w", err)
+	}
+
+	return scheme, nil
+}
+
 func enhanceScheme(scheme *runtime.Scheme) error {
 	addFuncs := []struct {
 		name string
@@ -108,6 +133,7 @@ func mapInternalAliases(scheme *runtime.Scheme) {
 	add("Service", gvCoreInternal, &corev1.Service{})
 	add("Endpoints", gvCoreInternal, &corev1.Endpoints{})
 	add("ServiceAccount", gvCoreInternal, &corev1.ServiceAccount{})
+	add("ResourceQuota", gvCoreInternal, &corev1.ResourceQuota{})
 
 	add("ConfigMapList", gvCoreInternal, &corev1.ConfigMapList{})
 	add("SecretList", gvCoreInternal, &corev1.SecretList{})
@@ -116,6 +142,13 @@ func mapInternalAliases(scheme *runtime.Scheme) {
 	add("ServiceList", gvCoreInternal, &corev1.ServiceList{})
 	add("EndpointsList", gvCoreInternal, &corev1.EndpointsList{})
 	add("ServiceAccountList", gvCoreInternal, &corev1.ServiceAccountList{})
+	add("ResourceQuotaList", gvCoreInternal, &corev1.ResourceQuotaList{})
+
+	// events.k8s.io is served from the storage of core events, converted on the fly.
+	gvEventsInternal := schema.GroupVersion{Group: eventsv1.GroupName, Version: runtime.APIVersionInternal}
+
+	add("Event", gvEventsInternal, &corev1.Event{})
+	add("EventList", gvEventsInternal, &corev1.EventList{})
 
 	gvAdmissionRegistrationInternal := schema.GroupVersion{
 		Group: "admissionregistration.k8s.io", Version: runtime.APIVersionInternal}
@@ -126,6 +159,18 @@ func mapInternalAliases(scheme *runtime.Scheme) {
 	add("MutatingWebhookConfiguration",
 		gvAdmissionRegistrationInternal,
 		&admissionregistrationv1.MutatingWebhookConfiguration{})
+	add("ValidatingAdmissionPolicy",
+		gvAdmissionRegistrationInternal,
+		&admissionregistrationv1.ValidatingAdmissionPolicy{})
+	add("ValidatingAdmissionPolicyList",
+		gvAdmissionRegistrationInternal,
+		&admissionregistrationv1.ValidatingAdmissionPolicyList{})
+	add("ValidatingAdmissionPolicyBinding",
+		gvAdmissionRegistrationInternal,
+		&admissionregistrationv1.ValidatingAdmissionPolicyBinding{})
+	add("ValidatingAdmissionPolicyBindingList",
+		gvAdmissionRegistrationInternal,
+		&admissionregistrationv1.ValidatingAdmissionPolicyBindingList{})
 
 	gvRbacInternal := schema.GroupVersion{Group: "rbac.authorization.k8s.io", Version: runtime.APIVersionInternal}
 
@@ -138,21 +183,47 @@ func mapInternalAliases(scheme *runtime.Scheme) {
 	gvStorageInternal := schema.GroupVersion{Group: "storage.k8s.io", Version: runtime.APIVersionInternal}
 	add("VolumeAttachment", gvStorageInternal, &storageapiv1.VolumeAttachment{})
 	add("VolumeAttachmentList", gvStorageInternal, &storageapiv1.VolumeAttachmentList{})
+
+	gvAppsInternal := schema.GroupVersion{Group: appsv1.GroupName, Version: runtime.APIVersionInternal}
+	add("Deployment", gvAppsInternal, &appsv1.Deployment{})
+	add("DeploymentList", gvAppsInternal, &appsv1.DeploymentList{})
+	add("ReplicaSet", gvAppsInternal, &appsv1.ReplicaSet{})
+	add("ReplicaSetList", gvAppsInternal, &appsv1.ReplicaSetList{})
 }
 
 func setupSecureServingWithSelfSigned(cfg *config.KommodityConfig) (*options.SecureServingOptions, error) {
+	bindAddress := cfg.APIServerBindAddress
+	if bindAddress == nil {
+		bindAddress = net.ParseIP(loopbackBindAddress)
+	}
+
 	secureServing := options.NewSecureServingOptions()
-	secureServing.BindAddress = net.ParseIP(loopbackBindAddress)
-	secureServing.BindNetwork = "tcp4"
+	secureServing.BindAddress = bindAddress
+	secureServing.BindNetwork = bindNetwork(bindAddress)
 	secureServing.BindPort = cfg.APIServerPort
 
-	// Generate self-signed certs for "localhost"
+	// Generate self-signed certs for "localhost", and the names the API server is
+	// reachable under when it listens on all interfaces.
 	alternateIPs := []net.IP{
-		net.ParseIP(loopbackBindAddress), // IPv4
+		net.ParseIP(loopbackBindAddress),   // IPv4
+		net.ParseIP(loopbackBindAddressV6), // IPv6
 	}
 	alternateDNS := []string{"localhost", "apiserver-loopback-client"}
 
-	err := secureServing.MaybeDefaultWithSelfSignedCerts("localhost", alternateDNS, alternateIPs)
+	for _, san := range cfg.APIServerCertSANs {
+		if ip := net.ParseIP(san); ip != nil {
+			alternateIPs = append(alternateIPs, ip)
+		} else {
+			alternateDNS = append(alternateDNS, san)
+		}
+	}
+
+	err := removeServingCertWithoutSANs(secureServing, alternateDNS, alternateIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = secureServing.MaybeDefaultWithSelfSignedCerts("localhost", alternateDNS, alternateIPs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate self-signed certificates: %w", err)
 	}
@@ -160,6 +231,59 @@ func setupSecureServingWithSelfSigned(cfg *config.KommodityConfig) (*options.Sec
 	return secureServing, nil
 }
 
+// removeServingCertWithoutSANs removes the self-signed serving certificate generated by an
+// earlier start when it does not cover all SANs, so that it is generated again. It is
+// kept in the certificate directory and reused otherwise.
+func removeServingCertWithoutSANs(secureServing *options.SecureServingOptions,
+	alternateDNS []string, alternateIPs []net.IP) error {
+	certFile := filepath.Join(secureServing.ServerCert.CertDirectory, secureServing.ServerCert.PairName+".crt")
+	keyFile := filepath.Join(secureServing.ServerCert.CertDirectory, secureServing.ServerCert.PairName+".key")
+
+	//nolint:gosec // The path is built from the serving options.
+	certPEM, err := os.ReadFile(certFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read serving certificate: %w", err)
+	}
+
+	hosts := slices.Clone(alternateDNS)
+	for _, ip := range alternateIPs {
+		hosts = append(hosts, ip.String())
+	}
+
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err == nil && slices.IndexFunc(hosts, func(host string) bool {
+		return certs[0].VerifyHostname(host) != nil
+	}) == -1 {
+		return nil
+	}
+
+	for _, file := range []string{certFile, keyFile} {
+		err = os.Remove(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove outdated serving certificate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// bindNetwork returns the network to listen on for a bind address. All interfaces are
+// listened on over both IPv4 and IPv6, whichever of "0.0.0.0" and "::" is given.
+func bindNetwork(address net.IP) string {
+	switch {
+	case address.IsUnspecified():
+		return "tcp"
+	case address.To4() != nil:
+		return "tcp4"
+	default:
+		return "tcp6"
+	}
+}
+
 func getServingCertFromFiles(genericServerConfig *genericapiserver.RecommendedConfig) ([]byte, error) {
 	combinedCertName := genericServerConfig.SecureServing.Cert.Name()
 	if combinedCertName == "" {
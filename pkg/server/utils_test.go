@@ -0,0 +1,85 @@
+//nolint:testpackage // white-box tests exercise the unexported secure serving setup
+package server
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+)
+
+func servingCert(t *testing.T, cfg *config.KommodityConfig) *x509.Certificate {
+	t.Helper()
+
+	secureServing, err := setupSecureServingWithSelfSigned(cfg)
+	if err != nil {
+		t.Fatalf("failed to set up secure serving: %v", err)
+	}
+
+	certPEM, err := os.ReadFile(secureServing.ServerCert.CertKey.CertFile)
+	if err != nil {
+		t.Fatalf("failed to read serving certificate: %v", err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatal("expected a PEM encoded serving certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse serving certificate: %v", err)
+	}
+
+	return cert
+}
+
+// The serving certificate is kept in the working directory, so the test cannot run in parallel.
+func TestSetupSecureServingWithSelfSignedAddsSANs(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cfg := &config.KommodityConfig{
+		APIServerPort:        8443,
+		APIServerBindAddress: net.ParseIP("::"),
+	}
+
+	cert := servingCert(t, cfg)
+	if cert.VerifyHostname("::1") != nil {
+		t.Fatalf("expected the IPv6 loopback address to be covered, got %v", cert.IPAddresses)
+	}
+
+	// The certificate generated before is replaced once it misses a SAN.
+	cfg.APIServerCertSANs = []string{"kommodity.example.com", "2001:db8::1", "192.0.2.10"}
+	cert = servingCert(t, cfg)
+
+	for _, host := range []string{"localhost", "127.0.0.1", "::1", "kommodity.example.com", "2001:db8::1", "192.0.2.10"} {
+		if cert.VerifyHostname(host) != nil {
+			t.Fatalf("expected %s to be covered, got %v and %v", host, cert.DNSNames, cert.IPAddresses)
+		}
+	}
+
+	// It is reused as long as it covers them all.
+	if again := servingCert(t, cfg); !again.Equal(cert) {
+		t.Fatal("expected the serving certificate to be reused")
+	}
+}
+
+func TestBindNetwork(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"0.0.0.0":   "tcp",
+		"::":        "tcp",
+		"127.0.0.1": "tcp4",
+		"::1":       "tcp6",
+	}
+
+	for address, want := range tests {
+		if got := bindNetwork(net.ParseIP(address)); got != want {
+			t.Fatalf("expected network %q for %s, got %q", want, address, got)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package spiffe
+
+import "errors"
+
+var (
+	// ErrUnsupportedEndpoint is returned for a Workload API address that is not a unix socket.
+	ErrUnsupportedEndpoint = errors.New("unsupported Workload API endpoint, expected unix:///path")
+	// ErrNoSVID is returned when the Workload API provides no SVID.
+	ErrNoSVID = errors.New("no SVID received from the Workload API")
+	// ErrInvalidSVID is returned for an SVID whose certificates or key cannot be used.
+	ErrInvalidSVID = errors.New("invalid SVID")
+	// ErrInvalidBundle is returned for a trust bundle that holds invalid certificates.
+	ErrInvalidBundle = errors.New("invalid trust bundle")
+	// ErrInvalidMessage is returned for a Workload API message that cannot be decoded.
+	ErrInvalidMessage = errors.New("invalid Workload API message")
+	// ErrUnexpectedMessage is returned when a message of an unexpected type is encoded.
+	ErrUnexpectedMessage = errors.New("unexpected message type")
+)
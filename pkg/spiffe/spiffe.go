@@ -0,0 +1,285 @@
+// Package spiffe gets the X.509 SVID of Kommodity and the trust bundles from the
+// SPIFFE Workload API, such as a SPIRE agent, and keeps them up to date as they rotate.
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// unixEndpointPrefix prefixes the path of the Workload API socket.
+	unixEndpointPrefix = "unix://"
+
+	// initialSVIDTimeout bounds how long Kommodity waits for its first SVID.
+	initialSVIDTimeout = 30 * time.Second
+	// retryInterval is how long to wait before fetching SVIDs again after the stream
+	// of the Workload API ended.
+	retryInterval = 5 * time.Second
+)
+
+// Source holds the current X.509 SVID of Kommodity and the trust bundles, which it
+// receives from the Workload API.
+type Source struct {
+	conn    *grpc.ClientConn
+	current atomic.Pointer[svid]
+}
+
+type svid struct {
+	id          string
+	certificate *tls.Certificate
+	bundle      []*x509.Certificate
+}
+
+// NewSource connects to the Workload API at the endpoint and waits for the first SVID.
+// The SVID and bundles are updated until the context is cancelled.
+func NewSource(ctx context.Context, endpoint string) (*Source, error) {
+	path, found := strings.CutPrefix(endpoint, unixEndpointPrefix)
+	if !found || !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEndpoint, endpoint)
+	}
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Workload API: %w", err)
+	}
+
+	source := &Source{conn: conn}
+	updated := make(chan error, 1)
+
+	go source.watch(ctx, updated)
+
+	timer := time.NewTimer(initialSVIDTimeout)
+	defer timer.Stop()
+
+	// Errors are retried until the first SVID arrives, the last one is reported.
+	var lastErr error
+
+	for {
+		select {
+		case err := <-updated:
+			if err == nil {
+				return source, nil
+			}
+
+			lastErr = err
+		case <-timer.C:
+			if lastErr == nil {
+				return nil, ErrNoSVID
+			}
+
+			return nil, fmt.Errorf("%w: %w", ErrNoSVID, lastErr)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrNoSVID, ctx.Err())
+		}
+	}
+}
+
+// ID returns the SPIFFE ID of Kommodity.
+func (s *Source) ID() string {
+	return s.current.Load().id
+}
+
+// GetCertificate returns the current SVID, for the GetCertificate of a TLS config.
+func (s *Source) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return s.current.Load().certificate, nil
+}
+
+// GetCertificateFunc returns the function the combined server gets the current SVID
+// with, or nil when SPIFFE is not configured.
+func (s *Source) GetCertificateFunc() func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if s == nil {
+		return nil
+	}
+
+	return s.GetCertificate
+}
+
+// Bundle returns the CAs of the trust domain of Kommodity and of the trust domains
+// federated with it.
+func (s *Source) Bundle() []*x509.Certificate {
+	return s.current.Load().bundle
+}
+
+// watch streams the SVIDs from the Workload API, reporting the outcome of each update
+// as long as someone waits for it.
+func (s *Source) watch(ctx context.Context, updated chan<- error) {
+	logger := logging.FromContext(ctx)
+
+	defer func() {
+		_ = s.conn.Close()
+	}()
+
+	for {
+		err := s.stream(ctx, updated)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Warn("Fetching SPIFFE SVIDs failed, retrying", zap.Error(err),
+			zap.Duration("retryInterval", retryInterval))
+
+		select {
+		case updated <- err:
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (s *Source) stream(ctx context.Context, updated chan<- error) error {
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, workloadAPIHeader, "true"))
+	defer cancel()
+
+	stream, err := s.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fetchX509SVIDMethod,
+		grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return fmt.Errorf("failed to fetch SVIDs: %w", err)
+	}
+
+	// X509SVIDRequest has no fields.
+	err = stream.SendMsg(&[]byte{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch SVIDs: %w", err)
+	}
+
+	err = stream.CloseSend()
+	if err != nil {
+		return fmt.Errorf("failed to fetch SVIDs: %w", err)
+	}
+
+	for {
+		var message []byte
+
+		err = stream.RecvMsg(&message)
+		if err != nil {
+			return fmt.Errorf("failed to receive SVIDs: %w", err)
+		}
+
+		err = s.update(message)
+
+		select {
+		case updated <- err:
+		default:
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// update replaces the SVID with the first one of the response, the default SVID of
+// the workload.
+func (s *Source) update(message []byte) error {
+	response, err := parseX509SVIDResponse(message)
+	if err != nil {
+		return err
+	}
+
+	if len(response.svids) == 0 {
+		return ErrNoSVID
+	}
+
+	current, err := parseSVID(response.svids[0], response.federatedBundles)
+	if err != nil {
+		return err
+	}
+
+	s.current.Store(current)
+
+	return nil
+}
+
+func parseSVID(message x509SVID, federatedBundles [][]byte) (*svid, error) {
+	chain, err := x509.ParseCertificates(message.chain)
+	if err != nil || len(chain) == 0 {
+		return nil, errors.Join(ErrInvalidSVID, err)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(message.key)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSVID, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidSVID, key)
+	}
+
+	id, ok := IDFromCertificate(chain[0])
+	if !ok || id != message.id {
+		return nil, fmt.Errorf("%w: certificate does not hold the SPIFFE ID %s", ErrInvalidSVID, message.id)
+	}
+
+	certificate := &tls.Certificate{PrivateKey: signer, Leaf: chain[0]}
+	for _, cert := range chain {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+
+	var bundle []*x509.Certificate
+
+	for _, der := range append([][]byte{message.bundle}, federatedBundles...) {
+		certs, err := x509.ParseCertificates(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+
+		bundle = append(bundle, certs...)
+	}
+
+	return &svid{id: id, certificate: certificate, bundle: bundle}, nil
+}
+
+// IDFromCertificate returns the SPIFFE ID of an SVID, its URI SAN with the spiffe
+// scheme.
+func IDFromCertificate(cert *x509.Certificate) (string, bool) {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" {
+			return uri.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// TrustDomain returns the trust domain of the SPIFFE ID, as spiffe://<trust domain>.
+func TrustDomain(id string) string {
+	uri, err := url.Parse(id)
+	if err != nil {
+		return ""
+	}
+
+	return "spiffe://" + uri.Host
+}
+
+// MatchesID reports whether the SPIFFE ID is one of the allowed IDs or below one of
+// them, so that allowing spiffe://example.org allows the whole trust domain.
+func MatchesID(id string, allowed []string) bool {
+	for _, allowedID := range allowed {
+		if id == allowedID || strings.HasPrefix(id, allowedID+"/") {
+			return true
+		}
+	}
+
+	return false
+}
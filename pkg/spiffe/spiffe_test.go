@@ -0,0 +1,252 @@
+package spiffe_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/spiffe"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var errMissingHeader = errors.New("missing workload.spiffe.io header")
+
+// bytesCodec sends the messages of the fake Workload API as they are.
+type bytesCodec struct{}
+
+func (bytesCodec) Marshal(v any) ([]byte, error) { return *v.(*[]byte), nil } //nolint:forcetypeassert // Test codec.
+
+func (bytesCodec) Unmarshal(data []byte, v any) error {
+	*v.(*[]byte) = data //nolint:forcetypeassert // Test codec.
+
+	return nil
+}
+
+func (bytesCodec) Name() string { return "proto" }
+
+// newCA creates a CA of a trust domain.
+func newCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA: %v", err)
+	}
+
+	return ca, key
+}
+
+// newSVIDMessage encodes an X509SVIDResponse holding an SVID for the SPIFFE ID.
+func newSVIDMessage(t *testing.T, id string, ca *x509.Certificate, caKey *ecdsa.PrivateKey,
+	federatedCA *x509.Certificate) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	uri, err := url.Parse(id)
+	if err != nil {
+		t.Fatalf("failed to parse SPIFFE ID: %v", err)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+	}, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create SVID: %v", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	var svid []byte
+	svid = protowire.AppendTag(svid, 1, protowire.BytesType)
+	svid = protowire.AppendString(svid, id)
+	svid = protowire.AppendTag(svid, 2, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, der)
+	svid = protowire.AppendTag(svid, 3, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, keyDER)
+	svid = protowire.AppendTag(svid, 4, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, ca.Raw)
+
+	var federated []byte
+	federated = protowire.AppendTag(federated, 1, protowire.BytesType)
+	federated = protowire.AppendString(federated, "spiffe://federated.example")
+	federated = protowire.AppendTag(federated, 2, protowire.BytesType)
+	federated = protowire.AppendBytes(federated, federatedCA.Raw)
+
+	var response []byte
+	response = protowire.AppendTag(response, 1, protowire.BytesType)
+	response = protowire.AppendBytes(response, svid)
+	response = protowire.AppendTag(response, 3, protowire.BytesType)
+	response = protowire.AppendBytes(response, federated)
+
+	return response
+}
+
+// serveWorkloadAPI serves a fake Workload API on a unix socket, which streams the
+// messages sent on the channel.
+func serveWorkloadAPI(t *testing.T, messages <-chan []byte) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := grpc.NewServer(grpc.ForceServerCodec(bytesCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "SpiffeWorkloadAPI",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "FetchX509SVID",
+			ServerStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				md, _ := metadata.FromIncomingContext(stream.Context())
+				if len(md.Get("workload.spiffe.io")) == 0 {
+					return errMissingHeader
+				}
+
+				var request []byte
+
+				err := stream.RecvMsg(&request)
+				if err != nil {
+					return err
+				}
+
+				for {
+					select {
+					case <-stream.Context().Done():
+						return nil
+					case message := <-messages:
+						err = stream.SendMsg(&message)
+						if err != nil {
+							return err
+						}
+					}
+				}
+			},
+		}},
+	}, nil)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	return "unix://" + socket
+}
+
+func TestSourceFollowsWorkloadAPI(t *testing.T) {
+	t.Parallel()
+
+	ca, caKey := newCA(t)
+	federatedCA, _ := newCA(t)
+
+	messages := make(chan []byte, 1)
+	messages <- newSVIDMessage(t, "spiffe://example.org/kommodity", ca, caKey, federatedCA)
+
+	source, err := spiffe.NewSource(t.Context(), serveWorkloadAPI(t, messages))
+	if err != nil {
+		t.Fatalf("failed to get SVID: %v", err)
+	}
+
+	if source.ID() != "spiffe://example.org/kommodity" {
+		t.Fatalf("expected the SPIFFE ID of the SVID, got %s", source.ID())
+	}
+
+	certificate, err := source.GetCertificateFunc()(&tls.ClientHelloInfo{})
+	if err != nil || certificate.Leaf.CheckSignatureFrom(ca) != nil {
+		t.Fatalf("expected the SVID issued by the CA, got %v", err)
+	}
+
+	if len(source.Bundle()) != 2 || !source.Bundle()[0].Equal(ca) || !source.Bundle()[1].Equal(federatedCA) {
+		t.Fatalf("expected the bundles of both trust domains, got %d certificates", len(source.Bundle()))
+	}
+
+	// Rotated SVIDs are picked up while serving.
+	messages <- newSVIDMessage(t, "spiffe://example.org/kommodity-rotated", ca, caKey, federatedCA)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for source.ID() != "spiffe://example.org/kommodity-rotated" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the rotated SVID to be picked up")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewSourceRejectsUnsupportedEndpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, endpoint := range []string{"tcp://127.0.0.1:8081", "unix://relative.sock", "/run/agent.sock"} {
+		_, err := spiffe.NewSource(t.Context(), endpoint)
+		if !errors.Is(err, spiffe.ErrUnsupportedEndpoint) {
+			t.Fatalf("expected %q to be rejected, got %v", endpoint, err)
+		}
+	}
+}
+
+func TestMatchesID(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"spiffe://example.org/talos", "spiffe://other.example"}
+
+	tests := map[string]bool{
+		"spiffe://example.org/talos":          true,
+		"spiffe://example.org/talos/worker-0": true,
+		"spiffe://example.org/talos-evil":     false,
+		"spiffe://example.org/kommodity":      false,
+		"spiffe://other.example/anything":     true,
+		"spiffe://other.example.evil/x":       false,
+	}
+
+	for id, expected := range tests {
+		if spiffe.MatchesID(id, allowed) != expected {
+			t.Fatalf("expected %s to match %t", id, expected)
+		}
+	}
+
+	if domain := spiffe.TrustDomain("spiffe://example.org/talos/worker-0"); domain != "spiffe://example.org" {
+		t.Fatalf("expected the trust domain spiffe://example.org, got %s", domain)
+	}
+}
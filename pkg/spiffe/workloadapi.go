@@ -0,0 +1,168 @@
+package spiffe
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// fetchX509SVIDMethod streams the X.509 SVIDs and bundles of the workload, see
+	// https://github.com/spiffe/spiffe/blob/main/standards/SPIFFE_Workload_API.md.
+	fetchX509SVIDMethod = "/SpiffeWorkloadAPI/FetchX509SVID"
+	// workloadAPIHeader has to be set on all calls, so that the agent can tell them
+	// apart from requests forwarded by a proxy (SSRF).
+	workloadAPIHeader = "workload.spiffe.io"
+
+	// Field numbers of X509SVIDResponse.
+	responseSVIDsField            protowire.Number = 1
+	responseFederatedBundlesField protowire.Number = 3
+
+	// Field numbers of X509SVID.
+	svidIDField     protowire.Number = 1
+	svidChainField  protowire.Number = 2
+	svidKeyField    protowire.Number = 3
+	svidBundleField protowire.Number = 4
+
+	// Field number of the values of map entries.
+	mapValueField protowire.Number = 2
+)
+
+// x509SVIDResponse holds the fields of X509SVIDResponse that Kommodity uses. The
+// certificates and keys are ASN.1 DER encoded.
+type x509SVIDResponse struct {
+	svids            []x509SVID
+	federatedBundles [][]byte
+}
+
+type x509SVID struct {
+	id     string
+	chain  []byte
+	key    []byte
+	bundle []byte
+}
+
+// rawCodec passes the messages of the Workload API as they are, as they are encoded
+// and decoded by hand instead of with generated code. It is named proto so that the
+// agent accepts the content type.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	message, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnexpectedMessage, v)
+	}
+
+	return *message, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	message, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnexpectedMessage, v)
+	}
+
+	*message = append((*message)[:0], data...)
+
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// parseX509SVIDResponse decodes an X509SVIDResponse. Unknown fields, such as the
+// CRLs, are skipped.
+func parseX509SVIDResponse(data []byte) (*x509SVIDResponse, error) {
+	response := &x509SVIDResponse{}
+
+	err := forEachField(data, func(number protowire.Number, value []byte) error {
+		switch number {
+		case responseSVIDsField:
+			svid, err := parseX509SVID(value)
+			if err != nil {
+				return err
+			}
+
+			response.svids = append(response.svids, *svid)
+		case responseFederatedBundlesField:
+			// Map entries are messages with the trust domain as key and the bundle as value.
+			return forEachField(value, func(number protowire.Number, value []byte) error {
+				if number == mapValueField {
+					response.federatedBundles = append(response.federatedBundles, value)
+				}
+
+				return nil
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func parseX509SVID(data []byte) (*x509SVID, error) {
+	svid := &x509SVID{}
+
+	err := forEachField(data, func(number protowire.Number, value []byte) error {
+		switch number {
+		case svidIDField:
+			svid.id = string(value)
+		case svidChainField:
+			svid.chain = value
+		case svidKeyField:
+			svid.key = value
+		case svidBundleField:
+			svid.bundle = value
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svid, nil
+}
+
+// forEachField calls the function with the length-delimited fields of the message,
+// which are the only ones of the Workload API messages Kommodity uses.
+func forEachField(data []byte, field func(protowire.Number, []byte) error) error {
+	for len(data) > 0 {
+		number, wireType, length := protowire.ConsumeTag(data)
+		if length < 0 {
+			return fmt.Errorf("%w: %w", ErrInvalidMessage, protowire.ParseError(length))
+		}
+
+		data = data[length:]
+
+		if wireType != protowire.BytesType {
+			length = protowire.ConsumeFieldValue(number, wireType, data)
+			if length < 0 {
+				return fmt.Errorf("%w: %w", ErrInvalidMessage, protowire.ParseError(length))
+			}
+
+			data = data[length:]
+
+			continue
+		}
+
+		value, length := protowire.ConsumeBytes(data)
+		if length < 0 {
+			return fmt.Errorf("%w: %w", ErrInvalidMessage, protowire.ParseError(length))
+		}
+
+		data = data[length:]
+
+		err := field(number, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
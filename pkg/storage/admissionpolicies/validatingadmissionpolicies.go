@@ -0,0 +1,288 @@
+package admissionpolicies
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/kommodity-io/kommodity/pkg/storage"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+	admissionregistrationapiv1 "k8s.io/kubernetes/pkg/apis/admissionregistration/v1"
+	admissionregistrationvalidation "k8s.io/kubernetes/pkg/apis/admissionregistration/validation"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+const validatingAdmissionPolicyResource = "validatingadmissionpolicies"
+
+// NewValidatingAdmissionPolicyREST creates a REST interface for admissionregistration v1
+// ValidatingAdmissionPolicy resources, along with their status subresource.
+func NewValidatingAdmissionPolicyREST(storageConfig storagebackend.Config,
+	scheme runtime.Scheme) (*genericregistry.Store, *StatusREST, error) {
+	store, destroy, err := factory.Create(
+		*storageConfig.ForResource(admissionregistrationv1.Resource(validatingAdmissionPolicyResource)),
+		func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicy{} },
+		func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicyList{} },
+		"/"+validatingAdmissionPolicyResource,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	dryRunnableStorage := genericregistry.DryRunnableStorage{
+		Storage: store,
+		Codec:   storageConfig.Codec,
+	}
+
+	policyStrategy := validatingAdmissionPolicyStrategy{
+		ObjectTyper:   &scheme,
+		NameGenerator: names.SimpleNameGenerator,
+	}
+
+	restStore := &genericregistry.Store{
+		NewFunc:       func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicy{} },
+		NewListFunc:   func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicyList{} },
+		PredicateFunc: storage.PredicateFunc(VAPGetAttrs),
+		KeyRootFunc:   func(_ context.Context) string { return "/" + validatingAdmissionPolicyResource },
+		KeyFunc: func(_ context.Context, name string) (string, error) {
+			return path.Join("/"+validatingAdmissionPolicyResource, name), nil
+		},
+		ObjectNameFunc:      VAPObjectNameFunc,
+		CreateStrategy:      policyStrategy,
+		UpdateStrategy:      policyStrategy,
+		DeleteStrategy:      policyStrategy,
+		ResetFieldsStrategy: policyStrategy,
+		Storage:             dryRunnableStorage,
+		TableConvertor:      storage.NewTableConvertor(),
+		DestroyFunc:         destroy,
+	}
+
+	statusStore := *restStore
+	statusStore.UpdateStrategy = validatingAdmissionPolicyStatusStrategy{policyStrategy}
+	statusStore.ResetFieldsStrategy = validatingAdmissionPolicyStatusStrategy{policyStrategy}
+
+	return restStore, &StatusREST{store: &statusStore}, nil
+}
+
+// VAPObjectNameFunc returns the name of the object.
+func VAPObjectNameFunc(obj runtime.Object) (string, error) {
+	policy, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !ok {
+		return "", storage.ErrObjectIsNotAValidatingAdmissionPolicy
+	}
+
+	return policy.Name, nil
+}
+
+// VAPGetAttrs returns labels and fields for a ValidatingAdmissionPolicy object.
+func VAPGetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	policy, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !ok {
+		return nil, nil, storage.ErrObjectIsNotAValidatingAdmissionPolicy
+	}
+
+	return labels.Set(policy.Labels), generic.ObjectMetaFieldsSet(&policy.ObjectMeta, false), nil
+}
+
+// validatingAdmissionPolicyStrategy implements RESTCreateStrategy, RESTUpdateStrategy, RESTDeleteStrategy
+// Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/admissionregistration/validatingadmissionpolicy/strategy.go
+//
+// Unlike upstream, callers are not authorized against the kind of the policy parameters,
+// as only administrators manage admission policies in Kommodity.
+//
+//nolint:lll
+type validatingAdmissionPolicyStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+var _ rest.RESTCreateStrategy = validatingAdmissionPolicyStrategy{}
+var _ rest.RESTUpdateStrategy = validatingAdmissionPolicyStrategy{}
+var _ rest.RESTDeleteStrategy = validatingAdmissionPolicyStrategy{}
+var _ rest.NamespaceScopedStrategy = validatingAdmissionPolicyStrategy{}
+
+// NamespaceScoped returns false as ValidatingAdmissionPolicy is not namespaced.
+func (validatingAdmissionPolicyStrategy) NamespaceScoped() bool {
+	return false
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (validatingAdmissionPolicyStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"admissionregistration.k8s.io/v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("status"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForCreate defaults the policy and clears its status.
+func (validatingAdmissionPolicyStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	policy, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !ok {
+		return
+	}
+
+	admissionregistrationapiv1.SetObjectDefaults_ValidatingAdmissionPolicy(policy)
+
+	policy.Status = admissionregistrationv1.ValidatingAdmissionPolicyStatus{}
+	policy.Generation = 1
+}
+
+// WarningsOnCreate returns warnings for create operations.
+func (validatingAdmissionPolicyStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForUpdate defaults the policy, keeps its status and bumps the generation when
+// the spec changes.
+func (validatingAdmissionPolicyStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newPolicy, okNew := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+
+	oldPolicy, okOld := old.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !okNew || !okOld {
+		return
+	}
+
+	admissionregistrationapiv1.SetObjectDefaults_ValidatingAdmissionPolicy(newPolicy)
+
+	newPolicy.Status = oldPolicy.Status
+
+	if !apiequality.Semantic.DeepEqual(newPolicy.Spec, oldPolicy.Spec) {
+		newPolicy.Generation = oldPolicy.Generation + 1
+	}
+}
+
+// WarningsOnUpdate returns warnings for update operations.
+func (validatingAdmissionPolicyStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
+	return nil
+}
+
+// Validate validates new objects with the validation of the upstream API server, which
+// also compiles the CEL expressions of the policy.
+func (validatingAdmissionPolicyStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	policy, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAValidatingAdmissionPolicy.Error())}
+	}
+
+	internal := &admissionregistration.ValidatingAdmissionPolicy{}
+
+	err := toInternal(policy, internal)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	return admissionregistrationvalidation.ValidateValidatingAdmissionPolicy(internal)
+}
+
+// ValidateUpdate validates updated objects.
+func (validatingAdmissionPolicyStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := policiesToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	return admissionregistrationvalidation.ValidateValidatingAdmissionPolicyUpdate(newInternal, oldInternal)
+}
+
+// Canonicalize normalizes objects.
+func (validatingAdmissionPolicyStrategy) Canonicalize(_ runtime.Object) {}
+
+// AllowCreateOnUpdate determines if create is allowed on update.
+func (validatingAdmissionPolicyStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// AllowUnconditionalUpdate determines if update can ignore resource version.
+func (validatingAdmissionPolicyStrategy) AllowUnconditionalUpdate() bool {
+	return false
+}
+
+// validatingAdmissionPolicyStatusStrategy implements behavior for the status subresource
+// of ValidatingAdmissionPolicies.
+type validatingAdmissionPolicyStatusStrategy struct {
+	validatingAdmissionPolicyStrategy
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (validatingAdmissionPolicyStatusStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"admissionregistration.k8s.io/v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("metadata"),
+			fieldpath.MakePathOrDie("spec"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForUpdate keeps everything but the status of the policy.
+func (validatingAdmissionPolicyStatusStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newPolicy, okNew := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+
+	oldPolicy, okOld := old.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !okNew || !okOld {
+		return
+	}
+
+	newPolicy.Spec = oldPolicy.Spec
+	metav1.ResetObjectMetaForStatus(&newPolicy.ObjectMeta, &oldPolicy.ObjectMeta)
+}
+
+// ValidateUpdate validates the status of updated objects.
+func (validatingAdmissionPolicyStatusStrategy) ValidateUpdate(_ context.Context,
+	obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := policiesToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	return admissionregistrationvalidation.ValidateValidatingAdmissionPolicyStatusUpdate(newInternal, oldInternal)
+}
+
+// policiesToInternal converts the new and old policy of an update to their internal
+// versions.
+func policiesToInternal(obj, old runtime.Object) (*admissionregistration.ValidatingAdmissionPolicy,
+	*admissionregistration.ValidatingAdmissionPolicy, field.ErrorList) {
+	newPolicy, okNew := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+
+	oldPolicy, okOld := old.(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	if !okNew || !okOld {
+		return nil, nil, field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAValidatingAdmissionPolicy.Error())}
+	}
+
+	newInternal := &admissionregistration.ValidatingAdmissionPolicy{}
+	oldInternal := &admissionregistration.ValidatingAdmissionPolicy{}
+
+	err := toInternal(newPolicy, newInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	err = toInternal(oldPolicy, oldInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	return newInternal, oldInternal, nil
+}
@@ -0,0 +1,177 @@
+package admissionpolicies_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/admissionpolicies"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newPolicy(name, expression string) *admissionregistrationv1.ValidatingAdmissionPolicy {
+	return &admissionregistrationv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"policy": name},
+		},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+					RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{"cluster.x-k8s.io"},
+							APIVersions: []string{"*"},
+							Resources:   []string{"clusters"},
+						},
+					},
+				}},
+			},
+			Validations: []admissionregistrationv1.Validation{{Expression: expression}},
+		},
+	}
+}
+
+func newPolicyStorage(tb testing.TB) (*genericregistry.Store, *admissionpolicies.StatusREST) {
+	tb.Helper()
+
+	storageConfig, scheme := storagetest.NewStorageConfig(tb, admissionregistrationv1.SchemeGroupVersion)
+
+	storage, status, err := admissionpolicies.NewValidatingAdmissionPolicyREST(storageConfig, *scheme)
+	if err != nil {
+		tb.Fatalf("failed to create validating admission policies storage: %v", err)
+	}
+
+	return storage, status
+}
+
+func policyContext() context.Context {
+	ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceNone)
+
+	return genericapirequest.WithRequestInfo(ctx, &genericapirequest.RequestInfo{
+		IsResourceRequest: true,
+		APIGroup:          admissionregistrationv1.GroupName,
+		APIVersion:        admissionregistrationv1.SchemeGroupVersion.Version,
+		Resource:          "validatingadmissionpolicies",
+	})
+}
+
+func TestValidatingAdmissionPoliciesStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storage, _ := newPolicyStorage(tb)
+
+			return storage
+		},
+		GroupVersion: admissionregistrationv1.SchemeGroupVersion,
+		Resource:     "validatingadmissionpolicies",
+		NewObject: func(name string) runtime.Object {
+			return newPolicy(name, "has(object.metadata.labels)")
+		},
+		Mutate: func(obj runtime.Object) {
+			//nolint:forcetypeassert // Always a ValidatingAdmissionPolicy.
+			obj.(*admissionregistrationv1.ValidatingAdmissionPolicy).Spec.Validations[0].Message = "labels required"
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "policy label",
+				Objects: []runtime.Object{
+					newPolicy("first", "true"),
+					newPolicy("second", "true"),
+				},
+				LabelSelector: "policy=second",
+				Expected:      []string{"second"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{
+				Name:   "expression not compiling",
+				Object: newPolicy("invalid", "object.("),
+			},
+			{
+				Name: "missing match constraints",
+				Object: func() runtime.Object {
+					policy := newPolicy("invalid", "true")
+					policy.Spec.MatchConstraints = nil
+
+					return policy
+				}(),
+			},
+			{
+				Name: "no validations",
+				Object: func() runtime.Object {
+					policy := newPolicy("invalid", "true")
+					policy.Spec.Validations = nil
+
+					return policy
+				}(),
+			},
+		},
+	})
+}
+
+func TestValidatingAdmissionPolicyStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctx := policyContext()
+	storage, status := newPolicyStorage(t)
+
+	t.Cleanup(storage.Destroy)
+
+	policy := newPolicy("require-labels", "has(object.metadata.labels)")
+	policy.Status.ObservedGeneration = 7
+
+	obj, err := storage.Create(ctx, policy, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	created := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy) //nolint:forcetypeassert // Always a policy.
+	if created.Generation != 1 || created.Status.ObservedGeneration != 0 {
+		t.Fatalf("expected generation 1 and an empty status, got %d and %+v", created.Generation, created.Status)
+	}
+
+	if created.Spec.FailurePolicy == nil || *created.Spec.FailurePolicy != admissionregistrationv1.Fail ||
+		created.Spec.MatchConstraints.MatchPolicy == nil {
+		t.Fatalf("expected the policy to be defaulted, got %+v", created.Spec)
+	}
+
+	created.Status = admissionregistrationv1.ValidatingAdmissionPolicyStatus{ObservedGeneration: 1}
+	created.Spec.Validations[0].Expression = "true"
+
+	obj, _, err = status.Update(ctx, created.Name, rest.DefaultUpdatedObjectInfo(created),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update policy status: %v", err)
+	}
+
+	updated := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy) //nolint:forcetypeassert // Always a policy.
+	if updated.Status.ObservedGeneration != 1 || updated.Spec.Validations[0].Expression == "true" ||
+		updated.Generation != 1 {
+		t.Fatalf("expected the status subresource to only update the status, got %+v", updated)
+	}
+
+	updated.Spec.Validations[0].Expression = "true"
+	updated.Status = admissionregistrationv1.ValidatingAdmissionPolicyStatus{}
+
+	obj, _, err = storage.Update(ctx, updated.Name, rest.DefaultUpdatedObjectInfo(updated),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update policy: %v", err)
+	}
+
+	changed := obj.(*admissionregistrationv1.ValidatingAdmissionPolicy) //nolint:forcetypeassert // Always a policy.
+	if changed.Generation != 2 || changed.Status.ObservedGeneration != 1 {
+		t.Fatalf("expected a spec change to bump the generation and keep the status, got %+v", changed)
+	}
+}
@@ -0,0 +1,208 @@
+package admissionpolicies
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/kommodity-io/kommodity/pkg/storage"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+	admissionregistrationapiv1 "k8s.io/kubernetes/pkg/apis/admissionregistration/v1"
+	admissionregistrationvalidation "k8s.io/kubernetes/pkg/apis/admissionregistration/validation"
+)
+
+const validatingAdmissionPolicyBindingResource = "validatingadmissionpolicybindings"
+
+// NewValidatingAdmissionPolicyBindingREST creates a REST interface for admissionregistration
+// v1 ValidatingAdmissionPolicyBinding resources.
+func NewValidatingAdmissionPolicyBindingREST(storageConfig storagebackend.Config,
+	scheme runtime.Scheme) (*genericregistry.Store, error) {
+	store, destroy, err := factory.Create(
+		*storageConfig.ForResource(admissionregistrationv1.Resource(validatingAdmissionPolicyBindingResource)),
+		func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicyBinding{} },
+		func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicyBindingList{} },
+		"/"+validatingAdmissionPolicyBindingResource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	dryRunnableStorage := genericregistry.DryRunnableStorage{
+		Storage: store,
+		Codec:   storageConfig.Codec,
+	}
+
+	bindingStrategy := validatingAdmissionPolicyBindingStrategy{
+		ObjectTyper:   &scheme,
+		NameGenerator: names.SimpleNameGenerator,
+	}
+
+	return &genericregistry.Store{
+		NewFunc:       func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicyBinding{} },
+		NewListFunc:   func() runtime.Object { return &admissionregistrationv1.ValidatingAdmissionPolicyBindingList{} },
+		PredicateFunc: storage.PredicateFunc(VAPBGetAttrs),
+		KeyRootFunc:   func(_ context.Context) string { return "/" + validatingAdmissionPolicyBindingResource },
+		KeyFunc: func(_ context.Context, name string) (string, error) {
+			return path.Join("/"+validatingAdmissionPolicyBindingResource, name), nil
+		},
+		ObjectNameFunc: VAPBObjectNameFunc,
+		CreateStrategy: bindingStrategy,
+		UpdateStrategy: bindingStrategy,
+		DeleteStrategy: bindingStrategy,
+		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
+	}, nil
+}
+
+// VAPBObjectNameFunc returns the name of the object.
+func VAPBObjectNameFunc(obj runtime.Object) (string, error) {
+	binding, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	if !ok {
+		return "", storage.ErrObjectIsNotAValidatingAdmissionPolicyBinding
+	}
+
+	return binding.Name, nil
+}
+
+// VAPBGetAttrs returns labels and fields for a ValidatingAdmissionPolicyBinding object.
+func VAPBGetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	binding, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	if !ok {
+		return nil, nil, storage.ErrObjectIsNotAValidatingAdmissionPolicyBinding
+	}
+
+	return labels.Set(binding.Labels), generic.ObjectMetaFieldsSet(&binding.ObjectMeta, false), nil
+}
+
+// validatingAdmissionPolicyBindingStrategy implements RESTCreateStrategy, RESTUpdateStrategy, RESTDeleteStrategy
+// Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/admissionregistration/validatingadmissionpolicybinding/strategy.go
+//
+// Unlike upstream, callers are not authorized against the parameter resources the
+// binding refers to, as only administrators manage admission policies in Kommodity.
+//
+//nolint:lll
+type validatingAdmissionPolicyBindingStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+var _ rest.RESTCreateStrategy = validatingAdmissionPolicyBindingStrategy{}
+var _ rest.RESTUpdateStrategy = validatingAdmissionPolicyBindingStrategy{}
+var _ rest.RESTDeleteStrategy = validatingAdmissionPolicyBindingStrategy{}
+var _ rest.NamespaceScopedStrategy = validatingAdmissionPolicyBindingStrategy{}
+
+// NamespaceScoped returns false as ValidatingAdmissionPolicyBinding is not namespaced.
+func (validatingAdmissionPolicyBindingStrategy) NamespaceScoped() bool {
+	return false
+}
+
+// PrepareForCreate defaults the binding.
+func (validatingAdmissionPolicyBindingStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	binding, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	if !ok {
+		return
+	}
+
+	admissionregistrationapiv1.SetObjectDefaults_ValidatingAdmissionPolicyBinding(binding)
+
+	binding.Generation = 1
+}
+
+// WarningsOnCreate returns warnings for create operations.
+func (validatingAdmissionPolicyBindingStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForUpdate defaults the binding and bumps the generation when the spec changes.
+func (validatingAdmissionPolicyBindingStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newBinding, okNew := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+
+	oldBinding, okOld := old.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	if !okNew || !okOld {
+		return
+	}
+
+	admissionregistrationapiv1.SetObjectDefaults_ValidatingAdmissionPolicyBinding(newBinding)
+
+	if !apiequality.Semantic.DeepEqual(newBinding.Spec, oldBinding.Spec) {
+		newBinding.Generation = oldBinding.Generation + 1
+	}
+}
+
+// WarningsOnUpdate returns warnings for update operations.
+func (validatingAdmissionPolicyBindingStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
+	return nil
+}
+
+// Validate validates new objects with the validation of the upstream API server.
+func (validatingAdmissionPolicyBindingStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	binding, ok := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAValidatingAdmissionPolicyBinding.Error())}
+	}
+
+	internal := &admissionregistration.ValidatingAdmissionPolicyBinding{}
+
+	err := toInternal(binding, internal)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	return admissionregistrationvalidation.ValidateValidatingAdmissionPolicyBinding(internal)
+}
+
+// ValidateUpdate validates updated objects.
+func (validatingAdmissionPolicyBindingStrategy) ValidateUpdate(_ context.Context,
+	obj, old runtime.Object) field.ErrorList {
+	newBinding, okNew := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+
+	oldBinding, okOld := old.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	if !okNew || !okOld {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAValidatingAdmissionPolicyBinding.Error())}
+	}
+
+	newInternal := &admissionregistration.ValidatingAdmissionPolicyBinding{}
+	oldInternal := &admissionregistration.ValidatingAdmissionPolicyBinding{}
+
+	err := toInternal(newBinding, newInternal)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	err = toInternal(oldBinding, oldInternal)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	return admissionregistrationvalidation.ValidateValidatingAdmissionPolicyBindingUpdate(newInternal, oldInternal)
+}
+
+// Canonicalize normalizes objects.
+func (validatingAdmissionPolicyBindingStrategy) Canonicalize(_ runtime.Object) {}
+
+// AllowCreateOnUpdate determines if create is allowed on update.
+func (validatingAdmissionPolicyBindingStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// AllowUnconditionalUpdate determines if update can ignore resource version.
+func (validatingAdmissionPolicyBindingStrategy) AllowUnconditionalUpdate() bool {
+	return false
+}
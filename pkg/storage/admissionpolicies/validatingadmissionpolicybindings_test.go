@@ -0,0 +1,80 @@
+package admissionpolicies_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/admissionpolicies"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newBinding(name, policyName string) *admissionregistrationv1.ValidatingAdmissionPolicyBinding {
+	return &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"policy": policyName},
+		},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        policyName,
+			ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+		},
+	}
+}
+
+func TestValidatingAdmissionPolicyBindingsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, admissionregistrationv1.SchemeGroupVersion)
+
+			storage, err := admissionpolicies.NewValidatingAdmissionPolicyBindingREST(storageConfig, *scheme)
+			if err != nil {
+				tb.Fatalf("failed to create validating admission policy bindings storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: admissionregistrationv1.SchemeGroupVersion,
+		Resource:     "validatingadmissionpolicybindings",
+		NewObject: func(name string) runtime.Object {
+			return newBinding(name, "require-labels")
+		},
+		Mutate: func(obj runtime.Object) {
+			//nolint:forcetypeassert // Always a ValidatingAdmissionPolicyBinding.
+			binding := obj.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+			binding.Spec.ValidationActions = append(binding.Spec.ValidationActions, admissionregistrationv1.Audit)
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "policy label",
+				Objects: []runtime.Object{
+					newBinding("first", "require-labels"),
+					newBinding("second", "allowed-sizes"),
+				},
+				LabelSelector: "policy=allowed-sizes",
+				Expected:      []string{"second"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{
+				Name:   "missing policy name",
+				Object: newBinding("invalid", ""),
+			},
+			{
+				Name: "no validation actions",
+				Object: func() runtime.Object {
+					binding := newBinding("invalid", "require-labels")
+					binding.Spec.ValidationActions = nil
+
+					return binding
+				}(),
+			},
+		},
+	})
+}
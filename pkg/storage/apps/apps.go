@@ -0,0 +1,74 @@
+// Package apps implements the storage strategy towards kine for the apps v1 Deployment
+// and ReplicaSet resources. Kommodity runs no controllers for them: the objects are
+// stored and validated like the upstream API server does, for external runners to
+// reconcile.
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	appsinstall "k8s.io/kubernetes/pkg/apis/apps/install"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// StatusREST implements the REST endpoint for the status subresource of Deployments
+// and ReplicaSets.
+type StatusREST struct {
+	store *genericregistry.Store
+}
+
+// New returns an empty object of the resource.
+func (r *StatusREST) New() runtime.Object {
+	return r.store.New()
+}
+
+// Destroy is a no-op, the store is shared with the main resource.
+func (r *StatusREST) Destroy() {}
+
+// Get retrieves the object, it is required to support Patch.
+func (r *StatusREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return r.store.Get(ctx, name, options) //nolint:wrapcheck // API status errors must be returned as is.
+}
+
+// GetResetFields returns the fields the status subresource does not let callers change.
+func (r *StatusREST) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	return r.store.GetResetFields()
+}
+
+// Update alters the status of an object.
+func (r *StatusREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo,
+	createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc,
+	_ bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	// Subresources never create objects.
+	//nolint:wrapcheck // API status errors must be returned as is.
+	return r.store.Update(ctx, name, objInfo, createValidation, updateValidation, false, options)
+}
+
+// internalScheme converts the stored apps v1 objects to the internal types the
+// upstream validation is written against.
+//
+//nolint:gochecknoglobals // Built once, only read afterwards.
+var internalScheme = newInternalScheme()
+
+func newInternalScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	appsinstall.Install(scheme)
+
+	return scheme
+}
+
+// toInternal converts the apps v1 object in to its internal version out.
+func toInternal(in, out runtime.Object) *field.Error {
+	err := internalScheme.Convert(in, out, nil)
+	if err != nil {
+		return field.InternalError(field.NewPath("object"), fmt.Errorf("failed to convert %T: %w", in, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/storage"
+	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/kubernetes/pkg/api/pod"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	appsapiv1 "k8s.io/kubernetes/pkg/apis/apps/v1"
+	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+const deploymentResource = "deployments"
+
+// DeploymentREST wraps a Store and implements rest.Scoper.
+type DeploymentREST struct {
+	*genericregistry.Store
+}
+
+var _ rest.ShortNamesProvider = &DeploymentREST{}
+
+// ShortNames implement ShortNamesProvider to return short names for the resource.
+func (*DeploymentREST) ShortNames() []string {
+	return []string{"deploy"}
+}
+
+// NewDeploymentREST creates a REST interface for appsv1 Deployment resource, along with
+// the status subresource written by the runners that reconcile Deployments.
+//
+//nolint:dupl // Similar to NewReplicaSetREST but not identical.
+func NewDeploymentREST(storageConfig storagebackend.Config,
+	scheme runtime.Scheme) (*DeploymentREST, *StatusREST, error) {
+	store, destroy, err := factory.Create(
+		*storageConfig.ForResource(appsv1.Resource(deploymentResource)),
+		func() runtime.Object { return &appsv1.Deployment{} },
+		func() runtime.Object { return &appsv1.DeploymentList{} },
+		"/"+deploymentResource,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	dryRunnableStorage := genericregistry.DryRunnableStorage{
+		Storage: store,
+		Codec:   storageConfig.Codec,
+	}
+
+	deploymentStrategy := deploymentStrategy{
+		ObjectTyper:   &scheme,
+		NameGenerator: names.SimpleNameGenerator,
+	}
+
+	restStore := &genericregistry.Store{
+		NewFunc:       func() runtime.Object { return &appsv1.Deployment{} },
+		NewListFunc:   func() runtime.Object { return &appsv1.DeploymentList{} },
+		PredicateFunc: storage.NamespacedPredicateFunc(),
+		// Unlike most built-in resources served by Kommodity, workloads of different
+		// namespaces commonly share names, so the namespace is part of the key.
+		KeyRootFunc: func(ctx context.Context) string {
+			return genericregistry.NamespaceKeyRootFunc(ctx, "/"+deploymentResource)
+		},
+		KeyFunc: func(ctx context.Context, name string) (string, error) {
+			//nolint:wrapcheck // API status errors must be returned as is.
+			return genericregistry.NamespaceKeyFunc(ctx, "/"+deploymentResource, name)
+		},
+		ObjectNameFunc:      ObjectNameFuncDeployment,
+		CreateStrategy:      deploymentStrategy,
+		UpdateStrategy:      deploymentStrategy,
+		DeleteStrategy:      deploymentStrategy,
+		ResetFieldsStrategy: deploymentStrategy,
+		Storage:             dryRunnableStorage,
+		TableConvertor:      storage.NewTableConvertor(),
+		DestroyFunc:         destroy,
+	}
+
+	statusStore := *restStore
+	statusStore.UpdateStrategy = deploymentStatusStrategy{deploymentStrategy}
+	statusStore.ResetFieldsStrategy = deploymentStatusStrategy{deploymentStrategy}
+
+	return &DeploymentREST{restStore}, &StatusREST{store: &statusStore}, nil
+}
+
+// ObjectNameFuncDeployment returns the name of the object.
+func ObjectNameFuncDeployment(obj runtime.Object) (string, error) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return "", storage.ErrObjectIsNotADeployment
+	}
+
+	return deployment.Name, nil
+}
+
+// deploymentStrategy implements RESTCreateStrategy, RESTUpdateStrategy, RESTDeleteStrategy
+// Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/apps/deployment/strategy.go
+type deploymentStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+var _ rest.RESTCreateStrategy = deploymentStrategy{}
+var _ rest.RESTUpdateStrategy = deploymentStrategy{}
+var _ rest.RESTDeleteStrategy = deploymentStrategy{}
+var _ rest.NamespaceScopedStrategy = deploymentStrategy{}
+
+// NamespaceScoped tells the apiserver if the resource lives in a namespace.
+func (deploymentStrategy) NamespaceScoped() bool {
+	return true
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (deploymentStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"apps/v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("status"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForCreate defaults the Deployment and clears its status.
+func (deploymentStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+
+	appsapiv1.SetObjectDefaults_Deployment(deployment)
+
+	deployment.Status = appsv1.DeploymentStatus{}
+	deployment.Generation = 1
+}
+
+// WarningsOnCreate returns warnings for create operations.
+func (deploymentStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForUpdate defaults the Deployment, keeps its status and bumps the generation
+// when the spec changes.
+func (deploymentStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newDeployment, okNew := obj.(*appsv1.Deployment)
+
+	oldDeployment, okOld := old.(*appsv1.Deployment)
+	if !okNew || !okOld {
+		return
+	}
+
+	appsapiv1.SetObjectDefaults_Deployment(newDeployment)
+
+	newDeployment.Status = oldDeployment.Status
+
+	if !apiequality.Semantic.DeepEqual(newDeployment.Spec, oldDeployment.Spec) {
+		newDeployment.Generation = oldDeployment.Generation + 1
+	}
+}
+
+// WarningsOnUpdate returns warnings for update operations.
+func (deploymentStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForDelete clears fields before deletion.
+func (deploymentStrategy) PrepareForDelete(_ context.Context, _ runtime.Object) {}
+
+// Validate validates new objects with the validation of the upstream API server.
+func (deploymentStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotADeployment.Error())}
+	}
+
+	internal := &apps.Deployment{}
+
+	err := toInternal(deployment, internal)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	opts := pod.GetValidationOptionsFromPodTemplate(&internal.Spec.Template, nil)
+
+	return appsvalidation.ValidateDeployment(internal, opts)
+}
+
+// ValidateUpdate validates updated objects. As in apps/v1 upstream, the selector of a
+// Deployment is immutable.
+func (deploymentStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := deploymentsToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	opts := pod.GetValidationOptionsFromPodTemplate(&newInternal.Spec.Template, &oldInternal.Spec.Template)
+
+	allErrs := appsvalidation.ValidateDeploymentUpdate(newInternal, oldInternal, opts)
+	allErrs = append(allErrs, validation.ValidateImmutableField(newInternal.Spec.Selector,
+		oldInternal.Spec.Selector, field.NewPath("spec", "selector"))...)
+
+	return allErrs
+}
+
+// Canonicalize normalizes objects.
+func (deploymentStrategy) Canonicalize(_ runtime.Object) {}
+
+// AllowCreateOnUpdate determines if create is allowed on update.
+func (deploymentStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// AllowUnconditionalUpdate determines if update can ignore resource version.
+func (deploymentStrategy) AllowUnconditionalUpdate() bool {
+	return true
+}
+
+// deploymentStatusStrategy implements behavior for the Deployment status subresource.
+type deploymentStatusStrategy struct {
+	deploymentStrategy
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (deploymentStatusStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"apps/v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("metadata"),
+			fieldpath.MakePathOrDie("spec"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForUpdate keeps everything but the status of the Deployment.
+func (deploymentStatusStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newDeployment, okNew := obj.(*appsv1.Deployment)
+
+	oldDeployment, okOld := old.(*appsv1.Deployment)
+	if !okNew || !okOld {
+		return
+	}
+
+	newDeployment.Spec = oldDeployment.Spec
+	metav1.ResetObjectMetaForStatus(&newDeployment.ObjectMeta, &oldDeployment.ObjectMeta)
+}
+
+// ValidateUpdate validates the status of updated objects.
+func (deploymentStatusStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := deploymentsToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	return appsvalidation.ValidateDeploymentStatusUpdate(newInternal, oldInternal)
+}
+
+// deploymentsToInternal converts the new and old Deployment of an update to their
+// internal versions.
+func deploymentsToInternal(obj, old runtime.Object) (*apps.Deployment, *apps.Deployment, field.ErrorList) {
+	newDeployment, okNew := obj.(*appsv1.Deployment)
+
+	oldDeployment, okOld := old.(*appsv1.Deployment)
+	if !okNew || !okOld {
+		return nil, nil, field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotADeployment.Error())}
+	}
+
+	newInternal, oldInternal := &apps.Deployment{}, &apps.Deployment{}
+
+	err := toInternal(newDeployment, newInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	err = toInternal(oldDeployment, oldInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	return newInternal, oldInternal, nil
+}
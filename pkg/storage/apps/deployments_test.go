@@ -0,0 +1,225 @@
+package apps_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/apps"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/utils/ptr"
+)
+
+func newPodTemplate(app string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": app}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: app, Image: "registry.example.com/" + app + ":v1"}},
+		},
+	}
+}
+
+func newDeployment(name, app string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{"app": app},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			Template: newPodTemplate(app),
+		},
+	}
+}
+
+func newDeploymentStorage(tb testing.TB) (*apps.DeploymentREST, *apps.StatusREST) {
+	tb.Helper()
+
+	storageConfig, scheme := storagetest.NewStorageConfig(tb, appsv1.SchemeGroupVersion)
+
+	storage, status, err := apps.NewDeploymentREST(storageConfig, *scheme)
+	if err != nil {
+		tb.Fatalf("failed to create deployments storage: %v", err)
+	}
+
+	return storage, status
+}
+
+func deploymentContext(namespace string) context.Context {
+	ctx := genericapirequest.WithNamespace(context.Background(), namespace)
+
+	return genericapirequest.WithRequestInfo(ctx, &genericapirequest.RequestInfo{
+		IsResourceRequest: true,
+		APIGroup:          appsv1.GroupName,
+		APIVersion:        appsv1.SchemeGroupVersion.Version,
+		Namespace:         namespace,
+		Resource:          "deployments",
+	})
+}
+
+func TestDeploymentsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storage, _ := newDeploymentStorage(tb)
+
+			return storage
+		},
+		GroupVersion: appsv1.SchemeGroupVersion,
+		Resource:     "deployments",
+		Namespace:    metav1.NamespaceDefault,
+		NewObject: func(name string) runtime.Object {
+			return newDeployment(name, "web")
+		},
+		Mutate: func(obj runtime.Object) {
+			obj.(*appsv1.Deployment).Spec.Replicas = ptr.To[int32](3) //nolint:forcetypeassert // Always a Deployment.
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "app label",
+				Objects: []runtime.Object{
+					newDeployment("first", "web"),
+					newDeployment("second", "api"),
+				},
+				LabelSelector: "app=api",
+				Expected:      []string{"second"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{
+				Name: "missing selector",
+				Object: func() runtime.Object {
+					deployment := newDeployment("invalid", "web")
+					deployment.Spec.Selector = nil
+
+					return deployment
+				}(),
+			},
+			{
+				Name: "selector not matching the template",
+				Object: func() runtime.Object {
+					deployment := newDeployment("invalid", "web")
+					deployment.Spec.Template.Labels = map[string]string{"app": "api"}
+
+					return deployment
+				}(),
+			},
+			{
+				Name: "negative replicas",
+				Object: func() runtime.Object {
+					deployment := newDeployment("invalid", "web")
+					deployment.Spec.Replicas = ptr.To[int32](-1)
+
+					return deployment
+				}(),
+			},
+			{
+				Name:   "no containers",
+				Object: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "invalid"}},
+			},
+		},
+	})
+}
+
+func TestDeploymentStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctx := deploymentContext(metav1.NamespaceDefault)
+	storage, status := newDeploymentStorage(t)
+
+	t.Cleanup(storage.Destroy)
+
+	deployment := newDeployment("web", "web")
+	deployment.Status.Replicas = 5
+
+	obj, err := storage.Create(ctx, deployment, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+
+	created := obj.(*appsv1.Deployment) //nolint:forcetypeassert // Always a Deployment.
+	if created.Generation != 1 || created.Status.Replicas != 0 {
+		t.Fatalf("expected generation 1 and an empty status, got %d and %+v", created.Generation, created.Status)
+	}
+
+	if created.Spec.Replicas == nil || *created.Spec.Replicas != 1 ||
+		created.Spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType ||
+		created.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyAlways {
+		t.Fatalf("expected the deployment to be defaulted, got %+v", created.Spec)
+	}
+
+	created.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, ReadyReplicas: 1}
+	created.Spec.Replicas = ptr.To[int32](4)
+
+	obj, _, err = status.Update(ctx, created.Name, rest.DefaultUpdatedObjectInfo(created),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update deployment status: %v", err)
+	}
+
+	updated := obj.(*appsv1.Deployment) //nolint:forcetypeassert // Always a Deployment.
+	if updated.Status.ReadyReplicas != 1 || *updated.Spec.Replicas != 1 || updated.Generation != 1 {
+		t.Fatalf("expected the status subresource to only update the status, got %+v", updated)
+	}
+
+	updated.Spec.Replicas = ptr.To[int32](2)
+	updated.Status = appsv1.DeploymentStatus{}
+
+	obj, _, err = storage.Update(ctx, updated.Name, rest.DefaultUpdatedObjectInfo(updated),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+
+	scaled := obj.(*appsv1.Deployment) //nolint:forcetypeassert // Always a Deployment.
+	if scaled.Generation != 2 || scaled.Status.ReadyReplicas != 1 {
+		t.Fatalf("expected a spec change to bump the generation and keep the status, got %+v", scaled)
+	}
+
+	scaled.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web", "tier": "front"}}
+	scaled.Spec.Template.Labels["tier"] = "front"
+
+	_, _, err = storage.Update(ctx, scaled.Name, rest.DefaultUpdatedObjectInfo(scaled),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected Invalid when changing the selector, got %v", err)
+	}
+}
+
+func TestDeploymentsAreKeyedByNamespace(t *testing.T) {
+	t.Parallel()
+
+	storage, _ := newDeploymentStorage(t)
+
+	t.Cleanup(storage.Destroy)
+
+	for _, namespace := range []string{"team-a", "team-b"} {
+		deployment := newDeployment("web", "web")
+		deployment.Namespace = namespace
+
+		_, err := storage.Create(deploymentContext(namespace), deployment,
+			rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("failed to create deployment in %s: %v", namespace, err)
+		}
+	}
+
+	obj, err := storage.Get(deploymentContext("team-b"), "web", &metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+
+	if namespace := obj.(*appsv1.Deployment).Namespace; namespace != "team-b" { //nolint:forcetypeassert // Always a Deployment.
+		t.Fatalf("expected the deployment of team-b, got the one of %s", namespace)
+	}
+}
@@ -0,0 +1,11 @@
+package apps_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+)
+
+func TestMain(m *testing.M) {
+	storagetest.VerifyTestMain(m)
+}
@@ -0,0 +1,296 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/storage"
+	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/kubernetes/pkg/api/pod"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	appsapiv1 "k8s.io/kubernetes/pkg/apis/apps/v1"
+	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+const replicaSetResource = "replicasets"
+
+// ReplicaSetREST wraps a Store and implements rest.Scoper.
+type ReplicaSetREST struct {
+	*genericregistry.Store
+}
+
+var _ rest.ShortNamesProvider = &ReplicaSetREST{}
+
+// ShortNames implement ShortNamesProvider to return short names for the resource.
+func (*ReplicaSetREST) ShortNames() []string {
+	return []string{"rs"}
+}
+
+// NewReplicaSetREST creates a REST interface for appsv1 ReplicaSet resource, along with
+// the status subresource written by the runners that reconcile ReplicaSets.
+//
+//nolint:dupl // Similar to NewDeploymentREST but not identical.
+func NewReplicaSetREST(storageConfig storagebackend.Config,
+	scheme runtime.Scheme) (*ReplicaSetREST, *StatusREST, error) {
+	store, destroy, err := factory.Create(
+		*storageConfig.ForResource(appsv1.Resource(replicaSetResource)),
+		func() runtime.Object { return &appsv1.ReplicaSet{} },
+		func() runtime.Object { return &appsv1.ReplicaSetList{} },
+		"/"+replicaSetResource,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	dryRunnableStorage := genericregistry.DryRunnableStorage{
+		Storage: store,
+		Codec:   storageConfig.Codec,
+	}
+
+	replicaSetStrategy := replicaSetStrategy{
+		ObjectTyper:   &scheme,
+		NameGenerator: names.SimpleNameGenerator,
+	}
+
+	restStore := &genericregistry.Store{
+		NewFunc:       func() runtime.Object { return &appsv1.ReplicaSet{} },
+		NewListFunc:   func() runtime.Object { return &appsv1.ReplicaSetList{} },
+		PredicateFunc: storage.NamespacedPredicateFunc(),
+		// Keyed by namespace and name, like Deployments.
+		KeyRootFunc: func(ctx context.Context) string {
+			return genericregistry.NamespaceKeyRootFunc(ctx, "/"+replicaSetResource)
+		},
+		KeyFunc: func(ctx context.Context, name string) (string, error) {
+			//nolint:wrapcheck // API status errors must be returned as is.
+			return genericregistry.NamespaceKeyFunc(ctx, "/"+replicaSetResource, name)
+		},
+		ObjectNameFunc:      ObjectNameFuncReplicaSet,
+		CreateStrategy:      replicaSetStrategy,
+		UpdateStrategy:      replicaSetStrategy,
+		DeleteStrategy:      replicaSetStrategy,
+		ResetFieldsStrategy: replicaSetStrategy,
+		Storage:             dryRunnableStorage,
+		TableConvertor:      storage.NewTableConvertor(),
+		DestroyFunc:         destroy,
+	}
+
+	statusStore := *restStore
+	statusStore.UpdateStrategy = replicaSetStatusStrategy{replicaSetStrategy}
+	statusStore.ResetFieldsStrategy = replicaSetStatusStrategy{replicaSetStrategy}
+
+	return &ReplicaSetREST{restStore}, &StatusREST{store: &statusStore}, nil
+}
+
+// ObjectNameFuncReplicaSet returns the name of the object.
+func ObjectNameFuncReplicaSet(obj runtime.Object) (string, error) {
+	replicaSet, ok := obj.(*appsv1.ReplicaSet)
+	if !ok {
+		return "", storage.ErrObjectIsNotAReplicaSet
+	}
+
+	return replicaSet.Name, nil
+}
+
+// replicaSetStrategy implements RESTCreateStrategy, RESTUpdateStrategy, RESTDeleteStrategy
+// Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/apps/replicaset/strategy.go
+type replicaSetStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+var _ rest.RESTCreateStrategy = replicaSetStrategy{}
+var _ rest.RESTUpdateStrategy = replicaSetStrategy{}
+var _ rest.RESTDeleteStrategy = replicaSetStrategy{}
+var _ rest.NamespaceScopedStrategy = replicaSetStrategy{}
+
+// NamespaceScoped tells the apiserver if the resource lives in a namespace.
+func (replicaSetStrategy) NamespaceScoped() bool {
+	return true
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (replicaSetStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"apps/v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("status"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForCreate defaults the ReplicaSet and clears its status.
+func (replicaSetStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	replicaSet, ok := obj.(*appsv1.ReplicaSet)
+	if !ok {
+		return
+	}
+
+	appsapiv1.SetObjectDefaults_ReplicaSet(replicaSet)
+
+	replicaSet.Status = appsv1.ReplicaSetStatus{}
+	replicaSet.Generation = 1
+}
+
+// WarningsOnCreate returns warnings for create operations.
+func (replicaSetStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForUpdate defaults the ReplicaSet, keeps its status and bumps the generation
+// when the spec changes.
+func (replicaSetStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newReplicaSet, okNew := obj.(*appsv1.ReplicaSet)
+
+	oldReplicaSet, okOld := old.(*appsv1.ReplicaSet)
+	if !okNew || !okOld {
+		return
+	}
+
+	appsapiv1.SetObjectDefaults_ReplicaSet(newReplicaSet)
+
+	newReplicaSet.Status = oldReplicaSet.Status
+
+	if !apiequality.Semantic.DeepEqual(newReplicaSet.Spec, oldReplicaSet.Spec) {
+		newReplicaSet.Generation = oldReplicaSet.Generation + 1
+	}
+}
+
+// WarningsOnUpdate returns warnings for update operations.
+func (replicaSetStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForDelete clears fields before deletion.
+func (replicaSetStrategy) PrepareForDelete(_ context.Context, _ runtime.Object) {}
+
+// Validate validates new objects with the validation of the upstream API server.
+func (replicaSetStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	replicaSet, ok := obj.(*appsv1.ReplicaSet)
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAReplicaSet.Error())}
+	}
+
+	internal := &apps.ReplicaSet{}
+
+	err := toInternal(replicaSet, internal)
+	if err != nil {
+		return field.ErrorList{err}
+	}
+
+	opts := pod.GetValidationOptionsFromPodTemplate(&internal.Spec.Template, nil)
+
+	return appsvalidation.ValidateReplicaSet(internal, opts)
+}
+
+// ValidateUpdate validates updated objects. As in apps/v1 upstream, the selector of a
+// ReplicaSet is immutable.
+func (replicaSetStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := replicaSetsToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	opts := pod.GetValidationOptionsFromPodTemplate(&newInternal.Spec.Template, &oldInternal.Spec.Template)
+
+	allErrs := appsvalidation.ValidateReplicaSetUpdate(newInternal, oldInternal, opts)
+	allErrs = append(allErrs, validation.ValidateImmutableField(newInternal.Spec.Selector,
+		oldInternal.Spec.Selector, field.NewPath("spec", "selector"))...)
+
+	return allErrs
+}
+
+// Canonicalize normalizes objects.
+func (replicaSetStrategy) Canonicalize(_ runtime.Object) {}
+
+// AllowCreateOnUpdate determines if create is allowed on update.
+func (replicaSetStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// AllowUnconditionalUpdate determines if update can ignore resource version.
+func (replicaSetStrategy) AllowUnconditionalUpdate() bool {
+	return true
+}
+
+// replicaSetStatusStrategy implements behavior for the ReplicaSet status subresource.
+type replicaSetStatusStrategy struct {
+	replicaSetStrategy
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (replicaSetStatusStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"apps/v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("metadata"),
+			fieldpath.MakePathOrDie("spec"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForUpdate keeps everything but the status of the ReplicaSet.
+func (replicaSetStatusStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newReplicaSet, okNew := obj.(*appsv1.ReplicaSet)
+
+	oldReplicaSet, okOld := old.(*appsv1.ReplicaSet)
+	if !okNew || !okOld {
+		return
+	}
+
+	newReplicaSet.Spec = oldReplicaSet.Spec
+	metav1.ResetObjectMetaForStatus(&newReplicaSet.ObjectMeta, &oldReplicaSet.ObjectMeta)
+}
+
+// ValidateUpdate validates the status of updated objects.
+func (replicaSetStatusStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := replicaSetsToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	return appsvalidation.ValidateReplicaSetStatusUpdate(newInternal, oldInternal)
+}
+
+// replicaSetsToInternal converts the new and old ReplicaSet of an update to their
+// internal versions.
+func replicaSetsToInternal(obj, old runtime.Object) (*apps.ReplicaSet, *apps.ReplicaSet, field.ErrorList) {
+	newReplicaSet, okNew := obj.(*appsv1.ReplicaSet)
+
+	oldReplicaSet, okOld := old.(*appsv1.ReplicaSet)
+	if !okNew || !okOld {
+		return nil, nil, field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAReplicaSet.Error())}
+	}
+
+	newInternal, oldInternal := &apps.ReplicaSet{}, &apps.ReplicaSet{}
+
+	err := toInternal(newReplicaSet, newInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	err = toInternal(oldReplicaSet, oldInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	return newInternal, oldInternal, nil
+}
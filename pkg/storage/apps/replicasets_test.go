@@ -0,0 +1,86 @@
+package apps_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/apps"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/utils/ptr"
+)
+
+func newReplicaSet(name, app string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{"app": app},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			Template: newPodTemplate(app),
+		},
+	}
+}
+
+func TestReplicaSetsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, appsv1.SchemeGroupVersion)
+
+			storage, _, err := apps.NewReplicaSetREST(storageConfig, *scheme)
+			if err != nil {
+				tb.Fatalf("failed to create replicasets storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: appsv1.SchemeGroupVersion,
+		Resource:     "replicasets",
+		Namespace:    metav1.NamespaceDefault,
+		NewObject: func(name string) runtime.Object {
+			return newReplicaSet(name, "web")
+		},
+		Mutate: func(obj runtime.Object) {
+			obj.(*appsv1.ReplicaSet).Spec.Replicas = ptr.To[int32](3) //nolint:forcetypeassert // Always a ReplicaSet.
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "app label",
+				Objects: []runtime.Object{
+					newReplicaSet("first", "web"),
+					newReplicaSet("second", "api"),
+				},
+				LabelSelector: "app=web",
+				Expected:      []string{"first"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{
+				Name: "selector not matching the template",
+				Object: func() runtime.Object {
+					replicaSet := newReplicaSet("invalid", "web")
+					replicaSet.Spec.Template.Labels = map[string]string{"app": "api"}
+
+					return replicaSet
+				}(),
+			},
+			{
+				Name: "invalid image pull policy",
+				Object: func() runtime.Object {
+					replicaSet := newReplicaSet("invalid", "web")
+					replicaSet.Spec.Template.Spec.Containers[0].ImagePullPolicy = "Sometimes"
+
+					return replicaSet
+				}(),
+			},
+		},
+	})
+}
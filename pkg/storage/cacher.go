@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	cacherstorage "k8s.io/apiserver/pkg/storage/cacher"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+)
+
+// WatchCache describes the kine storage of a resource to serve from a watch cache.
+type WatchCache struct {
+	// Storage is the kine storage of the resource, destroyed by Destroy.
+	Storage apistorage.Interface
+	Destroy factory.DestroyFunc
+	Codec   runtime.Codec
+
+	// Resource is the plural name of a core resource, its objects are stored below it.
+	Resource       string
+	NewFunc        func() runtime.Object
+	NewListFunc    func() runtime.Object
+	GetAttrsFunc   apistorage.AttrFunc
+	ObjectNameFunc func(obj runtime.Object) (string, error)
+}
+
+// NewWatchCache wraps the storage in a watch cache, like the upstream API server does
+// for its resources. Watches, and lists with a resourceVersion, are served from memory
+// instead of each one polling kine. The returned destroy function stops the cache
+// before destroying the storage.
+func NewWatchCache(watchCache WatchCache) (apistorage.Interface, factory.DestroyFunc, error) {
+	resourcePrefix := "/" + watchCache.Resource
+
+	cacher, err := cacherstorage.NewCacherFromConfig(cacherstorage.Config{
+		Storage:        watchCache.Storage,
+		Versioner:      apistorage.APIObjectVersioner{},
+		GroupResource:  schema.GroupResource{Resource: watchCache.Resource},
+		ResourcePrefix: resourcePrefix,
+		KeyFunc: func(obj runtime.Object) (string, error) {
+			name, err := watchCache.ObjectNameFunc(obj)
+			if err != nil {
+				return "", err
+			}
+
+			return path.Join(resourcePrefix, name), nil
+		},
+		GetAttrsFunc: watchCache.GetAttrsFunc,
+		NewFunc:      watchCache.NewFunc,
+		NewListFunc:  watchCache.NewListFunc,
+		Codec:        watchCache.Codec,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s watch cache: %w", watchCache.Resource, err)
+	}
+
+	return cacher, func() {
+		cacher.Stop()
+		watchCache.Destroy()
+	}, nil
+}
@@ -39,9 +39,11 @@ func (*REST) ShortNames() []string {
 	return []string{"cm"}
 }
 
-// NewConfigMapsREST creates a REST interface for corev1 ConfigMap resource.
-func NewConfigMapsREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+// NewConfigMapsREST creates a REST interface for corev1 ConfigMap resource. With
+// watchCache, the ConfigMaps are served from a watch cache.
+func NewConfigMapsREST(storageConfig storagebackend.Config, scheme runtime.Scheme,
+	watchCache bool) (rest.Storage, error) {
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(configMapResource)),
 		func() runtime.Object { return &corev1.ConfigMap{} },
 		func() runtime.Object { return &corev1.ConfigMapList{} },
@@ -51,6 +53,26 @@ func NewConfigMapsREST(storageConfig storagebackend.Config, scheme runtime.Schem
 		return nil, fmt.Errorf("failed to create storage backend: %w", err)
 	}
 
+	if watchCache {
+		cached, destroyCached, err := storage.NewWatchCache(storage.WatchCache{
+			Storage:        store,
+			Destroy:        destroy,
+			Codec:          storageConfig.Codec,
+			Resource:       configMapResource,
+			NewFunc:        func() runtime.Object { return &corev1.ConfigMap{} },
+			NewListFunc:    func() runtime.Object { return &corev1.ConfigMapList{} },
+			GetAttrsFunc:   GetAttrs,
+			ObjectNameFunc: ObjectNameFunc,
+		})
+		if err != nil {
+			destroy()
+
+			return nil, err //nolint:wrapcheck // Already wrapped by the storage package.
+		}
+
+		store, destroy = cached, destroyCached
+	}
+
 	dryRunnableStorage := genericregistry.DryRunnableStorage{
 		Storage: store,
 		Codec:   storageConfig.Codec,
@@ -74,6 +96,8 @@ func NewConfigMapsREST(storageConfig storagebackend.Config, scheme runtime.Schem
 		UpdateStrategy: configMapStrategy,
 		DeleteStrategy: configMapStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
@@ -0,0 +1,97 @@
+package configmaps_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/configmaps"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newConfigMap(name string, labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Labels: labels},
+		Data:       map[string]string{"key": "value"},
+	}
+}
+
+func TestConfigMapsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, configMapsSuite(false))
+}
+
+// TestCachedConfigMapsStorage runs the suite with the ConfigMaps served from a watch cache.
+func TestCachedConfigMapsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, configMapsSuite(true))
+}
+
+func configMapsSuite(watchCache bool) storagetest.Suite {
+	return storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, corev1.SchemeGroupVersion)
+
+			storage, err := configmaps.NewConfigMapsREST(storageConfig, *scheme, watchCache)
+			if err != nil {
+				tb.Fatalf("failed to create configmaps storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: corev1.SchemeGroupVersion,
+		Resource:     "configmaps",
+		Namespace:    metav1.NamespaceDefault,
+		NewObject: func(name string) runtime.Object {
+			return newConfigMap(name, nil)
+		},
+		Mutate: func(obj runtime.Object) {
+			obj.(*corev1.ConfigMap).Data["key"] = "changed" //nolint:forcetypeassert // Always a ConfigMap.
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "name",
+				Objects: []runtime.Object{
+					newConfigMap("first", nil),
+					newConfigMap("second", nil),
+				},
+				FieldSelector: "metadata.name=first",
+				Expected:      []string{"first"},
+			},
+			{
+				Name: "label",
+				Objects: []runtime.Object{
+					newConfigMap("blue", map[string]string{"color": "blue"}),
+					newConfigMap("green", map[string]string{"color": "green"}),
+					newConfigMap("plain", nil),
+				},
+				LabelSelector: "color in (blue,green)",
+				Expected:      []string{"blue", "green"},
+			},
+			{
+				Name: "label and field",
+				Objects: []runtime.Object{
+					newConfigMap("blue", map[string]string{"color": "blue"}),
+					newConfigMap("green", map[string]string{"color": "green"}),
+				},
+				LabelSelector: "color",
+				FieldSelector: "metadata.name!=blue",
+				Expected:      []string{"green"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{Name: "name", Object: newConfigMap("Not_A_Name", nil)},
+			{Name: "duplicate key", Object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "duplicate", Namespace: metav1.NamespaceDefault},
+				Data:       map[string]string{"key": "value"},
+				BinaryData: map[string][]byte{"key": []byte("value")},
+			}},
+		},
+	}
+}
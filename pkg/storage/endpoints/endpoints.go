@@ -43,7 +43,7 @@ func (*REST) ShortNames() []string {
 
 // NewEndpointsREST creates a REST interface for corev1 Endpoint resource.
 func NewEndpointsREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(endpointResource)),
 		func() runtime.Object { return &corev1.Endpoints{} },
 		func() runtime.Object { return &corev1.EndpointsList{} },
@@ -76,6 +76,8 @@ func NewEndpointsREST(storageConfig storagebackend.Config, scheme runtime.Scheme
 		UpdateStrategy: endpointsStrategy,
 		DeleteStrategy: endpointsStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
@@ -23,12 +23,21 @@ var (
 	ErrObjectIsNotAnEvent = errors.New("object is not an Event")
 	// ErrObjectIsNotAServiceAccount indicates that the object is not a ServiceAccount.
 	ErrObjectIsNotAServiceAccount = errors.New("object is not a ServiceAccount")
+	// ErrObjectIsNotAResourceQuota indicates that the object is not a ResourceQuota.
+	ErrObjectIsNotAResourceQuota = errors.New("object is not a ResourceQuota")
 	// ErrObjectIsNotAnMutatingWebhookConfiguration indicates that the object is not a MutatingWebhookConfiguration.
 	ErrObjectIsNotAnMutatingWebhookConfiguration = errors.New("object is not a MutatingWebhookConfiguration")
 	// ErrObjectIsNotAValidatingWebhookConfiguration indicates that the object is not a ValidatingWebhookConfiguration.
 	ErrObjectIsNotAValidatingWebhookConfiguration = errors.New("object is not a ValidatingWebhookConfiguration")
+	// ErrObjectIsNotAValidatingAdmissionPolicy indicates that the object is not a ValidatingAdmissionPolicy.
+	ErrObjectIsNotAValidatingAdmissionPolicy = errors.New("object is not a ValidatingAdmissionPolicy")
+	// ErrObjectIsNotAValidatingAdmissionPolicyBinding indicates that the object is not a
+	// ValidatingAdmissionPolicyBinding.
+	ErrObjectIsNotAValidatingAdmissionPolicyBinding = errors.New("object is not a ValidatingAdmissionPolicyBinding")
 	// ErrObjectIsNotASelfSubjectAccessReview indicates that the object is not a SelfSubjectAccessReview.
 	ErrObjectIsNotASelfSubjectAccessReview = errors.New("object is not a SelfSubjectAccessReview")
+	// ErrObjectIsNotASelfSubjectReview indicates that the object is not a SelfSubjectReview.
+	ErrObjectIsNotASelfSubjectReview = errors.New("object is not a SelfSubjectReview")
 	// ErrObjectIsNotASubjectAccessReview indicates that the object is not a SubjectAccessReview.
 	ErrObjectIsNotASubjectAccessReview = errors.New("object is not a SubjectAccessReview")
 	// ErrObjectIsNotARole indicates that the object is not a Role.
@@ -41,6 +50,10 @@ var (
 	ErrObjectIsNotAClusterRoleBinding = errors.New("object is not a ClusterRoleBinding")
 	// ErrObjectIsNotAVolumeAttachment indicates that the object is not a VolumeAttachment.
 	ErrObjectIsNotAVolumeAttachment = errors.New("object is not a VolumeAttachment")
+	// ErrObjectIsNotADeployment indicates that the object is not a Deployment.
+	ErrObjectIsNotADeployment = errors.New("object is not a Deployment")
+	// ErrObjectIsNotAReplicaSet indicates that the object is not a ReplicaSet.
+	ErrObjectIsNotAReplicaSet = errors.New("object is not a ReplicaSet")
 	// ErrFieldIsNull indicates that the field is null.
 	ErrFieldIsNull = errors.New("field must not be null")
 )
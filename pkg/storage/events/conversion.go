@@ -0,0 +1,122 @@
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventsapiv1 "k8s.io/kubernetes/pkg/apis/events/v1"
+)
+
+// RegisterConversions adds the conversions between core v1 events, which are what is
+// stored, and events.k8s.io/v1 events to the scheme, together with the field
+// selectors of events.k8s.io/v1. Both API groups are served from the same storage.
+func RegisterConversions(scheme *runtime.Scheme) error {
+	conversions := []struct {
+		from, to any
+		fn       conversion.ConversionFunc
+	}{
+		{(*corev1.Event)(nil), (*eventsv1.Event)(nil), convertWith(convertCoreEventToEventsV1)},
+		{(*eventsv1.Event)(nil), (*corev1.Event)(nil), convertWith(convertEventsV1EventToCore)},
+		{(*corev1.EventList)(nil), (*eventsv1.EventList)(nil), convertWith(convertCoreEventListToEventsV1)},
+		{(*eventsv1.EventList)(nil), (*corev1.EventList)(nil), convertWith(convertEventsV1EventListToCore)},
+	}
+
+	for _, conv := range conversions {
+		err := scheme.AddConversionFunc(conv.from, conv.to, conv.fn)
+		if err != nil {
+			return fmt.Errorf("failed to add event conversion: %w", err)
+		}
+	}
+
+	err := eventsapiv1.AddFieldLabelConversionsForEvent(scheme)
+	if err != nil {
+		return fmt.Errorf("failed to add events.k8s.io/v1 field selectors: %w", err)
+	}
+
+	return nil
+}
+
+// convertWith adapts a typed conversion to the scheme.
+func convertWith[In, Out any](convert func(*In, *Out)) conversion.ConversionFunc {
+	return func(in, out any, _ conversion.Scope) error {
+		convert(in.(*In), out.(*Out)) //nolint:forcetypeassert // Only registered for these types.
+
+		return nil
+	}
+}
+
+// convertCoreEventToEventsV1 maps the core v1 fields to their events.k8s.io/v1 names,
+// like the upstream API server does.
+func convertCoreEventToEventsV1(in *corev1.Event, out *eventsv1.Event) {
+	*out = eventsv1.Event{
+		ObjectMeta:               in.ObjectMeta,
+		EventTime:                in.EventTime,
+		ReportingController:      in.ReportingController,
+		ReportingInstance:        in.ReportingInstance,
+		Action:                   in.Action,
+		Reason:                   in.Reason,
+		Regarding:                in.InvolvedObject,
+		Related:                  in.Related,
+		Note:                     in.Message,
+		Type:                     in.Type,
+		DeprecatedSource:         in.Source,
+		DeprecatedFirstTimestamp: in.FirstTimestamp,
+		DeprecatedLastTimestamp:  in.LastTimestamp,
+		DeprecatedCount:          in.Count,
+	}
+
+	if in.Series != nil {
+		out.Series = &eventsv1.EventSeries{
+			Count:            in.Series.Count,
+			LastObservedTime: in.Series.LastObservedTime,
+		}
+	}
+}
+
+// convertEventsV1EventToCore is the inverse of convertCoreEventToEventsV1.
+func convertEventsV1EventToCore(in *eventsv1.Event, out *corev1.Event) {
+	*out = corev1.Event{
+		ObjectMeta:          in.ObjectMeta,
+		InvolvedObject:      in.Regarding,
+		Reason:              in.Reason,
+		Message:             in.Note,
+		Source:              in.DeprecatedSource,
+		FirstTimestamp:      in.DeprecatedFirstTimestamp,
+		LastTimestamp:       in.DeprecatedLastTimestamp,
+		Count:               in.DeprecatedCount,
+		Type:                in.Type,
+		EventTime:           in.EventTime,
+		Action:              in.Action,
+		Related:             in.Related,
+		ReportingController: in.ReportingController,
+		ReportingInstance:   in.ReportingInstance,
+	}
+
+	if in.Series != nil {
+		out.Series = &corev1.EventSeries{
+			Count:            in.Series.Count,
+			LastObservedTime: in.Series.LastObservedTime,
+		}
+	}
+}
+
+func convertCoreEventListToEventsV1(in *corev1.EventList, out *eventsv1.EventList) {
+	out.ListMeta = in.ListMeta
+	out.Items = make([]eventsv1.Event, len(in.Items))
+
+	for i := range in.Items {
+		convertCoreEventToEventsV1(&in.Items[i], &out.Items[i])
+	}
+}
+
+func convertEventsV1EventListToCore(in *eventsv1.EventList, out *corev1.EventList) {
+	out.ListMeta = in.ListMeta
+	out.Items = make([]corev1.Event, len(in.Items))
+
+	for i := range in.Items {
+		convertEventsV1EventToCore(&in.Items[i], &out.Items[i])
+	}
+}
@@ -0,0 +1,113 @@
+package events_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/server"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints"
+)
+
+// eventStorage stands in for the events REST storage when only its type matters.
+type eventStorage struct{}
+
+func (eventStorage) New() runtime.Object { return &corev1.Event{} }
+
+func (eventStorage) Destroy() {}
+
+func newSeriesEvent() *corev1.Event {
+	event := newEvent("series", "pod", "BackOff")
+	event.EventTime = metav1.NewMicroTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	event.Series = &corev1.EventSeries{Count: 3, LastObservedTime: event.EventTime}
+	event.Related = &corev1.ObjectReference{Kind: "Node", Name: "node-a"}
+	event.ReportingController = "kubelet"
+	event.ReportingInstance = "kubelet-node-a"
+	event.Action = "Restarting"
+	event.Count = 4
+
+	return event
+}
+
+func TestEventsV1Conversion(t *testing.T) {
+	t.Parallel()
+
+	scheme, err := server.NewScheme()
+	if err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	event := newSeriesEvent()
+
+	converted, err := scheme.ConvertToVersion(event.DeepCopy(), eventsv1.SchemeGroupVersion)
+	if err != nil {
+		t.Fatalf("failed to convert to events.k8s.io/v1: %v", err)
+	}
+
+	eventV1, ok := converted.(*eventsv1.Event)
+	if !ok {
+		t.Fatalf("expected an events.k8s.io/v1 Event, got %T", converted)
+	}
+
+	if eventV1.Regarding != event.InvolvedObject || eventV1.Note != event.Message ||
+		eventV1.Related == nil || eventV1.Related.Name != "node-a" ||
+		eventV1.DeprecatedSource != event.Source || eventV1.DeprecatedCount != event.Count {
+		t.Fatalf("unexpected events.k8s.io/v1 Event %+v", eventV1)
+	}
+
+	if eventV1.Series == nil || eventV1.Series.Count != 3 || !eventV1.Series.LastObservedTime.Equal(&event.EventTime) {
+		t.Fatalf("expected the series to be converted, got %+v", eventV1.Series)
+	}
+
+	roundTripped := &corev1.Event{}
+
+	err = scheme.Convert(eventV1, roundTripped, nil)
+	if err != nil {
+		t.Fatalf("failed to convert to core v1: %v", err)
+	}
+
+	roundTripped.TypeMeta = event.TypeMeta
+	if !reflect.DeepEqual(roundTripped, event) {
+		t.Fatalf("expected the event to survive a round trip, got %+v", roundTripped)
+	}
+}
+
+func TestEventsV1FieldSelectors(t *testing.T) {
+	t.Parallel()
+
+	scheme, err := server.NewScheme()
+	if err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	label, value, err := scheme.ConvertFieldLabel(eventsv1.SchemeGroupVersion.WithKind("Event"), "regarding.name", "pod")
+	if err != nil {
+		t.Fatalf("failed to convert field label: %v", err)
+	}
+
+	if label != "involvedObject.name" || value != "pod" {
+		t.Fatalf("expected regarding.name to select involvedObject.name, got %s=%s", label, value)
+	}
+}
+
+func TestEventsV1ServedFromCoreStorage(t *testing.T) {
+	t.Parallel()
+
+	scheme, err := server.NewScheme()
+	if err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	kind, err := endpoints.GetResourceKind(eventsv1.SchemeGroupVersion, eventStorage{}, scheme)
+	if err != nil {
+		t.Fatalf("failed to resolve the kind of events.k8s.io/v1 events: %v", err)
+	}
+
+	if kind != eventsv1.SchemeGroupVersion.WithKind("Event") {
+		t.Fatalf("expected events.k8s.io/v1 Event, got %s", kind)
+	}
+}
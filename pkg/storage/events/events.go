@@ -10,6 +10,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	eventsv1beta1 "k8s.io/api/events/v1beta1"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	storage "github.com/kommodity-io/kommodity/pkg/storage"
@@ -25,9 +26,12 @@ import (
 	"k8s.io/apiserver/pkg/registry/generic"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/registry/rest"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	cacherstorage "k8s.io/apiserver/pkg/storage/cacher"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
 )
 
 // Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/core/event/strategy.go
@@ -53,9 +57,11 @@ func (*REST) ShortNames() []string {
 	return []string{"ev"}
 }
 
-// NewEventsREST creates a REST interface for corev1 Event resource.
-func NewEventsREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+// NewEventsREST creates a REST interface for corev1 Event resource. Events are written
+// with a lease of the given TTL, so kine deletes them once it expires, like etcd does
+// for the upstream API server.
+func NewEventsREST(storageConfig storagebackend.Config, scheme runtime.Scheme, ttl time.Duration) (rest.Storage, error) {
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(eventResource)),
 		func() runtime.Object { return &corev1.Event{} },
 		func() runtime.Object { return &corev1.EventList{} },
@@ -65,8 +71,15 @@ func NewEventsREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (
 		return nil, fmt.Errorf("failed to create storage backend: %w", err)
 	}
 
+	cacher, err := newEventCacher(store, storageConfig.Codec, indexedEventFields)
+	if err != nil {
+		destroy()
+
+		return nil, err
+	}
+
 	dryRunnableStorage := genericregistry.DryRunnableStorage{
-		Storage: store,
+		Storage: cacher,
 		Codec:   storageConfig.Codec,
 	}
 
@@ -78,21 +91,141 @@ func NewEventsREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (
 	restStore := &genericregistry.Store{
 		NewFunc:       func() runtime.Object { return &corev1.Event{} },
 		NewListFunc:   func() runtime.Object { return &corev1.EventList{} },
-		PredicateFunc: storage.PredicateFunc(GetAttrs),
+		PredicateFunc: MatchEvent,
 		KeyRootFunc:   func(_ context.Context) string { return "/" + eventResource },
 		KeyFunc: func(_ context.Context, name string) (string, error) {
 			return path.Join("/"+eventResource, name), nil
 		},
 		ObjectNameFunc: ObjectNameFunc,
+		TTLFunc: func(runtime.Object, uint64, bool) (uint64, error) {
+			return uint64(ttl.Seconds()), nil
+		},
 		CreateStrategy: eventStrategy,
 		UpdateStrategy: eventStrategy,
 		DeleteStrategy: eventStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc: func() {
+			cacher.Stop()
+			destroy()
+		},
 	}
 
 	return &REST{restStore}, nil
 }
 
+// List serves lists that select events by an indexed involvedObject field from the
+// watch cache, where the field index makes them independent of the total number of
+// events. Kine cannot confirm the freshness of the cache the way etcd does with
+// progress notifications, so a list without a resourceVersion would otherwise always
+// be delegated to kine and filtered row by row. Events are informational and a list
+// that trails the latest write by a watch event is acceptable for them. Paginated
+// lists are left alone, as the watch cache ignores the limit of lists it serves.
+func (r *REST) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	if options != nil && options.ResourceVersion == "" && options.Continue == "" && options.Limit == 0 &&
+		selectsIndexedField(options.FieldSelector) {
+		options = options.DeepCopy()
+		options.ResourceVersion = "0"
+	}
+
+	return r.Store.List(ctx, options) //nolint:wrapcheck // API status errors must be returned as is.
+}
+
+// indexedEventFields are the event fields the watch cache maintains an index for.
+// They back the involvedObject queries issued by kubectl describe and controllers
+// looking up the events of a single object.
+var indexedEventFields = []string{"involvedObject.uid", "involvedObject.name"}
+
+// MatchEvent returns a selection predicate for events that advertises the indexed
+// fields, so that the watch cache can answer exact matches from its indexes.
+func MatchEvent(label labels.Selector, field fields.Selector) apistorage.SelectionPredicate {
+	return apistorage.SelectionPredicate{
+		Label:       label,
+		Field:       field,
+		GetAttrs:    GetAttrs,
+		IndexFields: indexedEventFields,
+	}
+}
+
+// selectsIndexedField reports whether the selector requires an exact value for one
+// of the indexed fields.
+func selectsIndexedField(selector fields.Selector) bool {
+	if selector == nil {
+		return false
+	}
+
+	for _, field := range indexedEventFields {
+		if _, ok := selector.RequiresExactMatch(field); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newEventCacher wraps the kine storage in a watch cache that indexes events by the
+// given fields. Only involvedObject.name is used as a watch trigger, as the cacher
+// supports a single one.
+func newEventCacher(store apistorage.Interface,
+	codec runtime.Codec, indexed []string) (*cacherstorage.Cacher, error) {
+	indexers := cache.Indexers{}
+	for _, field := range indexed {
+		indexers[apistorage.FieldIndex(field)] = eventFieldIndexFunc(field)
+	}
+
+	cacher, err := cacherstorage.NewCacherFromConfig(cacherstorage.Config{
+		Storage:        store,
+		Versioner:      apistorage.APIObjectVersioner{},
+		GroupResource:  corev1.Resource(eventResource),
+		ResourcePrefix: "/" + eventResource,
+		KeyFunc: func(obj runtime.Object) (string, error) {
+			name, err := ObjectNameFunc(obj)
+			if err != nil {
+				return "", err
+			}
+
+			return path.Join("/"+eventResource, name), nil
+		},
+		GetAttrsFunc: GetAttrs,
+		IndexerFuncs: apistorage.IndexerFuncs{
+			"involvedObject.name": func(obj runtime.Object) string {
+				event, ok := obj.(*corev1.Event)
+				if !ok {
+					return ""
+				}
+
+				return event.InvolvedObject.Name
+			},
+		},
+		Indexers:    &indexers,
+		NewFunc:     func() runtime.Object { return &corev1.Event{} },
+		NewListFunc: func() runtime.Object { return &corev1.EventList{} },
+		Codec:       codec,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events watch cache: %w", err)
+	}
+
+	return cacher, nil
+}
+
+// eventFieldIndexFunc indexes events by the value of a selectable field.
+func eventFieldIndexFunc(field string) cache.IndexFunc {
+	return func(obj any) ([]string, error) {
+		event, ok := obj.(*corev1.Event)
+		if !ok {
+			return nil, storage.ErrObjectIsNotAnEvent
+		}
+
+		_, fieldSet, err := GetAttrs(event)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{fieldSet[field]}, nil
+	}
+}
+
 // GetAttrs returns labels and fields for a Event object.
 func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
 	event, ok := obj.(*corev1.Event)
@@ -0,0 +1,219 @@
+//nolint:testpackage // Exercises the unexported watch cache wiring directly.
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	cacherstorage "k8s.io/apiserver/pkg/storage/cacher"
+)
+
+const (
+	involvedObjects = 100
+	listedEvents    = 1000
+	benchmarkEvents = 100000
+)
+
+var errNotImplemented = errors.New("not implemented")
+
+// listOnlyStorage is a kine stand-in that serves a fixed set of events to the watch
+// cache and never changes.
+type listOnlyStorage struct {
+	apistorage.Interface
+
+	events []corev1.Event
+}
+
+func newListOnlyStorage(count int) *listOnlyStorage {
+	events := make([]corev1.Event, count)
+
+	for i := range events {
+		object := strconv.Itoa(i % involvedObjects)
+
+		events[i] = corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "event-" + strconv.Itoa(i),
+				Namespace:       "default",
+				ResourceVersion: "1",
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: "default",
+				Name:      "pod-" + object,
+				UID:       types.UID("uid-" + object),
+			},
+			Reason: "Scheduled",
+		}
+	}
+
+	return &listOnlyStorage{events: events}
+}
+
+func (s *listOnlyStorage) Versioner() apistorage.Versioner {
+	return apistorage.APIObjectVersioner{}
+}
+
+func (s *listOnlyStorage) GetList(_ context.Context, _ string, _ apistorage.ListOptions, listObj runtime.Object) error {
+	list, ok := listObj.(*corev1.EventList)
+	if !ok {
+		return errNotImplemented
+	}
+
+	list.Items = append(list.Items[:0], s.events...)
+	list.ResourceVersion = "1"
+
+	return nil
+}
+
+func (s *listOnlyStorage) Watch(_ context.Context, _ string, _ apistorage.ListOptions) (watch.Interface, error) {
+	return watch.NewFake(), nil
+}
+
+func (s *listOnlyStorage) ReadinessCheck() error {
+	return nil
+}
+
+func (s *listOnlyStorage) RequestWatchProgress(_ context.Context) error {
+	return errNotImplemented
+}
+
+func newReadyCacher(tb testing.TB, events int, indexed []string) *cacherstorage.Cacher {
+	tb.Helper()
+
+	scheme := runtime.NewScheme()
+
+	err := corev1.AddToScheme(scheme)
+	if err != nil {
+		tb.Fatalf("failed to build scheme: %v", err)
+	}
+
+	codec := serializer.NewCodecFactory(scheme).LegacyCodec(corev1.SchemeGroupVersion)
+
+	cacher, err := newEventCacher(newListOnlyStorage(events), codec, indexed)
+	if err != nil {
+		tb.Fatalf("failed to create cacher: %v", err)
+	}
+
+	tb.Cleanup(cacher.Stop)
+
+	deadline := time.Now().Add(30 * time.Second)
+	for cacher.ReadinessCheck() != nil {
+		if time.Now().After(deadline) {
+			tb.Fatalf("watch cache did not become ready")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return cacher
+}
+
+func listInvolvedObject(tb testing.TB, cacher *cacherstorage.Cacher, selector fields.Selector) *corev1.EventList {
+	tb.Helper()
+
+	list := &corev1.EventList{}
+
+	err := cacher.GetList(context.Background(), "/"+eventResource, apistorage.ListOptions{
+		ResourceVersion: "0",
+		Recursive:       true,
+		Predicate:       MatchEvent(labels.Everything(), selector),
+	}, list)
+	if err != nil {
+		tb.Fatalf("failed to list events: %v", err)
+	}
+
+	return list
+}
+
+func TestIndexedInvolvedObjectList(t *testing.T) {
+	t.Parallel()
+
+	cacher := newReadyCacher(t, listedEvents, indexedEventFields)
+
+	selectors := map[string]fields.Selector{
+		"name": fields.OneTermEqualSelector("involvedObject.name", "pod-7"),
+		"uid":  fields.OneTermEqualSelector("involvedObject.uid", "uid-7"),
+		"name and kind": fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.name", "pod-7"),
+			fields.OneTermEqualSelector("involvedObject.kind", "Pod")),
+	}
+
+	for name, selector := range selectors {
+		list := listInvolvedObject(t, cacher, selector)
+
+		if len(list.Items) != listedEvents/involvedObjects {
+			t.Fatalf("%s: expected %d events, got %d", name, listedEvents/involvedObjects, len(list.Items))
+		}
+
+		for _, event := range list.Items {
+			if event.InvolvedObject.Name != "pod-7" {
+				t.Fatalf("%s: listed event %s of %s", name, event.Name, event.InvolvedObject.Name)
+			}
+		}
+	}
+
+	list := listInvolvedObject(t, cacher, fields.OneTermEqualSelector("involvedObject.kind", "Node"))
+	if len(list.Items) != 0 {
+		t.Fatalf("expected no Node events, got %d", len(list.Items))
+	}
+}
+
+func TestSelectsIndexedField(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		selector fields.Selector
+		expected bool
+	}{
+		"nil":        {selector: nil, expected: false},
+		"everything": {selector: fields.Everything(), expected: false},
+		"name":       {selector: fields.OneTermEqualSelector("involvedObject.name", "pod"), expected: true},
+		"uid":        {selector: fields.OneTermEqualSelector("involvedObject.uid", "uid"), expected: true},
+		"not name":   {selector: fields.OneTermNotEqualSelector("involvedObject.name", "pod"), expected: false},
+		"reason":     {selector: fields.OneTermEqualSelector("reason", "Scheduled"), expected: false},
+	}
+
+	for name, test := range tests {
+		if got := selectsIndexedField(test.selector); got != test.expected {
+			t.Fatalf("%s: expected %t, got %t", name, test.expected, got)
+		}
+	}
+}
+
+// BenchmarkInvolvedObjectList compares listing the events of one object out of 100k
+// with and without the involvedObject field indexes.
+func BenchmarkInvolvedObjectList(b *testing.B) {
+	selector := fields.OneTermEqualSelector("involvedObject.name", "pod-42")
+
+	benchmarks := map[string][]string{
+		"indexed":   indexedEventFields,
+		"unindexed": nil,
+	}
+
+	for name, indexed := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			cacher := newReadyCacher(b, benchmarkEvents, indexed)
+
+			b.ResetTimer()
+
+			for range b.N {
+				list := listInvolvedObject(b, cacher, selector)
+				if len(list.Items) != benchmarkEvents/involvedObjects {
+					b.Fatalf("expected %d events, got %d", benchmarkEvents/involvedObjects, len(list.Items))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/events"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newEvent(name, involvedObject, reason string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: metav1.NamespaceDefault,
+			Name:      involvedObject,
+			UID:       types.UID(involvedObject + "-uid"),
+		},
+		Reason:  reason,
+		Message: "Event for " + involvedObject,
+		Type:    corev1.EventTypeNormal,
+		Source:  corev1.EventSource{Component: "kommodity"},
+	}
+}
+
+func TestEventsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, corev1.SchemeGroupVersion)
+
+			storage, err := events.NewEventsREST(storageConfig, *scheme, time.Hour)
+			if err != nil {
+				tb.Fatalf("failed to create events storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: corev1.SchemeGroupVersion,
+		Resource:     "events",
+		Namespace:    metav1.NamespaceDefault,
+		NewObject: func(name string) runtime.Object {
+			return newEvent(name, "pod", "Scheduled")
+		},
+		Mutate: func(obj runtime.Object) {
+			obj.(*corev1.Event).Count = 2 //nolint:forcetypeassert // Always an Event.
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "involvedObject.name",
+				Objects: []runtime.Object{
+					newEvent("first", "pod-a", "Scheduled"),
+					newEvent("second", "pod-b", "Scheduled"),
+					newEvent("third", "pod-a", "Pulled"),
+				},
+				FieldSelector: "involvedObject.name=pod-a",
+				Expected:      []string{"first", "third"},
+			},
+			{
+				Name: "involvedObject.uid",
+				Objects: []runtime.Object{
+					newEvent("first", "pod-a", "Scheduled"),
+					newEvent("second", "pod-b", "Scheduled"),
+				},
+				FieldSelector: "involvedObject.uid=pod-b-uid",
+				Expected:      []string{"second"},
+			},
+			{
+				Name: "involvedObject and reason",
+				Objects: []runtime.Object{
+					newEvent("first", "pod-a", "Scheduled"),
+					newEvent("second", "pod-b", "Scheduled"),
+					newEvent("third", "pod-a", "Pulled"),
+				},
+				FieldSelector: "involvedObject.kind=Pod,involvedObject.name=pod-a,reason=Pulled",
+				Expected:      []string{"third"},
+			},
+			{
+				Name: "type",
+				Objects: []runtime.Object{
+					newEvent("first", "pod-a", "Scheduled"),
+					newEvent("second", "pod-b", "Scheduled"),
+				},
+				FieldSelector: "type=Warning",
+				Expected:      []string{},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{Name: "namespace mismatch", Object: &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "mismatch", Namespace: metav1.NamespaceDefault},
+				InvolvedObject: corev1.ObjectReference{
+					Kind:      "Pod",
+					Namespace: "other",
+					Name:      "pod",
+				},
+				Type:   corev1.EventTypeNormal,
+				Source: corev1.EventSource{Component: "kommodity"},
+			}},
+			{Name: "missing reportingComponent", Object: &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "unreported", Namespace: metav1.NamespaceDefault},
+				InvolvedObject: corev1.ObjectReference{
+					Kind:      "Pod",
+					Namespace: metav1.NamespaceDefault,
+					Name:      "pod",
+				},
+				EventTime: metav1.NowMicro(),
+				Type:      corev1.EventTypeNormal,
+			}},
+		},
+	})
+}
+
+func TestEventsExpireAfterTTL(t *testing.T) {
+	t.Parallel()
+
+	storageConfig, scheme := storagetest.NewStorageConfig(t, corev1.SchemeGroupVersion)
+
+	created, err := events.NewEventsREST(storageConfig, *scheme, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create events storage: %v", err)
+	}
+
+	t.Cleanup(created.Destroy)
+
+	storage, ok := created.(rest.StandardStorage)
+	if !ok {
+		t.Fatalf("events storage does not implement rest.StandardStorage")
+	}
+
+	ctx := genericapirequest.WithRequestInfo(
+		genericapirequest.WithNamespace(t.Context(), metav1.NamespaceDefault),
+		&genericapirequest.RequestInfo{
+			IsResourceRequest: true,
+			APIVersion:        corev1.SchemeGroupVersion.Version,
+			Namespace:         metav1.NamespaceDefault,
+			Resource:          "events",
+		})
+
+	_, err = storage.Create(ctx, newEvent("expiring", "pod", "Scheduled"),
+		rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+
+	for {
+		_, err = storage.Get(ctx, "expiring", &metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("failed to get event: %v", err)
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the event to expire after its TTL")
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/kommodity-io/kommodity/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// LoggerFrom returns the logger of the request, at the level of the storage module.
+func LoggerFrom(ctx context.Context) *zap.Logger {
+	return logging.ForModule(logging.FromContext(ctx), logging.ModuleStorage)
+}
@@ -6,21 +6,26 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 
 	storage "github.com/kommodity-io/kommodity/pkg/storage"
 
 	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/registry/rest"
+	apistorage "k8s.io/apiserver/pkg/storage"
+	storageerr "k8s.io/apiserver/pkg/storage/errors"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/apiserver/pkg/util/dryrun"
 )
 
 const namespaceResource = "namespaces"
@@ -30,6 +35,16 @@ type REST struct {
 	*genericregistry.Store
 }
 
+// StatusREST implements the REST endpoint for the namespaces/status subresource.
+type StatusREST struct {
+	store *genericregistry.Store
+}
+
+// FinalizeREST implements the REST endpoint for the namespaces/finalize subresource.
+type FinalizeREST struct {
+	store *genericregistry.Store
+}
+
 var _ rest.ShortNamesProvider = &REST{}
 
 // ShortNames implement ShortNamesProvider to return short names for the resource.
@@ -37,16 +52,18 @@ func (*REST) ShortNames() []string {
 	return []string{"ns"}
 }
 
-// NewNamespacesREST creates a REST interface for corev1 Namespace resource.
-func NewNamespacesREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+// NewNamespacesREST creates a REST interface for corev1 Namespace resource, along with
+// the status and finalize subresources used by the namespace lifecycle controller.
+func NewNamespacesREST(storageConfig storagebackend.Config,
+	scheme runtime.Scheme) (*REST, *StatusREST, *FinalizeREST, error) {
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(namespaceResource)),
 		func() runtime.Object { return &corev1.Namespace{} },
 		func() runtime.Object { return &corev1.NamespaceList{} },
 		"/"+namespaceResource,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create storage backend: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create storage backend: %w", err)
 	}
 
 	dryRunnableStorage := genericregistry.DryRunnableStorage{
@@ -67,14 +84,159 @@ func NewNamespacesREST(storageConfig storagebackend.Config, scheme runtime.Schem
 		KeyFunc: func(_ context.Context, name string) (string, error) {
 			return path.Join("/"+namespaceResource, name), nil
 		},
-		ObjectNameFunc: ObjectNameFunc,
-		CreateStrategy: namespaceStrategy,
-		UpdateStrategy: namespaceStrategy,
-		DeleteStrategy: namespaceStrategy,
-		Storage:        dryRunnableStorage,
+		ObjectNameFunc:           ObjectNameFunc,
+		CreateStrategy:           namespaceStrategy,
+		UpdateStrategy:           namespaceStrategy,
+		DeleteStrategy:           namespaceStrategy,
+		ShouldDeleteDuringUpdate: shouldDeleteNamespaceDuringUpdate,
+		Storage:                  dryRunnableStorage,
+		TableConvertor:           storage.NewTableConvertor(),
+		DestroyFunc:              destroy,
+	}
+
+	statusStore := *restStore
+	statusStore.UpdateStrategy = namespaceStatusStrategy{namespaceStrategy}
+
+	finalizeStore := *restStore
+	finalizeStore.UpdateStrategy = namespaceFinalizeStrategy{namespaceStrategy}
+
+	return &REST{restStore}, &StatusREST{store: &statusStore}, &FinalizeREST{store: &finalizeStore}, nil
+}
+
+// Delete enforces the namespace lifecycle. The first request only marks the namespace
+// as Terminating; the namespace lifecycle controller then deletes its content and
+// removes the kubernetes finalizer, which removes the namespace itself.
+//
+// Namespaces created before the lifecycle controller existed carry no finalizer. They
+// get one here so their content is cleaned up as well.
+// Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/core/namespace/storage/storage.go
+func (r *REST) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc,
+	options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	obj, err := r.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck // API status errors must be returned as is.
+	}
+
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return nil, false, storage.ErrObjectIsNotANamespace
+	}
+
+	if options == nil {
+		options = metav1.NewDeleteOptions(0)
+	}
+
+	if !namespace.DeletionTimestamp.IsZero() {
+		if len(namespace.Spec.Finalizers) != 0 {
+			return namespace, false, nil
+		}
+
+		return r.Store.Delete(ctx, name, deleteValidation, options) //nolint:wrapcheck // API status errors must be returned as is.
+	}
+
+	key, err := r.KeyFunc(ctx, name)
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck // API status errors must be returned as is.
+	}
+
+	preconditions := apistorage.Preconditions{UID: &namespace.UID}
+	if options.Preconditions != nil {
+		preconditions.ResourceVersion = options.Preconditions.ResourceVersion
+
+		if options.Preconditions.UID != nil {
+			preconditions.UID = options.Preconditions.UID
+		}
+	}
+
+	out := r.NewFunc()
+
+	err = r.Storage.GuaranteedUpdate(ctx, key, out, false, &preconditions,
+		apistorage.SimpleUpdate(func(existing runtime.Object) (runtime.Object, error) {
+			existingNamespace, ok := existing.(*corev1.Namespace)
+			if !ok {
+				return nil, storage.ErrObjectIsNotANamespace
+			}
+
+			err := deleteValidation(ctx, existingNamespace)
+			if err != nil {
+				return nil, err
+			}
+
+			if existingNamespace.DeletionTimestamp.IsZero() {
+				now := metav1.Now()
+				existingNamespace.DeletionTimestamp = &now
+			}
+
+			existingNamespace.Status.Phase = corev1.NamespaceTerminating
+
+			if !slices.Contains(existingNamespace.Spec.Finalizers, corev1.FinalizerKubernetes) {
+				existingNamespace.Spec.Finalizers = append(existingNamespace.Spec.Finalizers, corev1.FinalizerKubernetes)
+			}
+
+			return existingNamespace, nil
+		}),
+		dryrun.IsDryRun(options.DryRun),
+		nil,
+	)
+	if err != nil {
+		err = storageerr.InterpretGetError(err, corev1.Resource(namespaceResource), name)
+		err = storageerr.InterpretUpdateError(err, corev1.Resource(namespaceResource), name)
+
+		return nil, false, err //nolint:wrapcheck // API status errors must be returned as is.
+	}
+
+	return out, false, nil
+}
+
+// New returns an empty Namespace.
+func (r *StatusREST) New() runtime.Object {
+	return r.store.New()
+}
+
+// Destroy is a no-op, the store is shared with the main resource.
+func (r *StatusREST) Destroy() {}
+
+// Get retrieves the namespace, it is required to support Patch.
+func (r *StatusREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return r.store.Get(ctx, name, options) //nolint:wrapcheck // API status errors must be returned as is.
+}
+
+// Update alters the status of a namespace.
+func (r *StatusREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo,
+	createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc,
+	_ bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	// Subresources never create objects.
+	//nolint:wrapcheck // API status errors must be returned as is.
+	return r.store.Update(ctx, name, objInfo, createValidation, updateValidation, false, options)
+}
+
+// New returns an empty Namespace.
+func (r *FinalizeREST) New() runtime.Object {
+	return r.store.New()
+}
+
+// Destroy is a no-op, the store is shared with the main resource.
+func (r *FinalizeREST) Destroy() {}
+
+// Update alters the spec.finalizers of a namespace. Removing the last finalizer of a
+// terminating namespace deletes it.
+func (r *FinalizeREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo,
+	createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc,
+	_ bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	// Subresources never create objects.
+	//nolint:wrapcheck // API status errors must be returned as is.
+	return r.store.Update(ctx, name, objInfo, createValidation, updateValidation, false, options)
+}
+
+// shouldDeleteNamespaceDuringUpdate only lets an update remove a terminating namespace
+// once all of its spec finalizers are gone.
+func shouldDeleteNamespaceDuringUpdate(ctx context.Context, key string, obj, existing runtime.Object) bool {
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return false
 	}
 
-	return &REST{restStore}, nil
+	return len(namespace.Spec.Finalizers) == 0 && genericregistry.ShouldDeleteDuringUpdate(ctx, key, obj, existing)
 }
 
 // GetAttrs returns labels and fields for a Namespace object.
@@ -117,8 +279,22 @@ func (namespaceStrategy) NamespaceScoped() bool {
 	return false
 }
 
-// PrepareForCreate sets defaults for new objects.
-func (namespaceStrategy) PrepareForCreate(_ context.Context, _ runtime.Object) {}
+// PrepareForCreate marks new namespaces as active and adds the kubernetes finalizer,
+// which the namespace lifecycle controller removes once the content is deleted.
+func (namespaceStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	namespace, success := obj.(*corev1.Namespace)
+	if !success {
+		log.Printf("expected *corev1.Namespace, got %T", obj)
+
+		return
+	}
+
+	namespace.Status = corev1.NamespaceStatus{Phase: corev1.NamespaceActive}
+
+	if !slices.Contains(namespace.Spec.Finalizers, corev1.FinalizerKubernetes) {
+		namespace.Spec.Finalizers = append(namespace.Spec.Finalizers, corev1.FinalizerKubernetes)
+	}
+}
 
 // WarningsOnCreate returns warnings for create operations.
 func (namespaceStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
@@ -204,3 +380,52 @@ func (namespaceStrategy) AllowCreateOnUpdate() bool {
 func (namespaceStrategy) AllowUnconditionalUpdate() bool {
 	return false
 }
+
+// namespaceStatusStrategy only lets updates through the status subresource change the status.
+type namespaceStatusStrategy struct {
+	namespaceStrategy
+}
+
+// PrepareForUpdate keeps everything but the status of the stored namespace.
+func (namespaceStatusStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newNamespace, success := obj.(*corev1.Namespace)
+	if !success {
+		log.Printf("expected *corev1.Namespace, got %T", obj)
+
+		return
+	}
+
+	oldNamespace, success := old.(*corev1.Namespace)
+	if !success {
+		log.Printf("expected *corev1.Namespace, got %T", old)
+
+		return
+	}
+
+	newNamespace.Spec = oldNamespace.Spec
+}
+
+// namespaceFinalizeStrategy only lets updates through the finalize subresource change
+// the spec finalizers.
+type namespaceFinalizeStrategy struct {
+	namespaceStrategy
+}
+
+// PrepareForUpdate keeps the status of the stored namespace.
+func (namespaceFinalizeStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newNamespace, success := obj.(*corev1.Namespace)
+	if !success {
+		log.Printf("expected *corev1.Namespace, got %T", obj)
+
+		return
+	}
+
+	oldNamespace, success := old.(*corev1.Namespace)
+	if !success {
+		log.Printf("expected *corev1.Namespace, got %T", old)
+
+		return
+	}
+
+	newNamespace.Status = oldNamespace.Status
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"path"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -33,7 +32,7 @@ func NewClusterRoleBindingREST(
 	storageConfig storagebackend.Config,
 	scheme runtime.Scheme,
 ) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(clusterRoleBindingResource)),
 		func() runtime.Object { return &rbacv1.ClusterRoleBinding{} },
 		func() runtime.Object { return &rbacv1.ClusterRoleBindingList{} },
@@ -66,6 +65,8 @@ func NewClusterRoleBindingREST(
 		UpdateStrategy: clusterRoleBindingStrategy,
 		DeleteStrategy: clusterRoleBindingStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return restStore, nil
@@ -126,7 +127,7 @@ func (clusterRoleBindingStrategy) WarningsOnCreate(_ context.Context, _ runtime.
 
 // PrepareForCreate clears fields that are not allowed to be set by end users on creation.
 func (clusterRoleBindingStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.ClusterRoleBinding)
 	if !success {
@@ -143,7 +144,7 @@ func (clusterRoleBindingStrategy) WarningsOnUpdate(_ context.Context, _, _ runti
 
 // PrepareForUpdate clears fields that are not allowed to be set by end users on update.
 func (clusterRoleBindingStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.ClusterRoleBinding)
 	if !success {
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"path"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -33,7 +32,7 @@ func NewClusterRoleREST(
 	storageConfig storagebackend.Config,
 	scheme runtime.Scheme,
 ) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(clusterRoleResource)),
 		func() runtime.Object { return &rbacv1.ClusterRole{} },
 		func() runtime.Object { return &rbacv1.ClusterRoleList{} },
@@ -66,6 +65,8 @@ func NewClusterRoleREST(
 		UpdateStrategy: clusterRoleStrategy,
 		DeleteStrategy: clusterRoleStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return restStore, nil
@@ -125,7 +126,7 @@ func (clusterRoleStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object)
 
 // PrepareForCreate clears fields that are not allowed to be set by end users on creation.
 func (clusterRoleStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.ClusterRole)
 	if !success {
@@ -142,7 +143,7 @@ func (clusterRoleStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Obje
 
 // PrepareForUpdate clears fields that are not allowed to be set by end users on update.
 func (clusterRoleStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.ClusterRole)
 	if !success {
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"path"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,7 +26,7 @@ const roleBindingResource = "rolebindings"
 //
 //nolint:dupl // Similar to pkg/storage/rbac/roles.go::NewRoleREST but not identical.
 func NewRoleBindingREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(roleBindingResource)),
 		func() runtime.Object { return &rbacv1.RoleBinding{} },
 		func() runtime.Object { return &rbacv1.RoleBindingList{} },
@@ -60,6 +59,8 @@ func NewRoleBindingREST(storageConfig storagebackend.Config, scheme runtime.Sche
 		UpdateStrategy: roleBindingStrategy,
 		DeleteStrategy: roleBindingStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
@@ -103,7 +104,7 @@ func (roleBindingStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object)
 
 // PrepareForCreate clears fields that are not allowed to be set by end users on creation.
 func (roleBindingStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.RoleBinding)
 	if !success {
@@ -120,7 +121,7 @@ func (roleBindingStrategy) WarningsOnUpdate(_ context.Context, _ runtime.Object,
 
 // PrepareForUpdate clears fields that are not allowed to be set by end users on update.
 func (roleBindingStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.RoleBinding)
 	if !success {
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"path"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -31,7 +30,7 @@ type REST struct {
 //
 //nolint:dupl // Similar to pkg/storage/rbac/rolebindings.go::NewRoleBindingREST but not identical.
 func NewRoleREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(roleResource)),
 		func() runtime.Object { return &rbacv1.Role{} },
 		func() runtime.Object { return &rbacv1.RoleList{} },
@@ -64,6 +63,8 @@ func NewRoleREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (re
 		UpdateStrategy: roleStrategy,
 		DeleteStrategy: roleStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
@@ -107,7 +108,7 @@ func (roleStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []stri
 
 // PrepareForCreate clears fields that are not allowed to be set by end users on creation.
 func (roleStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.Role)
 	if !success {
@@ -124,7 +125,7 @@ func (roleStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []s
 
 // PrepareForUpdate clears fields that are not allowed to be set by end users on update.
 func (roleStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	_, success := obj.(*rbacv1.Role)
 	if !success {
@@ -0,0 +1,352 @@
+// Package resourcequotas implements the storage strategy towards kine for the core v1
+// ResourceQuota resource. Kommodity enforces the object count quotas in the quota
+// admission plugin of the server; the objects are stored and validated like the
+// upstream API server does.
+package resourcequotas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity/pkg/storage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/kubernetes/pkg/apis/core"
+	coreinstall "k8s.io/kubernetes/pkg/apis/core/install"
+	corevalidation "k8s.io/kubernetes/pkg/apis/core/validation"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+const resourceQuotaResource = "resourcequotas"
+
+// REST wraps a Store and implements rest.Scoper.
+type REST struct {
+	*genericregistry.Store
+}
+
+// StatusREST implements the REST endpoint for the resourcequotas/status subresource.
+type StatusREST struct {
+	store *genericregistry.Store
+}
+
+var _ rest.ShortNamesProvider = &REST{}
+
+// ShortNames implement ShortNamesProvider to return short names for the resource.
+func (*REST) ShortNames() []string {
+	return []string{"quota"}
+}
+
+// NewResourceQuotasREST creates a REST interface for corev1 ResourceQuota resource,
+// along with the status subresource.
+func NewResourceQuotasREST(storageConfig storagebackend.Config,
+	scheme runtime.Scheme) (*REST, *StatusREST, error) {
+	store, destroy, err := factory.Create(
+		*storageConfig.ForResource(corev1.Resource(resourceQuotaResource)),
+		func() runtime.Object { return &corev1.ResourceQuota{} },
+		func() runtime.Object { return &corev1.ResourceQuotaList{} },
+		"/"+resourceQuotaResource,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	dryRunnableStorage := genericregistry.DryRunnableStorage{
+		Storage: store,
+		Codec:   storageConfig.Codec,
+	}
+
+	resourceQuotaStrategy := resourceQuotaStrategy{
+		ObjectTyper:   &scheme,
+		NameGenerator: names.SimpleNameGenerator,
+	}
+
+	restStore := &genericregistry.Store{
+		NewFunc:       func() runtime.Object { return &corev1.ResourceQuota{} },
+		NewListFunc:   func() runtime.Object { return &corev1.ResourceQuotaList{} },
+		PredicateFunc: storage.NamespacedPredicateFunc(),
+		// Every tenant namespace commonly holds a quota of the same name, so the
+		// namespace is part of the key.
+		KeyRootFunc: func(ctx context.Context) string {
+			return genericregistry.NamespaceKeyRootFunc(ctx, "/"+resourceQuotaResource)
+		},
+		KeyFunc: func(ctx context.Context, name string) (string, error) {
+			//nolint:wrapcheck // API status errors must be returned as is.
+			return genericregistry.NamespaceKeyFunc(ctx, "/"+resourceQuotaResource, name)
+		},
+		ObjectNameFunc:      ObjectNameFunc,
+		CreateStrategy:      resourceQuotaStrategy,
+		UpdateStrategy:      resourceQuotaStrategy,
+		DeleteStrategy:      resourceQuotaStrategy,
+		ResetFieldsStrategy: resourceQuotaStrategy,
+		Storage:             dryRunnableStorage,
+		TableConvertor:      storage.NewTableConvertor(),
+		DestroyFunc:         destroy,
+	}
+
+	statusStore := *restStore
+	statusStore.UpdateStrategy = resourceQuotaStatusStrategy{resourceQuotaStrategy}
+	statusStore.ResetFieldsStrategy = resourceQuotaStatusStrategy{resourceQuotaStrategy}
+
+	return &REST{restStore}, &StatusREST{store: &statusStore}, nil
+}
+
+// New returns an empty ResourceQuota.
+func (r *StatusREST) New() runtime.Object {
+	return r.store.New()
+}
+
+// Destroy is a no-op, the store is shared with the main resource.
+func (r *StatusREST) Destroy() {}
+
+// Get retrieves the ResourceQuota, it is required to support Patch.
+func (r *StatusREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return r.store.Get(ctx, name, options) //nolint:wrapcheck // API status errors must be returned as is.
+}
+
+// GetResetFields returns the fields the status subresource does not let callers change.
+func (r *StatusREST) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	return r.store.GetResetFields()
+}
+
+// Update alters the status of a ResourceQuota.
+func (r *StatusREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo,
+	createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc,
+	_ bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	// Subresources never create objects.
+	//nolint:wrapcheck // API status errors must be returned as is.
+	return r.store.Update(ctx, name, objInfo, createValidation, updateValidation, false, options)
+}
+
+// ObjectNameFunc returns the name of the object.
+func ObjectNameFunc(obj runtime.Object) (string, error) {
+	resourceQuota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return "", storage.ErrObjectIsNotAResourceQuota
+	}
+
+	return resourceQuota.Name, nil
+}
+
+// resourceQuotaStrategy implements RESTCreateStrategy, RESTUpdateStrategy, RESTDeleteStrategy
+// Heavily inspired by: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/core/resourcequota/strategy.go
+type resourceQuotaStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+var _ rest.RESTCreateStrategy = resourceQuotaStrategy{}
+var _ rest.RESTUpdateStrategy = resourceQuotaStrategy{}
+var _ rest.RESTDeleteStrategy = resourceQuotaStrategy{}
+var _ rest.NamespaceScopedStrategy = resourceQuotaStrategy{}
+
+// NamespaceScoped tells the apiserver if the resource lives in a namespace.
+func (resourceQuotaStrategy) NamespaceScoped() bool {
+	return true
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (resourceQuotaStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("status"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForCreate clears the status, which only the status subresource sets.
+func (resourceQuotaStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	resourceQuota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return
+	}
+
+	resourceQuota.Status = corev1.ResourceQuotaStatus{}
+}
+
+// WarningsOnCreate returns warnings for the creation of the given object.
+func (resourceQuotaStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForUpdate keeps the status of the ResourceQuota.
+func (resourceQuotaStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newResourceQuota, okNew := obj.(*corev1.ResourceQuota)
+
+	oldResourceQuota, okOld := old.(*corev1.ResourceQuota)
+	if !okNew || !okOld {
+		return
+	}
+
+	newResourceQuota.Status = oldResourceQuota.Status
+}
+
+// WarningsOnUpdate returns warnings for the given update.
+func (resourceQuotaStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
+	return nil
+}
+
+// PrepareForDelete clears fields before deletion.
+func (resourceQuotaStrategy) PrepareForDelete(_ context.Context, _ runtime.Object) {}
+
+// Validate validates new objects with the validation of the upstream API server.
+func (resourceQuotaStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	resourceQuota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAResourceQuota.Error())}
+	}
+
+	internal := &core.ResourceQuota{}
+
+	errs := toInternal(resourceQuota, internal)
+	if errs != nil {
+		return field.ErrorList{errs}
+	}
+
+	return append(corevalidation.ValidateResourceQuota(internal), validateScopes(resourceQuota)...)
+}
+
+// ValidateUpdate validates updated objects with the validation of the upstream API server.
+func (resourceQuotaStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := resourceQuotasToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	//nolint:forcetypeassert // Checked by resourceQuotasToInternal.
+	return append(corevalidation.ValidateResourceQuotaUpdate(newInternal, oldInternal),
+		validateScopes(obj.(*corev1.ResourceQuota))...)
+}
+
+// validateScopes rejects scoped quotas. The scopes of upstream select pods by their
+// priority class or termination, which do not exist on the management plane, and
+// Kommodity would otherwise silently enforce the quota on every object.
+func validateScopes(resourceQuota *corev1.ResourceQuota) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(resourceQuota.Spec.Scopes) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "scopes"),
+			"scopes are not supported"))
+	}
+
+	if resourceQuota.Spec.ScopeSelector != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "scopeSelector"),
+			"scopes are not supported"))
+	}
+
+	return allErrs
+}
+
+// Canonicalize normalizes objects.
+func (resourceQuotaStrategy) Canonicalize(_ runtime.Object) {}
+
+// AllowCreateOnUpdate determines if create is allowed on update.
+func (resourceQuotaStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// AllowUnconditionalUpdate determines if update can ignore resource version.
+func (resourceQuotaStrategy) AllowUnconditionalUpdate() bool {
+	return true
+}
+
+// resourceQuotaStatusStrategy implements behavior for the ResourceQuota status subresource.
+type resourceQuotaStatusStrategy struct {
+	resourceQuotaStrategy
+}
+
+// GetResetFields returns the set of fields that get reset by the strategy
+// and should not be modified by the user.
+func (resourceQuotaStatusStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	fields := map[fieldpath.APIVersion]*fieldpath.Set{
+		"v1": fieldpath.NewSet(
+			fieldpath.MakePathOrDie("spec"),
+		),
+	}
+
+	return fields
+}
+
+// PrepareForUpdate keeps everything but the status of the ResourceQuota.
+func (resourceQuotaStatusStrategy) PrepareForUpdate(_ context.Context, obj, old runtime.Object) {
+	newResourceQuota, okNew := obj.(*corev1.ResourceQuota)
+
+	oldResourceQuota, okOld := old.(*corev1.ResourceQuota)
+	if !okNew || !okOld {
+		return
+	}
+
+	newResourceQuota.Spec = oldResourceQuota.Spec
+	metav1.ResetObjectMetaForStatus(&newResourceQuota.ObjectMeta, &oldResourceQuota.ObjectMeta)
+}
+
+// ValidateUpdate validates the status of updated objects.
+func (resourceQuotaStatusStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newInternal, oldInternal, errs := resourceQuotasToInternal(obj, old)
+	if errs != nil {
+		return errs
+	}
+
+	return corevalidation.ValidateResourceQuotaStatusUpdate(newInternal, oldInternal)
+}
+
+// internalScheme converts the stored core v1 objects to the internal types the
+// upstream validation is written against.
+//
+//nolint:gochecknoglobals // Built once, only read afterwards.
+var internalScheme = newInternalScheme()
+
+func newInternalScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	coreinstall.Install(scheme)
+
+	return scheme
+}
+
+// toInternal converts the core v1 object in to its internal version out.
+func toInternal(in, out runtime.Object) *field.Error {
+	err := internalScheme.Convert(in, out, nil)
+	if err != nil {
+		return field.InternalError(field.NewPath("object"), fmt.Errorf("failed to convert %T: %w", in, err))
+	}
+
+	return nil
+}
+
+// resourceQuotasToInternal converts the new and old ResourceQuota of an update to their
+// internal versions.
+func resourceQuotasToInternal(obj, old runtime.Object) (*core.ResourceQuota, *core.ResourceQuota, field.ErrorList) {
+	newResourceQuota, okNew := obj.(*corev1.ResourceQuota)
+
+	oldResourceQuota, okOld := old.(*corev1.ResourceQuota)
+	if !okNew || !okOld {
+		return nil, nil, field.ErrorList{field.Invalid(
+			field.NewPath("object"), obj,
+			storage.ErrObjectIsNotAResourceQuota.Error())}
+	}
+
+	newInternal, oldInternal := &core.ResourceQuota{}, &core.ResourceQuota{}
+
+	err := toInternal(newResourceQuota, newInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	err = toInternal(oldResourceQuota, oldInternal)
+	if err != nil {
+		return nil, nil, field.ErrorList{err}
+	}
+
+	return newInternal, oldInternal, nil
+}
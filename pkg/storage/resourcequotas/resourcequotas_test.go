@@ -0,0 +1,153 @@
+package resourcequotas_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/resourcequotas"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+const clusterCount corev1.ResourceName = "count/clusters.cluster.x-k8s.io"
+
+func newResourceQuota(name string, clusters string, labels map[string]string) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Labels: labels},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{clusterCount: resource.MustParse(clusters)},
+		},
+	}
+}
+
+func newResourceQuotaStorage(tb testing.TB) (*resourcequotas.REST, *resourcequotas.StatusREST) {
+	tb.Helper()
+
+	storageConfig, scheme := storagetest.NewStorageConfig(tb, corev1.SchemeGroupVersion)
+
+	storage, status, err := resourcequotas.NewResourceQuotasREST(storageConfig, *scheme)
+	if err != nil {
+		tb.Fatalf("failed to create resourcequotas storage: %v", err)
+	}
+
+	return storage, status
+}
+
+func resourceQuotaContext(namespace string) context.Context {
+	ctx := genericapirequest.WithNamespace(context.Background(), namespace)
+
+	return genericapirequest.WithRequestInfo(ctx, &genericapirequest.RequestInfo{
+		IsResourceRequest: true,
+		APIVersion:        corev1.SchemeGroupVersion.Version,
+		Namespace:         namespace,
+		Resource:          "resourcequotas",
+	})
+}
+
+func TestResourceQuotasStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storage, _ := newResourceQuotaStorage(tb)
+
+			return storage
+		},
+		GroupVersion: corev1.SchemeGroupVersion,
+		Resource:     "resourcequotas",
+		Namespace:    metav1.NamespaceDefault,
+		NewObject: func(name string) runtime.Object {
+			return newResourceQuota(name, "10", nil)
+		},
+		Mutate: func(obj runtime.Object) {
+			//nolint:forcetypeassert // Always a ResourceQuota.
+			obj.(*corev1.ResourceQuota).Spec.Hard[clusterCount] = resource.MustParse("20")
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "team label",
+				Objects: []runtime.Object{
+					newResourceQuota("first", "1", map[string]string{"team": "blue"}),
+					newResourceQuota("second", "1", map[string]string{"team": "green"}),
+				},
+				LabelSelector: "team=green",
+				Expected:      []string{"second"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{Name: "name", Object: newResourceQuota("Not_A_Name", "1", nil)},
+			{Name: "negative", Object: newResourceQuota("negative", "-1", nil)},
+			{
+				Name: "scopes",
+				Object: func() runtime.Object {
+					resourceQuota := newResourceQuota("scoped", "1", nil)
+					resourceQuota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+
+					return resourceQuota
+				}(),
+			},
+		},
+	})
+}
+
+func TestResourceQuotaStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctx := resourceQuotaContext(metav1.NamespaceDefault)
+	storage, status := newResourceQuotaStorage(t)
+
+	t.Cleanup(storage.Destroy)
+
+	resourceQuota := newResourceQuota("clusters", "2", nil)
+	resourceQuota.Status.Used = corev1.ResourceList{clusterCount: resource.MustParse("1")}
+
+	obj, err := storage.Create(ctx, resourceQuota, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create resource quota: %v", err)
+	}
+
+	created := obj.(*corev1.ResourceQuota) //nolint:forcetypeassert // Always a ResourceQuota.
+	if len(created.Status.Used) != 0 {
+		t.Fatalf("expected an empty status, got %+v", created.Status)
+	}
+
+	created.Status = corev1.ResourceQuotaStatus{
+		Hard: created.Spec.Hard,
+		Used: corev1.ResourceList{clusterCount: resource.MustParse("1")},
+	}
+	created.Spec.Hard = corev1.ResourceList{clusterCount: resource.MustParse("5")}
+
+	obj, _, err = status.Update(ctx, created.Name, rest.DefaultUpdatedObjectInfo(created),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update resource quota status: %v", err)
+	}
+
+	updated := obj.(*corev1.ResourceQuota) //nolint:forcetypeassert // Always a ResourceQuota.
+	if updated.Status.Used.Name(clusterCount, resource.DecimalSI).Value() != 1 ||
+		updated.Spec.Hard.Name(clusterCount, resource.DecimalSI).Value() != 2 {
+		t.Fatalf("expected the status subresource to only update the status, got %+v", updated)
+	}
+
+	updated.Spec.Hard = corev1.ResourceList{clusterCount: resource.MustParse("3")}
+	updated.Status = corev1.ResourceQuotaStatus{}
+
+	obj, _, err = storage.Update(ctx, updated.Name, rest.DefaultUpdatedObjectInfo(updated),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update resource quota: %v", err)
+	}
+
+	raised := obj.(*corev1.ResourceQuota) //nolint:forcetypeassert // Always a ResourceQuota.
+	if raised.Spec.Hard.Name(clusterCount, resource.DecimalSI).Value() != 3 ||
+		raised.Status.Used.Name(clusterCount, resource.DecimalSI).Value() != 1 {
+		t.Fatalf("expected a spec update to keep the status, got %+v", raised)
+	}
+}
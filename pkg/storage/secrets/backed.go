@@ -0,0 +1,226 @@
+package secrets
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apistorage "k8s.io/apiserver/pkg/storage"
+)
+
+// backedStorage keeps the data of the Secrets selected by the external store in the
+// secret manager. The Secrets stored in kine only hold the key of their data, which
+// is filled in again on every read, so that clients do not notice the difference.
+type backedStorage struct {
+	apistorage.Interface
+
+	external *ExternalStore
+}
+
+var _ apistorage.Interface = &backedStorage{}
+
+func newBackedStorage(storage apistorage.Interface, external *ExternalStore) apistorage.Interface {
+	if external == nil {
+		return storage
+	}
+
+	return &backedStorage{Interface: storage, external: external}
+}
+
+// Create writes the data to the backend before the Secret is created, and removes it
+// again if the Secret could not be created.
+func (s *backedStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return s.Interface.Create(ctx, key, obj, out, ttl) //nolint:wrapcheck // Passed through as is.
+	}
+
+	stored, writtenKey, err := s.external.store(ctx, secret, nil)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	err = s.Interface.Create(ctx, key, stored, out, ttl)
+	if err != nil {
+		s.external.discard(ctx, writtenKey)
+
+		return err //nolint:wrapcheck // Storage errors are API errors.
+	}
+
+	return s.fill(ctx, out)
+}
+
+// Delete removes the data from the backend once the Secret is deleted.
+func (s *backedStorage) Delete(ctx context.Context, key string, out runtime.Object,
+	preconditions *apistorage.Preconditions, validateDeletion apistorage.ValidateObjectFunc,
+	cachedExistingObject runtime.Object, opts apistorage.DeleteOptions) error {
+	validate := func(ctx context.Context, obj runtime.Object) error {
+		if validateDeletion == nil {
+			return nil
+		}
+
+		filled := obj.DeepCopyObject()
+
+		err := s.fill(ctx, filled)
+		if err != nil {
+			return err
+		}
+
+		return validateDeletion(ctx, filled)
+	}
+
+	err := s.Interface.Delete(ctx, key, out, preconditions, validate, cachedExistingObject, opts)
+	if err != nil {
+		return err //nolint:wrapcheck // Storage errors are API errors.
+	}
+
+	secret, ok := out.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+
+	deletedKey := backendKey(secret)
+
+	err = s.fill(ctx, out)
+
+	s.external.discard(ctx, deletedKey)
+
+	return err
+}
+
+// Watch fills in the data of the Secrets of the events.
+func (s *backedStorage) Watch(ctx context.Context, key string, opts apistorage.ListOptions) (watch.Interface, error) {
+	watcher, err := s.Interface.Watch(ctx, key, opts)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Storage errors are API errors.
+	}
+
+	return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+		if event.Type != watch.Added && event.Type != watch.Modified && event.Type != watch.Deleted {
+			return event, true
+		}
+
+		err := s.fill(ctx, event.Object)
+
+		switch {
+		case err == nil:
+			return event, true
+		case event.Type == watch.Deleted:
+			// The data of deleted Secrets may already be gone from the backend.
+			return event, true
+		default:
+			return watch.Event{Type: watch.Error, Object: &apierrors.NewInternalError(err).ErrStatus}, true
+		}
+	}), nil
+}
+
+// Get fills in the data of the Secret.
+func (s *backedStorage) Get(ctx context.Context, key string, opts apistorage.GetOptions, objPtr runtime.Object) error {
+	err := s.Interface.Get(ctx, key, opts, objPtr)
+	if err != nil {
+		return err //nolint:wrapcheck // Storage errors are API errors.
+	}
+
+	return s.fill(ctx, objPtr)
+}
+
+// GetList fills in the data of the listed Secrets.
+func (s *backedStorage) GetList(ctx context.Context, key string, opts apistorage.ListOptions,
+	listObj runtime.Object) error {
+	err := s.Interface.GetList(ctx, key, opts, listObj)
+	if err != nil {
+		return err //nolint:wrapcheck // Storage errors are API errors.
+	}
+
+	list, ok := listObj.(*corev1.SecretList)
+	if !ok {
+		return nil
+	}
+
+	for i := range list.Items {
+		err = s.fill(ctx, &list.Items[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GuaranteedUpdate hands the Secret with its data to the update, and writes the
+// updated data to the backend before the Secret is updated. Data written by attempts
+// that did not make it into kine, and the data the update replaced, is removed.
+func (s *backedStorage) GuaranteedUpdate(ctx context.Context, key string, destination runtime.Object,
+	ignoreNotFound bool, preconditions *apistorage.Preconditions, tryUpdate apistorage.UpdateFunc,
+	cachedExistingObject runtime.Object) error {
+	var written, replaced []string
+
+	update := func(input runtime.Object, res apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+		existing, ok := input.DeepCopyObject().(*corev1.Secret)
+		if !ok {
+			return tryUpdate(input, res)
+		}
+
+		err := s.external.fill(ctx, existing)
+		if err != nil {
+			return nil, nil, apierrors.NewInternalError(err)
+		}
+
+		output, ttl, err := tryUpdate(existing, res)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		secret, ok := output.(*corev1.Secret)
+		if !ok {
+			return output, ttl, nil
+		}
+
+		stored, writtenKey, err := s.external.store(ctx, secret, existing)
+		if err != nil {
+			return nil, nil, apierrors.NewInternalError(err)
+		}
+
+		written = append(written, writtenKey)
+		replaced = append(replaced, backendKey(existing))
+
+		return stored, ttl, nil
+	}
+
+	err := s.Interface.GuaranteedUpdate(ctx, key, destination, ignoreNotFound, preconditions, update,
+		cachedExistingObject)
+	if err != nil {
+		s.external.discard(ctx, written...)
+
+		return err //nolint:wrapcheck // Storage errors are API errors.
+	}
+
+	current := ""
+	if secret, ok := destination.(*corev1.Secret); ok {
+		current = backendKey(secret)
+	}
+
+	stale := slices.DeleteFunc(slices.Concat(written, replaced), func(key string) bool {
+		return key == current
+	})
+	s.external.discard(ctx, slices.Compact(slices.Sorted(slices.Values(stale)))...)
+
+	return s.fill(ctx, destination)
+}
+
+func (s *backedStorage) fill(ctx context.Context, obj runtime.Object) error {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+
+	err := s.external.fill(ctx, secret)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	return nil
+}
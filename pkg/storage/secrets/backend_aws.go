@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/aws/sigv4"
+)
+
+const (
+	awsSecretsManagerService = "secretsmanager"
+	awsJSONContentType       = "application/x-amz-json-1.1"
+	awsNotFoundException     = "ResourceNotFoundException"
+)
+
+// awsBackend keeps the data of Secrets in AWS Secrets Manager, as secrets named
+// <prefix>/<namespace>/<name>/<suffix> holding the data as JSON. It calls the JSON API
+// with SigV4 signed requests; credentials are read from the standard AWS_* environment
+// variables on every call so that rotated session credentials are picked up.
+type awsBackend struct {
+	client   *http.Client
+	endpoint string
+	region   string
+	prefix   string
+	now      func() time.Time
+}
+
+func newAWSBackend(client *http.Client, region, prefix string) (*awsBackend, error) {
+	if os.Getenv(sigv4.EnvAccessKeyID) == "" || os.Getenv(sigv4.EnvSecretAccessKey) == "" {
+		return nil, fmt.Errorf("%w: %s and %s must be set", ErrMissingCredentials,
+			sigv4.EnvAccessKeyID, sigv4.EnvSecretAccessKey)
+	}
+
+	return &awsBackend{
+		client:   client,
+		endpoint: "https://secretsmanager." + region + ".amazonaws.com/",
+		region:   region,
+		prefix:   prefix,
+		now:      time.Now,
+	}, nil
+}
+
+func (b *awsBackend) Get(ctx context.Context, key string) (map[string][]byte, error) {
+	var response struct {
+		SecretString string `json:"SecretString"`
+	}
+
+	err := b.call(ctx, "GetSecretValue", map[string]string{"SecretId": b.prefix + "/" + key}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	// encoding/json base64 encodes the values, which keeps binary data intact.
+	data := map[string][]byte{}
+
+	err = json.Unmarshal([]byte(response.SecretString), &data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret %s: %w", key, err)
+	}
+
+	return data, nil
+}
+
+// Put creates a new secret, as data is never written twice under the same key.
+func (b *awsBackend) Put(ctx context.Context, key string, data map[string][]byte) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode secret %s: %w", key, err)
+	}
+
+	return b.call(ctx, "CreateSecret", map[string]string{
+		"Name":         b.prefix + "/" + key,
+		"SecretString": string(encoded),
+	}, nil)
+}
+
+// Delete removes the secret without the recovery window, as Kommodity does not refer
+// to it anymore.
+func (b *awsBackend) Delete(ctx context.Context, key string) error {
+	err := b.call(ctx, "DeleteSecret", map[string]any{
+		"SecretId":                   b.prefix + "/" + key,
+		"ForceDeleteWithoutRecovery": true,
+	}, nil)
+	if errors.Is(err, ErrSecretDataNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// call invokes a secretsmanager operation.
+func (b *awsBackend) call(ctx context.Context, operation string, input any, out any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode AWS Secrets Manager request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create AWS Secrets Manager request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", awsJSONContentType)
+	req.Header.Set("X-Amz-Target", "secretsmanager."+operation)
+
+	sigv4.Sign(req, body, sigv4.CredentialsFromEnv(), b.region, awsSecretsManagerService, b.now())
+
+	return doJSON(b.client, req, out, func(status int, body []byte) bool {
+		return status == http.StatusBadRequest && bytes.Contains(body, []byte(awsNotFoundException))
+	})
+}
+
+// doJSON executes a request against AWS Secrets Manager and decodes a JSON response into
+// out, if given. Responses reporting a missing secret are returned as
+// ErrSecretDataNotFound, other non-2xx responses as ErrSecretsBackend errors
+// including the body.
+func doJSON(client *http.Client, req *http.Request, out any, notFound func(status int, body []byte) bool) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSecretsBackend, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendBodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", req.URL.Host, err)
+	}
+
+	if notFound(resp.StatusCode, body) {
+		return ErrSecretDataNotFound
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned %d: %s", ErrSecretsBackend, req.URL.Host, resp.StatusCode,
+			strings.TrimSpace(string(body)))
+	}
+
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(body, out)
+	if err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Host, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package secrets_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/secrets"
+)
+
+func TestVaultBackend(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		stored = map[string]map[string]string{}
+	)
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		dataPath, isData := strings.CutPrefix(r.URL.Path, "/v1/secret/data/")
+		metadataPath, _ := strings.CutPrefix(r.URL.Path, "/v1/secret/metadata/")
+
+		switch {
+		case isData && r.Method == http.MethodPost:
+			body := struct {
+				Data map[string]string `json:"data"`
+			}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			stored[dataPath] = body.Data
+		case isData && r.Method == http.MethodGet && stored[dataPath] != nil:
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": stored[dataPath]}})
+		case r.Method == http.MethodDelete && stored[metadataPath] != nil:
+			delete(stored, metadataPath)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(vault.Close)
+
+	backend := secrets.NewVaultBackend(vault.Client(), vault.URL+"/", "root", "/secret/", "kommodity")
+	key := "default/db-credentials/abc"
+
+	err := backend.Put(t.Context(), key, map[string][]byte{"password": {0, 1, 2}})
+	if err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+
+	if stored["kommodity/"+key]["password"] != "AAEC" {
+		t.Fatalf("expected base64 encoded values below the prefix, got %v", stored)
+	}
+
+	data, err := backend.Get(t.Context(), key)
+	if err != nil || string(data["password"]) != string([]byte{0, 1, 2}) {
+		t.Fatalf("expected the stored data, got %v: %v", data, err)
+	}
+
+	err = backend.Delete(t.Context(), key)
+	if err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	_, err = backend.Get(t.Context(), key)
+	if !errors.Is(err, secrets.ErrSecretDataNotFound) {
+		t.Fatalf("expected the data to be gone, got %v", err)
+	}
+
+	err = backend.Delete(t.Context(), key)
+	if err != nil {
+		t.Fatalf("expected deleting missing data to succeed, got %v", err)
+	}
+
+	_, err = secrets.NewVaultBackend(vault.Client(), vault.URL, "wrong", "secret", "kommodity").
+		Get(t.Context(), key)
+	if !errors.Is(err, secrets.ErrSecretsBackend) {
+		t.Fatalf("expected an error for an invalid token, got %v", err)
+	}
+}
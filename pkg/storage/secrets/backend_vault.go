@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kommodity-io/kommodity/pkg/vault"
+)
+
+// vaultBackend keeps the data of Secrets in the HashiCorp Vault KV version 2 secrets
+// engine, at <mount>/<prefix>/<namespace>/<name>/<suffix>. Values are base64 encoded,
+// as KV values are strings while Secret data may be binary.
+type vaultBackend struct {
+	client *vault.Client
+	mount  string
+	prefix string
+}
+
+func newVaultBackend(client *http.Client, address, token, mount, prefix string) *vaultBackend {
+	return &vaultBackend{
+		client: vault.NewClient(client, address, token),
+		mount:  strings.Trim(mount, "/"),
+		prefix: prefix,
+	}
+}
+
+func (b *vaultBackend) Get(ctx context.Context, key string) (map[string][]byte, error) {
+	var response struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+
+	err := b.do(ctx, http.MethodGet, "data", key, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string][]byte, len(response.Data.Data))
+
+	for name, value := range response.Data.Data {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode vault value %s: %w", name, err)
+		}
+
+		data[name] = decoded
+	}
+
+	return data, nil
+}
+
+func (b *vaultBackend) Put(ctx context.Context, key string, data map[string][]byte) error {
+	encoded := make(map[string]string, len(data))
+	for name, value := range data {
+		encoded[name] = base64.StdEncoding.EncodeToString(value)
+	}
+
+	return b.do(ctx, http.MethodPost, "data", key, map[string]any{"data": encoded}, nil)
+}
+
+// Delete removes all versions of the key, not only the latest one.
+func (b *vaultBackend) Delete(ctx context.Context, key string) error {
+	err := b.do(ctx, http.MethodDelete, "metadata", key, nil, nil)
+	if errors.Is(err, ErrSecretDataNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// do calls <address>/v1/<mount>/<operation>/<prefix>/<key> and decodes the JSON response.
+func (b *vaultBackend) do(ctx context.Context, method, operation, key string, body any, out any) error {
+	err := b.client.Do(ctx, method, b.mount+"/"+operation+"/"+b.prefix+"/"+key, body, out)
+	if errors.Is(err, vault.ErrNotFound) {
+		return ErrSecretDataNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSecretsBackend, err)
+	}
+
+	return nil
+}
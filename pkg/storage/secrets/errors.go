@@ -0,0 +1,16 @@
+package secrets
+
+import "errors"
+
+var (
+	// ErrUnknownSecretsBackend is returned when the configured secret manager is not supported.
+	ErrUnknownSecretsBackend = errors.New("unknown secrets backend")
+	// ErrMissingCredentials is returned when no credentials for the secret manager are available.
+	ErrMissingCredentials = errors.New("missing secrets backend credentials")
+	// ErrSecretsBackend is returned when the secret manager rejects a request.
+	ErrSecretsBackend = errors.New("secrets backend error")
+	// ErrSecretDataNotFound is returned when the secret manager holds no data under a key.
+	ErrSecretDataNotFound = errors.New("secret data not found in secrets backend")
+	// ErrInvalidBackendKey is returned when the backend key of a Secret does not belong to it.
+	ErrInvalidBackendKey = errors.New("backend key does not belong to the Secret")
+)
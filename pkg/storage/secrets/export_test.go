@@ -0,0 +1,8 @@
+package secrets
+
+// Secrets backends for black-box testing.
+//
+//nolint:gochecknoglobals // test exports
+var (
+	NewVaultBackend = newVaultBackend
+)
@@ -0,0 +1,202 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	// BackendKeyAnnotation records the key the data of a Secret is kept under in the
+	// external secret manager. Every write stores the data under a new key, so data
+	// stored under a key never changes and can be cached.
+	BackendKeyAnnotation = "kommodity.io/secrets-backend-key"
+
+	backendHTTPTimeout   = 10 * time.Second
+	maxBackendBodyBytes  = 1 << 20
+	backendCacheSize     = 4096
+	backendKeySuffixSize = 10
+)
+
+// SecretBackend keeps the data of Secrets in an external secret manager, such as
+// Vault or AWS Secrets Manager.
+type SecretBackend interface {
+	// Get returns the data stored under the key, or ErrSecretDataNotFound.
+	Get(ctx context.Context, key string) (map[string][]byte, error)
+	// Put stores the data under the key.
+	Put(ctx context.Context, key string, data map[string][]byte) error
+	// Delete removes the data stored under the key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ExternalStore decides which Secrets are kept in a SecretBackend and moves their data
+// between the backend and the objects stored in kine, caching what it reads.
+type ExternalStore struct {
+	backend    SecretBackend
+	types      []string
+	namespaces []string
+	cache      *cache.LRUExpireCache
+	cacheTTL   time.Duration
+}
+
+// NewExternalStore keeps the data of the Secrets of the given types and namespaces in
+// the backend. Empty lists select Secrets of all types or in all namespaces.
+func NewExternalStore(backend SecretBackend, types, namespaces []string, cacheTTL time.Duration) *ExternalStore {
+	return &ExternalStore{
+		backend:    backend,
+		types:      types,
+		namespaces: namespaces,
+		cache:      cache.NewLRUExpireCache(backendCacheSize),
+		cacheTTL:   cacheTTL,
+	}
+}
+
+// NewExternalStoreFromConfig builds the external store of the configured secret
+// manager, or returns nil when Secrets are kept in kine.
+func NewExternalStoreFromConfig(cfg *config.SecretsBackendConfig) (*ExternalStore, error) {
+	if !cfg.Enabled() {
+		return nil, nil //nolint:nilnil // A nil store keeps all Secrets in kine.
+	}
+
+	httpClient := &http.Client{Timeout: backendHTTPTimeout}
+
+	var backend SecretBackend
+
+	switch cfg.Provider {
+	case config.SecretsBackendVault:
+		backend = newVaultBackend(httpClient, cfg.VaultAddress, cfg.VaultToken, cfg.VaultMount, cfg.Prefix)
+	case config.SecretsBackendAWS:
+		awsBackend, err := newAWSBackend(httpClient, cfg.AWSRegion, cfg.Prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		backend = awsBackend
+	case config.SecretsBackendNone:
+		return nil, nil //nolint:nilnil // Unreachable, handled by Enabled.
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSecretsBackend, cfg.Provider)
+	}
+
+	return NewExternalStore(backend, cfg.Types, cfg.Namespaces, cfg.CacheTTL), nil
+}
+
+// Selects reports whether the data of the Secret is kept in the backend.
+func (e *ExternalStore) Selects(secret *corev1.Secret) bool {
+	return (len(e.types) == 0 || slices.Contains(e.types, string(secret.Type))) &&
+		(len(e.namespaces) == 0 || slices.Contains(e.namespaces, secret.Namespace))
+}
+
+// store returns a copy of the Secret to store in kine. The data of selected Secrets
+// is written to the backend under a new key, unless it is unchanged from the existing
+// Secret, and replaced with the key annotation. Other Secrets are returned without the
+// annotation, so that it can only ever be set by the store.
+func (e *ExternalStore) store(ctx context.Context, secret, existing *corev1.Secret) (*corev1.Secret, string, error) {
+	stored := secret.DeepCopy()
+	delete(stored.Annotations, BackendKeyAnnotation)
+
+	if !e.Selects(secret) {
+		return stored, "", nil
+	}
+
+	if stored.Annotations == nil {
+		stored.Annotations = map[string]string{}
+	}
+
+	stored.Data = nil
+
+	existingKey := backendKey(existing)
+	if existingKey != "" && maps.EqualFunc(secret.Data, existing.Data, slices.Equal[[]byte]) {
+		stored.Annotations[BackendKeyAnnotation] = existingKey
+
+		return stored, "", nil
+	}
+
+	key := secret.Namespace + "/" + secret.Name + "/" + utilrand.String(backendKeySuffixSize)
+
+	data := secret.Data
+	if data == nil {
+		data = map[string][]byte{}
+	}
+
+	err := e.backend.Put(ctx, key, data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to write Secret %s/%s to the secrets backend: %w",
+			secret.Namespace, secret.Name, err)
+	}
+
+	e.cache.Add(key, maps.Clone(data), e.cacheTTL)
+
+	stored.Annotations[BackendKeyAnnotation] = key
+
+	return stored, key, nil
+}
+
+// fill sets the data of a Secret stored in kine from the backend.
+func (e *ExternalStore) fill(ctx context.Context, secret *corev1.Secret) error {
+	key := backendKey(secret)
+	if key == "" {
+		return nil
+	}
+
+	// Keys are only ever set by the store, this guards against annotations written
+	// around it handing out the data of another Secret.
+	if !strings.HasPrefix(key, secret.Namespace+"/"+secret.Name+"/") {
+		return fmt.Errorf("%w: %s/%s: %s", ErrInvalidBackendKey, secret.Namespace, secret.Name, key)
+	}
+
+	cached, found := e.cache.Get(key)
+	if found {
+		data, _ := cached.(map[string][]byte)
+		secret.Data = maps.Clone(data)
+
+		return nil
+	}
+
+	data, err := e.backend.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read Secret %s/%s from the secrets backend: %w",
+			secret.Namespace, secret.Name, err)
+	}
+
+	e.cache.Add(key, data, e.cacheTTL)
+
+	secret.Data = maps.Clone(data)
+
+	return nil
+}
+
+// discard deletes data from the backend that no Secret refers to anymore. Failures
+// only leave the data behind, so they are logged.
+func (e *ExternalStore) discard(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		e.cache.Remove(key)
+
+		err := e.backend.Delete(ctx, key)
+		if err != nil {
+			log.Printf("failed to delete %s from the secrets backend: %v", key, err)
+		}
+	}
+}
+
+func backendKey(secret *corev1.Secret) string {
+	if secret == nil {
+		return ""
+	}
+
+	return secret.Annotations[BackendKeyAnnotation]
+}
@@ -41,9 +41,14 @@ func (*REST) ShortNames() []string {
 	return []string{"sc"}
 }
 
-// NewSecretsREST creates a REST interface for corev1 Secret resource.
-func NewSecretsREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+// NewSecretsREST creates a REST interface for corev1 Secret resource. The data of the
+// Secrets selected by the external store is kept in its secret manager instead of kine;
+// a nil external store keeps all Secrets in kine. With watchCache, the Secrets are
+// served from a watch cache. It caches them as stored in kine, so the data kept in the
+// secret manager is still read from it and never held in memory.
+func NewSecretsREST(storageConfig storagebackend.Config, scheme runtime.Scheme,
+	external *ExternalStore, watchCache bool) (rest.Storage, error) {
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(secretResource)),
 		func() runtime.Object { return &corev1.Secret{} },
 		func() runtime.Object { return &corev1.SecretList{} },
@@ -53,8 +58,28 @@ func NewSecretsREST(storageConfig storagebackend.Config, scheme runtime.Scheme)
 		return nil, fmt.Errorf("failed to create storage backend: %w", err)
 	}
 
+	if watchCache {
+		cached, destroyCached, err := storage.NewWatchCache(storage.WatchCache{
+			Storage:        store,
+			Destroy:        destroy,
+			Codec:          storageConfig.Codec,
+			Resource:       secretResource,
+			NewFunc:        func() runtime.Object { return &corev1.Secret{} },
+			NewListFunc:    func() runtime.Object { return &corev1.SecretList{} },
+			GetAttrsFunc:   GetAttrs,
+			ObjectNameFunc: ObjectNameFunc,
+		})
+		if err != nil {
+			destroy()
+
+			return nil, err //nolint:wrapcheck // Already wrapped by the storage package.
+		}
+
+		store, destroy = cached, destroyCached
+	}
+
 	dryRunnableStorage := genericregistry.DryRunnableStorage{
-		Storage: store,
+		Storage: newBackedStorage(store, external),
 		Codec:   storageConfig.Codec,
 	}
 
@@ -77,6 +102,8 @@ func NewSecretsREST(storageConfig storagebackend.Config, scheme runtime.Scheme)
 		UpdateStrategy: secretStrategy,
 		DeleteStrategy: secretStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
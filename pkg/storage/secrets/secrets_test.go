@@ -0,0 +1,268 @@
+package secrets_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/secrets"
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newSecret(name string, secretType corev1.SecretType, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Labels: labels},
+		Type:       secretType,
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+}
+
+// memoryBackend is a secrets backend keeping the data in memory.
+type memoryBackend struct {
+	mu   sync.Mutex
+	data map[string]map[string][]byte
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{data: map[string]map[string][]byte{}}
+}
+
+func (b *memoryBackend) Get(_ context.Context, key string) (map[string][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data, found := b.data[key]
+	if !found {
+		return nil, secrets.ErrSecretDataNotFound
+	}
+
+	return maps.Clone(data), nil
+}
+
+func (b *memoryBackend) Put(_ context.Context, key string, data map[string][]byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data[key] = maps.Clone(data)
+
+	return nil
+}
+
+func (b *memoryBackend) Delete(_ context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.data, key)
+
+	return nil
+}
+
+func (b *memoryBackend) keys() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(b.data))
+}
+
+func TestSecretsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, secretsSuite(nil, false))
+}
+
+// TestCachedSecretsStorage runs the suite with the Secrets served from a watch cache,
+// and their data kept in a secrets backend below it.
+func TestCachedSecretsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, secretsSuite(func() *secrets.ExternalStore {
+		return secrets.NewExternalStore(newMemoryBackend(), nil, nil, time.Minute)
+	}, true))
+}
+
+// TestExternalSecretsStorage runs the suite with the data of all Secrets kept in a
+// secrets backend, which clients must not notice.
+func TestExternalSecretsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, secretsSuite(func() *secrets.ExternalStore {
+		return secrets.NewExternalStore(newMemoryBackend(), nil, nil, time.Minute)
+	}, false))
+}
+
+func secretsSuite(newExternalStore func() *secrets.ExternalStore, watchCache bool) storagetest.Suite {
+	return storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, corev1.SchemeGroupVersion)
+
+			var external *secrets.ExternalStore
+			if newExternalStore != nil {
+				external = newExternalStore()
+			}
+
+			storage, err := secrets.NewSecretsREST(storageConfig, *scheme, external, watchCache)
+			if err != nil {
+				tb.Fatalf("failed to create secrets storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: corev1.SchemeGroupVersion,
+		Resource:     "secrets",
+		Namespace:    metav1.NamespaceDefault,
+		NewObject: func(name string) runtime.Object {
+			return newSecret(name, corev1.SecretTypeOpaque, nil)
+		},
+		Mutate: func(obj runtime.Object) {
+			obj.(*corev1.Secret).Data["key"] = []byte("changed") //nolint:forcetypeassert // Always a Secret.
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "type",
+				Objects: []runtime.Object{
+					newSecret("opaque", corev1.SecretTypeOpaque, nil),
+					newSecret("token", corev1.SecretTypeBootstrapToken, nil),
+				},
+				FieldSelector: "type=" + string(corev1.SecretTypeBootstrapToken),
+				Expected:      []string{"token"},
+			},
+			{
+				Name: "name",
+				Objects: []runtime.Object{
+					newSecret("first", corev1.SecretTypeOpaque, nil),
+					newSecret("second", corev1.SecretTypeOpaque, nil),
+				},
+				FieldSelector: "metadata.name=second",
+				Expected:      []string{"second"},
+			},
+			{
+				Name: "label",
+				Objects: []runtime.Object{
+					newSecret("labelled", corev1.SecretTypeOpaque, map[string]string{"app": "kommodity"}),
+					newSecret("unlabelled", corev1.SecretTypeOpaque, nil),
+				},
+				LabelSelector: "app=kommodity",
+				Expected:      []string{"labelled"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{Name: "name", Object: newSecret("Not_A_Name", corev1.SecretTypeOpaque, nil)},
+			{Name: "data key", Object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bad-key", Namespace: metav1.NamespaceDefault},
+				Data:       map[string][]byte{"not a key": []byte("value")},
+			}},
+		},
+	}
+}
+
+func TestExternalSecretsKeepDataOutOfKine(t *testing.T) {
+	t.Parallel()
+
+	storageConfig, scheme := storagetest.NewStorageConfig(t, corev1.SchemeGroupVersion)
+	backend := newMemoryBackend()
+
+	newStorage := func(external *secrets.ExternalStore) rest.StandardStorage {
+		storage, err := secrets.NewSecretsREST(storageConfig, *scheme, external, false)
+		if err != nil {
+			t.Fatalf("failed to create secrets storage: %v", err)
+		}
+
+		t.Cleanup(storage.Destroy)
+
+		return storage.(rest.StandardStorage) //nolint:forcetypeassert // Always a StandardStorage.
+	}
+
+	backed := newStorage(secrets.NewExternalStore(backend, []string{string(corev1.SecretTypeOpaque)}, nil, time.Minute))
+	plain := newStorage(nil)
+
+	ctx := genericapirequest.WithNamespace(t.Context(), metav1.NamespaceDefault)
+
+	get := func(storage rest.StandardStorage, name string) *corev1.Secret {
+		obj, err := storage.Get(ctx, name, &metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get secret: %v", err)
+		}
+
+		return obj.(*corev1.Secret) //nolint:forcetypeassert // Always a Secret.
+	}
+
+	update := func(secret *corev1.Secret) {
+		_, _, err := backed.Update(ctx, secret.Name, rest.DefaultUpdatedObjectInfo(secret),
+			rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+		if err != nil {
+			t.Fatalf("failed to update secret: %v", err)
+		}
+	}
+
+	// Annotations pointing at the data of other Secrets are not taken over.
+	secret := newSecret("external", corev1.SecretTypeOpaque, nil)
+	secret.Annotations = map[string]string{secrets.BackendKeyAnnotation: "default/other/key"}
+
+	_, err := backed.Create(ctx, secret, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+
+	stored := get(plain, "external")
+	if len(stored.Data) != 0 || !slices.Equal(backend.keys(), []string{stored.Annotations[secrets.BackendKeyAnnotation]}) {
+		t.Fatalf("expected the data to be kept in the backend only, got %v in kine and keys %v",
+			stored.Data, backend.keys())
+	}
+
+	got := get(backed, "external")
+	if string(got.Data["key"]) != "value" {
+		t.Fatalf("expected the data to be read from the backend, got %v", got.Data)
+	}
+
+	// Changing metadata only keeps the data where it is, changing data replaces it.
+	got.Labels = map[string]string{"app": "kommodity"}
+	update(got)
+
+	storedKey := stored.Annotations[secrets.BackendKeyAnnotation]
+	if key := get(plain, "external").Annotations[secrets.BackendKeyAnnotation]; key != storedKey {
+		t.Fatalf("expected the data to stay under %s, got %s", storedKey, key)
+	}
+
+	got = get(backed, "external")
+	got.Data["key"] = []byte("changed")
+	update(got)
+
+	stored = get(plain, "external")
+	if !slices.Equal(backend.keys(), []string{stored.Annotations[secrets.BackendKeyAnnotation]}) {
+		t.Fatalf("expected the replaced data to be removed, got keys %v", backend.keys())
+	}
+
+	if string(get(backed, "external").Data["key"]) != "changed" {
+		t.Fatalf("expected the updated data to be read from the backend")
+	}
+
+	// Secrets that are not selected are kept in kine.
+	_, err = backed.Create(ctx, newSecret("internal", "example.com/custom", nil), rest.ValidateAllObjectFunc,
+		&metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+
+	if string(get(plain, "internal").Data["key"]) != "value" || len(backend.keys()) != 1 {
+		t.Fatalf("expected unselected Secrets to be kept in kine, got keys %v", backend.keys())
+	}
+
+	_, _, err = backed.Delete(ctx, "external", rest.ValidateAllObjectFunc, &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+
+	if len(backend.keys()) != 0 {
+		t.Fatalf("expected the data of deleted Secrets to be removed, got keys %v", backend.keys())
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -52,7 +53,7 @@ func (r *SelfSubjectAccessReviewREST) Create(
 ) (runtime.Object, error) {
 	ssar, success := obj.(*authorizationv1.SelfSubjectAccessReview)
 	if !success {
-		return nil, storage.ErrObjectIsNotASelfSubjectAccessReview
+		return nil, apierrors.NewBadRequest(storage.ExpectedGot(storage.ErrObjectIsNotASelfSubjectAccessReview, obj))
 	}
 
 	// SSAR always evaluates the *requesting* user ("self"), not a provided user.
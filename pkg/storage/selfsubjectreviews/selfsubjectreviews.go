@@ -0,0 +1,79 @@
+// Package selfsubjectreviews implements a REST storage for SelfSubjectReview.
+package selfsubjectreviews
+
+import (
+	"context"
+	"time"
+
+	"github.com/kommodity-io/kommodity/pkg/storage"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// SelfSubjectReviewREST implements the SelfSubjectReview REST endpoint.
+type SelfSubjectReviewREST struct{}
+
+//nolint:misspell // Creater is spelled correctly as per K8s interfaces.
+var _ rest.Creater = &SelfSubjectReviewREST{}
+var _ rest.Storage = &SelfSubjectReviewREST{}
+var _ rest.Scoper = &SelfSubjectReviewREST{}
+var _ rest.SingularNameProvider = &SelfSubjectReviewREST{}
+
+// Destroy implements rest.Storage. No resources to tear down.
+func (r *SelfSubjectReviewREST) Destroy() {}
+
+// New returns an empty SelfSubjectReview object.
+func (r *SelfSubjectReviewREST) New() runtime.Object {
+	return &authenticationv1.SelfSubjectReview{}
+}
+
+// NamespaceScoped implements rest.Scoper. SelfSubjectReview is not namespaced.
+func (r *SelfSubjectReviewREST) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName returns the singular name of the resource.
+func (r *SelfSubjectReviewREST) GetSingularName() string {
+	return "selfsubjectreview"
+}
+
+// Create returns the attributes of the requesting user.
+func (r *SelfSubjectReviewREST) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	_ rest.ValidateObjectFunc,
+	_ *metav1.CreateOptions,
+) (runtime.Object, error) {
+	_, success := obj.(*authenticationv1.SelfSubjectReview)
+	if !success {
+		return nil, apierrors.NewBadRequest(storage.ExpectedGot(storage.ErrObjectIsNotASelfSubjectReview, obj))
+	}
+
+	requester, success := request.UserFrom(ctx)
+	if !success || requester == nil {
+		return nil, apierrors.NewBadRequest("no user on context")
+	}
+
+	extra := make(map[string]authenticationv1.ExtraValue, len(requester.GetExtra()))
+	for key, values := range requester.GetExtra() {
+		extra[key] = values
+	}
+
+	return &authenticationv1.SelfSubjectReview{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(time.Now()),
+		},
+		Status: authenticationv1.SelfSubjectReviewStatus{
+			UserInfo: authenticationv1.UserInfo{
+				Username: requester.GetName(),
+				UID:      requester.GetUID(),
+				Groups:   requester.GetGroups(),
+				Extra:    extra,
+			},
+		},
+	}, nil
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"path"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/validation"
@@ -35,7 +34,7 @@ func (*REST) ShortNames() []string {
 
 // NewServiceAccountREST creates a REST interface for corev1 ServiceAccount resource.
 func NewServiceAccountREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(serviceAccountResource)),
 		func() runtime.Object { return &corev1.ServiceAccount{} },
 		func() runtime.Object { return &corev1.ServiceAccountList{} },
@@ -68,6 +67,8 @@ func NewServiceAccountREST(storageConfig storagebackend.Config, scheme runtime.S
 		UpdateStrategy: serviceAccountStrategy,
 		DeleteStrategy: serviceAccountStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
@@ -103,7 +104,7 @@ func (serviceAccountStrategy) NamespaceScoped() bool {
 }
 
 func (serviceAccountStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	serviceAccountObject, ok := obj.(*corev1.ServiceAccount)
 	if !ok {
@@ -148,7 +149,7 @@ func (serviceAccountStrategy) AllowCreateOnUpdate() bool {
 }
 
 func (serviceAccountStrategy) PrepareForUpdate(ctx context.Context, obj, _ runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	serviceAccountObject, ok := obj.(*corev1.ServiceAccount)
 	if !ok {
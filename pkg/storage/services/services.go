@@ -40,7 +40,7 @@ func (*REST) ShortNames() []string {
 
 // NewServicesREST creates a REST interface for corev1 Namespace resource.
 func NewServicesREST(storageConfig storagebackend.Config, scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(serviceResource)),
 		func() runtime.Object { return &corev1.Service{} },
 		func() runtime.Object { return &corev1.ServiceList{} },
@@ -73,6 +73,8 @@ func NewServicesREST(storageConfig storagebackend.Config, scheme runtime.Scheme)
 		UpdateStrategy: serviceStrategy,
 		DeleteStrategy: serviceStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
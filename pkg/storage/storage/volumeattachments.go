@@ -43,7 +43,7 @@ var Strategy = volumeAttachmentStrategy{legacyscheme.Scheme, names.SimpleNameGen
 
 // NewVolumeAttachmentREST creates a REST interface for storagev1 VolumeAttachment resource.
 func NewVolumeAttachmentREST(storageConfig storagebackend.Config, _ runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(corev1.Resource(volumeAttachmentResource)),
 		func() runtime.Object { return &storagev1.VolumeAttachment{} },
 		func() runtime.Object { return &storagev1.VolumeAttachmentList{} },
@@ -73,6 +73,8 @@ func NewVolumeAttachmentREST(storageConfig storagebackend.Config, _ runtime.Sche
 		UpdateStrategy: volumeAttachmentStrategy,
 		DeleteStrategy: volumeAttachmentStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}
 
 	return &REST{restStore}, nil
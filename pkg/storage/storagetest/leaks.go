@@ -0,0 +1,23 @@
+package storagetest
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// VerifyTestMain runs the tests of a package and fails it if goroutines are still
+// running after all tests returned.
+//
+// Kine does not always stop the watch behind its TTL handling: when kine is stopped
+// while that watch is being set up, the goroutine forwarding its events blocks on a
+// nil channel forever, and with it the TTL loop and its work queue. Those goroutines
+// belong to kine and are ignored.
+func VerifyTestMain(m *testing.M) {
+	goleak.VerifyTestMain(m,
+		goleak.IgnoreTopFunction("github.com/k3s-io/kine/pkg/logstructured.(*LogStructured).Watch.func1"),
+		goleak.IgnoreAnyFunction("github.com/k3s-io/kine/pkg/logstructured.(*LogStructured).ttl"),
+		goleak.IgnoreAnyFunction("github.com/k3s-io/kine/pkg/logstructured.(*LogStructured).handleTTLEvents"),
+		goleak.IgnoreTopFunction("k8s.io/client-go/util/workqueue.(*delayingType[...]).waitingLoop"),
+	)
+}
@@ -0,0 +1,464 @@
+package storagetest
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+const (
+	// eventuallyTimeout bounds how long lists and watches may trail a write. Kine
+	// polls the database for changes and watch caches trail kine.
+	eventuallyTimeout = 15 * time.Second
+	pollInterval      = 100 * time.Millisecond
+	// pageSize is the limit of paginated lists.
+	pageSize = 2
+)
+
+// Suite describes a resource served by one of the storage packages.
+type Suite struct {
+	// NewStorage creates the REST storage of the resource on top of the given kine. The
+	// storage is destroyed when the test ends.
+	NewStorage func(tb testing.TB) rest.Storage
+	// GroupVersion is the group version the resource is served at.
+	GroupVersion schema.GroupVersion
+	// Resource is the plural name of the resource.
+	Resource string
+	// Namespace is the namespace objects are created in, empty for cluster scoped resources.
+	Namespace string
+	// NewObject returns a valid object with the given name.
+	NewObject func(name string) runtime.Object
+	// Mutate changes an object in a way the update strategy accepts.
+	Mutate func(obj runtime.Object)
+	// Selectors are list calls that must return exactly the expected objects.
+	Selectors []SelectorCase
+	// Invalid are objects the create strategy must reject.
+	Invalid []InvalidCase
+}
+
+// SelectorCase lists the stored objects with label and field selectors.
+type SelectorCase struct {
+	Name          string
+	Objects       []runtime.Object
+	LabelSelector string
+	FieldSelector string
+	Expected      []string
+}
+
+// InvalidCase is an object that must fail validation on create.
+type InvalidCase struct {
+	Name   string
+	Object runtime.Object
+}
+
+// Run runs the create, read, update, delete, pagination, table, watch, selector and
+// validation tests of the suite. Every subtest runs against a fresh kine.
+func Run(t *testing.T, suite Suite) {
+	t.Helper()
+
+	t.Run("crud", func(t *testing.T) {
+		t.Parallel()
+		suite.testCRUD(t)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		t.Parallel()
+		suite.testPagination(t)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		suite.testTable(t)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		t.Parallel()
+		suite.testWatch(t)
+	})
+
+	for _, selector := range suite.Selectors {
+		t.Run("selector/"+selector.Name, func(t *testing.T) {
+			t.Parallel()
+			suite.testSelector(t, selector)
+		})
+	}
+
+	for _, invalid := range suite.Invalid {
+		t.Run("invalid/"+invalid.Name, func(t *testing.T) {
+			t.Parallel()
+			suite.testInvalid(t, invalid)
+		})
+	}
+}
+
+func (s Suite) context() context.Context {
+	ctx := genericapirequest.WithNamespace(context.Background(), s.Namespace)
+
+	return genericapirequest.WithRequestInfo(ctx, &genericapirequest.RequestInfo{
+		IsResourceRequest: true,
+		APIGroup:          s.GroupVersion.Group,
+		APIVersion:        s.GroupVersion.Version,
+		Namespace:         s.Namespace,
+		Resource:          s.Resource,
+	})
+}
+
+func (s Suite) storage(tb testing.TB) rest.StandardStorage {
+	tb.Helper()
+
+	created := s.NewStorage(tb)
+
+	// Destroying the storage closes its etcd client and stops its watch cache before
+	// the kine started by NewStorageConfig is stopped.
+	tb.Cleanup(created.Destroy)
+
+	storage, ok := created.(rest.StandardStorage)
+	if !ok {
+		tb.Fatalf("storage of %s does not implement rest.StandardStorage", s.Resource)
+	}
+
+	return storage
+}
+
+func (s Suite) create(tb testing.TB, storage rest.StandardStorage, obj runtime.Object) runtime.Object {
+	tb.Helper()
+
+	created, err := storage.Create(s.context(), obj, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		tb.Fatalf("failed to create %s: %v", s.Resource, err)
+	}
+
+	return created
+}
+
+func (s Suite) testCRUD(t *testing.T) {
+	t.Helper()
+
+	ctx := s.context()
+	storage := s.storage(t)
+
+	created := s.create(t, storage, s.NewObject("crud"))
+
+	createdMeta, err := meta.Accessor(created)
+	if err != nil {
+		t.Fatalf("failed to access created object: %v", err)
+	}
+
+	if createdMeta.GetResourceVersion() == "" || createdMeta.GetUID() == "" {
+		t.Fatalf("expected created %s to have a resourceVersion and uid", s.Resource)
+	}
+
+	_, err = storage.Create(ctx, s.NewObject("crud"), rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("expected AlreadyExists when creating %s twice, got %v", s.Resource, err)
+	}
+
+	got, err := storage.Get(ctx, "crud", &metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get %s: %v", s.Resource, err)
+	}
+
+	s.Mutate(got)
+
+	updated, _, err := storage.Update(ctx, "crud", rest.DefaultUpdatedObjectInfo(got),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update %s: %v", s.Resource, err)
+	}
+
+	updatedMeta, err := meta.Accessor(updated)
+	if err != nil {
+		t.Fatalf("failed to access updated object: %v", err)
+	}
+
+	if updatedMeta.GetResourceVersion() == createdMeta.GetResourceVersion() {
+		t.Fatalf("expected update of %s to change the resourceVersion", s.Resource)
+	}
+
+	_, _, err = storage.Update(ctx, "crud", rest.DefaultUpdatedObjectInfo(got),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if !apierrors.IsConflict(err) {
+		t.Fatalf("expected Conflict when updating a stale %s, got %v", s.Resource, err)
+	}
+
+	_, _, err = storage.Delete(ctx, "crud", rest.ValidateAllObjectFunc, &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("failed to delete %s: %v", s.Resource, err)
+	}
+
+	_, err = storage.Get(ctx, "crud", &metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected NotFound after deleting %s, got %v", s.Resource, err)
+	}
+}
+
+func (s Suite) testPagination(t *testing.T) {
+	t.Helper()
+
+	storage := s.storage(t)
+
+	expected := []string{"page-0", "page-1", "page-2", "page-3", "page-4"}
+	for _, name := range expected {
+		s.create(t, storage, s.NewObject(name))
+	}
+
+	listed, pages := s.listPages(t, storage, &metainternalversion.ListOptions{})
+
+	expectedPages := (len(expected) + pageSize - 1) / pageSize
+	if !slices.Equal(listed, expected) || pages != expectedPages {
+		t.Fatalf("expected %v in %d pages, listed %v in %d pages", expected, expectedPages, listed, pages)
+	}
+}
+
+// listPages follows the continue tokens of a list limited to pageSize and returns the
+// sorted names of all listed objects together with the number of pages.
+func (s Suite) listPages(tb testing.TB, storage rest.StandardStorage,
+	options *metainternalversion.ListOptions) ([]string, int) {
+	tb.Helper()
+
+	var (
+		listed []string
+		pages  int
+	)
+
+	options = options.DeepCopy()
+	options.Limit = pageSize
+
+	for {
+		list, err := storage.List(s.context(), options)
+		if err != nil {
+			tb.Fatalf("failed to list page %d of %s: %v", pages, s.Resource, err)
+		}
+
+		page, err := names(list)
+		if err != nil {
+			tb.Fatalf("failed to read page %d of %s: %v", pages, s.Resource, err)
+		}
+
+		if len(page) > pageSize {
+			tb.Fatalf("expected at most %d %s per page, got %d", pageSize, s.Resource, len(page))
+		}
+
+		listed = append(listed, page...)
+		pages++
+
+		listMeta, err := meta.ListAccessor(list)
+		if err != nil {
+			tb.Fatalf("failed to access list metadata: %v", err)
+		}
+
+		options.Continue = listMeta.GetContinue()
+		if options.Continue == "" {
+			break
+		}
+	}
+
+	slices.Sort(listed)
+
+	return listed, pages
+}
+
+func (s Suite) testTable(t *testing.T) {
+	t.Helper()
+
+	ctx := s.context()
+	storage := s.storage(t)
+
+	created := s.create(t, storage, s.NewObject("printed"))
+
+	table, err := storage.ConvertToTable(ctx, created, &metav1.TableOptions{})
+	if err != nil {
+		t.Fatalf("failed to convert %s to a table: %v", s.Resource, err)
+	}
+
+	// The default table convertor only prints the name and creation timestamp.
+	namedColumn := slices.ContainsFunc(table.ColumnDefinitions, func(column metav1.TableColumnDefinition) bool {
+		return column.Format == "name"
+	})
+	if len(table.ColumnDefinitions) < 3 || !namedColumn {
+		t.Fatalf("expected the columns of the native %s, got %v", s.Resource, table.ColumnDefinitions)
+	}
+
+	if len(table.Rows) != 1 || len(table.Rows[0].Cells) != len(table.ColumnDefinitions) {
+		t.Fatalf("expected a single row with a cell per column, got %v", table.Rows)
+	}
+
+	// Rows must carry the object in the version that was requested, which is what the
+	// API server returns when the client asks to include the object.
+	if got, want := reflect.TypeOf(table.Rows[0].Object.Object), reflect.TypeOf(created); got != want {
+		t.Fatalf("expected row object of type %v, got %v", want, got)
+	}
+
+	list, err := storage.List(ctx, &metainternalversion.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list %s: %v", s.Resource, err)
+	}
+
+	table, err = storage.ConvertToTable(ctx, list, nil)
+	if err != nil {
+		t.Fatalf("failed to convert %s list to a table: %v", s.Resource, err)
+	}
+
+	if len(table.Rows) != 1 {
+		t.Fatalf("expected a row per listed %s, got %d", s.Resource, len(table.Rows))
+	}
+}
+
+func (s Suite) testWatch(t *testing.T) {
+	t.Helper()
+
+	ctx := s.context()
+	storage := s.storage(t)
+
+	var watcher watch.Interface
+
+	// Watch caches reject watches with TooManyRequests until they are initialized. The
+	// watch must outlive the polling, so it is started with the context of the test.
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, eventuallyTimeout, true,
+		func(context.Context) (bool, error) {
+			var err error
+
+			watcher, err = storage.Watch(ctx, &metainternalversion.ListOptions{Watch: true, ResourceVersion: "0"})
+			if apierrors.IsTooManyRequests(err) {
+				return false, nil
+			}
+
+			return err == nil, err
+		})
+	if err != nil {
+		t.Fatalf("failed to watch %s: %v", s.Resource, err)
+	}
+	defer watcher.Stop()
+
+	s.create(t, storage, s.NewObject("watched"))
+
+	timeout := time.After(eventuallyTimeout)
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				t.Fatalf("watch of %s closed before the create was observed", s.Resource)
+			}
+
+			if event.Type != watch.Added {
+				continue
+			}
+
+			objectMeta, err := meta.Accessor(event.Object)
+			if err != nil {
+				t.Fatalf("failed to access watched object: %v", err)
+			}
+
+			if objectMeta.GetName() == "watched" {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for the create of %s to be watched", s.Resource)
+		}
+	}
+}
+
+func (s Suite) testSelector(t *testing.T, selector SelectorCase) {
+	t.Helper()
+
+	ctx := s.context()
+	storage := s.storage(t)
+
+	for _, obj := range selector.Objects {
+		s.create(t, storage, obj)
+	}
+
+	labelSelector, err := labels.Parse(selector.LabelSelector)
+	if err != nil {
+		t.Fatalf("invalid label selector: %v", err)
+	}
+
+	fieldSelector, err := fields.ParseSelector(selector.FieldSelector)
+	if err != nil {
+		t.Fatalf("invalid field selector: %v", err)
+	}
+
+	expected := slices.Sorted(slices.Values(selector.Expected))
+
+	var listed []string
+
+	// Lists may be served from a watch cache that trails the writes above.
+	err = wait.PollUntilContextTimeout(ctx, pollInterval, eventuallyTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			list, err := storage.List(ctx, &metainternalversion.ListOptions{
+				LabelSelector: labelSelector,
+				FieldSelector: fieldSelector,
+			})
+			if err != nil {
+				return false, err
+			}
+
+			listed, err = names(list)
+			if err != nil {
+				return false, err
+			}
+
+			return slices.Equal(listed, expected), nil
+		})
+	if err != nil {
+		t.Fatalf("expected %s %v, listed %v: %v", s.Resource, expected, listed, err)
+	}
+
+	listed, _ = s.listPages(t, storage, &metainternalversion.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	})
+	if !slices.Equal(listed, expected) {
+		t.Fatalf("expected paginated %s %v, listed %v", s.Resource, expected, listed)
+	}
+}
+
+func (s Suite) testInvalid(t *testing.T, invalid InvalidCase) {
+	t.Helper()
+
+	storage := s.storage(t)
+
+	_, err := storage.Create(s.context(), invalid.Object, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected Invalid when creating %s, got %v", s.Resource, err)
+	}
+}
+
+// names returns the sorted names of the objects in a list.
+func names(list runtime.Object) ([]string, error) {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Only used to fail the test.
+	}
+
+	listed := make([]string, 0, len(items))
+
+	for _, item := range items {
+		objectMeta, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // Only used to fail the test.
+		}
+
+		listed = append(listed, objectMeta.GetName())
+	}
+
+	slices.Sort(listed)
+
+	return listed, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	admissionregistrationinstall "k8s.io/kubernetes/pkg/apis/admissionregistration/install"
+	appsinstall "k8s.io/kubernetes/pkg/apis/apps/install"
+	coreinstall "k8s.io/kubernetes/pkg/apis/core/install"
+	rbacinstall "k8s.io/kubernetes/pkg/apis/rbac/install"
+	storageinstall "k8s.io/kubernetes/pkg/apis/storage/install"
+	"k8s.io/kubernetes/pkg/printers"
+	printersinternal "k8s.io/kubernetes/pkg/printers/internalversion"
+	printerstorage "k8s.io/kubernetes/pkg/printers/storage"
+)
+
+// TableConvertor renders the versioned objects kept by the storage packages with the
+// columns of the native Kubernetes API server, so that kubectl prints them the same.
+type TableConvertor struct {
+	scheme  *runtime.Scheme
+	printer printerstorage.TableConvertor
+}
+
+var _ rest.TableConvertor = &TableConvertor{}
+
+// NewTableConvertor creates a TableConvertor for the built-in resources served by
+// Kommodity.
+func NewTableConvertor() *TableConvertor {
+	scheme := runtime.NewScheme()
+
+	// The native printers are written against the internal types, which the scheme of
+	// the API server does not know about.
+	coreinstall.Install(scheme)
+	rbacinstall.Install(scheme)
+	admissionregistrationinstall.Install(scheme)
+	storageinstall.Install(scheme)
+	appsinstall.Install(scheme)
+
+	return &TableConvertor{
+		scheme: scheme,
+		printer: printerstorage.TableConvertor{
+			TableGenerator: printers.NewTableGenerator().With(printersinternal.AddHandlers),
+		},
+	}
+}
+
+// ConvertToTable converts an object or list to its internal version, prints it and
+// converts the objects attached to the rows back to the version that was requested.
+func (c *TableConvertor) ConvertToTable(ctx context.Context, obj runtime.Object,
+	tableOptions runtime.Object) (*metav1.Table, error) {
+	kinds, _, err := c.scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine kind of %T: %w", obj, err)
+	}
+
+	internal, err := c.scheme.ConvertToVersion(obj, runtime.InternalGroupVersioner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T to its internal version: %w", obj, err)
+	}
+
+	table, err := c.printer.ConvertToTable(ctx, internal, tableOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to print %T: %w", obj, err)
+	}
+
+	for i := range table.Rows {
+		row := &table.Rows[i]
+		if row.Object.Object == nil {
+			continue
+		}
+
+		row.Object.Object, err = c.scheme.ConvertToVersion(row.Object.Object, kinds[0].GroupVersion())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert row of %T back to %s: %w", obj, kinds[0].GroupVersion(), err)
+		}
+	}
+
+	return table, nil
+}
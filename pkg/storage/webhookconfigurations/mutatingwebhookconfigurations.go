@@ -9,7 +9,6 @@ import (
 	"path"
 	"reflect"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	"go.uber.org/zap"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -31,7 +30,7 @@ const mutatingWebhookConfigurationResource = "mutatingwebhookconfigurations"
 // NewMutatingWebhookConfigurationREST creates a REST interface for mutating webhook configurations.
 func NewMutatingWebhookConfigurationREST(storageConfig storagebackend.Config,
 	scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(admissionregistrationv1.Resource(mutatingWebhookConfigurationResource)),
 		func() runtime.Object { return &admissionregistrationv1.MutatingWebhookConfiguration{} },
 		func() runtime.Object { return &admissionregistrationv1.MutatingWebhookConfigurationList{} },
@@ -64,6 +63,8 @@ func NewMutatingWebhookConfigurationREST(storageConfig storagebackend.Config,
 		UpdateStrategy: mutatingWebhookConfigurationStrategy,
 		DeleteStrategy: mutatingWebhookConfigurationStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}, nil
 }
 
@@ -116,7 +117,7 @@ func (mutatingWebhookConfigurationStrategy) NamespaceScoped() bool {
 
 // PrepareForCreate sets the generation number to 1 and clears the status.
 func (mutatingWebhookConfigurationStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	mwc, success := obj.(*admissionregistrationv1.MutatingWebhookConfiguration)
 	if !success {
@@ -137,14 +138,14 @@ func (mutatingWebhookConfigurationStrategy) WarningsOnCreate(_ context.Context,
 func (mutatingWebhookConfigurationStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
 	newMWC, success := obj.(*admissionregistrationv1.MutatingWebhookConfiguration)
 	if !success {
-		logging.FromContext(ctx).Error(storage.ErrObjectIsNotAnMutatingWebhookConfiguration.Error(), zap.Any("object", obj))
+		storage.LoggerFrom(ctx).Error(storage.ErrObjectIsNotAnMutatingWebhookConfiguration.Error(), zap.Any("object", obj))
 
 		return
 	}
 
 	oldMWC, success := old.(*admissionregistrationv1.MutatingWebhookConfiguration)
 	if !success {
-		logging.FromContext(ctx).Error(storage.ErrObjectIsNotAnMutatingWebhookConfiguration.Error(), zap.Any("object", old))
+		storage.LoggerFrom(ctx).Error(storage.ErrObjectIsNotAnMutatingWebhookConfiguration.Error(), zap.Any("object", old))
 
 		return
 	}
@@ -7,7 +7,6 @@ import (
 	"path"
 	"reflect"
 
-	"github.com/kommodity-io/kommodity/pkg/logging"
 	"github.com/kommodity-io/kommodity/pkg/storage"
 	"go.uber.org/zap"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -29,7 +28,7 @@ const validatingWebhookConfigurationResource = "validatingwebhookconfigurations"
 // NewValidatingWebhookConfigurationREST creates a REST interface for validating webhook configurations.
 func NewValidatingWebhookConfigurationREST(storageConfig storagebackend.Config,
 	scheme runtime.Scheme) (rest.Storage, error) {
-	store, _, err := factory.Create(
+	store, destroy, err := factory.Create(
 		*storageConfig.ForResource(admissionregistrationv1.Resource(validatingWebhookConfigurationResource)),
 		func() runtime.Object { return &admissionregistrationv1.ValidatingWebhookConfiguration{} },
 		func() runtime.Object { return &admissionregistrationv1.ValidatingWebhookConfigurationList{} },
@@ -62,6 +61,8 @@ func NewValidatingWebhookConfigurationREST(storageConfig storagebackend.Config,
 		UpdateStrategy: validatingWebhookConfigurationStrategy,
 		DeleteStrategy: validatingWebhookConfigurationStrategy,
 		Storage:        dryRunnableStorage,
+		TableConvertor: storage.NewTableConvertor(),
+		DestroyFunc:    destroy,
 	}, nil
 }
 
@@ -111,7 +112,7 @@ func (validatingWebhookConfigurationStrategy) NamespaceScoped() bool {
 
 // PrepareForCreate sets the generation number to 1 and clears the status.
 func (validatingWebhookConfigurationStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
-	logger := logging.FromContext(ctx)
+	logger := storage.LoggerFrom(ctx)
 
 	vwc, success := obj.(*admissionregistrationv1.ValidatingWebhookConfiguration)
 	if !success {
@@ -132,14 +133,14 @@ func (validatingWebhookConfigurationStrategy) WarningsOnCreate(_ context.Context
 func (validatingWebhookConfigurationStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
 	newvwc, success := obj.(*admissionregistrationv1.ValidatingWebhookConfiguration)
 	if !success {
-		logging.FromContext(ctx).Error(storage.ErrObjectIsNotAValidatingWebhookConfiguration.Error(), zap.Any("object", obj))
+		storage.LoggerFrom(ctx).Error(storage.ErrObjectIsNotAValidatingWebhookConfiguration.Error(), zap.Any("object", obj))
 
 		return
 	}
 
 	oldvwc, success := old.(*admissionregistrationv1.ValidatingWebhookConfiguration)
 	if !success {
-		logging.FromContext(ctx).Error(storage.ErrObjectIsNotAValidatingWebhookConfiguration.Error(), zap.Any("object", old))
+		storage.LoggerFrom(ctx).Error(storage.ErrObjectIsNotAValidatingWebhookConfiguration.Error(), zap.Any("object", old))
 
 		return
 	}
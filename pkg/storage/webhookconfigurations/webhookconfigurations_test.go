@@ -0,0 +1,128 @@
+package webhookconfigurations_test
+
+import (
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/storage/storagetest"
+	"github.com/kommodity-io/kommodity/pkg/storage/webhookconfigurations"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newMutatingWebhookConfiguration(name string,
+	labels map[string]string) *admissionregistrationv1.MutatingWebhookConfiguration {
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+	}
+}
+
+func newValidatingWebhookConfiguration(name string,
+	labels map[string]string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+	}
+}
+
+func TestMutatingWebhookConfigurationsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, admissionregistrationv1.SchemeGroupVersion)
+
+			storage, err := webhookconfigurations.NewMutatingWebhookConfigurationREST(storageConfig, *scheme)
+			if err != nil {
+				tb.Fatalf("failed to create mutatingwebhookconfigurations storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: admissionregistrationv1.SchemeGroupVersion,
+		Resource:     "mutatingwebhookconfigurations",
+		NewObject: func(name string) runtime.Object {
+			return newMutatingWebhookConfiguration(name, nil)
+		},
+		Mutate: func(obj runtime.Object) {
+			//nolint:forcetypeassert // Always a MutatingWebhookConfiguration.
+			obj.(*admissionregistrationv1.MutatingWebhookConfiguration).Labels = map[string]string{"changed": "true"}
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "name",
+				Objects: []runtime.Object{
+					newMutatingWebhookConfiguration("capi", nil),
+					newMutatingWebhookConfiguration("capk", nil),
+				},
+				FieldSelector: "metadata.name=capk",
+				Expected:      []string{"capk"},
+			},
+			{
+				Name: "label",
+				Objects: []runtime.Object{
+					newMutatingWebhookConfiguration("capi", map[string]string{"provider": "core"}),
+					newMutatingWebhookConfiguration("capk", map[string]string{"provider": "kubevirt"}),
+				},
+				LabelSelector: "provider=core",
+				Expected:      []string{"capi"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{Name: "name", Object: newMutatingWebhookConfiguration("Not_A_Name", nil)},
+		},
+	})
+}
+
+func TestValidatingWebhookConfigurationsStorage(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, storagetest.Suite{
+		NewStorage: func(tb testing.TB) rest.Storage {
+			tb.Helper()
+
+			storageConfig, scheme := storagetest.NewStorageConfig(tb, admissionregistrationv1.SchemeGroupVersion)
+
+			storage, err := webhookconfigurations.NewValidatingWebhookConfigurationREST(storageConfig, *scheme)
+			if err != nil {
+				tb.Fatalf("failed to create validatingwebhookconfigurations storage: %v", err)
+			}
+
+			return storage
+		},
+		GroupVersion: admissionregistrationv1.SchemeGroupVersion,
+		Resource:     "validatingwebhookconfigurations",
+		NewObject: func(name string) runtime.Object {
+			return newValidatingWebhookConfiguration(name, nil)
+		},
+		Mutate: func(obj runtime.Object) {
+			//nolint:forcetypeassert // Always a ValidatingWebhookConfiguration.
+			obj.(*admissionregistrationv1.ValidatingWebhookConfiguration).Labels = map[string]string{"changed": "true"}
+		},
+		Selectors: []storagetest.SelectorCase{
+			{
+				Name: "name",
+				Objects: []runtime.Object{
+					newValidatingWebhookConfiguration("capi", nil),
+					newValidatingWebhookConfiguration("capk", nil),
+				},
+				FieldSelector: "metadata.name=capi",
+				Expected:      []string{"capi"},
+			},
+			{
+				Name: "label",
+				Objects: []runtime.Object{
+					newValidatingWebhookConfiguration("capi", map[string]string{"provider": "core"}),
+					newValidatingWebhookConfiguration("capk", map[string]string{"provider": "kubevirt"}),
+				},
+				LabelSelector: "provider!=core",
+				Expected:      []string{"capk"},
+			},
+		},
+		Invalid: []storagetest.InvalidCase{
+			{Name: "name", Object: newValidatingWebhookConfiguration("Not_A_Name", nil)},
+		},
+	})
+}
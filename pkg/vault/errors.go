@@ -0,0 +1,10 @@
+package vault
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when Vault reports that the requested path does not exist.
+	ErrNotFound = errors.New("vault path not found")
+	// ErrRequestFailed is returned when Vault cannot be reached or rejects a request.
+	ErrRequestFailed = errors.New("vault request failed")
+)
@@ -0,0 +1,92 @@
+// Package vault calls the HTTP API of HashiCorp Vault, and of compatible servers such as
+// OpenBao, with a token.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	tokenHeader = "X-Vault-Token"
+
+	// maxBodyBytes bounds the size of the responses read from Vault.
+	maxBodyBytes = 1 << 20
+)
+
+// Client calls the Vault API at an address with a token.
+type Client struct {
+	client  *http.Client
+	address string
+	token   string
+}
+
+// NewClient returns a client for the Vault API at the address.
+func NewClient(client *http.Client, address, token string) *Client {
+	return &Client{
+		client:  client,
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+	}
+}
+
+// Do calls <address>/v1/<path> with the body encoded as JSON, if given, and decodes the
+// JSON response into out, if given. Responses with status 404 are returned as
+// ErrNotFound, other non-2xx responses as ErrRequestFailed errors including the body.
+func (c *Client) Do(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode vault request: %w", err)
+		}
+
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.address+"/v1/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create vault request: %w", err)
+	}
+
+	req.Header.Set(tokenHeader, c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", req.URL.Host, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned %d: %s", ErrRequestFailed, req.URL.Host, resp.StatusCode,
+			strings.TrimSpace(string(responseBody)))
+	}
+
+	if out == nil || len(responseBody) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(responseBody, out)
+	if err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Host, err)
+	}
+
+	return nil
+}
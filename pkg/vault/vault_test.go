@@ -0,0 +1,51 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kommodity-io/kommodity/pkg/vault"
+)
+
+func TestClientDo(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("X-Vault-Token") != "root":
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		case r.URL.Path == "/v1/secret/data/present":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"value": "ok"}})
+		default:
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	var response struct {
+		Data struct {
+			Value string `json:"value"`
+		} `json:"data"`
+	}
+
+	err := vault.NewClient(server.Client(), server.URL+"/", "root").
+		Do(t.Context(), http.MethodGet, "secret/data/present", nil, &response)
+	if err != nil || response.Data.Value != "ok" {
+		t.Fatalf("expected the response to be decoded, got %+v: %v", response, err)
+	}
+
+	err = vault.NewClient(server.Client(), server.URL, "root").
+		Do(t.Context(), http.MethodGet, "secret/data/missing", nil, nil)
+	if !errors.Is(err, vault.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	err = vault.NewClient(server.Client(), server.URL, "wrong").
+		Do(t.Context(), http.MethodGet, "secret/data/present", nil, nil)
+	if !errors.Is(err, vault.ErrRequestFailed) {
+		t.Fatalf("expected ErrRequestFailed, got %v", err)
+	}
+}
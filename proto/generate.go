@@ -0,0 +1,4 @@
+//go:generate go run github.com/bufbuild/buf/cmd/buf@latest generate
+
+// Package proto holds the protobuf definitions of the gRPC APIs Kommodity serves.
+package proto
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+
+# Vendors the Swagger UI assets embedded by pkg/apidocs, at the release named in the
+# VERSION file next to them. Bump VERSION and run this script to upgrade.
+
+set -euo pipefail
+
+asset_dir="pkg/apidocs/swagger-ui"
+version=$(cat "$asset_dir/VERSION")
+
+tmp_dir=$(mktemp -d)
+trap 'rm -rf "$tmp_dir"' EXIT
+
+url="https://registry.npmjs.org/swagger-ui-dist/-/swagger-ui-dist-${version}.tgz"
+
+echo "Fetching Swagger UI from $url"
+
+curl -sSfL "$url" | tar -xz -C "$tmp_dir"
+
+for file in swagger-ui.css swagger-ui-bundle.js swagger-ui-standalone-preset.js LICENSE; do
+  cp "$tmp_dir/package/$file" "$asset_dir/$file"
+done